@@ -12,25 +12,27 @@ import (
 	"log/slog"
 
 	"github.com/eren_dev/go_server/internal/app"
+	"github.com/eren_dev/go_server/internal/app/indexes"
 	"github.com/eren_dev/go_server/internal/app/lifecycle"
 	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/appointments"
 	"github.com/eren_dev/go_server/internal/modules/health"
-	"github.com/eren_dev/go_server/internal/modules/inventory"
-	"github.com/eren_dev/go_server/internal/modules/laboratory"
 	"github.com/eren_dev/go_server/internal/modules/medical_records"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
-	"github.com/eren_dev/go_server/internal/modules/vaccinations"
 	"github.com/eren_dev/go_server/internal/modules/owners"
-	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/platform/cache"
+	"github.com/eren_dev/go_server/internal/platform/email/smtp"
 	"github.com/eren_dev/go_server/internal/platform/logger"
 	"github.com/eren_dev/go_server/internal/platform/metrics"
+	platformNotifications "github.com/eren_dev/go_server/internal/platform/notifications"
 	"github.com/eren_dev/go_server/internal/platform/notifications/fcm"
 	"github.com/eren_dev/go_server/internal/platform/payment"
 	"github.com/eren_dev/go_server/internal/platform/payment/stripe"
 	"github.com/eren_dev/go_server/internal/platform/payment/wompi"
+	"github.com/eren_dev/go_server/internal/platform/sms/gateway"
 	"github.com/eren_dev/go_server/internal/scheduler"
 	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
 // @title           Vetsify API
@@ -54,6 +56,7 @@ import (
 // @description     ## Paginación
 // @description     Las rutas que retornan listas soportan `skip` y `limit` como query params.
 // @description     Respuesta incluye `pagination: { skip, limit, total, total_pages }`.
+// @description     `limit` tiene un máximo (PAGINATION_MAX_LIMIT, 100 por defecto); si se pide más se recorta y la respuesta trae `capped: true`.
 
 // @contact.name   Vetsify Support
 // @contact.email  support@vetsify.com
@@ -82,6 +85,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	pagination.Configure(cfg.PaginationDefaultLimit, cfg.PaginationMaxLimit)
+	cache.ConfigureReference(time.Duration(cfg.ReferenceCacheTTLSeconds) * time.Second)
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
@@ -95,41 +101,9 @@ func main() {
 		logger.Default().Info(context.Background(), "database_connected", "database", cfg.MongoDatabase)
 		health.SetDatabase(db)
 
-		// Ensure indexes for all collections
-		if err := tenant.EnsureIndexes(context.Background(), db); err != nil {
-			logger.Default().Error(context.Background(), "tenant_indexes_creation_failed", "error", err)
-		} else {
-			logger.Default().Info(context.Background(), "tenant_indexes_created")
-		}
-
-		if err := appointments.EnsureIndexes(context.Background(), db); err != nil {
-			logger.Default().Error(context.Background(), "appointments_indexes_creation_failed", "error", err)
-		} else {
-			logger.Default().Info(context.Background(), "appointments_indexes_created")
-		}
-
-		if err := medical_records.EnsureIndexes(context.Background(), db); err != nil {
-			logger.Default().Error(context.Background(), "medical_records_indexes_creation_failed", "error", err)
-		} else {
-			logger.Default().Info(context.Background(), "medical_records_indexes_created")
-		}
-
-		if err := inventory.EnsureIndexes(context.Background(), db); err != nil {
-			logger.Default().Error(context.Background(), "inventory_indexes_creation_failed", "error", err)
-		} else {
-			logger.Default().Info(context.Background(), "inventory_indexes_created")
-		}
-
-		if err := vaccinations.EnsureIndexes(context.Background(), db); err != nil {
-			logger.Default().Error(context.Background(), "vaccinations_indexes_creation_failed", "error", err)
-		} else {
-			logger.Default().Info(context.Background(), "vaccinations_indexes_created")
-		}
-
-		if err := laboratory.EnsureIndexes(context.Background(), db); err != nil {
-			logger.Default().Error(context.Background(), "laboratory_indexes_creation_failed", "error", err)
-		} else {
-			logger.Default().Info(context.Background(), "laboratory_indexes_created")
+		// Ensure indexes for all registered collections
+		if _, err := indexes.EnsureAll(context.Background(), db); err != nil {
+			logger.Default().Error(context.Background(), "indexes_creation_failed", "error", err)
 		}
 	} else {
 		logger.Default().Info(context.Background(), "database_disabled", "reason", "MONGO_DATABASE not configured")
@@ -173,15 +147,23 @@ func main() {
 		}
 	}
 
-	// Initialize FCM push provider
-	pushProvider, err := fcm.NewProvider(ctx, cfg)
+	// Initialize Prometheus metrics
+	metricsService := metrics.NewMetrics()
+	if db != nil {
+		db.WithSlowQueryMetrics(metricsService)
+	}
+
+	// Initialize FCM push provider behind a bounded send pool
+	fcmProvider, err := fcm.NewProvider(ctx, cfg)
 	if err != nil {
 		logger.Default().Error(context.Background(), "fcm_init_failed", "error", err)
 		os.Exit(1)
 	}
-
-	// Initialize Prometheus metrics
-	metricsService := metrics.NewMetrics()
+	pushProvider := platformNotifications.NewPool(fcmProvider, platformNotifications.PoolConfig{
+		MaxConcurrent: cfg.FCMMaxConcurrentSends,
+		QueueSize:     cfg.FCMSendQueueSize,
+		SendTimeout:   cfg.FCMSendTimeout,
+	}, metricsService)
 
 	// Initialize extended health service
 	healthSvc := health.NewHealthService(cfg.Env, "1.0.0")
@@ -189,6 +171,7 @@ func main() {
 		healthSvc.RegisterChecker(health.NewMongoHealthChecker(func(ctx context.Context) error {
 			return db.Health(ctx)
 		}))
+		healthSvc.RegisterReadinessChecker(indexes.NewHealthChecker(db))
 	}
 	health.SetHealthService(healthSvc)
 
@@ -201,8 +184,13 @@ func main() {
 	}
 
 	ownerRepo := owners.NewRepository(db)
-	notifSvc := notifications.NewService(notifications.NewRepository(db), notifications.NewStaffRepository(db), ownerRepo, pushProvider)
-	apptScheduler := scheduler.New(db, notifSvc, slog.Default(), cfg)
+	emailSender := smtp.NewSender(cfg)
+	notifSvc := notifications.NewService(notifications.NewRepository(db), notifications.NewStaffRepository(db), notifications.NewTemplateRepository(db), ownerRepo, pushProvider).
+		WithContactChannels(emailSender, gateway.NewSender(cfg)).
+		WithDeadLetters(notifications.NewDeadLetterRepository(db), metricsService).
+		WithOutbox(notifications.NewOutboxRepository(db)).
+		WithOutboxSources(appointments.Collection, medical_records.RecordsCollection)
+	apptScheduler := scheduler.New(db, notifSvc, emailSender, slog.Default(), cfg)
 	apptScheduler.Start(ctx, workers)
 
 	logger.Default().Info(context.Background(), "server_running", "port", cfg.Port, "env", cfg.Env)
@@ -219,6 +207,12 @@ func main() {
 
 	apptScheduler.Stop()
 
+	pushCtx, cancelPush := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := pushProvider.Close(pushCtx); err != nil {
+		logger.Default().Warn(context.Background(), "push_pool_drain_timeout", "error", err)
+	}
+	cancelPush()
+
 	if db != nil {
 		if err := db.Close(context.Background()); err != nil {
 			logger.Default().Error(context.Background(), "database_close_error", "error", err)
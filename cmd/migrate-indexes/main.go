@@ -8,9 +8,8 @@ import (
 
 	"github.com/joho/godotenv"
 
+	"github.com/eren_dev/go_server/internal/app/indexes"
 	"github.com/eren_dev/go_server/internal/config"
-	"github.com/eren_dev/go_server/internal/modules/appointments"
-	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/platform/logger"
 	"github.com/eren_dev/go_server/internal/shared/database"
 )
@@ -24,7 +23,7 @@ func main() {
 	log := logger.NewSlogLogger(cfg.Env)
 	logger.SetDefault(log)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
 	db, err := database.NewProvider(cfg)
@@ -36,25 +35,17 @@ func main() {
 
 	logger.Default().Info(ctx, "database_connected", "database", cfg.MongoDatabase)
 
-	success := true
+	results, err := indexes.EnsureAll(ctx, db)
+	success := err == nil
 
-	// Create tenant indexes
-	logger.Default().Info(ctx, "creating_tenant_indexes")
-	if err := tenant.EnsureIndexes(ctx, db); err != nil {
-		logger.Default().Error(ctx, "tenant_indexes_creation_failed", "error", err)
-		success = false
-	} else {
-		logger.Default().Info(ctx, "tenant_indexes_created")
-	}
-
-	// Create appointments indexes
-	logger.Default().Info(ctx, "creating_appointments_indexes")
-	if err := appointments.EnsureIndexes(ctx, db); err != nil {
-		logger.Default().Error(ctx, "appointments_indexes_creation_failed", "error", err)
-		success = false
-	} else {
-		logger.Default().Info(ctx, "appointments_indexes_created")
+	created := 0
+	for _, r := range results {
+		created += len(r.Created)
+		for _, name := range r.Created {
+			fmt.Printf("  + %s.%s\n", r.Collection, name)
+		}
 	}
+	fmt.Printf("%d indexes created across %d collections\n", created, len(results))
 
 	fmt.Println()
 	if success {
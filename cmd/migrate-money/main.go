@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
+	"github.com/eren_dev/go_server/internal/platform/logger"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Migration script converting stored prices, invoices and deposits to
+// integer minor units
+// Usage: go run cmd/migrate-money/main.go
+func main() {
+	_ = godotenv.Load(".env")
+
+	cfg := config.Load()
+	log := logger.NewSlogLogger(cfg.Env)
+	logger.SetDefault(log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	db, err := database.NewProvider(cfg)
+	if err != nil {
+		logger.Default().Error(ctx, "database_connection_failed", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close(ctx)
+
+	logger.Default().Info(ctx, "database_connected", "database", cfg.MongoDatabase)
+
+	result, err := inventory.MigrateToMinorUnits(ctx, db)
+	if result != nil {
+		fmt.Printf("%d tenants: %d product prices and %d expiry write-offs converted\n", result.Tenants, result.Products, result.WriteOffs)
+	}
+	if err == nil {
+		var invoiceResult *invoices.MinorUnitsMigration
+		invoiceResult, err = invoices.MigrateToMinorUnits(ctx, db)
+		if invoiceResult != nil {
+			fmt.Printf("%d invoice amounts and %d payment amounts converted\n", invoiceResult.Invoices, invoiceResult.Payments)
+		}
+	}
+	if err == nil {
+		var depositResult *appointments.DepositMigration
+		depositResult, err = appointments.MigrateDepositsToMinorUnits(ctx, db)
+		if depositResult != nil {
+			fmt.Printf("deposits converted: %d clinic settings, %d appointment types, %d appointments\n", depositResult.Tenants, depositResult.Types, depositResult.Appointments)
+		}
+	}
+
+	fmt.Println()
+	if err != nil {
+		logger.Default().Error(ctx, "migration_completed_with_errors", "error", err)
+		fmt.Println("❌ Money migration failed. It can be re-run safely once the error is fixed.")
+		os.Exit(1)
+	}
+	logger.Default().Info(ctx, "migration_completed_successfully")
+	fmt.Println("✅ Money migration completed successfully!")
+}
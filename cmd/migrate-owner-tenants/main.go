@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/platform/logger"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Migration script linking owners to the tenants of their patients
+// Usage: go run cmd/migrate-owner-tenants/main.go
+func main() {
+	_ = godotenv.Load(".env")
+
+	cfg := config.Load()
+	log := logger.NewSlogLogger(cfg.Env)
+	logger.SetDefault(log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	db, err := database.NewProvider(cfg)
+	if err != nil {
+		logger.Default().Error(ctx, "database_connection_failed", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close(ctx)
+
+	logger.Default().Info(ctx, "database_connected", "database", cfg.MongoDatabase)
+
+	updated, err := owners.BackfillTenantIDs(ctx, db)
+	fmt.Printf("%d owners linked to their patients' tenants\n", updated)
+
+	fmt.Println()
+	if err != nil {
+		logger.Default().Error(ctx, "migration_completed_with_errors", "error", err)
+		fmt.Println("❌ Owner tenant migration failed. It can be re-run safely once the error is fixed.")
+		os.Exit(1)
+	}
+	logger.Default().Info(ctx, "migration_completed_successfully")
+	fmt.Println("✅ Owner tenant migration completed successfully!")
+}
@@ -26,12 +26,65 @@ var vetResources = []resourceSeed{
 	{"owners", "Propietarios y contactos de las mascotas"},
 	{"medical-records", "Historias clínicas y expedientes médicos"},
 	{"vaccines", "Registro y control de vacunación"},
+	{"bulk", "Registro masivo de vacunas en jornadas"},
 	{"prescriptions", "Recetas médicas y tratamientos"},
 	{"inventory", "Inventario de medicamentos e insumos"},
 	{"billing", "Facturación, pagos y cobros"},
 	{"reports", "Reportes y estadísticas del negocio"},
 	{"users", "Usuarios del sistema"},
 	{"roles", "Roles y permisos de acceso"},
+	{"config", "Configuración efectiva del servidor (solo administradores)"},
+	{"broadcast", "Avisos masivos a propietarios"},
+	{"templates", "Plantillas de notificaciones"},
+	{"dead-letters", "Notificaciones no entregadas"},
+	{"webhooks", "Webhooks de pago recibidos"},
+	{"reprocess", "Reproceso de webhooks de pago fallidos"},
+	{"reverse", "Reversión de movimientos de inventario"},
+	{"approve", "Aprobación de ajustes de inventario grandes"},
+	{"reject", "Rechazo de ajustes de inventario pendientes"},
+	{"reorder-suggestions", "Sugerencias de reabastecimiento por proveedor"},
+	{"expiry-writeoffs", "Bajas automáticas de productos vencidos"},
+	{"invoices", "Facturas a propietarios"},
+	{"issue", "Emisión de facturas en borrador"},
+	{"payment-link", "Links de pago en línea para facturas"},
+	{"record-payment", "Registro de pagos y abonos de facturas"},
+	{"loyalty", "Programa de puntos de propietarios"},
+	{"redeem", "Redención de puntos de fidelización"},
+	{"holidays", "Calendario de festivos y días de cierre"},
+	{"booking-window", "Ventana de reserva de citas para propietarios"},
+	{"reassign", "Reasignación de citas entre veterinarios"},
+	{"qr", "Códigos QR de pacientes para placas"},
+	{"resolve-qr", "Lectura de códigos QR de pacientes en recepción"},
+	{"no-shows", "Reporte de inasistencias por propietario"},
+	{"vaccination-coverage", "Reporte de cobertura de vacunación de pacientes"},
+	{"preview-series", "Vista previa de disponibilidad de citas recurrentes"},
+	{"status-subscription", "Suscripción del personal a cambios de estado de citas"},
+	{"offboard", "Baja de veterinarios y reasignación de su agenda"},
+	{"appointment-types", "Tipos de cita configurables por clínica"},
+	{"types", "Tipos de cita que los propietarios pueden reservar"},
+	{"shifts", "Cuadro de turnos del personal"},
+	{"publish", "Publicación del cuadro de turnos"},
+	{"referral-letter", "Cartas de remisión a especialistas externos"},
+	{"mark-deceased", "Registro del fallecimiento de pacientes"},
+	{"export.csv", "Exportación de citas a CSV para contabilidad"},
+	{"export", "Exportación de citas y productos en CSV o JSON lines"},
+	{"reschedule-request", "Aprobación de reprogramaciones pedidas por propietarios"},
+	{"medical-record-templates", "Plantillas de historia clínica por tipo de consulta"},
+	{"tags", "Autocompletado de etiquetas de pacientes y productos"},
+	{"staff", "Directorio del personal con roles, especialidades y disponibilidad"},
+	{"rooms", "Salas y equipos reservables por cita"},
+	{"sale", "Ventas de mostrador sin factura"},
+	{"sales", "Historial de ventas de mostrador"},
+	{"receipt.pdf", "Recibos imprimibles de ventas de mostrador"},
+	{"merge", "Fusión de propietarios duplicados"},
+	{"intakes", "Formularios de ingreso de clientes nuevos"},
+	{"appointment-workflow", "Estados y transiciones de citas por clínica"},
+	{"weight", "Registro rápido e historial de peso de pacientes"},
+	{"vaccine-protocols", "Protocolos de vacunación de varias dosis"},
+	{"schedule", "Programación de las dosis de un protocolo de vacunación"},
+	{"consent-templates", "Textos de consentimiento informado por procedimiento"},
+	{"consents", "Consentimientos firmados por propietarios"},
+	{"consent", "Presentación y firma del consentimiento de una cita"},
 }
 
 type permEntry struct {
@@ -53,43 +106,59 @@ func allResourcePermissions(resourceNames []string) []permEntry {
 
 var veterinarianPermissions = []permEntry{
 	{"dashboard", "get"},
-	{"appointments", "get"}, {"appointments", "post"}, {"appointments", "patch"},
-	{"patients", "get"}, {"patients", "post"}, {"patients", "put"}, {"patients", "patch"},
+	{"appointments", "get"}, {"appointments", "post"}, {"appointments", "patch"}, {"status-subscription", "get"}, {"status-subscription", "put"}, {"status-subscription", "delete"}, {"reassign", "patch"}, {"reschedule-request", "patch"}, {"preview-series", "post"}, {"appointment-types", "get"}, {"appointment-workflow", "get"}, {"booking-window", "get"}, {"types", "get"},
+	{"patients", "get"}, {"patients", "post"}, {"patients", "put"}, {"patients", "patch"}, {"qr", "get"}, {"resolve-qr", "get"}, {"mark-deceased", "post"}, {"weight", "get"}, {"weight", "post"}, {"tags", "get"},
 	{"species", "get"}, {"species", "post"},
-	{"owners", "get"}, {"owners", "post"}, {"owners", "patch"},
-	{"medical-records", "get"}, {"medical-records", "post"}, {"medical-records", "put"}, {"medical-records", "patch"}, {"medical-records", "delete"},
-	{"vaccines", "get"}, {"vaccines", "post"}, {"vaccines", "patch"}, {"vaccines", "delete"},
+	{"owners", "get"}, {"owners", "post"}, {"owners", "patch"}, {"intakes", "get"},
+	{"medical-records", "get"}, {"medical-records", "post"}, {"medical-records", "put"}, {"medical-records", "patch"}, {"medical-records", "delete"}, {"referral-letter", "get"}, {"medical-record-templates", "get"},
+	{"vaccines", "get"}, {"vaccines", "post"}, {"vaccines", "patch"}, {"vaccines", "delete"}, {"bulk", "post"},
+	{"vaccine-protocols", "get"}, {"vaccine-protocols", "post"}, {"vaccine-protocols", "put"}, {"vaccine-protocols", "delete"}, {"schedule", "post"},
 	{"prescriptions", "get"}, {"prescriptions", "post"}, {"prescriptions", "patch"}, {"prescriptions", "delete"},
-	{"inventory", "get"},
+	{"inventory", "get"}, {"reorder-suggestions", "get"}, {"expiry-writeoffs", "get"},
 	{"billing", "get"},
+	{"invoices", "get"},
+	{"loyalty", "get"},
+	{"holidays", "get"}, {"shifts", "get"}, {"staff", "get"}, {"rooms", "get"},
+	{"consent", "get"}, {"consent", "post"}, {"consents", "get"}, {"consent-templates", "get"},
+	{"vaccination-coverage", "get"},
 }
 
 var receptionistPermissions = []permEntry{
 	{"dashboard", "get"},
-	{"appointments", "get"}, {"appointments", "post"}, {"appointments", "patch"}, {"appointments", "delete"},
-	{"patients", "get"}, {"patients", "post"}, {"patients", "patch"},
+	{"appointments", "get"}, {"appointments", "post"}, {"appointments", "patch"}, {"appointments", "delete"}, {"status-subscription", "get"}, {"status-subscription", "put"}, {"status-subscription", "delete"}, {"reassign", "patch"}, {"reschedule-request", "patch"}, {"preview-series", "post"}, {"appointment-types", "get"}, {"appointment-workflow", "get"}, {"booking-window", "get"}, {"types", "get"},
+	{"patients", "get"}, {"patients", "post"}, {"patients", "patch"}, {"qr", "get"}, {"resolve-qr", "get"}, {"weight", "get"}, {"weight", "post"}, {"tags", "get"},
 	{"species", "get"}, {"species", "post"},
-	{"owners", "get"}, {"owners", "post"}, {"owners", "patch"},
+	{"owners", "get"}, {"owners", "post"}, {"owners", "patch"}, {"intakes", "get"},
 	{"billing", "get"}, {"billing", "post"}, {"billing", "patch"},
+	{"invoices", "get"}, {"invoices", "post"}, {"issue", "patch"}, {"payment-link", "post"}, {"record-payment", "post"},
+	{"sale", "post"}, {"sales", "get"}, {"receipt.pdf", "get"},
+	{"loyalty", "get"}, {"redeem", "post"},
+	{"holidays", "get"}, {"shifts", "get"}, {"staff", "get"}, {"rooms", "get"},
+	{"consent", "get"}, {"consent", "post"}, {"consents", "get"}, {"consent-templates", "get"},
 	{"prescriptions", "get"},
+	{"no-shows", "get"}, {"vaccination-coverage", "get"},
 }
 
 var assistantPermissions = []permEntry{
 	{"dashboard", "get"},
-	{"appointments", "get"}, {"appointments", "patch"},
-	{"patients", "get"},
+	{"appointments", "get"}, {"appointments", "patch"}, {"status-subscription", "get"}, {"status-subscription", "put"}, {"status-subscription", "delete"},
+	{"patients", "get"}, {"weight", "get"}, {"weight", "post"}, {"tags", "get"},
 	{"species", "get"},
 	{"owners", "get"},
 	{"medical-records", "get"},
-	{"vaccines", "get"}, {"vaccines", "post"}, {"vaccines", "patch"},
-	{"inventory", "get"}, {"inventory", "post"}, {"inventory", "patch"},
+	{"vaccines", "get"}, {"vaccines", "post"}, {"vaccines", "patch"}, {"bulk", "post"},
+	{"vaccine-protocols", "get"}, {"schedule", "post"},
+	{"inventory", "get"}, {"inventory", "post"}, {"inventory", "patch"}, {"reorder-suggestions", "get"}, {"expiry-writeoffs", "get"},
+	{"shifts", "get"}, {"staff", "get"}, {"rooms", "get"},
+	{"consent", "get"},
 }
 
 var accountantPermissions = []permEntry{
 	{"dashboard", "get"},
 	{"billing", "get"}, {"billing", "post"}, {"billing", "put"}, {"billing", "patch"},
-	{"reports", "get"},
-	{"inventory", "get"},
+	{"reports", "get"}, {"no-shows", "get"}, {"export.csv", "get"}, {"export", "get"},
+	{"inventory", "get"}, {"reorder-suggestions", "get"}, {"expiry-writeoffs", "get"},
+	{"sales", "get"}, {"receipt.pdf", "get"},
 }
 
 type roleSeed struct {
@@ -0,0 +1,143 @@
+package indexes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/eren_dev/go_server/internal/modules/health"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Expected is an index the hot query paths rely on, identified by its key
+// pattern in order
+type Expected struct {
+	Collection string
+	Keys       bson.D
+}
+
+// Critical lists the indexes whose absence turns the busiest tenant-scoped
+// queries into collection scans. EnsureAll creates them at startup; the
+// health check catches them going missing afterwards, e.g. after restoring a
+// dump taken without indexes.
+var Critical = []Expected{
+	{Collection: "tenants", Keys: bson.D{{Key: "domain", Value: 1}}},
+	{Collection: "appointments", Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "scheduled_at", Value: 1}}},
+	{Collection: "appointments", Keys: bson.D{{Key: "veterinarian_id", Value: 1}, {Key: "scheduled_at", Value: 1}}},
+	{Collection: "appointments", Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "owner_id", Value: 1}, {Key: "scheduled_at", Value: -1}}},
+	{Collection: "medical_records", Keys: bson.D{{Key: "patient_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Collection: "vaccinations", Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "next_due_date", Value: 1}}},
+	{Collection: "invoices", Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "deleted_at", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Collection: "invoices", Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "number", Value: 1}}},
+	{Collection: "notifications", Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	{Collection: "sequence_counters", Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "key", Value: 1}}},
+}
+
+// Missing returns, per collection, the key patterns of the expected indexes
+// that do not exist, written the way MongoDB names indexes by default
+// (e.g. "tenant_id_1_status_1")
+func Missing(ctx context.Context, db *database.MongoDB, expected []Expected) (map[string][]string, error) {
+	present := make(map[string]map[string]struct{})
+	missing := make(map[string][]string)
+
+	for _, exp := range expected {
+		keys, ok := present[exp.Collection]
+		if !ok {
+			var err error
+			keys, err = keyPatterns(ctx, db, exp.Collection)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", exp.Collection, err)
+			}
+			present[exp.Collection] = keys
+		}
+
+		pattern := keyPattern(exp.Keys)
+		if _, ok := keys[pattern]; !ok {
+			missing[exp.Collection] = append(missing[exp.Collection], pattern)
+		}
+	}
+	return missing, nil
+}
+
+// keyPatterns lists the key patterns of a collection's indexes. Options such
+// as uniqueness are not compared: an index with the right keys serves the
+// queries either way.
+func keyPatterns(ctx context.Context, db *database.MongoDB, collection string) (map[string]struct{}, error) {
+	cursor, err := db.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var specs []struct {
+		Key bson.D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+
+	patterns := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		patterns[keyPattern(spec.Key)] = struct{}{}
+	}
+	return patterns, nil
+}
+
+// keyPattern renders index keys as MongoDB's default index name does. The
+// server reports directions as int32, int64 or double depending on how the
+// index was created, so they are normalized first.
+func keyPattern(keys bson.D) string {
+	parts := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		var dir string
+		switch v := k.Value.(type) {
+		case int32:
+			dir = fmt.Sprint(v)
+		case int64:
+			dir = fmt.Sprint(v)
+		case int:
+			dir = fmt.Sprint(v)
+		case float64:
+			dir = fmt.Sprint(int64(v))
+		default:
+			dir = fmt.Sprint(v)
+		}
+		parts = append(parts, k.Key, dir)
+	}
+	return strings.Join(parts, "_")
+}
+
+// HealthChecker reports the database degraded while any critical index is
+// missing, listing them in the health details
+type HealthChecker struct {
+	db       *database.MongoDB
+	expected []Expected
+}
+
+// NewHealthChecker creates a checker for the Critical indexes
+func NewHealthChecker(db *database.MongoDB) *HealthChecker {
+	return &HealthChecker{db: db, expected: Critical}
+}
+
+func (h *HealthChecker) Name() string {
+	return "mongodb_indexes"
+}
+
+func (h *HealthChecker) Check(ctx context.Context) (int64, error) {
+	start := time.Now()
+	missing, err := Missing(ctx, h.db, h.expected)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		return latency, err
+	}
+	if len(missing) > 0 {
+		return latency, &health.DegradedError{
+			Reason:  "critical indexes missing",
+			Details: map[string]any{"missing_indexes": missing},
+		}
+	}
+	return latency, nil
+}
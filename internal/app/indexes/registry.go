@@ -0,0 +1,149 @@
+package indexes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/consents"
+	"github.com/eren_dev/go_server/internal/modules/holidays"
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
+	"github.com/eren_dev/go_server/internal/modules/laboratory"
+	"github.com/eren_dev/go_server/internal/modules/loyalty"
+	"github.com/eren_dev/go_server/internal/modules/medical_records"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/pos"
+	"github.com/eren_dev/go_server/internal/modules/rooms"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/shifts"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/modules/vaccinations"
+	"github.com/eren_dev/go_server/internal/modules/webhooks"
+	"github.com/eren_dev/go_server/internal/platform/logger"
+	"github.com/eren_dev/go_server/internal/scheduler"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Entry ties a module's EnsureIndexes function to the collections it manages,
+// so the runner can tell which indexes a run actually created.
+type Entry struct {
+	Module      string
+	Collections []string
+	Ensure      func(ctx context.Context, db *database.MongoDB) error
+}
+
+// Registry lists every module that owns MongoDB indexes. New modules with an
+// indexes.go must be added here to be picked up by the API and the migrator.
+var Registry = []Entry{
+	{Module: "tenant", Collections: []string{"tenants"}, Ensure: tenant.EnsureIndexes},
+	{Module: "audit", Collections: []string{"audit_logs"}, Ensure: audit.EnsureIndexes},
+	{Module: "appointments", Collections: []string{"appointments", "appointment_status_transitions", "appointment_types", "appointment_workflows", "appointment_status_subscriptions"}, Ensure: appointments.EnsureIndexes},
+	{Module: "medical_records", Collections: []string{"medical_records", "allergies", "medical_histories", "medical_record_templates"}, Ensure: medical_records.EnsureIndexes},
+	{Module: "inventory", Collections: []string{"products", "product_categories", "stock_movements", "expiry_writeoffs"}, Ensure: inventory.EnsureIndexes},
+	{Module: "vaccinations", Collections: []string{"vaccinations", "vaccines", "vaccine_protocols"}, Ensure: vaccinations.EnsureIndexes},
+	{Module: "laboratory", Collections: []string{"lab_orders", "lab_tests"}, Ensure: laboratory.EnsureIndexes},
+	{Module: "invoices", Collections: []string{"invoices", "invoice_payments"}, Ensure: invoices.EnsureIndexes},
+	{Module: "holidays", Collections: []string{"holidays"}, Ensure: holidays.EnsureIndexes},
+	{Module: "shifts", Collections: []string{"shifts"}, Ensure: shifts.EnsureIndexes},
+	{Module: "loyalty", Collections: []string{"loyalty_transactions"}, Ensure: loyalty.EnsureIndexes},
+	{Module: "notifications", Collections: []string{"notifications", "notification_broadcasts", "notification_templates", "notification_outbox", "notification_dead_letters"}, Ensure: notifications.EnsureIndexes},
+	{Module: "owners", Collections: []string{"owners", "contact_verifications", "owner_intakes"}, Ensure: owners.EnsureIndexes},
+	{Module: "patients", Collections: []string{"patients", "weight_measurements"}, Ensure: patients.EnsureIndexes},
+	{Module: "sequences", Collections: []string{"sequence_counters"}, Ensure: sequences.EnsureIndexes},
+	{Module: "rooms", Collections: []string{"rooms"}, Ensure: rooms.EnsureIndexes},
+	{Module: "consents", Collections: []string{"consent_templates", "consents"}, Ensure: consents.EnsureIndexes},
+	{Module: "pos", Collections: []string{"pos_sales"}, Ensure: pos.EnsureIndexes},
+	{Module: "webhooks", Collections: []string{"webhook_events"}, Ensure: webhooks.EnsureIndexes},
+	{Module: "scheduler", Collections: []string{"scheduler_leases"}, Ensure: scheduler.EnsureIndexes},
+}
+
+// CollectionResult reports the outcome of one run for a single collection.
+type CollectionResult struct {
+	Module     string
+	Collection string
+	Created    []string
+	Existing   int
+}
+
+// EnsureAll runs every registered module and logs, per collection, which
+// indexes were newly created and how many were already in place. It is safe
+// to run repeatedly: CreateMany is a no-op for indexes that already exist.
+// A failing module does not stop the others; all failures are joined in the error.
+func EnsureAll(ctx context.Context, db *database.MongoDB) ([]CollectionResult, error) {
+	log := logger.Default()
+
+	var results []CollectionResult
+	var errs []error
+
+	for _, entry := range Registry {
+		before := make(map[string]map[string]struct{}, len(entry.Collections))
+		for _, coll := range entry.Collections {
+			names, err := indexNames(ctx, db, coll)
+			if err != nil {
+				log.Warn(ctx, "index_list_failed", "module", entry.Module, "collection", coll, "error", err)
+			}
+			before[coll] = names
+		}
+
+		if err := entry.Ensure(ctx, db); err != nil {
+			log.Error(ctx, "indexes_creation_failed", "module", entry.Module, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Module, err))
+			continue
+		}
+
+		for _, coll := range entry.Collections {
+			after, err := indexNames(ctx, db, coll)
+			if err != nil {
+				log.Warn(ctx, "index_list_failed", "module", entry.Module, "collection", coll, "error", err)
+				continue
+			}
+
+			res := CollectionResult{Module: entry.Module, Collection: coll}
+			for name := range after {
+				if _, ok := before[coll][name]; ok {
+					res.Existing++
+				} else {
+					res.Created = append(res.Created, name)
+				}
+			}
+			results = append(results, res)
+
+			log.Info(ctx, "indexes_ensured",
+				"module", entry.Module,
+				"collection", coll,
+				"created", res.Created,
+				"existing", res.Existing,
+			)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func indexNames(ctx context.Context, db *database.MongoDB, collection string) (map[string]struct{}, error) {
+	cursor, err := db.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var specs []bson.M
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		if name, ok := spec["name"].(string); ok {
+			names[name] = struct{}{}
+		}
+	}
+	return names, nil
+}
@@ -7,10 +7,15 @@ import (
 
 	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/admin"
 	"github.com/eren_dev/go_server/internal/modules/appointments"
 	"github.com/eren_dev/go_server/internal/modules/auth"
+	"github.com/eren_dev/go_server/internal/modules/consents"
+	"github.com/eren_dev/go_server/internal/modules/holidays"
 	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
 	"github.com/eren_dev/go_server/internal/modules/laboratory"
+	"github.com/eren_dev/go_server/internal/modules/loyalty"
 	"github.com/eren_dev/go_server/internal/modules/medical_records"
 	"github.com/eren_dev/go_server/internal/modules/vaccinations"
 	mobileAuth "github.com/eren_dev/go_server/internal/modules/mobile_auth"
@@ -20,8 +25,14 @@ import (
 	"github.com/eren_dev/go_server/internal/modules/payments"
 	"github.com/eren_dev/go_server/internal/modules/permissions"
 	"github.com/eren_dev/go_server/internal/modules/plans"
+	"github.com/eren_dev/go_server/internal/modules/pos"
+	"github.com/eren_dev/go_server/internal/modules/reports"
 	"github.com/eren_dev/go_server/internal/modules/resources"
 	"github.com/eren_dev/go_server/internal/modules/roles"
+	"github.com/eren_dev/go_server/internal/modules/rooms"
+	"github.com/eren_dev/go_server/internal/modules/shifts"
+	"github.com/eren_dev/go_server/internal/modules/staff"
+	"github.com/eren_dev/go_server/internal/modules/tags"
 	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/modules/webhooks"
@@ -90,10 +101,30 @@ func registerRoutes(engine *gin.Engine, db *database.MongoDB, cfg *config.Config
 	mobileTenant.Use(sharedMiddleware.TenantMiddleware())
 	mobileTenant.Use(sharedMiddleware.OwnerGuardMiddleware())
 
+	// Tenant-scoped mobile routes for staff (JWT + X-Tenant-ID + StaffGuard)
+	mobileStaff := r.Group("/mobile")
+	mobileStaff.Use(sharedAuth.JWTMiddleware(cfg))
+	mobileStaff.Use(sharedMiddleware.TenantMiddleware())
+	mobileStaff.Use(sharedMiddleware.StaffGuardMiddleware())
+
 	if db != nil {
 		// Initialize optional Redis cache (disabled if REDIS_ADDR not configured)
 		redisCache := initializeCache()
 
+		// X-Tenant-ID must name an active tenant the caller belongs to
+		tenantAccess := tenant.NewAccessChecker(tenant.NewTenantRepository(db))
+		privateTenant.Use(sharedMiddleware.TenantAccessMiddleware(sharedMiddleware.TenantAccessConfig{
+			Tenants: tenantAccess,
+			Users:   users.NewRepository(db),
+		}))
+		mobileTenant.Use(sharedMiddleware.TenantAccessMiddleware(sharedMiddleware.TenantAccessConfig{
+			Tenants: tenantAccess,
+		}))
+		mobileStaff.Use(sharedMiddleware.TenantAccessMiddleware(sharedMiddleware.TenantAccessConfig{
+			Tenants: tenantAccess,
+			Users:   users.NewRepository(db),
+		}))
+
 		// Initialize hierarchical rate limiter
 		rateLimiterCfg := ratelimit.DefaultConfig()
 		rateLimiter := ratelimit.NewLimiter(rateLimiterCfg)
@@ -116,6 +147,16 @@ func registerRoutes(engine *gin.Engine, db *database.MongoDB, cfg *config.Config
 		// Apply tenant rate limiting to tenant-scoped routes
 		privateTenant.Use(sharedMiddleware.TenantRateLimitMiddleware(rateLimiter))
 		mobileTenant.Use(sharedMiddleware.TenantRateLimitMiddleware(rateLimiter))
+		mobileStaff.Use(sharedMiddleware.TenantRateLimitMiddleware(rateLimiter))
+
+		// La agenda mobile del staff es solo para veterinarios
+		mobileStaff.Use(sharedMiddleware.StaffRoleMiddleware(sharedMiddleware.StaffRoleConfig{
+			UserRepo: users.NewRepository(db),
+			RoleRepo: roles.NewRepository(db),
+		}, "veterinarian"))
+
+		// Owners only reach the clinics they are associated with
+		mobileTenant.Use(sharedMiddleware.OwnerTenantMiddleware(owners.NewService(owners.NewRepository(db), tenant.NewTenantRepository(db))))
 
 		// Staff auth: rutas públicas + /auth/me sin RBAC
 		auth.RegisterRoutes(public, authPrivate, db, cfg)
@@ -136,42 +177,87 @@ func registerRoutes(engine *gin.Engine, db *database.MongoDB, cfg *config.Config
 		payments.RegisterRoutes(private, db)
 
 		// Webhooks module (público)
-		webhooks.RegisterRoutes(public, db, paymentManager, cfg)
+		webhooks.RegisterRoutes(public, db, paymentManager, pushProvider, cfg)
+		webhooks.RegisterAdminRoutes(private, db, paymentManager, pushProvider, cfg)
 
 		// RBAC modules (JWT + RBAC)
 		resources.RegisterRoutes(private, db)
 		permissions.RegisterRoutes(private, db)
 		roles.RegisterRoutes(private, db)
 
+		// Diagnóstico de configuración para operadores (JWT + RBAC)
+		admin.RegisterRoutes(private, cfg)
+
+		// Owner merge (JWT + Tenant + RBAC)
+		owners.RegisterMergeRoutes(privateTenant, db, auditService)
+		owners.RegisterIntakeRoutes(privateTenant, db)
+
 		// Patients + Species (JWT + Tenant + RBAC)
-		patients.RegisterAdminRoutes(privateTenant, db)
+		patients.RegisterAdminRoutes(privateTenant, db, cfg)
 
 		// Appointments (JWT + Tenant + RBAC)
-		appointments.RegisterAdminRoutes(privateTenant, db, pushProvider, cfg)
+		appointments.RegisterAdminRoutes(privateTenant, db, pushProvider, paymentManager, cfg)
 
 		// Medical Records (JWT + Tenant + RBAC)
-		medical_records.RegisterAdminRoutes(privateTenant, db)
+		medical_records.RegisterAdminRoutes(privateTenant, db, cfg, appointments.BuildService(db, pushProvider, paymentManager, cfg))
 
 		// Inventory (JWT + Tenant + RBAC)
-		inventory.RegisterAdminRoutes(privateTenant, db)
+		inventory.RegisterAdminRoutes(privateTenant, db, cfg)
 
 		// Vaccinations (JWT + Tenant + RBAC)
 		vaccinations.RegisterAdminRoutes(privateTenant, db)
 
+		// Verificación pública de certificados de vacunación (sin autenticación)
+		vaccinations.RegisterPublicRoutes(public, db)
+
 		// Laboratory (JWT + Tenant + RBAC)
 		laboratory.RegisterAdminRoutes(privateTenant, db)
 
+		// Invoices (JWT + Tenant + RBAC)
+		invoices.RegisterAdminRoutes(privateTenant, db, paymentManager)
+
+		// Counter sales (JWT + Tenant + RBAC)
+		pos.RegisterAdminRoutes(privateTenant, db)
+
+		// Holiday calendar (JWT + Tenant + RBAC, admin only)
+		holidays.RegisterAdminRoutes(privateTenant, db)
+
+		// Tag autocomplete for patients and products (JWT + Tenant + RBAC)
+		tags.RegisterAdminRoutes(privateTenant, db)
+
+		// Staff roster (JWT + Tenant + RBAC)
+		shifts.RegisterAdminRoutes(privateTenant, db)
+
+		// Staff directory (JWT + Tenant + RBAC)
+		staff.RegisterAdminRoutes(privateTenant, db)
+
+		// Bookable rooms and equipment (JWT + Tenant + RBAC)
+		rooms.RegisterAdminRoutes(privateTenant, db)
+
+		// Procedure consent texts and signed consents (JWT + Tenant + RBAC)
+		consents.RegisterAdminRoutes(privateTenant, db)
+
+		// Loyalty program (JWT + Tenant + RBAC)
+		loyalty.RegisterAdminRoutes(privateTenant, db)
+
+		// Reports (JWT + Tenant + RBAC)
+		reports.RegisterAdminRoutes(privateTenant, db)
+
 		// Mobile auth routes (public + owner-private)
 		mobileAuth.RegisterRoutes(mobilePublic, mobilePrivate, db, cfg)
 
-		// Mobile owner profile routes (owner-private)
-		owners.RegisterMobileRoutes(mobilePrivate, db)
+		// Mobile owner profile + clinic selection routes (owner-private)
+		owners.RegisterMobileRoutes(mobilePrivate, db, cfg)
 
 		// Mobile patients (owner-private + tenant)
 		patients.RegisterMobileRoutes(mobileTenant, db)
+		owners.RegisterIntakeMobileRoutes(mobileTenant, db)
 
 		// Mobile appointments (owner-private + tenant)
-		appointments.RegisterMobileRoutes(mobileTenant, db, pushProvider, cfg)
+		appointments.RegisterMobileRoutes(mobileTenant, db, pushProvider, paymentManager, cfg)
+
+		// Mobile vet agenda (staff-private + tenant, vets only)
+		appointments.RegisterVetMobileRoutes(mobileStaff, db, pushProvider, paymentManager, cfg)
 
 		// Mobile medical records (owner-private + tenant, read-only)
 		medical_records.RegisterMobileRoutes(mobileTenant, db)
@@ -182,10 +268,21 @@ func registerRoutes(engine *gin.Engine, db *database.MongoDB, cfg *config.Config
 		// Mobile laboratory (owner-private + tenant, read-only)
 		laboratory.RegisterMobileRoutes(mobileTenant, db)
 
-		// Mobile notifications (owner-private)
-		notifications.RegisterMobileRoutes(mobilePrivate, db, pushProvider)
+		// Mobile loyalty balance (owner-private + tenant)
+		loyalty.RegisterMobileRoutes(mobileTenant, db)
+
+		// Mobile vet picker (owner-private + tenant, read-only)
+		staff.RegisterMobileRoutes(mobileTenant, db)
+
+		// Mobile notifications inbox (owner-private + tenant)
+		notifications.RegisterMobileRoutes(mobileTenant, db, pushProvider)
 
 		// Admin notifications (JWT only, no RBAC — any staff member can read their own)
 		notifications.RegisterAdminRoutes(authPrivate, db, pushProvider)
+
+		// Owner broadcasts (JWT + Tenant + RBAC, admin only)
+		notifications.RegisterBroadcastRoutes(privateTenant, db, cfg)
+		notifications.RegisterTemplateRoutes(privateTenant, db)
+		notifications.RegisterDeadLetterRoutes(privateTenant, db)
 	}
 }
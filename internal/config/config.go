@@ -49,15 +49,33 @@ type Config struct {
 	// Trusted Proxies
 	TrustedProxies []string
 
+	// Pagination: limit por defecto de los listados y máximo que se acepta
+	PaginationDefaultLimit int64 `env:"PAGINATION_DEFAULT_LIMIT" envDefault:"10"`
+	PaginationMaxLimit     int64 `env:"PAGINATION_MAX_LIMIT" envDefault:"100"`
+
+	// Caché en memoria de datos de referencia (tenants, especies, catálogo de
+	// vacunas, festivos): segundos que vive cada entrada; 0 la desactiva
+	ReferenceCacheTTLSeconds int `env:"REFERENCE_CACHE_TTL_SECONDS" envDefault:"60"`
+
 	// MongoDB
 	MongoURI      string
 	MongoDatabase string
 	MongoTimeout  time.Duration
-
-	// JWT
-	JWTSecret            string
-	JWTExpiration        time.Duration
-	JWTRefreshExpiration time.Duration
+	// Operations slower than this are logged with their collection and
+	// filter shape; 0 disables the instrumentation
+	MongoSlowQueryThreshold time.Duration
+
+	// JWT: staff sessions. Refresh tokens of a "remember me" login live for
+	// JWTRememberExpiration instead of JWTRefreshExpiration.
+	JWTSecret             string
+	JWTExpiration         time.Duration
+	JWTRefreshExpiration  time.Duration
+	JWTRememberExpiration time.Duration
+
+	// JWT: owner (mobile app) sessions, independent from staff defaults
+	MobileJWTExpiration         time.Duration
+	MobileJWTRefreshExpiration  time.Duration
+	MobileJWTRememberExpiration time.Duration
 
 	// Payment Providers
 	PaymentDefaultProvider string
@@ -70,12 +88,62 @@ type Config struct {
 
 	// Firebase / Push Notifications
 	FirebaseCredentialsPath string
+	FCMMaxConcurrentSends   int `env:"FCM_MAX_CONCURRENT_SENDS" envDefault:"10"`
+	FCMSendQueueSize        int `env:"FCM_SEND_QUEUE_SIZE" envDefault:"1000"`
+	FCMSendTimeout          time.Duration
 
 	// Business Rules
 	AppointmentBusinessStartHour int `env:"APPOINTMENT_START_HOUR" envDefault:"8"`
 	AppointmentBusinessEndHour   int `env:"APPOINTMENT_END_HOUR" envDefault:"18"`
 	TenantTrialDays              int `env:"TENANT_TRIAL_DAYS" envDefault:"14"`
 	SchedulerIntervalMinutes     int `env:"SCHEDULER_INTERVAL_MINS" envDefault:"15"`
+	// Scheduler lock: with several instances, each job runs on the one holding
+	// its lease; the lease lasts SchedulerLockTTLSeconds (0 = one interval)
+	SchedulerLockEnabled    bool `env:"SCHEDULER_LOCK_ENABLED" envDefault:"false"`
+	SchedulerLockTTLSeconds int  `env:"SCHEDULER_LOCK_TTL_SECS" envDefault:"0"`
+
+	// Notifications
+	NotificationBroadcastsPerHour int `env:"NOTIFICATION_BROADCASTS_PER_HOUR" envDefault:"5"`
+
+	// Inventory
+	StockReversalWindowHours int `env:"STOCK_REVERSAL_WINDOW_HOURS" envDefault:"24"`
+
+	// Push: tokens whose device did not register again in this many days are
+	// pruned; 0 keeps them until FCM reports them invalid
+	PushTokenTTLDays int `env:"PUSH_TOKEN_TTL_DAYS" envDefault:"60"`
+
+	// Patients: HMAC key for patient QR codes (falls back to JWT_SECRET)
+	PatientQRSecret string `env:"PATIENT_QR_SECRET"`
+
+	// Email
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+
+	// SMS: gateway HTTP que recibe {"to","body"}; sin URL el canal queda deshabilitado
+	SMSGatewayURL   string `env:"SMS_GATEWAY_URL"`
+	SMSGatewayToken string `env:"SMS_GATEWAY_TOKEN"`
+
+	// Verificación de contacto de propietarios: vigencia del código, intentos
+	// por código y códigos que se pueden pedir por hora y canal
+	ContactVerificationTTLMinutes  int `env:"CONTACT_VERIFICATION_TTL_MINUTES" envDefault:"10"`
+	ContactVerificationMaxAttempts int `env:"CONTACT_VERIFICATION_MAX_ATTEMPTS" envDefault:"5"`
+	ContactVerificationPerHour     int `env:"CONTACT_VERIFICATION_PER_HOUR" envDefault:"3"`
+
+	// Weekly vet digest: weekday (0=Sunday) and hour at which it is sent
+	WeeklyDigestWeekday int `env:"WEEKLY_DIGEST_WEEKDAY" envDefault:"0"`
+	WeeklyDigestHour    int `env:"WEEKLY_DIGEST_HOUR" envDefault:"18"`
+
+	// Retention: days a soft-deleted document is kept before being purged.
+	// RETENTION_DAYS overrides it per collection, e.g. "appointments=730,medical_records=0";
+	// 0 keeps that collection's documents forever. Clinical collections
+	// (ClinicalRecordCollections) are kept forever unless RETENTION_DAYS names them.
+	RetentionDefaultDays int            `env:"RETENTION_DEFAULT_DAYS" envDefault:"3650"`
+	RetentionDays        map[string]int `env:"RETENTION_DAYS"`
+	RetentionDryRun      bool           `env:"RETENTION_DRY_RUN" envDefault:"false"`
+	RetentionPurgeHour   int            `env:"RETENTION_PURGE_HOUR" envDefault:"3"`
 }
 
 func Load() *Config {
@@ -120,15 +188,28 @@ func Load() *Config {
 		// Proxies
 		TrustedProxies: getEnvSlice("TRUSTED_PROXIES", nil),
 
+		// Pagination
+		PaginationDefaultLimit: getEnvInt64("PAGINATION_DEFAULT_LIMIT", 10),
+		PaginationMaxLimit:     getEnvInt64("PAGINATION_MAX_LIMIT", 100),
+
+		ReferenceCacheTTLSeconds: getEnvInt("REFERENCE_CACHE_TTL_SECONDS", 60),
+
 		// MongoDB
 		MongoURI:      getEnv("MONGO_URI", "mongodb://localhost:27017"),
 		MongoDatabase: getEnv("MONGO_DATABASE", ""),
 		MongoTimeout:  time.Duration(getEnvInt("MONGO_TIMEOUT_SECS", 10)) * time.Second,
 
+		MongoSlowQueryThreshold: time.Duration(getEnvInt("MONGO_SLOW_QUERY_MS", 0)) * time.Millisecond,
+
 		// JWT
-		JWTSecret:            getEnv("JWT_SECRET", ""),
-		JWTExpiration:        time.Duration(getEnvInt("JWT_EXPIRATION_MINS", 15)) * time.Minute,
-		JWTRefreshExpiration: time.Duration(getEnvInt("JWT_REFRESH_EXPIRATION_DAYS", 7)) * 24 * time.Hour,
+		JWTSecret:             getEnv("JWT_SECRET", ""),
+		JWTExpiration:         time.Duration(getEnvInt("JWT_EXPIRATION_MINS", 15)) * time.Minute,
+		JWTRefreshExpiration:  time.Duration(getEnvInt("JWT_REFRESH_EXPIRATION_DAYS", 7)) * 24 * time.Hour,
+		JWTRememberExpiration: time.Duration(getEnvInt("JWT_REMEMBER_EXPIRATION_DAYS", 30)) * 24 * time.Hour,
+
+		MobileJWTExpiration:         time.Duration(getEnvInt("MOBILE_JWT_EXPIRATION_MINS", 60)) * time.Minute,
+		MobileJWTRefreshExpiration:  time.Duration(getEnvInt("MOBILE_JWT_REFRESH_EXPIRATION_DAYS", 7)) * 24 * time.Hour,
+		MobileJWTRememberExpiration: time.Duration(getEnvInt("MOBILE_JWT_REMEMBER_EXPIRATION_DAYS", 90)) * 24 * time.Hour,
 
 		// Payment Providers
 		PaymentDefaultProvider: getEnv("PAYMENT_DEFAULT_PROVIDER", "wompi"),
@@ -140,12 +221,51 @@ func Load() *Config {
 		StripeWebhookSecret:    getEnv("STRIPE_WEBHOOK_SECRET", ""),
 
 		FirebaseCredentialsPath: getEnv("FIREBASE_CREDENTIALS_PATH", ""),
+		FCMMaxConcurrentSends:   getEnvInt("FCM_MAX_CONCURRENT_SENDS", 10),
+		FCMSendQueueSize:        getEnvInt("FCM_SEND_QUEUE_SIZE", 1000),
+		FCMSendTimeout:          time.Duration(getEnvInt("FCM_SEND_TIMEOUT_SECS", 10)) * time.Second,
 
 		// Business Rules
 		AppointmentBusinessStartHour: getEnvInt("APPOINTMENT_START_HOUR", 8),
 		AppointmentBusinessEndHour:   getEnvInt("APPOINTMENT_END_HOUR", 18),
 		TenantTrialDays:              getEnvInt("TENANT_TRIAL_DAYS", 14),
 		SchedulerIntervalMinutes:     getEnvInt("SCHEDULER_INTERVAL_MINS", 15),
+		SchedulerLockEnabled:         getEnvBool("SCHEDULER_LOCK_ENABLED", false),
+		SchedulerLockTTLSeconds:      getEnvInt("SCHEDULER_LOCK_TTL_SECS", 0),
+
+		// Notifications
+		NotificationBroadcastsPerHour: getEnvInt("NOTIFICATION_BROADCASTS_PER_HOUR", 5),
+
+		// Inventory
+		StockReversalWindowHours: getEnvInt("STOCK_REVERSAL_WINDOW_HOURS", 24),
+
+		// Push
+		PushTokenTTLDays: getEnvInt("PUSH_TOKEN_TTL_DAYS", 60),
+
+		// Patients
+		PatientQRSecret: getEnv("PATIENT_QR_SECRET", ""),
+
+		// Email
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "no-reply@vetapp.local"),
+
+		SMSGatewayURL:   getEnv("SMS_GATEWAY_URL", ""),
+		SMSGatewayToken: getEnv("SMS_GATEWAY_TOKEN", ""),
+
+		ContactVerificationTTLMinutes:  getEnvInt("CONTACT_VERIFICATION_TTL_MINUTES", 10),
+		ContactVerificationMaxAttempts: getEnvInt("CONTACT_VERIFICATION_MAX_ATTEMPTS", 5),
+		ContactVerificationPerHour:     getEnvInt("CONTACT_VERIFICATION_PER_HOUR", 3),
+
+		WeeklyDigestWeekday: getEnvInt("WEEKLY_DIGEST_WEEKDAY", 0),
+		WeeklyDigestHour:    getEnvInt("WEEKLY_DIGEST_HOUR", 18),
+
+		RetentionDefaultDays: getEnvInt("RETENTION_DEFAULT_DAYS", 3650),
+		RetentionDays:        getRetentionDays(),
+		RetentionDryRun:      getEnvBool("RETENTION_DRY_RUN", false),
+		RetentionPurgeHour:   getEnvInt("RETENTION_PURGE_HOUR", 3),
 	}
 }
 
@@ -198,3 +318,40 @@ func getEnvSlice(key string, def []string) []string {
 	}
 	return def
 }
+
+// getEnvIntMap lee pares "clave=entero" separados por comas; los mal formados se ignoran
+// ClinicalRecordCollections hold medical records that clinics must keep;
+// their soft-deleted documents are never purged unless configured explicitly.
+var ClinicalRecordCollections = []string{"medical_records", "medical_histories", "vaccinations", "allergies"}
+
+// getRetentionDays reads RETENTION_DAYS, keeping clinical collections forever
+// when it does not set them
+func getRetentionDays() map[string]int {
+	days := getEnvIntMap("RETENTION_DAYS")
+	for _, collection := range ClinicalRecordCollections {
+		if _, ok := days[collection]; !ok {
+			days[collection] = 0
+		}
+	}
+	return days
+}
+
+func getEnvIntMap(key string) map[string]int {
+	m := make(map[string]int)
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return m
+	}
+	for _, pair := range strings.Split(v, ",") {
+		k, raw, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+		m[strings.TrimSpace(k)] = n
+	}
+	return m
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_RetentionKeepsClinicalRecordsByDefault(t *testing.T) {
+	t.Setenv("RETENTION_DAYS", "")
+
+	cfg := Load()
+
+	for _, collection := range ClinicalRecordCollections {
+		days, ok := cfg.RetentionDays[collection]
+		assert.True(t, ok, "%s must have an explicit retention", collection)
+		assert.Zero(t, days, "%s must be kept forever by default", collection)
+	}
+}
+
+func TestLoad_RetentionOverridesClinicalRecords(t *testing.T) {
+	t.Setenv("RETENTION_DAYS", "medical_records=7300,appointments=730")
+
+	cfg := Load()
+
+	assert.Equal(t, 7300, cfg.RetentionDays["medical_records"])
+	assert.Equal(t, 730, cfg.RetentionDays["appointments"])
+	assert.Zero(t, cfg.RetentionDays["vaccinations"])
+}
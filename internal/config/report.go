@@ -0,0 +1,192 @@
+package config
+
+// Report es la configuración efectiva tal como la ve el proceso, pensada para
+// diagnóstico de operadores. Nunca incluye valores secretos: de las llaves,
+// contraseñas y URIs con credenciales solo se informa si están definidas.
+type Report struct {
+	Env   string `json:"env" example:"production"`
+	Port  string `json:"port" example:"8080"`
+	Valid bool   `json:"valid"`
+	// ValidationError es el error de Validate() cuando Valid es false
+	ValidationError string `json:"validation_error,omitempty"`
+
+	Server        ServerReport        `json:"server"`
+	Database      DatabaseReport      `json:"database"`
+	Auth          AuthReport          `json:"auth"`
+	Integrations  IntegrationsReport  `json:"integrations"`
+	BusinessRules BusinessRulesReport `json:"business_rules"`
+}
+
+type ServerReport struct {
+	ShutdownSecs           int      `json:"shutdown_secs"`
+	ReadTimeoutSecs        int      `json:"read_timeout_secs"`
+	WriteTimeoutSecs       int      `json:"write_timeout_secs"`
+	RequestTimeout         string   `json:"request_timeout" example:"30s"`
+	MaxBodySize            int64    `json:"max_body_size"`
+	CORSAllowOrigins       []string `json:"cors_allow_origins"`
+	RateLimitEnabled       bool     `json:"rate_limit_enabled"`
+	RateLimitRPS           float64  `json:"rate_limit_rps"`
+	RateLimitBurst         int      `json:"rate_limit_burst"`
+	SecurityHeadersEnabled bool     `json:"security_headers_enabled"`
+	CompressionEnabled     bool     `json:"compression_enabled"`
+	TrustedProxies         []string `json:"trusted_proxies"`
+}
+
+type DatabaseReport struct {
+	MongoURISet   bool   `json:"mongo_uri_set"`
+	MongoDatabase string `json:"mongo_database"`
+	MongoTimeout  string `json:"mongo_timeout" example:"10s"`
+	// MongoSlowQueryThreshold "0s" significa que no se registran consultas lentas
+	MongoSlowQueryThreshold string `json:"mongo_slow_query_threshold" example:"200ms"`
+}
+
+type AuthReport struct {
+	JWTSecretSet                bool   `json:"jwt_secret_set"`
+	JWTExpiration               string `json:"jwt_expiration"`
+	JWTRefreshExpiration        string `json:"jwt_refresh_expiration"`
+	JWTRememberExpiration       string `json:"jwt_remember_expiration"`
+	MobileJWTExpiration         string `json:"mobile_jwt_expiration"`
+	MobileJWTRefreshExpiration  string `json:"mobile_jwt_refresh_expiration"`
+	MobileJWTRememberExpiration string `json:"mobile_jwt_remember_expiration"`
+	// PatientQRSecretSet false significa que los QR se firman con JWT_SECRET
+	PatientQRSecretSet bool `json:"patient_qr_secret_set"`
+}
+
+// IntegrationsReport indica qué integraciones opcionales quedan activas según
+// las llaves definidas
+type IntegrationsReport struct {
+	PaymentDefaultProvider string          `json:"payment_default_provider" example:"wompi"`
+	Wompi                  IntegrationInfo `json:"wompi"`
+	Stripe                 IntegrationInfo `json:"stripe"`
+	FCM                    FCMInfo         `json:"fcm"`
+	SMTP                   SMTPInfo        `json:"smtp"`
+}
+
+type IntegrationInfo struct {
+	Active bool `json:"active"`
+	// WebhookSecretSet false significa que los webhooks no se pueden verificar
+	WebhookSecretSet bool `json:"webhook_secret_set"`
+}
+
+type FCMInfo struct {
+	Active             bool   `json:"active"`
+	MaxConcurrentSends int    `json:"max_concurrent_sends"`
+	SendQueueSize      int    `json:"send_queue_size"`
+	SendTimeout        string `json:"send_timeout"`
+	TokenTTLDays       int    `json:"token_ttl_days"`
+}
+
+type SMTPInfo struct {
+	Active      bool   `json:"active"`
+	Host        string `json:"host,omitempty"`
+	Port        int    `json:"port"`
+	From        string `json:"from"`
+	UsernameSet bool   `json:"username_set"`
+	PasswordSet bool   `json:"password_set"`
+}
+
+type BusinessRulesReport struct {
+	AppointmentBusinessStartHour  int  `json:"appointment_business_start_hour"`
+	AppointmentBusinessEndHour    int  `json:"appointment_business_end_hour"`
+	TenantTrialDays               int  `json:"tenant_trial_days"`
+	SchedulerIntervalMinutes      int  `json:"scheduler_interval_minutes"`
+	SchedulerLockEnabled          bool `json:"scheduler_lock_enabled"`
+	SchedulerLockTTLSeconds       int  `json:"scheduler_lock_ttl_seconds"`
+	NotificationBroadcastsPerHour int  `json:"notification_broadcasts_per_hour"`
+	StockReversalWindowHours      int  `json:"stock_reversal_window_hours"`
+	WeeklyDigestWeekday           int  `json:"weekly_digest_weekday"`
+	WeeklyDigestHour              int  `json:"weekly_digest_hour"`
+
+	RetentionDefaultDays int            `json:"retention_default_days"`
+	RetentionDays        map[string]int `json:"retention_days,omitempty"`
+	RetentionDryRun      bool           `json:"retention_dry_run"`
+	RetentionPurgeHour   int            `json:"retention_purge_hour"`
+}
+
+// Report arma el reporte de la configuración sin secretos
+func (c *Config) Report() *Report {
+	r := &Report{
+		Env:   c.Env,
+		Port:  c.Port,
+		Valid: true,
+		Server: ServerReport{
+			ShutdownSecs:           c.ShutdownSecs,
+			ReadTimeoutSecs:        c.ReadTimeoutSecs,
+			WriteTimeoutSecs:       c.WriteTimeoutSecs,
+			RequestTimeout:         c.RequestTimeout.String(),
+			MaxBodySize:            c.MaxBodySize,
+			CORSAllowOrigins:       c.CORSAllowOrigins,
+			RateLimitEnabled:       c.RateLimitEnabled,
+			RateLimitRPS:           c.RateLimitRPS,
+			RateLimitBurst:         c.RateLimitBurst,
+			SecurityHeadersEnabled: c.SecurityHeadersEnabled,
+			CompressionEnabled:     c.CompressionEnabled,
+			TrustedProxies:         c.TrustedProxies,
+		},
+		Database: DatabaseReport{
+			MongoURISet:   c.MongoURI != "",
+			MongoDatabase: c.MongoDatabase,
+			MongoTimeout:  c.MongoTimeout.String(),
+
+			MongoSlowQueryThreshold: c.MongoSlowQueryThreshold.String(),
+		},
+		Auth: AuthReport{
+			JWTSecretSet:                c.JWTSecret != "",
+			JWTExpiration:               c.JWTExpiration.String(),
+			JWTRefreshExpiration:        c.JWTRefreshExpiration.String(),
+			JWTRememberExpiration:       c.JWTRememberExpiration.String(),
+			MobileJWTExpiration:         c.MobileJWTExpiration.String(),
+			MobileJWTRefreshExpiration:  c.MobileJWTRefreshExpiration.String(),
+			MobileJWTRememberExpiration: c.MobileJWTRememberExpiration.String(),
+			PatientQRSecretSet:          c.PatientQRSecret != "",
+		},
+		Integrations: IntegrationsReport{
+			PaymentDefaultProvider: c.PaymentDefaultProvider,
+			Wompi: IntegrationInfo{
+				Active:           c.WompiPublicKey != "" && c.WompiPrivateKey != "",
+				WebhookSecretSet: c.WompiWebhookSecret != "",
+			},
+			Stripe: IntegrationInfo{
+				Active:           c.StripeAPIKey != "",
+				WebhookSecretSet: c.StripeWebhookSecret != "",
+			},
+			FCM: FCMInfo{
+				Active:             c.FirebaseCredentialsPath != "",
+				MaxConcurrentSends: c.FCMMaxConcurrentSends,
+				SendQueueSize:      c.FCMSendQueueSize,
+				SendTimeout:        c.FCMSendTimeout.String(),
+				TokenTTLDays:       c.PushTokenTTLDays,
+			},
+			SMTP: SMTPInfo{
+				Active:      c.SMTPHost != "",
+				Host:        c.SMTPHost,
+				Port:        c.SMTPPort,
+				From:        c.SMTPFrom,
+				UsernameSet: c.SMTPUsername != "",
+				PasswordSet: c.SMTPPassword != "",
+			},
+		},
+		BusinessRules: BusinessRulesReport{
+			AppointmentBusinessStartHour:  c.AppointmentBusinessStartHour,
+			AppointmentBusinessEndHour:    c.AppointmentBusinessEndHour,
+			TenantTrialDays:               c.TenantTrialDays,
+			SchedulerIntervalMinutes:      c.SchedulerIntervalMinutes,
+			SchedulerLockEnabled:          c.SchedulerLockEnabled,
+			SchedulerLockTTLSeconds:       c.SchedulerLockTTLSeconds,
+			NotificationBroadcastsPerHour: c.NotificationBroadcastsPerHour,
+			StockReversalWindowHours:      c.StockReversalWindowHours,
+			WeeklyDigestWeekday:           c.WeeklyDigestWeekday,
+			WeeklyDigestHour:              c.WeeklyDigestHour,
+			RetentionDefaultDays:          c.RetentionDefaultDays,
+			RetentionDays:                 c.RetentionDays,
+			RetentionDryRun:               c.RetentionDryRun,
+			RetentionPurgeHour:            c.RetentionPurgeHour,
+		},
+	}
+
+	if err := c.Validate(); err != nil {
+		r.Valid = false
+		r.ValidationError = err.Error()
+	}
+	return r
+}
@@ -44,5 +44,42 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max_header_bytes invalid")
 	}
 
+	if c.PaginationMaxLimit <= 0 {
+		return fmt.Errorf("pagination_max_limit invalid")
+	}
+	if c.PaginationDefaultLimit <= 0 || c.PaginationDefaultLimit > c.PaginationMaxLimit {
+		return fmt.Errorf("pagination_default_limit invalid")
+	}
+
+	if c.RetentionDefaultDays < 0 {
+		return fmt.Errorf("retention_default_days invalid")
+	}
+	for collection, days := range c.RetentionDays {
+		if days < 0 {
+			return fmt.Errorf("retention_days[%s] invalid", collection)
+		}
+	}
+	if c.RetentionPurgeHour < 0 || c.RetentionPurgeHour > 23 {
+		return fmt.Errorf("retention_purge_hour invalid")
+	}
+
+	if c.SchedulerLockTTLSeconds < 0 {
+		return fmt.Errorf("scheduler_lock_ttl_seconds invalid")
+	}
+
+	if c.PushTokenTTLDays < 0 {
+		return fmt.Errorf("push_token_ttl_days invalid")
+	}
+
+	if c.ContactVerificationTTLMinutes <= 0 {
+		return fmt.Errorf("contact_verification_ttl_minutes invalid")
+	}
+	if c.ContactVerificationMaxAttempts <= 0 {
+		return fmt.Errorf("contact_verification_max_attempts invalid")
+	}
+	if c.ContactVerificationPerHour <= 0 {
+		return fmt.Errorf("contact_verification_per_hour invalid")
+	}
+
 	return nil
 }
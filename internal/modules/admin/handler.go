@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/eren_dev/go_server/internal/config"
+)
+
+// Handler serves operator diagnostics
+type Handler struct {
+	cfg *config.Config
+}
+
+// NewHandler creates a new admin handler
+func NewHandler(cfg *config.Config) *Handler {
+	return &Handler{cfg: cfg}
+}
+
+// GetConfig returns the effective configuration
+// @Summary Effective configuration
+// @Description Non-secret effective configuration, the result of its validation and which optional integrations (Wompi, Stripe, FCM, SMTP) are active. Keys and secrets are reported only as set or not set
+// @Tags admin
+// @Produce json
+// @Success 200 {object} config.Report
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/admin/config [get]
+func (h *Handler) GetConfig(c *gin.Context) (any, error) {
+	return h.cfg.Report(), nil
+}
@@ -0,0 +1,14 @@
+package admin
+
+import (
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterRoutes registers operator routes under /api/admin (JWT + RBAC, resource "config")
+func RegisterRoutes(private *httpx.Router, cfg *config.Config) {
+	handler := NewHandler(cfg)
+
+	r := private.Group("/admin")
+	r.GET("/config", handler.GetConfig)
+}
@@ -0,0 +1,206 @@
+package appointments
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/staff"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// VetDirectory lists the clinic's active veterinarians
+type VetDirectory interface {
+	Vets(ctx context.Context, tenantID primitive.ObjectID) ([]staff.VetResponse, error)
+}
+
+// VetCandidate is a veterinarian considered for an appointment request
+type VetCandidate struct {
+	ID          primitive.ObjectID
+	Specialties []string
+	// Load is the number of active appointments the vet has that day
+	Load int
+}
+
+// AssignmentRequest describes the appointment a vet is being picked for
+type AssignmentRequest struct {
+	TenantID    primitive.ObjectID
+	ScheduledAt time.Time
+	Duration    int
+	// Specialty is the one configured on the appointment type, if any
+	Specialty string
+}
+
+// AssignmentStrategy orders the candidates for an appointment request, most
+// preferred first. Candidates it leaves out are never assigned. Availability
+// is checked afterwards, so strategies only express preference.
+type AssignmentStrategy interface {
+	Rank(req AssignmentRequest, candidates []VetCandidate) []VetCandidate
+}
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[string]AssignmentStrategy{
+		tenant.AutoAssignRoundRobin:  newRoundRobinStrategy(),
+		tenant.AutoAssignLeastLoaded: leastLoadedStrategy{},
+		tenant.AutoAssignSpecialty:   specialtyStrategy{},
+	}
+)
+
+// RegisterAssignmentStrategy makes a strategy selectable by name in the
+// clinic's appointment_auto_assign setting, replacing any previous one
+func RegisterAssignmentStrategy(name string, strategy AssignmentStrategy) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	strategies[name] = strategy
+}
+
+func assignmentStrategy(name string) AssignmentStrategy {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	return strategies[name]
+}
+
+// roundRobinStrategy rotates the starting vet on every request of a clinic.
+// The rotation lives in memory, so it restarts with the process and is kept
+// per instance; over time it still spreads requests evenly.
+type roundRobinStrategy struct {
+	mu   sync.Mutex
+	next map[primitive.ObjectID]int
+}
+
+func newRoundRobinStrategy() *roundRobinStrategy {
+	return &roundRobinStrategy{next: make(map[primitive.ObjectID]int)}
+}
+
+func (s *roundRobinStrategy) Rank(req AssignmentRequest, candidates []VetCandidate) []VetCandidate {
+	if len(candidates) == 0 {
+		return candidates
+	}
+	ordered := append([]VetCandidate(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID.Hex() < ordered[j].ID.Hex() })
+
+	s.mu.Lock()
+	start := s.next[req.TenantID] % len(ordered)
+	s.next[req.TenantID] = start + 1
+	s.mu.Unlock()
+
+	return append(ordered[start:], ordered[:start]...)
+}
+
+// leastLoadedStrategy prefers the vet with the fewest appointments that day
+type leastLoadedStrategy struct{}
+
+func (leastLoadedStrategy) Rank(_ AssignmentRequest, candidates []VetCandidate) []VetCandidate {
+	ordered := append([]VetCandidate(nil), candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Load < ordered[j].Load })
+	return ordered
+}
+
+// specialtyStrategy keeps the vets listing the appointment type's specialty,
+// least loaded first. Types without a specialty fall back to least loaded
+// among all vets.
+type specialtyStrategy struct{}
+
+func (specialtyStrategy) Rank(req AssignmentRequest, candidates []VetCandidate) []VetCandidate {
+	if req.Specialty == "" {
+		return leastLoadedStrategy{}.Rank(req, candidates)
+	}
+	matching := make([]VetCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		for _, sp := range c.Specialties {
+			if strings.EqualFold(strings.TrimSpace(sp), strings.TrimSpace(req.Specialty)) {
+				matching = append(matching, c)
+				break
+			}
+		}
+	}
+	return leastLoadedStrategy{}.Rank(req, matching)
+}
+
+// autoAssign picks a vet for a mobile appointment request using the clinic's
+// configured strategy. It returns the zero ID when auto-assignment is off or
+// no ranked vet is on duty and free for the slot; the request then stays
+// unassigned for the staff to handle, so failures here never block it.
+func (s *Service) autoAssign(ctx context.Context, tenantID primitive.ObjectID, apptType *AppointmentTypeConfig, at time.Time, duration int) primitive.ObjectID {
+	if s.vets == nil {
+		return primitive.NilObjectID
+	}
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, leaving request unassigned", "tenant_id", tenantID.Hex(), "error", err)
+		return primitive.NilObjectID
+	}
+	name := t.Settings.AppointmentAutoAssign
+	if name == "" || name == tenant.AutoAssignNone {
+		return primitive.NilObjectID
+	}
+	strategy := assignmentStrategy(name)
+	if strategy == nil {
+		slog.Warn("unknown auto-assignment strategy", "tenant_id", tenantID.Hex(), "strategy", name)
+		return primitive.NilObjectID
+	}
+
+	candidates, err := s.assignmentCandidates(ctx, tenantID, at)
+	if err != nil {
+		slog.Warn("failed to load vets for auto-assignment", "tenant_id", tenantID.Hex(), "error", err)
+		return primitive.NilObjectID
+	}
+
+	req := AssignmentRequest{TenantID: tenantID, ScheduledAt: at, Duration: duration}
+	if apptType != nil {
+		req.Specialty = apptType.Specialty
+	}
+	for _, c := range strategy.Rank(req, candidates) {
+		if err := s.checkOnDuty(ctx, tenantID, c.ID, at, duration); err != nil {
+			continue
+		}
+		busy, err := s.repo.CheckConflicts(ctx, c.ID, at, duration, nil, tenantID)
+		if err != nil {
+			slog.Warn("failed to check vet conflicts for auto-assignment", "tenant_id", tenantID.Hex(), "vet_id", c.ID.Hex(), "error", err)
+			continue
+		}
+		if !busy {
+			return c.ID
+		}
+	}
+	return primitive.NilObjectID
+}
+
+// assignmentCandidates lists the clinic's vets with their load on the day of at
+func (s *Service) assignmentCandidates(ctx context.Context, tenantID primitive.ObjectID, at time.Time) ([]VetCandidate, error) {
+	vets, err := s.vets.Vets(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(vets) == 0 {
+		return nil, nil
+	}
+
+	dayStart := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	dayAppointments, err := s.repo.FindByDateRange(ctx, dayStart, dayStart.AddDate(0, 0, 1), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	load := make(map[primitive.ObjectID]int)
+	for i := range dayAppointments {
+		if dayAppointments[i].IsActive() {
+			load[dayAppointments[i].VeterinarianID]++
+		}
+	}
+
+	candidates := make([]VetCandidate, 0, len(vets))
+	for _, v := range vets {
+		id, err := primitive.ObjectIDFromHex(v.ID)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, VetCandidate{ID: id, Specialties: v.Specialties, Load: load[id]})
+	}
+	return candidates, nil
+}
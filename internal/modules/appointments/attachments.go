@@ -0,0 +1,86 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// MaxAttachmentSizeBytes caps each file an owner attaches to an appointment
+const MaxAttachmentSizeBytes = 10 << 20
+
+// attachmentLimit is how many files an appointment may hold. Clinics set a
+// single attachment limit, shared with medical records; lookup failures fall
+// back to the default.
+func (s *Service) attachmentLimit(ctx context.Context, tenantID primitive.ObjectID) int {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, using default attachment limit", "tenant_id", tenantID.Hex(), "error", err)
+		return tenant.DefaultMaxRecordAttachments
+	}
+	return t.Settings.RecordAttachmentLimit()
+}
+
+// AddOwnerAttachment links a file the owner uploaded, such as a photo of a
+// wound, to one of their upcoming appointments and tells the assigned vet,
+// or the staff when no vet is assigned yet.
+func (s *Service) AddOwnerAttachment(ctx context.Context, id string, dto AddAttachmentDTO, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*OwnerAppointmentResponse, error) {
+	appointmentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment ID format")
+	}
+	if dto.SizeBytes > MaxAttachmentSizeBytes {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	appointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.OwnerID != ownerID {
+		return nil, ErrOwnerMismatch
+	}
+	if !appointment.IsActive() {
+		return nil, ErrAttachmentsClosed
+	}
+
+	limit := s.attachmentLimit(ctx, tenantID)
+	attachment := AppointmentAttachment{
+		FileID:      dto.FileID,
+		ContentType: dto.ContentType,
+		SizeBytes:   dto.SizeBytes,
+		Caption:     dto.Caption,
+		AddedBy:     ownerID,
+		AddedAt:     time.Now(),
+	}
+	added, err := s.repo.AddAttachment(ctx, appointmentID, tenantID, attachment, limit)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrTooManyAttachments(limit)
+	}
+	appointment.Attachments = append(appointment.Attachments, attachment)
+	appointment.UpdatedAt = attachment.AddedAt
+
+	body := fmt.Sprintf("El cliente adjuntó un archivo a la cita del %s", appointment.ScheduledAt.Format("02/01/2006 15:04"))
+	if dto.Caption != "" {
+		body += ": " + dto.Caption
+	}
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   appointment.VeterinarianID.Hex(),
+		TenantID: tenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    "Nuevo adjunto en una cita",
+		Body:     body,
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
+	})
+
+	return s.ownerView(ctx, appointment, appointment.ToResponse()), nil
+}
@@ -0,0 +1,172 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/consents"
+)
+
+// ConsentRegistry presents and records the signed consents of procedures
+type ConsentRegistry interface {
+	CurrentTemplate(ctx context.Context, tenantID primitive.ObjectID, procedure string) (*consents.ConsentTemplate, error)
+	ForAppointment(ctx context.Context, tenantID, appointmentID primitive.ObjectID, procedure string) (*consents.Consent, error)
+	Sign(ctx context.Context, req consents.SignRequest) (*consents.Consent, error)
+}
+
+// AppointmentConsentResponse is the consent of an appointment's procedure:
+// the text to present and, once signed, the signed consent
+type AppointmentConsentResponse struct {
+	// Required is true when the appointment cannot start until the consent is signed
+	Required bool                       `json:"required"`
+	Template *consents.TemplateResponse `json:"template,omitempty"`
+	Consent  *consents.ConsentResponse  `json:"consent,omitempty"`
+}
+
+// WithConsents enables procedure consents. Without it no consent is
+// required to start an appointment.
+func (s *Service) WithConsents(registry ConsentRegistry) *Service {
+	s.consents = registry
+	return s
+}
+
+// requiresConsent reports whether appointments of the given type need a
+// signed consent. Unlike the intake check, a failed lookup is returned rather
+// than skipped: an appointment must not start unsigned because the clinic's
+// types could not be read.
+func (s *Service) requiresConsent(ctx context.Context, appointment *Appointment) (bool, error) {
+	types, err := s.loadAppointmentTypes(ctx, appointment.TenantID)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range types {
+		if t.Key == appointment.Type {
+			return t.RequiresConsent, nil
+		}
+	}
+	return false, nil
+}
+
+// checkConsent keeps an appointment of a type that requires consent from
+// starting until the owner has signed it. Any signed version counts: a text
+// published after signing does not block a visit that is about to start.
+func (s *Service) checkConsent(ctx context.Context, appointment *Appointment) error {
+	if s.consents == nil {
+		return nil
+	}
+	required, err := s.requiresConsent(ctx, appointment)
+	if err != nil || !required {
+		return err
+	}
+	_, err = s.consents.ForAppointment(ctx, appointment.TenantID, appointment.ID, appointment.Type)
+	if errors.Is(err, consents.ErrConsentNotFound) {
+		return ErrConsentRequired
+	}
+	return err
+}
+
+// GetConsent returns the consent of an appointment for the clinic to present
+func (s *Service) GetConsent(ctx context.Context, id string, tenantID primitive.ObjectID) (*AppointmentConsentResponse, error) {
+	appointment, err := s.consentAppointment(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.appointmentConsent(ctx, appointment)
+}
+
+// SignConsent records a consent the owner signed at the clinic, captured by
+// the staff member userID
+func (s *Service) SignConsent(ctx context.Context, id string, dto consents.SignConsentDTO, tenantID, userID primitive.ObjectID) (*AppointmentConsentResponse, error) {
+	appointment, err := s.consentAppointment(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.signConsent(ctx, appointment, dto, consents.ChannelClinic, &userID)
+}
+
+// GetOwnerConsent returns the consent of one of the owner's appointments
+func (s *Service) GetOwnerConsent(ctx context.Context, id string, tenantID, ownerID primitive.ObjectID) (*AppointmentConsentResponse, error) {
+	appointment, err := s.consentAppointment(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.OwnerID != ownerID {
+		return nil, ErrOwnerMismatch
+	}
+	return s.appointmentConsent(ctx, appointment)
+}
+
+// SignOwnerConsent records a consent the owner signed in the app
+func (s *Service) SignOwnerConsent(ctx context.Context, id string, dto consents.SignConsentDTO, tenantID, ownerID primitive.ObjectID) (*AppointmentConsentResponse, error) {
+	appointment, err := s.consentAppointment(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.OwnerID != ownerID {
+		return nil, ErrOwnerMismatch
+	}
+	return s.signConsent(ctx, appointment, dto, consents.ChannelMobile, nil)
+}
+
+func (s *Service) consentAppointment(ctx context.Context, id string, tenantID primitive.ObjectID) (*Appointment, error) {
+	appointmentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment ID format")
+	}
+	if s.consents == nil {
+		return nil, consents.ErrTemplateNotFound
+	}
+	return s.repo.FindByID(ctx, appointmentID, tenantID)
+}
+
+// appointmentConsent presents the current text of the appointment's
+// procedure together with the consent signed for it, if any
+func (s *Service) appointmentConsent(ctx context.Context, appointment *Appointment) (*AppointmentConsentResponse, error) {
+	required, err := s.requiresConsent(ctx, appointment)
+	if err != nil {
+		return nil, err
+	}
+	resp := &AppointmentConsentResponse{Required: required}
+
+	template, err := s.consents.CurrentTemplate(ctx, appointment.TenantID, appointment.Type)
+	switch {
+	case err == nil:
+		t := template.ToResponse()
+		resp.Template = &t
+	case !errors.Is(err, consents.ErrTemplateNotFound):
+		return nil, err
+	}
+
+	consent, err := s.consents.ForAppointment(ctx, appointment.TenantID, appointment.ID, appointment.Type)
+	switch {
+	case err == nil:
+		c := consent.ToResponse()
+		resp.Consent = &c
+	case !errors.Is(err, consents.ErrConsentNotFound):
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Service) signConsent(ctx context.Context, appointment *Appointment, dto consents.SignConsentDTO, channel string, recordedBy *primitive.ObjectID) (*AppointmentConsentResponse, error) {
+	if !appointment.IsActive() {
+		return nil, ErrConsentClosed
+	}
+
+	_, err := s.consents.Sign(ctx, consents.SignRequest{
+		TenantID:      appointment.TenantID,
+		PatientID:     appointment.PatientID,
+		OwnerID:       appointment.OwnerID,
+		AppointmentID: appointment.ID,
+		Procedure:     appointment.Type,
+		Channel:       channel,
+		RecordedBy:    recordedBy,
+		Form:          dto,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.appointmentConsent(ctx, appointment)
+}
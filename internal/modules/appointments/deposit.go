@@ -0,0 +1,235 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/platform/payment"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// PaymentLinkCreator creates hosted checkouts, implemented by payment.PaymentManager
+type PaymentLinkCreator interface {
+	CreatePaymentLink(ctx context.Context, req *payment.PaymentLinkRequest, providerType *payment.ProviderType) (*payment.PaymentLinkResponse, error)
+}
+
+// defaultDepositExpiry is how long an owner has to pay when the clinic does not say
+const defaultDepositExpiry = 60 * time.Minute
+
+// depositReferencePrefix tells deposit payments apart from invoice payments,
+// whose reference is the bare invoice ID
+const depositReferencePrefix = "deposit-"
+
+// prepareDeposit puts the appointment on hold behind a payment link when the
+// clinic requires a deposit for its type; nextStatus is where it goes once
+// paid. It must run before the appointment is inserted, since it assigns the
+// ID used as payment reference. Types without a deposit are left untouched.
+// The deposit of the type itself takes precedence over the older per-type
+// map in the tenant settings.
+func (s *Service) prepareDeposit(ctx context.Context, appointment *Appointment, apptType *AppointmentTypeConfig, nextStatus string) error {
+	if s.payments == nil {
+		return nil
+	}
+
+	t, err := s.tenantRepo.FindByID(ctx, appointment.TenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, booking without deposit", "tenant_id", appointment.TenantID.Hex(), "error", err)
+		return nil
+	}
+	rule, ok := depositRule(t, appointment.Type, apptType)
+	if !ok {
+		return nil
+	}
+	if t.Currency == "" {
+		return ErrDepositCurrencyNotDefined
+	}
+	currency := depositCurrency(t, rule)
+
+	expiresIn := time.Duration(rule.ExpiresAfterMinutes) * time.Minute
+	if expiresIn <= 0 {
+		expiresIn = defaultDepositExpiry
+	}
+
+	var customerEmail string
+	if owner, err := s.ownerRepo.FindByID(ctx, appointment.OwnerID.Hex()); err == nil && owner != nil {
+		customerEmail = owner.Email
+	}
+
+	var providerType *payment.ProviderType
+	if t.Settings.PaymentProvider != "" {
+		p := payment.ProviderType(t.Settings.PaymentProvider)
+		providerType = &p
+	}
+
+	appointment.ID = primitive.NewObjectID()
+	link, err := s.payments.CreatePaymentLink(ctx, &payment.PaymentLinkRequest{
+		TenantID:      appointment.TenantID.Hex(),
+		Reference:     depositReferencePrefix + appointment.ID.Hex(),
+		Description:   fmt.Sprintf("Anticipo cita %s - %s", appointment.ScheduledAt.Format("02/01/2006 15:04"), t.Name),
+		CustomerEmail: customerEmail,
+		Amount:        rule.Amount,
+		Currency:      currency,
+	}, providerType)
+	if err != nil {
+		slog.Error("failed to create deposit payment link", "tenant_id", appointment.TenantID.Hex(), "type", appointment.Type, "error", err)
+		return ErrDepositLinkFailed
+	}
+
+	appointment.Status = AppointmentStatusAwaitingDeposit
+	appointment.ConfirmedAt = nil
+	appointment.Deposit = &AppointmentDeposit{
+		Amount:     rule.Amount,
+		Currency:   currency,
+		Provider:   string(link.Provider),
+		LinkID:     link.LinkID,
+		URL:        link.URL,
+		NextStatus: nextStatus,
+		ExpiresAt:  time.Now().Add(expiresIn),
+	}
+	return nil
+}
+
+// depositRule returns the deposit the clinic asks for typeKey, if any. The
+// deposit of the type itself takes precedence over the tenant settings map.
+func depositRule(t *tenant.Tenant, typeKey string, apptType *AppointmentTypeConfig) (tenant.AppointmentDeposit, bool) {
+	rule, ok := t.Settings.AppointmentDeposits[typeKey]
+	if apptType != nil && apptType.Deposit != nil {
+		rule, ok = *apptType.Deposit, true
+	}
+	return rule, ok && rule.Amount > 0
+}
+
+// depositCurrency is the currency a deposit rule's amount was configured in.
+// Its minor units are charged as is, even if the clinic has since changed
+// currency.
+func depositCurrency(t *tenant.Tenant, rule tenant.AppointmentDeposit) string {
+	if rule.Currency != "" {
+		return rule.Currency
+	}
+	return t.Currency
+}
+
+// notifyDepositDue sends the owner the payment link of a held appointment
+func (s *Service) notifyDepositDue(ctx context.Context, appointment *Appointment, patientName string) {
+	d := appointment.Deposit
+	s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		OwnerID:  appointment.OwnerID.Hex(),
+		TenantID: appointment.TenantID.Hex(),
+		Type:     notifications.TypeAppointmentReminder,
+		Template: notifications.TemplateAppointmentDepositDue,
+		Vars: map[string]string{
+			"patient_name": patientName,
+			"amount":       money.Format(d.Amount, d.Currency),
+		},
+		Times: map[string]time.Time{"date": appointment.ScheduledAt, "expires_at": d.ExpiresAt},
+		Data: map[string]string{
+			"appointment_id": appointment.ID.Hex(),
+			"payment_url":    d.URL,
+			"action":         "pay_deposit",
+		},
+		SendPush: true,
+	})
+}
+
+// ApplyDepositPayment books the appointment a successful payment webhook
+// refers to. It reports whether the event belonged to a deposit at all, so the
+// caller can fall back to invoice or subscription handling otherwise.
+func (s *Service) ApplyDepositPayment(ctx context.Context, event *payment.WebhookEvent) (bool, error) {
+	appointment := s.findAppointmentForDeposit(ctx, event)
+	if appointment == nil || appointment.Deposit == nil {
+		return false, nil
+	}
+
+	if appointment.Deposit.PaidAt != nil {
+		slog.Info("appointment deposit already recorded", "appointment_id", appointment.ID.Hex(), "transaction_id", event.TransactionID)
+		return true, nil
+	}
+	if appointment.Status != AppointmentStatusAwaitingDeposit {
+		s.warnLateDeposit(ctx, appointment, event)
+		return true, nil
+	}
+
+	now := time.Now()
+	next := appointment.Deposit.NextStatus
+	if next == "" {
+		next = AppointmentStatusScheduled
+	}
+	updates := bson.M{
+		"status":                 next,
+		"deposit.paid_at":        now,
+		"deposit.transaction_id": event.TransactionID,
+	}
+	if next == AppointmentStatusConfirmed {
+		updates["confirmed_at"] = now
+	}
+
+	applied, err := s.repo.UpdateIfStatus(ctx, appointment.ID, appointment.TenantID, AppointmentStatusAwaitingDeposit, updates)
+	if err != nil {
+		return true, err
+	}
+	if !applied {
+		// The expiry job released it between the lookup and the update
+		s.warnLateDeposit(ctx, appointment, event)
+		return true, nil
+	}
+
+	s.repo.CreateStatusTransition(ctx, &AppointmentStatusTransition{
+		TenantID:      appointment.TenantID,
+		AppointmentID: appointment.ID,
+		FromStatus:    AppointmentStatusAwaitingDeposit,
+		ToStatus:      next,
+		ChangedBy:     primitive.NilObjectID,
+		Reason:        "Anticipo pagado",
+		CreatedAt:     now,
+	})
+
+	s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		OwnerID:  appointment.OwnerID.Hex(),
+		TenantID: appointment.TenantID.Hex(),
+		Type:     notifications.TypeAppointmentConfirmed,
+		Template: notifications.TemplateAppointmentDepositPaid,
+		Times:    map[string]time.Time{"date": appointment.ScheduledAt},
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
+		SendPush: true,
+	})
+	return true, nil
+}
+
+// warnLateDeposit alerts the clinic to a deposit collected for an appointment
+// that was already released, so it can be refunded or rebooked by hand.
+func (s *Service) warnLateDeposit(ctx context.Context, appointment *Appointment, event *payment.WebhookEvent) {
+	slog.Warn("deposit paid for appointment no longer awaiting it", "appointment_id", appointment.ID.Hex(), "status", appointment.Status, "transaction_id", event.TransactionID)
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   primitive.NilObjectID.Hex(),
+		TenantID: appointment.TenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    "Anticipo recibido fuera de plazo",
+		Body:     fmt.Sprintf("Se recibió el anticipo de la cita del %s, que ya no estaba en espera de pago", appointment.ScheduledAt.Format("02/01/2006 15:04")),
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex(), "transaction_id": event.TransactionID},
+	})
+}
+
+func (s *Service) findAppointmentForDeposit(ctx context.Context, event *payment.WebhookEvent) *Appointment {
+	// Stripe echoes the reference; Wompi only the payment link ID
+	if hex, ok := strings.CutPrefix(event.Reference, depositReferencePrefix); ok {
+		if id, err := primitive.ObjectIDFromHex(hex); err == nil {
+			if appointment, err := s.repo.FindForDeposit(ctx, id); err == nil {
+				return appointment
+			}
+		}
+	}
+	if event.PaymentLinkID != "" {
+		if appointment, err := s.repo.FindByDepositLinkID(ctx, event.PaymentLinkID); err == nil {
+			return appointment
+		}
+	}
+	return nil
+}
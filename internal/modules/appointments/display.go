@@ -0,0 +1,118 @@
+package appointments
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// DisplayResponse tells the calendar how to render an appointment
+type DisplayResponse struct {
+	Color string `json:"color" example:"#3B82F6"`
+	Label string `json:"label" example:"Consulta"`
+}
+
+// Built-in calendar styles, used for any key the clinic has not customized.
+var (
+	defaultTypeStyles = map[string]tenant.CalendarStyle{
+		AppointmentTypeConsultation: {Color: "#3B82F6", Label: "Consulta"},
+		AppointmentTypeSurgery:      {Color: "#8B5CF6", Label: "Cirugía"},
+		AppointmentTypeVaccination:  {Color: "#10B981", Label: "Vacunación"},
+		AppointmentTypeEmergency:    {Color: "#EF4444", Label: "Emergencia"},
+		AppointmentTypeCheckup:      {Color: "#14B8A6", Label: "Control"},
+		AppointmentTypeGrooming:     {Color: "#EC4899", Label: "Peluquería"},
+	}
+
+	// Only urgent priorities recolor an appointment by default
+	defaultPriorityStyles = map[string]tenant.CalendarStyle{
+		AppointmentPriorityHigh:      {Color: "#F97316"},
+		AppointmentPriorityEmergency: {Color: "#DC2626"},
+		PriorityTriage:               {Color: "#EAB308"},
+	}
+
+	// Closed appointments are greyed out whatever their type or priority
+	defaultStatusStyles = map[string]tenant.CalendarStyle{
+		AppointmentStatusCancelled: {Color: "#9CA3AF", Label: "Cancelada"},
+		AppointmentStatusNoShow:    {Color: "#6B7280", Label: "No asistió"},
+	}
+)
+
+const fallbackDisplayColor = "#64748B"
+
+// calendarStyle merges the clinic's style for key over the built-in one,
+// field by field, so a clinic can rename a type without picking a color.
+func calendarStyle(custom, defaults map[string]tenant.CalendarStyle, key string) tenant.CalendarStyle {
+	style := defaults[key]
+	if c, ok := custom[key]; ok {
+		if c.Color != "" {
+			style.Color = c.Color
+		}
+		if c.Label != "" {
+			style.Label = c.Label
+		}
+	}
+	return style
+}
+
+// resolveDisplay picks the color by precedence status > priority > type, and
+// the label from the status when it restyles the appointment, else the type.
+func resolveDisplay(settings tenant.CalendarSettings, appointmentType, status, priority string) *DisplayResponse {
+	typeStyle := calendarStyle(settings.Types, defaultTypeStyles, appointmentType)
+	display := &DisplayResponse{Color: typeStyle.Color, Label: typeStyle.Label}
+	if display.Label == "" {
+		display.Label = appointmentType
+	}
+
+	if p := calendarStyle(settings.Priorities, defaultPriorityStyles, priority); p.Color != "" {
+		display.Color = p.Color
+	}
+
+	st := calendarStyle(settings.Statuses, defaultStatusStyles, status)
+	if st.Color != "" {
+		display.Color = st.Color
+	}
+	if st.Label != "" {
+		display.Label = st.Label
+	}
+
+	if display.Color == "" {
+		display.Color = fallbackDisplayColor
+	}
+	return display
+}
+
+// calendarSettings loads the clinic's calendar styles, with the name and
+// color of its appointment types as the base style of each type. Explicit
+// calendar overrides still win. Tenant lookup failures fall back to the
+// built-in styles.
+func (s *Service) calendarSettings(ctx context.Context, tenantID primitive.ObjectID) tenant.CalendarSettings {
+	var settings tenant.CalendarSettings
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, using default calendar styles", "tenant_id", tenantID.Hex(), "error", err)
+	} else {
+		settings = t.Settings.Calendar
+	}
+
+	typeStyles := make(map[string]tenant.CalendarStyle, len(settings.Types))
+	for key, style := range settings.Types {
+		typeStyles[key] = style
+	}
+	for _, apptType := range s.appointmentTypes(ctx, tenantID) {
+		base := map[string]tenant.CalendarStyle{apptType.Key: {Color: apptType.Color, Label: apptType.Name}}
+		typeStyles[apptType.Key] = calendarStyle(settings.Types, base, apptType.Key)
+	}
+	settings.Types = typeStyles
+	return settings
+}
+
+// applyDisplay re-resolves the display of responses with the clinic's styles.
+func applyDisplay(settings tenant.CalendarSettings, responses []AppointmentResponse) {
+	for i := range responses {
+		r := &responses[i]
+		r.Display = resolveDisplay(settings, r.Type, r.Status, calendarPriority(r.Priority, r.RequestedPriority))
+	}
+}
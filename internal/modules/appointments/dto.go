@@ -3,38 +3,75 @@ package appointments
 import (
 	"time"
 
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/shared/money"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Input DTOs
 
-// CreateAppointmentDTO defines the structure for creating appointments
+// CreateAppointmentDTO defines the structure for creating appointments. Type
+// must be one of the clinic's appointment types; Duration defaults to the
+// type's and VeterinarianID may be empty for types that do not require a vet.
 type CreateAppointmentDTO struct {
 	PatientID      string    `json:"patient_id" binding:"required" example:"507f1f77bcf86cd799439011"`
-	VeterinarianID string    `json:"veterinarian_id" binding:"required" example:"507f1f77bcf86cd799439012"`
+	VeterinarianID string    `json:"veterinarian_id" example:"507f1f77bcf86cd799439012"`
 	ScheduledAt    time.Time `json:"scheduled_at" binding:"required" example:"2024-01-15T10:30:00Z"`
-	Duration       int       `json:"duration" binding:"required,min=15,max=480" example:"30"`
-	Type           string    `json:"type" binding:"required,oneof=consultation surgery vaccination emergency checkup grooming" example:"consultation"`
+	Duration       int       `json:"duration" binding:"omitempty,min=15,max=480" example:"30"`
+	Type           string    `json:"type" binding:"required,max=50" example:"consultation"`
 	Priority       string    `json:"priority" binding:"omitempty,oneof=low normal high emergency" example:"normal"`
 	Reason         string    `json:"reason" binding:"required,max=500" example:"Annual checkup"`
 	Notes          string    `json:"notes" binding:"omitempty,max=1000" example:"First visit for this patient"`
+	// DisableReminders skips the reminder pushes for this appointment only
+	DisableReminders bool `json:"disable_reminders" example:"false"`
+	// RoomID reserves a room or equipment; required when the type sets a room kind
+	RoomID string `json:"room_id" example:"507f1f77bcf86cd799439015"`
+	// OwnerModifiable overrides whether the owner may cancel or reschedule this
+	// appointment from the app; when omitted the appointment type decides
+	OwnerModifiable *bool `json:"owner_modifiable" example:"false"`
 }
 
 // UpdateAppointmentDTO defines the structure for updating appointments
 type UpdateAppointmentDTO struct {
 	ScheduledAt *time.Time `json:"scheduled_at" binding:"omitempty" example:"2024-01-15T11:00:00Z"`
 	Duration    *int       `json:"duration" binding:"omitempty,min=15,max=480" example:"45"`
-	Type        *string    `json:"type" binding:"omitempty,oneof=consultation surgery vaccination emergency checkup grooming" example:"surgery"`
+	Type        *string    `json:"type" binding:"omitempty,max=50" example:"surgery"`
 	Priority    *string    `json:"priority" binding:"omitempty,oneof=low normal high emergency" example:"high"`
 	Reason      *string    `json:"reason" binding:"omitempty,max=500" example:"Updated reason"`
 	Notes       *string    `json:"notes" binding:"omitempty,max=1000" example:"Updated notes"`
+	// DisableReminders turns the reminder pushes for this appointment off or back on
+	DisableReminders *bool `json:"disable_reminders" example:"true"`
+	// RoomID moves the appointment to another room; an empty string releases it
+	RoomID *string `json:"room_id" example:"507f1f77bcf86cd799439015"`
+	// OwnerModifiable overrides whether the owner may cancel or reschedule this
+	// appointment from the app
+	OwnerModifiable *bool `json:"owner_modifiable" example:"false"`
 }
 
-// UpdateStatusDTO defines the structure for updating appointment status
+// UpdateStatusDTO defines the structure for updating appointment status.
+// Status is checked against the clinic's workflow, which may add custom statuses.
 type UpdateStatusDTO struct {
-	Status string `json:"status" binding:"required,oneof=scheduled confirmed in_progress completed cancelled no_show" example:"confirmed"`
+	Status string `json:"status" binding:"required,max=50" example:"confirmed"`
 	Reason string `json:"reason" binding:"omitempty,max=200" example:"Patient confirmed by phone"`
+	// ShiftStart, when starting a late appointment, moves its effective start to
+	// now so it keeps its full duration, within the clinic's late tolerance
+	ShiftStart bool `json:"shift_start" example:"true"`
+	// Weight, in kg, optionally taken at check-in or when completing the visit
+	Weight float64 `json:"weight,omitempty" binding:"omitempty,gt=0,max=1500" example:"12.4"`
+}
+
+// ReassignAppointmentDTO defines the structure for handing an appointment over to another vet
+type ReassignAppointmentDTO struct {
+	VeterinarianID string `json:"veterinarian_id" binding:"required" example:"507f1f77bcf86cd799439013"`
+	Reason         string `json:"reason" binding:"required,max=200" example:"Fin de turno del veterinario"`
+}
+
+// OffboardVeterinarianDTO defines the structure for offboarding a departing vet.
+// Without a replacement their appointments return to the request queue.
+type OffboardVeterinarianDTO struct {
+	ReplacementVeterinarianID string `json:"replacement_veterinarian_id" example:"507f1f77bcf86cd799439013"`
+	Reason                    string `json:"reason" binding:"required,max=200" example:"Renuncia"`
 }
 
 // AppointmentCancelDTO defines the structure for cancelling an appointment
@@ -42,18 +79,187 @@ type AppointmentCancelDTO struct {
 	Reason string `json:"reason" binding:"required,max=200" example:"Ya no necesito la cita"`
 }
 
+// OwnerRescheduleDTO defines the structure for an owner moving an appointment
+type OwnerRescheduleDTO struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required" example:"2024-01-16T15:00:00Z"`
+	Reason      string    `json:"reason" binding:"omitempty,max=200" example:"Tengo un viaje ese día"`
+}
+
+// AddAttachmentDTO links an uploaded file to an appointment from mobile
+type AddAttachmentDTO struct {
+	FileID      string `json:"file_id" binding:"required,max=200" example:"uploads/2024/01/herida.jpg"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/webp image/heic" example:"image/jpeg"`
+	SizeBytes   int64  `json:"size_bytes" binding:"required,min=1" example:"245760"`
+	Caption     string `json:"caption" binding:"omitempty,max=200" example:"Herida en la pata trasera"`
+}
+
+// RescheduleDecisionDTO defines the structure for approving or declining an
+// owner's reschedule request
+type RescheduleDecisionDTO struct {
+	Approve *bool  `json:"approve" binding:"required" example:"true"`
+	Reason  string `json:"reason" binding:"omitempty,max=200" example:"Sin disponibilidad a esa hora"`
+}
+
+// DeleteAppointmentDTO defines the query options for deleting an appointment.
+// Force and Reason are only needed when medical records reference it.
+type DeleteAppointmentDTO struct {
+	Force  bool   `form:"force" example:"true"`
+	Reason string `form:"reason" binding:"omitempty,max=500" example:"Cita duplicada por error"`
+}
+
 // MobileAppointmentRequestDTO defines the structure for mobile appointment requests
 type MobileAppointmentRequestDTO struct {
 	PatientID   string    `json:"patient_id" binding:"required" example:"507f1f77bcf86cd799439011"`
 	ScheduledAt time.Time `json:"scheduled_at" binding:"required" example:"2024-01-15T10:30:00Z"`
-	Type        string    `json:"type" binding:"required,oneof=consultation surgery vaccination emergency checkup grooming" example:"consultation"`
+	Type        string    `json:"type" binding:"required,max=50" example:"consultation"`
 	Priority    string    `json:"priority" binding:"omitempty,oneof=low normal high emergency" example:"normal"`
 	Reason      string    `json:"reason" binding:"required,max=500" example:"My pet is not feeling well"`
 	OwnerNotes  string    `json:"owner_notes" binding:"omitempty,max=1000" example:"Additional information"`
+	// DisableReminders skips the reminder pushes for this appointment only
+	DisableReminders bool `json:"disable_reminders" example:"false"`
+}
+
+// CreateAppointmentTypeDTO defines a clinic appointment type. Key is what
+// appointments store as their type and cannot be changed later.
+type CreateAppointmentTypeDTO struct {
+	Key             string                        `json:"key" binding:"required,max=50" example:"bath"`
+	Name            string                        `json:"name" binding:"required,max=100" example:"Baño"`
+	DefaultDuration int                           `json:"default_duration" binding:"required,min=15,max=480" example:"45"`
+	Color           string                        `json:"color" binding:"omitempty,hexcolor" example:"#EC4899"`
+	RequiresVet     *bool                         `json:"requires_vet" example:"false"`
+	Specialty       string                        `json:"specialty" binding:"omitempty,max=50" example:"Cirugía"`
+	RoomKind        string                        `json:"room_kind" binding:"omitempty,max=50" example:"surgery"`
+	Deposit         *tenant.AppointmentDepositDTO `json:"deposit,omitempty"`
+	// Fee is billed on the invoice drafted when an appointment of this type completes
+	Fee float64 `json:"fee" binding:"min=0" example:"45000"`
+	// MinNoticeHours is how far ahead this type must be booked, e.g. 48 for surgery
+	MinNoticeHours int `json:"min_notice_hours" binding:"min=0,max=720" example:"48"`
+	// OwnerLocked keeps owners from cancelling or rescheduling this type from the app
+	OwnerLocked bool `json:"owner_locked" example:"false"`
+	// RequiresConsent blocks starting the appointment until the owner signs the consent
+	RequiresConsent bool `json:"requires_consent" example:"true"`
+}
+
+// UpdateAppointmentTypeDTO changes a clinic appointment type. A deposit with
+// amount 0 stops requiring one.
+type UpdateAppointmentTypeDTO struct {
+	Name            *string                       `json:"name" binding:"omitempty,max=100" example:"Baño y corte"`
+	DefaultDuration *int                          `json:"default_duration" binding:"omitempty,min=15,max=480" example:"60"`
+	Color           *string                       `json:"color" binding:"omitempty,hexcolor" example:"#EC4899"`
+	RequiresVet     *bool                         `json:"requires_vet" example:"false"`
+	Specialty       *string                       `json:"specialty" binding:"omitempty,max=50" example:"Cirugía"`
+	RoomKind        *string                       `json:"room_kind" binding:"omitempty,max=50" example:"surgery"`
+	Active          *bool                         `json:"active" example:"true"`
+	Deposit         *tenant.AppointmentDepositDTO `json:"deposit,omitempty"`
+	OwnerLocked     *bool                         `json:"owner_locked" example:"true"`
+	Fee             *float64                      `json:"fee" binding:"omitempty,min=0" example:"50000"`
+	MinNoticeHours  *int                          `json:"min_notice_hours" binding:"omitempty,min=0,max=720" example:"48"`
+	RequiresConsent *bool                         `json:"requires_consent" example:"true"`
+}
+
+// StatusConfigDTO is a status of a clinic's workflow
+type StatusConfigDTO struct {
+	Key   string `json:"key" binding:"required,max=50" example:"awaiting_labs"`
+	Label string `json:"label" binding:"required,max=100" example:"Esperando laboratorio"`
+	// ActsAs runs the side effects of a built-in status when entering a custom one
+	ActsAs string `json:"acts_as" binding:"omitempty,oneof=confirmed in_progress" example:"in_progress"`
+}
+
+// UpdateStatusWorkflowDTO replaces a clinic's statuses and allowed transitions.
+// The built-in statuses must be kept.
+type UpdateStatusWorkflowDTO struct {
+	Statuses    []StatusConfigDTO   `json:"statuses" binding:"required,min=1,dive"`
+	Transitions map[string][]string `json:"transitions" binding:"required"`
 }
 
 // Response DTOs
 
+// StatusConfigResponse is a status of a clinic's workflow
+type StatusConfigResponse struct {
+	Key    string `json:"key" example:"awaiting_labs"`
+	Label  string `json:"label" example:"Esperando laboratorio"`
+	ActsAs string `json:"acts_as,omitempty" example:"in_progress"`
+	// Builtin statuses cannot be removed
+	Builtin bool `json:"builtin" example:"false"`
+}
+
+// StatusWorkflowResponse is a clinic's statuses and allowed transitions
+type StatusWorkflowResponse struct {
+	Statuses    []StatusConfigResponse `json:"statuses"`
+	Transitions map[string][]string    `json:"transitions"`
+	// Custom is false while the clinic uses the built-in workflow
+	Custom    bool       `json:"custom" example:"true"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ToResponse converts a workflow to its response
+func (w *AppointmentWorkflow) ToResponse() *StatusWorkflowResponse {
+	resp := &StatusWorkflowResponse{
+		Statuses:    make([]StatusConfigResponse, len(w.Statuses)),
+		Transitions: map[string][]string{},
+		Custom:      !w.UpdatedAt.IsZero(),
+	}
+	for i, st := range w.Statuses {
+		_, builtin := builtinStatusLabels[st.Key]
+		resp.Statuses[i] = StatusConfigResponse{Key: st.Key, Label: st.Label, ActsAs: st.ActsAs, Builtin: builtin}
+	}
+	for _, st := range w.Statuses {
+		resp.Transitions[st.Key] = append([]string{}, w.Transitions[st.Key]...)
+	}
+	if resp.Custom {
+		updatedAt := w.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+// AppointmentTypeResponse is a clinic appointment type. ID is empty for the
+// built-in types of a clinic that has not stored its own list yet.
+type AppointmentTypeResponse struct {
+	ID              string                             `json:"id,omitempty" example:"507f1f77bcf86cd799439015"`
+	Key             string                             `json:"key" example:"consultation"`
+	Name            string                             `json:"name" example:"Consulta"`
+	DefaultDuration int                                `json:"default_duration" example:"30"`
+	Color           string                             `json:"color,omitempty" example:"#3B82F6"`
+	RequiresVet     bool                               `json:"requires_vet" example:"true"`
+	Specialty       string                             `json:"specialty,omitempty" example:"Cirugía"`
+	RoomKind        string                             `json:"room_kind,omitempty" example:"surgery"`
+	Deposit         *tenant.AppointmentDepositResponse `json:"deposit,omitempty"`
+	Fee             float64                            `json:"fee,omitempty" example:"45000"`
+	MinNoticeHours  int                                `json:"min_notice_hours,omitempty" example:"48"`
+	Active          bool                               `json:"active" example:"true"`
+	// OwnerLocked is true when owners must call the clinic to change these appointments
+	OwnerLocked bool `json:"owner_locked" example:"false"`
+	// RequiresConsent is true when the owner must sign a consent before the visit starts
+	RequiresConsent bool `json:"requires_consent" example:"false"`
+}
+
+// ToResponse converts an appointment type to its response
+func (t *AppointmentTypeConfig) ToResponse() AppointmentTypeResponse {
+	resp := AppointmentTypeResponse{
+		Key:             t.Key,
+		Name:            t.Name,
+		DefaultDuration: t.DefaultDuration,
+		Color:           t.Color,
+		RequiresVet:     t.RequiresVet,
+		Specialty:       t.Specialty,
+		RoomKind:        t.RoomKind,
+		Fee:             t.Fee,
+		MinNoticeHours:  t.MinNoticeHours,
+		Active:          t.Active,
+		OwnerLocked:     t.OwnerLocked,
+		RequiresConsent: t.RequiresConsent,
+	}
+	if t.Deposit != nil {
+		deposit := t.Deposit.ToResponse()
+		resp.Deposit = &deposit
+	}
+	if !t.ID.IsZero() {
+		resp.ID = t.ID.Hex()
+	}
+	return resp
+}
+
 // PatientSummary provides a summary of patient details
 type PatientSummary struct {
 	ID      string `json:"id"`
@@ -97,8 +303,35 @@ type AppointmentResponse struct {
 	CompletedAt    *time.Time `json:"completed_at,omitempty" example:"2024-01-15T11:05:00Z"`
 	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
 	CancelReason   string     `json:"cancel_reason,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
 	CreatedAt      time.Time  `json:"created_at" example:"2024-01-10T14:20:00Z"`
 	UpdatedAt      time.Time  `json:"updated_at" example:"2024-01-14T15:00:00Z"`
+	// OriginalVeterinarianID is set once the appointment has been reassigned
+	OriginalVeterinarianID string `json:"original_veterinarian_id,omitempty"`
+	// RoomID is the room or equipment the appointment reserves
+	RoomID string `json:"room_id,omitempty"`
+	// Display is the resolved calendar color and label
+	Display *DisplayResponse `json:"display,omitempty"`
+	// Deposit is present when the appointment type requires a prepayment
+	Deposit *DepositResponse `json:"deposit,omitempty"`
+	// EffectiveStartAt is set when the start was shifted for a late arrival
+	EffectiveStartAt *time.Time `json:"effective_start_at,omitempty"`
+	// RequestedPriority is the priority an owner asked for above what owners
+	// may set, present until staff triage the appointment
+	RequestedPriority string `json:"requested_priority,omitempty" example:"emergency"`
+	// Warnings are issues staff should act on; the operation itself succeeded
+	Warnings []AppointmentWarning `json:"warnings,omitempty"`
+	// DisableReminders is true when the appointment is opted out of reminders
+	DisableReminders bool `json:"disable_reminders"`
+	// OwnerModifiable tells the owner app whether to offer cancel and
+	// reschedule; it is only set on the owner endpoints
+	OwnerModifiable *bool `json:"owner_modifiable,omitempty"`
+	// RescheduleRequest is the owner's pending request to move the appointment
+	RescheduleRequest *RescheduleRequestResponse `json:"reschedule_request,omitempty"`
+	// Attachments are the files the owner sent ahead of the visit
+	Attachments []AttachmentResponse `json:"attachments,omitempty"`
+	// FollowUpOf is the medical record that booked this appointment as its next visit
+	FollowUpOf string `json:"follow_up_of,omitempty"`
 
 	// Populated data (will be filled when populate=true)
 	Patient      *PatientSummary      `json:"patient,omitempty"`
@@ -106,6 +339,89 @@ type AppointmentResponse struct {
 	Veterinarian *VeterinarianSummary `json:"veterinarian,omitempty"`
 }
 
+// OwnerAppointmentResponse is the owner app's view of an appointment. It
+// leaves out staff-only data such as internal notes, staff cancel reasons,
+// room and reassignment details, warnings and the owner and vet contacts.
+type OwnerAppointmentResponse struct {
+	ID             string     `json:"id" example:"507f1f77bcf86cd799439011"`
+	PatientID      string     `json:"patient_id" example:"507f1f77bcf86cd799439012"`
+	VeterinarianID string     `json:"veterinarian_id" example:"507f1f77bcf86cd799439014"`
+	ScheduledAt    time.Time  `json:"scheduled_at" example:"2024-01-15T10:30:00Z"`
+	Duration       int        `json:"duration" example:"30"`
+	Type           string     `json:"type" example:"consultation"`
+	Status         string     `json:"status" example:"scheduled"`
+	Priority       string     `json:"priority" example:"normal"`
+	Reason         string     `json:"reason" example:"Annual checkup"`
+	OwnerNotes     string     `json:"owner_notes,omitempty" example:"Patient anxious"`
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty" example:"2024-01-14T15:00:00Z"`
+	StartedAt      *time.Time `json:"started_at,omitempty" example:"2024-01-15T10:35:00Z"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty" example:"2024-01-15T11:05:00Z"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+	// CancelReason is only present when the owner cancelled
+	CancelReason   string     `json:"cancel_reason,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-01-10T14:20:00Z"`
+	UpdatedAt      time.Time  `json:"updated_at" example:"2024-01-14T15:00:00Z"`
+	// RequestedPriority is the priority the owner asked for, until staff triage it
+	RequestedPriority string                     `json:"requested_priority,omitempty" example:"emergency"`
+	Display           *DisplayResponse           `json:"display,omitempty"`
+	Deposit           *DepositResponse           `json:"deposit,omitempty"`
+	OwnerModifiable   *bool                      `json:"owner_modifiable,omitempty"`
+	RescheduleRequest *RescheduleRequestResponse `json:"reschedule_request,omitempty"`
+	Attachments       []AttachmentResponse       `json:"attachments,omitempty"`
+
+	// Populated data (will be filled when populate=true)
+	Patient      *PatientSummary  `json:"patient,omitempty"`
+	Veterinarian *OwnerVetSummary `json:"veterinarian,omitempty"`
+}
+
+// OwnerVetSummary names the appointment's vet without their contact details
+type OwnerVetSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PaginatedOwnerAppointmentsResponse is a page of an owner's appointments
+type PaginatedOwnerAppointmentsResponse struct {
+	Data       []OwnerAppointmentResponse `json:"data"`
+	Pagination pagination.PaginationInfo  `json:"pagination"`
+}
+
+// AppointmentWarning flags a side effect of an accepted change, such as a
+// shifted start that now runs into the vet's next appointment
+type AppointmentWarning struct {
+	Code              string `json:"code" example:"OVERRUNS_NEXT_APPOINTMENT"`
+	Message           string `json:"message"`
+	NextAppointmentID string `json:"next_appointment_id,omitempty"`
+	OverlapMinutes    int    `json:"overlap_minutes,omitempty" example:"10"`
+}
+
+// RescheduleRequestResponse describes an owner's pending reschedule request
+type RescheduleRequestResponse struct {
+	ScheduledAt time.Time `json:"scheduled_at" example:"2024-01-16T15:00:00Z"`
+	Reason      string    `json:"reason,omitempty" example:"Tengo un viaje ese día"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// AttachmentResponse describes a file attached to an appointment
+type AttachmentResponse struct {
+	FileID      string    `json:"file_id" example:"uploads/2024/01/herida.jpg"`
+	ContentType string    `json:"content_type" example:"image/jpeg"`
+	SizeBytes   int64     `json:"size_bytes" example:"245760"`
+	Caption     string    `json:"caption,omitempty" example:"Herida en la pata trasera"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// DepositResponse describes the prepayment of an appointment. PaymentURL is
+// only returned while the deposit is still payable.
+type DepositResponse struct {
+	Amount     money.Amount `json:"amount"`
+	Currency   string       `json:"currency" example:"COP"`
+	PaymentURL string       `json:"payment_url,omitempty" example:"https://checkout.wompi.co/l/abc123"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	PaidAt     *time.Time   `json:"paid_at,omitempty"`
+}
+
 // AppointmentStatusTransitionResponse defines the structure for status transition responses
 type AppointmentStatusTransitionResponse struct {
 	ID            string    `json:"id" example:"507f1f77bcf86cd799439011"`
@@ -115,6 +431,9 @@ type AppointmentStatusTransitionResponse struct {
 	ChangedBy     string    `json:"changed_by" example:"507f1f77bcf86cd799439013"`
 	Reason        string    `json:"reason,omitempty" example:"Confirmed by staff"`
 	CreatedAt     time.Time `json:"created_at" example:"2024-01-14T15:00:00Z"`
+	// Present only on reassignment entries
+	FromVeterinarianID string `json:"from_veterinarian_id,omitempty"`
+	ToVeterinarianID   string `json:"to_veterinarian_id,omitempty"`
 }
 
 // PaginatedAppointmentsResponse defines the structure for paginated appointment responses
@@ -129,11 +448,114 @@ type CalendarViewResponse struct {
 	Appointments []AppointmentResponse `json:"appointments"`
 }
 
+// RecurrenceDTO describes how a booking repeats. Either Count or Until bounds
+// the series, which never exceeds MaxSeriesOccurrences.
+type RecurrenceDTO struct {
+	Frequency string     `json:"frequency" binding:"required,oneof=daily weekly monthly" example:"weekly"`
+	Interval  int        `json:"interval" binding:"omitempty,min=1,max=12" example:"1"`
+	Count     int        `json:"count" binding:"omitempty,min=1,max=52" example:"8"`
+	Until     *time.Time `json:"until" binding:"omitempty" example:"2024-03-15T23:59:59Z"`
+}
+
+// PreviewSeriesDTO defines the recurring booking to check before creating it
+type PreviewSeriesDTO struct {
+	VeterinarianID string        `json:"veterinarian_id" binding:"required" example:"507f1f77bcf86cd799439012"`
+	ScheduledAt    time.Time     `json:"scheduled_at" binding:"required" example:"2024-01-15T10:30:00Z"`
+	Duration       int           `json:"duration" binding:"required,min=15,max=480" example:"30"`
+	Recurrence     RecurrenceDTO `json:"recurrence" binding:"required"`
+}
+
+// SeriesOccurrenceResponse is one occurrence of a previewed series. Reason is
+// the error code that would reject it, e.g. APPOINTMENT_CONFLICT.
+type SeriesOccurrenceResponse struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Available   bool      `json:"available"`
+	Reason      string    `json:"reason,omitempty" example:"APPOINTMENT_CONFLICT"`
+	Message     string    `json:"message,omitempty"`
+}
+
+// SeriesPreviewResponse defines the structure for series preview responses
+type SeriesPreviewResponse struct {
+	Occurrences      []SeriesOccurrenceResponse `json:"occurrences"`
+	AvailableCount   int                        `json:"available_count" example:"7"`
+	UnavailableCount int                        `json:"unavailable_count" example:"1"`
+}
+
+// OffboardConflict is an appointment the replacement vet could not take; it
+// was left unassigned instead
+type OffboardConflict struct {
+	AppointmentID string    `json:"appointment_id"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	Reason        string    `json:"reason" example:"VETERINARIAN_NOT_AVAILABLE"`
+}
+
+// OffboardResponse defines the structure for offboarding responses
+type OffboardResponse struct {
+	VeterinarianID string             `json:"veterinarian_id"`
+	Reassigned     int                `json:"reassigned" example:"12"`
+	Unassigned     int                `json:"unassigned" example:"2"`
+	Conflicts      []OffboardConflict `json:"conflicts"`
+}
+
 // AvailabilityResponse defines the structure for availability check responses
 type AvailabilityResponse struct {
 	Available     bool     `json:"available" example:"true"`
 	ConflictTimes []string `json:"conflict_times,omitempty" example:"[\"10:30-11:00\", \"14:00-14:30\"]"`
 	Suggestions   []string `json:"suggestions,omitempty" example:"[\"11:00\", \"15:00\", \"16:30\"]"`
+	// ClosedReason is set when the clinic is closed that day (e.g. a holiday)
+	ClosedReason string `json:"closed_reason,omitempty"`
+	// OffDuty is set when the day has a published roster without a shift of the vet at that time
+	OffDuty bool `json:"off_duty,omitempty"`
+	// TypeNoticeHours is set when the slot is too soon for the lead time of the requested type
+	TypeNoticeHours int `json:"type_notice_hours,omitempty"`
+	// Room is the availability of the requested room, when one was given
+	Room *RoomAvailability `json:"room,omitempty"`
+}
+
+// RoomAvailability reports how full a room is for a time slot
+type RoomAvailability struct {
+	Available bool  `json:"available" example:"false"`
+	Capacity  int   `json:"capacity" example:"1"`
+	Booked    int64 `json:"booked" example:"1"`
+	// Inactive is set when the room has been taken out of service
+	Inactive bool `json:"inactive,omitempty"`
+}
+
+// VetTodayAppointment is one entry of a vet's day in the mobile app, trimmed
+// to what the agenda screen shows
+type VetTodayAppointment struct {
+	ID          string    `json:"id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Duration    int       `json:"duration" example:"30"`
+	Type        string    `json:"type" example:"consultation"`
+	Status      string    `json:"status" example:"confirmed"`
+	Priority    string    `json:"priority,omitempty" example:"normal"`
+	Reason      string    `json:"reason,omitempty"`
+	// CheckedIn is true once the visit has started
+	CheckedIn bool            `json:"checked_in"`
+	Patient   *PatientSummary `json:"patient,omitempty"`
+	Owner     *OwnerSummary   `json:"owner,omitempty"`
+}
+
+// VetTodayResponse is the authenticated vet's schedule for the current day
+type VetTodayResponse struct {
+	Date         string                `json:"date" example:"2026-03-14"`
+	TimeZone     string                `json:"timezone" example:"America/Bogota"`
+	Appointments []VetTodayAppointment `json:"appointments"`
+	// PendingRequests counts the vet's upcoming appointments not yet confirmed
+	PendingRequests int64 `json:"pending_requests" example:"3"`
+}
+
+// BookingWindowResponse describes the range in which the clinic accepts
+// bookings. Earliest/Latest are omitted when the limit is disabled.
+type BookingWindowResponse struct {
+	MinNoticeHours int `json:"min_notice_hours" example:"2"`
+	// TypeNoticeHours is the lead time of the requested type, when it needs one;
+	// Earliest already accounts for it
+	TypeNoticeHours int        `json:"type_notice_hours,omitempty" example:"48"`
+	MaxAdvanceDays  int        `json:"max_advance_days" example:"90"`
+	Earliest        *time.Time `json:"earliest,omitempty"`
+	Latest          *time.Time `json:"latest,omitempty"`
 }
 
 // Internal DTOs for filtering and querying
@@ -148,6 +570,8 @@ type appointmentFilters struct {
 	DateFrom       *time.Time
 	DateTo         *time.Time
 	Priority       *string
+	// TriagePending keeps owner requests waiting for staff to set their priority
+	TriagePending bool
 }
 
 // Conversion functions
@@ -172,16 +596,102 @@ func (a *Appointment) ToResponse() *AppointmentResponse {
 		CompletedAt:    a.CompletedAt,
 		CancelledAt:    a.CancelledAt,
 		CancelReason:   a.CancelReason,
+		AcknowledgedAt: a.AcknowledgedAt,
 		CreatedAt:      a.CreatedAt,
 		UpdatedAt:      a.UpdatedAt,
 	}
+	response.DisableReminders = a.DisableReminders
+	response.RequestedPriority = a.RequestedPriority
+	if a.EffectiveStartAt != nil {
+		response.EffectiveStartAt = a.EffectiveStartAt
+	}
+	if a.OriginalVeterinarianID != nil {
+		response.OriginalVeterinarianID = a.OriginalVeterinarianID.Hex()
+	}
+	if a.RoomID != nil {
+		response.RoomID = a.RoomID.Hex()
+	}
+	if a.FollowUpOf != nil {
+		response.FollowUpOf = a.FollowUpOf.Hex()
+	}
+	response.Display = resolveDisplay(tenant.CalendarSettings{}, a.Type, a.Status, calendarPriority(a.Priority, a.RequestedPriority))
+	if a.Deposit != nil {
+		response.Deposit = &DepositResponse{
+			Amount:    money.New(a.Deposit.Amount, a.Deposit.Currency),
+			Currency:  a.Deposit.Currency,
+			ExpiresAt: a.Deposit.ExpiresAt,
+			PaidAt:    a.Deposit.PaidAt,
+		}
+		if a.Status == AppointmentStatusAwaitingDeposit {
+			response.Deposit.PaymentURL = a.Deposit.URL
+		}
+	}
+	if a.RescheduleRequest != nil {
+		response.RescheduleRequest = &RescheduleRequestResponse{
+			ScheduledAt: a.RescheduleRequest.ScheduledAt,
+			Reason:      a.RescheduleRequest.Reason,
+			RequestedAt: a.RescheduleRequest.RequestedAt,
+		}
+	}
+	for _, att := range a.Attachments {
+		response.Attachments = append(response.Attachments, AttachmentResponse{
+			FileID:      att.FileID,
+			ContentType: att.ContentType,
+			SizeBytes:   att.SizeBytes,
+			Caption:     att.Caption,
+			AddedAt:     att.AddedAt,
+		})
+	}
 
 	return response
 }
 
+// ToOwnerResponse converts an appointment to the owner app's view
+func (a *Appointment) ToOwnerResponse() *OwnerAppointmentResponse {
+	return ownerProjection(a, a.ToResponse())
+}
+
+// ownerProjection copies the owner-safe fields of a full response, which may
+// have populated references
+func ownerProjection(a *Appointment, full *AppointmentResponse) *OwnerAppointmentResponse {
+	resp := &OwnerAppointmentResponse{
+		ID:                full.ID,
+		PatientID:         full.PatientID,
+		VeterinarianID:    full.VeterinarianID,
+		ScheduledAt:       full.ScheduledAt,
+		Duration:          full.Duration,
+		Type:              full.Type,
+		Status:            full.Status,
+		Priority:          full.Priority,
+		Reason:            full.Reason,
+		OwnerNotes:        full.OwnerNotes,
+		ConfirmedAt:       full.ConfirmedAt,
+		StartedAt:         full.StartedAt,
+		CompletedAt:       full.CompletedAt,
+		CancelledAt:       full.CancelledAt,
+		AcknowledgedAt:    full.AcknowledgedAt,
+		CreatedAt:         full.CreatedAt,
+		UpdatedAt:         full.UpdatedAt,
+		RequestedPriority: full.RequestedPriority,
+		Display:           full.Display,
+		Deposit:           full.Deposit,
+		OwnerModifiable:   full.OwnerModifiable,
+		RescheduleRequest: full.RescheduleRequest,
+		Attachments:       full.Attachments,
+		Patient:           full.Patient,
+	}
+	if a.CancelledByOwner {
+		resp.CancelReason = a.CancelReason
+	}
+	if full.Veterinarian != nil {
+		resp.Veterinarian = &OwnerVetSummary{ID: full.Veterinarian.ID, Name: full.Veterinarian.Name}
+	}
+	return resp
+}
+
 // ToResponse converts AppointmentStatusTransition to AppointmentStatusTransitionResponse
 func (t *AppointmentStatusTransition) ToResponse() *AppointmentStatusTransitionResponse {
-	return &AppointmentStatusTransitionResponse{
+	resp := &AppointmentStatusTransitionResponse{
 		ID:            t.ID.Hex(),
 		AppointmentID: t.AppointmentID.Hex(),
 		FromStatus:    t.FromStatus,
@@ -190,6 +700,13 @@ func (t *AppointmentStatusTransition) ToResponse() *AppointmentStatusTransitionR
 		Reason:        t.Reason,
 		CreatedAt:     t.CreatedAt,
 	}
+	if t.FromVeterinarianID != nil {
+		resp.FromVeterinarianID = t.FromVeterinarianID.Hex()
+	}
+	if t.ToVeterinarianID != nil {
+		resp.ToVeterinarianID = t.ToVeterinarianID.Hex()
+	}
+	return resp
 }
 
 // CreatePaginatedResponse creates a paginated response
@@ -204,3 +721,33 @@ func CreatePaginatedResponse(appointments []Appointment, params pagination.Param
 		Pagination: pagination.NewPaginationInfo(params, total),
 	}
 }
+
+// StatusSubscriptionDTO defines which appointment status changes a staff
+// member is notified of. Every list is optional and empty means any.
+type StatusSubscriptionDTO struct {
+	Statuses        []string `json:"statuses" binding:"omitempty,dive,oneof=scheduled confirmed in_progress completed cancelled no_show awaiting_deposit" example:"cancelled,no_show"`
+	Types           []string `json:"types" binding:"omitempty,dive,max=50" example:"surgery"`
+	VeterinarianIDs []string `json:"veterinarian_ids" binding:"omitempty,dive,len=24" example:"507f1f77bcf86cd799439013"`
+}
+
+// StatusSubscriptionResponse defines the structure for a status subscription response
+type StatusSubscriptionResponse struct {
+	Statuses        []string  `json:"statuses"`
+	Types           []string  `json:"types"`
+	VeterinarianIDs []string  `json:"veterinarian_ids"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ToResponse converts a StatusSubscription to StatusSubscriptionResponse
+func (s *StatusSubscription) ToResponse() *StatusSubscriptionResponse {
+	vetIDs := make([]string, len(s.VeterinarianIDs))
+	for i, id := range s.VeterinarianIDs {
+		vetIDs[i] = id.Hex()
+	}
+	return &StatusSubscriptionResponse{
+		Statuses:        nonNil(s.Statuses),
+		Types:           nonNil(s.Types),
+		VeterinarianIDs: vetIDs,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
@@ -1,75 +1,122 @@
 package appointments
 
-import "errors"
+import (
+	"fmt"
+	"time"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
 
 var (
 	// General appointment errors
-	ErrAppointmentNotFound      = errors.New("appointment not found")
-	ErrAppointmentAlreadyExists = errors.New("appointment already exists at this time")
+	ErrAppointmentNotFound      = sharedErrors.New(sharedErrors.ErrNotFound, "APPOINTMENT_NOT_FOUND", "appointment not found")
+	ErrAppointmentAlreadyExists = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_ALREADY_EXISTS", "appointment already exists at this time")
 
 	// Validation errors
-	ErrInvalidAppointmentTime = errors.New("invalid appointment time")
-	ErrPastAppointmentTime    = errors.New("cannot schedule appointment in the past")
-	ErrInvalidDuration        = errors.New("invalid appointment duration")
-	ErrInvalidTimeRange       = errors.New("invalid time range for appointment")
-	ErrOutsideBusinessHours   = errors.New("appointment must be scheduled during business hours")
+	ErrInvalidAppointmentTime = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_APPOINTMENT_TIME", "invalid appointment time")
+	ErrPastAppointmentTime    = sharedErrors.New(sharedErrors.ErrInvalidInput, "PAST_APPOINTMENT_TIME", "cannot schedule appointment in the past")
+	ErrInvalidDuration        = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_DURATION", "invalid appointment duration")
+	ErrInvalidTimeRange       = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_TIME_RANGE", "invalid time range for appointment")
+	ErrOutsideBusinessHours   = sharedErrors.New(sharedErrors.ErrInvalidInput, "OUTSIDE_BUSINESS_HOURS", "appointment must be scheduled during business hours")
+	ErrOffSlotGrid            = sharedErrors.New(sharedErrors.ErrInvalidInput, "OFF_SLOT_GRID", "invalid appointment time: must start on the clinic's slot grid")
 
 	// Conflict errors
-	ErrAppointmentConflict      = errors.New("appointment time conflicts with existing appointment")
-	ErrVeterinarianNotAvailable = errors.New("veterinarian is not available at the requested time")
-	ErrPatientNotAvailable      = errors.New("patient already has an appointment at this time")
+	ErrAppointmentConflict      = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_CONFLICT", "appointment time conflicts with existing appointment")
+	ErrVeterinarianNotAvailable = sharedErrors.New(sharedErrors.ErrConflict, "VETERINARIAN_NOT_AVAILABLE", "veterinarian is not available at the requested time")
+	ErrPatientNotAvailable      = sharedErrors.New(sharedErrors.ErrConflict, "PATIENT_NOT_AVAILABLE", "patient already has an appointment at this time")
+	ErrVeterinarianOffDuty      = sharedErrors.New(sharedErrors.ErrConflict, "VETERINARIAN_OFF_DUTY", "veterinarian has no published shift covering the requested time")
 
 	// Status transition errors
-	ErrInvalidStatusTransition     = errors.New("invalid status transition")
-	ErrAppointmentAlreadyStarted   = errors.New("appointment already started")
-	ErrAppointmentAlreadyCompleted = errors.New("appointment already completed")
-	ErrAppointmentAlreadyCancelled = errors.New("appointment already cancelled")
-	ErrAppointmentNotConfirmed     = errors.New("appointment must be confirmed before starting")
-	ErrCannotCancelPastAppointment = errors.New("cannot cancel past appointments")
+	ErrInvalidStatusTransition     = sharedErrors.New(sharedErrors.ErrConflict, "INVALID_STATUS_TRANSITION", "invalid status transition")
+	ErrAppointmentAlreadyStarted   = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_ALREADY_STARTED", "appointment already started")
+	ErrAppointmentAlreadyCompleted = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_ALREADY_COMPLETED", "appointment already completed")
+	ErrAppointmentAlreadyCancelled = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_ALREADY_CANCELLED", "appointment already cancelled")
+	ErrAppointmentNotConfirmed     = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_NOT_CONFIRMED", "appointment must be confirmed before starting")
+	ErrCannotReassignClosed        = sharedErrors.New(sharedErrors.ErrConflict, "CANNOT_REASSIGN_CLOSED_APPOINTMENT", "completed, cancelled or no-show appointments cannot be reassigned")
+	ErrCannotCancelPastAppointment = sharedErrors.New(sharedErrors.ErrUnprocessable, "CANNOT_CANCEL_PAST_APPOINTMENT", "cannot cancel past appointments")
+	ErrPatientDeceased             = sharedErrors.New(sharedErrors.ErrUnprocessable, "PATIENT_DECEASED", "cannot book appointments for a deceased patient")
+
+	// Deletion errors
+	ErrAppointmentHasMedicalRecords = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_HAS_MEDICAL_RECORDS", "appointment has linked medical records, pass force with a reason to delete it")
+	ErrForceDeleteReasonRequired    = sharedErrors.New(sharedErrors.ErrInvalidInput, "FORCE_DELETE_REASON_REQUIRED", "validation failed: reason is required when forcing deletion")
 
 	// Business logic errors
-	ErrPatientNotFound        = errors.New("patient not found for appointment")
-	ErrOwnerNotFound          = errors.New("owner not found for appointment")
-	ErrVeterinarianNotFound   = errors.New("veterinarian not found")
-	ErrInvalidAppointmentType = errors.New("invalid appointment type")
-	ErrInvalidPriority        = errors.New("invalid appointment priority")
+	ErrPatientNotFound        = sharedErrors.New(sharedErrors.ErrNotFound, "PATIENT_NOT_FOUND", "patient not found for appointment")
+	ErrOwnerNotFound          = sharedErrors.New(sharedErrors.ErrNotFound, "OWNER_NOT_FOUND", "owner not found for appointment")
+	ErrVeterinarianNotFound   = sharedErrors.New(sharedErrors.ErrNotFound, "VETERINARIAN_NOT_FOUND", "veterinarian not found")
+	ErrInvalidAppointmentType = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_APPOINTMENT_TYPE", "invalid appointment type")
+	ErrInvalidPriority        = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_PRIORITY", "invalid appointment priority")
 
 	// Permission errors
-	ErrUnauthorizedAccess      = errors.New("unauthorized access to appointment")
-	ErrInsufficientPermissions = errors.New("insufficient permissions to perform this action")
-	ErrOwnerMismatch           = errors.New("appointment does not belong to this owner")
+	ErrUnauthorizedAccess      = sharedErrors.New(sharedErrors.ErrForbidden, "UNAUTHORIZED_APPOINTMENT_ACCESS", "unauthorized access to appointment")
+	ErrInsufficientPermissions = sharedErrors.New(sharedErrors.ErrForbidden, "INSUFFICIENT_PERMISSIONS", "insufficient permissions to perform this action")
+	ErrOwnerMismatch           = sharedErrors.New(sharedErrors.ErrForbidden, "OWNER_MISMATCH", "appointment does not belong to this owner")
 
 	// Mobile-specific errors
-	ErrAppointmentRequestLimit = errors.New("appointment request limit reached")
-	ErrTooManyPendingRequests  = errors.New("too many pending appointment requests")
-	ErrRequestTooSoon          = errors.New("cannot request appointment with less than 24 hours notice")
+	ErrAppointmentRequestLimit = sharedErrors.New(sharedErrors.ErrUnprocessable, "APPOINTMENT_REQUEST_LIMIT", "appointment request limit reached")
+	ErrTooManyPendingRequests  = sharedErrors.New(sharedErrors.ErrUnprocessable, "TOO_MANY_PENDING_REQUESTS", "too many pending appointment requests")
+	ErrRequestTooSoon          = sharedErrors.New(sharedErrors.ErrUnprocessable, "REQUEST_TOO_SOON", "cannot request appointment with less than 24 hours notice")
 
-	// System errors
-	ErrDatabaseConnection  = errors.New("database connection error")
-	ErrNotificationFailed  = errors.New("failed to send notification")
-	ErrInternalServerError = errors.New("internal server error")
-)
+	// Booking window errors
+	ErrBeyondBookingWindow    = sharedErrors.New(sharedErrors.ErrInvalidInput, "BEYOND_BOOKING_WINDOW", "invalid appointment time: beyond the clinic's maximum advance booking window")
+	ErrInsufficientNotice     = sharedErrors.New(sharedErrors.ErrInvalidInput, "INSUFFICIENT_NOTICE", "invalid appointment time: less than the clinic's minimum booking notice")
+	ErrInsufficientTypeNotice = sharedErrors.New(sharedErrors.ErrInvalidInput, "INSUFFICIENT_TYPE_NOTICE", "invalid appointment time: less than the lead time of the appointment type")
 
-// AppointmentError wraps appointment-specific errors with additional context
-type AppointmentError struct {
-	Code    string
-	Message string
-	Details map[string]interface{}
-	Err     error
-}
+	// Deposit errors
+	ErrDepositCurrencyNotDefined = sharedErrors.New(sharedErrors.ErrUnprocessable, "DEPOSIT_CURRENCY_NOT_DEFINED", "the clinic has no currency configured to charge the deposit")
+	ErrDepositLinkFailed         = sharedErrors.New(sharedErrors.ErrInternal, "DEPOSIT_LINK_FAILED", "failed to create the deposit payment link")
 
-func (e AppointmentError) Error() string {
-	if e.Err != nil {
-		return e.Message + ": " + e.Err.Error()
-	}
-	return e.Message
-}
+	// Appointment type catalog errors
+	ErrAppointmentTypeNotFound = sharedErrors.New(sharedErrors.ErrNotFound, "APPOINTMENT_TYPE_NOT_FOUND", "appointment type not found")
+	ErrAppointmentTypeExists   = sharedErrors.New(sharedErrors.ErrConflict, "APPOINTMENT_TYPE_EXISTS", "an appointment type with this key already exists")
+	ErrVeterinarianRequired    = sharedErrors.New(sharedErrors.ErrInvalidInput, "VETERINARIAN_REQUIRED", "validation failed: this appointment type requires a veterinarian")
 
-func (e AppointmentError) Unwrap() error {
-	return e.Err
-}
+	// Reschedule errors
+	ErrRescheduleNotAllowed = sharedErrors.New(sharedErrors.ErrConflict, "RESCHEDULE_NOT_ALLOWED", "only scheduled or confirmed appointments can be rescheduled")
+	ErrNoRescheduleRequest  = sharedErrors.New(sharedErrors.ErrConflict, "NO_RESCHEDULE_REQUEST", "appointment has no pending reschedule request")
+	ErrRescheduleCutoff     = sharedErrors.New(sharedErrors.ErrUnprocessable, "RESCHEDULE_CUTOFF_PASSED", "the appointment is too close to be rescheduled")
+	ErrOwnerChangeLocked    = sharedErrors.New(sharedErrors.ErrForbidden, "CALL_CLINIC_TO_CHANGE", "this appointment can only be cancelled or rescheduled by the clinic, please call them")
+
+	// Status workflow errors
+	ErrInvalidStatusWorkflow = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_STATUS_WORKFLOW", "invalid appointment status workflow")
+
+	// Intake errors
+	ErrIntakeRequired = sharedErrors.New(sharedErrors.ErrUnprocessable, "INTAKE_REQUIRED", "the owner must complete the new client intake form before their first appointment can be confirmed")
 
-// NewAppointmentError creates a new AppointmentError
+	// Consent errors
+	ErrConsentRequired = sharedErrors.New(sharedErrors.ErrUnprocessable, "CONSENT_REQUIRED", "the owner must sign the consent for this procedure before the appointment can start")
+	ErrConsentClosed   = sharedErrors.New(sharedErrors.ErrConflict, "CONSENT_CLOSED", "consents can only be signed for upcoming or ongoing appointments")
+
+	// Status subscription errors
+	ErrSubscriptionNotFound = sharedErrors.New(sharedErrors.ErrNotFound, "SUBSCRIPTION_NOT_FOUND", "no status subscription for this user")
+
+	// Room errors
+	ErrRoomNotFound     = sharedErrors.New(sharedErrors.ErrNotFound, "ROOM_NOT_FOUND", "room not found")
+	ErrRoomRequired     = sharedErrors.New(sharedErrors.ErrInvalidInput, "ROOM_REQUIRED", "validation failed: this appointment type requires a room")
+	ErrRoomKindMismatch = sharedErrors.New(sharedErrors.ErrInvalidInput, "ROOM_KIND_MISMATCH", "validation failed: the room is not of the kind this appointment type requires")
+	ErrRoomInactive     = sharedErrors.New(sharedErrors.ErrConflict, "ROOM_INACTIVE", "the room is out of service")
+	ErrRoomFull         = sharedErrors.New(sharedErrors.ErrConflict, "ROOM_NOT_AVAILABLE", "the room is fully booked at the requested time")
+
+	// Attachment errors
+	ErrAttachmentsClosed  = sharedErrors.New(sharedErrors.ErrConflict, "ATTACHMENTS_CLOSED", "attachments can only be added to upcoming appointments")
+	ErrAttachmentTooLarge = sharedErrors.New(sharedErrors.ErrInvalidInput, "ATTACHMENT_TOO_LARGE", "validation failed: attachment exceeds the maximum file size")
+	ErrAttachmentLimit    = sharedErrors.New(sharedErrors.ErrUnprocessable, "TOO_MANY_ATTACHMENTS", "the appointment already has the maximum number of attachments")
+
+	// Late arrival errors
+	ErrLateArrival = sharedErrors.New(sharedErrors.ErrUnprocessable, "LATE_ARRIVAL_BEYOND_TOLERANCE", "the patient arrived later than the clinic's late arrival tolerance")
+
+	// System errors
+	ErrDatabaseConnection  = sharedErrors.New(sharedErrors.ErrInternal, "DATABASE_ERROR", "database connection error")
+	ErrNotificationFailed  = sharedErrors.New(sharedErrors.ErrInternal, "NOTIFICATION_FAILED", "failed to send notification")
+	ErrInternalServerError = sharedErrors.New(sharedErrors.ErrInternal, "INTERNAL_ERROR", "internal server error")
+)
+
+// AppointmentError wraps appointment-specific errors with additional context.
+// It is the shared coded error, so the HTTP layer maps it like any other.
+type AppointmentError = sharedErrors.Error
+
+// NewAppointmentError creates a new AppointmentError. Its status is taken
+// from err, which should be one of the coded sentinels above.
 func NewAppointmentError(code, message string, details map[string]interface{}, err error) *AppointmentError {
 	return &AppointmentError{
 		Code:    code,
@@ -103,8 +150,21 @@ func ErrInvalidStatus(currentStatus, targetStatus string) *AppointmentError {
 	)
 }
 
-func ErrResourceNotFound(resourceType, resourceID string) *AppointmentError {
+// ErrInvalidWorkflow reports why a clinic's status workflow was rejected
+func ErrInvalidWorkflow(status, reason string) *AppointmentError {
 	return NewAppointmentError(
+		"INVALID_STATUS_WORKFLOW",
+		"Invalid appointment status workflow",
+		map[string]interface{}{
+			"status": status,
+			"reason": reason,
+		},
+		ErrInvalidStatusWorkflow,
+	)
+}
+
+func ErrResourceNotFound(resourceType, resourceID string) *AppointmentError {
+	err := NewAppointmentError(
 		"RESOURCE_NOT_FOUND",
 		resourceType+" not found",
 		map[string]interface{}{
@@ -113,10 +173,98 @@ func ErrResourceNotFound(resourceType, resourceID string) *AppointmentError {
 		},
 		nil,
 	)
+	err.Kind = sharedErrors.ErrNotFound
+	return err
 }
 
-func ErrValidationFailed(field, reason string) *AppointmentError {
+func ErrBookingTooFarAhead(maxAdvanceDays int, latest time.Time) *AppointmentError {
+	return NewAppointmentError(
+		"BOOKING_TOO_FAR_AHEAD",
+		"Appointment is too far in the future",
+		map[string]interface{}{
+			"max_advance_days": maxAdvanceDays,
+			"latest":           latest,
+		},
+		ErrBeyondBookingWindow,
+	)
+}
+
+func ErrBookingNoticeTooShort(minNoticeHours int, earliest time.Time) *AppointmentError {
+	return NewAppointmentError(
+		"BOOKING_NOTICE_TOO_SHORT",
+		"Appointment does not meet the minimum notice",
+		map[string]interface{}{
+			"min_notice_hours": minNoticeHours,
+			"earliest":         earliest,
+		},
+		ErrInsufficientNotice,
+	)
+}
+
+func ErrTypeNoticeTooShort(typeKey string, minNoticeHours int, earliest time.Time) *AppointmentError {
 	return NewAppointmentError(
+		"TYPE_NOTICE_TOO_SHORT",
+		"Appointment does not meet the lead time of its type",
+		map[string]interface{}{
+			"type":             typeKey,
+			"min_notice_hours": minNoticeHours,
+			"earliest":         earliest,
+		},
+		ErrInsufficientTypeNotice,
+	)
+}
+
+func ErrNotOnSlotGrid(granularityMinutes int, before, after time.Time) *AppointmentError {
+	return NewAppointmentError(
+		"OFF_SLOT_GRID",
+		fmt.Sprintf("Appointments must start on a %d-minute boundary", granularityMinutes),
+		map[string]interface{}{
+			"granularity_minutes": granularityMinutes,
+			"previous_slot":       before,
+			"next_slot":           after,
+		},
+		ErrOffSlotGrid,
+	)
+}
+
+func ErrUnknownAppointmentType(key, reason string) *AppointmentError {
+	return NewAppointmentError(
+		"INVALID_APPOINTMENT_TYPE",
+		"Appointment type is not available at this clinic",
+		map[string]interface{}{
+			"type":   key,
+			"reason": reason,
+		},
+		ErrInvalidAppointmentType,
+	)
+}
+
+func ErrLateBeyondTolerance(lateMinutes, toleranceMinutes int) *AppointmentError {
+	return NewAppointmentError(
+		"LATE_ARRIVAL_BEYOND_TOLERANCE",
+		"Patient arrived too late to shift the appointment start",
+		map[string]interface{}{
+			"late_minutes":      lateMinutes,
+			"tolerance_minutes": toleranceMinutes,
+		},
+		ErrLateArrival,
+	)
+}
+
+func ErrRescheduleTooLate(cutoffHours int, deadline time.Time) *AppointmentError {
+	return NewAppointmentError(
+		"RESCHEDULE_CUTOFF_PASSED",
+		"Appointment is too close to be rescheduled from the app",
+		map[string]interface{}{
+			"cutoff_hours": cutoffHours,
+			"deadline":     deadline,
+		},
+		ErrRescheduleCutoff,
+	)
+}
+
+func ErrValidationFailed(field, reason string) *AppointmentError {
+	err := NewAppointmentError(
 		"VALIDATION_ERROR",
 		"Validation failed for field: "+field,
 		map[string]interface{}{
@@ -125,4 +273,29 @@ func ErrValidationFailed(field, reason string) *AppointmentError {
 		},
 		nil,
 	)
+	err.Kind = sharedErrors.ErrInvalidInput
+	err.Field = field
+	return err
+}
+
+// ErrClinicClosedOnHoliday reports a booking on a day the clinic is closed
+func ErrClinicClosedOnHoliday(name string, day time.Time) error {
+	return &AppointmentError{
+		Kind:    sharedErrors.ErrInvalidInput,
+		Code:    "CLINIC_CLOSED_HOLIDAY",
+		Message: fmt.Sprintf("the clinic is closed on %s (%s)", day.Format("2006-01-02"), name),
+		Details: map[string]interface{}{"date": day.Format("2006-01-02"), "holiday": name},
+	}
+}
+
+// ErrTooManyAttachments reports an appointment already at the clinic's attachment limit
+func ErrTooManyAttachments(limit int) *AppointmentError {
+	return NewAppointmentError(
+		"TOO_MANY_ATTACHMENTS",
+		"Appointment already has the maximum number of attachments",
+		map[string]interface{}{
+			"max_attachments": limit,
+		},
+		ErrAttachmentLimit,
+	)
 }
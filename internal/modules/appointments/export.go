@@ -0,0 +1,90 @@
+package appointments
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/export"
+)
+
+// AppointmentExportRow is an exported appointment with the names of the
+// people involved
+type AppointmentExportRow struct {
+	*Appointment
+	Patient      string
+	Owner        string
+	Veterinarian string
+}
+
+// appointmentExportColumns are the export columns; dates are in loc
+func appointmentExportColumns(loc *time.Location) []export.Column[AppointmentExportRow] {
+	return []export.Column[AppointmentExportRow]{
+		{Key: "patient", Value: func(r *AppointmentExportRow) any { return r.Patient }},
+		{Key: "owner", Value: func(r *AppointmentExportRow) any { return r.Owner }},
+		{Key: "veterinarian", Value: func(r *AppointmentExportRow) any { return r.Veterinarian }},
+		{Key: "date", Value: func(r *AppointmentExportRow) any { return r.ScheduledAt.In(loc).Format("2006-01-02 15:04") }},
+		{Key: "duration_minutes", Value: func(r *AppointmentExportRow) any { return r.Duration }},
+		{Key: "type", Value: func(r *AppointmentExportRow) any { return r.Type }},
+		{Key: "status", Value: func(r *AppointmentExportRow) any { return r.Status }},
+		{Key: "priority", Value: func(r *AppointmentExportRow) any { return r.Priority }},
+		{Key: "cancel_reason", Value: func(r *AppointmentExportRow) any { return r.CancelReason }},
+	}
+}
+
+// ExportAppointments validates the list filters and returns an export of the
+// matching appointments, oldest first. Dates are in the clinic's timezone.
+// Names are looked up once per patient, owner and vet, so memory grows with
+// the people involved rather than with the appointments.
+func (s *Service) ExportAppointments(ctx context.Context, filters map[string]interface{}, tenantID primitive.ObjectID) (*export.Export[AppointmentExportRow], error) {
+	appointmentFilters := s.parseFilters(filters)
+	if err := s.validateTypeFilter(ctx, tenantID, appointmentFilters.Type); err != nil {
+		return nil, err
+	}
+
+	loc := time.UTC
+	if t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex()); err == nil && t.TimeZone != "" {
+		if l, err := time.LoadLocation(t.TimeZone); err == nil {
+			loc = l
+		}
+	}
+
+	source := func(emit func(*AppointmentExportRow) error) error {
+		patientNames := make(map[primitive.ObjectID]string)
+		ownerNames := make(map[primitive.ObjectID]string)
+		vetNames := make(map[primitive.ObjectID]string)
+
+		return s.repo.ForEach(ctx, appointmentFilters, tenantID, func(a *Appointment) error {
+			patient, ok := patientNames[a.PatientID]
+			if !ok {
+				if p, err := s.patientRepo.FindByID(ctx, tenantID, a.PatientID.Hex()); err == nil {
+					patient = p.Name
+				}
+				patientNames[a.PatientID] = patient
+			}
+			owner, ok := ownerNames[a.OwnerID]
+			if !ok {
+				if o, err := s.ownerRepo.FindByID(ctx, a.OwnerID.Hex()); err == nil {
+					owner = o.Name
+				}
+				ownerNames[a.OwnerID] = owner
+			}
+			vet, ok := vetNames[a.VeterinarianID]
+			if !ok && !a.VeterinarianID.IsZero() {
+				if u, err := s.userRepo.FindByID(ctx, a.VeterinarianID.Hex()); err == nil {
+					vet = u.Name
+				}
+				vetNames[a.VeterinarianID] = vet
+			}
+
+			return emit(&AppointmentExportRow{Appointment: a, Patient: patient, Owner: owner, Veterinarian: vet})
+		})
+	}
+
+	return &export.Export[AppointmentExportRow]{
+		Name:    "citas",
+		Columns: appointmentExportColumns(loc),
+		Source:  source,
+	}, nil
+}
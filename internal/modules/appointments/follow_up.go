@@ -0,0 +1,138 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/medical_records"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+)
+
+// BookFollowUp puts the next visit of a medical record on the calendar,
+// implementing medical_records.FollowUpBooker. An open appointment the patient
+// already has that day is linked instead of booking a second one. Otherwise
+// the visit is booked with the record's vet; when that vet is busy or off
+// duty it is still created, unassigned, in the request queue for staff to
+// place. Any other rejection of the time is returned as is.
+func (s *Service) BookFollowUp(ctx context.Context, req medical_records.FollowUpRequest) (*medical_records.FollowUpBooking, error) {
+	if existing := s.findOpenAppointmentOn(ctx, req.TenantID, req.PatientID, req.ScheduledAt); existing != nil {
+		return &medical_records.FollowUpBooking{
+			AppointmentID: existing.ID,
+			Outcome:       medical_records.NextVisitLinked,
+			Note:          fmt.Sprintf("El paciente ya tenía una cita ese día a las %s", existing.ScheduledAt.Format("15:04")),
+		}, nil
+	}
+
+	booked, err := s.createAppointment(ctx, CreateAppointmentDTO{
+		PatientID:      req.PatientID.Hex(),
+		VeterinarianID: req.VeterinarianID.Hex(),
+		ScheduledAt:    req.ScheduledAt,
+		Type:           req.Type,
+		Reason:         req.Reason,
+	}, req.TenantID, req.BookedBy, &req.RecordID)
+	if err == nil {
+		id, _ := primitive.ObjectIDFromHex(booked.ID)
+		return &medical_records.FollowUpBooking{AppointmentID: id, Outcome: medical_records.NextVisitBooked}, nil
+	}
+	if !errors.Is(err, ErrAppointmentConflict) && !errors.Is(err, ErrVeterinarianOffDuty) {
+		return nil, err
+	}
+
+	queued, err := s.queueFollowUp(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &medical_records.FollowUpBooking{
+		AppointmentID: queued.ID,
+		Outcome:       medical_records.NextVisitQueued,
+		Note:          "El veterinario no estaba disponible a esa hora; la cita quedó sin asignar en la cola de solicitudes",
+	}, nil
+}
+
+// ReleaseFollowUp removes a follow-up booked for a record that was not saved
+func (s *Service) ReleaseFollowUp(ctx context.Context, appointmentID, tenantID primitive.ObjectID) error {
+	return s.repo.Delete(ctx, appointmentID, tenantID)
+}
+
+// findOpenAppointmentOn returns an open appointment of the patient on the day
+// of at, or nil. A lookup failure returns nil so a new one is booked instead.
+func (s *Service) findOpenAppointmentOn(ctx context.Context, tenantID, patientID primitive.ObjectID, at time.Time) *Appointment {
+	dayStart := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	day, err := s.repo.FindByDateRange(ctx, dayStart, dayStart.AddDate(0, 0, 1).Add(-time.Nanosecond), tenantID)
+	if err != nil {
+		return nil
+	}
+	for i := range day {
+		a := &day[i]
+		if a.PatientID != patientID {
+			continue
+		}
+		switch a.Status {
+		case AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusAwaitingDeposit:
+			return a
+		}
+	}
+	return nil
+}
+
+// queueFollowUp creates the follow-up without a vet, the way owner requests
+// wait for triage, and flags it to staff. The time itself was already
+// validated by the booking attempt.
+func (s *Service) queueFollowUp(ctx context.Context, req medical_records.FollowUpRequest) (*Appointment, error) {
+	apptType, err := s.resolveAppointmentType(ctx, req.TenantID, req.Type, "")
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := s.patientRepo.FindByID(ctx, req.TenantID, req.PatientID.Hex())
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+	if err := s.checkPatientAvailability(ctx, req.TenantID, req.PatientID, req.ScheduledAt, apptType.DefaultDuration, nil); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	appointment := &Appointment{
+		TenantID:    req.TenantID,
+		PatientID:   req.PatientID,
+		OwnerID:     patient.OwnerID,
+		ScheduledAt: req.ScheduledAt,
+		Duration:    apptType.DefaultDuration,
+		Type:        req.Type,
+		Status:      AppointmentStatusScheduled,
+		Priority:    AppointmentPriorityNormal,
+		Reason:      req.Reason,
+		Notes:       "Control pedido en la historia clínica; el veterinario no estaba disponible a esa hora",
+		FollowUpOf:  &req.RecordID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.prepareDeposit(ctx, appointment, apptType, AppointmentStatusScheduled); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, appointment); err != nil {
+		return nil, err
+	}
+
+	if appointment.Deposit != nil {
+		s.notifyDepositDue(ctx, appointment, patient.Name)
+	}
+
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   primitive.NilObjectID.Hex(),
+		TenantID: req.TenantID.Hex(),
+		Type:     notifications.TypeStaffNewAppointment,
+		Title:    "Control por agendar",
+		Body:     fmt.Sprintf("Control de %s para el %s sin veterinario disponible", patient.Name, req.ScheduledAt.Format("02/01/2006 15:04")),
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex(), "record_id": req.RecordID.Hex()},
+	})
+
+	return appointment, nil
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/modules/consents"
 	"github.com/eren_dev/go_server/internal/shared/auth"
+	"github.com/eren_dev/go_server/internal/shared/export"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
 	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"github.com/eren_dev/go_server/internal/shared/validation"
@@ -70,7 +73,9 @@ func (h *Handler) CreateAppointment(c *gin.Context) (any, error) {
 // @Produce json
 // @Param id path string true "Appointment ID"
 // @Param populate query bool false "Populate related data"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} AppointmentResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Security BearerAuth
@@ -89,7 +94,7 @@ func (h *Handler) GetAppointment(c *gin.Context) (any, error) {
 		return nil, err
 	}
 
-	return appointment, nil
+	return &httpx.Tagged{Data: appointment, UpdatedAt: appointment.UpdatedAt}, nil
 }
 
 // ListAppointments lists appointments with filters and pagination
@@ -108,7 +113,9 @@ func (h *Handler) GetAppointment(c *gin.Context) (any, error) {
 // @Param date_from query string false "Filter from date (RFC3339)"
 // @Param date_to query string false "Filter to date (RFC3339)"
 // @Param priority query string false "Filter by priority"
+// @Param triage query bool false "Only owner requests waiting for priority triage"
 // @Param populate query bool false "Populate related data"
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending (scheduled_at, status)"
 // @Success 200 {object} PaginatedAppointmentsResponse
 // @Failure 400 {object} map[string]interface{}
 // @Security BearerAuth
@@ -118,6 +125,57 @@ func (h *Handler) ListAppointments(c *gin.Context) (any, error) {
 	populate := c.Query("populate") == "true"
 	tenantID := sharedMiddleware.GetTenantID(c)
 
+	filters, err := listFilters(c)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := h.service.ListAppointments(c.Request.Context(), filters, tenantID, params, populate)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// ExportAppointments streams the filtered appointment list as CSV or JSON lines
+// @Summary Export appointments
+// @Description Streams the appointments matching the list filters as a UTF-8 CSV (with BOM) for spreadsheets, or as JSON lines
+// @Tags admin-appointments
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Export format, overrides the Accept header" Enums(csv, jsonl)
+// @Param columns query string false "Comma separated columns to include, in order (patient,owner,veterinarian,date,duration_minutes,type,status,priority,cancel_reason)"
+// @Param status query []string false "Filter by status"
+// @Param type query []string false "Filter by appointment type"
+// @Param veterinarian_id query string false "Filter by veterinarian ID"
+// @Param patient_id query string false "Filter by patient ID"
+// @Param owner_id query string false "Filter by owner ID"
+// @Param date_from query string false "Filter from date (RFC3339)"
+// @Param date_to query string false "Filter to date (RFC3339)"
+// @Param priority query string false "Filter by priority"
+// @Param triage query bool false "Only owner requests waiting for priority triage"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/export [get]
+func (h *Handler) ExportAppointments(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	filters, err := listFilters(c)
+	if err != nil {
+		return nil, err
+	}
+
+	appointmentsExport, err := h.service.ExportAppointments(c.Request.Context(), filters, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return export.Respond(c, appointmentsExport)
+}
+
+// listFilters reads the appointment list filters from the query string
+func listFilters(c *gin.Context) (map[string]interface{}, error) {
 	filters := make(map[string]interface{})
 
 	if statuses := c.QueryArray("status"); len(statuses) > 0 {
@@ -160,12 +218,11 @@ func (h *Handler) ListAppointments(c *gin.Context) (any, error) {
 		filters["priority"] = priority
 	}
 
-	appointments, err := h.service.ListAppointments(c.Request.Context(), filters, tenantID, params, populate)
-	if err != nil {
-		return nil, err
+	if c.Query("triage") == "true" {
+		filters["triage"] = true
 	}
 
-	return appointments, nil
+	return filters, nil
 }
 
 // UpdateAppointment updates an appointment
@@ -210,14 +267,17 @@ func (h *Handler) UpdateAppointment(c *gin.Context) (any, error) {
 
 // DeleteAppointment deletes an appointment
 // @Summary Delete appointment
-// @Description Soft delete an appointment
+// @Description Soft delete an appointment. Appointments referenced by medical records require force=true and a reason, which is written to the audit log
 // @Tags admin-appointments
 // @Accept json
 // @Produce json
 // @Param id path string true "Appointment ID"
+// @Param force query bool false "Delete even if medical records reference the appointment"
+// @Param reason query string false "Reason for a forced deletion"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Security BearerAuth
 // @Router /api/appointments/{id} [delete]
 func (h *Handler) DeleteAppointment(c *gin.Context) (any, error) {
@@ -232,9 +292,14 @@ func (h *Handler) DeleteAppointment(c *gin.Context) (any, error) {
 		return nil, ErrValidationFailed("user_id", "invalid user ID format")
 	}
 
+	var dto DeleteAppointmentDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
 	tenantID := sharedMiddleware.GetTenantID(c)
 
-	err = h.service.DeleteAppointment(c.Request.Context(), id, tenantID, userID)
+	err = h.service.DeleteAppointment(c.Request.Context(), id, tenantID, userID, dto)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +309,7 @@ func (h *Handler) DeleteAppointment(c *gin.Context) (any, error) {
 
 // UpdateStatus updates an appointment status
 // @Summary Update appointment status
-// @Description Update the status of an appointment
+// @Description Update the status of an appointment. When starting a late appointment, shift_start moves its effective start to now if the delay is within the clinic's late tolerance; an overrun into the vet's next appointment is returned in warnings.
 // @Tags admin-appointments
 // @Accept json
 // @Produce json
@@ -253,6 +318,7 @@ func (h *Handler) DeleteAppointment(c *gin.Context) (any, error) {
 // @Success 200 {object} AppointmentResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Security BearerAuth
 // @Router /api/appointments/{id}/status [patch]
 func (h *Handler) UpdateStatus(c *gin.Context) (any, error) {
@@ -284,7 +350,7 @@ func (h *Handler) UpdateStatus(c *gin.Context) (any, error) {
 
 // GetCalendarView gets a calendar view of appointments
 // @Summary Get calendar view
-// @Description Get appointments organized by date for calendar display
+// @Description Get appointments organized by date for calendar display. Each appointment carries a display color and label resolved from the clinic calendar styles.
 // @Tags admin-appointments
 // @Accept json
 // @Produce json
@@ -338,6 +404,8 @@ func (h *Handler) GetCalendarView(c *gin.Context) (any, error) {
 // @Param scheduled_at query string true "Scheduled time (RFC3339)"
 // @Param duration query int true "Duration in minutes"
 // @Param exclude_id query string false "Exclude appointment ID (for updates)"
+// @Param room_id query string false "Room ID, to also check the room's capacity"
+// @Param type query string false "Appointment type key, to also check its lead time"
 // @Success 200 {object} AvailabilityResponse
 // @Failure 400 {object} map[string]interface{}
 // @Security BearerAuth
@@ -368,7 +436,7 @@ func (h *Handler) CheckAvailability(c *gin.Context) (any, error) {
 
 	tenantID := sharedMiddleware.GetTenantID(c)
 
-	available, err := h.service.CheckAvailability(c.Request.Context(), vetID, scheduledAt, duration, excludeID, tenantID)
+	available, err := h.service.CheckAvailability(c.Request.Context(), vetID, scheduledAt, duration, excludeID, c.Query("room_id"), c.Query("type"), tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -376,6 +444,113 @@ func (h *Handler) CheckAvailability(c *gin.Context) (any, error) {
 	return available, nil
 }
 
+// PreviewSeries checks a recurring booking without creating it
+// @Summary Preview appointment series
+// @Description Expand a recurring booking and report, per occurrence, whether it can be booked (business hours, holidays, booking window and veterinarian conflicts). Nothing is created
+// @Tags admin-appointments
+// @Accept json
+// @Produce json
+// @Param series body PreviewSeriesDTO true "Series to preview"
+// @Success 200 {object} SeriesPreviewResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/preview-series [post]
+func (h *Handler) PreviewSeries(c *gin.Context) (any, error) {
+	var dto PreviewSeriesDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.PreviewSeries(c.Request.Context(), dto, sharedMiddleware.GetTenantID(c))
+}
+
+// ReassignVeterinarian hands an appointment over to another veterinarian
+// @Summary Reassign appointment
+// @Description Change the veterinarian of an active appointment (e.g. end of shift). The new vet must be free at that time; the handoff is kept in the status history and both vets are notified
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Param reassign body ReassignAppointmentDTO true "New veterinarian and reason"
+// @Success 200 {object} AppointmentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/{id}/reassign [patch]
+func (h *Handler) ReassignVeterinarian(c *gin.Context) (any, error) {
+	var dto ReassignAppointmentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.ReassignVeterinarian(c.Request.Context(), c.Param("id"), dto, tenantID, userID)
+}
+
+// DecideRescheduleRequest approves or declines an owner's reschedule request
+// @Summary Decide reschedule request
+// @Description Approve or decline the new time an owner asked for. Approval checks the slot again and moves the appointment; either way the owner is notified
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Param decision body RescheduleDecisionDTO true "Approval and optional reason"
+// @Success 200 {object} AppointmentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/{id}/reschedule-request [patch]
+func (h *Handler) DecideRescheduleRequest(c *gin.Context) (any, error) {
+	var dto RescheduleDecisionDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.DecideRescheduleRequest(c.Request.Context(), c.Param("id"), dto, tenantID, userID)
+}
+
+// OffboardVeterinarian hands a departing vet's agenda over and removes them from the clinic
+// @Summary Offboard veterinarian
+// @Description Reassign every future appointment of a departing veterinarian to a replacement, or return them to the request queue when none is given, then revoke the vet's access to the clinic. Appointments that clash with the replacement's agenda are left unassigned and listed in conflicts
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Veterinarian user ID"
+// @Param offboard body OffboardVeterinarianDTO true "Replacement veterinarian and reason"
+// @Success 200 {object} OffboardResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/users/{id}/offboard [post]
+func (h *Handler) OffboardVeterinarian(c *gin.Context) (any, error) {
+	var dto OffboardVeterinarianDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	return h.service.OffboardVeterinarian(c.Request.Context(), c.Param("id"), dto, sharedMiddleware.GetTenantID(c), userID)
+}
+
 // GetStatusHistory gets the status history for an appointment
 // @Summary Get status history
 // @Description Get the status change history for an appointment
@@ -404,6 +579,49 @@ func (h *Handler) GetStatusHistory(c *gin.Context) (any, error) {
 	return history, nil
 }
 
+// GetConsent gets the consent of an appointment
+// @Summary Get appointment consent
+// @Description Get the current consent text for the appointment's procedure, whether it must be signed before the appointment starts and the consent already signed, if any
+// @Tags admin-appointments
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} AppointmentConsentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/{id}/consent [get]
+func (h *Handler) GetConsent(c *gin.Context) (any, error) {
+	return h.service.GetConsent(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+}
+
+// SignConsent records a consent signed at the clinic
+// @Summary Sign appointment consent
+// @Description Record the owner's signature of the consent for the appointment's procedure, captured at the clinic. template_version must be the version presented; signing fails with 409 if the text changed since
+// @Tags admin-appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Param consent body consents.SignConsentDTO true "Signed consent"
+// @Success 201 {object} AppointmentConsentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/{id}/consent [post]
+func (h *Handler) SignConsent(c *gin.Context) (any, error) {
+	var dto consents.SignConsentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	return h.service.SignConsent(c.Request.Context(), c.Param("id"), dto, sharedMiddleware.GetTenantID(c), userID)
+}
+
 // Mobile endpoints
 
 // RequestAppointment creates an appointment request from mobile
@@ -413,7 +631,7 @@ func (h *Handler) GetStatusHistory(c *gin.Context) (any, error) {
 // @Accept json
 // @Produce json
 // @Param appointment body MobileAppointmentRequestDTO true "Appointment request data"
-// @Success 201 {object} AppointmentResponse
+// @Success 201 {object} OwnerAppointmentResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Security MobileBearerAuth
@@ -440,6 +658,21 @@ func (h *Handler) RequestAppointment(c *gin.Context) (any, error) {
 	return appointment, nil
 }
 
+// GetBookingWindow returns the clinic's booking limits
+// @Summary Get booking window
+// @Description Get the minimum notice and maximum advance booking limits of the clinic
+// @Tags mobile-appointments
+// @Produce json
+// @Param type query string false "Appointment type key, to include its lead time"
+// @Success 200 {object} BookingWindowResponse
+// @Failure 401 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/booking-window [get]
+func (h *Handler) GetBookingWindow(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.GetBookingWindow(c.Request.Context(), tenantID, c.Query("type"))
+}
+
 // GetOwnerAppointments gets appointments for a specific owner
 // @Summary Get owner appointments
 // @Description Get appointments for the authenticated owner
@@ -448,7 +681,7 @@ func (h *Handler) RequestAppointment(c *gin.Context) (any, error) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
-// @Success 200 {object} PaginatedAppointmentsResponse
+// @Success 200 {object} PaginatedOwnerAppointmentsResponse
 // @Failure 400 {object} map[string]interface{}
 // @Security MobileBearerAuth
 // @Router /mobile/appointments [get]
@@ -471,6 +704,24 @@ func (h *Handler) GetOwnerAppointments(c *gin.Context) (any, error) {
 	return appointments, nil
 }
 
+// GetVetToday gets the authenticated vet's schedule for today
+// @Summary Get today's schedule
+// @Description Get the authenticated veterinarian's appointments for the current day in the clinic's timezone, ordered by time, with the number of upcoming appointments still waiting to be confirmed
+// @Tags mobile-vet
+// @Produce json
+// @Success 200 {object} VetTodayResponse
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /mobile/vet/today [get]
+func (h *Handler) GetVetToday(c *gin.Context) (any, error) {
+	vetID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	return h.service.GetVetToday(c.Request.Context(), vetID, sharedMiddleware.GetTenantID(c))
+}
+
 // GetOwnerAppointment gets a specific appointment for an owner
 // @Summary Get owner appointment
 // @Description Get appointment details for the authenticated owner
@@ -479,7 +730,9 @@ func (h *Handler) GetOwnerAppointments(c *gin.Context) (any, error) {
 // @Produce json
 // @Param id path string true "Appointment ID"
 // @Param populate query bool false "Populate related data"
-// @Success 200 {object} AppointmentResponse
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} OwnerAppointmentResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Security MobileBearerAuth
@@ -504,7 +757,7 @@ func (h *Handler) GetOwnerAppointment(c *gin.Context) (any, error) {
 		return nil, err
 	}
 
-	return appointment, nil
+	return &httpx.Tagged{Data: appointment, UpdatedAt: appointment.UpdatedAt}, nil
 }
 
 // CancelOwnerAppointment cancels an appointment by the owner
@@ -515,7 +768,7 @@ func (h *Handler) GetOwnerAppointment(c *gin.Context) (any, error) {
 // @Produce json
 // @Param id path string true "Appointment ID"
 // @Param cancel body AppointmentCancelDTO true "Cancellation reason"
-// @Success 200 {object} AppointmentResponse
+// @Success 200 {object} OwnerAppointmentResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -547,3 +800,332 @@ func (h *Handler) CancelOwnerAppointment(c *gin.Context) (any, error) {
 
 	return appointment, nil
 }
+
+// RescheduleOwnerAppointment moves an appointment by the owner
+// @Summary Reschedule appointment
+// @Description Move a scheduled or confirmed appointment to a new time. The time must be within business hours, the booking window and free for the vet and the patient. Clinics that allow owner self-service apply it right away; otherwise it is stored as a request for staff approval. Not allowed within the clinic's reschedule cutoff
+// @Tags mobile-appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Param reschedule body OwnerRescheduleDTO true "New time and optional reason"
+// @Success 200 {object} OwnerAppointmentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/{id}/reschedule [patch]
+func (h *Handler) RescheduleOwnerAppointment(c *gin.Context) (any, error) {
+	var dto OwnerRescheduleDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("owner_id", "invalid owner ID format")
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.RescheduleOwnerAppointment(c.Request.Context(), c.Param("id"), dto, tenantID, ownerID)
+}
+
+// AcknowledgeOwnerAppointment acknowledges an appointment reminder
+// @Summary Acknowledge appointment reminder
+// @Description Record that the owner saw the reminder (deep link from the push notification). If the clinic allows it, a scheduled appointment is confirmed as well
+// @Tags mobile-appointments
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} OwnerAppointmentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/{id}/acknowledge [post]
+func (h *Handler) AcknowledgeOwnerAppointment(c *gin.Context) (any, error) {
+	ownerID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("owner_id", "invalid owner ID format")
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.AcknowledgeAppointment(c.Request.Context(), c.Param("id"), tenantID, ownerID)
+}
+
+// AddOwnerAttachment attaches a file to an appointment from mobile
+// @Summary Add appointment attachment
+// @Description Link an uploaded file, such as a photo of a wound, to one of the owner's upcoming appointments so the vet can see it before the visit. Images only, up to 10 MB each and the clinic's attachment limit per appointment. The assigned vet is notified
+// @Tags mobile-appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Param attachment body AddAttachmentDTO true "Uploaded file"
+// @Success 201 {object} OwnerAppointmentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/{id}/attachments [post]
+func (h *Handler) AddOwnerAttachment(c *gin.Context) (any, error) {
+	var dto AddAttachmentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("owner_id", "invalid owner ID format")
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.AddOwnerAttachment(c.Request.Context(), c.Param("id"), dto, tenantID, ownerID)
+}
+
+// GetOwnerConsent gets the consent of an appointment from mobile
+// @Summary Get appointment consent
+// @Description Get the consent text the owner must read and sign for one of their appointments, and the consent already signed, if any
+// @Tags mobile-appointments
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} AppointmentConsentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/{id}/consent [get]
+func (h *Handler) GetOwnerConsent(c *gin.Context) (any, error) {
+	ownerID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("owner_id", "invalid owner ID format")
+	}
+
+	return h.service.GetOwnerConsent(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c), ownerID)
+}
+
+// SignOwnerConsent signs the consent of an appointment from mobile
+// @Summary Sign appointment consent
+// @Description Sign the consent for one of the owner's appointments. template_version must be the version presented; signing fails with 409 if the text changed since
+// @Tags mobile-appointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment ID"
+// @Param consent body consents.SignConsentDTO true "Signed consent"
+// @Success 201 {object} AppointmentConsentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/{id}/consent [post]
+func (h *Handler) SignOwnerConsent(c *gin.Context) (any, error) {
+	var dto consents.SignConsentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("owner_id", "invalid owner ID format")
+	}
+
+	return h.service.SignOwnerConsent(c.Request.Context(), c.Param("id"), dto, sharedMiddleware.GetTenantID(c), ownerID)
+}
+
+// GetStatusSubscription gets the caller's status subscription
+// @Summary Get my status subscription
+// @Description Get which appointment status changes the current staff member is notified of
+// @Tags admin-appointments
+// @Produce json
+// @Success 200 {object} StatusSubscriptionResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/status-subscription [get]
+func (h *Handler) GetStatusSubscription(c *gin.Context) (any, error) {
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	return h.service.GetStatusSubscription(c.Request.Context(), userID, sharedMiddleware.GetTenantID(c))
+}
+
+// SetStatusSubscription subscribes the caller to appointment status changes
+// @Summary Subscribe to status changes
+// @Description Notify the current staff member of appointment status changes, narrowed by status, type and vet. Empty lists match any value, e.g. {"statuses":["cancelled"]} covers every cancellation. Replaces any previous subscription
+// @Tags admin-appointments
+// @Accept json
+// @Produce json
+// @Param subscription body StatusSubscriptionDTO true "Subscription filters"
+// @Success 200 {object} StatusSubscriptionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/status-subscription [put]
+func (h *Handler) SetStatusSubscription(c *gin.Context) (any, error) {
+	var dto StatusSubscriptionDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	return h.service.SetStatusSubscription(c.Request.Context(), dto, userID, sharedMiddleware.GetTenantID(c))
+}
+
+// DeleteStatusSubscription unsubscribes the caller
+// @Summary Unsubscribe from status changes
+// @Description Stop notifying the current staff member of appointment status changes
+// @Tags admin-appointments
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointments/status-subscription [delete]
+func (h *Handler) DeleteStatusSubscription(c *gin.Context) (any, error) {
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidationFailed("user_id", "invalid user ID format")
+	}
+
+	if err := h.service.DeleteStatusSubscription(c.Request.Context(), userID, sharedMiddleware.GetTenantID(c)); err != nil {
+		return nil, err
+	}
+	return gin.H{"message": "Status subscription deleted successfully"}, nil
+}
+
+// GetStatusWorkflow gets the clinic's appointment status workflow
+// @Summary Get status workflow
+// @Description Get the clinic's appointment statuses and allowed transitions. Clinics that have not configured their own get the built-in workflow
+// @Tags admin-appointment-workflow
+// @Produce json
+// @Success 200 {object} StatusWorkflowResponse
+// @Failure 401 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointment-workflow [get]
+func (h *Handler) GetStatusWorkflow(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.GetStatusWorkflow(c.Request.Context(), tenantID), nil
+}
+
+// UpdateStatusWorkflow replaces the clinic's appointment status workflow
+// @Summary Update status workflow
+// @Description Replace the clinic's statuses and allowed transitions. Built-in statuses must be kept; custom statuses must be reachable from a booking status and lead to a closing one, and can only be removed when no appointment is in them
+// @Tags admin-appointment-workflow
+// @Accept json
+// @Produce json
+// @Param workflow body UpdateStatusWorkflowDTO true "Statuses and transitions"
+// @Success 200 {object} StatusWorkflowResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointment-workflow [put]
+func (h *Handler) UpdateStatusWorkflow(c *gin.Context) (any, error) {
+	var dto UpdateStatusWorkflowDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.UpdateStatusWorkflow(c.Request.Context(), dto, tenantID)
+}
+
+// ListAppointmentTypes lists the clinic's appointment types
+// @Summary List appointment types
+// @Description List the clinic's appointment types, including inactive ones. A clinic without its own list gets the built-in types stored as a starting point
+// @Tags admin-appointment-types
+// @Produce json
+// @Success 200 {array} AppointmentTypeResponse
+// @Failure 401 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointment-types [get]
+func (h *Handler) ListAppointmentTypes(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.ListAppointmentTypes(c.Request.Context(), tenantID, false), nil
+}
+
+// CreateAppointmentType adds an appointment type
+// @Summary Create appointment type
+// @Description Add an appointment type with its default duration, calendar color, vet requirement and optional deposit
+// @Tags admin-appointment-types
+// @Accept json
+// @Produce json
+// @Param type body CreateAppointmentTypeDTO true "Appointment type data"
+// @Success 201 {object} AppointmentTypeResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointment-types [post]
+func (h *Handler) CreateAppointmentType(c *gin.Context) (any, error) {
+	var dto CreateAppointmentTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.CreateAppointmentType(c.Request.Context(), dto, tenantID)
+}
+
+// UpdateAppointmentType updates an appointment type
+// @Summary Update appointment type
+// @Description Update an appointment type. The key cannot be changed; deactivated types are kept on existing appointments but cannot be booked
+// @Tags admin-appointment-types
+// @Accept json
+// @Produce json
+// @Param id path string true "Appointment type ID"
+// @Param type body UpdateAppointmentTypeDTO true "Updated appointment type data"
+// @Success 200 {object} AppointmentTypeResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointment-types/{id} [put]
+func (h *Handler) UpdateAppointmentType(c *gin.Context) (any, error) {
+	var dto UpdateAppointmentTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.UpdateAppointmentType(c.Request.Context(), c.Param("id"), dto, tenantID)
+}
+
+// DeleteAppointmentType deletes an appointment type
+// @Summary Delete appointment type
+// @Description Soft delete an appointment type. Appointments already booked with it keep their type
+// @Tags admin-appointment-types
+// @Produce json
+// @Param id path string true "Appointment type ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/appointment-types/{id} [delete]
+func (h *Handler) DeleteAppointmentType(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	if err := h.service.DeleteAppointmentType(c.Request.Context(), c.Param("id"), tenantID); err != nil {
+		return nil, err
+	}
+	return gin.H{"message": "Appointment type deleted successfully"}, nil
+}
+
+// GetOwnerAppointmentTypes lists the types an owner can request
+// @Summary List bookable appointment types
+// @Description List the clinic's active appointment types
+// @Tags mobile-appointments
+// @Produce json
+// @Success 200 {array} AppointmentTypeResponse
+// @Failure 401 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/appointments/types [get]
+func (h *Handler) GetOwnerAppointmentTypes(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	return h.service.ListAppointmentTypes(c.Request.Context(), tenantID, true), nil
+}
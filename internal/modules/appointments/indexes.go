@@ -58,6 +58,14 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 				{"scheduled_at", -1},
 			},
 		},
+		// Index for deposit webhooks and the deposit expiry job
+		{
+			Keys:    bson.D{{"deposit.link_id", 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{"status", 1}, {"deposit.expires_at", 1}},
+		},
 		// Index for soft delete filtering
 		{
 			Keys:    bson.D{{"deleted_at", 1}},
@@ -67,6 +75,11 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		{
 			Keys: bson.D{{"appointment_id", 1}, {"created_at", -1}},
 		},
+		// Appointments whose owner notification has not reached the outbox yet
+		{
+			Keys:    bson.D{{"pending_notifications.dedupe_key", 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
@@ -91,5 +104,44 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		return fmt.Errorf("failed to create transition indexes: %w", err)
 	}
 
+	// One live type per key and clinic; deleted ones keep their deleted_at
+	typeIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "key", Value: 1}, {Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = db.Collection("appointment_types").Indexes().CreateMany(ctx, typeIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create appointment type indexes: %w", err)
+	}
+
+	// One status workflow per clinic
+	workflowIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = db.Collection("appointment_workflows").Indexes().CreateMany(ctx, workflowIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create appointment workflow indexes: %w", err)
+	}
+
+	// One status subscription per staff member and clinic
+	subscriptionIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = db.Collection("appointment_status_subscriptions").Indexes().CreateMany(ctx, subscriptionIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create status subscription indexes: %w", err)
+	}
+
 	return nil
 }
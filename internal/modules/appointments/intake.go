@@ -0,0 +1,46 @@
+package appointments
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// checkIntake enforces the clinic's optional rule that a new client fills in
+// the intake form before their first appointment is confirmed. Owners with a
+// completed visit at the clinic are not new clients. Lookup failures let the
+// confirmation through rather than blocking the front desk.
+func (s *Service) checkIntake(ctx context.Context, appointment *Appointment) error {
+	t, err := s.tenantRepo.FindByID(ctx, appointment.TenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping intake check", "tenant_id", appointment.TenantID.Hex(), "error", err)
+		return nil
+	}
+	if !t.Settings.RequireIntakeForFirstAppointment {
+		return nil
+	}
+
+	owner, err := s.ownerRepo.FindByID(ctx, appointment.OwnerID.Hex())
+	if err != nil {
+		slog.Warn("failed to load owner, skipping intake check", "owner_id", appointment.OwnerID.Hex(), "error", err)
+		return nil
+	}
+	if !owner.IntakeCompletedAt[appointment.TenantID.Hex()].IsZero() {
+		return nil
+	}
+
+	ownerID := appointment.OwnerID
+	_, total, err := s.repo.List(ctx, appointmentFilters{
+		OwnerID: &ownerID,
+		Status:  []string{AppointmentStatusCompleted},
+	}, appointment.TenantID, pagination.Params{Limit: 1})
+	if err != nil {
+		slog.Warn("failed to count owner visits, skipping intake check", "owner_id", ownerID.Hex(), "error", err)
+		return nil
+	}
+	if total > 0 {
+		return nil
+	}
+	return ErrIntakeRequired
+}
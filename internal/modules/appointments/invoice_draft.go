@@ -0,0 +1,49 @@
+package appointments
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/invoices"
+)
+
+// InvoiceDrafter bills completed appointments, implemented by invoices.Service
+type InvoiceDrafter interface {
+	DraftForAppointment(ctx context.Context, tenantID, createdBy primitive.ObjectID, draft *invoices.AppointmentDraft) (*invoices.Invoice, error)
+}
+
+// draftCompletionInvoice leaves a draft invoice for the owner of a completed
+// appointment when the clinic opted in, billing the fee of its type and the
+// products dispensed during it. Billing never blocks completing the visit, so
+// failures are only logged.
+func (s *Service) draftCompletionInvoice(ctx context.Context, appointment *Appointment, tenantID, changedBy primitive.ObjectID) {
+	if s.invoices == nil {
+		return
+	}
+
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping invoice draft", "tenant_id", tenantID.Hex(), "error", err)
+		return
+	}
+	if !t.Settings.AutoDraftInvoiceOnCompletion {
+		return
+	}
+
+	draft := &invoices.AppointmentDraft{
+		AppointmentID: appointment.ID,
+		OwnerID:       appointment.OwnerID,
+		PatientID:     appointment.PatientID,
+	}
+	// The appointment keeps its type even after the clinic deactivates it
+	if apptType, err := s.resolveAppointmentType(ctx, tenantID, appointment.Type, appointment.Type); err == nil {
+		draft.TypeName = apptType.Name
+		draft.Fee = apptType.Fee
+	}
+
+	if _, err := s.invoices.DraftForAppointment(ctx, tenantID, changedBy, draft); err != nil {
+		slog.Error("failed to draft appointment invoice", "appointment_id", appointment.ID.Hex(), "error", err)
+	}
+}
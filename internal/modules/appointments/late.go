@@ -0,0 +1,55 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WarningOverrunsNext flags a shifted start that runs into the vet's next appointment
+const WarningOverrunsNext = "OVERRUNS_NEXT_APPOINTMENT"
+
+// shiftLateStart moves the effective start of a late appointment to now when
+// staff ask for it, as long as the delay is within the clinic's tolerance.
+// ScheduledAt is left untouched. Only the vet's immediately following
+// appointment is checked: an overrun does not block the start, it comes back
+// as a warning so staff can decide how to catch up. It returns the minutes
+// shifted, 0 when the appointment is not late.
+func (s *Service) shiftLateStart(ctx context.Context, appointment *Appointment, now time.Time, updates bson.M, tenantID primitive.ObjectID) (int, []AppointmentWarning, error) {
+	late := int(now.Sub(appointment.ScheduledAt) / time.Minute)
+	if late <= 0 {
+		return 0, nil, nil
+	}
+
+	tolerance := 0
+	if t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex()); err == nil {
+		tolerance = t.Settings.LateArrivalToleranceMinutes
+	}
+	if late > tolerance {
+		return 0, nil, ErrLateBeyondTolerance(late, tolerance)
+	}
+	updates["effective_start_at"] = now
+	if appointment.VeterinarianID.IsZero() {
+		return late, nil, nil
+	}
+
+	next, err := s.repo.FindNextForVeterinarian(ctx, appointment.VeterinarianID, appointment.ScheduledAt, appointment.ID, tenantID)
+	if err != nil || next == nil {
+		return late, nil, err
+	}
+
+	end := now.Add(time.Duration(appointment.Duration) * time.Minute)
+	if !end.After(next.ScheduledAt) {
+		return late, nil, nil
+	}
+	overlap := max(int(end.Sub(next.ScheduledAt).Round(time.Minute)/time.Minute), 1)
+	return late, []AppointmentWarning{{
+		Code:              WarningOverrunsNext,
+		Message:           fmt.Sprintf("Shifted start overruns the next appointment by %d minutes", overlap),
+		NextAppointmentID: next.ID.Hex(),
+		OverlapMinutes:    overlap,
+	}}, nil
+}
@@ -0,0 +1,101 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// DepositMigration reports what MigrateDepositsToMinorUnits converted
+type DepositMigration struct {
+	Tenants      int64
+	Types        int64
+	Appointments int64
+}
+
+// MigrateDepositsToMinorUnits converts deposit amounts stored as decimal
+// floats into integer minor units: the per-type map in the tenant settings
+// and the deposits of appointment types, in the clinic's currency, and the
+// deposits held on appointments, in the currency they were charged in. Rules
+// are stamped with the currency used. Only amounts still stored as doubles
+// are touched, so it is safe to run more than once. It must run before
+// deploying code that reads deposits as integers.
+func MigrateDepositsToMinorUnits(ctx context.Context, db *database.MongoDB) (*DepositMigration, error) {
+	cursor, err := db.Collection("tenants").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var tenants []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Currency string             `bson:"currency"`
+		Settings struct {
+			AppointmentDeposits map[string]bson.M `bson:"appointment_deposits"`
+		} `bson:"settings"`
+	}
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, err
+	}
+
+	types := db.Collection("appointment_types")
+	result := &DepositMigration{}
+
+	for _, t := range tenants {
+		currency := money.Normalize(t.Currency)
+
+		// The settings map is small, so its doubles are converted here
+		set := bson.M{}
+		for key, rule := range t.Settings.AppointmentDeposits {
+			if amount, ok := rule["amount"].(float64); ok {
+				set["settings.appointment_deposits."+key+".amount"] = money.FromMajor(amount, currency)
+				set["settings.appointment_deposits."+key+".currency"] = currency
+			}
+		}
+		if len(set) > 0 {
+			res, err := db.Collection("tenants").UpdateOne(ctx, bson.M{"_id": t.ID}, bson.M{"$set": set})
+			if err != nil {
+				return result, fmt.Errorf("tenant %s deposits: %w", t.ID.Hex(), err)
+			}
+			result.Tenants += res.ModifiedCount
+		}
+
+		res, err := types.UpdateMany(ctx,
+			bson.M{"tenant_id": t.ID, "deposit.amount": bson.M{"$type": "double"}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{
+				"deposit.amount":   money.MinorUnitsExpr("$deposit.amount", money.Factor(currency)),
+				"deposit.currency": currency,
+			}}}},
+		)
+		if err != nil {
+			return result, fmt.Errorf("tenant %s appointment types: %w", t.ID.Hex(), err)
+		}
+		result.Types += res.ModifiedCount
+	}
+
+	appointments := db.Collection("appointments")
+	held := bson.M{"deposit.amount": bson.M{"$type": "double"}}
+	currencies, err := appointments.Distinct(ctx, "deposit.currency", held)
+	if err != nil {
+		return result, err
+	}
+	for _, v := range currencies {
+		currency, _ := v.(string)
+		res, err := appointments.UpdateMany(ctx,
+			bson.M{"deposit.currency": currency, "deposit.amount": bson.M{"$type": "double"}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{
+				"deposit.amount": money.MinorUnitsExpr("$deposit.amount", money.Factor(currency)),
+			}}}},
+		)
+		if err != nil {
+			return result, fmt.Errorf("appointment deposits %s: %w", currency, err)
+		}
+		result.Appointments += res.ModifiedCount
+	}
+
+	return result, nil
+}
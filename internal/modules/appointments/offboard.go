@@ -0,0 +1,156 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/users"
+)
+
+// offboardHorizon bounds how far ahead a departing vet's agenda is searched
+const offboardHorizon = 2 * 365 * 24 * time.Hour
+
+// OffboardVeterinarian hands a departing vet's future agenda over and removes
+// them from the clinic. With a replacement each appointment is moved to them
+// unless it clashes with their agenda; clashes, and every appointment when no
+// replacement is given, go back to the request queue unassigned. Clashes are
+// reported so the front desk can place them by hand.
+func (s *Service) OffboardVeterinarian(ctx context.Context, id string, dto OffboardVeterinarianDTO, tenantID, changedBy primitive.ObjectID) (*OffboardResponse, error) {
+	vetID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid user ID format")
+	}
+	if _, err := s.findTenantVet(ctx, vetID, tenantID); err != nil {
+		return nil, err
+	}
+
+	var replacement *users.User
+	if dto.ReplacementVeterinarianID != "" {
+		replacementID, err := primitive.ObjectIDFromHex(dto.ReplacementVeterinarianID)
+		if err != nil || replacementID.IsZero() {
+			return nil, ErrValidationFailed("replacement_veterinarian_id", "invalid veterinarian ID format")
+		}
+		if replacementID == vetID {
+			return nil, ErrValidationFailed("replacement_veterinarian_id", "replacement must be a different veterinarian")
+		}
+		if replacement, err = s.findTenantVet(ctx, replacementID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	agenda, err := s.repo.FindByVeterinarian(ctx, vetID, now, now.Add(offboardHorizon), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &OffboardResponse{VeterinarianID: vetID.Hex(), Conflicts: []OffboardConflict{}}
+	for i := range agenda {
+		appointment := &agenda[i]
+		if !appointment.IsActive() {
+			continue
+		}
+
+		target := primitive.NilObjectID
+		if replacement != nil {
+			hasConflict, err := s.repo.CheckConflicts(ctx, replacement.ID, appointment.ScheduledAt, appointment.Duration, &appointment.ID, tenantID)
+			if err != nil {
+				return nil, err
+			}
+			if !hasConflict && s.checkOnDuty(ctx, tenantID, replacement.ID, appointment.ScheduledAt, appointment.Duration) != nil {
+				hasConflict = true
+			}
+			if hasConflict {
+				resp.Conflicts = append(resp.Conflicts, OffboardConflict{
+					AppointmentID: appointment.ID.Hex(),
+					ScheduledAt:   appointment.ScheduledAt,
+					Reason:        ErrVeterinarianNotAvailable.Code,
+				})
+			} else {
+				target = replacement.ID
+			}
+		}
+
+		if err := s.handOver(ctx, appointment, target, dto.Reason, changedBy); err != nil {
+			return nil, err
+		}
+		if target.IsZero() {
+			resp.Unassigned++
+		} else {
+			resp.Reassigned++
+		}
+	}
+
+	if err := s.userRepo.RemoveTenant(ctx, vetID.Hex(), tenantID); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Veterinarian offboarded: %d appointments reassigned, %d unassigned. Reason: %s", resp.Reassigned, resp.Unassigned, dto.Reason)
+	if err := s.auditLog.LogUserAction(ctx, tenantID, changedBy, audit.EventUserUpdated, "offboard", description); err != nil {
+		return nil, err
+	}
+
+	if replacement != nil && resp.Reassigned > 0 {
+		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+			UserID:   replacement.ID.Hex(),
+			TenantID: tenantID.Hex(),
+			Type:     notifications.TypeStaffNewAppointment,
+			Title:    "Citas reasignadas a ti",
+			Body:     fmt.Sprintf("Se te asignaron %d citas de un veterinario que dejó la clínica", resp.Reassigned),
+			Data:     map[string]string{"veterinarian_id": vetID.Hex()},
+		})
+	}
+
+	return resp, nil
+}
+
+// findTenantVet loads a staff member and checks they still work at the clinic
+func (s *Service) findTenantVet(ctx context.Context, id, tenantID primitive.ObjectID) (*users.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id.Hex())
+	if err != nil {
+		return nil, ErrVeterinarianNotFound
+	}
+	for _, t := range user.TenantIds {
+		if t == tenantID {
+			return user, nil
+		}
+	}
+	return nil, ErrVeterinarianNotFound
+}
+
+// handOver moves one appointment to target, or to the request queue when
+// target is the nil ID, and records the change in its history
+func (s *Service) handOver(ctx context.Context, appointment *Appointment, target primitive.ObjectID, reason string, changedBy primitive.ObjectID) error {
+	now := time.Now()
+	previousVetID := appointment.VeterinarianID
+	updates := bson.M{
+		"veterinarian_id": target,
+		"updated_at":      now,
+	}
+	if appointment.OriginalVeterinarianID == nil {
+		updates["original_veterinarian_id"] = previousVetID
+	}
+
+	if err := s.repo.Update(ctx, appointment.ID, updates, appointment.TenantID); err != nil {
+		return err
+	}
+
+	s.repo.CreateStatusTransition(ctx, &AppointmentStatusTransition{
+		TenantID:           appointment.TenantID,
+		AppointmentID:      appointment.ID,
+		FromStatus:         appointment.Status,
+		ToStatus:           appointment.Status,
+		ChangedBy:          changedBy,
+		Reason:             "Veterinario dado de baja: " + reason,
+		FromVeterinarianID: &previousVetID,
+		ToVeterinarianID:   &target,
+		CreatedAt:          now,
+	})
+	return nil
+}
@@ -0,0 +1,69 @@
+package appointments
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+)
+
+// creationNotification is the owner notification of a new appointment. The
+// appointment ID is fresh, so the key identifies the event on its own.
+func (s *Service) creationNotification(appointment *Appointment, autoConfirmed bool, patientName string) *notifications.PendingNotification {
+	dto := &notifications.SendDTO{
+		OwnerID:  appointment.OwnerID.Hex(),
+		TenantID: appointment.TenantID.Hex(),
+		Type:     notifications.TypeAppointmentReminder,
+		Template: notifications.TemplateAppointmentScheduled,
+		Vars:     map[string]string{"patient_name": patientName},
+		Times:    map[string]time.Time{"date": appointment.ScheduledAt},
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex(), "patient_id": appointment.PatientID.Hex()},
+		SendPush: true,
+	}
+	source := "appointment_created"
+	if autoConfirmed {
+		dto.Type = notifications.TypeAppointmentConfirmed
+		dto.Template = notifications.TemplateAppointmentAutoConfirmed
+		source = "appointment_confirmed"
+	}
+	return pendingNotification(source+":"+appointment.ID.Hex(), source, dto)
+}
+
+// statusNotification is the owner notification of a status change, or nil
+// when the change does not notify the owner. An appointment can reach the
+// same status more than once through a clinic workflow, so the key includes
+// the time of the change.
+func (s *Service) statusNotification(appointment *Appointment, effective string, dto UpdateStatusDTO, at time.Time) *notifications.PendingNotification {
+	var source string
+	send := &notifications.SendDTO{
+		OwnerID:  appointment.OwnerID.Hex(),
+		TenantID: appointment.TenantID.Hex(),
+		Times:    map[string]time.Time{"date": appointment.ScheduledAt},
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
+		SendPush: true,
+	}
+	switch {
+	case effective == AppointmentStatusConfirmed:
+		source = "appointment_confirmed"
+		send.Type = notifications.TypeAppointmentConfirmed
+		send.Template = notifications.TemplateAppointmentConfirmed
+	case dto.Status == AppointmentStatusCancelled:
+		source = "appointment_cancelled"
+		send.Type = notifications.TypeAppointmentCancelled
+		send.Template = notifications.TemplateAppointmentCancelled
+		send.Vars = map[string]string{"reason": dto.Reason}
+	default:
+		return nil
+	}
+	return pendingNotification(fmt.Sprintf("%s:%s:%d", source, appointment.ID.Hex(), at.UnixNano()), source, send)
+}
+
+func pendingNotification(dedupeKey, source string, dto *notifications.SendDTO) *notifications.PendingNotification {
+	pending, err := notifications.NewPendingNotification(dedupeKey, source, dto)
+	if err != nil {
+		slog.Error("failed to prepare owner notification", "dedupe_key", dedupeKey, "error", err)
+		return nil
+	}
+	return &pending
+}
@@ -0,0 +1,60 @@
+package appointments
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// ownerModifiable reports whether the owner may cancel or reschedule the
+// appointment from the app. A staff override on the appointment wins;
+// otherwise appointments of an owner-locked type, such as surgery, must be
+// changed through the clinic.
+func ownerModifiable(appointment *Appointment, types []AppointmentTypeConfig) bool {
+	if appointment.OwnerModifiable != nil {
+		return *appointment.OwnerModifiable
+	}
+	for _, t := range types {
+		if t.Key == appointment.Type {
+			return !t.OwnerLocked
+		}
+	}
+	return true
+}
+
+// checkOwnerCanModify rejects owner cancels and reschedules of locked appointments
+func (s *Service) checkOwnerCanModify(ctx context.Context, appointment *Appointment) error {
+	if !ownerModifiable(appointment, s.appointmentTypes(ctx, appointment.TenantID)) {
+		return ErrOwnerChangeLocked
+	}
+	return nil
+}
+
+// ownerAppointments projects a page of appointments for the owner app and
+// sets OwnerModifiable, so the app can hide the cancel and reschedule
+// actions of locked appointments
+func (s *Service) ownerAppointments(ctx context.Context, tenantID primitive.ObjectID, appointments []Appointment, params pagination.Params, total int64) *PaginatedOwnerAppointmentsResponse {
+	types := s.appointmentTypes(ctx, tenantID)
+	data := make([]OwnerAppointmentResponse, len(appointments))
+	for i := range appointments {
+		resp := appointments[i].ToOwnerResponse()
+		modifiable := ownerModifiable(&appointments[i], types)
+		resp.OwnerModifiable = &modifiable
+		data[i] = *resp
+	}
+	return &PaginatedOwnerAppointmentsResponse{
+		Data:       data,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}
+}
+
+// ownerView projects a single appointment, possibly populated, for the
+// owner app and sets OwnerModifiable
+func (s *Service) ownerView(ctx context.Context, appointment *Appointment, full *AppointmentResponse) *OwnerAppointmentResponse {
+	resp := ownerProjection(appointment, full)
+	modifiable := ownerModifiable(appointment, s.appointmentTypes(ctx, appointment.TenantID))
+	resp.OwnerModifiable = &modifiable
+	return resp
+}
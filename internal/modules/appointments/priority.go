@@ -0,0 +1,124 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// PriorityTriage is the calendar style key of an owner request that asked for
+// more priority than owners may set and is waiting for staff to triage it
+const PriorityTriage = "triage"
+
+// priorityRank orders the priority levels. What each level does is set per
+// clinic in tenant.PrioritySettings, as the lowest level a given effect
+// applies from:
+//   - BypassNoticeFrom: booking inside the minimum notice is allowed
+//   - UrgentAlertFrom: staff notifications about the appointment are urgent
+//   - QueueFirstFrom: waiting patients go ahead of the day's queue
+//   - OwnerMaxPriority: the highest level owners can set from the app
+var priorityRank = map[string]int{
+	AppointmentPriorityLow:       0,
+	AppointmentPriorityNormal:    1,
+	AppointmentPriorityHigh:      2,
+	AppointmentPriorityEmergency: 3,
+}
+
+// priorityAtLeast reports whether priority reaches threshold. A disabled
+// threshold is never reached and an empty priority counts as normal.
+func priorityAtLeast(priority, threshold string) bool {
+	minRank, ok := priorityRank[threshold]
+	if !ok {
+		return false
+	}
+	if priority == "" {
+		priority = AppointmentPriorityNormal
+	}
+	return priorityRank[priority] >= minRank
+}
+
+// prioritySettings loads the clinic's priority effects. Lookup failures fall
+// back to the defaults.
+func (s *Service) prioritySettings(ctx context.Context, tenantID primitive.ObjectID) tenant.PrioritySettings {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, using default priority settings", "tenant_id", tenantID.Hex(), "error", err)
+		return tenant.PrioritySettings{}.Effective()
+	}
+	return t.Settings.Priority.Effective()
+}
+
+// ownerPriority caps the priority an owner asked for at what the clinic lets
+// owners set. Anything above comes back as requested so staff can triage it.
+func ownerPriority(settings tenant.PrioritySettings, asked string) (priority, requested string) {
+	if asked == "" {
+		return AppointmentPriorityNormal, ""
+	}
+	if priorityAtLeast(settings.OwnerMaxPriority, asked) {
+		return asked, ""
+	}
+	return settings.OwnerMaxPriority, asked
+}
+
+// calendarPriority is the priority key the calendar is colored by
+func calendarPriority(priority, requested string) string {
+	if requested != "" {
+		return PriorityTriage
+	}
+	return priority
+}
+
+// sortCheckInQueue moves patients still waiting whose priority reaches
+// QueueFirstFrom to the front, most urgent first, keeping the booked order
+// otherwise. Visits already started or closed keep their place.
+func sortCheckInQueue(items []VetTodayAppointment, settings tenant.PrioritySettings) {
+	first := func(a VetTodayAppointment) bool {
+		waiting := a.Status == AppointmentStatusScheduled || a.Status == AppointmentStatusConfirmed
+		return waiting && !a.CheckedIn && priorityAtLeast(a.Priority, settings.QueueFirstFrom)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		fi, fj := first(items[i]), first(items[j])
+		if fi != fj {
+			return fi
+		}
+		if fi {
+			return priorityRank[items[i].Priority] > priorityRank[items[j].Priority]
+		}
+		return false
+	})
+}
+
+// urgentTitle marks the title of an urgent staff notification
+func urgentTitle(title string, urgent bool) string {
+	if urgent {
+		return "Urgente: " + title
+	}
+	return title
+}
+
+// notifyEscalation tells the assigned vet when staff raise an appointment to
+// an urgent priority
+func (s *Service) notifyEscalation(ctx context.Context, before, after *Appointment) {
+	if after.VeterinarianID.IsZero() {
+		return
+	}
+	threshold := s.prioritySettings(ctx, after.TenantID).UrgentAlertFrom
+	if !priorityAtLeast(after.Priority, threshold) || priorityAtLeast(before.Priority, threshold) {
+		return
+	}
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   after.VeterinarianID.Hex(),
+		TenantID: after.TenantID.Hex(),
+		Type:     notifications.TypeStaffAppointmentStatus,
+		Title:    urgentTitle("Prioridad de cita elevada", true),
+		Body:     fmt.Sprintf("La cita del %s pasó a prioridad %s", after.ScheduledAt.Format("02/01/2006 15:04"), after.Priority),
+		Data:     map[string]string{"appointment_id": after.ID.Hex(), "priority": after.Priority},
+		Urgent:   true,
+	})
+}
@@ -2,6 +2,7 @@ package appointments
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -19,6 +20,7 @@ type AppointmentRepository interface {
 	Create(ctx context.Context, appointment *Appointment) error
 	FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error)
 	List(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
+	ForEach(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, fn func(*Appointment) error) error
 	Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
 	Delete(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
 
@@ -28,6 +30,9 @@ type AppointmentRepository interface {
 	FindByOwner(ctx context.Context, ownerID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
 	FindByVeterinarian(ctx context.Context, vetID primitive.ObjectID, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error)
 	CheckConflicts(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error)
+	CountRoomBookings(ctx context.Context, roomID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (int64, error)
+	CheckPatientConflicts(ctx context.Context, patientID primitive.ObjectID, from, to time.Time, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error)
+	FindNextForVeterinarian(ctx context.Context, vetID primitive.ObjectID, after time.Time, excludeID primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error)
 
 	// Status transitions
 	CreateStatusTransition(ctx context.Context, transition *AppointmentStatusTransition) error
@@ -35,25 +40,47 @@ type AppointmentRepository interface {
 
 	// Analytics and reporting
 	CountByStatus(ctx context.Context, status string, tenantID primitive.ObjectID) (int64, error)
+	CountPendingForVeterinarian(ctx context.Context, vetID primitive.ObjectID, from time.Time, tenantID primitive.ObjectID) (int64, error)
 	FindUpcoming(ctx context.Context, tenantID primitive.ObjectID, hours int) ([]Appointment, error)
 
 	// Background jobs
 	FindUnconfirmedBefore(ctx context.Context, before time.Time) ([]Appointment, error)
+	FindUnassigned(ctx context.Context, tenantID primitive.ObjectID, from, to time.Time) ([]Appointment, error)
+	FindStaleActive(ctx context.Context, tenantID primitive.ObjectID, startedBefore time.Time) ([]Appointment, error)
+	MarkStaleAlerted(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error
+	FindOverrunning(ctx context.Context, tenantID primitive.ObjectID, startedBefore time.Time) ([]Appointment, error)
+	MarkDelayNotified(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, minutes int, at time.Time) error
+
+	// Deposits
+	FindForDeposit(ctx context.Context, id primitive.ObjectID) (*Appointment, error)
+	FindByDepositLinkID(ctx context.Context, linkID string) (*Appointment, error)
+	FindExpiredDeposits(ctx context.Context, now time.Time) ([]Appointment, error)
+	UpdateIfStatus(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, status string, updates bson.M) (bool, error)
+
+	// Attachments
+	AddAttachment(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, attachment AppointmentAttachment, limit int) (bool, error)
 
 	// Setup
 	EnsureIndexes(ctx context.Context) error
 }
 
+// Collection stores the appointments, along with the owner notifications
+// their writes leave pending for the outbox
+const Collection = "appointments"
+
 // appointmentRepository implements AppointmentRepository interface
 type appointmentRepository struct {
+	base                 *database.BaseRepository[Appointment]
 	collection           *mongo.Collection
 	transitionCollection *mongo.Collection
 }
 
 // NewAppointmentRepository creates a new appointment repository
 func NewAppointmentRepository(db *database.MongoDB) AppointmentRepository {
+	collection := db.Collection(Collection)
 	return &appointmentRepository{
-		collection:           db.Collection("appointments"),
+		base:                 database.NewBaseRepository[Appointment](collection, ErrAppointmentNotFound),
+		collection:           collection,
 		transitionCollection: db.Collection("appointment_status_transitions"),
 	}
 }
@@ -74,232 +101,130 @@ func (r *appointmentRepository) Create(ctx context.Context, appointment *Appoint
 
 // FindByID finds an appointment by ID
 func (r *appointmentRepository) FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
-	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
-
-	var appointment Appointment
-	err := r.collection.FindOne(ctx, filter).Decode(&appointment)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, ErrAppointmentNotFound
-		}
-		return nil, err
-	}
-
-	return &appointment, nil
+	return r.base.FindByID(ctx, id, tenantID)
 }
 
+// appointmentSortFields are the fields List accepts in ?sort=, all backed by an index
+var appointmentSortFields = []string{"scheduled_at", "status"}
+
 // List returns appointments with filters and pagination
 func (r *appointmentRepository) List(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error) {
-	filter := r.buildFilter(filters, tenantID)
-
-	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, filter)
+	sort, err := params.SortOrder(appointmentSortFields, bson.D{{Key: "scheduled_at", Value: 1}})
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Build options
 	opts := options.Find().
 		SetSkip(params.Skip).
 		SetLimit(params.Limit).
-		SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+		SetSort(sort)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	return r.base.Page(ctx, tenantID, r.buildFilter(filters, tenantID), opts)
+}
+
+// ForEach calls fn for every appointment matching the filters, oldest first,
+// decoding one document at a time so large exports stay out of memory
+func (r *appointmentRepository) ForEach(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, fn func(*Appointment) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, r.buildFilter(filters, tenantID), opts)
 	if err != nil {
-		return nil, 0, err
+		return err
 	}
 	defer cursor.Close(ctx)
 
-	var appointments []Appointment
-	if err = cursor.All(ctx, &appointments); err != nil {
-		return nil, 0, err
+	for cursor.Next(ctx) {
+		var appointment Appointment
+		if err := cursor.Decode(&appointment); err != nil {
+			return err
+		}
+		if err := fn(&appointment); err != nil {
+			return err
+		}
 	}
-
-	return appointments, total, nil
+	return cursor.Err()
 }
 
 // Update updates an appointment
 func (r *appointmentRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
-	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
-
-	updates["updated_at"] = time.Now()
-
-	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": updates})
-	if err != nil {
-		return err
-	}
-
-	if result.MatchedCount == 0 {
-		return ErrAppointmentNotFound
-	}
-
-	return nil
+	return r.base.Update(ctx, id, tenantID, updates)
 }
 
 // Delete soft deletes an appointment
 func (r *appointmentRepository) Delete(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
-	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"deleted_at": time.Now(),
-			"updated_at": time.Now(),
-		},
-	}
-
-	result, err := r.collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
-	}
-
-	if result.MatchedCount == 0 {
-		return ErrAppointmentNotFound
-	}
-
-	return nil
+	return r.base.SoftDelete(ctx, id, tenantID)
 }
 
 // FindByDateRange finds appointments within a date range
 func (r *appointmentRepository) FindByDateRange(ctx context.Context, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error) {
 	filter := bson.M{
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
 		"scheduled_at": bson.M{
 			"$gte": from,
 			"$lte": to,
 		},
 	}
-
-	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var appointments []Appointment
-	if err = cursor.All(ctx, &appointments); err != nil {
-		return nil, err
-	}
-
-	return appointments, nil
+	return r.base.List(ctx, tenantID, filter, options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}}))
 }
 
 // FindByPatient finds appointments for a specific patient with pagination
 func (r *appointmentRepository) FindByPatient(ctx context.Context, patientID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error) {
-	filter := bson.M{
-		"patient_id": patientID,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
-
-	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	opts := options.Find().
-		SetSkip(params.Skip).
-		SetLimit(params.Limit).
-		SetSort(bson.D{{Key: "scheduled_at", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-
-	var appointments []Appointment
-	if err = cursor.All(ctx, &appointments); err != nil {
-		return nil, 0, err
-	}
-
-	return appointments, total, nil
+	return r.base.Page(ctx, tenantID, bson.M{"patient_id": patientID}, newestFirst(params))
 }
 
 // FindByOwner finds appointments for a specific owner with pagination
 func (r *appointmentRepository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error) {
-	filter := bson.M{
-		"owner_id":   ownerID,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
-
-	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
+	return r.base.Page(ctx, tenantID, bson.M{"owner_id": ownerID}, newestFirst(params))
+}
 
-	opts := options.Find().
+// newestFirst pages through appointments from the latest scheduled
+func newestFirst(params pagination.Params) *options.FindOptions {
+	return options.Find().
 		SetSkip(params.Skip).
 		SetLimit(params.Limit).
 		SetSort(bson.D{{Key: "scheduled_at", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-
-	var appointments []Appointment
-	if err = cursor.All(ctx, &appointments); err != nil {
-		return nil, 0, err
-	}
-
-	return appointments, total, nil
 }
 
 // FindByVeterinarian finds appointments for a specific veterinarian within a date range
 func (r *appointmentRepository) FindByVeterinarian(ctx context.Context, vetID primitive.ObjectID, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error) {
 	filter := bson.M{
 		"veterinarian_id": vetID,
-		"tenant_id":       tenantID,
-		"deleted_at":      nil,
 		"scheduled_at": bson.M{
 			"$gte": from,
 			"$lte": to,
 		},
 	}
+	return r.base.List(ctx, tenantID, filter, options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}}))
+}
 
-	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+// CheckConflicts checks if there are conflicting appointments
+func (r *appointmentRepository) CheckConflicts(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+	filter := overlapFilter(scheduledAt, duration, excludeID, tenantID)
+	filter["veterinarian_id"] = vetID
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	count, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	defer cursor.Close(ctx)
 
-	var appointments []Appointment
-	if err = cursor.All(ctx, &appointments); err != nil {
-		return nil, err
-	}
+	return count > 0, nil
+}
 
-	return appointments, nil
+// CountRoomBookings counts the live appointments reserving the room that
+// overlap the slot, to compare against the room's capacity
+func (r *appointmentRepository) CountRoomBookings(ctx context.Context, roomID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (int64, error) {
+	filter := overlapFilter(scheduledAt, duration, excludeID, tenantID)
+	filter["room_id"] = roomID
+
+	return r.collection.CountDocuments(ctx, filter)
 }
 
-// CheckConflicts checks if there are conflicting appointments
-func (r *appointmentRepository) CheckConflicts(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+// overlapFilter matches the tenant's live, not cancelled appointments that
+// overlap [scheduledAt, scheduledAt+duration), except excludeID (for updates)
+func overlapFilter(scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) bson.M {
 	endTime := scheduledAt.Add(time.Duration(duration) * time.Minute)
 
 	filter := bson.M{
-		"veterinarian_id": vetID,
-		"tenant_id":       tenantID,
-		"deleted_at":      nil,
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
 		"status": bson.M{"$nin": []string{
 			AppointmentStatusCancelled,
 			AppointmentStatusNoShow,
@@ -339,7 +264,32 @@ func (r *appointmentRepository) CheckConflicts(ctx context.Context, vetID primit
 		},
 	}
 
-	// Exclude specific appointment if provided (for updates)
+	if excludeID != nil {
+		filter["_id"] = bson.M{"$ne": *excludeID}
+	}
+
+	return filter
+}
+
+// CheckPatientConflicts reports whether the patient has a live appointment,
+// with any vet, that overlaps [from, to)
+func (r *appointmentRepository) CheckPatientConflicts(ctx context.Context, patientID primitive.ObjectID, from, to time.Time, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"patient_id": patientID,
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+		"status": bson.M{"$nin": []string{
+			AppointmentStatusCancelled,
+			AppointmentStatusNoShow,
+		}},
+		"scheduled_at": bson.M{"$lt": to},
+		"$expr": bson.M{
+			"$gt": bson.A{
+				bson.M{"$add": bson.A{"$scheduled_at", bson.M{"$multiply": bson.A{"$duration", 60000}}}},
+				from,
+			},
+		},
+	}
 	if excludeID != nil {
 		filter["_id"] = bson.M{"$ne": *excludeID}
 	}
@@ -348,10 +298,38 @@ func (r *appointmentRepository) CheckConflicts(ctx context.Context, vetID primit
 	if err != nil {
 		return false, err
 	}
-
 	return count > 0, nil
 }
 
+// FindNextForVeterinarian returns the vet's first live appointment booked at
+// or after the given time, or nil when the rest of the agenda is free
+func (r *appointmentRepository) FindNextForVeterinarian(ctx context.Context, vetID primitive.ObjectID, after time.Time, excludeID primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+	filter := bson.M{
+		"veterinarian_id": vetID,
+		"tenant_id":       tenantID,
+		"_id":             bson.M{"$ne": excludeID},
+		"deleted_at":      nil,
+		// Not $in the built-in live statuses, so clinic-defined ones count too
+		"status": bson.M{"$nin": []string{
+			AppointmentStatusAwaitingDeposit,
+			AppointmentStatusCompleted,
+			AppointmentStatusCancelled,
+			AppointmentStatusNoShow,
+		}},
+		"scheduled_at": bson.M{"$gte": after},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+
+	var appointment Appointment
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&appointment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
 // CreateStatusTransition creates a status transition record
 func (r *appointmentRepository) CreateStatusTransition(ctx context.Context, transition *AppointmentStatusTransition) error {
 	result, err := r.transitionCollection.InsertOne(ctx, transition)
@@ -396,6 +374,20 @@ func (r *appointmentRepository) CountByStatus(ctx context.Context, status string
 	return r.collection.CountDocuments(ctx, filter)
 }
 
+// CountPendingForVeterinarian counts the vet's appointments from the given
+// time on that are still waiting to be confirmed
+func (r *appointmentRepository) CountPendingForVeterinarian(ctx context.Context, vetID primitive.ObjectID, from time.Time, tenantID primitive.ObjectID) (int64, error) {
+	filter := bson.M{
+		"veterinarian_id": vetID,
+		"tenant_id":       tenantID,
+		"status":          AppointmentStatusScheduled,
+		"deleted_at":      nil,
+		"scheduled_at":    bson.M{"$gte": from},
+	}
+
+	return r.collection.CountDocuments(ctx, filter)
+}
+
 // FindUpcoming finds upcoming appointments within specified hours
 func (r *appointmentRepository) FindUpcoming(ctx context.Context, tenantID primitive.ObjectID, hours int) ([]Appointment, error) {
 	now := time.Now()
@@ -430,15 +422,128 @@ func (r *appointmentRepository) FindUpcoming(ctx context.Context, tenantID primi
 	return appointments, nil
 }
 
-// FindUnconfirmedBefore finds unconfirmed appointments scheduled before a certain time
+// FindUnconfirmedBefore finds unconfirmed appointments created before a certain
+// time. Appointments whose reminder was never acknowledged come first (a
+// missing acknowledged_at sorts lowest), oldest first within each group.
 func (r *appointmentRepository) FindUnconfirmedBefore(ctx context.Context, before time.Time) ([]Appointment, error) {
 	filter := bson.M{
 		"status":     AppointmentStatusScheduled,
 		"created_at": bson.M{"$lt": before},
 		"deleted_at": nil,
+		// A paid deposit already commits the owner to the visit
+		"deposit.paid_at": nil,
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	opts := options.Find().SetSort(bson.D{{Key: "acknowledged_at", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []Appointment
+	if err := cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// FindStaleActive finds appointments still scheduled, confirmed or in
+// progress that were due to start before startedBefore and whose vet has not
+// been alerted yet. Callers still compare the end time, which depends on each
+// appointment's duration.
+func (r *appointmentRepository) FindStaleActive(ctx context.Context, tenantID primitive.ObjectID, startedBefore time.Time) ([]Appointment, error) {
+	filter := bson.M{
+		"tenant_id": tenantID,
+		"status": bson.M{"$in": []string{
+			AppointmentStatusScheduled,
+			AppointmentStatusConfirmed,
+			AppointmentStatusInProgress,
+		}},
+		"scheduled_at":     bson.M{"$lt": startedBefore},
+		"stale_alerted_at": nil,
+		"deleted_at":       nil,
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []Appointment
+	if err := cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// MarkStaleAlerted records that the vet was alerted about a stale appointment
+func (r *appointmentRepository) MarkStaleAlerted(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "tenant_id": tenantID},
+		bson.M{"$set": bson.M{"stale_alerted_at": at}},
+	)
+	return err
+}
+
+// FindOverrunning finds the in-progress appointments with a veterinarian
+// that were due to start before startedBefore. Callers still compare the end
+// time, which depends on each appointment's duration and late start.
+func (r *appointmentRepository) FindOverrunning(ctx context.Context, tenantID primitive.ObjectID, startedBefore time.Time) ([]Appointment, error) {
+	filter := bson.M{
+		"tenant_id":       tenantID,
+		"status":          AppointmentStatusInProgress,
+		"veterinarian_id": bson.M{"$ne": primitive.NilObjectID},
+		"scheduled_at":    bson.M{"$lt": startedBefore},
+		"deleted_at":      nil,
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []Appointment
+	if err := cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// MarkDelayNotified records the delay the owner was told to expect
+func (r *appointmentRepository) MarkDelayNotified(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, minutes int, at time.Time) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "tenant_id": tenantID},
+		bson.M{"$set": bson.M{"delay_notice_minutes": minutes, "delay_notified_at": at}},
+	)
+	return err
+}
+
+// FindUnassigned finds pending appointment requests in a date range that have no veterinarian yet
+func (r *appointmentRepository) FindUnassigned(ctx context.Context, tenantID primitive.ObjectID, from, to time.Time) ([]Appointment, error) {
+	filter := bson.M{
+		"tenant_id":       tenantID,
+		"veterinarian_id": primitive.NilObjectID,
+		"status":          bson.M{"$in": []string{AppointmentStatusScheduled, AppointmentStatusConfirmed}},
+		"deleted_at":      nil,
+		"scheduled_at": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -505,6 +610,10 @@ func (r *appointmentRepository) buildFilter(filters appointmentFilters, tenantID
 		filter["priority"] = *filters.Priority
 	}
 
+	if filters.TriagePending {
+		filter["requested_priority"] = bson.M{"$exists": true, "$ne": ""}
+	}
+
 	// Date range filter
 	if filters.DateFrom != nil || filters.DateTo != nil {
 		dateFilter := bson.M{}
@@ -519,3 +628,88 @@ func (r *appointmentRepository) buildFilter(filters appointmentFilters, tenantID
 
 	return filter
 }
+
+// FindForDeposit finds an appointment by ID in any tenant, for payment
+// webhooks that only carry the reference
+func (r *appointmentRepository) FindForDeposit(ctx context.Context, id primitive.ObjectID) (*Appointment, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "deposit": bson.M{"$exists": true}, "deleted_at": nil})
+}
+
+// FindByDepositLinkID finds the appointment whose deposit was charged through the given payment link
+func (r *appointmentRepository) FindByDepositLinkID(ctx context.Context, linkID string) (*Appointment, error) {
+	return r.findOne(ctx, bson.M{"deposit.link_id": linkID, "deleted_at": nil})
+}
+
+func (r *appointmentRepository) findOne(ctx context.Context, filter bson.M) (*Appointment, error) {
+	var appointment Appointment
+	if err := r.collection.FindOne(ctx, filter).Decode(&appointment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// FindExpiredDeposits finds appointments, across tenants, still awaiting a deposit past its deadline
+func (r *appointmentRepository) FindExpiredDeposits(ctx context.Context, now time.Time) ([]Appointment, error) {
+	filter := bson.M{
+		"status":             AppointmentStatusAwaitingDeposit,
+		"deposit.expires_at": bson.M{"$lte": now},
+		"deleted_at":         nil,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []Appointment
+	if err := cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// UpdateIfStatus applies updates only while the appointment is still in
+// status, so a webhook and the expiry job cannot both act on it. It reports
+// whether the update was applied.
+func (r *appointmentRepository) UpdateIfStatus(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, status string, updates bson.M) (bool, error) {
+	filter := bson.M{
+		"_id":        id,
+		"tenant_id":  tenantID,
+		"status":     status,
+		"deleted_at": nil,
+	}
+
+	updates["updated_at"] = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": updates})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// AddAttachment appends an attachment unless the appointment already holds
+// limit of them. The limit is part of the filter so concurrent uploads cannot
+// overshoot it; false means the appointment was full or no longer exists.
+func (r *appointmentRepository) AddAttachment(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, attachment AppointmentAttachment, limit int) (bool, error) {
+	filter := bson.M{
+		"_id":        id,
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+	}
+	filter[fmt.Sprintf("attachments.%d", limit-1)] = bson.M{"$exists": false}
+	update := bson.M{
+		"$push": bson.M{"attachments": attachment},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
@@ -0,0 +1,216 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+)
+
+// ownerReschedulePolicy reports whether owners may move their own
+// appointments and how close to the visit they may still do it. Lookup
+// failures leave the change to the staff, without a cutoff.
+func (s *Service) ownerReschedulePolicy(ctx context.Context, tenantID primitive.ObjectID) (selfService bool, cutoffHours int) {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, sending reschedule to staff", "tenant_id", tenantID.Hex(), "error", err)
+		return false, 0
+	}
+	return t.Settings.AllowOwnerReschedule, t.Settings.OwnerRescheduleCutoffHours
+}
+
+// validateReschedule checks that the appointment can move to scheduledAt:
+// business hours, booking window, the vet's shifts, the reserved room's
+// capacity and both the vet's and the patient's other appointments.
+func (s *Service) validateReschedule(ctx context.Context, appointment *Appointment, scheduledAt time.Time) error {
+	if appointment.Status != AppointmentStatusScheduled && appointment.Status != AppointmentStatusConfirmed {
+		return ErrRescheduleNotAllowed
+	}
+	if err := s.validateAppointmentTime(ctx, appointment.TenantID, scheduledAt); err != nil {
+		return err
+	}
+	if err := s.validateBookingWindow(ctx, appointment.TenantID, scheduledAt, appointment.Priority); err != nil {
+		return err
+	}
+	if apptType, err := s.resolveAppointmentType(ctx, appointment.TenantID, appointment.Type, appointment.Type); err == nil {
+		if err := s.checkTypeNotice(ctx, appointment.TenantID, apptType, scheduledAt, appointment.Priority); err != nil {
+			return err
+		}
+	}
+
+	if !appointment.VeterinarianID.IsZero() {
+		if err := s.checkOnDuty(ctx, appointment.TenantID, appointment.VeterinarianID, scheduledAt, appointment.Duration); err != nil {
+			return err
+		}
+		hasConflict, err := s.repo.CheckConflicts(ctx, appointment.VeterinarianID, scheduledAt, appointment.Duration, &appointment.ID, appointment.TenantID)
+		if err != nil {
+			return err
+		}
+		if hasConflict {
+			return ErrAppointmentConflict
+		}
+	}
+
+	if appointment.RoomID != nil {
+		if err := s.checkRoom(ctx, appointment.TenantID, *appointment.RoomID, "", scheduledAt, appointment.Duration, &appointment.ID); err != nil {
+			return err
+		}
+	}
+
+	return s.checkPatientAvailability(ctx, appointment.TenantID, appointment.PatientID, scheduledAt, appointment.Duration, &appointment.ID)
+}
+
+// RescheduleOwnerAppointment moves an owner's appointment to a new time. When
+// the clinic allows owner self-service the change is applied right away;
+// otherwise it is stored as a request for staff approval. Either way the
+// staff are notified.
+func (s *Service) RescheduleOwnerAppointment(ctx context.Context, id string, dto OwnerRescheduleDTO, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*OwnerAppointmentResponse, error) {
+	appointmentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment ID format")
+	}
+
+	appointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.OwnerID != ownerID {
+		return nil, ErrOwnerMismatch
+	}
+
+	if err := s.checkOwnerCanModify(ctx, appointment); err != nil {
+		return nil, err
+	}
+
+	selfService, cutoffHours := s.ownerReschedulePolicy(ctx, tenantID)
+	if cutoffHours > 0 {
+		deadline := appointment.ScheduledAt.Add(-time.Duration(cutoffHours) * time.Hour)
+		if time.Now().After(deadline) {
+			return nil, ErrRescheduleTooLate(cutoffHours, deadline)
+		}
+	}
+
+	if err := s.validateReschedule(ctx, appointment, dto.ScheduledAt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updates := bson.M{"updated_at": now}
+	title := "Solicitud de reprogramación"
+	if selfService {
+		updates["scheduled_at"] = dto.ScheduledAt
+		updates["reschedule_request"] = nil
+		title = "Cita reprogramada por cliente"
+	} else {
+		updates["reschedule_request"] = &RescheduleRequest{
+			ScheduledAt: dto.ScheduledAt,
+			Reason:      dto.Reason,
+			RequestedAt: now,
+		}
+	}
+
+	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("El cliente movió la cita del %s al %s", appointment.ScheduledAt.Format("02/01/2006 15:04"), dto.ScheduledAt.Format("02/01/2006 15:04"))
+	if !selfService {
+		body = fmt.Sprintf("El cliente pide mover la cita del %s al %s", appointment.ScheduledAt.Format("02/01/2006 15:04"), dto.ScheduledAt.Format("02/01/2006 15:04"))
+	}
+	if dto.Reason != "" {
+		body += ". Razón: " + dto.Reason
+	}
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   primitive.NilObjectID.Hex(),
+		TenantID: tenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    title,
+		Body:     body,
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
+	})
+
+	updatedAppointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ownerView(ctx, updatedAppointment, updatedAppointment.ToResponse()), nil
+}
+
+// DecideRescheduleRequest approves or declines an owner's pending reschedule
+// request. Approval checks the requested slot again, since it may have been
+// taken since the owner asked for it. The owner is notified of the outcome.
+func (s *Service) DecideRescheduleRequest(ctx context.Context, id string, dto RescheduleDecisionDTO, tenantID primitive.ObjectID, changedBy primitive.ObjectID) (*AppointmentResponse, error) {
+	appointmentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment ID format")
+	}
+
+	appointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := appointment.RescheduleRequest
+	if request == nil {
+		return nil, ErrNoRescheduleRequest
+	}
+
+	approve := *dto.Approve
+	updates := bson.M{"reschedule_request": nil, "updated_at": time.Now()}
+	if approve {
+		if err := s.validateReschedule(ctx, appointment, request.ScheduledAt); err != nil {
+			return nil, err
+		}
+		updates["scheduled_at"] = request.ScheduledAt
+	}
+
+	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
+		return nil, err
+	}
+
+	action, description := "reschedule_declined", fmt.Sprintf("Declined owner request to move to %s", request.ScheduledAt.Format(time.RFC3339))
+	if approve {
+		action, description = "reschedule_approved", fmt.Sprintf("Moved from %s to %s at the owner's request", appointment.ScheduledAt.Format(time.RFC3339), request.ScheduledAt.Format(time.RFC3339))
+	}
+	if err := s.auditLog.LogAppointmentAction(ctx, tenantID, changedBy, appointmentID, audit.EventAppointmentUpdated, action, description); err != nil {
+		slog.Error("failed to audit reschedule decision", "appointment_id", id, "error", err)
+	}
+
+	var patientName string
+	if patient, err := s.patientRepo.FindByID(ctx, tenantID, appointment.PatientID.Hex()); err == nil {
+		patientName = patient.Name
+	}
+
+	notice := &notifications.SendDTO{
+		OwnerID:  appointment.OwnerID.Hex(),
+		TenantID: tenantID.Hex(),
+		Template: notifications.TemplateAppointmentRescheduled,
+		Vars:     map[string]string{"patient_name": patientName},
+		Times:    map[string]time.Time{"date": request.ScheduledAt},
+		Data:     map[string]string{"appointment_id": appointment.ID.Hex(), "patient_id": appointment.PatientID.Hex()},
+		SendPush: true,
+	}
+	if !approve {
+		notice.Template = notifications.TemplateRescheduleDeclined
+		notice.Times = map[string]time.Time{"date": appointment.ScheduledAt}
+		if dto.Reason != "" {
+			notice.Data["reason"] = dto.Reason
+		}
+	}
+	s.notificationSvc.Send(ctx, notice)
+
+	updatedAppointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedAppointment.ToResponse(), nil
+}
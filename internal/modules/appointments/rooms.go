@@ -0,0 +1,75 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/rooms"
+)
+
+// RoomDirectory looks up the clinic's bookable rooms and equipment
+type RoomDirectory interface {
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*rooms.Room, error)
+}
+
+// parseRoomID parses an optional room ID from a request
+func parseRoomID(raw string) (*primitive.ObjectID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	roomID, err := primitive.ObjectIDFromHex(raw)
+	if err != nil {
+		return nil, ErrValidationFailed("room_id", "invalid room ID format")
+	}
+	return &roomID, nil
+}
+
+// roomAvailability reports how full the room is over the slot
+func (s *Service) roomAvailability(ctx context.Context, tenantID, roomID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID) (*rooms.Room, *RoomAvailability, error) {
+	room, err := s.rooms.FindByID(ctx, roomID, tenantID)
+	if err != nil {
+		if errors.Is(err, rooms.ErrRoomNotFound) {
+			return nil, nil, ErrRoomNotFound
+		}
+		return nil, nil, err
+	}
+
+	capacity := room.Capacity
+	if capacity < 1 {
+		capacity = rooms.DefaultCapacity
+	}
+	booked, err := s.repo.CountRoomBookings(ctx, roomID, scheduledAt, duration, excludeID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return room, &RoomAvailability{
+		Available: room.Active && booked < int64(capacity),
+		Capacity:  capacity,
+		Booked:    booked,
+		Inactive:  !room.Active,
+	}, nil
+}
+
+// checkRoom makes sure the room can take one more appointment over the slot.
+// kind is the room kind the appointment type asks for, if any.
+func (s *Service) checkRoom(ctx context.Context, tenantID, roomID primitive.ObjectID, kind string, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID) error {
+	room, availability, err := s.roomAvailability(ctx, tenantID, roomID, scheduledAt, duration, excludeID)
+	if err != nil {
+		return err
+	}
+	if availability.Inactive {
+		return ErrRoomInactive
+	}
+	if kind != "" && !strings.EqualFold(room.Kind, kind) {
+		return ErrRoomKindMismatch
+	}
+	if !availability.Available {
+		return ErrRoomFull
+	}
+	return nil
+}
@@ -5,60 +5,120 @@ import (
 	"log"
 
 	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/consents"
+	"github.com/eren_dev/go_server/internal/modules/holidays"
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
+	"github.com/eren_dev/go_server/internal/modules/loyalty"
+	"github.com/eren_dev/go_server/internal/modules/medical_records"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/rooms"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/shifts"
+	"github.com/eren_dev/go_server/internal/modules/staff"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	platformNotifications "github.com/eren_dev/go_server/internal/platform/notifications"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
 )
 
-// RegisterAdminRoutes registers admin-panel routes under /api/appointments (JWT + RBAC)
-func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider, cfg *config.Config) {
-	repo := NewAppointmentRepository(db)
-	patientRepo := patients.NewPatientRepository(db)
+// BuildService wires the appointment service with its database-backed
+// dependencies, for callers outside the HTTP routes such as payment webhooks
+func BuildService(db *database.MongoDB, pushProvider platformNotifications.PushProvider, payments PaymentLinkCreator, cfg *config.Config) *Service {
 	ownerRepo := owners.NewRepository(db)
+	tenantRepo := tenant.NewTenantRepository(db)
+	notifSvc := notifications.NewService(notifications.NewRepository(db), notifications.NewStaffRepository(db), notifications.NewTemplateRepository(db), ownerRepo, pushProvider).
+		WithOutbox(notifications.NewOutboxRepository(db))
+
 	userRepo := users.NewRepository(db)
-	notifSvc := notifications.NewService(notifications.NewRepository(db), notifications.NewStaffRepository(db), ownerRepo, pushProvider)
+	roster := shifts.NewService(shifts.NewRepository(db), userRepo, notifSvc)
+
+	patientRepo := patients.NewPatientRepository(db)
+	recordRepo := medical_records.NewMedicalRecordRepository(db)
+	loyaltySvc := loyalty.NewService(loyalty.NewRepository(db), ownerRepo, tenantRepo)
+	invoiceSvc := invoices.NewService(invoices.NewInvoiceRepository(db), ownerRepo, tenantRepo, payments, loyaltySvc, sequences.NewService(sequences.NewRepository(db))).
+		WithAppointmentSources(recordRepo, inventory.NewProductRepository(db))
 
-	if err := repo.EnsureIndexes(context.Background()); err != nil {
+	return NewService(NewAppointmentRepository(db), NewAppointmentTypeRepository(db), patientRepo, ownerRepo, userRepo, tenantRepo, recordRepo, audit.NewService(audit.NewRepository(db)), notifSvc, loyaltySvc, holidays.NewService(holidays.NewRepository(db)), roster, payments, staff.NewService(staff.NewRepository(db)), rooms.NewService(rooms.NewRepository(db)), NewStatusSubscriptionRepository(db), NewAppointmentWorkflowRepository(db), patients.NewWeightService(patients.NewWeightRepository(db), patientRepo), invoiceSvc, cfg).
+		WithConsents(consents.NewService(consents.NewTemplateRepository(db), consents.NewRepository(db)))
+}
+
+// RegisterAdminRoutes registers admin-panel routes under /api/appointments (JWT + RBAC)
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider, payments PaymentLinkCreator, cfg *config.Config) {
+	if err := NewAppointmentRepository(db).EnsureIndexes(context.Background()); err != nil {
 		log.Printf("failed to ensure indexes for appointments: %v", err)
 	}
 
-	service := NewService(repo, patientRepo, ownerRepo, userRepo, notifSvc, cfg)
-	handler := NewHandler(service)
+	handler := NewHandler(BuildService(db, pushProvider, payments, cfg))
 
 	p := private.Group("/appointments")
 	p.POST("", handler.CreateAppointment)
 	p.GET("", handler.ListAppointments)
+	p.GET("/export", handler.ExportAppointments)
+	// export.csv predates format negotiation and keeps answering CSV by default
+	p.GET("/export.csv", handler.ExportAppointments)
 	p.GET("/calendar", handler.GetCalendarView)
 	p.GET("/availability", handler.CheckAvailability)
+	p.POST("/preview-series", handler.PreviewSeries)
+	p.GET("/status-subscription", handler.GetStatusSubscription)
+	p.PUT("/status-subscription", handler.SetStatusSubscription)
+	p.DELETE("/status-subscription", handler.DeleteStatusSubscription)
 	p.GET("/:id", handler.GetAppointment)
 	p.PUT("/:id", handler.UpdateAppointment)
 	p.DELETE("/:id", handler.DeleteAppointment)
 	p.PATCH("/:id/status", handler.UpdateStatus)
+	p.PATCH("/:id/reassign", handler.ReassignVeterinarian)
+	p.PATCH("/:id/reschedule-request", handler.DecideRescheduleRequest)
 	p.GET("/:id/history", handler.GetStatusHistory)
+	p.GET("/:id/consent", handler.GetConsent)
+	p.POST("/:id/consent", handler.SignConsent)
+
+	w := private.Group("/appointment-workflow")
+	w.GET("", handler.GetStatusWorkflow)
+	w.PUT("", handler.UpdateStatusWorkflow)
+
+	t := private.Group("/appointment-types")
+	t.GET("", handler.ListAppointmentTypes)
+	t.POST("", handler.CreateAppointmentType)
+	t.PUT("/:id", handler.UpdateAppointmentType)
+	t.DELETE("/:id", handler.DeleteAppointmentType)
+
+	// Offboarding lives here rather than in users: it is tenant-scoped and
+	// mostly moves appointments
+	private.POST("/users/:id/offboard", handler.OffboardVeterinarian)
 }
 
 // RegisterMobileRoutes registers mobile (owner-facing) routes under /mobile/appointments
-func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider, cfg *config.Config) {
-	repo := NewAppointmentRepository(db)
-	patientRepo := patients.NewPatientRepository(db)
-	ownerRepo := owners.NewRepository(db)
-	userRepo := users.NewRepository(db)
-	notifSvc := notifications.NewService(notifications.NewRepository(db), notifications.NewStaffRepository(db), ownerRepo, pushProvider)
-
-	if err := repo.EnsureIndexes(context.Background()); err != nil {
+func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider, payments PaymentLinkCreator, cfg *config.Config) {
+	if err := NewAppointmentRepository(db).EnsureIndexes(context.Background()); err != nil {
 		log.Printf("failed to ensure indexes for appointments: %v", err)
 	}
 
-	service := NewService(repo, patientRepo, ownerRepo, userRepo, notifSvc, cfg)
-	handler := NewHandler(service)
+	handler := NewHandler(BuildService(db, pushProvider, payments, cfg))
 
 	m := mobile.Group("/appointments")
 	m.POST("/request", handler.RequestAppointment)
 	m.GET("", handler.GetOwnerAppointments)
+	m.GET("/booking-window", handler.GetBookingWindow)
+	m.GET("/types", handler.GetOwnerAppointmentTypes)
 	m.GET("/:id", handler.GetOwnerAppointment)
 	m.PATCH("/:id/cancel", handler.CancelOwnerAppointment)
+	m.PATCH("/:id/reschedule", handler.RescheduleOwnerAppointment)
+	m.POST("/:id/acknowledge", handler.AcknowledgeOwnerAppointment)
+	m.POST("/:id/attachments", handler.AddOwnerAttachment)
+	m.GET("/:id/consent", handler.GetOwnerConsent)
+	m.POST("/:id/consent", handler.SignOwnerConsent)
+}
+
+// RegisterVetMobileRoutes registers staff-facing mobile routes under /mobile/vet.
+// The group is expected to admit only staff holding a vet role in the tenant.
+func RegisterVetMobileRoutes(mobileStaff *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider, payments PaymentLinkCreator, cfg *config.Config) {
+	handler := NewHandler(BuildService(db, pushProvider, payments, cfg))
+
+	v := mobileStaff.Group("/vet")
+	v.GET("/today", handler.GetVetToday)
 }
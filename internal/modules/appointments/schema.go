@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
 )
 
 // Appointment represents an appointment in the veterinary system
@@ -15,6 +17,10 @@ type Appointment struct {
 	PatientID      primitive.ObjectID `bson:"patient_id"`
 	OwnerID        primitive.ObjectID `bson:"owner_id"`
 	VeterinarianID primitive.ObjectID `bson:"veterinarian_id"`
+	// OriginalVeterinarianID is the vet the appointment was booked with, set on the first reassignment
+	OriginalVeterinarianID *primitive.ObjectID `bson:"original_veterinarian_id,omitempty"`
+	// RoomID is the room or equipment the appointment reserves, if any
+	RoomID *primitive.ObjectID `bson:"room_id,omitempty"`
 
 	// Scheduling
 	ScheduledAt time.Time `bson:"scheduled_at"`
@@ -24,6 +30,9 @@ type Appointment struct {
 	Type     string `bson:"type"`     // consultation, surgery, vaccination, etc.
 	Status   string `bson:"status"`   // scheduled, confirmed, in_progress, completed, cancelled, no_show
 	Priority string `bson:"priority"` // low, normal, high, emergency
+	// RequestedPriority is what an owner asked for above the clinic's owner
+	// cap; it stays set until staff triage the appointment
+	RequestedPriority string `bson:"requested_priority,omitempty"`
 
 	// Notes and observations
 	Reason     string `bson:"reason"`                // Reason for visit
@@ -36,6 +45,46 @@ type Appointment struct {
 	CompletedAt  *time.Time `bson:"completed_at,omitempty"`
 	CancelledAt  *time.Time `bson:"cancelled_at,omitempty"`
 	CancelReason string     `bson:"cancel_reason,omitempty"`
+	// CancelledByOwner is set when the owner cancelled from the app, so the
+	// reason they wrote can be shown back to them; staff reasons are internal
+	CancelledByOwner bool `bson:"cancelled_by_owner,omitempty"`
+
+	// EffectiveStartAt is when a late appointment actually started, shifted
+	// within the clinic's late tolerance. ScheduledAt keeps the booked time.
+	EffectiveStartAt *time.Time `bson:"effective_start_at,omitempty"`
+
+	// AcknowledgedAt is when the owner first acknowledged a reminder for this appointment
+	AcknowledgedAt *time.Time `bson:"acknowledged_at,omitempty"`
+	// DisableReminders opts this appointment out of the reminder sweeps,
+	// regardless of the owner's notification preferences
+	DisableReminders bool `bson:"disable_reminders,omitempty"`
+	// OwnerModifiable, when set by staff, overrides the appointment type's
+	// owner lock for this appointment only
+	OwnerModifiable *bool `bson:"owner_modifiable,omitempty"`
+	// StaleAlertedAt is when the vet was asked to update the status of this
+	// appointment after it stayed active past its end
+	StaleAlertedAt *time.Time `bson:"stale_alerted_at,omitempty"`
+	// DelayNoticeMinutes is the delay the owner was last told to expect
+	// because an earlier appointment of the vet ran long; DelayNotifiedAt is
+	// when they were told
+	DelayNoticeMinutes int        `bson:"delay_notice_minutes,omitempty"`
+	DelayNotifiedAt    *time.Time `bson:"delay_notified_at,omitempty"`
+	// PendingNotifications are owner notifications written together with the
+	// change that triggers them, until they are moved into the outbox
+	PendingNotifications []notifications.PendingNotification `bson:"pending_notifications,omitempty"`
+
+	// Deposit is set when the appointment type requires a prepayment
+	Deposit *AppointmentDeposit `bson:"deposit,omitempty"`
+
+	// RescheduleRequest is an owner's request to move the appointment, pending
+	// until staff approve or decline it
+	RescheduleRequest *RescheduleRequest `bson:"reschedule_request,omitempty"`
+
+	// Attachments are files the owner sent ahead of the visit, such as a photo of a wound
+	Attachments []AppointmentAttachment `bson:"attachments,omitempty"`
+
+	// FollowUpOf is the medical record whose next visit this appointment is
+	FollowUpOf *primitive.ObjectID `bson:"follow_up_of,omitempty"`
 
 	// Standard fields
 	CreatedAt time.Time  `bson:"created_at"`
@@ -43,6 +92,39 @@ type Appointment struct {
 	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
 }
 
+// RescheduleRequest is a new time an owner asked for from the mobile app
+type RescheduleRequest struct {
+	ScheduledAt time.Time `bson:"scheduled_at"`
+	Reason      string    `bson:"reason,omitempty"`
+	RequestedAt time.Time `bson:"requested_at"`
+}
+
+// AppointmentAttachment links an uploaded file to an appointment
+type AppointmentAttachment struct {
+	FileID      string             `bson:"file_id"`
+	ContentType string             `bson:"content_type"`
+	SizeBytes   int64              `bson:"size_bytes"`
+	Caption     string             `bson:"caption,omitempty"`
+	AddedBy     primitive.ObjectID `bson:"added_by"`
+	AddedAt     time.Time          `bson:"added_at"`
+}
+
+// AppointmentDeposit is the prepayment collected through a payment link before
+// an appointment of a deposit-requiring type is booked. Amount is in minor
+// units of Currency.
+type AppointmentDeposit struct {
+	Amount   int64  `bson:"amount"`
+	Currency string `bson:"currency"`
+	Provider string `bson:"provider"`
+	LinkID   string `bson:"link_id"`
+	URL      string `bson:"url"`
+	// NextStatus is the status the appointment moves to once the deposit is paid
+	NextStatus    string     `bson:"next_status"`
+	ExpiresAt     time.Time  `bson:"expires_at"`
+	PaidAt        *time.Time `bson:"paid_at,omitempty"`
+	TransactionID string     `bson:"transaction_id,omitempty"`
+}
+
 // AppointmentStatusTransition tracks status changes for audit purposes
 type AppointmentStatusTransition struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty"`
@@ -52,7 +134,10 @@ type AppointmentStatusTransition struct {
 	ToStatus      string             `bson:"to_status"`
 	ChangedBy     primitive.ObjectID `bson:"changed_by"`
 	Reason        string             `bson:"reason,omitempty"`
-	CreatedAt     time.Time          `bson:"created_at"`
+	// Set when the entry records a vet reassignment rather than a status change
+	FromVeterinarianID *primitive.ObjectID `bson:"from_veterinarian_id,omitempty"`
+	ToVeterinarianID   *primitive.ObjectID `bson:"to_veterinarian_id,omitempty"`
+	CreatedAt          time.Time           `bson:"created_at"`
 }
 
 // AppointmentType constants
@@ -73,6 +158,9 @@ const (
 	AppointmentStatusCompleted  = "completed"
 	AppointmentStatusCancelled  = "cancelled"
 	AppointmentStatusNoShow     = "no_show"
+
+	// AppointmentStatusAwaitingDeposit holds the slot until the deposit is paid
+	AppointmentStatusAwaitingDeposit = "awaiting_deposit"
 )
 
 // AppointmentPriority constants
@@ -91,6 +179,9 @@ var ValidStatusTransitions = map[string][]string{
 	AppointmentStatusCompleted:  {}, // Terminal status
 	AppointmentStatusCancelled:  {}, // Terminal status
 	AppointmentStatusNoShow:     {}, // Terminal status
+
+	// Staff may also book it directly when the deposit is paid in person
+	AppointmentStatusAwaitingDeposit: {AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusCancelled},
 }
 
 // GetValidNextStatuses returns the valid statuses that can be transitioned to from current status
@@ -116,6 +207,16 @@ func (a *Appointment) IsActive() bool {
 		a.Status != AppointmentStatusNoShow
 }
 
+// EndsAt returns when the appointment is due to end, counting from the
+// shifted start of a late arrival if there was one
+func (a *Appointment) EndsAt() time.Time {
+	start := a.ScheduledAt
+	if a.EffectiveStartAt != nil {
+		start = *a.EffectiveStartAt
+	}
+	return start.Add(time.Duration(a.Duration) * time.Minute)
+}
+
 // IsUpcoming returns true if the appointment is scheduled in the future
 func (a *Appointment) IsUpcoming() bool {
 	return a.ScheduledAt.After(time.Now()) && a.IsActive()
@@ -0,0 +1,133 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Recurrence frequencies
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// MaxSeriesOccurrences caps how many appointments a series can expand to
+const MaxSeriesOccurrences = 52
+
+// expandRecurrence lists the start times of a series, the first one being
+// start itself. Each occurrence is computed from start rather than from the
+// previous one, so a monthly series on the 31st skips short months instead of
+// drifting to the 1st.
+func expandRecurrence(start time.Time, r RecurrenceDTO) ([]time.Time, error) {
+	if r.Count == 0 && r.Until == nil {
+		return nil, ErrValidationFailed("recurrence", "either count or until is required")
+	}
+	if r.Until != nil && r.Until.Before(start) {
+		return nil, ErrValidationFailed("recurrence.until", "until must be after scheduled_at")
+	}
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	limit := MaxSeriesOccurrences
+	if r.Count > 0 && r.Count < limit {
+		limit = r.Count
+	}
+
+	var occurrences []time.Time
+expand:
+	for i := 0; len(occurrences) < limit; i++ {
+		var next time.Time
+		switch r.Frequency {
+		case RecurrenceDaily:
+			next = start.AddDate(0, 0, i*interval)
+		case RecurrenceWeekly:
+			next = start.AddDate(0, 0, 7*i*interval)
+		case RecurrenceMonthly:
+			next = start.AddDate(0, i*interval, 0)
+			if next.Day() != start.Day() {
+				// Feb 29 recurs within four years; past that the day never comes back
+				if i*interval > 48 {
+					break expand
+				}
+				continue
+			}
+		default:
+			return nil, ErrValidationFailed("recurrence.frequency", "must be one of daily, weekly, monthly")
+		}
+
+		if r.Until != nil && next.After(*r.Until) {
+			break expand
+		}
+		occurrences = append(occurrences, next)
+	}
+	return occurrences, nil
+}
+
+// PreviewSeries expands a recurring booking and reports, for each occurrence,
+// whether it could be booked. Nothing is created.
+func (s *Service) PreviewSeries(ctx context.Context, dto PreviewSeriesDTO, tenantID primitive.ObjectID) (*SeriesPreviewResponse, error) {
+	veterinarianID, err := primitive.ObjectIDFromHex(dto.VeterinarianID)
+	if err != nil {
+		return nil, ErrValidationFailed("veterinarian_id", "invalid veterinarian ID format")
+	}
+
+	occurrences, err := expandRecurrence(dto.ScheduledAt, dto.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SeriesPreviewResponse{Occurrences: make([]SeriesOccurrenceResponse, 0, len(occurrences))}
+	for _, at := range occurrences {
+		occ := SeriesOccurrenceResponse{ScheduledAt: at, Available: true}
+		if err := s.checkOccurrence(ctx, veterinarianID, at, dto.Duration, tenantID); err != nil {
+			var coded *sharedErrors.Error
+			if !errors.As(err, &coded) {
+				return nil, err
+			}
+			occ.Available = false
+			occ.Reason = coded.Code
+			occ.Message = coded.Message
+		}
+
+		if occ.Available {
+			resp.AvailableCount++
+		} else {
+			resp.UnavailableCount++
+		}
+		resp.Occurrences = append(resp.Occurrences, occ)
+	}
+	return resp, nil
+}
+
+// checkOccurrence runs the same time checks as CreateAppointment for a single
+// occurrence. Rule violations come back as coded errors; anything else is a
+// lookup failure.
+func (s *Service) checkOccurrence(ctx context.Context, veterinarianID primitive.ObjectID, at time.Time, duration int, tenantID primitive.ObjectID) error {
+	if err := s.validateAppointmentTime(ctx, tenantID, at); err != nil {
+		return err
+	}
+	if err := s.validateBookingWindow(ctx, tenantID, at, ""); err != nil {
+		return err
+	}
+
+	if err := s.checkOnDuty(ctx, tenantID, veterinarianID, at, duration); err != nil {
+		return err
+	}
+
+	hasConflict, err := s.repo.CheckConflicts(ctx, veterinarianID, at, duration, nil, tenantID)
+	if err != nil {
+		return err
+	}
+	if hasConflict {
+		return ErrAppointmentConflict
+	}
+	return nil
+}
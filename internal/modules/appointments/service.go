@@ -3,12 +3,17 @@ package appointments
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/holidays"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,30 +23,137 @@ import (
 type NotificationSender interface {
 	Send(ctx context.Context, dto *notifications.SendDTO) error
 	SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error
+	// Enqueue delivers a notification embedded in a document through the
+	// outbox, retrying until the owner is notified
+	Enqueue(ctx context.Context, collection string, docID primitive.ObjectID, pending notifications.PendingNotification) error
+}
+
+// TenantReader loads the clinic settings that change appointment behavior
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+// MedicalRecordCounter reports visit records that reference an appointment
+type MedicalRecordCounter interface {
+	CountByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (int64, error)
+}
+
+// AuditLogger records sensitive appointment actions
+type AuditLogger interface {
+	LogAppointmentAction(ctx context.Context, tenantID, userID, appointmentID primitive.ObjectID, eventType audit.EventType, action, description string) error
+	LogUserAction(ctx context.Context, tenantID, userID primitive.ObjectID, eventType audit.EventType, action, description string) error
+}
+
+// HolidayCalendar reports days the clinic is closed
+type HolidayCalendar interface {
+	HolidayOn(ctx context.Context, tenantID primitive.ObjectID, day time.Time) (*holidays.Holiday, error)
+}
+
+// DutyRoster reports whether a vet is on duty according to the published staff roster
+type DutyRoster interface {
+	OnDuty(ctx context.Context, tenantID, userID primitive.ObjectID, start, end time.Time) (bool, error)
+}
+
+// LoyaltyAccruer grants loyalty points for completed visits
+type LoyaltyAccruer interface {
+	AccrueVisit(ctx context.Context, tenantID, ownerID, appointmentID primitive.ObjectID) error
+}
+
+// WeightRecorder stores the weight reading taken at check-in or completion
+type WeightRecorder interface {
+	RecordFromAppointment(ctx context.Context, tenantID, patientID, appointmentID, userID primitive.ObjectID, weight float64) error
 }
 
 // Service provides business logic for appointments
 type Service struct {
 	repo            AppointmentRepository
+	types           AppointmentTypeRepository
+	workflows       AppointmentWorkflowRepository
 	patientRepo     patients.PatientRepository
 	ownerRepo       owners.OwnerRepository
 	userRepo        users.UserRepository
+	tenantRepo      TenantReader
+	recordCounter   MedicalRecordCounter
+	auditLog        AuditLogger
 	notificationSvc NotificationSender
+	loyalty         LoyaltyAccruer
+	holidays        HolidayCalendar
+	roster          DutyRoster
+	payments        PaymentLinkCreator
+	vets            VetDirectory
+	rooms           RoomDirectory
+	subscriptions   StatusSubscriptionRepository
+	weights         WeightRecorder
+	invoices        InvoiceDrafter
+	consents        ConsentRegistry
 	cfg             *config.Config
 }
 
 // NewService creates a new appointment service
-func NewService(repo AppointmentRepository, patientRepo patients.PatientRepository, ownerRepo owners.OwnerRepository, userRepo users.UserRepository, notificationSvc NotificationSender, cfg *config.Config) *Service {
+func NewService(repo AppointmentRepository, types AppointmentTypeRepository, patientRepo patients.PatientRepository, ownerRepo owners.OwnerRepository, userRepo users.UserRepository, tenantRepo TenantReader, recordCounter MedicalRecordCounter, auditLog AuditLogger, notificationSvc NotificationSender, loyalty LoyaltyAccruer, holidays HolidayCalendar, roster DutyRoster, payments PaymentLinkCreator, vets VetDirectory, rooms RoomDirectory, subscriptions StatusSubscriptionRepository, workflows AppointmentWorkflowRepository, weights WeightRecorder, invoices InvoiceDrafter, cfg *config.Config) *Service {
 	return &Service{
 		repo:            repo,
+		types:           types,
 		patientRepo:     patientRepo,
 		ownerRepo:       ownerRepo,
 		userRepo:        userRepo,
+		tenantRepo:      tenantRepo,
+		recordCounter:   recordCounter,
+		auditLog:        auditLog,
 		notificationSvc: notificationSvc,
+		loyalty:         loyalty,
+		holidays:        holidays,
+		roster:          roster,
+		payments:        payments,
+		vets:            vets,
+		rooms:           rooms,
+		subscriptions:   subscriptions,
+		workflows:       workflows,
+		weights:         weights,
+		invoices:        invoices,
 		cfg:             cfg,
 	}
 }
 
+// autoConfirmEnabled reports whether the clinic books staff-created
+// appointments straight into confirmed. Lookup failures fall back to the
+// default scheduled flow rather than blocking the booking.
+func (s *Service) autoConfirmEnabled(ctx context.Context, tenantID primitive.ObjectID) bool {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, keeping manual confirmation", "tenant_id", tenantID.Hex(), "error", err)
+		return false
+	}
+	return t.Settings.AutoConfirmAppointments
+}
+
+// checkPatientAvailability enforces the clinic's optional rule that a patient
+// cannot be booked twice at once, keeping the configured gap on either side
+// of its other appointments. It complements the per-vet CheckConflicts.
+func (s *Service) checkPatientAvailability(ctx context.Context, tenantID, patientID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID) error {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping patient overlap check", "tenant_id", tenantID.Hex(), "error", err)
+		return nil
+	}
+	if !t.Settings.PreventPatientOverlap {
+		return nil
+	}
+
+	gap := time.Duration(t.Settings.PatientAppointmentGapMinutes) * time.Minute
+	from := scheduledAt.Add(-gap)
+	to := scheduledAt.Add(time.Duration(duration)*time.Minute + gap)
+
+	busy, err := s.repo.CheckPatientConflicts(ctx, patientID, from, to, excludeID, tenantID)
+	if err != nil {
+		return err
+	}
+	if busy {
+		return ErrPatientNotAvailable
+	}
+	return nil
+}
+
 // populateAppointment populates references for an appointment
 func (s *Service) populateAppointment(ctx context.Context, appointment *Appointment, tenantID primitive.ObjectID) (*AppointmentResponse, error) {
 	resp := appointment.ToResponse()
@@ -66,8 +178,9 @@ func (s *Service) populateAppointment(ctx context.Context, appointment *Appointm
 	return resp, nil
 }
 
-// validateAppointmentTime checks if the appointment time is valid
-func (s *Service) validateAppointmentTime(scheduledAt time.Time) error {
+// validateAppointmentTime checks if the appointment time is valid: in the
+// future, within business hours and not on a Sunday or a clinic holiday
+func (s *Service) validateAppointmentTime(ctx context.Context, tenantID primitive.ObjectID, scheduledAt time.Time) error {
 	if scheduledAt.Before(time.Now()) {
 		return ErrPastAppointmentTime
 	}
@@ -77,26 +190,200 @@ func (s *Service) validateAppointmentTime(scheduledAt time.Time) error {
 		return ErrInvalidAppointmentTime
 	}
 
+	// Sundays stay closed until clinics can configure their own business hours
 	if scheduledAt.Weekday() == time.Sunday {
 		return ErrInvalidAppointmentTime
 	}
 
+	if err := s.checkSlotGrid(ctx, tenantID, scheduledAt); err != nil {
+		return err
+	}
+
+	return s.checkHoliday(ctx, tenantID, scheduledAt)
+}
+
+// checkSlotGrid rejects start times off the clinic's slot grid, counted from
+// midnight so a 15-minute grid means :00, :15, :30 and :45. A granularity of
+// 0 or a failed settings lookup accepts any time.
+func (s *Service) checkSlotGrid(ctx context.Context, tenantID primitive.ObjectID, scheduledAt time.Time) error {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping slot grid", "tenant_id", tenantID.Hex(), "error", err)
+		return nil
+	}
+	granularity := t.Settings.SlotGranularityMinutes
+	if granularity <= 0 {
+		return nil
+	}
+
+	midnight := time.Date(scheduledAt.Year(), scheduledAt.Month(), scheduledAt.Day(), 0, 0, 0, 0, scheduledAt.Location())
+	step := time.Duration(granularity) * time.Minute
+	offset := scheduledAt.Sub(midnight) % step
+	if offset == 0 {
+		return nil
+	}
+	before := scheduledAt.Add(-offset)
+	return ErrNotOnSlotGrid(granularity, before, before.Add(step))
+}
+
+// checkHoliday rejects dates on the clinic holiday calendar. A lookup failure
+// is logged and ignored so the calendar never blocks bookings on its own.
+func (s *Service) checkHoliday(ctx context.Context, tenantID primitive.ObjectID, day time.Time) error {
+	holiday, err := s.holidays.HolidayOn(ctx, tenantID, day)
+	if err != nil {
+		slog.Warn("failed to load holiday calendar", "tenant_id", tenantID.Hex(), "error", err)
+		return nil
+	}
+	if holiday != nil {
+		return ErrClinicClosedOnHoliday(holiday.Name, day)
+	}
 	return nil
 }
 
+// checkOnDuty rejects times outside the vet's published shifts. Like the
+// holiday calendar, a roster lookup failure is logged and ignored.
+func (s *Service) checkOnDuty(ctx context.Context, tenantID, vetID primitive.ObjectID, at time.Time, duration int) error {
+	if s.roster == nil || vetID.IsZero() {
+		return nil
+	}
+	onDuty, err := s.roster.OnDuty(ctx, tenantID, vetID, at, at.Add(time.Duration(duration)*time.Minute))
+	if err != nil {
+		slog.Warn("failed to load staff roster", "tenant_id", tenantID.Hex(), "error", err)
+		return nil
+	}
+	if !onDuty {
+		return ErrVeterinarianOffDuty
+	}
+	return nil
+}
+
+// bookingWindow computes the clinic's bookable range relative to now. A zero
+// Earliest/Latest means the corresponding limit is disabled. Lookup failures
+// leave both limits disabled so a settings outage never blocks bookings.
+func (s *Service) bookingWindow(ctx context.Context, tenantID primitive.ObjectID, now time.Time) *BookingWindowResponse {
+	window := &BookingWindowResponse{}
+
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping booking window", "tenant_id", tenantID.Hex(), "error", err)
+		return window
+	}
+
+	window.MaxAdvanceDays = t.Settings.MaxAdvanceBookingDays
+	window.MinNoticeHours = t.Settings.MinBookingNoticeHours
+	if window.MinNoticeHours > 0 {
+		earliest := now.Add(time.Duration(window.MinNoticeHours) * time.Hour)
+		window.Earliest = &earliest
+	}
+	if window.MaxAdvanceDays > 0 {
+		latest := now.AddDate(0, 0, window.MaxAdvanceDays)
+		window.Latest = &latest
+	}
+	return window
+}
+
+// validateBookingWindow enforces the clinic's minimum notice and maximum
+// advance booking limits on top of validateAppointmentTime. Appointments of a
+// priority at or above the clinic's BypassNoticeFrom skip the minimum notice.
+func (s *Service) validateBookingWindow(ctx context.Context, tenantID primitive.ObjectID, scheduledAt time.Time, priority string) error {
+	window := s.bookingWindow(ctx, tenantID, time.Now())
+
+	if window.Earliest != nil && scheduledAt.Before(*window.Earliest) &&
+		!priorityAtLeast(priority, s.prioritySettings(ctx, tenantID).BypassNoticeFrom) {
+		return ErrBookingNoticeTooShort(window.MinNoticeHours, *window.Earliest)
+	}
+	if window.Latest != nil && scheduledAt.After(*window.Latest) {
+		return ErrBookingTooFarAhead(window.MaxAdvanceDays, *window.Latest)
+	}
+	return nil
+}
+
+// checkTypeNotice enforces the lead time of an appointment type, for visits
+// that need preparation such as surgery. Types without one only follow the
+// clinic's minimum notice, and priorities that bypass that notice bypass this
+// one too.
+func (s *Service) checkTypeNotice(ctx context.Context, tenantID primitive.ObjectID, apptType *AppointmentTypeConfig, scheduledAt time.Time, priority string) error {
+	if apptType == nil || apptType.MinNoticeHours <= 0 {
+		return nil
+	}
+	earliest := time.Now().Add(time.Duration(apptType.MinNoticeHours) * time.Hour)
+	if !scheduledAt.Before(earliest) ||
+		priorityAtLeast(priority, s.prioritySettings(ctx, tenantID).BypassNoticeFrom) {
+		return nil
+	}
+	return ErrTypeNoticeTooShort(apptType.Key, apptType.MinNoticeHours, earliest)
+}
+
+// GetBookingWindow returns the clinic's booking limits so clients can
+// constrain their date pickers. With typeKey, Earliest also honours the lead
+// time of that type.
+func (s *Service) GetBookingWindow(ctx context.Context, tenantID primitive.ObjectID, typeKey string) (*BookingWindowResponse, error) {
+	now := time.Now()
+	window := s.bookingWindow(ctx, tenantID, now)
+	if typeKey == "" {
+		return window, nil
+	}
+
+	apptType, err := s.resolveAppointmentType(ctx, tenantID, typeKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if apptType.MinNoticeHours > 0 {
+		window.TypeNoticeHours = apptType.MinNoticeHours
+		earliest := now.Add(time.Duration(apptType.MinNoticeHours) * time.Hour)
+		if window.Earliest == nil || earliest.After(*window.Earliest) {
+			window.Earliest = &earliest
+		}
+	}
+	return window, nil
+}
+
 // CreateAppointment creates a new appointment
 func (s *Service) CreateAppointment(ctx context.Context, dto CreateAppointmentDTO, tenantID primitive.ObjectID, createdBy primitive.ObjectID) (*AppointmentResponse, error) {
+	return s.createAppointment(ctx, dto, tenantID, createdBy, nil)
+}
+
+// createAppointment books an appointment, optionally as the follow-up of a medical record
+func (s *Service) createAppointment(ctx context.Context, dto CreateAppointmentDTO, tenantID primitive.ObjectID, createdBy primitive.ObjectID, followUpOf *primitive.ObjectID) (*AppointmentResponse, error) {
 	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
 	if err != nil {
 		return nil, ErrValidationFailed("patient_id", "invalid patient ID format")
 	}
 
-	veterinarianID, err := primitive.ObjectIDFromHex(dto.VeterinarianID)
+	veterinarianID := primitive.NilObjectID
+	if dto.VeterinarianID != "" {
+		if veterinarianID, err = primitive.ObjectIDFromHex(dto.VeterinarianID); err != nil {
+			return nil, ErrValidationFailed("veterinarian_id", "invalid veterinarian ID format")
+		}
+	}
+
+	roomID, err := parseRoomID(dto.RoomID)
 	if err != nil {
-		return nil, ErrValidationFailed("veterinarian_id", "invalid veterinarian ID format")
+		return nil, err
 	}
 
-	if err := s.validateAppointmentTime(dto.ScheduledAt); err != nil {
+	apptType, err := s.resolveAppointmentType(ctx, tenantID, dto.Type, "")
+	if err != nil {
+		return nil, err
+	}
+	if apptType.RequiresVet && veterinarianID.IsZero() {
+		return nil, ErrVeterinarianRequired
+	}
+	if apptType.RoomKind != "" && roomID == nil {
+		return nil, ErrRoomRequired
+	}
+	duration := dto.Duration
+	if duration == 0 {
+		duration = apptType.DefaultDuration
+	}
+
+	if err := s.validateAppointmentTime(ctx, tenantID, dto.ScheduledAt); err != nil {
+		return nil, err
+	}
+	if err := s.validateBookingWindow(ctx, tenantID, dto.ScheduledAt, dto.Priority); err != nil {
+		return nil, err
+	}
+	if err := s.checkTypeNotice(ctx, tenantID, apptType, dto.ScheduledAt, dto.Priority); err != nil {
 		return nil, err
 	}
 
@@ -104,21 +391,39 @@ func (s *Service) CreateAppointment(ctx context.Context, dto CreateAppointmentDT
 	if err != nil {
 		return nil, ErrPatientNotFound
 	}
+	if patient.IsDeceased() {
+		return nil, ErrPatientDeceased
+	}
 
+	// Visits without a vet (e.g. grooming) have no agenda to clash with
 	if !veterinarianID.IsZero() {
 		_, err := s.userRepo.FindByID(ctx, veterinarianID.Hex())
 		if err != nil {
 			return nil, ErrVeterinarianNotFound
 		}
+
+		if err := s.checkOnDuty(ctx, tenantID, veterinarianID, dto.ScheduledAt, duration); err != nil {
+			return nil, err
+		}
+
+		hasConflict, err := s.repo.CheckConflicts(ctx, veterinarianID, dto.ScheduledAt, duration, nil, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasConflict {
+			return nil, ErrAppointmentConflict
+		}
 	}
 
-	hasConflict, err := s.repo.CheckConflicts(ctx, veterinarianID, dto.ScheduledAt, dto.Duration, nil, tenantID)
-	if err != nil {
+	if err := s.checkPatientAvailability(ctx, tenantID, patientID, dto.ScheduledAt, duration, nil); err != nil {
 		return nil, err
 	}
 
-	if hasConflict {
-		return nil, ErrAppointmentConflict
+	if roomID != nil {
+		if err := s.checkRoom(ctx, tenantID, *roomID, apptType.RoomKind, dto.ScheduledAt, duration, nil); err != nil {
+			return nil, err
+		}
 	}
 
 	priority := dto.Priority
@@ -132,8 +437,9 @@ func (s *Service) CreateAppointment(ctx context.Context, dto CreateAppointmentDT
 		PatientID:      patientID,
 		OwnerID:        patient.OwnerID,
 		VeterinarianID: veterinarianID,
+		RoomID:         roomID,
 		ScheduledAt:    dto.ScheduledAt,
-		Duration:       dto.Duration,
+		Duration:       duration,
 		Type:           dto.Type,
 		Status:         AppointmentStatusScheduled,
 		Priority:       priority,
@@ -142,29 +448,67 @@ func (s *Service) CreateAppointment(ctx context.Context, dto CreateAppointmentDT
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
+	appointment.DisableReminders = dto.DisableReminders
+	appointment.OwnerModifiable = dto.OwnerModifiable
+	appointment.FollowUpOf = followUpOf
+
+	// A new client without an intake form is booked as scheduled instead
+	autoConfirm := s.autoConfirmEnabled(ctx, tenantID) && s.checkIntake(ctx, appointment) == nil
+	if autoConfirm {
+		appointment.Status = AppointmentStatusConfirmed
+		appointment.ConfirmedAt = &now
+	}
+
+	// A required deposit holds the appointment until paid; it then moves to
+	// the status it would have been booked in
+	if err := s.prepareDeposit(ctx, appointment, apptType, appointment.Status); err != nil {
+		return nil, err
+	}
+
+	// The owner notification is stored with the appointment, so a crash
+	// before it reaches the outbox cannot lose it
+	appointment.ID = primitive.NewObjectID()
+	var pending *notifications.PendingNotification
+	if appointment.Deposit == nil {
+		pending = s.creationNotification(appointment, autoConfirm, patient.Name)
+		if pending != nil {
+			appointment.PendingNotifications = []notifications.PendingNotification{*pending}
+		}
+	}
 
 	if err := s.repo.Create(ctx, appointment); err != nil {
 		return nil, err
 	}
 
-	s.notificationSvc.Send(ctx, &notifications.SendDTO{
-		OwnerID:  appointment.OwnerID.Hex(),
-		TenantID: tenantID.Hex(),
-		Type:     notifications.TypeAppointmentReminder,
-		Title:    "Nueva cita agendada",
-		Body:     fmt.Sprintf("Se ha agendado una cita para %s el %s", patient.Name, appointment.ScheduledAt.Format("02/01/2006 15:04")),
-		Data:     map[string]string{"appointment_id": appointment.ID.Hex(), "patient_id": appointment.PatientID.Hex()},
-		SendPush: true,
-	})
+	if pending != nil {
+		s.notificationSvc.Enqueue(ctx, Collection, appointment.ID, *pending)
+	}
+
+	if appointment.Deposit != nil {
+		s.notifyDepositDue(ctx, appointment, patient.Name)
+	} else if autoConfirm {
+		// Record the implicit scheduled -> confirmed step so the history matches a manual confirmation
+		s.repo.CreateStatusTransition(ctx, &AppointmentStatusTransition{
+			TenantID:      tenantID,
+			AppointmentID: appointment.ID,
+			FromStatus:    AppointmentStatusScheduled,
+			ToStatus:      AppointmentStatusConfirmed,
+			ChangedBy:     createdBy,
+			Reason:        "Confirmada automáticamente por la clínica",
+			CreatedAt:     now,
+		})
+	}
 
 	if !appointment.VeterinarianID.IsZero() {
+		urgent := priorityAtLeast(appointment.Priority, s.prioritySettings(ctx, tenantID).UrgentAlertFrom)
 		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
 			UserID:   appointment.VeterinarianID.Hex(),
 			TenantID: tenantID.Hex(),
 			Type:     notifications.TypeStaffNewAppointment,
-			Title:    "Nueva cita asignada",
+			Title:    urgentTitle("Nueva cita asignada", urgent),
 			Body:     fmt.Sprintf("Cita con %s el %s", patient.Name, appointment.ScheduledAt.Format("02/01/2006 15:04")),
 			Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
+			Urgent:   urgent,
 		})
 	}
 
@@ -183,25 +527,38 @@ func (s *Service) GetAppointment(ctx context.Context, id string, tenantID primit
 		return nil, err
 	}
 
+	var resp *AppointmentResponse
 	if populate {
-		return s.populateAppointment(ctx, appointment, tenantID)
+		if resp, err = s.populateAppointment(ctx, appointment, tenantID); err != nil {
+			return nil, err
+		}
+	} else {
+		resp = appointment.ToResponse()
 	}
-	return appointment.ToResponse(), nil
+	resp.Display = resolveDisplay(s.calendarSettings(ctx, tenantID), resp.Type, resp.Status, calendarPriority(resp.Priority, resp.RequestedPriority))
+	return resp, nil
 }
 
 // ListAppointments lists appointments with filters and pagination
 func (s *Service) ListAppointments(ctx context.Context, filters map[string]interface{}, tenantID primitive.ObjectID, params pagination.Params, populate bool) (*PaginatedAppointmentsResponse, error) {
 	appointmentFilters := s.parseFilters(filters)
+	if err := s.validateTypeFilter(ctx, tenantID, appointmentFilters.Type); err != nil {
+		return nil, err
+	}
 
 	appointments, total, err := s.repo.List(ctx, appointmentFilters, tenantID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	return CreatePaginatedResponse(appointments, params, total), nil
+	resp := CreatePaginatedResponse(appointments, params, total)
+	applyDisplay(s.calendarSettings(ctx, tenantID), resp.Data)
+	return resp, nil
 }
 
-// UpdateAppointment updates an appointment
+// UpdateAppointment updates an appointment. Changing the type re-applies its
+// default duration unless a duration is also given, and the vet and room are
+// checked again whenever the slot moves or changes length.
 func (s *Service) UpdateAppointment(ctx context.Context, id string, dto UpdateAppointmentDTO, tenantID primitive.ObjectID, updatedBy primitive.ObjectID) (*AppointmentResponse, error) {
 	appointmentID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -214,18 +571,78 @@ func (s *Service) UpdateAppointment(ctx context.Context, id string, dto UpdateAp
 	}
 
 	updates := bson.M{}
+	var warnings []AppointmentWarning
+
+	// The room kind only needs checking when the type or the room changes
+	var roomKind string
+	var newType *AppointmentTypeConfig
+	if dto.Type != nil {
+		apptType, err := s.resolveAppointmentType(ctx, tenantID, *dto.Type, appointment.Type)
+		if err != nil {
+			return nil, err
+		}
+		if apptType.RequiresVet && appointment.VeterinarianID.IsZero() {
+			return nil, ErrVeterinarianRequired
+		}
+		roomKind = apptType.RoomKind
+		updates["type"] = *dto.Type
+		if apptType.Key != appointment.Type {
+			newType = apptType
+		}
+	} else if dto.RoomID != nil {
+		if apptType, err := s.resolveAppointmentType(ctx, tenantID, appointment.Type, appointment.Type); err == nil {
+			roomKind = apptType.RoomKind
+		}
+	}
 
+	duration, adjusted, err := typeChangeDuration(appointment, newType, dto.Duration)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, adjusted...)
+	if dto.Duration != nil || duration != appointment.Duration {
+		updates["duration"] = duration
+	}
+	durationChanged := duration != appointment.Duration
+
+	priority := appointment.Priority
+	if dto.Priority != nil {
+		priority = *dto.Priority
+	}
+	scheduledAt := appointment.ScheduledAt
 	if dto.ScheduledAt != nil {
-		if err := s.validateAppointmentTime(*dto.ScheduledAt); err != nil {
+		if err := s.validateAppointmentTime(ctx, tenantID, *dto.ScheduledAt); err != nil {
+			return nil, err
+		}
+		if err := s.validateBookingWindow(ctx, tenantID, *dto.ScheduledAt, priority); err != nil {
+			return nil, err
+		}
+		scheduledAt = *dto.ScheduledAt
+
+		updates["scheduled_at"] = scheduledAt
+		// Staff moving the appointment settles any pending owner request
+		if appointment.RescheduleRequest != nil {
+			updates["reschedule_request"] = nil
+		}
+	}
+
+	// The type's lead time is checked again when the slot or the type changes
+	if dto.ScheduledAt != nil || newType != nil {
+		apptType := newType
+		if apptType == nil {
+			apptType, _ = s.resolveAppointmentType(ctx, tenantID, appointment.Type, appointment.Type)
+		}
+		if err := s.checkTypeNotice(ctx, tenantID, apptType, scheduledAt, priority); err != nil {
 			return nil, err
 		}
+	}
 
-		duration := appointment.Duration
-		if dto.Duration != nil {
-			duration = *dto.Duration
+	if (dto.ScheduledAt != nil || durationChanged) && !appointment.VeterinarianID.IsZero() {
+		if err := s.checkOnDuty(ctx, tenantID, appointment.VeterinarianID, scheduledAt, duration); err != nil {
+			return nil, err
 		}
 
-		hasConflict, err := s.repo.CheckConflicts(ctx, appointment.VeterinarianID, *dto.ScheduledAt, duration, &appointmentID, tenantID)
+		hasConflict, err := s.repo.CheckConflicts(ctx, appointment.VeterinarianID, scheduledAt, duration, &appointmentID, tenantID)
 		if err != nil {
 			return nil, err
 		}
@@ -233,20 +650,38 @@ func (s *Service) UpdateAppointment(ctx context.Context, id string, dto UpdateAp
 		if hasConflict {
 			return nil, ErrAppointmentConflict
 		}
-
-		updates["scheduled_at"] = *dto.ScheduledAt
 	}
 
-	if dto.Duration != nil {
-		updates["duration"] = *dto.Duration
+	roomID := appointment.RoomID
+	if dto.RoomID != nil {
+		if roomID, err = parseRoomID(*dto.RoomID); err != nil {
+			return nil, err
+		}
+		if roomID == nil {
+			updates["room_id"] = nil
+		} else {
+			updates["room_id"] = *roomID
+		}
+	}
+	if roomKind != "" && roomID == nil {
+		return nil, ErrRoomRequired
+	}
+	if roomID != nil && (dto.ScheduledAt != nil || durationChanged || dto.RoomID != nil || dto.Type != nil) {
+		if err := s.checkRoom(ctx, tenantID, *roomID, roomKind, scheduledAt, duration, &appointmentID); err != nil {
+			return nil, err
+		}
 	}
 
-	if dto.Type != nil {
-		updates["type"] = *dto.Type
+	if newType != nil {
+		warnings = append(warnings, s.depositWarnings(ctx, appointment, newType)...)
 	}
 
 	if dto.Priority != nil {
 		updates["priority"] = *dto.Priority
+		// Staff setting the priority settles an owner's pending triage
+		if appointment.RequestedPriority != "" {
+			updates["requested_priority"] = ""
+		}
 	}
 
 	if dto.Reason != nil {
@@ -257,6 +692,14 @@ func (s *Service) UpdateAppointment(ctx context.Context, id string, dto UpdateAp
 		updates["notes"] = *dto.Notes
 	}
 
+	if dto.DisableReminders != nil {
+		updates["disable_reminders"] = *dto.DisableReminders
+	}
+
+	if dto.OwnerModifiable != nil {
+		updates["owner_modifiable"] = *dto.OwnerModifiable
+	}
+
 	updates["updated_at"] = time.Now()
 
 	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
@@ -268,7 +711,13 @@ func (s *Service) UpdateAppointment(ctx context.Context, id string, dto UpdateAp
 		return nil, err
 	}
 
-	return updatedAppointment.ToResponse(), nil
+	if dto.Priority != nil {
+		s.notifyEscalation(ctx, appointment, updatedAppointment)
+	}
+
+	resp := updatedAppointment.ToResponse()
+	resp.Warnings = warnings
+	return resp, nil
 }
 
 // UpdateStatus updates an appointment status
@@ -283,9 +732,17 @@ func (s *Service) UpdateStatus(ctx context.Context, id string, dto UpdateStatusD
 		return nil, err
 	}
 
-	if !appointment.CanTransitionTo(dto.Status) {
+	workflow := s.statusWorkflow(ctx, tenantID)
+	if !workflow.canTransition(appointment.Status, dto.Status) {
 		return nil, ErrInvalidStatus(appointment.Status, dto.Status)
 	}
+	// Custom statuses run the side effects of the built-in status they act as
+	effective := workflow.effectiveStatus(dto.Status)
+
+	// A quick weight reading is taken when the patient is checked in or the visit ends
+	if dto.Weight != 0 && effective != AppointmentStatusInProgress && effective != AppointmentStatusCompleted {
+		return nil, ErrValidationFailed("weight", "weight can only be recorded when starting or completing an appointment")
+	}
 
 	now := time.Now()
 	updates := bson.M{
@@ -293,11 +750,29 @@ func (s *Service) UpdateStatus(ctx context.Context, id string, dto UpdateStatusD
 		"updated_at": now,
 	}
 
-	switch dto.Status {
+	reason := dto.Reason
+	var warnings []AppointmentWarning
+	switch effective {
 	case AppointmentStatusConfirmed:
+		if err := s.checkIntake(ctx, appointment); err != nil {
+			return nil, err
+		}
 		updates["confirmed_at"] = now
 	case AppointmentStatusInProgress:
+		if err := s.checkConsent(ctx, appointment); err != nil {
+			return nil, err
+		}
 		updates["started_at"] = now
+		if dto.ShiftStart {
+			shifted, w, err := s.shiftLateStart(ctx, appointment, now, updates, tenantID)
+			if err != nil {
+				return nil, err
+			}
+			warnings = w
+			if shifted > 0 && reason == "" {
+				reason = fmt.Sprintf("Llegada tarde: inicio ajustado %d min", shifted)
+			}
+		}
 	case AppointmentStatusCompleted:
 		updates["completed_at"] = now
 	case AppointmentStatusCancelled, AppointmentStatusNoShow:
@@ -307,41 +782,145 @@ func (s *Service) UpdateStatus(ctx context.Context, id string, dto UpdateStatusD
 		}
 	}
 
+	// The owner notification is stored with the status change, so a crash
+	// before it reaches the outbox cannot lose it
+	pending := s.statusNotification(appointment, effective, dto, now)
+	if pending != nil {
+		updates[notifications.PendingNotificationsField] = append(appointment.PendingNotifications, *pending)
+	}
+
 	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
 		return nil, err
 	}
 
+	if pending != nil {
+		s.notificationSvc.Enqueue(ctx, Collection, appointment.ID, *pending)
+	}
+
 	transition := &AppointmentStatusTransition{
 		TenantID:      tenantID,
 		AppointmentID: appointmentID,
 		FromStatus:    appointment.Status,
 		ToStatus:      dto.Status,
 		ChangedBy:     changedBy,
-		Reason:        dto.Reason,
+		Reason:        reason,
 		CreatedAt:     now,
 	}
 
 	s.repo.CreateStatusTransition(ctx, transition)
+	s.notifyStatusSubscribers(ctx, appointment, dto.Status, reason, changedBy)
 
-	if dto.Status == AppointmentStatusConfirmed {
-		s.notificationSvc.Send(ctx, &notifications.SendDTO{
-			OwnerID:  appointment.OwnerID.Hex(),
-			TenantID: tenantID.Hex(),
-			Type:     notifications.TypeAppointmentConfirmed,
-			Title:    "Cita confirmada",
-			Body:     fmt.Sprintf("Tu cita del %s ha sido confirmada", appointment.ScheduledAt.Format("02/01/2006 15:04")),
-			Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
-			SendPush: true,
-		})
-	} else if dto.Status == AppointmentStatusCancelled {
-		s.notificationSvc.Send(ctx, &notifications.SendDTO{
-			OwnerID:  appointment.OwnerID.Hex(),
+	if dto.Weight != 0 {
+		if err := s.weights.RecordFromAppointment(ctx, tenantID, appointment.PatientID, appointment.ID, changedBy, dto.Weight); err != nil {
+			slog.Error("failed to record appointment weight", "appointment_id", appointment.ID.Hex(), "error", err)
+		}
+	}
+
+	// Only completed visits earn points and get billed; cancellations and no-shows never reach here
+	if dto.Status == AppointmentStatusCompleted {
+		if err := s.loyalty.AccrueVisit(ctx, tenantID, appointment.OwnerID, appointment.ID); err != nil {
+			slog.Error("failed to accrue loyalty points", "appointment_id", appointment.ID.Hex(), "error", err)
+		}
+		s.draftCompletionInvoice(ctx, appointment, tenantID, changedBy)
+	}
+
+	updatedAppointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := updatedAppointment.ToResponse()
+	resp.Warnings = warnings
+	return resp, nil
+}
+
+// ReassignVeterinarian hands an active appointment over to another vet, e.g.
+// when the current one goes off shift mid-visit. The new vet's agenda is
+// checked for conflicts, the handoff is written to the status history and
+// both vets are notified.
+func (s *Service) ReassignVeterinarian(ctx context.Context, id string, dto ReassignAppointmentDTO, tenantID primitive.ObjectID, changedBy primitive.ObjectID) (*AppointmentResponse, error) {
+	appointmentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment ID format")
+	}
+	newVetID, err := primitive.ObjectIDFromHex(dto.VeterinarianID)
+	if err != nil || newVetID.IsZero() {
+		return nil, ErrValidationFailed("veterinarian_id", "invalid veterinarian ID format")
+	}
+
+	appointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appointment.IsActive() {
+		return nil, ErrCannotReassignClosed
+	}
+	if appointment.VeterinarianID == newVetID {
+		return nil, ErrValidationFailed("veterinarian_id", "appointment is already assigned to this veterinarian")
+	}
+
+	newVet, err := s.userRepo.FindByID(ctx, newVetID.Hex())
+	if err != nil {
+		return nil, ErrVeterinarianNotFound
+	}
+
+	if err := s.checkOnDuty(ctx, tenantID, newVetID, appointment.ScheduledAt, appointment.Duration); err != nil {
+		return nil, err
+	}
+
+	hasConflict, err := s.repo.CheckConflicts(ctx, newVetID, appointment.ScheduledAt, appointment.Duration, &appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if hasConflict {
+		return nil, ErrVeterinarianNotAvailable
+	}
+
+	now := time.Now()
+	previousVetID := appointment.VeterinarianID
+	updates := bson.M{
+		"veterinarian_id": newVetID,
+		"updated_at":      now,
+	}
+	if appointment.OriginalVeterinarianID == nil && !previousVetID.IsZero() {
+		updates["original_veterinarian_id"] = previousVetID
+	}
+
+	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
+		return nil, err
+	}
+
+	s.repo.CreateStatusTransition(ctx, &AppointmentStatusTransition{
+		TenantID:           tenantID,
+		AppointmentID:      appointmentID,
+		FromStatus:         appointment.Status,
+		ToStatus:           appointment.Status,
+		ChangedBy:          changedBy,
+		Reason:             "Reasignada: " + dto.Reason,
+		FromVeterinarianID: &previousVetID,
+		ToVeterinarianID:   &newVetID,
+		CreatedAt:          now,
+	})
+
+	when := appointment.ScheduledAt.Format("02/01/2006 15:04")
+	data := map[string]string{"appointment_id": appointment.ID.Hex()}
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   newVetID.Hex(),
+		TenantID: tenantID.Hex(),
+		Type:     notifications.TypeStaffNewAppointment,
+		Title:    "Cita reasignada a ti",
+		Body:     fmt.Sprintf("Se te ha asignado la cita del %s. Motivo: %s", when, dto.Reason),
+		Data:     data,
+	})
+	if !previousVetID.IsZero() {
+		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+			UserID:   previousVetID.Hex(),
 			TenantID: tenantID.Hex(),
-			Type:     notifications.TypeAppointmentCancelled,
-			Title:    "Cita cancelada",
-			Body:     fmt.Sprintf("La cita del %s ha sido cancelada. Razón: %s", appointment.ScheduledAt.Format("02/01/2006 15:04"), dto.Reason),
-			Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
-			SendPush: true,
+			Type:     notifications.TypeStaffGeneral,
+			Title:    "Cita reasignada",
+			Body:     fmt.Sprintf("La cita del %s fue reasignada a %s. Motivo: %s", when, newVet.Name, dto.Reason),
+			Data:     data,
 		})
 	}
 
@@ -354,7 +933,7 @@ func (s *Service) UpdateStatus(ctx context.Context, id string, dto UpdateStatusD
 }
 
 // DeleteAppointment deletes an appointment
-func (s *Service) DeleteAppointment(ctx context.Context, id string, tenantID primitive.ObjectID, deletedBy primitive.ObjectID) error {
+func (s *Service) DeleteAppointment(ctx context.Context, id string, tenantID primitive.ObjectID, deletedBy primitive.ObjectID, dto DeleteAppointmentDTO) error {
 	appointmentID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return ErrValidationFailed("id", "invalid appointment ID format")
@@ -365,17 +944,51 @@ func (s *Service) DeleteAppointment(ctx context.Context, id string, tenantID pri
 		return err
 	}
 
-	return s.repo.Delete(ctx, appointmentID, tenantID)
+	// Appointments that already produced a visit record are clinical history;
+	// removing them needs an explicit override that is kept in the audit log.
+	records, err := s.recordCounter.CountByAppointment(ctx, appointmentID, tenantID)
+	if err != nil {
+		return err
+	}
+	if records > 0 {
+		if !dto.Force {
+			return ErrAppointmentHasMedicalRecords
+		}
+		if strings.TrimSpace(dto.Reason) == "" {
+			return ErrForceDeleteReasonRequired
+		}
+	}
+
+	if err := s.repo.Delete(ctx, appointmentID, tenantID); err != nil {
+		return err
+	}
+
+	if records > 0 {
+		description := fmt.Sprintf("Forced deletion with %d linked medical records: %s", records, dto.Reason)
+		if err := s.auditLog.LogAppointmentAction(ctx, tenantID, deletedBy, appointmentID, audit.EventAppointmentDeleted, "force_delete", description); err != nil {
+			slog.Error("failed to audit forced appointment deletion", "appointment_id", id, "error", err)
+		}
+	}
+
+	return nil
 }
 
 // RequestAppointment creates an appointment request from mobile
-func (s *Service) RequestAppointment(ctx context.Context, dto MobileAppointmentRequestDTO, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*AppointmentResponse, error) {
+func (s *Service) RequestAppointment(ctx context.Context, dto MobileAppointmentRequestDTO, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*OwnerAppointmentResponse, error) {
 	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
 	if err != nil {
 		return nil, ErrValidationFailed("patient_id", "invalid patient ID format")
 	}
 
-	if err := s.validateAppointmentTime(dto.ScheduledAt); err != nil {
+	// Owners cannot rank their own request above the clinic's cap; what they
+	// asked for is kept for staff to triage instead
+	settings := s.prioritySettings(ctx, tenantID)
+	priority, requestedPriority := ownerPriority(settings, dto.Priority)
+
+	if err := s.validateAppointmentTime(ctx, tenantID, dto.ScheduledAt); err != nil {
+		return nil, err
+	}
+	if err := s.validateBookingWindow(ctx, tenantID, dto.ScheduledAt, priority); err != nil {
 		return nil, err
 	}
 
@@ -383,28 +996,41 @@ func (s *Service) RequestAppointment(ctx context.Context, dto MobileAppointmentR
 	if err != nil {
 		return nil, ErrPatientNotFound
 	}
+	if patient.IsDeceased() {
+		return nil, ErrPatientDeceased
+	}
 	if patient.OwnerID != ownerID {
 		return nil, ErrOwnerMismatch
 	}
 
+	// The request holds the type's default slot until the clinic sets the real duration
+	apptType, err := s.resolveAppointmentType(ctx, tenantID, dto.Type, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTypeNotice(ctx, tenantID, apptType, dto.ScheduledAt, priority); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPatientAvailability(ctx, tenantID, patientID, dto.ScheduledAt, apptType.DefaultDuration, nil); err != nil {
+		return nil, err
+	}
+
 	owner, err := s.ownerRepo.FindByID(ctx, ownerID.Hex())
 	if err != nil {
 		return nil, ErrOwnerNotFound
 	}
 
-	priority := dto.Priority
-	if priority == "" {
-		priority = AppointmentPriorityNormal
-	}
+	vetID := s.autoAssign(ctx, tenantID, apptType, dto.ScheduledAt, apptType.DefaultDuration)
 
 	now := time.Now()
 	appointment := &Appointment{
 		TenantID:       tenantID,
 		PatientID:      patientID,
 		OwnerID:        ownerID,
-		VeterinarianID: primitive.NilObjectID,
+		VeterinarianID: vetID,
 		ScheduledAt:    dto.ScheduledAt,
-		Duration:       30,
+		Duration:       apptType.DefaultDuration,
 		Type:           dto.Type,
 		Status:         AppointmentStatusScheduled,
 		Priority:       priority,
@@ -413,35 +1039,68 @@ func (s *Service) RequestAppointment(ctx context.Context, dto MobileAppointmentR
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
+	appointment.DisableReminders = dto.DisableReminders
+	appointment.RequestedPriority = requestedPriority
+
+	if err := s.prepareDeposit(ctx, appointment, apptType, AppointmentStatusScheduled); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Create(ctx, appointment); err != nil {
 		return nil, err
 	}
 
+	if appointment.Deposit != nil {
+		s.notifyDepositDue(ctx, appointment, patient.Name)
+	}
+
+	// A request waiting for triage is flagged to staff with the priority the
+	// owner asked for, so an emergency does not sit in the inbox as normal
+	urgent := priorityAtLeast(priority, settings.UrgentAlertFrom)
+	title, body := "Nueva solicitud de cita", fmt.Sprintf("Solicitud de cita de %s para %s", owner.Name, patient.Name)
+	data := map[string]string{"appointment_id": appointment.ID.Hex()}
+	if requestedPriority != "" {
+		urgent = urgent || priorityAtLeast(requestedPriority, settings.UrgentAlertFrom)
+		title = "Solicitud de cita por triar"
+		body += fmt.Sprintf(". El propietario la marcó con prioridad %s", requestedPriority)
+		data["requested_priority"] = requestedPriority
+	}
 	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
 		UserID:   primitive.NilObjectID.Hex(),
 		TenantID: tenantID.Hex(),
 		Type:     notifications.TypeStaffNewAppointment,
-		Title:    "Nueva solicitud de cita",
-		Body:     fmt.Sprintf("Solicitud de cita de %s para %s", owner.Name, patient.Name),
-		Data:     map[string]string{"appointment_id": appointment.ID.Hex()},
+		Title:    urgentTitle(title, urgent),
+		Body:     body,
+		Data:     data,
+		Urgent:   urgent,
 	})
+	if !vetID.IsZero() {
+		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+			UserID:   vetID.Hex(),
+			TenantID: tenantID.Hex(),
+			Type:     notifications.TypeStaffNewAppointment,
+			Title:    urgentTitle("Cita asignada a ti", urgent),
+			Body:     fmt.Sprintf("Se te asignó la solicitud de cita de %s para %s", owner.Name, patient.Name),
+			Data:     data,
+			Urgent:   urgent,
+		})
+	}
 
-	return appointment.ToResponse(), nil
+	return s.ownerView(ctx, appointment, appointment.ToResponse()), nil
 }
 
 // GetOwnerAppointments gets appointments for a specific owner
-func (s *Service) GetOwnerAppointments(ctx context.Context, ownerID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) (*PaginatedAppointmentsResponse, error) {
+func (s *Service) GetOwnerAppointments(ctx context.Context, ownerID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) (*PaginatedOwnerAppointmentsResponse, error) {
 	appointments, total, err := s.repo.FindByOwner(ctx, ownerID, tenantID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	return CreatePaginatedResponse(appointments, params, total), nil
+	return s.ownerAppointments(ctx, tenantID, appointments, params, total), nil
 }
 
 // GetOwnerAppointment gets a specific appointment for an owner
-func (s *Service) GetOwnerAppointment(ctx context.Context, id string, tenantID primitive.ObjectID, ownerID primitive.ObjectID, populate bool) (*AppointmentResponse, error) {
+func (s *Service) GetOwnerAppointment(ctx context.Context, id string, tenantID primitive.ObjectID, ownerID primitive.ObjectID, populate bool) (*OwnerAppointmentResponse, error) {
 	appointmentID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, ErrValidationFailed("id", "invalid appointment ID format")
@@ -457,14 +1116,18 @@ func (s *Service) GetOwnerAppointment(ctx context.Context, id string, tenantID p
 	}
 
 	if populate {
-		return s.populateAppointment(ctx, appointment, tenantID)
+		resp, err := s.populateAppointment(ctx, appointment, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		return s.ownerView(ctx, appointment, resp), nil
 	}
 
-	return appointment.ToResponse(), nil
+	return s.ownerView(ctx, appointment, appointment.ToResponse()), nil
 }
 
 // CancelAppointment cancels an appointment (used by mobile)
-func (s *Service) CancelAppointment(ctx context.Context, id string, reason string, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*AppointmentResponse, error) {
+func (s *Service) CancelAppointment(ctx context.Context, id string, reason string, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*OwnerAppointmentResponse, error) {
 	appointmentID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, ErrValidationFailed("id", "invalid appointment ID format")
@@ -480,19 +1143,24 @@ func (s *Service) CancelAppointment(ctx context.Context, id string, reason strin
 	}
 
 	if appointment.Status == AppointmentStatusCancelled {
-		return appointment.ToResponse(), nil
+		return s.ownerView(ctx, appointment, appointment.ToResponse()), nil
 	}
 
-	if !appointment.CanTransitionTo(AppointmentStatusCancelled) {
+	if err := s.checkOwnerCanModify(ctx, appointment); err != nil {
+		return nil, err
+	}
+
+	if !s.statusWorkflow(ctx, tenantID).canTransition(appointment.Status, AppointmentStatusCancelled) {
 		return nil, ErrInvalidStatus(appointment.Status, AppointmentStatusCancelled)
 	}
 
 	now := time.Now()
 	updates := bson.M{
-		"status":        AppointmentStatusCancelled,
-		"cancelled_at":  now,
-		"cancel_reason": reason,
-		"updated_at":    now,
+		"status":             AppointmentStatusCancelled,
+		"cancelled_at":       now,
+		"cancel_reason":      reason,
+		"cancelled_by_owner": true,
+		"updated_at":         now,
 	}
 
 	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
@@ -513,7 +1181,77 @@ func (s *Service) CancelAppointment(ctx context.Context, id string, reason strin
 		return nil, err
 	}
 
-	return updatedAppointment.ToResponse(), nil
+	return s.ownerView(ctx, updatedAppointment, updatedAppointment.ToResponse()), nil
+}
+
+// AcknowledgeAppointment records that the owner saw a reminder. When the clinic
+// allows owner self-confirmation, a scheduled appointment is also confirmed.
+// Repeated acknowledgments keep the first timestamp.
+func (s *Service) AcknowledgeAppointment(ctx context.Context, id string, tenantID primitive.ObjectID, ownerID primitive.ObjectID) (*OwnerAppointmentResponse, error) {
+	appointmentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment ID format")
+	}
+
+	appointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.OwnerID != ownerID {
+		return nil, ErrOwnerMismatch
+	}
+
+	if !appointment.IsActive() {
+		return nil, ErrInvalidStatus(appointment.Status, AppointmentStatusConfirmed)
+	}
+
+	now := time.Now()
+	updates := bson.M{"updated_at": now}
+	if appointment.AcknowledgedAt == nil {
+		updates["acknowledged_at"] = now
+	}
+
+	// The acknowledgment is still recorded when a missing intake form holds the confirmation
+	confirm := appointment.Status == AppointmentStatusScheduled && s.ownerConfirmationEnabled(ctx, tenantID) && s.checkIntake(ctx, appointment) == nil
+	if confirm {
+		updates["status"] = AppointmentStatusConfirmed
+		updates["confirmed_at"] = now
+	}
+
+	if err := s.repo.Update(ctx, appointmentID, updates, tenantID); err != nil {
+		return nil, err
+	}
+
+	if confirm {
+		s.repo.CreateStatusTransition(ctx, &AppointmentStatusTransition{
+			TenantID:      tenantID,
+			AppointmentID: appointmentID,
+			FromStatus:    appointment.Status,
+			ToStatus:      AppointmentStatusConfirmed,
+			ChangedBy:     ownerID,
+			Reason:        "Confirmada por el propietario desde el recordatorio",
+			CreatedAt:     now,
+		})
+	}
+
+	updatedAppointment, err := s.repo.FindByID(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ownerView(ctx, updatedAppointment, updatedAppointment.ToResponse()), nil
+}
+
+// ownerConfirmationEnabled reports whether owners may confirm their own
+// appointments. Lookup failures leave confirmation to the staff.
+func (s *Service) ownerConfirmationEnabled(ctx context.Context, tenantID primitive.ObjectID) bool {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping owner confirmation", "tenant_id", tenantID.Hex(), "error", err)
+		return false
+	}
+	return t.Settings.AllowOwnerConfirmation
 }
 
 // GetCalendarView gets a calendar view of appointments
@@ -539,12 +1277,15 @@ func (s *Service) GetCalendarView(ctx context.Context, from, to time.Time, veter
 	for i, appointment := range appointments {
 		response[i] = *appointment.ToResponse()
 	}
+	applyDisplay(s.calendarSettings(ctx, tenantID), response)
 
 	return response, nil
 }
 
-// CheckAvailability checks veterinarian availability
-func (s *Service) CheckAvailability(ctx context.Context, vetID string, scheduledAt time.Time, duration int, excludeID *string, tenantID primitive.ObjectID) (*AvailabilityResponse, error) {
+// CheckAvailability checks veterinarian availability and, when roomID is
+// given, whether the room still has capacity for the slot. With typeKey, slots
+// inside the lead time of that type are reported as unavailable.
+func (s *Service) CheckAvailability(ctx context.Context, vetID string, scheduledAt time.Time, duration int, excludeID *string, roomID string, typeKey string, tenantID primitive.ObjectID) (*AvailabilityResponse, error) {
 	veterinarianID, err := primitive.ObjectIDFromHex(vetID)
 	if err != nil {
 		return nil, ErrValidationFailed("veterinarian_id", "invalid veterinarian ID format")
@@ -559,6 +1300,28 @@ func (s *Service) CheckAvailability(ctx context.Context, vetID string, scheduled
 		excludeOID = &oid
 	}
 
+	roomOID, err := parseRoomID(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A holiday closes the whole day, regardless of the vet's agenda
+	if holidayErr := s.checkHoliday(ctx, tenantID, scheduledAt); holidayErr != nil {
+		return &AvailabilityResponse{Available: false, ClosedReason: holidayErr.Error()}, nil
+	}
+	if dutyErr := s.checkOnDuty(ctx, tenantID, veterinarianID, scheduledAt, duration); dutyErr != nil {
+		return &AvailabilityResponse{Available: false, OffDuty: true}, nil
+	}
+	if typeKey != "" {
+		apptType, err := s.resolveAppointmentType(ctx, tenantID, typeKey, typeKey)
+		if err != nil {
+			return nil, err
+		}
+		if apptType.MinNoticeHours > 0 && scheduledAt.Before(time.Now().Add(time.Duration(apptType.MinNoticeHours)*time.Hour)) {
+			return &AvailabilityResponse{Available: false, TypeNoticeHours: apptType.MinNoticeHours}, nil
+		}
+	}
+
 	hasConflict, err := s.repo.CheckConflicts(ctx, veterinarianID, scheduledAt, duration, excludeOID, tenantID)
 	if err != nil {
 		return nil, err
@@ -568,6 +1331,15 @@ func (s *Service) CheckAvailability(ctx context.Context, vetID string, scheduled
 		Available: !hasConflict,
 	}
 
+	if roomOID != nil {
+		_, room, err := s.roomAvailability(ctx, tenantID, *roomOID, scheduledAt, duration, excludeOID)
+		if err != nil {
+			return nil, err
+		}
+		response.Room = room
+		response.Available = response.Available && room.Available
+	}
+
 	if hasConflict {
 		endTime := scheduledAt.Add(time.Duration(duration) * time.Minute)
 		conflicts, err := s.repo.FindByVeterinarian(ctx, veterinarianID, scheduledAt.Add(-1*time.Hour), endTime.Add(1*time.Hour), tenantID)
@@ -652,5 +1424,9 @@ func (s *Service) parseFilters(filters map[string]interface{}) appointmentFilter
 		result.Priority = &priority
 	}
 
+	if triage, ok := filters["triage"].(bool); ok {
+		result.TriagePending = triage
+	}
+
 	return result
 }
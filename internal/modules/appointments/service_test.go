@@ -2,14 +2,22 @@ package appointments
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/consents"
+	"github.com/eren_dev/go_server/internal/modules/holidays"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
+	"github.com/eren_dev/go_server/internal/modules/medical_records"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"github.com/stretchr/testify/assert"
@@ -47,22 +55,24 @@ func getNextSunday() time.Time {
 }
 
 type mockAppointmentRepo struct {
-	CreateFunc                 func(ctx context.Context, appointment *Appointment) error
-	FindByIDFunc               func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error)
-	ListFunc                   func(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
-	UpdateFunc                 func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
-	DeleteFunc                 func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
-	FindByDateRangeFunc        func(ctx context.Context, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error)
-	FindByPatientFunc          func(ctx context.Context, patientID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
-	FindByOwnerFunc            func(ctx context.Context, ownerID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
-	FindByVeterinarianFunc     func(ctx context.Context, vetID primitive.ObjectID, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error)
-	CheckConflictsFunc         func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error)
-	CreateStatusTransitionFunc func(ctx context.Context, transition *AppointmentStatusTransition) error
-	GetStatusHistoryFunc       func(ctx context.Context, appointmentID primitive.ObjectID) ([]AppointmentStatusTransition, error)
-	CountByStatusFunc          func(ctx context.Context, status string, tenantID primitive.ObjectID) (int64, error)
-	FindUpcomingFunc           func(ctx context.Context, tenantID primitive.ObjectID, hours int) ([]Appointment, error)
-	FindUnconfirmedBeforeFunc  func(ctx context.Context, before time.Time) ([]Appointment, error)
-	EnsureIndexesFunc          func(ctx context.Context) error
+	CreateFunc                  func(ctx context.Context, appointment *Appointment) error
+	FindByIDFunc                func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error)
+	ListFunc                    func(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
+	UpdateFunc                  func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
+	DeleteFunc                  func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
+	FindByDateRangeFunc         func(ctx context.Context, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error)
+	FindByPatientFunc           func(ctx context.Context, patientID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
+	FindByOwnerFunc             func(ctx context.Context, ownerID primitive.ObjectID, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error)
+	FindByVeterinarianFunc      func(ctx context.Context, vetID primitive.ObjectID, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error)
+	CheckConflictsFunc          func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error)
+	CheckPatientConflictsFunc   func(ctx context.Context, patientID primitive.ObjectID, from, to time.Time, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error)
+	FindNextForVeterinarianFunc func(ctx context.Context, vetID primitive.ObjectID, after time.Time, excludeID primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error)
+	CreateStatusTransitionFunc  func(ctx context.Context, transition *AppointmentStatusTransition) error
+	GetStatusHistoryFunc        func(ctx context.Context, appointmentID primitive.ObjectID) ([]AppointmentStatusTransition, error)
+	CountByStatusFunc           func(ctx context.Context, status string, tenantID primitive.ObjectID) (int64, error)
+	FindUpcomingFunc            func(ctx context.Context, tenantID primitive.ObjectID, hours int) ([]Appointment, error)
+	FindUnconfirmedBeforeFunc   func(ctx context.Context, before time.Time) ([]Appointment, error)
+	EnsureIndexesFunc           func(ctx context.Context) error
 }
 
 func (m *mockAppointmentRepo) Create(ctx context.Context, appointment *Appointment) error {
@@ -86,6 +96,10 @@ func (m *mockAppointmentRepo) List(ctx context.Context, filters appointmentFilte
 	return nil, 0, nil
 }
 
+func (m *mockAppointmentRepo) ForEach(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, fn func(*Appointment) error) error {
+	return nil
+}
+
 func (m *mockAppointmentRepo) Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, id, updates, tenantID)
@@ -135,6 +149,20 @@ func (m *mockAppointmentRepo) CheckConflicts(ctx context.Context, vetID primitiv
 	return false, nil
 }
 
+func (m *mockAppointmentRepo) CheckPatientConflicts(ctx context.Context, patientID primitive.ObjectID, from, to time.Time, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+	if m.CheckPatientConflictsFunc != nil {
+		return m.CheckPatientConflictsFunc(ctx, patientID, from, to, excludeID, tenantID)
+	}
+	return false, nil
+}
+
+func (m *mockAppointmentRepo) FindNextForVeterinarian(ctx context.Context, vetID primitive.ObjectID, after time.Time, excludeID primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+	if m.FindNextForVeterinarianFunc != nil {
+		return m.FindNextForVeterinarianFunc(ctx, vetID, after, excludeID, tenantID)
+	}
+	return nil, nil
+}
+
 func (m *mockAppointmentRepo) CreateStatusTransition(ctx context.Context, transition *AppointmentStatusTransition) error {
 	if m.CreateStatusTransitionFunc != nil {
 		return m.CreateStatusTransitionFunc(ctx, transition)
@@ -156,6 +184,10 @@ func (m *mockAppointmentRepo) CountByStatus(ctx context.Context, status string,
 	return 0, nil
 }
 
+func (m *mockAppointmentRepo) CountPendingForVeterinarian(ctx context.Context, vetID primitive.ObjectID, from time.Time, tenantID primitive.ObjectID) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockAppointmentRepo) FindUpcoming(ctx context.Context, tenantID primitive.ObjectID, hours int) ([]Appointment, error) {
 	if m.FindUpcomingFunc != nil {
 		return m.FindUpcomingFunc(ctx, tenantID, hours)
@@ -170,6 +202,50 @@ func (m *mockAppointmentRepo) FindUnconfirmedBefore(ctx context.Context, before
 	return nil, nil
 }
 
+func (m *mockAppointmentRepo) FindUnassigned(ctx context.Context, tenantID primitive.ObjectID, from, to time.Time) ([]Appointment, error) {
+	return nil, nil
+}
+
+func (m *mockAppointmentRepo) FindStaleActive(ctx context.Context, tenantID primitive.ObjectID, startedBefore time.Time) ([]Appointment, error) {
+	return nil, nil
+}
+
+func (m *mockAppointmentRepo) MarkStaleAlerted(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error {
+	return nil
+}
+
+func (m *mockAppointmentRepo) FindOverrunning(ctx context.Context, tenantID primitive.ObjectID, startedBefore time.Time) ([]Appointment, error) {
+	return nil, nil
+}
+
+func (m *mockAppointmentRepo) MarkDelayNotified(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, minutes int, at time.Time) error {
+	return nil
+}
+
+func (m *mockAppointmentRepo) FindForDeposit(ctx context.Context, id primitive.ObjectID) (*Appointment, error) {
+	return nil, ErrAppointmentNotFound
+}
+
+func (m *mockAppointmentRepo) FindByDepositLinkID(ctx context.Context, linkID string) (*Appointment, error) {
+	return nil, ErrAppointmentNotFound
+}
+
+func (m *mockAppointmentRepo) FindExpiredDeposits(ctx context.Context, now time.Time) ([]Appointment, error) {
+	return nil, nil
+}
+
+func (m *mockAppointmentRepo) UpdateIfStatus(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, status string, updates bson.M) (bool, error) {
+	return true, nil
+}
+
+func (m *mockAppointmentRepo) CountRoomBookings(ctx context.Context, roomID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockAppointmentRepo) AddAttachment(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, attachment AppointmentAttachment, limit int) (bool, error) {
+	return true, nil
+}
+
 func (m *mockAppointmentRepo) EnsureIndexes(ctx context.Context) error {
 	if m.EnsureIndexesFunc != nil {
 		return m.EnsureIndexesFunc(ctx)
@@ -193,7 +269,7 @@ func (m *mockPatientRepo) Create(ctx context.Context, p *patients.Patient) error
 	return nil
 }
 
-func (m *mockPatientRepo) FindAll(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]patients.Patient, int64, error) {
+func (m *mockPatientRepo) FindAll(ctx context.Context, tenantID primitive.ObjectID, filters patients.PatientFilters, params pagination.Params) ([]patients.Patient, int64, error) {
 	if m.FindAllFunc != nil {
 		return m.FindAllFunc(ctx, tenantID, params)
 	}
@@ -214,6 +290,26 @@ func (m *mockPatientRepo) FindByOwner(ctx context.Context, tenantID primitive.Ob
 	return nil, 0, nil
 }
 
+func (m *mockPatientRepo) FindPossibleDuplicates(ctx context.Context, tenantID, ownerID, speciesID primitive.ObjectID, name string) ([]patients.Patient, error) {
+	return nil, nil
+}
+
+func (m *mockPatientRepo) MarkDeceased(ctx context.Context, tenantID primitive.ObjectID, id string, deceasedAt time.Time) (*patients.Patient, error) {
+	return nil, nil
+}
+
+func (m *mockPatientRepo) CancelFutureAppointments(ctx context.Context, tenantID, patientID, changedBy primitive.ObjectID, reason string, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockPatientRepo) FindReachingAge(ctx context.Context, tenantID, speciesID primitive.ObjectID, bornAfter, bornUntil time.Time, key string) ([]patients.Patient, error) {
+	return nil, nil
+}
+
+func (m *mockPatientRepo) MarkAgeReminderSent(ctx context.Context, tenantID, patientID primitive.ObjectID, key string) error {
+	return nil
+}
+
 func (m *mockPatientRepo) Update(ctx context.Context, tenantID primitive.ObjectID, id string, dto *patients.UpdatePatientDTO) (*patients.Patient, error) {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, tenantID, id, dto)
@@ -295,10 +391,30 @@ func (m *mockOwnerRepo) RemovePushToken(ctx context.Context, id string, token st
 	return nil
 }
 
+func (m *mockOwnerRepo) PrunePushTokens(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockOwnerRepo) AddTenantID(ctx context.Context, id string, tenantID primitive.ObjectID) error {
 	return nil
 }
 
+func (m *mockOwnerRepo) PatientTenantIDs(ctx context.Context, id string) ([]primitive.ObjectID, error) {
+	return nil, nil
+}
+
+func (m *mockOwnerRepo) UpdateNotificationPrefs(ctx context.Context, id string, prefs owners.NotificationPreferences) error {
+	return nil
+}
+
+func (m *mockOwnerRepo) MarkContactVerified(ctx context.Context, id string, channel, target string) error {
+	return nil
+}
+
+func (m *mockOwnerRepo) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]*owners.Owner, error) {
+	return nil, nil
+}
+
 type mockUserRepo struct {
 	CreateFunc             func(ctx context.Context, dto *users.CreateUserDTO) (*users.User, error)
 	CreateWithPasswordFunc func(ctx context.Context, name, email, hashedPassword string) (*users.User, error)
@@ -366,9 +482,33 @@ func (m *mockUserRepo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockUserRepo) UpdateNotificationPrefs(ctx context.Context, id string, prefs users.NotificationPreferences) error {
+	return nil
+}
+
+func (m *mockUserRepo) RemoveTenant(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	return nil
+}
+
+func (m *mockUserRepo) FindWeeklyDigestSubscribers(ctx context.Context) ([]*users.User, error) {
+	return nil, nil
+}
+
+type mockTenantRepo struct {
+	FindByIDFunc func(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+func (m *mockTenantRepo) FindByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return &tenant.Tenant{}, nil
+}
+
 type mockNotificationSender struct {
 	SendFunc        func(ctx context.Context, dto *notifications.SendDTO) error
 	SendToStaffFunc func(ctx context.Context, dto *notifications.SendStaffDTO) error
+	EnqueueFunc     func(ctx context.Context, collection string, docID primitive.ObjectID, pending notifications.PendingNotification) error
 }
 
 func (m *mockNotificationSender) Send(ctx context.Context, dto *notifications.SendDTO) error {
@@ -378,6 +518,14 @@ func (m *mockNotificationSender) Send(ctx context.Context, dto *notifications.Se
 	return nil
 }
 
+// Enqueue falls back to Send, as the service does without an outbox
+func (m *mockNotificationSender) Enqueue(ctx context.Context, collection string, docID primitive.ObjectID, pending notifications.PendingNotification) error {
+	if m.EnqueueFunc != nil {
+		return m.EnqueueFunc(ctx, collection, docID, pending)
+	}
+	return m.Send(ctx, pending.SendDTO())
+}
+
 func (m *mockNotificationSender) SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error {
 	if m.SendToStaffFunc != nil {
 		return m.SendToStaffFunc(ctx, dto)
@@ -385,13 +533,66 @@ func (m *mockNotificationSender) SendToStaff(ctx context.Context, dto *notificat
 	return nil
 }
 
+type mockRecordCounter struct {
+	CountByAppointmentFunc func(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (int64, error)
+}
+
+func (m *mockRecordCounter) CountByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (int64, error) {
+	if m.CountByAppointmentFunc != nil {
+		return m.CountByAppointmentFunc(ctx, appointmentID, tenantID)
+	}
+	return 0, nil
+}
+
+type mockAuditLogger struct {
+	LogAppointmentActionFunc func(ctx context.Context, tenantID, userID, appointmentID primitive.ObjectID, eventType audit.EventType, action, description string) error
+}
+
+func (m *mockAuditLogger) LogUserAction(ctx context.Context, tenantID, userID primitive.ObjectID, eventType audit.EventType, action, description string) error {
+	return nil
+}
+
+func (m *mockAuditLogger) LogAppointmentAction(ctx context.Context, tenantID, userID, appointmentID primitive.ObjectID, eventType audit.EventType, action, description string) error {
+	if m.LogAppointmentActionFunc != nil {
+		return m.LogAppointmentActionFunc(ctx, tenantID, userID, appointmentID, eventType, action, description)
+	}
+	return nil
+}
+
+type mockHolidayCalendar struct {
+	HolidayOnFunc func(ctx context.Context, tenantID primitive.ObjectID, day time.Time) (*holidays.Holiday, error)
+}
+
+func (m *mockHolidayCalendar) HolidayOn(ctx context.Context, tenantID primitive.ObjectID, day time.Time) (*holidays.Holiday, error) {
+	if m.HolidayOnFunc != nil {
+		return m.HolidayOnFunc(ctx, tenantID, day)
+	}
+	return nil, nil
+}
+
+type mockLoyaltyAccruer struct {
+	AccrueVisitFunc func(ctx context.Context, tenantID, ownerID, appointmentID primitive.ObjectID) error
+}
+
+func (m *mockLoyaltyAccruer) AccrueVisit(ctx context.Context, tenantID, ownerID, appointmentID primitive.ObjectID) error {
+	if m.AccrueVisitFunc != nil {
+		return m.AccrueVisitFunc(ctx, tenantID, ownerID, appointmentID)
+	}
+	return nil
+}
+
 func newTestService(repo *mockAppointmentRepo, patientRepo *mockPatientRepo, ownerRepo *mockOwnerRepo, userRepo *mockUserRepo, notifSvc *mockNotificationSender) *Service {
 	return &Service{
 		repo:            repo,
 		patientRepo:     patientRepo,
 		ownerRepo:       ownerRepo,
 		userRepo:        userRepo,
+		tenantRepo:      &mockTenantRepo{},
+		recordCounter:   &mockRecordCounter{},
+		auditLog:        &mockAuditLogger{},
 		notificationSvc: notifSvc,
+		loyalty:         &mockLoyaltyAccruer{},
+		holidays:        &mockHolidayCalendar{},
 		cfg:             &config.Config{AppointmentBusinessStartHour: 8, AppointmentBusinessEndHour: 18},
 	}
 }
@@ -588,6 +789,75 @@ func TestCreateAppointment_TimeConflict(t *testing.T) {
 	assert.Equal(t, ErrAppointmentConflict, err)
 }
 
+func TestBookFollowUp_ConflictQueuesUnassigned(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	patientRepo := &mockPatientRepo{}
+	userRepo := &mockUserRepo{}
+
+	patientRepo.FindByIDFunc = func(ctx context.Context, tenantID primitive.ObjectID, id string) (*patients.Patient, error) {
+		return &patients.Patient{ID: testPatientID, TenantID: testTenantID, OwnerID: testOwnerID, Name: "Buddy", Active: true}, nil
+	}
+	userRepo.FindByIDFunc = func(ctx context.Context, id string) (*users.User, error) {
+		return &users.User{ID: testVetID, Name: "Dr. Smith"}, nil
+	}
+	repo.CheckConflictsFunc = func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+		return true, nil
+	}
+	var created *Appointment
+	repo.CreateFunc = func(ctx context.Context, appointment *Appointment) error {
+		appointment.ID = primitive.NewObjectID()
+		created = appointment
+		return nil
+	}
+
+	svc := newTestService(repo, patientRepo, &mockOwnerRepo{}, userRepo, &mockNotificationSender{})
+
+	recordID := primitive.NewObjectID()
+	booking, err := svc.BookFollowUp(context.Background(), medical_records.FollowUpRequest{
+		TenantID:       testTenantID,
+		RecordID:       recordID,
+		PatientID:      testPatientID,
+		VeterinarianID: testVetID,
+		ScheduledAt:    getNextMonday10AM(),
+		Type:           AppointmentTypeCheckup,
+		Reason:         "Control: otitis",
+		BookedBy:       testUserID,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, medical_records.NextVisitQueued, booking.Outcome)
+	assert.NotEmpty(t, booking.Note)
+	assert.Equal(t, created.ID, booking.AppointmentID)
+	assert.True(t, created.VeterinarianID.IsZero())
+	assert.Equal(t, &recordID, created.FollowUpOf)
+}
+
+func TestBookFollowUp_LinksOpenAppointmentSameDay(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	existing := Appointment{ID: primitive.NewObjectID(), PatientID: testPatientID, ScheduledAt: getNextMonday10AM().Add(2 * time.Hour), Status: AppointmentStatusConfirmed}
+	repo.FindByDateRangeFunc = func(ctx context.Context, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error) {
+		return []Appointment{existing}, nil
+	}
+	repo.CreateFunc = func(ctx context.Context, appointment *Appointment) error {
+		t.Fatal("a follow-up must not be booked when the patient already has an appointment that day")
+		return nil
+	}
+
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+
+	booking, err := svc.BookFollowUp(context.Background(), medical_records.FollowUpRequest{
+		TenantID:    testTenantID,
+		RecordID:    primitive.NewObjectID(),
+		PatientID:   testPatientID,
+		ScheduledAt: getNextMonday10AM(),
+		Type:        AppointmentTypeCheckup,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, medical_records.NextVisitLinked, booking.Outcome)
+	assert.Equal(t, existing.ID, booking.AppointmentID)
+}
+
 func TestCreateAppointment_PastTime(t *testing.T) {
 	repo := &mockAppointmentRepo{}
 	patientRepo := &mockPatientRepo{}
@@ -736,6 +1006,69 @@ func TestCreateAppointment_DefaultPriority(t *testing.T) {
 	assert.Equal(t, AppointmentPriorityNormal, createdAppointment.Priority)
 }
 
+func TestCreateAppointment_AutoConfirm(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	patientRepo := &mockPatientRepo{}
+	ownerRepo := &mockOwnerRepo{}
+	userRepo := &mockUserRepo{}
+	notifSvc := &mockNotificationSender{}
+
+	patientRepo.FindByIDFunc = func(ctx context.Context, tenantID primitive.ObjectID, id string) (*patients.Patient, error) {
+		return &patients.Patient{ID: testPatientID, TenantID: testTenantID, OwnerID: testOwnerID, Name: "Buddy"}, nil
+	}
+	userRepo.FindByIDFunc = func(ctx context.Context, id string) (*users.User, error) {
+		return &users.User{ID: testVetID}, nil
+	}
+	repo.CheckConflictsFunc = func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+		return false, nil
+	}
+	repo.CreateFunc = func(ctx context.Context, appointment *Appointment) error {
+		appointment.ID = testAppointmentID
+		return nil
+	}
+
+	var transition *AppointmentStatusTransition
+	repo.CreateStatusTransitionFunc = func(ctx context.Context, tr *AppointmentStatusTransition) error {
+		transition = tr
+		return nil
+	}
+
+	var ownerNotif *notifications.SendDTO
+	notifSvc.SendFunc = func(ctx context.Context, dto *notifications.SendDTO) error {
+		ownerNotif = dto
+		return nil
+	}
+
+	svc := newTestService(repo, patientRepo, ownerRepo, userRepo, notifSvc)
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{AutoConfirmAppointments: true}}, nil
+		},
+	}
+
+	dto := CreateAppointmentDTO{
+		PatientID:      testPatientID.Hex(),
+		VeterinarianID: testVetID.Hex(),
+		ScheduledAt:    getNextMonday10AM(),
+		Duration:       30,
+		Type:           AppointmentTypeConsultation,
+		Reason:         "Vaccination",
+	}
+
+	resp, err := svc.CreateAppointment(context.Background(), dto, testTenantID, testUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, AppointmentStatusConfirmed, resp.Status)
+	assert.NotNil(t, resp.ConfirmedAt)
+	if assert.NotNil(t, transition) {
+		assert.Equal(t, AppointmentStatusScheduled, transition.FromStatus)
+		assert.Equal(t, AppointmentStatusConfirmed, transition.ToStatus)
+	}
+	if assert.NotNil(t, ownerNotif) {
+		assert.Equal(t, notifications.TypeAppointmentConfirmed, ownerNotif.Type)
+	}
+}
+
 func TestUpdateStatus_ScheduledToConfirmed(t *testing.T) {
 	repo := &mockAppointmentRepo{}
 	patientRepo := &mockPatientRepo{}
@@ -911,6 +1244,77 @@ func TestRequestAppointment_OwnerMismatch(t *testing.T) {
 	assert.Equal(t, ErrOwnerMismatch, err)
 }
 
+func TestRequestAppointment_PriorityAboveOwnerCapGoesToTriage(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	patientRepo := &mockPatientRepo{}
+	ownerRepo := &mockOwnerRepo{}
+	notifSvc := &mockNotificationSender{}
+
+	patientRepo.FindByIDFunc = func(ctx context.Context, tenantID primitive.ObjectID, id string) (*patients.Patient, error) {
+		return &patients.Patient{ID: testPatientID, TenantID: testTenantID, OwnerID: testOwnerID, Name: "Buddy", Active: true}, nil
+	}
+	ownerRepo.FindByIDFunc = func(ctx context.Context, id string) (*owners.Owner, error) {
+		return &owners.Owner{ID: testOwnerID, Name: "John Doe"}, nil
+	}
+	repo.CreateFunc = func(ctx context.Context, appointment *Appointment) error {
+		appointment.ID = testAppointmentID
+		return nil
+	}
+	var staff *notifications.SendStaffDTO
+	notifSvc.SendToStaffFunc = func(ctx context.Context, dto *notifications.SendStaffDTO) error {
+		staff = dto
+		return nil
+	}
+
+	svc := newTestService(repo, patientRepo, ownerRepo, &mockUserRepo{}, notifSvc)
+
+	dto := MobileAppointmentRequestDTO{
+		PatientID:   testPatientID.Hex(),
+		ScheduledAt: getNextMonday10AM(),
+		Type:        AppointmentTypeConsultation,
+		Priority:    AppointmentPriorityEmergency,
+		Reason:      "Hit by a car",
+	}
+
+	resp, err := svc.RequestAppointment(context.Background(), dto, testTenantID, testOwnerID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, AppointmentPriorityNormal, resp.Priority)
+	assert.Equal(t, AppointmentPriorityEmergency, resp.RequestedPriority)
+	assert.Equal(t, defaultPriorityStyles[PriorityTriage].Color, resp.Display.Color)
+	assert.NotNil(t, staff)
+	assert.True(t, staff.Urgent)
+	assert.Equal(t, AppointmentPriorityEmergency, staff.Data["requested_priority"])
+}
+
+func TestValidateBookingWindow_PriorityBypassesMinNotice(t *testing.T) {
+	svc := newTestService(&mockAppointmentRepo{}, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			t := &tenant.Tenant{}
+			t.Settings.MinBookingNoticeHours = 24
+			return t, nil
+		},
+	}
+	soon := time.Now().Add(time.Hour)
+
+	assert.Error(t, svc.validateBookingWindow(context.Background(), testTenantID, soon, AppointmentPriorityNormal))
+	assert.NoError(t, svc.validateBookingWindow(context.Background(), testTenantID, soon, AppointmentPriorityHigh))
+	assert.NoError(t, svc.validateBookingWindow(context.Background(), testTenantID, soon, AppointmentPriorityEmergency))
+}
+
+func TestCheckTypeNotice_EnforcesLeadTimeOfType(t *testing.T) {
+	svc := newTestService(&mockAppointmentRepo{}, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	surgery := &AppointmentTypeConfig{Key: AppointmentTypeSurgery, MinNoticeHours: 48}
+	consultation := &AppointmentTypeConfig{Key: AppointmentTypeConsultation}
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	assert.ErrorIs(t, svc.checkTypeNotice(context.Background(), testTenantID, surgery, tomorrow, AppointmentPriorityNormal), ErrInsufficientTypeNotice)
+	assert.NoError(t, svc.checkTypeNotice(context.Background(), testTenantID, surgery, time.Now().Add(72*time.Hour), AppointmentPriorityNormal))
+	assert.NoError(t, svc.checkTypeNotice(context.Background(), testTenantID, surgery, tomorrow, AppointmentPriorityEmergency))
+	assert.NoError(t, svc.checkTypeNotice(context.Background(), testTenantID, consultation, time.Now().Add(time.Hour), AppointmentPriorityNormal))
+}
+
 func TestCancelAppointment_HappyPath(t *testing.T) {
 	repo := &mockAppointmentRepo{}
 	patientRepo := &mockPatientRepo{}
@@ -938,6 +1342,9 @@ func TestCancelAppointment_HappyPath(t *testing.T) {
 		if reason, ok := updates["cancel_reason"].(string); ok {
 			currentAppointment.CancelReason = reason
 		}
+		if byOwner, ok := updates["cancelled_by_owner"].(bool); ok {
+			currentAppointment.CancelledByOwner = byOwner
+		}
 		return nil
 	}
 	notifStaffSendCalls := 0
@@ -957,24 +1364,66 @@ func TestCancelAppointment_HappyPath(t *testing.T) {
 	assert.Equal(t, 1, notifStaffSendCalls)
 }
 
-func TestCancelAppointment_OwnerMismatch(t *testing.T) {
+func TestCancelAppointment_OwnerLockedType(t *testing.T) {
 	repo := &mockAppointmentRepo{}
-	patientRepo := &mockPatientRepo{}
-	ownerRepo := &mockOwnerRepo{}
-	userRepo := &mockUserRepo{}
 	notifSvc := &mockNotificationSender{}
 
-	otherOwnerID := primitive.NewObjectID()
+	overridden := true
+	appointment := &Appointment{
+		ID:          testAppointmentID,
+		TenantID:    testTenantID,
+		PatientID:   testPatientID,
+		OwnerID:     testOwnerID,
+		Type:        AppointmentTypeSurgery,
+		Status:      AppointmentStatusScheduled,
+		ScheduledAt: getNextMonday10AM(),
+	}
 	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
-		return &Appointment{
-			ID:        testAppointmentID,
-			TenantID:  testTenantID,
-			PatientID: testPatientID,
-			OwnerID:   otherOwnerID,
-			Status:    AppointmentStatusScheduled,
-		}, nil
+		return appointment, nil
 	}
-
+	updated := false
+	repo.UpdateFunc = func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+		updated = true
+		return nil
+	}
+
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, notifSvc)
+
+	resp, err := svc.CancelAppointment(context.Background(), testAppointmentID.Hex(), "Reason", testTenantID, testOwnerID)
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrOwnerChangeLocked, err)
+	assert.False(t, updated)
+
+	// Staff may unlock a single appointment
+	appointment.OwnerModifiable = &overridden
+	resp, err = svc.GetOwnerAppointment(context.Background(), testAppointmentID.Hex(), testTenantID, testOwnerID, false)
+	assert.NoError(t, err)
+	assert.True(t, *resp.OwnerModifiable)
+
+	appointment.OwnerModifiable = nil
+	resp, err = svc.GetOwnerAppointment(context.Background(), testAppointmentID.Hex(), testTenantID, testOwnerID, false)
+	assert.NoError(t, err)
+	assert.False(t, *resp.OwnerModifiable)
+}
+
+func TestCancelAppointment_OwnerMismatch(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	patientRepo := &mockPatientRepo{}
+	ownerRepo := &mockOwnerRepo{}
+	userRepo := &mockUserRepo{}
+	notifSvc := &mockNotificationSender{}
+
+	otherOwnerID := primitive.NewObjectID()
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:        testAppointmentID,
+			TenantID:  testTenantID,
+			PatientID: testPatientID,
+			OwnerID:   otherOwnerID,
+			Status:    AppointmentStatusScheduled,
+		}, nil
+	}
+
 	svc := newTestService(repo, patientRepo, ownerRepo, userRepo, notifSvc)
 
 	resp, err := svc.CancelAppointment(context.Background(), testAppointmentID.Hex(), "Reason", testTenantID, testOwnerID)
@@ -984,6 +1433,45 @@ func TestCancelAppointment_OwnerMismatch(t *testing.T) {
 	assert.Equal(t, ErrOwnerMismatch, err)
 }
 
+func TestDeleteAppointment_LinkedMedicalRecords(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{ID: testAppointmentID, TenantID: testTenantID, Status: AppointmentStatusCompleted}, nil
+	}
+	deleted := false
+	repo.DeleteFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+		deleted = true
+		return nil
+	}
+
+	var audited audit.EventType
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.recordCounter = &mockRecordCounter{
+		CountByAppointmentFunc: func(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (int64, error) {
+			return 1, nil
+		},
+	}
+	svc.auditLog = &mockAuditLogger{
+		LogAppointmentActionFunc: func(ctx context.Context, tenantID, userID, appointmentID primitive.ObjectID, eventType audit.EventType, action, description string) error {
+			audited = eventType
+			return nil
+		},
+	}
+
+	err := svc.DeleteAppointment(context.Background(), testAppointmentID.Hex(), testTenantID, testUserID, DeleteAppointmentDTO{})
+	assert.ErrorIs(t, err, ErrAppointmentHasMedicalRecords)
+	assert.False(t, deleted)
+
+	err = svc.DeleteAppointment(context.Background(), testAppointmentID.Hex(), testTenantID, testUserID, DeleteAppointmentDTO{Force: true})
+	assert.ErrorIs(t, err, ErrForceDeleteReasonRequired)
+	assert.False(t, deleted)
+
+	err = svc.DeleteAppointment(context.Background(), testAppointmentID.Hex(), testTenantID, testUserID, DeleteAppointmentDTO{Force: true, Reason: "Registro duplicado"})
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, audit.EventAppointmentDeleted, audited)
+}
+
 func TestValidateAppointmentTime_Before8AM(t *testing.T) {
 	repo := &mockAppointmentRepo{}
 	patientRepo := &mockPatientRepo{}
@@ -995,7 +1483,7 @@ func TestValidateAppointmentTime_Before8AM(t *testing.T) {
 
 	monday10am := getNextMonday10AM()
 	earlyMorning := time.Date(monday10am.Year(), monday10am.Month(), monday10am.Day(), 7, 0, 0, 0, time.Local)
-	err := svc.validateAppointmentTime(earlyMorning)
+	err := svc.validateAppointmentTime(context.Background(), primitive.NewObjectID(), earlyMorning)
 
 	assert.Error(t, err)
 	assert.Equal(t, ErrInvalidAppointmentTime, err)
@@ -1012,8 +1500,803 @@ func TestValidateAppointmentTime_After6PM(t *testing.T) {
 
 	monday10am := getNextMonday10AM()
 	evening := time.Date(monday10am.Year(), monday10am.Month(), monday10am.Day(), 19, 0, 0, 0, time.Local)
-	err := svc.validateAppointmentTime(evening)
+	err := svc.validateAppointmentTime(context.Background(), primitive.NewObjectID(), evening)
 
 	assert.Error(t, err)
 	assert.Equal(t, ErrInvalidAppointmentTime, err)
 }
+
+func quarterHourGridService() *Service {
+	svc := newTestService(&mockAppointmentRepo{}, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{SlotGranularityMinutes: 15}}, nil
+		},
+	}
+	return svc
+}
+
+func TestValidateAppointmentTime_AlignedToSlotGrid(t *testing.T) {
+	svc := quarterHourGridService()
+	monday10am := getNextMonday10AM()
+
+	for _, minutes := range []int{0, 15, 30, 45} {
+		err := svc.validateAppointmentTime(context.Background(), testTenantID, monday10am.Add(time.Duration(minutes)*time.Minute))
+		assert.NoError(t, err, "minute %d", minutes)
+	}
+}
+
+func TestValidateAppointmentTime_OffSlotGrid(t *testing.T) {
+	svc := quarterHourGridService()
+	monday10am := getNextMonday10AM()
+
+	err := svc.validateAppointmentTime(context.Background(), testTenantID, monday10am.Add(7*time.Minute))
+
+	assert.ErrorIs(t, err, ErrOffSlotGrid)
+	var appErr *AppointmentError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, 15, appErr.Details["granularity_minutes"])
+		assert.Equal(t, monday10am, appErr.Details["previous_slot"])
+		assert.Equal(t, monday10am.Add(15*time.Minute), appErr.Details["next_slot"])
+	}
+
+	// Seconds off the boundary are off the grid too
+	err = svc.validateAppointmentTime(context.Background(), testTenantID, monday10am.Add(30*time.Second))
+	assert.ErrorIs(t, err, ErrOffSlotGrid)
+}
+
+func TestValidateAppointmentTime_NoGranularityAcceptsAnyMinute(t *testing.T) {
+	svc := newTestService(&mockAppointmentRepo{}, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+
+	err := svc.validateAppointmentTime(context.Background(), testTenantID, getNextMonday10AM().Add(7*time.Minute))
+
+	assert.NoError(t, err)
+}
+
+func TestOffboardVeterinarian_ConflictsReturnToQueue(t *testing.T) {
+	replacementID := primitive.NewObjectID()
+	free := Appointment{ID: primitive.NewObjectID(), TenantID: testTenantID, VeterinarianID: testVetID, Status: AppointmentStatusConfirmed, ScheduledAt: time.Now().Add(24 * time.Hour), Duration: 30}
+	clash := Appointment{ID: primitive.NewObjectID(), TenantID: testTenantID, VeterinarianID: testVetID, Status: AppointmentStatusScheduled, ScheduledAt: time.Now().Add(48 * time.Hour), Duration: 30}
+
+	assigned := map[primitive.ObjectID]primitive.ObjectID{}
+	repo := &mockAppointmentRepo{
+		FindByVeterinarianFunc: func(ctx context.Context, vetID primitive.ObjectID, from, to time.Time, tenantID primitive.ObjectID) ([]Appointment, error) {
+			return []Appointment{free, clash}, nil
+		},
+		CheckConflictsFunc: func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+			return *excludeID == clash.ID, nil
+		},
+		UpdateFunc: func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+			assigned[id] = updates["veterinarian_id"].(primitive.ObjectID)
+			return nil
+		},
+	}
+	userRepo := &mockUserRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*users.User, error) {
+			oid, _ := primitive.ObjectIDFromHex(id)
+			return &users.User{ID: oid, TenantIds: []primitive.ObjectID{testTenantID}}, nil
+		},
+	}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, userRepo, &mockNotificationSender{})
+
+	resp, err := svc.OffboardVeterinarian(context.Background(), testVetID.Hex(), OffboardVeterinarianDTO{ReplacementVeterinarianID: replacementID.Hex(), Reason: "Renuncia"}, testTenantID, testUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Reassigned)
+	assert.Equal(t, 1, resp.Unassigned)
+	assert.Len(t, resp.Conflicts, 1)
+	assert.Equal(t, clash.ID.Hex(), resp.Conflicts[0].AppointmentID)
+	assert.Equal(t, replacementID, assigned[free.ID])
+	assert.True(t, assigned[clash.ID].IsZero())
+}
+
+func TestCreateAppointment_PatientOverlapRejected(t *testing.T) {
+	monday10am := getNextMonday10AM()
+	var window [2]time.Time
+	repo := &mockAppointmentRepo{
+		CheckPatientConflictsFunc: func(ctx context.Context, patientID primitive.ObjectID, from, to time.Time, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+			window = [2]time.Time{from, to}
+			return true, nil
+		},
+	}
+	patientRepo := &mockPatientRepo{
+		FindByIDFunc: func(ctx context.Context, tenantID primitive.ObjectID, id string) (*patients.Patient, error) {
+			return &patients.Patient{ID: testPatientID, TenantID: testTenantID, OwnerID: testOwnerID}, nil
+		},
+	}
+	svc := newTestService(repo, patientRepo, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{PreventPatientOverlap: true, PatientAppointmentGapMinutes: 15}}, nil
+		},
+	}
+
+	_, err := svc.CreateAppointment(context.Background(), CreateAppointmentDTO{
+		PatientID:      testPatientID.Hex(),
+		VeterinarianID: testVetID.Hex(),
+		ScheduledAt:    monday10am,
+		Duration:       30,
+		Type:           AppointmentTypeConsultation,
+		Reason:         "Control",
+	}, testTenantID, testUserID)
+
+	assert.Equal(t, ErrPatientNotAvailable, err)
+	assert.Equal(t, monday10am.Add(-15*time.Minute), window[0])
+	assert.Equal(t, monday10am.Add(45*time.Minute), window[1])
+}
+
+func TestUpdateStatus_LateStartShiftedWithOverrunWarning(t *testing.T) {
+	scheduledAt := time.Now().Add(-10 * time.Minute)
+	appointmentID := primitive.NewObjectID()
+	nextID := primitive.NewObjectID()
+	var updates bson.M
+	repo := &mockAppointmentRepo{
+		FindByIDFunc: func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+			return &Appointment{ID: appointmentID, TenantID: testTenantID, VeterinarianID: testVetID, ScheduledAt: scheduledAt, Duration: 30, Status: AppointmentStatusConfirmed}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id primitive.ObjectID, u bson.M, tenantID primitive.ObjectID) error {
+			updates = u
+			return nil
+		},
+		FindNextForVeterinarianFunc: func(ctx context.Context, vetID primitive.ObjectID, after time.Time, excludeID primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+			return &Appointment{ID: nextID, ScheduledAt: scheduledAt.Add(30 * time.Minute)}, nil
+		},
+	}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{LateArrivalToleranceMinutes: 15}}, nil
+		},
+	}
+
+	resp, err := svc.UpdateStatus(context.Background(), appointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusInProgress, ShiftStart: true}, testTenantID, testUserID)
+
+	assert.NoError(t, err)
+	assert.Contains(t, updates, "effective_start_at")
+	assert.NotContains(t, updates, "scheduled_at")
+	if !assert.Len(t, resp.Warnings, 1) {
+		return
+	}
+	assert.Equal(t, WarningOverrunsNext, resp.Warnings[0].Code)
+	assert.Equal(t, nextID.Hex(), resp.Warnings[0].NextAppointmentID)
+	assert.Equal(t, 10, resp.Warnings[0].OverlapMinutes)
+
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{LateArrivalToleranceMinutes: 5}}, nil
+		},
+	}
+	_, err = svc.UpdateStatus(context.Background(), appointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusInProgress, ShiftStart: true}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrLateArrival)
+}
+
+func TestCreateAppointment_TypeDefaultsWithoutVet(t *testing.T) {
+	var created *Appointment
+	repo := &mockAppointmentRepo{
+		CheckConflictsFunc: func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+			t.Fatal("conflict check must not run without a veterinarian")
+			return false, nil
+		},
+		CreateFunc: func(ctx context.Context, appointment *Appointment) error {
+			created = appointment
+			return nil
+		},
+	}
+	patientRepo := &mockPatientRepo{
+		FindByIDFunc: func(ctx context.Context, tenantID primitive.ObjectID, id string) (*patients.Patient, error) {
+			return &patients.Patient{ID: testPatientID, TenantID: testTenantID, OwnerID: testOwnerID}, nil
+		},
+	}
+	svc := newTestService(repo, patientRepo, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+
+	dto := CreateAppointmentDTO{
+		PatientID:   testPatientID.Hex(),
+		ScheduledAt: getNextMonday10AM(),
+		Type:        AppointmentTypeGrooming,
+		Reason:      "Baño",
+	}
+	_, err := svc.CreateAppointment(context.Background(), dto, testTenantID, testUserID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, created) {
+		assert.Equal(t, 60, created.Duration)
+		assert.True(t, created.VeterinarianID.IsZero())
+	}
+
+	dto.Type = AppointmentTypeConsultation
+	_, err = svc.CreateAppointment(context.Background(), dto, testTenantID, testUserID)
+	assert.Equal(t, ErrVeterinarianRequired, err)
+
+	dto.Type = "hydrotherapy"
+	_, err = svc.CreateAppointment(context.Background(), dto, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrInvalidAppointmentType)
+}
+
+func TestUpdateAppointment_TypeChangeReappliesDuration(t *testing.T) {
+	stored := &Appointment{
+		ID:             testAppointmentID,
+		TenantID:       testTenantID,
+		PatientID:      testPatientID,
+		OwnerID:        testOwnerID,
+		VeterinarianID: testVetID,
+		ScheduledAt:    getNextMonday10AM(),
+		Duration:       30,
+		Type:           AppointmentTypeConsultation,
+		Status:         AppointmentStatusScheduled,
+	}
+	var checkedDuration int
+	var updates bson.M
+	repo := &mockAppointmentRepo{
+		FindByIDFunc: func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+			return stored, nil
+		},
+		CheckConflictsFunc: func(ctx context.Context, vetID primitive.ObjectID, scheduledAt time.Time, duration int, excludeID *primitive.ObjectID, tenantID primitive.ObjectID) (bool, error) {
+			checkedDuration = duration
+			return false, nil
+		},
+		UpdateFunc: func(ctx context.Context, id primitive.ObjectID, u bson.M, tenantID primitive.ObjectID) error {
+			updates = u
+			return nil
+		},
+	}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Currency: "COP", Settings: tenant.TenantSettings{
+				AppointmentDeposits: map[string]tenant.AppointmentDeposit{AppointmentTypeSurgery: {Amount: 50000}},
+			}}, nil
+		},
+	}
+
+	surgery := AppointmentTypeSurgery
+	resp, err := svc.UpdateAppointment(context.Background(), testAppointmentID.Hex(), UpdateAppointmentDTO{Type: &surgery}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, checkedDuration, "conflicts must be checked with the new type's duration")
+	assert.Equal(t, 120, updates["duration"])
+	if assert.NotNil(t, resp) && assert.Len(t, resp.Warnings, 2) {
+		assert.Equal(t, WarningDurationAdjusted, resp.Warnings[0].Code)
+		assert.Equal(t, WarningDepositRequired, resp.Warnings[1].Code)
+	}
+
+	// An explicit duration wins over the type's default
+	duration := 45
+	checkedDuration = 0
+	resp, err = svc.UpdateAppointment(context.Background(), testAppointmentID.Hex(), UpdateAppointmentDTO{Type: &surgery, Duration: &duration}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 45, checkedDuration)
+	assert.Equal(t, 45, updates["duration"])
+	if assert.NotNil(t, resp) && assert.Len(t, resp.Warnings, 1) {
+		assert.Equal(t, WarningDepositRequired, resp.Warnings[0].Code)
+	}
+}
+
+func TestRescheduleOwnerAppointment_StoredAsRequest(t *testing.T) {
+	current := &Appointment{
+		ID:          testAppointmentID,
+		TenantID:    testTenantID,
+		PatientID:   testPatientID,
+		OwnerID:     testOwnerID,
+		Status:      AppointmentStatusConfirmed,
+		ScheduledAt: getNextMonday10AM(),
+		Duration:    30,
+	}
+	var applied bson.M
+	repo := &mockAppointmentRepo{
+		FindByIDFunc: func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+			return current, nil
+		},
+		UpdateFunc: func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+			applied = updates
+			return nil
+		},
+	}
+	staffAlerts := 0
+	notifSvc := &mockNotificationSender{
+		SendToStaffFunc: func(ctx context.Context, dto *notifications.SendStaffDTO) error {
+			staffAlerts++
+			return nil
+		},
+	}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, notifSvc)
+
+	newTime := current.ScheduledAt.AddDate(0, 0, 1)
+	_, err := svc.RescheduleOwnerAppointment(context.Background(), testAppointmentID.Hex(), OwnerRescheduleDTO{ScheduledAt: newTime}, testTenantID, testOwnerID)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, applied, "scheduled_at")
+	request, ok := applied["reschedule_request"].(*RescheduleRequest)
+	assert.True(t, ok)
+	assert.Equal(t, newTime, request.ScheduledAt)
+	assert.Equal(t, 1, staffAlerts)
+}
+
+func TestRescheduleOwnerAppointment_WithinCutoff(t *testing.T) {
+	repo := &mockAppointmentRepo{
+		FindByIDFunc: func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+			return &Appointment{
+				ID:          testAppointmentID,
+				TenantID:    testTenantID,
+				OwnerID:     testOwnerID,
+				Status:      AppointmentStatusScheduled,
+				ScheduledAt: time.Now().Add(2 * time.Hour),
+			}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+			t.Fatal("appointment must not be updated within the cutoff")
+			return nil
+		},
+	}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{AllowOwnerReschedule: true, OwnerRescheduleCutoffHours: 24}}, nil
+		},
+	}
+
+	_, err := svc.RescheduleOwnerAppointment(context.Background(), testAppointmentID.Hex(), OwnerRescheduleDTO{ScheduledAt: getNextMonday10AM().AddDate(0, 0, 7)}, testTenantID, testOwnerID)
+
+	assert.ErrorIs(t, err, ErrRescheduleCutoff)
+}
+
+func intakeRequiredService(repo *mockAppointmentRepo, ownerRepo *mockOwnerRepo) *Service {
+	svc := newTestService(repo, &mockPatientRepo{}, ownerRepo, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+			return &tenant.Tenant{Settings: tenant.TenantSettings{RequireIntakeForFirstAppointment: true}}, nil
+		},
+	}
+	return svc
+}
+
+func TestUpdateStatus_ConfirmBlockedWithoutIntake(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:          testAppointmentID,
+			TenantID:    testTenantID,
+			PatientID:   testPatientID,
+			OwnerID:     testOwnerID,
+			Status:      AppointmentStatusScheduled,
+			ScheduledAt: getNextMonday10AM(),
+		}, nil
+	}
+	repo.ListFunc = func(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error) {
+		return nil, 0, nil
+	}
+	repo.UpdateFunc = func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+		t.Fatal("appointment must not be updated")
+		return nil
+	}
+	ownerRepo := &mockOwnerRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*owners.Owner, error) {
+			return &owners.Owner{ID: testOwnerID}, nil
+		},
+	}
+
+	svc := intakeRequiredService(repo, ownerRepo)
+
+	_, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusConfirmed}, testTenantID, testUserID)
+
+	assert.ErrorIs(t, err, ErrIntakeRequired)
+}
+
+func TestCheckIntake_CompletedIntakeOrPriorVisit(t *testing.T) {
+	appointment := &Appointment{TenantID: testTenantID, OwnerID: testOwnerID}
+
+	withIntake := &mockOwnerRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*owners.Owner, error) {
+			return &owners.Owner{ID: testOwnerID, IntakeCompletedAt: map[string]time.Time{testTenantID.Hex(): time.Now()}}, nil
+		},
+	}
+	assert.NoError(t, intakeRequiredService(&mockAppointmentRepo{}, withIntake).checkIntake(context.Background(), appointment))
+
+	returning := &mockAppointmentRepo{
+		ListFunc: func(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error) {
+			assert.Equal(t, []string{AppointmentStatusCompleted}, filters.Status)
+			return []Appointment{{}}, 3, nil
+		},
+	}
+	withoutIntake := &mockOwnerRepo{
+		FindByIDFunc: func(ctx context.Context, id string) (*owners.Owner, error) {
+			return &owners.Owner{ID: testOwnerID}, nil
+		},
+	}
+	assert.NoError(t, intakeRequiredService(returning, withoutIntake).checkIntake(context.Background(), appointment))
+}
+
+type mockWorkflowRepo struct {
+	workflow *AppointmentWorkflow
+}
+
+func (m *mockWorkflowRepo) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) (*AppointmentWorkflow, error) {
+	return m.workflow, nil
+}
+
+func (m *mockWorkflowRepo) Upsert(ctx context.Context, w *AppointmentWorkflow) error {
+	m.workflow = w
+	return nil
+}
+
+// labsWorkflow adds "awaiting_labs" between in_progress and completed
+func labsWorkflow() *AppointmentWorkflow {
+	w := defaultWorkflow(testTenantID)
+	w.Statuses = append(w.Statuses, StatusConfig{Key: "awaiting_labs", Label: "Esperando laboratorio", ActsAs: AppointmentStatusInProgress})
+	w.Transitions[AppointmentStatusInProgress] = append(w.Transitions[AppointmentStatusInProgress], "awaiting_labs")
+	w.Transitions["awaiting_labs"] = []string{AppointmentStatusCompleted, AppointmentStatusCancelled}
+	w.UpdatedAt = time.Now()
+	return w
+}
+
+func TestStatusWorkflow_DefaultMatchesBuiltinMachine(t *testing.T) {
+	w := defaultWorkflow(testTenantID)
+
+	assert.NoError(t, w.validate())
+	for from, targets := range ValidStatusTransitions {
+		for _, to := range targets {
+			assert.True(t, w.canTransition(from, to), "%s -> %s", from, to)
+		}
+	}
+	assert.False(t, w.canTransition(AppointmentStatusCompleted, AppointmentStatusScheduled))
+}
+
+func TestStatusWorkflow_RejectsUnreachableAndDeadEndStatuses(t *testing.T) {
+	unreachable := defaultWorkflow(testTenantID)
+	unreachable.Statuses = append(unreachable.Statuses, StatusConfig{Key: "awaiting_labs", Label: "Esperando laboratorio"})
+	unreachable.Transitions["awaiting_labs"] = []string{AppointmentStatusCompleted}
+	assert.ErrorIs(t, unreachable.validate(), ErrInvalidStatusWorkflow)
+
+	deadEnd := defaultWorkflow(testTenantID)
+	deadEnd.Statuses = append(deadEnd.Statuses, StatusConfig{Key: "awaiting_labs", Label: "Esperando laboratorio"})
+	deadEnd.Transitions[AppointmentStatusInProgress] = append(deadEnd.Transitions[AppointmentStatusInProgress], "awaiting_labs")
+	assert.ErrorIs(t, deadEnd.validate(), ErrInvalidStatusWorkflow)
+
+	missingBuiltin := defaultWorkflow(testTenantID)
+	missingBuiltin.Statuses = missingBuiltin.Statuses[1:]
+	delete(missingBuiltin.Transitions, AppointmentStatusAwaitingDeposit)
+	assert.ErrorIs(t, missingBuiltin.validate(), ErrInvalidStatusWorkflow)
+
+	reopened := defaultWorkflow(testTenantID)
+	reopened.Transitions[AppointmentStatusCompleted] = []string{AppointmentStatusScheduled}
+	assert.ErrorIs(t, reopened.validate(), ErrInvalidStatusWorkflow)
+
+	assert.NoError(t, labsWorkflow().validate())
+}
+
+func TestUpdateStatus_CustomStatusFromClinicWorkflow(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	currentAppointment := &Appointment{
+		ID:          testAppointmentID,
+		TenantID:    testTenantID,
+		PatientID:   testPatientID,
+		OwnerID:     testOwnerID,
+		Status:      AppointmentStatusInProgress,
+		ScheduledAt: getNextMonday10AM(),
+	}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return currentAppointment, nil
+	}
+	var updates bson.M
+	repo.UpdateFunc = func(ctx context.Context, id primitive.ObjectID, u bson.M, tenantID primitive.ObjectID) error {
+		updates = u
+		currentAppointment.Status = u["status"].(string)
+		return nil
+	}
+
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.workflows = &mockWorkflowRepo{workflow: labsWorkflow()}
+
+	resp, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: "awaiting_labs"}, testTenantID, testUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "awaiting_labs", resp.Status)
+	// acts_as in_progress runs the started_at side effect
+	assert.Contains(t, updates, "started_at")
+
+	_, err = svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusNoShow}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+}
+
+func TestUpdateStatusWorkflow_RemovingStatusInUse(t *testing.T) {
+	repo := &mockAppointmentRepo{
+		ListFunc: func(ctx context.Context, filters appointmentFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Appointment, int64, error) {
+			assert.Equal(t, []string{"awaiting_labs"}, filters.Status)
+			return []Appointment{{}}, 2, nil
+		},
+	}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	workflows := &mockWorkflowRepo{workflow: labsWorkflow()}
+	svc.workflows = workflows
+
+	builtin := defaultWorkflow(testTenantID)
+	dto := UpdateStatusWorkflowDTO{Transitions: builtin.Transitions}
+	for _, st := range builtin.Statuses {
+		dto.Statuses = append(dto.Statuses, StatusConfigDTO{Key: st.Key, Label: st.Label})
+	}
+
+	_, err := svc.UpdateStatusWorkflow(context.Background(), dto, testTenantID)
+
+	assert.ErrorIs(t, err, ErrInvalidStatusWorkflow)
+	assert.Len(t, workflows.workflow.Statuses, len(builtin.Statuses)+1)
+}
+
+func TestUpdateStatus_CancelNotifiesThroughOutbox(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:          testAppointmentID,
+			TenantID:    testTenantID,
+			PatientID:   testPatientID,
+			OwnerID:     testOwnerID,
+			Status:      AppointmentStatusConfirmed,
+			ScheduledAt: getNextMonday10AM(),
+		}, nil
+	}
+	var stored []notifications.PendingNotification
+	repo.UpdateFunc = func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+		stored, _ = updates[notifications.PendingNotificationsField].([]notifications.PendingNotification)
+		return nil
+	}
+	var enqueued []notifications.PendingNotification
+	notifSvc := &mockNotificationSender{
+		EnqueueFunc: func(ctx context.Context, collection string, docID primitive.ObjectID, pending notifications.PendingNotification) error {
+			assert.Equal(t, Collection, collection)
+			assert.Equal(t, testAppointmentID, docID)
+			enqueued = append(enqueued, pending)
+			return nil
+		},
+	}
+
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, notifSvc)
+
+	_, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusCancelled, Reason: "Dueño enfermo"}, testTenantID, testUserID)
+
+	assert.NoError(t, err)
+	// The notification is written with the status change, then enqueued
+	if assert.Len(t, stored, 1) && assert.Len(t, enqueued, 1) {
+		assert.Equal(t, stored[0].DedupeKey, enqueued[0].DedupeKey)
+		assert.Equal(t, "appointment_cancelled", enqueued[0].Source)
+		assert.Equal(t, notifications.TypeAppointmentCancelled, enqueued[0].Message.Type)
+		assert.True(t, strings.HasPrefix(enqueued[0].DedupeKey, "appointment_cancelled:"+testAppointmentID.Hex()+":"))
+	}
+}
+
+func TestStatusNotification_RepeatedStatusGetsItsOwnKey(t *testing.T) {
+	svc := newTestService(&mockAppointmentRepo{}, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	appointment := &Appointment{ID: testAppointmentID, TenantID: testTenantID, OwnerID: testOwnerID, ScheduledAt: getNextMonday10AM()}
+	dto := UpdateStatusDTO{Status: AppointmentStatusConfirmed}
+
+	first := svc.statusNotification(appointment, AppointmentStatusConfirmed, dto, time.Now())
+	second := svc.statusNotification(appointment, AppointmentStatusConfirmed, dto, time.Now().Add(time.Hour))
+
+	if assert.NotNil(t, first) && assert.NotNil(t, second) {
+		assert.NotEqual(t, first.DedupeKey, second.DedupeKey)
+	}
+	assert.Nil(t, svc.statusNotification(appointment, AppointmentStatusInProgress, UpdateStatusDTO{Status: AppointmentStatusInProgress}, time.Now()))
+}
+
+func TestToOwnerResponse_OmitsStaffOnlyFields(t *testing.T) {
+	vetID := primitive.NewObjectID()
+	roomID := primitive.NewObjectID()
+	cancelledAt := time.Now()
+	appointment := &Appointment{
+		ID:                     testAppointmentID,
+		TenantID:               testTenantID,
+		PatientID:              testPatientID,
+		OwnerID:                testOwnerID,
+		VeterinarianID:         vetID,
+		OriginalVeterinarianID: &testUserID,
+		RoomID:                 &roomID,
+		Status:                 AppointmentStatusCancelled,
+		ScheduledAt:            getNextMonday10AM(),
+		Notes:                  "Owner was aggressive last visit",
+		OwnerNotes:             "Patient anxious",
+		CancelledAt:            &cancelledAt,
+		CancelReason:           "Vet double-booked",
+		DisableReminders:       true,
+	}
+
+	full := appointment.ToResponse()
+	full.Owner = &OwnerSummary{ID: testOwnerID.Hex(), Name: "Ana", Email: "ana@example.com", Phone: "3001234567"}
+	full.Veterinarian = &VeterinarianSummary{ID: vetID.Hex(), Name: "Dr. Ruiz", Email: "ruiz@clinic.com", Phone: "3007654321"}
+	full.Warnings = []AppointmentWarning{{Code: "OVERRUNS_NEXT_APPOINTMENT", Message: "overrun"}}
+
+	body, err := json.Marshal(ownerProjection(appointment, full))
+	assert.NoError(t, err)
+
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(body, &fields))
+	for _, staffOnly := range []string{"notes", "cancel_reason", "owner_id", "owner", "original_veterinarian_id", "room_id", "warnings", "disable_reminders"} {
+		assert.NotContains(t, fields, staffOnly)
+	}
+	assert.Equal(t, "Patient anxious", fields["owner_notes"])
+	assert.Equal(t, map[string]any{"id": vetID.Hex(), "name": "Dr. Ruiz"}, fields["veterinarian"])
+
+	// A reason the owner wrote is shown back to them
+	appointment.CancelReason = "Ya no necesito la cita"
+	appointment.CancelledByOwner = true
+	assert.Equal(t, "Ya no necesito la cita", appointment.ToOwnerResponse().CancelReason)
+}
+
+type mockWeightRecorder struct {
+	weights []float64
+}
+
+func (m *mockWeightRecorder) RecordFromAppointment(ctx context.Context, tenantID, patientID, appointmentID, userID primitive.ObjectID, weight float64) error {
+	m.weights = append(m.weights, weight)
+	return nil
+}
+
+func TestUpdateStatus_RecordsWeightAtCompletionOnly(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:          testAppointmentID,
+			TenantID:    testTenantID,
+			PatientID:   testPatientID,
+			OwnerID:     testOwnerID,
+			Status:      AppointmentStatusInProgress,
+			ScheduledAt: getNextMonday10AM(),
+		}, nil
+	}
+	weights := &mockWeightRecorder{}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.weights = weights
+
+	_, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusCompleted, Weight: 8.2}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{8.2}, weights.weights)
+
+	_, err = svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusCancelled, Weight: 8.2}, testTenantID, testUserID)
+	var appErr *AppointmentError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, "weight", appErr.Field)
+	}
+	assert.Len(t, weights.weights, 1)
+}
+
+type mockInvoiceDrafter struct {
+	drafts []*invoices.AppointmentDraft
+}
+
+func (m *mockInvoiceDrafter) DraftForAppointment(ctx context.Context, tenantID, createdBy primitive.ObjectID, draft *invoices.AppointmentDraft) (*invoices.Invoice, error) {
+	m.drafts = append(m.drafts, draft)
+	return &invoices.Invoice{}, nil
+}
+
+func TestUpdateStatus_DraftsInvoiceOnCompletionWhenEnabled(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:          testAppointmentID,
+			TenantID:    testTenantID,
+			PatientID:   testPatientID,
+			OwnerID:     testOwnerID,
+			Type:        AppointmentTypeConsultation,
+			Status:      AppointmentStatusInProgress,
+			ScheduledAt: getNextMonday10AM(),
+		}, nil
+	}
+	settings := tenant.TenantSettings{}
+	drafter := &mockInvoiceDrafter{}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{})
+	svc.tenantRepo = &mockTenantRepo{FindByIDFunc: func(ctx context.Context, id string) (*tenant.Tenant, error) {
+		return &tenant.Tenant{Settings: settings}, nil
+	}}
+	svc.invoices = drafter
+
+	_, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusCompleted}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Empty(t, drafter.drafts)
+
+	settings.AutoDraftInvoiceOnCompletion = true
+	_, err = svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusCompleted}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	if assert.Len(t, drafter.drafts, 1) {
+		assert.Equal(t, testAppointmentID, drafter.drafts[0].AppointmentID)
+		assert.Equal(t, testOwnerID, drafter.drafts[0].OwnerID)
+		assert.Equal(t, "Consulta", drafter.drafts[0].TypeName)
+	}
+}
+
+type mockTypeRepo struct {
+	types []AppointmentTypeConfig
+	err   error
+}
+
+func (m *mockTypeRepo) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]AppointmentTypeConfig, error) {
+	return m.types, m.err
+}
+
+func (m *mockTypeRepo) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*AppointmentTypeConfig, error) {
+	return nil, ErrAppointmentTypeNotFound
+}
+
+func (m *mockTypeRepo) Create(ctx context.Context, t *AppointmentTypeConfig) error { return nil }
+
+func (m *mockTypeRepo) CreateMany(ctx context.Context, types []AppointmentTypeConfig) error {
+	return nil
+}
+
+func (m *mockTypeRepo) Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error {
+	return nil
+}
+
+func (m *mockTypeRepo) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	return nil
+}
+
+type mockConsentRegistry struct {
+	signed *consents.Consent
+}
+
+func (m *mockConsentRegistry) CurrentTemplate(ctx context.Context, tenantID primitive.ObjectID, procedure string) (*consents.ConsentTemplate, error) {
+	return &consents.ConsentTemplate{TenantID: tenantID, Procedure: procedure, Version: 1}, nil
+}
+
+func (m *mockConsentRegistry) ForAppointment(ctx context.Context, tenantID, appointmentID primitive.ObjectID, procedure string) (*consents.Consent, error) {
+	if m.signed == nil {
+		return nil, consents.ErrConsentNotFound
+	}
+	return m.signed, nil
+}
+
+func (m *mockConsentRegistry) Sign(ctx context.Context, req consents.SignRequest) (*consents.Consent, error) {
+	m.signed = &consents.Consent{TenantID: req.TenantID, AppointmentID: req.AppointmentID, Procedure: req.Procedure, Channel: req.Channel}
+	return m.signed, nil
+}
+
+func TestUpdateStatus_StartRequiresSignedConsent(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:          testAppointmentID,
+			TenantID:    testTenantID,
+			PatientID:   testPatientID,
+			OwnerID:     testOwnerID,
+			Type:        AppointmentTypeSurgery,
+			Status:      AppointmentStatusConfirmed,
+			ScheduledAt: time.Now(),
+			Duration:    60,
+		}, nil
+	}
+	registry := &mockConsentRegistry{}
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{}).WithConsents(registry)
+	svc.types = &mockTypeRepo{types: []AppointmentTypeConfig{
+		{Key: AppointmentTypeSurgery, Name: "Cirugía", DefaultDuration: 120, Active: true, RequiresConsent: true},
+	}}
+
+	_, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusInProgress}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrConsentRequired)
+
+	_, err = svc.SignConsent(context.Background(), testAppointmentID.Hex(), consents.SignConsentDTO{TemplateVersion: 1, SignedByName: "María Pérez"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, consents.ChannelClinic, registry.signed.Channel)
+
+	_, err = svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusInProgress}, testTenantID, testUserID)
+	assert.NoError(t, err)
+}
+
+func TestUpdateStatus_StartBlockedWhenConsentLookupFails(t *testing.T) {
+	repo := &mockAppointmentRepo{}
+	repo.FindByIDFunc = func(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Appointment, error) {
+		return &Appointment{
+			ID:          testAppointmentID,
+			TenantID:    testTenantID,
+			PatientID:   testPatientID,
+			OwnerID:     testOwnerID,
+			Type:        AppointmentTypeSurgery,
+			Status:      AppointmentStatusConfirmed,
+			ScheduledAt: time.Now(),
+			Duration:    60,
+		}, nil
+	}
+	repo.UpdateFunc = func(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+		t.Fatal("the appointment must not start when the consent check fails")
+		return nil
+	}
+	lookupErr := errors.New("connection reset")
+	svc := newTestService(repo, &mockPatientRepo{}, &mockOwnerRepo{}, &mockUserRepo{}, &mockNotificationSender{}).WithConsents(&mockConsentRegistry{})
+	svc.types = &mockTypeRepo{err: lookupErr}
+
+	_, err := svc.UpdateStatus(context.Background(), testAppointmentID.Hex(), UpdateStatusDTO{Status: AppointmentStatusInProgress}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, lookupErr)
+}
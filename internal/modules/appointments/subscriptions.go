@@ -0,0 +1,205 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// StatusSubscription makes a staff member, e.g. a practice manager, hear
+// about status changes of the clinic's appointments. Each list narrows the
+// changes they are told about; an empty list matches anything, so a
+// subscription with only Statuses ["cancelled"] covers every cancellation.
+type StatusSubscription struct {
+	ID              primitive.ObjectID   `bson:"_id,omitempty"`
+	TenantID        primitive.ObjectID   `bson:"tenant_id"`
+	UserID          primitive.ObjectID   `bson:"user_id"`
+	Statuses        []string             `bson:"statuses"`
+	Types           []string             `bson:"types"`
+	VeterinarianIDs []primitive.ObjectID `bson:"veterinarian_ids"`
+	CreatedAt       time.Time            `bson:"created_at"`
+	UpdatedAt       time.Time            `bson:"updated_at"`
+}
+
+// StatusSubscriptionRepository stores one subscription per staff member and clinic
+type StatusSubscriptionRepository interface {
+	FindByUser(ctx context.Context, userID, tenantID primitive.ObjectID) (*StatusSubscription, error)
+	Upsert(ctx context.Context, sub *StatusSubscription) error
+	Delete(ctx context.Context, userID, tenantID primitive.ObjectID) error
+	// FindMatching returns the subscriptions interested in an appointment of
+	// the given type and vet moving to status
+	FindMatching(ctx context.Context, tenantID primitive.ObjectID, status, apptType string, vetID primitive.ObjectID) ([]StatusSubscription, error)
+}
+
+type statusSubscriptionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewStatusSubscriptionRepository creates a new status subscription repository
+func NewStatusSubscriptionRepository(db *database.MongoDB) StatusSubscriptionRepository {
+	return &statusSubscriptionRepository{collection: db.Collection("appointment_status_subscriptions")}
+}
+
+func (r *statusSubscriptionRepository) FindByUser(ctx context.Context, userID, tenantID primitive.ObjectID) (*StatusSubscription, error) {
+	var sub StatusSubscription
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "user_id": userID}).Decode(&sub)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *statusSubscriptionRepository) Upsert(ctx context.Context, sub *StatusSubscription) error {
+	filter := bson.M{"tenant_id": sub.TenantID, "user_id": sub.UserID}
+	update := bson.M{
+		"$set": bson.M{
+			"statuses":         sub.Statuses,
+			"types":            sub.Types,
+			"veterinarian_ids": sub.VeterinarianIDs,
+			"updated_at":       sub.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"created_at": sub.CreatedAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(sub)
+}
+
+func (r *statusSubscriptionRepository) Delete(ctx context.Context, userID, tenantID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"tenant_id": tenantID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *statusSubscriptionRepository) FindMatching(ctx context.Context, tenantID primitive.ObjectID, status, apptType string, vetID primitive.ObjectID) ([]StatusSubscription, error) {
+	anyOr := func(field string, value any) bson.M {
+		return bson.M{"$or": bson.A{
+			bson.M{field: value},
+			bson.M{field: bson.M{"$size": 0}},
+		}}
+	}
+	filter := bson.M{
+		"tenant_id": tenantID,
+		"$and": bson.A{
+			anyOr("statuses", status),
+			anyOr("types", apptType),
+			anyOr("veterinarian_ids", vetID),
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var subs []StatusSubscription
+	if err := cursor.All(ctx, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// GetStatusSubscription returns the caller's subscription
+func (s *Service) GetStatusSubscription(ctx context.Context, userID, tenantID primitive.ObjectID) (*StatusSubscriptionResponse, error) {
+	sub, err := s.subscriptions.FindByUser(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return sub.ToResponse(), nil
+}
+
+// SetStatusSubscription creates or replaces the caller's subscription
+func (s *Service) SetStatusSubscription(ctx context.Context, dto StatusSubscriptionDTO, userID, tenantID primitive.ObjectID) (*StatusSubscriptionResponse, error) {
+	if err := s.validateTypeFilter(ctx, tenantID, dto.Types); err != nil {
+		return nil, err
+	}
+	vetIDs := make([]primitive.ObjectID, 0, len(dto.VeterinarianIDs))
+	for _, raw := range dto.VeterinarianIDs {
+		vetID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			return nil, ErrValidationFailed("veterinarian_ids", "invalid veterinarian ID format")
+		}
+		vetIDs = append(vetIDs, vetID)
+	}
+
+	now := time.Now()
+	sub := &StatusSubscription{
+		TenantID:        tenantID,
+		UserID:          userID,
+		Statuses:        nonNil(dto.Statuses),
+		Types:           nonNil(dto.Types),
+		VeterinarianIDs: vetIDs,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.subscriptions.Upsert(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub.ToResponse(), nil
+}
+
+// DeleteStatusSubscription stops the caller's status notifications
+func (s *Service) DeleteStatusSubscription(ctx context.Context, userID, tenantID primitive.ObjectID) error {
+	return s.subscriptions.Delete(ctx, userID, tenantID)
+}
+
+// notifyStatusSubscribers tells subscribed staff about a status change. The
+// person who made the change is not notified of it.
+func (s *Service) notifyStatusSubscribers(ctx context.Context, appointment *Appointment, toStatus, reason string, changedBy primitive.ObjectID) {
+	if s.subscriptions == nil {
+		return
+	}
+	subs, err := s.subscriptions.FindMatching(ctx, appointment.TenantID, toStatus, appointment.Type, appointment.VeterinarianID)
+	if err != nil {
+		slog.Error("failed to load status subscriptions", "tenant_id", appointment.TenantID.Hex(), "error", err)
+		return
+	}
+
+	body := fmt.Sprintf("La cita del %s pasó de %s a %s", appointment.ScheduledAt.Format("02/01/2006 15:04"), appointment.Status, toStatus)
+	if reason != "" {
+		body += ". Razón: " + reason
+	}
+	for _, sub := range subs {
+		if sub.UserID == changedBy {
+			continue
+		}
+		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+			UserID:   sub.UserID.Hex(),
+			TenantID: appointment.TenantID.Hex(),
+			Type:     notifications.TypeStaffAppointmentStatus,
+			Title:    "Cambio de estado de cita",
+			Body:     body,
+			Data: map[string]string{
+				"appointment_id": appointment.ID.Hex(),
+				"from_status":    appointment.Status,
+				"to_status":      toStatus,
+			},
+		})
+	}
+}
+
+// nonNil stores empty filters as empty arrays, which FindMatching treats as "any"
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,87 @@
+package appointments
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// GetVetToday returns the vet's appointments for the current day in the
+// clinic's timezone, oldest first, with cancelled ones left out. Waiting
+// patients the clinic queues first by priority are moved to the front. Patients and
+// owners are looked up once each since a vet often sees several pets of the
+// same family in a day.
+func (s *Service) GetVetToday(ctx context.Context, vetID, tenantID primitive.ObjectID) (*VetTodayResponse, error) {
+	loc := time.UTC
+	priority := tenant.PrioritySettings{}.Effective()
+	if t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex()); err == nil {
+		if l, err := time.LoadLocation(t.TimeZone); err == nil && t.TimeZone != "" {
+			loc = l
+		}
+		priority = t.Settings.Priority.Effective()
+	}
+
+	now := time.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	appointments, err := s.repo.FindByVeterinarian(ctx, vetID, dayStart, dayEnd, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.CountPendingForVeterinarian(ctx, vetID, now, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	patientsByID := make(map[primitive.ObjectID]*PatientSummary)
+	ownersByID := make(map[primitive.ObjectID]*OwnerSummary)
+
+	items := make([]VetTodayAppointment, 0, len(appointments))
+	for _, a := range appointments {
+		if a.Status == AppointmentStatusCancelled {
+			continue
+		}
+
+		patient, ok := patientsByID[a.PatientID]
+		if !ok {
+			if p, err := s.patientRepo.FindByID(ctx, tenantID, a.PatientID.Hex()); err == nil {
+				patient = &PatientSummary{ID: p.ID.Hex(), Name: p.Name}
+			}
+			patientsByID[a.PatientID] = patient
+		}
+		owner, ok := ownersByID[a.OwnerID]
+		if !ok {
+			if o, err := s.ownerRepo.FindByID(ctx, a.OwnerID.Hex()); err == nil {
+				owner = &OwnerSummary{ID: o.ID.Hex(), Name: o.Name, Phone: o.Phone}
+			}
+			ownersByID[a.OwnerID] = owner
+		}
+
+		items = append(items, VetTodayAppointment{
+			ID:          a.ID.Hex(),
+			ScheduledAt: a.ScheduledAt,
+			Duration:    a.Duration,
+			Type:        a.Type,
+			Status:      a.Status,
+			Priority:    a.Priority,
+			Reason:      a.Reason,
+			CheckedIn:   a.StartedAt != nil,
+			Patient:     patient,
+			Owner:       owner,
+		})
+	}
+
+	sortCheckInQueue(items, priority)
+
+	return &VetTodayResponse{
+		Date:            dayStart.Format("2006-01-02"),
+		TimeZone:        loc.String(),
+		Appointments:    items,
+		PendingRequests: pending,
+	}, nil
+}
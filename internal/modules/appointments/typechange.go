@@ -0,0 +1,68 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// Bounds of an appointment's length in minutes, as the DTOs accept them
+const (
+	MinAppointmentDuration = 15
+	MaxAppointmentDuration = 480
+)
+
+// Warnings raised when staff change the type of a booked appointment
+const (
+	// WarningDurationAdjusted flags a duration reset to the new type's default
+	WarningDurationAdjusted = "DURATION_ADJUSTED"
+	// WarningDepositRequired flags a new type that asks for a deposit the
+	// appointment was booked without; it is not collected retroactively
+	WarningDepositRequired = "DEPOSIT_REQUIRED"
+)
+
+// typeChangeDuration returns the duration the appointment ends up with. An
+// explicit duration always wins; otherwise a change to newType (nil when the
+// type stays the same) re-applies that type's default.
+func typeChangeDuration(appointment *Appointment, newType *AppointmentTypeConfig, explicit *int) (int, []AppointmentWarning, error) {
+	duration := appointment.Duration
+	var warnings []AppointmentWarning
+	switch {
+	case explicit != nil:
+		duration = *explicit
+	case newType != nil && newType.DefaultDuration > 0 && newType.DefaultDuration != appointment.Duration:
+		duration = newType.DefaultDuration
+		warnings = append(warnings, AppointmentWarning{
+			Code:    WarningDurationAdjusted,
+			Message: fmt.Sprintf("Duration changed from %d to %d minutes, the default of %s", appointment.Duration, duration, newType.Name),
+		})
+	}
+
+	if duration < MinAppointmentDuration || duration > MaxAppointmentDuration {
+		return 0, nil, ErrValidationFailed("duration", fmt.Sprintf("duration must be between %d and %d minutes", MinAppointmentDuration, MaxAppointmentDuration))
+	}
+	return duration, warnings, nil
+}
+
+// depositWarnings flags an appointment moved to a type that requires a
+// deposit when none was collected for it
+func (s *Service) depositWarnings(ctx context.Context, appointment *Appointment, newType *AppointmentTypeConfig) []AppointmentWarning {
+	if appointment.Deposit != nil {
+		return nil
+	}
+	t, err := s.tenantRepo.FindByID(ctx, appointment.TenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant settings, skipping deposit check", "tenant_id", appointment.TenantID.Hex(), "error", err)
+		return nil
+	}
+	rule, ok := depositRule(t, newType.Key, newType)
+	if !ok {
+		return nil
+	}
+	return []AppointmentWarning{{
+		Code:    WarningDepositRequired,
+		Message: fmt.Sprintf("%s requires a deposit of %s that was not collected for this appointment", newType.Name, money.Format(rule.Amount, depositCurrency(t, rule))),
+	}}
+}
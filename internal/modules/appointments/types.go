@@ -0,0 +1,416 @@
+package appointments
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// AppointmentTypeConfig is a kind of visit a clinic books. Key is what
+// appointments store in their type field; the built-in constants are the keys
+// every clinic starts with.
+type AppointmentTypeConfig struct {
+	ID              primitive.ObjectID         `bson:"_id,omitempty"`
+	TenantID        primitive.ObjectID         `bson:"tenant_id"`
+	Key             string                     `bson:"key"`
+	Name            string                     `bson:"name"`
+	DefaultDuration int                        `bson:"default_duration"` // minutes
+	Color           string                     `bson:"color,omitempty"`
+	RequiresVet     bool                       `bson:"requires_vet"`
+	Specialty       string                     `bson:"specialty,omitempty"` // vets with it are preferred by the by_specialty auto-assignment
+	RoomKind        string                     `bson:"room_kind,omitempty"` // when set, staff bookings must reserve a room of this kind
+	Deposit         *tenant.AppointmentDeposit `bson:"deposit,omitempty"`
+	// Fee is what the visit is billed at, in the clinic's currency, when the
+	// clinic drafts invoices for completed appointments
+	Fee float64 `bson:"fee,omitempty"`
+	// MinNoticeHours is the preparation time the type needs, on top of the
+	// clinic's minimum booking notice; 0 adds none
+	MinNoticeHours int `bson:"min_notice_hours,omitempty"`
+	// OwnerLocked keeps owners from cancelling or rescheduling appointments of
+	// this type themselves; they are told to call the clinic instead
+	OwnerLocked bool `bson:"owner_locked,omitempty"`
+	// RequiresConsent keeps appointments of this type from starting until the
+	// owner has signed the current consent text for it
+	RequiresConsent bool `bson:"requires_consent,omitempty"`
+	// Inactive types are kept for existing appointments but cannot be booked
+	Active    bool       `bson:"active"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
+}
+
+// builtinAppointmentTypes are the types of a clinic that has not configured
+// its own; they are stored as its starting list once staff manage the list.
+var builtinAppointmentTypes = []AppointmentTypeConfig{
+	{Key: AppointmentTypeConsultation, Name: "Consulta", DefaultDuration: 30, RequiresVet: true},
+	{Key: AppointmentTypeSurgery, Name: "Cirugía", DefaultDuration: 120, RequiresVet: true, OwnerLocked: true},
+	{Key: AppointmentTypeVaccination, Name: "Vacunación", DefaultDuration: 15, RequiresVet: true},
+	{Key: AppointmentTypeEmergency, Name: "Emergencia", DefaultDuration: 60, RequiresVet: true},
+	{Key: AppointmentTypeCheckup, Name: "Control", DefaultDuration: 30, RequiresVet: true},
+	{Key: AppointmentTypeGrooming, Name: "Peluquería", DefaultDuration: 60, RequiresVet: false},
+}
+
+// typeKeyPattern keeps keys usable as query values and calendar style keys
+var typeKeyPattern = regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*$`)
+
+// AppointmentTypeRepository stores each clinic's appointment types
+type AppointmentTypeRepository interface {
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]AppointmentTypeConfig, error)
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*AppointmentTypeConfig, error)
+	Create(ctx context.Context, t *AppointmentTypeConfig) error
+	CreateMany(ctx context.Context, types []AppointmentTypeConfig) error
+	Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error
+	Delete(ctx context.Context, id, tenantID primitive.ObjectID) error
+}
+
+type appointmentTypeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAppointmentTypeRepository creates a new appointment type repository
+func NewAppointmentTypeRepository(db *database.MongoDB) AppointmentTypeRepository {
+	return &appointmentTypeRepository{collection: db.Collection("appointment_types")}
+}
+
+func (r *appointmentTypeRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]AppointmentTypeConfig, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID, "deleted_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []AppointmentTypeConfig{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *appointmentTypeRepository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*AppointmentTypeConfig, error) {
+	var t AppointmentTypeConfig
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil}).Decode(&t)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAppointmentTypeNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *appointmentTypeRepository) Create(ctx context.Context, t *AppointmentTypeConfig) error {
+	result, err := r.collection.InsertOne(ctx, t)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAppointmentTypeExists
+		}
+		return err
+	}
+	t.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *appointmentTypeRepository) CreateMany(ctx context.Context, types []AppointmentTypeConfig) error {
+	docs := make([]interface{}, len(types))
+	for i := range types {
+		docs[i] = types[i]
+	}
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	// A concurrent seed may have inserted some of them already
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *appointmentTypeRepository) Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAppointmentTypeNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes the type and frees its key for reuse
+func (r *appointmentTypeRepository) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAppointmentTypeNotFound
+	}
+	return nil
+}
+
+// appointmentTypes returns the clinic's types, or the built-in ones when it
+// has not configured any. Lookup failures also fall back to the built-ins so
+// bookings keep working.
+func (s *Service) appointmentTypes(ctx context.Context, tenantID primitive.ObjectID) []AppointmentTypeConfig {
+	types, err := s.loadAppointmentTypes(ctx, tenantID)
+	if err != nil {
+		slog.Warn("failed to load appointment types, using built-in types", "tenant_id", tenantID.Hex(), "error", err)
+		return builtinTypes(tenantID)
+	}
+	return types
+}
+
+// loadAppointmentTypes is appointmentTypes without the fallback on lookup
+// failures, for checks that must not pass when the types are unknown
+func (s *Service) loadAppointmentTypes(ctx context.Context, tenantID primitive.ObjectID) ([]AppointmentTypeConfig, error) {
+	if s.types != nil {
+		types, err := s.types.FindByTenant(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if len(types) > 0 {
+			return types, nil
+		}
+	}
+	return builtinTypes(tenantID), nil
+}
+
+func builtinTypes(tenantID primitive.ObjectID) []AppointmentTypeConfig {
+	defaults := make([]AppointmentTypeConfig, len(builtinAppointmentTypes))
+	for i, t := range builtinAppointmentTypes {
+		t.TenantID = tenantID
+		t.Color = defaultTypeStyles[t.Key].Color
+		t.Active = true
+		defaults[i] = t
+	}
+	return defaults
+}
+
+// resolveAppointmentType finds the clinic's type for key. Inactive types are
+// only accepted when the appointment already had that type.
+func (s *Service) resolveAppointmentType(ctx context.Context, tenantID primitive.ObjectID, key string, current string) (*AppointmentTypeConfig, error) {
+	for _, t := range s.appointmentTypes(ctx, tenantID) {
+		if t.Key != key {
+			continue
+		}
+		if !t.Active && key != current {
+			return nil, ErrUnknownAppointmentType(key, "inactive")
+		}
+		return &t, nil
+	}
+	return nil, ErrUnknownAppointmentType(key, "unknown")
+}
+
+// validateTypeFilter rejects list filters naming types the clinic does not have
+func (s *Service) validateTypeFilter(ctx context.Context, tenantID primitive.ObjectID, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, t := range s.appointmentTypes(ctx, tenantID) {
+		known[t.Key] = true
+	}
+	for _, key := range keys {
+		if !known[key] {
+			return ErrUnknownAppointmentType(key, "unknown")
+		}
+	}
+	return nil
+}
+
+// seedAppointmentTypes stores the built-in types for a clinic that has none,
+// so its first change edits a full list instead of replacing the defaults.
+func (s *Service) seedAppointmentTypes(ctx context.Context, tenantID primitive.ObjectID) error {
+	existing, err := s.types.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	defaults := s.appointmentTypes(ctx, tenantID)
+	for i := range defaults {
+		defaults[i].CreatedAt = now
+		defaults[i].UpdatedAt = now
+	}
+	return s.types.CreateMany(ctx, defaults)
+}
+
+// ListAppointmentTypes returns the clinic's appointment types. Staff listings
+// store the built-in types first, so each entry has an ID to edit.
+func (s *Service) ListAppointmentTypes(ctx context.Context, tenantID primitive.ObjectID, activeOnly bool) []AppointmentTypeResponse {
+	if !activeOnly {
+		if err := s.seedAppointmentTypes(ctx, tenantID); err != nil {
+			slog.Warn("failed to seed appointment types", "tenant_id", tenantID.Hex(), "error", err)
+		}
+	}
+
+	types := s.appointmentTypes(ctx, tenantID)
+	resp := make([]AppointmentTypeResponse, 0, len(types))
+	for i := range types {
+		if activeOnly && !types[i].Active {
+			continue
+		}
+		resp = append(resp, types[i].ToResponse())
+	}
+	return resp
+}
+
+// CreateAppointmentType adds a type to the clinic's list
+func (s *Service) CreateAppointmentType(ctx context.Context, dto CreateAppointmentTypeDTO, tenantID primitive.ObjectID) (*AppointmentTypeResponse, error) {
+	if !typeKeyPattern.MatchString(dto.Key) {
+		return nil, ErrValidationFailed("key", "use lowercase letters, digits and underscores")
+	}
+	if err := s.seedAppointmentTypes(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	for _, t := range s.appointmentTypes(ctx, tenantID) {
+		if t.Key == dto.Key {
+			return nil, ErrAppointmentTypeExists
+		}
+	}
+
+	deposit, err := s.depositFromDTO(ctx, tenantID, dto.Deposit)
+	if err != nil {
+		return nil, err
+	}
+
+	requiresVet := true
+	if dto.RequiresVet != nil {
+		requiresVet = *dto.RequiresVet
+	}
+	now := time.Now()
+	t := &AppointmentTypeConfig{
+		TenantID:        tenantID,
+		Key:             dto.Key,
+		Name:            dto.Name,
+		DefaultDuration: dto.DefaultDuration,
+		Color:           dto.Color,
+		RequiresVet:     requiresVet,
+		Specialty:       dto.Specialty,
+		RoomKind:        dto.RoomKind,
+		Deposit:         deposit,
+		Fee:             dto.Fee,
+		MinNoticeHours:  dto.MinNoticeHours,
+		OwnerLocked:     dto.OwnerLocked,
+		RequiresConsent: dto.RequiresConsent,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.types.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	resp := t.ToResponse()
+	return &resp, nil
+}
+
+// UpdateAppointmentType changes a type of the clinic. The key is fixed, since
+// existing appointments refer to it.
+func (s *Service) UpdateAppointmentType(ctx context.Context, id string, dto UpdateAppointmentTypeDTO, tenantID primitive.ObjectID) (*AppointmentTypeResponse, error) {
+	typeID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidationFailed("id", "invalid appointment type ID format")
+	}
+
+	updates := bson.M{"updated_at": time.Now()}
+	if dto.Name != nil {
+		updates["name"] = *dto.Name
+	}
+	if dto.DefaultDuration != nil {
+		updates["default_duration"] = *dto.DefaultDuration
+	}
+	if dto.Color != nil {
+		updates["color"] = *dto.Color
+	}
+	if dto.RequiresVet != nil {
+		updates["requires_vet"] = *dto.RequiresVet
+	}
+	if dto.Specialty != nil {
+		updates["specialty"] = *dto.Specialty
+	}
+	if dto.RoomKind != nil {
+		updates["room_kind"] = *dto.RoomKind
+	}
+	if dto.Active != nil {
+		updates["active"] = *dto.Active
+	}
+	if dto.OwnerLocked != nil {
+		updates["owner_locked"] = *dto.OwnerLocked
+	}
+	if dto.RequiresConsent != nil {
+		updates["requires_consent"] = *dto.RequiresConsent
+	}
+	if dto.Fee != nil {
+		updates["fee"] = *dto.Fee
+	}
+	if dto.MinNoticeHours != nil {
+		updates["min_notice_hours"] = *dto.MinNoticeHours
+	}
+	if dto.Deposit != nil {
+		// A zero amount stops requiring a deposit
+		deposit, err := s.depositFromDTO(ctx, tenantID, dto.Deposit)
+		if err != nil {
+			return nil, err
+		}
+		updates["deposit"] = deposit
+	}
+
+	if err := s.types.Update(ctx, typeID, tenantID, updates); err != nil {
+		return nil, err
+	}
+
+	t, err := s.types.FindByID(ctx, typeID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := t.ToResponse()
+	return &resp, nil
+}
+
+// DeleteAppointmentType removes a type from the clinic's list. Appointments
+// already booked with it keep their type.
+func (s *Service) DeleteAppointmentType(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	typeID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrValidationFailed("id", "invalid appointment type ID format")
+	}
+	return s.types.Delete(ctx, typeID, tenantID)
+}
+
+// depositFromDTO converts a deposit given in major units to minor units of the
+// clinic currency. A zero amount means no deposit.
+func (s *Service) depositFromDTO(ctx context.Context, tenantID primitive.ObjectID, dto *tenant.AppointmentDepositDTO) (*tenant.AppointmentDeposit, error) {
+	if dto == nil || dto.Amount <= 0 {
+		return nil, nil
+	}
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	currency := money.Normalize(t.Currency)
+	amount := money.FromMajor(dto.Amount, currency)
+	if amount <= 0 {
+		return nil, nil
+	}
+	return &tenant.AppointmentDeposit{Amount: amount, Currency: currency, ExpiresAfterMinutes: dto.ExpiresAfterMinutes}, nil
+}
@@ -0,0 +1,298 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// StatusConfig is a status of a clinic's workflow. Custom statuses, such as
+// "awaiting_labs", are active intermediate states; ActsAs lets one run the
+// side effects of a built-in status, e.g. notifying the owner like a
+// confirmation does.
+type StatusConfig struct {
+	Key    string `bson:"key"`
+	Label  string `bson:"label"`
+	ActsAs string `bson:"acts_as,omitempty"`
+}
+
+// AppointmentWorkflow is the status set and allowed transitions of a clinic.
+// Clinics without one use the built-in machine of ValidStatusTransitions.
+type AppointmentWorkflow struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID    primitive.ObjectID  `bson:"tenant_id"`
+	Statuses    []StatusConfig      `bson:"statuses"`
+	Transitions map[string][]string `bson:"transitions"`
+	UpdatedAt   time.Time           `bson:"updated_at"`
+}
+
+// builtinStatusLabels are the statuses every workflow keeps, since bookings,
+// deposits, reminders and reports rely on them
+var builtinStatusLabels = map[string]string{
+	AppointmentStatusScheduled:       "Programada",
+	AppointmentStatusConfirmed:       "Confirmada",
+	AppointmentStatusInProgress:      "En curso",
+	AppointmentStatusCompleted:       "Completada",
+	AppointmentStatusCancelled:       "Cancelada",
+	AppointmentStatusNoShow:          "No asistió",
+	AppointmentStatusAwaitingDeposit: "Esperando anticipo",
+}
+
+// builtinStatusOrder lists the built-in statuses in workflow order
+var builtinStatusOrder = []string{
+	AppointmentStatusAwaitingDeposit,
+	AppointmentStatusScheduled,
+	AppointmentStatusConfirmed,
+	AppointmentStatusInProgress,
+	AppointmentStatusCompleted,
+	AppointmentStatusCancelled,
+	AppointmentStatusNoShow,
+}
+
+// entryStatuses are the statuses new appointments are booked in
+var entryStatuses = []string{AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusAwaitingDeposit}
+
+// terminalStatuses close an appointment; they have no outgoing transitions
+var terminalStatuses = map[string]bool{
+	AppointmentStatusCompleted: true,
+	AppointmentStatusCancelled: true,
+	AppointmentStatusNoShow:    true,
+}
+
+// actsAsStatuses are the built-in statuses whose side effects a custom status
+// may run. Closing statuses are left out: custom statuses stay active.
+var actsAsStatuses = map[string]bool{
+	AppointmentStatusConfirmed:  true,
+	AppointmentStatusInProgress: true,
+}
+
+// defaultWorkflow returns the built-in status machine
+func defaultWorkflow(tenantID primitive.ObjectID) *AppointmentWorkflow {
+	w := &AppointmentWorkflow{TenantID: tenantID, Transitions: map[string][]string{}}
+	for _, key := range builtinStatusOrder {
+		w.Statuses = append(w.Statuses, StatusConfig{Key: key, Label: builtinStatusLabels[key]})
+		w.Transitions[key] = append([]string{}, ValidStatusTransitions[key]...)
+	}
+	return w
+}
+
+func (w *AppointmentWorkflow) status(key string) *StatusConfig {
+	for i := range w.Statuses {
+		if w.Statuses[i].Key == key {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// canTransition reports whether the workflow allows moving from one status to another
+func (w *AppointmentWorkflow) canTransition(from, to string) bool {
+	for _, next := range w.Transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveStatus is the built-in status whose side effects entering key runs;
+// empty for custom statuses without any
+func (w *AppointmentWorkflow) effectiveStatus(key string) string {
+	if _, ok := builtinStatusLabels[key]; ok {
+		return key
+	}
+	if st := w.status(key); st != nil {
+		return st.ActsAs
+	}
+	return ""
+}
+
+// validate rejects workflows that drop a built-in status, name undeclared
+// statuses, reopen closed appointments, or leave a status that appointments
+// could never reach or never leave
+func (w *AppointmentWorkflow) validate() error {
+	seen := map[string]bool{}
+	for _, st := range w.Statuses {
+		if !typeKeyPattern.MatchString(st.Key) {
+			return ErrInvalidWorkflow(st.Key, "status keys must be lowercase words joined by underscores")
+		}
+		if seen[st.Key] {
+			return ErrInvalidWorkflow(st.Key, "status is listed twice")
+		}
+		seen[st.Key] = true
+
+		_, builtin := builtinStatusLabels[st.Key]
+		if builtin && st.ActsAs != "" {
+			return ErrInvalidWorkflow(st.Key, "built-in statuses keep their own behaviour")
+		}
+		if !builtin && st.ActsAs != "" && !actsAsStatuses[st.ActsAs] {
+			return ErrInvalidWorkflow(st.Key, "acts_as must be confirmed or in_progress")
+		}
+	}
+	for _, key := range builtinStatusOrder {
+		if !seen[key] {
+			return ErrInvalidWorkflow(key, "built-in statuses cannot be removed")
+		}
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return ErrInvalidWorkflow(from, "transition from an undeclared status")
+		}
+		if terminalStatuses[from] && len(targets) > 0 {
+			return ErrInvalidWorkflow(from, "closed appointments cannot change status")
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return ErrInvalidWorkflow(to, "transition to an undeclared status")
+			}
+			if to == from {
+				return ErrInvalidWorkflow(from, "a status cannot transition to itself")
+			}
+		}
+	}
+
+	reachable := w.reachableFrom(entryStatuses...)
+	for _, st := range w.Statuses {
+		if !reachable[st.Key] {
+			return ErrInvalidWorkflow(st.Key, "status cannot be reached from scheduled, confirmed or awaiting_deposit")
+		}
+		if terminalStatuses[st.Key] {
+			continue
+		}
+		closes := false
+		for key := range w.reachableFrom(st.Key) {
+			if terminalStatuses[key] {
+				closes = true
+				break
+			}
+		}
+		if !closes {
+			return ErrInvalidWorkflow(st.Key, "appointments in this status could never be completed or cancelled")
+		}
+	}
+	return nil
+}
+
+// reachableFrom returns the statuses reachable from the given ones, including them
+func (w *AppointmentWorkflow) reachableFrom(start ...string) map[string]bool {
+	seen := map[string]bool{}
+	queue := append([]string{}, start...)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		queue = append(queue, w.Transitions[key]...)
+	}
+	return seen
+}
+
+// AppointmentWorkflowRepository stores each clinic's status workflow
+type AppointmentWorkflowRepository interface {
+	// FindByTenant returns the clinic's workflow, or nil when it uses the built-in one
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID) (*AppointmentWorkflow, error)
+	Upsert(ctx context.Context, w *AppointmentWorkflow) error
+}
+
+type appointmentWorkflowRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAppointmentWorkflowRepository creates a new appointment workflow repository
+func NewAppointmentWorkflowRepository(db *database.MongoDB) AppointmentWorkflowRepository {
+	return &appointmentWorkflowRepository{collection: db.Collection("appointment_workflows")}
+}
+
+func (r *appointmentWorkflowRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) (*AppointmentWorkflow, error) {
+	var w AppointmentWorkflow
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID}).Decode(&w)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *appointmentWorkflowRepository) Upsert(ctx context.Context, w *AppointmentWorkflow) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"tenant_id": w.TenantID},
+		bson.M{"$set": bson.M{
+			"statuses":    w.Statuses,
+			"transitions": w.Transitions,
+			"updated_at":  w.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// statusWorkflow returns the clinic's workflow, or the built-in one when it
+// has not configured its own. Lookup failures also fall back to the built-in
+// machine so status changes keep working.
+func (s *Service) statusWorkflow(ctx context.Context, tenantID primitive.ObjectID) *AppointmentWorkflow {
+	if s.workflows != nil {
+		w, err := s.workflows.FindByTenant(ctx, tenantID)
+		if err != nil {
+			slog.Warn("failed to load appointment workflow, using built-in statuses", "tenant_id", tenantID.Hex(), "error", err)
+		} else if w != nil {
+			return w
+		}
+	}
+	return defaultWorkflow(tenantID)
+}
+
+// GetStatusWorkflow returns the clinic's statuses and allowed transitions
+func (s *Service) GetStatusWorkflow(ctx context.Context, tenantID primitive.ObjectID) *StatusWorkflowResponse {
+	return s.statusWorkflow(ctx, tenantID).ToResponse()
+}
+
+// UpdateStatusWorkflow replaces the clinic's workflow. A custom status can
+// only be dropped once no appointment is left in it.
+func (s *Service) UpdateStatusWorkflow(ctx context.Context, dto UpdateStatusWorkflowDTO, tenantID primitive.ObjectID) (*StatusWorkflowResponse, error) {
+	w := &AppointmentWorkflow{
+		TenantID:    tenantID,
+		Transitions: map[string][]string{},
+		UpdatedAt:   time.Now(),
+	}
+	for _, st := range dto.Statuses {
+		w.Statuses = append(w.Statuses, StatusConfig{Key: st.Key, Label: st.Label, ActsAs: st.ActsAs})
+	}
+	for from, targets := range dto.Transitions {
+		w.Transitions[from] = targets
+	}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+
+	for _, st := range s.statusWorkflow(ctx, tenantID).Statuses {
+		if w.status(st.Key) != nil {
+			continue
+		}
+		_, total, err := s.repo.List(ctx, appointmentFilters{Status: []string{st.Key}}, tenantID, pagination.Params{Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			return nil, ErrInvalidWorkflow(st.Key, fmt.Sprintf("%d appointments are still in this status", total))
+		}
+	}
+
+	if err := s.workflows.Upsert(ctx, w); err != nil {
+		return nil, err
+	}
+	return w.ToResponse(), nil
+}
@@ -40,12 +40,16 @@ const (
 	EventAppointmentUpdated   EventType = "appointment.updated"
 	EventAppointmentCancelled EventType = "appointment.cancelled"
 	EventAppointmentCompleted EventType = "appointment.completed"
+	EventAppointmentDeleted   EventType = "appointment.deleted"
 
 	// Patient events
 	EventPatientCreated EventType = "patient.created"
 	EventPatientUpdated EventType = "patient.updated"
 	EventPatientDeleted EventType = "patient.deleted"
 
+	// Owner events
+	EventOwnerMerged EventType = "owner.merged"
+
 	// RBAC events
 	EventRoleCreated    EventType = "role.created"
 	EventRoleUpdated    EventType = "role.updated"
@@ -55,6 +59,7 @@ const (
 
 	// System events
 	EventSystemAlert EventType = "system.alert"
+	EventDataPurged  EventType = "system.data_purged"
 )
 
 // AuditEvent represents an audit log entry
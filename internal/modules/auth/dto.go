@@ -1,5 +1,7 @@
 package auth
 
+import "github.com/eren_dev/go_server/internal/modules/users"
+
 // RegisterDTO datos para registro
 // @name RegisterDTO
 type RegisterDTO struct {
@@ -18,6 +20,8 @@ type LoginDTO struct {
 	Email string `json:"email" binding:"required,email" example:"john@example.com"`
 	// Contraseña
 	Password string `json:"password" binding:"required" example:"secret123"`
+	// Mantener la sesión iniciada: el refresh token dura JWT_REMEMBER_EXPIRATION_DAYS
+	Remember bool `json:"remember" example:"false"`
 }
 
 // RefreshDTO datos para refresh token
@@ -36,6 +40,8 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIs..."`
 	// Tiempo de expiración en segundos
 	ExpiresIn int64 `json:"expires_in" example:"900"`
+	// Tiempo de expiración del refresh token en segundos
+	RefreshExpiresIn int64 `json:"refresh_expires_in" example:"86400"`
 }
 
 // UserInfo información del usuario autenticado
@@ -47,4 +53,13 @@ type UserInfo struct {
 	Name string `json:"name" example:"John Doe"`
 	// Email del usuario
 	Email string `json:"email" example:"john@example.com"`
+	// Preferencias de notificación por email
+	NotificationPrefs users.NotificationPreferences `json:"notification_prefs"`
+}
+
+// UpdateNotificationPrefsDTO preferencias de notificación del usuario
+// @name UpdateNotificationPrefsDTO
+type UpdateNotificationPrefsDTO struct {
+	// Recibir el resumen semanal de agenda por email
+	WeeklyDigest bool `json:"weekly_digest" example:"true"`
 }
@@ -38,7 +38,7 @@ func (h *Handler) Register(c *gin.Context) (any, error) {
 
 // Login godoc
 // @Summary      Iniciar sesión
-// @Description  Autentica un usuario y retorna tokens
+// @Description  Autentica un usuario y retorna tokens. Con remember=true el refresh token dura JWT_REMEMBER_EXPIRATION_DAYS en lugar de JWT_REFRESH_EXPIRATION_DAYS
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -93,3 +93,29 @@ func (h *Handler) Me(c *gin.Context) (any, error) {
 	}
 	return h.service.GetUserInfo(c.Request.Context(), userID)
 }
+
+// UpdateNotificationPrefs godoc
+// @Summary      Actualizar preferencias de notificación
+// @Description  Activa o desactiva las notificaciones por email del usuario autenticado (resumen semanal de agenda)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        body  body      UpdateNotificationPrefsDTO  true  "Preferencias"
+// @Success      200   {object}  UserInfo
+// @Failure      400   {object}  validation.ValidationError
+// @Failure      401   {object}  map[string]string "No autorizado"
+// @Router       /api/auth/me/notification-preferences [put]
+func (h *Handler) UpdateNotificationPrefs(c *gin.Context) (any, error) {
+	userID := sharedAuth.GetUserID(c)
+	if userID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	var dto UpdateNotificationPrefsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.UpdateNotificationPrefs(c.Request.Context(), userID, &dto)
+}
@@ -21,4 +21,5 @@ func RegisterRoutes(public *httpx.Router, private *httpx.Router, db *database.Mo
 
 	// Protected routes
 	private.GET("/auth/me", handler.Me)
+	private.PUT("/auth/me/notification-preferences", handler.UpdateNotificationPrefs)
 }
@@ -35,15 +35,16 @@ func (s *Service) Register(ctx context.Context, dto *RegisterDTO) (*TokenRespons
 		return nil, err
 	}
 
-	tokens, err := s.jwtService.GenerateTokenPair(user.ID.Hex(), user.Email, auth.UserTypeStaff)
+	tokens, err := s.jwtService.GenerateTokenPair(user.ID.Hex(), user.Email, auth.UserTypeStaff, false)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
 	}, nil
 }
 
@@ -60,15 +61,16 @@ func (s *Service) Login(ctx context.Context, dto *LoginDTO) (*TokenResponse, err
 		return nil, ErrInvalidCredentials
 	}
 
-	tokens, err := s.jwtService.GenerateTokenPair(user.ID.Hex(), user.Email, auth.UserTypeStaff)
+	tokens, err := s.jwtService.GenerateTokenPair(user.ID.Hex(), user.Email, auth.UserTypeStaff, dto.Remember)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
 	}, nil
 }
 
@@ -79,9 +81,10 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenRespo
 	}
 
 	return &TokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
 	}, nil
 }
 
@@ -92,8 +95,17 @@ func (s *Service) GetUserInfo(ctx context.Context, userID string) (*UserInfo, er
 	}
 
 	return &UserInfo{
-		ID:    user.ID.Hex(),
-		Name:  user.Name,
-		Email: user.Email,
+		ID:                user.ID.Hex(),
+		Name:              user.Name,
+		Email:             user.Email,
+		NotificationPrefs: user.NotificationPrefs,
 	}, nil
 }
+
+func (s *Service) UpdateNotificationPrefs(ctx context.Context, userID string, dto *UpdateNotificationPrefsDTO) (*UserInfo, error) {
+	prefs := users.NotificationPreferences{WeeklyDigest: dto.WeeklyDigest}
+	if err := s.userRepo.UpdateNotificationPrefs(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+	return s.GetUserInfo(ctx, userID)
+}
@@ -0,0 +1,82 @@
+package consents
+
+import (
+	"time"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// PublishTemplateDTO publishes a new version of a procedure's consent text
+type PublishTemplateDTO struct {
+	// Procedure is the appointment type key the consent is for
+	Procedure string `json:"procedure" binding:"required,max=50" example:"surgery"`
+	Title     string `json:"title" binding:"required,max=200" example:"Consentimiento informado para cirugía"`
+	Text      string `json:"text" binding:"required,max=20000" example:"Autorizo al equipo médico a realizar el procedimiento quirúrgico..."`
+}
+
+// SignatureDTO is an uploaded signature image
+type SignatureDTO struct {
+	FileID      string `json:"file_id" binding:"required,max=200" example:"uploads/2024/01/firma.png"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/png image/jpeg image/webp image/svg+xml" example:"image/png"`
+	SizeBytes   int64  `json:"size_bytes" binding:"required,min=1" example:"24576"`
+}
+
+// SignConsentDTO signs the consent of an appointment. TemplateVersion is the
+// version that was presented; signing fails if the text changed since.
+type SignConsentDTO struct {
+	TemplateVersion int          `json:"template_version" binding:"required,min=1" example:"3"`
+	SignedByName    string       `json:"signed_by_name" binding:"required,max=200" example:"María Pérez"`
+	Signature       SignatureDTO `json:"signature" binding:"required"`
+}
+
+// ConsentFilters narrows the consent list
+type ConsentFilters struct {
+	PatientID     string
+	AppointmentID string
+	Procedure     string
+}
+
+// TemplateResponse is a version of a consent text
+type TemplateResponse struct {
+	ID        string    `json:"id"`
+	Procedure string    `json:"procedure"`
+	Version   int       `json:"version"`
+	Title     string    `json:"title"`
+	Text      string    `json:"text"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SignatureResponse struct {
+	FileID      string `json:"file_id"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// ConsentResponse is a signed consent
+type ConsentResponse struct {
+	ID              string            `json:"id"`
+	PatientID       string            `json:"patient_id"`
+	OwnerID         string            `json:"owner_id"`
+	AppointmentID   string            `json:"appointment_id"`
+	Procedure       string            `json:"procedure"`
+	TemplateID      string            `json:"template_id"`
+	TemplateVersion int               `json:"template_version"`
+	TextHash        string            `json:"text_hash"`
+	SignedByName    string            `json:"signed_by_name"`
+	Signature       SignatureResponse `json:"signature"`
+	Channel         string            `json:"channel"`
+	RecordedBy      string            `json:"recorded_by,omitempty"`
+	SignedAt        time.Time         `json:"signed_at"`
+}
+
+// ConsentDetailResponse is a signed consent together with the text that was signed
+type ConsentDetailResponse struct {
+	ConsentResponse
+	Template TemplateResponse `json:"template"`
+}
+
+type PaginatedConsentsResponse struct {
+	Data       []ConsentResponse         `json:"data"`
+	Pagination pagination.PaginationInfo `json:"pagination"`
+}
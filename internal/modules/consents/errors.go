@@ -0,0 +1,19 @@
+package consents
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrTemplateNotFound  = sharedErrors.New(sharedErrors.ErrNotFound, "CONSENT_TEMPLATE_NOT_FOUND", "no consent text has been published for this procedure")
+	ErrTemplateConflict  = sharedErrors.New(sharedErrors.ErrConflict, "CONSENT_TEMPLATE_CONFLICT", "another version of this consent text was published at the same time, retry")
+	ErrTemplateOutdated  = sharedErrors.New(sharedErrors.ErrConflict, "CONSENT_TEMPLATE_OUTDATED", "the consent text changed since it was presented, review and sign the current version")
+	ErrConsentNotFound   = sharedErrors.New(sharedErrors.ErrNotFound, "CONSENT_NOT_FOUND", "consent not found")
+	ErrSignatureTooLarge = sharedErrors.New(sharedErrors.ErrInvalidInput, "SIGNATURE_TOO_LARGE", "validation failed: signature image exceeds the maximum file size")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
@@ -0,0 +1,132 @@
+package consents
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for consent texts and signed consents
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new consent handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// PublishTemplate publishes a consent text version
+// @Summary Publish consent text
+// @Description Publish a new version of the consent text of a procedure (an appointment type key). Earlier versions are kept, and consents already signed keep pointing at the version they were signed on
+// @Tags consents
+// @Accept json
+// @Produce json
+// @Param template body PublishTemplateDTO true "Consent text"
+// @Success 200 {object} TemplateResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/consent-templates [post]
+func (h *Handler) PublishTemplate(c *gin.Context) (any, error) {
+	var dto PublishTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	template, err := h.service.PublishTemplate(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c), userID)
+	if err != nil {
+		return nil, err
+	}
+	return template.ToResponse(), nil
+}
+
+// ListTemplates lists the current consent texts
+// @Summary List consent texts
+// @Description List the current version of the consent text of every procedure
+// @Tags consents
+// @Produce json
+// @Success 200 {array} TemplateResponse
+// @Security BearerAuth
+// @Router /api/consent-templates [get]
+func (h *Handler) ListTemplates(c *gin.Context) (any, error) {
+	templates, err := h.service.ListTemplates(c.Request.Context(), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return templateResponses(templates), nil
+}
+
+// TemplateVersions lists the versions of a consent text
+// @Summary List consent text versions
+// @Description List every version of a procedure's consent text, newest first
+// @Tags consents
+// @Produce json
+// @Param procedure path string true "Procedure (appointment type key)"
+// @Success 200 {array} TemplateResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/consent-templates/{procedure} [get]
+func (h *Handler) TemplateVersions(c *gin.Context) (any, error) {
+	versions, err := h.service.TemplateVersions(c.Request.Context(), sharedMiddleware.GetTenantID(c), c.Param("procedure"))
+	if err != nil {
+		return nil, err
+	}
+	return templateResponses(versions), nil
+}
+
+// List lists signed consents
+// @Summary List consents
+// @Description List the clinic's signed consents, newest first
+// @Tags consents
+// @Produce json
+// @Param patient_id query string false "Patient ID"
+// @Param appointment_id query string false "Appointment ID"
+// @Param procedure query string false "Procedure (appointment type key)"
+// @Param skip query int false "Skip"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedConsentsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/consents [get]
+func (h *Handler) List(c *gin.Context) (any, error) {
+	filters := ConsentFilters{
+		PatientID:     c.Query("patient_id"),
+		AppointmentID: c.Query("appointment_id"),
+		Procedure:     c.Query("procedure"),
+	}
+	return h.service.List(c.Request.Context(), sharedMiddleware.GetTenantID(c), filters, pagination.FromContext(c))
+}
+
+// Get gets a signed consent
+// @Summary Get consent
+// @Description Get a signed consent together with the exact text version that was signed
+// @Tags consents
+// @Produce json
+// @Param id path string true "Consent ID"
+// @Success 200 {object} ConsentDetailResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/consents/{id} [get]
+func (h *Handler) Get(c *gin.Context) (any, error) {
+	return h.service.Get(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+}
+
+func templateResponses(templates []ConsentTemplate) []TemplateResponse {
+	data := make([]TemplateResponse, len(templates))
+	for i := range templates {
+		data[i] = templates[i].ToResponse()
+	}
+	return data
+}
@@ -0,0 +1,37 @@
+package consents
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the consent_templates and consents collections
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("consent_templates").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// One document per version; publishing the same version twice fails
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "procedure", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("consents").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Consent check when an appointment starts
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "appointment_id", Value: 1}, {Key: "procedure", Value: 1}},
+		},
+		{
+			// A patient's consents, newest first
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "patient_id", Value: 1}, {Key: "signed_at", Value: -1}},
+		},
+	})
+	return err
+}
@@ -0,0 +1,161 @@
+package consents
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// TemplateRepository defines data access for versioned consent texts
+type TemplateRepository interface {
+	// Create stores a new version; ErrTemplateConflict if the version exists
+	Create(ctx context.Context, template *ConsentTemplate) error
+	FindLatest(ctx context.Context, tenantID primitive.ObjectID, procedure string) (*ConsentTemplate, error)
+	FindVersions(ctx context.Context, tenantID primitive.ObjectID, procedure string) ([]ConsentTemplate, error)
+	// FindAllLatest returns the current version of every procedure
+	FindAllLatest(ctx context.Context, tenantID primitive.ObjectID) ([]ConsentTemplate, error)
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*ConsentTemplate, error)
+}
+
+// Repository defines data access for signed consents
+type Repository interface {
+	Create(ctx context.Context, consent *Consent) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Consent, error)
+	FindLatestForAppointment(ctx context.Context, tenantID, appointmentID primitive.ObjectID, procedure string) (*Consent, error)
+	List(ctx context.Context, tenantID primitive.ObjectID, filter bson.M, params pagination.Params) ([]Consent, int64, error)
+}
+
+type templateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTemplateRepository creates a new consent template repository
+func NewTemplateRepository(db *database.MongoDB) TemplateRepository {
+	return &templateRepository{collection: db.Collection("consent_templates")}
+}
+
+func (r *templateRepository) Create(ctx context.Context, template *ConsentTemplate) error {
+	result, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTemplateConflict
+		}
+		return err
+	}
+	template.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *templateRepository) FindLatest(ctx context.Context, tenantID primitive.ObjectID, procedure string) (*ConsentTemplate, error) {
+	var template ConsentTemplate
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "procedure": procedure}, opts).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) FindVersions(ctx context.Context, tenantID primitive.ObjectID, procedure string) ([]ConsentTemplate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	return r.find(ctx, bson.M{"tenant_id": tenantID, "procedure": procedure}, opts)
+}
+
+func (r *templateRepository) FindAllLatest(ctx context.Context, tenantID primitive.ObjectID) ([]ConsentTemplate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "procedure", Value: 1}, {Key: "version", Value: -1}})
+	all, err := r.find(ctx, bson.M{"tenant_id": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := []ConsentTemplate{}
+	for _, t := range all {
+		if len(latest) == 0 || latest[len(latest)-1].Procedure != t.Procedure {
+			latest = append(latest, t)
+		}
+	}
+	return latest, nil
+}
+
+func (r *templateRepository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*ConsentTemplate, error) {
+	var template ConsentTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID}).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]ConsentTemplate, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []ConsentTemplate{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+type repository struct {
+	base *database.BaseRepository[Consent]
+}
+
+// NewRepository creates a new consent repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		base: database.NewBaseRepository[Consent](db.Collection("consents"), ErrConsentNotFound),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, consent *Consent) error {
+	result, err := r.base.Collection.InsertOne(ctx, consent)
+	if err != nil {
+		return err
+	}
+	consent.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Consent, error) {
+	return r.base.FindByID(ctx, id, tenantID)
+}
+
+func (r *repository) FindLatestForAppointment(ctx context.Context, tenantID, appointmentID primitive.ObjectID, procedure string) (*Consent, error) {
+	return r.base.FindOne(ctx, tenantID,
+		bson.M{"appointment_id": appointmentID, "procedure": procedure},
+		options.FindOne().SetSort(bson.D{{Key: "signed_at", Value: -1}}),
+	)
+}
+
+func (r *repository) List(ctx context.Context, tenantID primitive.ObjectID, filter bson.M, params pagination.Params) ([]Consent, int64, error) {
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "signed_at", Value: -1}})
+
+	results, total, err := r.base.Page(ctx, tenantID, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if results == nil {
+		results = []Consent{}
+	}
+	return results, total, nil
+}
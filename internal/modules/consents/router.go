@@ -0,0 +1,22 @@
+package consents
+
+import (
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers consent texts and the signed consent log
+// under /api (JWT + Tenant + RBAC). Consents are signed through the
+// appointment they are for, see the appointments module.
+func RegisterAdminRoutes(privateTenant *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewTemplateRepository(db), NewRepository(db)))
+
+	t := privateTenant.Group("/consent-templates")
+	t.GET("", handler.ListTemplates)
+	t.POST("", handler.PublishTemplate)
+	t.GET("/:procedure", handler.TemplateVersions)
+
+	c := privateTenant.Group("/consents")
+	c.GET("", handler.List)
+	c.GET("/:id", handler.Get)
+}
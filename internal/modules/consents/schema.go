@@ -0,0 +1,99 @@
+package consents
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Channels a consent can be signed through
+const (
+	// ChannelClinic is a consent signed at the clinic and captured by staff
+	ChannelClinic = "clinic"
+	// ChannelMobile is a consent the owner signed in the mobile app
+	ChannelMobile = "mobile"
+)
+
+// ConsentTemplate is one version of the consent text of a procedure. The
+// procedure is the appointment type key, e.g. "surgery". Editing the text
+// publishes a new version and older ones are kept, so every signed consent
+// points at the exact wording that was agreed to.
+type ConsentTemplate struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  primitive.ObjectID `bson:"tenant_id"`
+	Procedure string             `bson:"procedure"`
+	Version   int                `bson:"version"`
+	Title     string             `bson:"title"`
+	Text      string             `bson:"text"`
+	CreatedBy primitive.ObjectID `bson:"created_by"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// Signature is the uploaded image of a handwritten or on-screen signature
+type Signature struct {
+	FileID      string `bson:"file_id"`
+	ContentType string `bson:"content_type"`
+	SizeBytes   int64  `bson:"size_bytes"`
+}
+
+// Consent is an owner's signed consent to a procedure on their patient.
+// Consents are never edited or deleted: they are the clinic's proof of what
+// was agreed to, by whom and when.
+type Consent struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID      primitive.ObjectID `bson:"tenant_id"`
+	PatientID     primitive.ObjectID `bson:"patient_id"`
+	OwnerID       primitive.ObjectID `bson:"owner_id"`
+	AppointmentID primitive.ObjectID `bson:"appointment_id"`
+	Procedure     string             `bson:"procedure"`
+	// TemplateID and TemplateVersion name the text that was signed;
+	// TextHash is its SHA-256, to show the stored text was not altered
+	TemplateID      primitive.ObjectID `bson:"template_id"`
+	TemplateVersion int                `bson:"template_version"`
+	TextHash        string             `bson:"text_hash"`
+	SignedByName    string             `bson:"signed_by_name"`
+	Signature       Signature          `bson:"signature"`
+	Channel         string             `bson:"channel"`
+	// RecordedBy is the staff member who captured a consent signed at the clinic
+	RecordedBy *primitive.ObjectID `bson:"recorded_by,omitempty"`
+	SignedAt   time.Time           `bson:"signed_at"`
+}
+
+// ToResponse converts a template version to its API representation
+func (t *ConsentTemplate) ToResponse() TemplateResponse {
+	return TemplateResponse{
+		ID:        t.ID.Hex(),
+		Procedure: t.Procedure,
+		Version:   t.Version,
+		Title:     t.Title,
+		Text:      t.Text,
+		CreatedBy: t.CreatedBy.Hex(),
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// ToResponse converts a consent to its API representation
+func (c *Consent) ToResponse() ConsentResponse {
+	resp := ConsentResponse{
+		ID:              c.ID.Hex(),
+		PatientID:       c.PatientID.Hex(),
+		OwnerID:         c.OwnerID.Hex(),
+		AppointmentID:   c.AppointmentID.Hex(),
+		Procedure:       c.Procedure,
+		TemplateID:      c.TemplateID.Hex(),
+		TemplateVersion: c.TemplateVersion,
+		TextHash:        c.TextHash,
+		SignedByName:    c.SignedByName,
+		Signature: SignatureResponse{
+			FileID:      c.Signature.FileID,
+			ContentType: c.Signature.ContentType,
+			SizeBytes:   c.Signature.SizeBytes,
+		},
+		Channel:  c.Channel,
+		SignedAt: c.SignedAt,
+	}
+	if c.RecordedBy != nil {
+		resp.RecordedBy = c.RecordedBy.Hex()
+	}
+	return resp
+}
@@ -0,0 +1,193 @@
+package consents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// MaxSignatureSizeBytes caps the uploaded signature image
+const MaxSignatureSizeBytes = 2 << 20
+
+// Service provides business logic for procedure consents
+type Service struct {
+	templates TemplateRepository
+	repo      Repository
+}
+
+// NewService creates a new consent service
+func NewService(templates TemplateRepository, repo Repository) *Service {
+	return &Service{templates: templates, repo: repo}
+}
+
+// SignRequest is a consent being signed for an appointment. The caller has
+// already checked that the patient, owner and appointment belong together.
+type SignRequest struct {
+	TenantID      primitive.ObjectID
+	PatientID     primitive.ObjectID
+	OwnerID       primitive.ObjectID
+	AppointmentID primitive.ObjectID
+	Procedure     string
+	Channel       string
+	// RecordedBy is the staff member capturing a consent signed at the clinic
+	RecordedBy *primitive.ObjectID
+	Form       SignConsentDTO
+}
+
+// PublishTemplate stores the next version of a procedure's consent text. It
+// becomes the version presented for signing from now on; consents already
+// signed keep pointing at the version they were signed on.
+func (s *Service) PublishTemplate(ctx context.Context, dto *PublishTemplateDTO, tenantID, userID primitive.ObjectID) (*ConsentTemplate, error) {
+	version := 1
+	current, err := s.templates.FindLatest(ctx, tenantID, dto.Procedure)
+	switch {
+	case err == nil:
+		version = current.Version + 1
+	case !errors.Is(err, ErrTemplateNotFound):
+		return nil, err
+	}
+
+	template := &ConsentTemplate{
+		TenantID:  tenantID,
+		Procedure: dto.Procedure,
+		Version:   version,
+		Title:     dto.Title,
+		Text:      dto.Text,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.templates.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ListTemplates returns the current consent text of every procedure
+func (s *Service) ListTemplates(ctx context.Context, tenantID primitive.ObjectID) ([]ConsentTemplate, error) {
+	return s.templates.FindAllLatest(ctx, tenantID)
+}
+
+// TemplateVersions returns every version of a procedure's consent text, newest first
+func (s *Service) TemplateVersions(ctx context.Context, tenantID primitive.ObjectID, procedure string) ([]ConsentTemplate, error) {
+	versions, err := s.templates.FindVersions(ctx, tenantID, procedure)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+	return versions, nil
+}
+
+// CurrentTemplate returns the consent text presented for signing a procedure
+func (s *Service) CurrentTemplate(ctx context.Context, tenantID primitive.ObjectID, procedure string) (*ConsentTemplate, error) {
+	return s.templates.FindLatest(ctx, tenantID, procedure)
+}
+
+// Sign records a signed consent. The form must name the current version of
+// the text, so nobody signs a text other than the one stored.
+func (s *Service) Sign(ctx context.Context, req SignRequest) (*Consent, error) {
+	if req.Form.Signature.SizeBytes > MaxSignatureSizeBytes {
+		return nil, ErrSignatureTooLarge
+	}
+
+	template, err := s.templates.FindLatest(ctx, req.TenantID, req.Procedure)
+	if err != nil {
+		return nil, err
+	}
+	if template.Version != req.Form.TemplateVersion {
+		return nil, ErrTemplateOutdated
+	}
+
+	hash := sha256.Sum256([]byte(template.Text))
+	consent := &Consent{
+		TenantID:        req.TenantID,
+		PatientID:       req.PatientID,
+		OwnerID:         req.OwnerID,
+		AppointmentID:   req.AppointmentID,
+		Procedure:       req.Procedure,
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+		TextHash:        hex.EncodeToString(hash[:]),
+		SignedByName:    req.Form.SignedByName,
+		Signature: Signature{
+			FileID:      req.Form.Signature.FileID,
+			ContentType: req.Form.Signature.ContentType,
+			SizeBytes:   req.Form.Signature.SizeBytes,
+		},
+		Channel:    req.Channel,
+		RecordedBy: req.RecordedBy,
+		SignedAt:   time.Now(),
+	}
+	if err := s.repo.Create(ctx, consent); err != nil {
+		return nil, err
+	}
+	return consent, nil
+}
+
+// ForAppointment returns the latest consent signed for a procedure of an
+// appointment, or ErrConsentNotFound
+func (s *Service) ForAppointment(ctx context.Context, tenantID, appointmentID primitive.ObjectID, procedure string) (*Consent, error) {
+	return s.repo.FindLatestForAppointment(ctx, tenantID, appointmentID, procedure)
+}
+
+// Get returns a consent with the text that was signed
+func (s *Service) Get(ctx context.Context, id string, tenantID primitive.ObjectID) (*ConsentDetailResponse, error) {
+	consentID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid consent ID format")
+	}
+
+	consent, err := s.repo.FindByID(ctx, consentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.templates.FindByID(ctx, consent.TemplateID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsentDetailResponse{ConsentResponse: consent.ToResponse(), Template: template.ToResponse()}, nil
+}
+
+// List returns the clinic's signed consents, newest first
+func (s *Service) List(ctx context.Context, tenantID primitive.ObjectID, filters ConsentFilters, params pagination.Params) (*PaginatedConsentsResponse, error) {
+	filter := bson.M{}
+	if filters.PatientID != "" {
+		id, err := primitive.ObjectIDFromHex(filters.PatientID)
+		if err != nil {
+			return nil, ErrValidation("patient_id", "invalid patient ID format")
+		}
+		filter["patient_id"] = id
+	}
+	if filters.AppointmentID != "" {
+		id, err := primitive.ObjectIDFromHex(filters.AppointmentID)
+		if err != nil {
+			return nil, ErrValidation("appointment_id", "invalid appointment ID format")
+		}
+		filter["appointment_id"] = id
+	}
+	if filters.Procedure != "" {
+		filter["procedure"] = filters.Procedure
+	}
+
+	consents, total, err := s.repo.List(ctx, tenantID, filter, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]ConsentResponse, len(consents))
+	for i := range consents {
+		data[i] = consents[i].ToResponse()
+	}
+	return &PaginatedConsentsResponse{
+		Data:       data,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}, nil
+}
@@ -64,11 +64,11 @@ func Health(c *gin.Context) {
 
 // Ready godoc
 // @Summary Readiness check
-// @Description Check if the service is ready to accept traffic
+// @Description Check if the service is ready to accept traffic. The readiness checks are reported under checks; a degraded check, such as missing database indexes, does not take the service out of rotation
 // @Tags health
 // @Produce json
-// @Success 200 {object} map[string]string
-// @Failure 503 {object} map[string]string
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
 // @Router /ready [get]
 func Ready(c *gin.Context) {
 	if !IsReady() {
@@ -79,8 +79,20 @@ func Ready(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"status":  "ready",
 		"message": "Service is ready to accept traffic",
-	})
+	}
+	if healthService != nil && len(healthService.readiness) > 0 {
+		report := healthService.CheckReadiness(c.Request.Context())
+		resp["checks"] = report
+		if report.Status == HealthStatusUnhealthy {
+			resp["status"] = "not_ready"
+			resp["message"] = "A readiness check failed"
+			c.JSON(http.StatusServiceUnavailable, resp)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
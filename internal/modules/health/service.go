@@ -2,6 +2,7 @@ package health
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -19,6 +20,7 @@ type ComponentHealth struct {
 	Status  HealthStatus `json:"status"`
 	Latency int64        `json:"latency_ms,omitempty"`
 	Error   string       `json:"error,omitempty"`
+	Details any          `json:"details,omitempty"`
 }
 
 // HealthReport represents the overall health report
@@ -36,11 +38,24 @@ type HealthChecker interface {
 	Check(ctx context.Context) (int64, error)
 }
 
+// DegradedError is returned by a checker whose component works but not as
+// expected. It marks the component degraded rather than unhealthy, with
+// Details shown in the report.
+type DegradedError struct {
+	Reason  string
+	Details any
+}
+
+func (e *DegradedError) Error() string {
+	return e.Reason
+}
+
 // Service provides health checking functionality
 type Service struct {
-	checkers []HealthChecker
-	env      string
-	version  string
+	checkers  []HealthChecker
+	readiness []HealthChecker
+	env       string
+	version   string
 }
 
 // NewHealthService creates a new health service
@@ -57,8 +72,23 @@ func (s *Service) RegisterChecker(checker HealthChecker) {
 	s.checkers = append(s.checkers, checker)
 }
 
+// RegisterReadinessChecker registers a checker run only by the readiness
+// probe, for checks too costly to run on every health poll
+func (s *Service) RegisterReadinessChecker(checker HealthChecker) {
+	s.readiness = append(s.readiness, checker)
+}
+
 // Check performs health checks on all registered components
 func (s *Service) Check(ctx context.Context) *HealthReport {
+	return s.run(ctx, s.checkers)
+}
+
+// CheckReadiness performs the readiness checks
+func (s *Service) CheckReadiness(ctx context.Context) *HealthReport {
+	return s.run(ctx, s.readiness)
+}
+
+func (s *Service) run(ctx context.Context, checkers []HealthChecker) *HealthReport {
 	report := &HealthReport{
 		Status:      HealthStatusHealthy,
 		Timestamp:   time.Now(),
@@ -70,7 +100,7 @@ func (s *Service) Check(ctx context.Context) *HealthReport {
 	hasUnhealthy := false
 	hasDegraded := false
 
-	for _, checker := range s.checkers {
+	for _, checker := range checkers {
 		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		latency, err := checker.Check(checkCtx)
 		cancel()
@@ -79,7 +109,14 @@ func (s *Service) Check(ctx context.Context) *HealthReport {
 			Status: HealthStatusHealthy,
 		}
 
-		if err != nil {
+		var degraded *DegradedError
+		if errors.As(err, &degraded) {
+			component.Status = HealthStatusDegraded
+			component.Latency = latency
+			component.Error = degraded.Reason
+			component.Details = degraded.Details
+			hasDegraded = true
+		} else if err != nil {
 			component.Status = HealthStatusUnhealthy
 			component.Error = err.Error()
 			hasUnhealthy = true
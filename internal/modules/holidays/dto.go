@@ -0,0 +1,28 @@
+package holidays
+
+import "time"
+
+// CreateHolidayDTO represents the request to add a closed day
+type CreateHolidayDTO struct {
+	Date      string `json:"date" binding:"required,datetime=2006-01-02" example:"2025-12-25"`
+	Name      string `json:"name" binding:"required,min=2,max=100" example:"Navidad"`
+	Recurring bool   `json:"recurring" example:"true"`
+}
+
+// UpdateHolidayDTO represents the request to update a closed day
+type UpdateHolidayDTO struct {
+	Date      *string `json:"date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-12-25"`
+	Name      *string `json:"name,omitempty" binding:"omitempty,min=2,max=100" example:"Navidad"`
+	Recurring *bool   `json:"recurring,omitempty" example:"true"`
+}
+
+// HolidayResponse represents a holiday in API responses. For recurring
+// holidays only the month and day of Date are meaningful.
+type HolidayResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Date      string    `json:"date"`
+	Recurring bool      `json:"recurring"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
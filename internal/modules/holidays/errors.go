@@ -0,0 +1,16 @@
+package holidays
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrHolidayNotFound = sharedErrors.New(sharedErrors.ErrNotFound, "HOLIDAY_NOT_FOUND", "holiday not found")
+	ErrHolidayExists   = sharedErrors.New(sharedErrors.ErrConflict, "HOLIDAY_EXISTS", "a holiday is already configured for this date")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
@@ -0,0 +1,127 @@
+package holidays
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for the holiday calendar
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new holiday handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create adds a holiday
+// @Summary Create holiday
+// @Description Add a day the clinic is closed. Recurring holidays repeat on the same month and day every year
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param holiday body CreateHolidayDTO true "Holiday data"
+// @Success 200 {object} HolidayResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/holidays [post]
+func (h *Handler) Create(c *gin.Context) (any, error) {
+	var dto CreateHolidayDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	holiday, err := h.service.Create(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return holiday.ToResponse(), nil
+}
+
+// List lists holidays
+// @Summary List holidays
+// @Description List the clinic holiday calendar ordered by month and day
+// @Tags holidays
+// @Produce json
+// @Success 200 {array} HolidayResponse
+// @Security BearerAuth
+// @Router /api/holidays [get]
+func (h *Handler) List(c *gin.Context) (any, error) {
+	holidays, err := h.service.List(c.Request.Context(), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]HolidayResponse, len(holidays))
+	for i, holiday := range holidays {
+		data[i] = holiday.ToResponse()
+	}
+	return data, nil
+}
+
+// Get gets a holiday
+// @Summary Get holiday
+// @Description Get a holiday by ID
+// @Tags holidays
+// @Produce json
+// @Param id path string true "Holiday ID"
+// @Success 200 {object} HolidayResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/holidays/{id} [get]
+func (h *Handler) Get(c *gin.Context) (any, error) {
+	holiday, err := h.service.Get(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return holiday.ToResponse(), nil
+}
+
+// Update updates a holiday
+// @Summary Update holiday
+// @Description Update the date, name or recurrence of a holiday
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param id path string true "Holiday ID"
+// @Param holiday body UpdateHolidayDTO true "Fields to update"
+// @Success 200 {object} HolidayResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/holidays/{id} [put]
+func (h *Handler) Update(c *gin.Context) (any, error) {
+	var dto UpdateHolidayDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	holiday, err := h.service.Update(c.Request.Context(), c.Param("id"), &dto, sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return holiday.ToResponse(), nil
+}
+
+// Delete deletes a holiday
+// @Summary Delete holiday
+// @Description Remove a holiday from the calendar
+// @Tags holidays
+// @Param id path string true "Holiday ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/holidays/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) (any, error) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c)); err != nil {
+		return nil, err
+	}
+	return gin.H{"message": "Holiday deleted successfully"}, nil
+}
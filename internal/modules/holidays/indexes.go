@@ -0,0 +1,20 @@
+package holidays
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the holidays collection
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("holidays").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "month", Value: 1}, {Key: "day", Value: 1}, {Key: "deleted_at", Value: 1}},
+		},
+	})
+	return err
+}
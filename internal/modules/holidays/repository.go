@@ -0,0 +1,158 @@
+package holidays
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/platform/cache"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Repository defines the interface for holiday data access
+type Repository interface {
+	Create(ctx context.Context, h *Holiday) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Holiday, error)
+	FindAll(ctx context.Context, tenantID primitive.ObjectID) ([]Holiday, error)
+	FindOnDate(ctx context.Context, tenantID primitive.ObjectID, year, month, day int) (*Holiday, error)
+	Update(ctx context.Context, h *Holiday) error
+	Delete(ctx context.Context, id, tenantID primitive.ObjectID) error
+}
+
+// closedDayCache keeps the FindOnDate answer per tenant and day, including
+// "no holiday", since every booking checks it. Any calendar write drops the
+// tenant's entries.
+var closedDayCache = cache.NewReference[*Holiday]()
+
+func invalidateClosedDays(tenantID primitive.ObjectID) {
+	closedDayCache.DeletePrefix(cache.TenantKey(tenantID.Hex(), "holiday"))
+}
+
+type repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates a new holiday repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		collection: db.Collection("holidays"),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, h *Holiday) error {
+	result, err := r.collection.InsertOne(ctx, h)
+	invalidateClosedDays(h.TenantID)
+	if err != nil {
+		return err
+	}
+	h.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Holiday, error) {
+	var h Holiday
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil}).Decode(&h)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrHolidayNotFound
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *repository) FindAll(ctx context.Context, tenantID primitive.ObjectID) ([]Holiday, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "month", Value: 1}, {Key: "day", Value: 1}, {Key: "year", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID, "deleted_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []Holiday{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FindOnDate returns the holiday that closes the clinic on the given calendar
+// day, either a one-off holiday for that exact date or a recurring one.
+func (r *repository) FindOnDate(ctx context.Context, tenantID primitive.ObjectID, year, month, day int) (*Holiday, error) {
+	key := cache.TenantKey(tenantID.Hex(), "holiday", fmt.Sprintf("%04d-%02d-%02d", year, month, day))
+	h, err := closedDayCache.Load(key, func() (*Holiday, error) {
+		return r.findOnDate(ctx, tenantID, year, month, day)
+	})
+	if err != nil || h == nil {
+		return nil, err
+	}
+	found := *h
+	return &found, nil
+}
+
+func (r *repository) findOnDate(ctx context.Context, tenantID primitive.ObjectID, year, month, day int) (*Holiday, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+		"month":      month,
+		"day":        day,
+		"$or": bson.A{
+			bson.M{"recurring": true},
+			bson.M{"year": year},
+		},
+	}
+
+	var h Holiday
+	if err := r.collection.FindOne(ctx, filter).Decode(&h); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *repository) Update(ctx context.Context, h *Holiday) error {
+	h.UpdatedAt = time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": h.ID, "tenant_id": h.TenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{
+			"name":       h.Name,
+			"year":       h.Year,
+			"month":      h.Month,
+			"day":        h.Day,
+			"recurring":  h.Recurring,
+			"updated_at": h.UpdatedAt,
+		}},
+	)
+	invalidateClosedDays(h.TenantID)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}},
+	)
+	invalidateClosedDays(tenantID)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package holidays
+
+import (
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/holidays
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db)))
+
+	h := private.Group("/holidays")
+	h.POST("", handler.Create)
+	h.GET("", handler.List)
+	h.GET("/:id", handler.Get)
+	h.PUT("/:id", handler.Update)
+	h.DELETE("/:id", handler.Delete)
+}
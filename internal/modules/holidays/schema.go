@@ -0,0 +1,44 @@
+package holidays
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Holiday is a day the clinic is closed. Dates are stored as calendar
+// components rather than timestamps so they do not shift with time zones.
+// Recurring holidays ignore Year and repeat on the same month/day every year.
+type Holiday struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  primitive.ObjectID `bson:"tenant_id"`
+	Name      string             `bson:"name"`
+	Year      int                `bson:"year"`
+	Month     int                `bson:"month"`
+	Day       int                `bson:"day"`
+	Recurring bool               `bson:"recurring"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// DateString formats the holiday as YYYY-MM-DD, or MM-DD when recurring
+func (h *Holiday) DateString() string {
+	if h.Recurring {
+		return fmt.Sprintf("%02d-%02d", h.Month, h.Day)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", h.Year, h.Month, h.Day)
+}
+
+// ToResponse converts a holiday to its API representation
+func (h *Holiday) ToResponse() HolidayResponse {
+	return HolidayResponse{
+		ID:        h.ID.Hex(),
+		Name:      h.Name,
+		Date:      fmt.Sprintf("%04d-%02d-%02d", h.Year, h.Month, h.Day),
+		Recurring: h.Recurring,
+		CreatedAt: h.CreatedAt,
+		UpdatedAt: h.UpdatedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package holidays
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Service provides business logic for the clinic holiday calendar
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new holiday service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create adds a closed day to the clinic calendar
+func (s *Service) Create(ctx context.Context, dto *CreateHolidayDTO, tenantID primitive.ObjectID) (*Holiday, error) {
+	date, err := time.Parse("2006-01-02", dto.Date)
+	if err != nil {
+		return nil, ErrValidation("date", "invalid date format, expected YYYY-MM-DD")
+	}
+
+	now := time.Now()
+	h := &Holiday{
+		TenantID:  tenantID,
+		Name:      dto.Name,
+		Year:      date.Year(),
+		Month:     int(date.Month()),
+		Day:       date.Day(),
+		Recurring: dto.Recurring,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.ensureUnique(ctx, h); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// List returns every holiday of the clinic ordered by month and day
+func (s *Service) List(ctx context.Context, tenantID primitive.ObjectID) ([]Holiday, error) {
+	return s.repo.FindAll(ctx, tenantID)
+}
+
+// Get returns a holiday by ID
+func (s *Service) Get(ctx context.Context, id string, tenantID primitive.ObjectID) (*Holiday, error) {
+	holidayID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid holiday ID format")
+	}
+	return s.repo.FindByID(ctx, holidayID, tenantID)
+}
+
+// Update changes the date, name or recurrence of a holiday
+func (s *Service) Update(ctx context.Context, id string, dto *UpdateHolidayDTO, tenantID primitive.ObjectID) (*Holiday, error) {
+	h, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Date != nil {
+		date, err := time.Parse("2006-01-02", *dto.Date)
+		if err != nil {
+			return nil, ErrValidation("date", "invalid date format, expected YYYY-MM-DD")
+		}
+		h.Year, h.Month, h.Day = date.Year(), int(date.Month()), date.Day()
+	}
+	if dto.Name != nil {
+		h.Name = *dto.Name
+	}
+	if dto.Recurring != nil {
+		h.Recurring = *dto.Recurring
+	}
+	if err := s.ensureUnique(ctx, h); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Delete removes a holiday from the calendar
+func (s *Service) Delete(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	holidayID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrValidation("id", "invalid holiday ID format")
+	}
+	return s.repo.Delete(ctx, holidayID, tenantID)
+}
+
+// HolidayOn returns the holiday that closes the clinic on day, or nil when it is open
+func (s *Service) HolidayOn(ctx context.Context, tenantID primitive.ObjectID, day time.Time) (*Holiday, error) {
+	return s.repo.FindOnDate(ctx, tenantID, day.Year(), int(day.Month()), day.Day())
+}
+
+// ensureUnique rejects a second holiday on a day that is already closed
+func (s *Service) ensureUnique(ctx context.Context, h *Holiday) error {
+	existing, err := s.repo.FindOnDate(ctx, h.TenantID, h.Year, h.Month, h.Day)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != h.ID {
+		return ErrHolidayExists
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+)
+
+// adjustmentNeedsApproval reports whether a manual adjustment of this size
+// must wait for a second user under the clinic's settings. Other reasons are
+// always applied immediately.
+func (s *Service) adjustmentNeedsApproval(ctx context.Context, tenantID primitive.ObjectID, reason string, quantity int) (bool, error) {
+	if StockMovementReason(reason) != StockReasonAdjustment || s.tenantRepo == nil {
+		return false, nil
+	}
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		return false, err
+	}
+	return t.Settings.NeedsStockAdjustmentApproval(quantity), nil
+}
+
+// holdAdjustment records an adjustment as pending without touching the stock
+// and asks the staff to review it
+func (s *Service) holdAdjustment(ctx context.Context, product *Product, movementType StockMovementType, quantity int, referenceID primitive.ObjectID, notes string, userID primitive.ObjectID) (*StockMovement, error) {
+	movement := &StockMovement{
+		ID:          primitive.NewObjectID(),
+		TenantID:    product.TenantID,
+		ProductID:   product.ID,
+		Type:        movementType,
+		Reason:      StockReasonAdjustment,
+		Quantity:    quantity,
+		ReferenceID: referenceID,
+		UserID:      userID,
+		Notes:       notes,
+		CreatedAt:   time.Now(),
+		Status:      StockMovementPending,
+	}
+
+	if err := s.repo.CreateStockMovement(ctx, movement); err != nil {
+		return nil, err
+	}
+
+	direction := "entrada"
+	if movementType == StockMovementOut {
+		direction = "salida"
+	}
+
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   primitive.NilObjectID.Hex(), // Broadcast to all staff
+		TenantID: product.TenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    "Ajuste de inventario por aprobar - " + product.Name,
+		Body:     fmt.Sprintf("Se solicitó un ajuste de %s de %d unidades de %s. Otro usuario debe aprobarlo antes de aplicarlo al stock.", direction, quantity, product.Name),
+		Data: map[string]string{
+			"movement_id":  movement.ID.Hex(),
+			"product_id":   product.ID.Hex(),
+			"product_name": product.Name,
+			"type":         string(movementType),
+			"quantity":     strconv.Itoa(quantity),
+		},
+	})
+
+	return movement, nil
+}
+
+// ApproveStockMovement applies a pending adjustment to the product stock. The
+// approver must be a different user from the one who requested it.
+func (s *Service) ApproveStockMovement(ctx context.Context, id string, dto *ReviewStockMovementDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*StockMovement, error) {
+	movement, err := s.pendingMovement(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if movement.UserID == userID {
+		return nil, ErrSelfApproval
+	}
+
+	now := time.Now()
+
+	// Claim the movement first so a concurrent review cannot apply it twice
+	if err := s.repo.ReviewPendingMovement(ctx, movement.ID, tenantID, StockMovementApproved, userID, dto.Notes, now); err != nil {
+		return nil, err
+	}
+
+	var stockBefore, stockAfter int
+	if movement.Type == StockMovementOut {
+		stockBefore, stockAfter, err = s.repo.DecrementStock(ctx, movement.ProductID, movement.Quantity, tenantID)
+	} else {
+		var product *Product
+		if product, err = s.repo.FindByID(ctx, movement.ProductID, tenantID); err == nil {
+			stockBefore, stockAfter = product.Stock, product.Stock+movement.Quantity
+			err = s.repo.UpdateStock(ctx, movement.ProductID, movement.Quantity, tenantID)
+		}
+	}
+	if err != nil {
+		_ = s.repo.ReopenPendingMovement(ctx, movement.ID, tenantID)
+		return nil, err
+	}
+
+	if err := s.repo.SetMovementStock(ctx, movement.ID, tenantID, stockBefore, stockAfter); err != nil {
+		return nil, err
+	}
+
+	movement.Status = StockMovementApproved
+	movement.ReviewedBy = &userID
+	movement.ReviewedAt = &now
+	movement.ReviewNotes = dto.Notes
+	movement.StockBefore = stockBefore
+	movement.StockAfter = stockAfter
+
+	s.notifyReviewed(ctx, movement)
+
+	return movement, nil
+}
+
+// RejectStockMovement discards a pending adjustment; the stock is never touched.
+// The requester may reject their own adjustment to withdraw it.
+func (s *Service) RejectStockMovement(ctx context.Context, id string, dto *ReviewStockMovementDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*StockMovement, error) {
+	movement, err := s.pendingMovement(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.repo.ReviewPendingMovement(ctx, movement.ID, tenantID, StockMovementRejected, userID, dto.Notes, now); err != nil {
+		return nil, err
+	}
+
+	movement.Status = StockMovementRejected
+	movement.ReviewedBy = &userID
+	movement.ReviewedAt = &now
+	movement.ReviewNotes = dto.Notes
+
+	if movement.UserID != userID {
+		s.notifyReviewed(ctx, movement)
+	}
+
+	return movement, nil
+}
+
+// pendingMovement loads a movement that is still waiting for review
+func (s *Service) pendingMovement(ctx context.Context, id string, tenantID primitive.ObjectID) (*StockMovement, error) {
+	movementID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid stock movement ID format")
+	}
+
+	movement, err := s.repo.FindStockMovementByID(ctx, movementID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if movement.Status != StockMovementPending {
+		return nil, ErrMovementNotPending
+	}
+
+	return movement, nil
+}
+
+// notifyReviewed tells the requester what happened to their adjustment
+func (s *Service) notifyReviewed(ctx context.Context, movement *StockMovement) {
+	title, verb := "Ajuste de inventario aprobado", "aprobado y aplicado al stock"
+	if movement.Status == StockMovementRejected {
+		title, verb = "Ajuste de inventario rechazado", "rechazado"
+	}
+
+	body := fmt.Sprintf("Tu ajuste de %d unidades fue %s.", movement.Quantity, verb)
+	if movement.ReviewNotes != "" {
+		body += " Nota: " + movement.ReviewNotes
+	}
+
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   movement.UserID.Hex(),
+		TenantID: movement.TenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    title,
+		Body:     body,
+		Data: map[string]string{
+			"movement_id": movement.ID.Hex(),
+			"product_id":  movement.ProductID.Hex(),
+			"status":      string(movement.Status),
+		},
+	})
+}
@@ -2,24 +2,31 @@ package inventory
 
 import (
 	"time"
+
+	"github.com/eren_dev/go_server/internal/modules/tags"
+	"github.com/eren_dev/go_server/internal/shared/money"
 )
 
-// CreateProductDTO represents the request to create a product
+// CreateProductDTO represents the request to create a product. Prices are in
+// major units of the clinic currency (12500.50) and stored as minor units.
 type CreateProductDTO struct {
-	CategoryID     string  `json:"category_id"`
-	Name           string  `json:"name" binding:"required,min=3,max=100"`
-	Description    string  `json:"description" max:"500"`
-	SKU            string  `json:"sku" binding:"required,min=1,max=50"`
-	Barcode        string  `json:"barcode" max:"50"`
-	Category       string  `json:"category" binding:"required,oneof=medicine supply food equipment"`
-	Unit           string  `json:"unit" binding:"required,oneof=tablet ml piece kg gram box bottle"`
-	PurchasePrice  float64 `json:"purchase_price" binding:"required,min=0"`
-	SalePrice      float64 `json:"sale_price" binding:"required,min=0"`
-	Stock          int     `json:"stock" binding:"min=0"`
-	MinStock       int     `json:"min_stock" binding:"min=0"`
-	ExpirationDate string  `json:"expiration_date"` // RFC3339
-	SupplierID     string  `json:"supplier_id"`
-	Active         bool    `json:"active"`
+	CategoryID          string   `json:"category_id"`
+	Name                string   `json:"name" binding:"required,min=3,max=100"`
+	Description         string   `json:"description" max:"500"`
+	SKU                 string   `json:"sku" binding:"required,min=1,max=50"`
+	Barcode             string   `json:"barcode" max:"50"`
+	Category            string   `json:"category" binding:"required,oneof=medicine supply food equipment"`
+	Unit                string   `json:"unit" binding:"required,oneof=tablet ml piece kg gram box bottle"`
+	PurchasePrice       float64  `json:"purchase_price" binding:"required,min=0"`
+	SalePrice           float64  `json:"sale_price" binding:"required,min=0"`
+	Stock               int      `json:"stock" binding:"min=0"`
+	MinStock            int      `json:"min_stock" binding:"min=0"`
+	ExpirationDate      string   `json:"expiration_date"` // RFC3339
+	SupplierID          string   `json:"supplier_id"`
+	ReorderQuantity     int      `json:"reorder_quantity" binding:"min=0"`
+	PreferredSupplierID string   `json:"preferred_supplier_id"`
+	Tags                []string `json:"tags" example:"vaccine,cold-chain"`
+	Active              bool     `json:"active"`
 }
 
 // ParseExpirationDate parses the ExpirationDate string to time.Time
@@ -34,21 +41,26 @@ func (d *CreateProductDTO) ParseExpirationDate() (*time.Time, error) {
 	return &t, nil
 }
 
-// UpdateProductDTO represents the request to update a product
+// UpdateProductDTO represents the request to update a product. Prices are in
+// major units, like CreateProductDTO.
 type UpdateProductDTO struct {
-	CategoryID     string  `json:"category_id"`
-	Name           string  `json:"name" max:"100"`
-	Description    string  `json:"description" max:"500"`
-	SKU            string  `json:"sku" max:"50"`
-	Barcode        string  `json:"barcode" max:"50"`
-	Category       string  `json:"category" oneof=medicine supply food equipment"`
-	Unit           string  `json:"unit" oneof=tablet ml piece kg gram box bottle"`
-	PurchasePrice  float64 `json:"purchase_price" binding:"omitempty,min=0"`
-	SalePrice      float64 `json:"sale_price" binding:"omitempty,min=0"`
-	MinStock       int     `json:"min_stock" binding:"omitempty,min=0"`
-	ExpirationDate string  `json:"expiration_date"`
-	SupplierID     string  `json:"supplier_id"`
-	Active         bool    `json:"active"`
+	CategoryID          string  `json:"category_id"`
+	Name                string  `json:"name" max:"100"`
+	Description         string  `json:"description" max:"500"`
+	SKU                 string  `json:"sku" max:"50"`
+	Barcode             string  `json:"barcode" max:"50"`
+	Category            string  `json:"category" oneof=medicine supply food equipment"`
+	Unit                string  `json:"unit" oneof=tablet ml piece kg gram box bottle"`
+	PurchasePrice       float64 `json:"purchase_price" binding:"omitempty,min=0"`
+	SalePrice           float64 `json:"sale_price" binding:"omitempty,min=0"`
+	MinStock            int     `json:"min_stock" binding:"omitempty,min=0"`
+	ExpirationDate      string  `json:"expiration_date"`
+	SupplierID          string  `json:"supplier_id"`
+	ReorderQuantity     *int    `json:"reorder_quantity" binding:"omitempty,min=0"`
+	PreferredSupplierID string  `json:"preferred_supplier_id"`
+	Active              bool    `json:"active"`
+	// Tags replaces the product's tags when present; send [] to clear them
+	Tags []string `json:"tags"`
 }
 
 // StockInDTO represents the request to add stock
@@ -62,11 +74,21 @@ type StockInDTO struct {
 // StockOutDTO represents the request to deduct stock
 type StockOutDTO struct {
 	Quantity    int    `json:"quantity" binding:"required,min=1"`
-	Reason      string `json:"reason" binding:"required,oneof=sale treatment adjustment expired damaged lost"`
-	ReferenceID string `json:"reference_id"` // AppointmentID, OrderID, etc.
+	Reason      string `json:"reason" binding:"required,max=40"` // sale, treatment, adjustment, expired, damaged, lost or one of the clinic's own reasons
+	ReferenceID string `json:"reference_id"`                     // AppointmentID, OrderID, etc.
 	Notes       string `json:"notes" max:"500"`
 }
 
+// ReverseStockMovementDTO represents the request to reverse a stock movement
+type ReverseStockMovementDTO struct {
+	Notes string `json:"notes" binding:"max=500"` // Why the original movement was wrong
+}
+
+// ReviewStockMovementDTO represents the request to approve or reject a pending adjustment
+type ReviewStockMovementDTO struct {
+	Notes string `json:"notes" binding:"max=500"`
+}
+
 // CreateCategoryDTO represents the request to create a category
 type CreateCategoryDTO struct {
 	Name        string `json:"name" binding:"required,min=2,max=100"`
@@ -90,6 +112,7 @@ type ProductListFilters struct {
 	ExpiringSoon bool
 	Expired      bool
 	Search       string // Search by name, SKU, barcode
+	Tags         tags.Filter
 }
 
 // StockMovementListFilters represents filters for listing stock movements
@@ -100,6 +123,7 @@ type StockMovementListFilters struct {
 	DateFrom    string // RFC3339
 	DateTo      string // RFC3339
 	ReferenceID string
+	Status      string // pending, approved, rejected
 }
 
 // ProductAlertResponse represents a product alert in API responses
@@ -113,3 +137,26 @@ type ProductAlertResponse struct {
 	ExpirationDate  *time.Time `json:"expiration_date,omitempty"`
 	DaysUntilExpiry *int       `json:"days_until_expiry,omitempty"`
 }
+
+// ReorderSuggestionItem is a single low-stock product with its suggested order quantity
+type ReorderSuggestionItem struct {
+	ProductID         string       `json:"product_id"`
+	ProductName       string       `json:"product_name"`
+	SKU               string       `json:"sku"`
+	Unit              string       `json:"unit"`
+	CurrentStock      int          `json:"current_stock"`
+	MinStock          int          `json:"min_stock"`
+	ReorderQuantity   int          `json:"reorder_quantity"`
+	TargetStock       int          `json:"target_stock"`
+	AvgDailyUsage     float64      `json:"avg_daily_usage"`
+	SuggestedQuantity int          `json:"suggested_quantity"`
+	EstimatedCost     money.Amount `json:"estimated_cost"`
+}
+
+// ReorderSuggestionGroup groups reorder suggestions by supplier and currency
+type ReorderSuggestionGroup struct {
+	SupplierID    string                  `json:"supplier_id,omitempty"` // Empty for products without supplier
+	Items         []ReorderSuggestionItem `json:"items"`
+	TotalQuantity int                     `json:"total_quantity"`
+	EstimatedCost money.Amount            `json:"estimated_cost"`
+}
@@ -1,64 +1,45 @@
 package inventory
 
 import (
-	"errors"
-	"fmt"
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
 )
 
 // Module errors
 var (
-	ErrProductNotFound       = errors.New("product not found")
-	ErrCategoryNotFound      = errors.New("category not found")
-	ErrCategoryNameExists    = errors.New("category name already exists")
-	ErrSKUAlreadyExists      = errors.New("SKU already exists")
-	ErrBarcodeAlreadyExists  = errors.New("barcode already exists")
-	ErrInsufficientStock     = errors.New("insufficient stock")
-	ErrInvalidStockMovement  = errors.New("invalid stock movement")
-	ErrCannotDeleteWithStock = errors.New("cannot delete product with stock > 0")
-	ErrInvalidCategory       = errors.New("invalid category")
-	ErrInvalidUnit           = errors.New("invalid unit")
-	ErrInvalidPrice          = errors.New("invalid price: must be >= 0")
-	ErrInvalidQuantity       = errors.New("invalid quantity: must be > 0")
-	ErrSalePriceTooLow       = errors.New("sale price must be >= purchase price")
+	ErrProductNotFound         = sharedErrors.New(sharedErrors.ErrNotFound, "PRODUCT_NOT_FOUND", "product not found")
+	ErrCategoryNotFound        = sharedErrors.New(sharedErrors.ErrNotFound, "CATEGORY_NOT_FOUND", "category not found")
+	ErrCategoryNameExists      = sharedErrors.New(sharedErrors.ErrConflict, "CATEGORY_NAME_EXISTS", "category name already exists")
+	ErrSKUAlreadyExists        = sharedErrors.New(sharedErrors.ErrConflict, "SKU_ALREADY_EXISTS", "SKU already exists")
+	ErrBarcodeAlreadyExists    = sharedErrors.New(sharedErrors.ErrConflict, "BARCODE_ALREADY_EXISTS", "barcode already exists")
+	ErrInsufficientStock       = sharedErrors.New(sharedErrors.ErrConflict, "INSUFFICIENT_STOCK", "insufficient stock")
+	ErrInvalidStockMovement    = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_STOCK_MOVEMENT", "invalid stock movement")
+	ErrCannotDeleteWithStock   = sharedErrors.New(sharedErrors.ErrConflict, "PRODUCT_HAS_STOCK", "cannot delete product with stock > 0")
+	ErrInvalidCategory         = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_CATEGORY", "invalid category")
+	ErrInvalidUnit             = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_UNIT", "invalid unit")
+	ErrInvalidPrice            = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_PRICE", "invalid price: must be >= 0")
+	ErrInvalidQuantity         = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_QUANTITY", "invalid quantity: must be > 0")
+	ErrSalePriceTooLow         = sharedErrors.New(sharedErrors.ErrInvalidInput, "SALE_PRICE_TOO_LOW", "sale price must be >= purchase price")
+	ErrStockMovementNotFound   = sharedErrors.New(sharedErrors.ErrNotFound, "STOCK_MOVEMENT_NOT_FOUND", "stock movement not found")
+	ErrMovementAlreadyReversed = sharedErrors.New(sharedErrors.ErrConflict, "MOVEMENT_ALREADY_REVERSED", "stock movement already reversed")
+	ErrMovementNotPending      = sharedErrors.New(sharedErrors.ErrConflict, "MOVEMENT_NOT_PENDING", "stock movement is not pending approval")
 )
 
-// ValidationError represents a validation error
-type ValidationError struct {
-	Field   string
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
-}
-
 // ErrValidation creates a new validation error
 func ErrValidation(field, message string) error {
-	return &ValidationError{
-		Field:   field,
-		Message: message,
-	}
-}
-
-// BusinessError represents a business logic error
-type BusinessError struct {
-	Code    string
-	Message string
-}
-
-func (e *BusinessError) Error() string {
-	return fmt.Sprintf("business error: %s - %s", e.Code, e.Message)
+	return sharedErrors.Validation(field, message)
 }
 
 // ErrBusiness creates a new business error
 func ErrBusiness(code, message string) error {
-	return &BusinessError{
-		Code:    code,
-		Message: message,
-	}
+	return sharedErrors.Business(code, message)
 }
 
 // Specific business errors
 var (
-	ErrProductExpired = ErrBusiness("PRODUCT_EXPIRED", "product has expired")
+	ErrProductExpired        = ErrBusiness("PRODUCT_EXPIRED", "product has expired")
+	ErrMovementNotReversible = ErrBusiness("MOVEMENT_NOT_REVERSIBLE", "only stock-in and stock-out movements can be reversed")
+	ErrCannotReverseReversal = ErrBusiness("CANNOT_REVERSE_REVERSAL", "a reversal movement cannot itself be reversed")
+	ErrReversalWindowExpired = ErrBusiness("REVERSAL_WINDOW_EXPIRED", "stock movement is too old to be reversed")
+	ErrMovementNotApplied    = ErrBusiness("MOVEMENT_NOT_APPLIED", "a pending or rejected stock movement cannot be reversed")
+	ErrSelfApproval          = ErrBusiness("SELF_APPROVAL", "a stock adjustment must be approved by a different user")
 )
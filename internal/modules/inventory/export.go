@@ -0,0 +1,41 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/export"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// productExportColumns are the columns of the product export. Prices are in
+// major units of the product's currency.
+var productExportColumns = []export.Column[Product]{
+	{Key: "sku", Value: func(p *Product) any { return p.SKU }},
+	{Key: "barcode", Value: func(p *Product) any { return p.Barcode }},
+	{Key: "name", Value: func(p *Product) any { return p.Name }},
+	{Key: "category", Value: func(p *Product) any { return string(p.Category) }},
+	{Key: "unit", Value: func(p *Product) any { return string(p.Unit) }},
+	{Key: "stock", Value: func(p *Product) any { return p.Stock }},
+	{Key: "min_stock", Value: func(p *Product) any { return p.MinStock }},
+	{Key: "purchase_price", Value: func(p *Product) any { return money.ToMajor(p.PurchasePrice, p.Currency) }},
+	{Key: "sale_price", Value: func(p *Product) any { return money.ToMajor(p.SalePrice, p.Currency) }},
+	{Key: "currency", Value: func(p *Product) any { return p.Currency }},
+	{Key: "expiration_date", Value: func(p *Product) any { return p.ExpirationDate }},
+	{Key: "tags", Value: func(p *Product) any { return strings.Join(p.Tags, ",") }},
+	{Key: "active", Value: func(p *Product) any { return p.Active }},
+}
+
+// ExportProducts returns an export of the products matching the list
+// filters, by name
+func (s *Service) ExportProducts(ctx context.Context, filters ProductListFilters, tenantID primitive.ObjectID) *export.Export[Product] {
+	return &export.Export[Product]{
+		Name:    "productos",
+		Columns: productExportColumns,
+		Source: func(emit func(*Product) error) error {
+			return s.repo.ForEachByFilters(ctx, tenantID, filters, emit)
+		},
+	}
+}
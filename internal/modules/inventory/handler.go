@@ -1,10 +1,16 @@
 package inventory
 
 import (
+	"errors"
+	"io"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/modules/tags"
 	"github.com/eren_dev/go_server/internal/shared/auth"
+	"github.com/eren_dev/go_server/internal/shared/export"
 	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"github.com/eren_dev/go_server/internal/shared/validation"
@@ -94,6 +100,9 @@ func (h *Handler) GetProduct(c *gin.Context) (any, error) {
 // @Param expiring query bool false "Filter expiring products"
 // @Param expired query bool false "Filter expired products"
 // @Param search query string false "Search by name, SKU, barcode"
+// @Param tags query string false "Comma-separated tags"
+// @Param tags_mode query string false "any (default) or all"
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending (name, sku, category, stock, expiration_date)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Security BearerAuth
@@ -102,17 +111,9 @@ func (h *Handler) ListProducts(c *gin.Context) (any, error) {
 	params := pagination.FromContext(c)
 	tenantID := sharedMiddleware.GetTenantID(c)
 
-	filters := ProductListFilters{
-		Category:     c.Query("category"),
-		Search:       c.Query("search"),
-		LowStock:     c.Query("low_stock") == "true",
-		ExpiringSoon: c.Query("expiring") == "true",
-		Expired:      c.Query("expired") == "true",
-	}
-
-	if active := c.Query("active"); active != "" {
-		activeBool := active == "true"
-		filters.Active = &activeBool
+	filters, err := productListFilters(c)
+	if err != nil {
+		return nil, err
 	}
 
 	products, total, err := h.service.ListProducts(c.Request.Context(), filters, tenantID, params)
@@ -136,6 +137,59 @@ func (h *Handler) ListProducts(c *gin.Context) (any, error) {
 	}, nil
 }
 
+// ExportProducts streams the filtered product list as CSV or JSON lines
+// @Summary Export products
+// @Description Streams the products matching the list filters as a UTF-8 CSV (with BOM) for spreadsheets, or as JSON lines
+// @Tags inventory
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param category query string false "Filter by category"
+// @Param active query bool false "Filter by active status"
+// @Param low_stock query bool false "Filter low stock products"
+// @Param expiring query bool false "Filter expiring products"
+// @Param expired query bool false "Filter expired products"
+// @Param search query string false "Search by name, SKU, barcode"
+// @Param tags query string false "Comma-separated tags"
+// @Param tags_mode query string false "any (default) or all"
+// @Param format query string false "Export format, overrides the Accept header" Enums(csv, jsonl)
+// @Param columns query string false "Comma separated columns to include, in order (sku,barcode,name,category,unit,stock,min_stock,purchase_price,sale_price,currency,expiration_date,tags,active)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/products/export [get]
+func (h *Handler) ExportProducts(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	filters, err := productListFilters(c)
+	if err != nil {
+		return nil, err
+	}
+	return export.Respond(c, h.service.ExportProducts(c.Request.Context(), filters, tenantID))
+}
+
+// productListFilters reads the product list filters from the query string
+func productListFilters(c *gin.Context) (ProductListFilters, error) {
+	filters := ProductListFilters{
+		Category:     c.Query("category"),
+		Search:       c.Query("search"),
+		LowStock:     c.Query("low_stock") == "true",
+		ExpiringSoon: c.Query("expiring") == "true",
+		Expired:      c.Query("expired") == "true",
+	}
+
+	if active := c.Query("active"); active != "" {
+		activeBool := active == "true"
+		filters.Active = &activeBool
+	}
+
+	tagFilter, err := tags.FromQuery(c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Tags = tagFilter
+	return filters, nil
+}
+
 // UpdateProduct updates a product
 // @Summary Update product
 // @Description Update product details
@@ -276,6 +330,128 @@ func (h *Handler) StockOut(c *gin.Context) (any, error) {
 	return movement.ToResponse(), nil
 }
 
+// ReverseStockMovement reverses a stock movement
+// @Summary Reverse stock movement
+// @Description Record a compensating movement that undoes a mistyped stock-in or stock-out and restores the product stock. Only allowed within the configured reversal window.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Stock movement ID"
+// @Param body body ReverseStockMovementDTO false "Reversal notes"
+// @Success 200 {object} StockMovementResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/stock-movements/{id}/reverse [post]
+func (h *Handler) ReverseStockMovement(c *gin.Context) (any, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, ErrValidation("id", "stock movement ID is required")
+	}
+
+	// The body is optional: notes only
+	var dto ReverseStockMovementDTO
+	if err := c.ShouldBindJSON(&dto); err != nil && !errors.Is(err, io.EOF) {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userIDStr := auth.GetUserID(c)
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	movement, err := h.service.ReverseStockMovement(c.Request.Context(), id, &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return movement.ToResponse(), nil
+}
+
+// ApproveStockMovement approves a pending stock adjustment
+// @Summary Approve stock adjustment
+// @Description Apply an adjustment that exceeded the clinic's approval threshold. It must be approved by a different user from the one who recorded it.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Stock movement ID"
+// @Param body body ReviewStockMovementDTO false "Review notes"
+// @Success 200 {object} StockMovementResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/stock-movements/{id}/approve [post]
+func (h *Handler) ApproveStockMovement(c *gin.Context) (any, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, ErrValidation("id", "stock movement ID is required")
+	}
+
+	// The body is optional: notes only
+	var dto ReviewStockMovementDTO
+	if err := c.ShouldBindJSON(&dto); err != nil && !errors.Is(err, io.EOF) {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userIDStr := auth.GetUserID(c)
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	movement, err := h.service.ApproveStockMovement(c.Request.Context(), id, &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return movement.ToResponse(), nil
+}
+
+// RejectStockMovement rejects a pending stock adjustment
+// @Summary Reject stock adjustment
+// @Description Discard an adjustment that is pending approval without changing the product stock.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Stock movement ID"
+// @Param body body ReviewStockMovementDTO false "Review notes"
+// @Success 200 {object} StockMovementResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/stock-movements/{id}/reject [post]
+func (h *Handler) RejectStockMovement(c *gin.Context) (any, error) {
+	id := c.Param("id")
+	if id == "" {
+		return nil, ErrValidation("id", "stock movement ID is required")
+	}
+
+	// The body is optional: notes only
+	var dto ReviewStockMovementDTO
+	if err := c.ShouldBindJSON(&dto); err != nil && !errors.Is(err, io.EOF) {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userIDStr := auth.GetUserID(c)
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	movement, err := h.service.RejectStockMovement(c.Request.Context(), id, &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return movement.ToResponse(), nil
+}
+
 // GetStockMovements lists stock movements
 // @Summary List stock movements
 // @Description Get a paginated list of stock movements
@@ -289,6 +465,7 @@ func (h *Handler) StockOut(c *gin.Context) (any, error) {
 // @Param reason query string false "Filter by reason"
 // @Param date_from query string false "Filter from date (RFC3339)"
 // @Param date_to query string false "Filter to date (RFC3339)"
+// @Param status query string false "Filter by approval status (pending, approved, rejected)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Security BearerAuth
@@ -304,6 +481,7 @@ func (h *Handler) GetStockMovements(c *gin.Context) (any, error) {
 		DateFrom:    c.Query("date_from"),
 		DateTo:      c.Query("date_to"),
 		ReferenceID: c.Query("reference_id"),
+		Status:      c.Query("status"),
 	}
 
 	movements, total, err := h.service.GetStockMovements(c.Request.Context(), filters, tenantID, params)
@@ -377,6 +555,68 @@ func (h *Handler) GetExpiringProducts(c *gin.Context) (any, error) {
 	return gin.H{"data": data}, nil
 }
 
+// GetReorderSuggestions gets low-stock products grouped by supplier with suggested order quantities
+// @Summary Get reorder suggestions
+// @Description Get low-stock products grouped by supplier, with the quantity needed to reach the reorder target refined by recent usage
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param usage_days query int false "Days of stock-out history used to compute average daily usage (default 30)"
+// @Param coverage_days query int false "Days of projected usage the restock should cover (default 30)"
+// @Success 200 {object} []ReorderSuggestionGroup
+// @Security BearerAuth
+// @Router /api/products/reorder-suggestions [get]
+func (h *Handler) GetReorderSuggestions(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	usageDays, err := strconv.Atoi(c.DefaultQuery("usage_days", strconv.Itoa(defaultUsageDays)))
+	if err != nil || usageDays < 1 || usageDays > 365 {
+		return nil, ErrValidation("usage_days", "must be a number between 1 and 365")
+	}
+	coverageDays, err := strconv.Atoi(c.DefaultQuery("coverage_days", strconv.Itoa(defaultCoverageDays)))
+	if err != nil || coverageDays < 0 || coverageDays > 365 {
+		return nil, ErrValidation("coverage_days", "must be a number between 0 and 365")
+	}
+
+	groups, err := h.service.GetReorderSuggestions(c.Request.Context(), tenantID, usageDays, coverageDays)
+	if err != nil {
+		return nil, err
+	}
+
+	return gin.H{"data": groups}, nil
+}
+
+// GetExpiryWriteOffs lists automatic expiry write-offs
+// @Summary List expiry write-offs
+// @Description Get a paginated report of products automatically written off because they expired
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip"
+// @Param limit query int false "Limit"
+// @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/products/expiry-writeoffs [get]
+func (h *Handler) GetExpiryWriteOffs(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	writeOffs, total, err := h.service.ListExpiryWriteOffs(c.Request.Context(), tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]ExpiryWriteOffResponse, len(writeOffs))
+	for i, w := range writeOffs {
+		data[i] = *w.ToResponse()
+	}
+
+	return gin.H{
+		"data":       data,
+		"pagination": pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
 // GetProductAlerts gets all product alerts
 // @Summary Get product alerts
 // @Description Get all product alerts (low stock, expiring, expired)
@@ -38,6 +38,10 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		{
 			Keys: bson.D{{"active", 1}},
 		},
+		{
+			// Tag filters on the list and tag autocomplete
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "tags", Value: 1}},
+		},
 		{
 			Keys: bson.D{{"stock", 1}, {"min_stock", 1}},
 		},
@@ -78,6 +82,10 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		{
 			Keys: bson.D{{"type", 1}, {"created_at", -1}},
 		},
+		{
+			// Adjustments waiting for approval
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
 	}
 
 	movementsCollection := db.Collection("stock_movements")
@@ -86,5 +94,18 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		return err
 	}
 
+	// Expiry write-offs indexes
+	writeOffsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	writeOffsCollection := db.Collection("expiry_writeoffs")
+	_, err = writeOffsCollection.Indexes().CreateMany(ctx, writeOffsIndexes, opts)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// MinorUnitsMigration reports what MigrateToMinorUnits converted
+type MinorUnitsMigration struct {
+	Tenants   int
+	Products  int64
+	WriteOffs int64
+}
+
+// MigrateToMinorUnits converts product prices and expiry write-off values
+// stored as decimal floats into integer minor units of each clinic's
+// currency. Only fields still stored as doubles are touched, so it is safe
+// to run more than once. It must run before deploying code that reads
+// prices as integers.
+func MigrateToMinorUnits(ctx context.Context, db *database.MongoDB) (*MinorUnitsMigration, error) {
+	cursor, err := db.Collection("tenants").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var tenants []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Currency string             `bson:"currency"`
+	}
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, err
+	}
+
+	products := db.Collection("products")
+	writeOffs := db.Collection("expiry_writeoffs")
+	result := &MinorUnitsMigration{Tenants: len(tenants)}
+
+	for _, t := range tenants {
+		currency := money.Normalize(t.Currency)
+		factor := money.Factor(currency)
+
+		for _, field := range []string{"purchase_price", "sale_price"} {
+			res, err := products.UpdateMany(ctx,
+				bson.M{"tenant_id": t.ID, field: bson.M{"$type": "double"}},
+				mongo.Pipeline{{{Key: "$set", Value: bson.M{
+					field:      money.MinorUnitsExpr("$"+field, factor),
+					"currency": bson.M{"$ifNull": bson.A{"$currency", currency}},
+				}}}},
+			)
+			if err != nil {
+				return result, fmt.Errorf("tenant %s %s: %w", t.ID.Hex(), field, err)
+			}
+			result.Products += res.ModifiedCount
+		}
+
+		res, err := writeOffs.UpdateMany(ctx,
+			bson.M{"tenant_id": t.ID, "total_value": bson.M{"$type": "double"}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{
+				"total_value": money.MinorUnitsExpr("$total_value", factor),
+				"currency":    currency,
+				"items": bson.M{"$map": bson.M{
+					"input": "$items",
+					"as":    "item",
+					"in": bson.M{"$mergeObjects": bson.A{"$$item", bson.M{
+						"unit_cost": money.MinorUnitsExpr("$$item.unit_cost", factor),
+						"value":     money.MinorUnitsExpr("$$item.value", factor),
+					}}},
+				}},
+			}}}},
+		)
+		if err != nil {
+			return result, fmt.Errorf("tenant %s write-offs: %w", t.ID.Hex(), err)
+		}
+		result.WriteOffs += res.ModifiedCount
+	}
+
+	return result, nil
+}
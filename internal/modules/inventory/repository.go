@@ -21,11 +21,15 @@ type ProductRepository interface {
 	FindBySKU(ctx context.Context, sku string, tenantID primitive.ObjectID) (*Product, error)
 	FindByBarcode(ctx context.Context, barcode string, tenantID primitive.ObjectID) (*Product, error)
 	FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters ProductListFilters, params pagination.Params) ([]Product, int64, error)
+	// ForEachByFilters calls fn for every product matching filters, by name,
+	// without loading them all in memory
+	ForEachByFilters(ctx context.Context, tenantID primitive.ObjectID, filters ProductListFilters, fn func(*Product) error) error
 	Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
 	Delete(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
 
 	// Stock operations
 	UpdateStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) error
+	DecrementStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) (before int, after int, err error)
 
 	// Alerts
 	FindLowStockProducts(ctx context.Context, tenantID primitive.ObjectID) ([]Product, error)
@@ -35,6 +39,17 @@ type ProductRepository interface {
 	// Stock Movement
 	CreateStockMovement(ctx context.Context, movement *StockMovement) error
 	FindStockMovements(ctx context.Context, filters StockMovementListFilters, tenantID primitive.ObjectID, params pagination.Params) ([]StockMovement, int64, error)
+	FindStockMovementByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*StockMovement, error)
+	MarkMovementReversed(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, reversalID primitive.ObjectID, userID primitive.ObjectID, at time.Time) error
+	ClearMovementReversal(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
+	ReviewPendingMovement(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, status StockMovementStatus, userID primitive.ObjectID, notes string, at time.Time) error
+	ReopenPendingMovement(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
+	SetMovementStock(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, before int, after int) error
+	SumStockOutSince(ctx context.Context, tenantID primitive.ObjectID, productIDs []primitive.ObjectID, since time.Time) (map[primitive.ObjectID]int, error)
+
+	// Expiry write-offs
+	CreateExpiryWriteOff(ctx context.Context, writeOff *ExpiryWriteOff) error
+	FindExpiryWriteOffs(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]ExpiryWriteOff, int64, error)
 
 	// Category CRUD
 	CreateCategory(ctx context.Context, category *Category) error
@@ -48,17 +63,26 @@ type ProductRepository interface {
 }
 
 type productRepository struct {
+	products   *database.BaseRepository[Product]
+	categories *database.BaseRepository[Category]
+
 	productsCollection  *mongo.Collection
 	categoriesCollection *mongo.Collection
 	movementsCollection *mongo.Collection
+	writeOffsCollection *mongo.Collection
 }
 
 // NewProductRepository creates a new product repository
 func NewProductRepository(db *database.MongoDB) ProductRepository {
+	products := db.Collection("products")
+	categories := db.Collection("product_categories")
 	return &productRepository{
-		productsCollection:   db.Collection("products"),
-		categoriesCollection: db.Collection("product_categories"),
+		products:             database.NewBaseRepository[Product](products, ErrProductNotFound).WithDuplicate(ErrSKUAlreadyExists),
+		categories:           database.NewBaseRepository[Category](categories, ErrCategoryNotFound).WithDuplicate(ErrCategoryNameExists),
+		productsCollection:   products,
+		categoriesCollection: categories,
 		movementsCollection:  db.Collection("stock_movements"),
+		writeOffsCollection:  db.Collection("expiry_writeoffs"),
 	}
 }
 
@@ -77,63 +101,59 @@ func (r *productRepository) Create(ctx context.Context, product *Product) error
 }
 
 func (r *productRepository) FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Product, error) {
-	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
+	return r.products.FindByID(ctx, id, tenantID)
+}
 
-	var product Product
-	err := r.productsCollection.FindOne(ctx, filter).Decode(&product)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, ErrProductNotFound
-		}
-		return nil, err
-	}
+func (r *productRepository) FindBySKU(ctx context.Context, sku string, tenantID primitive.ObjectID) (*Product, error) {
+	return r.products.FindOne(ctx, tenantID, bson.M{"sku": sku})
+}
 
-	return &product, nil
+func (r *productRepository) FindByBarcode(ctx context.Context, barcode string, tenantID primitive.ObjectID) (*Product, error) {
+	return r.products.FindOne(ctx, tenantID, bson.M{"barcode": barcode})
 }
 
-func (r *productRepository) FindBySKU(ctx context.Context, sku string, tenantID primitive.ObjectID) (*Product, error) {
-	filter := bson.M{
-		"sku":        sku,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
+// productSortFields are the fields the product list accepts in ?sort=
+var productSortFields = []string{"name", "sku", "category", "stock", "expiration_date"}
 
-	var product Product
-	err := r.productsCollection.FindOne(ctx, filter).Decode(&product)
+func (r *productRepository) FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters ProductListFilters, params pagination.Params) ([]Product, int64, error) {
+	filter := productListFilter(tenantID, filters)
+
+	sort, err := params.SortOrder(productSortFields, bson.D{{Key: "name", Value: 1}})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, ErrProductNotFound
-		}
-		return nil, err
+		return nil, 0, err
 	}
 
-	return &product, nil
+	// Set pagination options
+	opts := options.Find().
+		SetSkip(int64(params.Skip)).
+		SetLimit(int64(params.Limit)).
+		SetSort(sort)
+
+	return r.products.Page(ctx, tenantID, filter, opts)
 }
 
-func (r *productRepository) FindByBarcode(ctx context.Context, barcode string, tenantID primitive.ObjectID) (*Product, error) {
-	filter := bson.M{
-		"barcode":    barcode,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
+func (r *productRepository) ForEachByFilters(ctx context.Context, tenantID primitive.ObjectID, filters ProductListFilters, fn func(*Product) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.products.Collection.Find(ctx, productListFilter(tenantID, filters), opts)
+	if err != nil {
+		return err
 	}
+	defer cursor.Close(ctx)
 
-	var product Product
-	err := r.productsCollection.FindOne(ctx, filter).Decode(&product)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, ErrProductNotFound
+	for cursor.Next(ctx) {
+		var product Product
+		if err := cursor.Decode(&product); err != nil {
+			return err
+		}
+		if err := fn(&product); err != nil {
+			return err
 		}
-		return nil, err
 	}
-
-	return &product, nil
+	return cursor.Err()
 }
 
-func (r *productRepository) FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters ProductListFilters, params pagination.Params) ([]Product, int64, error) {
+// productListFilter builds the query of the product list filters
+func productListFilter(tenantID primitive.ObjectID, filters ProductListFilters) bson.M {
 	filter := bson.M{
 		"tenant_id":  tenantID,
 		"deleted_at": nil,
@@ -174,6 +194,8 @@ func (r *productRepository) FindByFilters(ctx context.Context, tenantID primitiv
 		filter["expiration_date"] = bson.M{"$lt": time.Now()}
 	}
 
+	filters.Tags.Apply(filter)
+
 	// Search filter
 	if filters.Search != "" {
 		filter["$or"] = []bson.M{
@@ -183,54 +205,11 @@ func (r *productRepository) FindByFilters(ctx context.Context, tenantID primitiv
 		}
 	}
 
-	// Count total
-	total, err := r.productsCollection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Set pagination options
-	opts := options.Find().
-		SetSkip(int64(params.Skip)).
-		SetLimit(int64(params.Limit)).
-		SetSort(bson.D{{"name", 1}})
-
-	cursor, err := r.productsCollection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-
-	var products []Product
-	if err := cursor.All(ctx, &products); err != nil {
-		return nil, 0, err
-	}
-
-	return products, total, nil
+	return filter
 }
 
 func (r *productRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
-	updates["updated_at"] = time.Now()
-
-	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}
-
-	result, err := r.productsCollection.UpdateOne(ctx, filter, bson.M{"$set": updates})
-	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return ErrSKUAlreadyExists
-		}
-		return err
-	}
-
-	if result.MatchedCount == 0 {
-		return ErrProductNotFound
-	}
-
-	return nil
+	return r.products.Update(ctx, id, tenantID, updates)
 }
 
 func (r *productRepository) Delete(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
@@ -244,28 +223,7 @@ func (r *productRepository) Delete(ctx context.Context, id primitive.ObjectID, t
 		return ErrCannotDeleteWithStock
 	}
 
-	filter := bson.M{
-		"_id":       id,
-		"tenant_id": tenantID,
-	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"deleted_at": time.Now(),
-			"updated_at": time.Now(),
-		},
-	}
-
-	result, err := r.productsCollection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
-	}
-
-	if result.MatchedCount == 0 {
-		return ErrProductNotFound
-	}
-
-	return nil
+	return r.products.SoftDelete(ctx, id, tenantID)
 }
 
 func (r *productRepository) UpdateStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) error {
@@ -292,72 +250,71 @@ func (r *productRepository) UpdateStock(ctx context.Context, id primitive.Object
 	return nil
 }
 
-func (r *productRepository) FindLowStockProducts(ctx context.Context, tenantID primitive.ObjectID) ([]Product, error) {
+// DecrementStock deducts quantity only if the product has at least that much
+// in stock, in a single update, so concurrent stock-outs cannot oversell. It
+// returns the stock before and after the deduction as written.
+func (r *productRepository) DecrementStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) (int, int, error) {
+	filter, update := decrementStockQuery(id, quantity, tenantID, time.Now())
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var product Product
+	err := r.productsCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// Either the product is gone or the guard failed
+			if _, findErr := r.FindByID(ctx, id, tenantID); findErr != nil {
+				return 0, 0, findErr
+			}
+			return 0, 0, ErrInsufficientStock
+		}
+		return 0, 0, err
+	}
+
+	return product.Stock + quantity, product.Stock, nil
+}
+
+// decrementStockQuery builds the guarded decrement: the filter only matches
+// while the product still has quantity units, so the check and the write are
+// a single atomic update
+func decrementStockQuery(id primitive.ObjectID, quantity int, tenantID primitive.ObjectID, now time.Time) (bson.M, bson.M) {
 	filter := bson.M{
+		"_id":        id,
 		"tenant_id":  tenantID,
 		"deleted_at": nil,
-		"active":     true,
-		"$expr":      bson.M{"$lte": []string{"$stock", "$min_stock"}},
+		"stock":      bson.M{"$gte": quantity},
 	}
 
-	cursor, err := r.productsCollection.Find(ctx, filter)
-	if err != nil {
-		return nil, err
+	update := bson.M{
+		"$inc": bson.M{"stock": -quantity},
+		"$set": bson.M{"updated_at": now},
 	}
-	defer cursor.Close(ctx)
+	return filter, update
+}
 
-	var products []Product
-	if err := cursor.All(ctx, &products); err != nil {
-		return nil, err
+func (r *productRepository) FindLowStockProducts(ctx context.Context, tenantID primitive.ObjectID) ([]Product, error) {
+	filter := bson.M{
+		"active": true,
+		"$expr":  bson.M{"$lte": []string{"$stock", "$min_stock"}},
 	}
-
-	return products, nil
+	return r.products.List(ctx, tenantID, filter)
 }
 
 func (r *productRepository) FindExpiringProducts(ctx context.Context, tenantID primitive.ObjectID, days int) ([]Product, error) {
 	expiryThreshold := time.Now().AddDate(0, 0, days)
 
 	filter := bson.M{
-		"tenant_id":       tenantID,
-		"deleted_at":      nil,
 		"active":          true,
 		"expiration_date": bson.M{"$lte": expiryThreshold, "$gte": time.Now()},
 	}
-
-	cursor, err := r.productsCollection.Find(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var products []Product
-	if err := cursor.All(ctx, &products); err != nil {
-		return nil, err
-	}
-
-	return products, nil
+	return r.products.List(ctx, tenantID, filter)
 }
 
 func (r *productRepository) FindExpiredProducts(ctx context.Context, tenantID primitive.ObjectID) ([]Product, error) {
 	filter := bson.M{
-		"tenant_id":       tenantID,
-		"deleted_at":      nil,
 		"active":          true,
 		"expiration_date": bson.M{"$lt": time.Now()},
 	}
-
-	cursor, err := r.productsCollection.Find(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var products []Product
-	if err := cursor.All(ctx, &products); err != nil {
-		return nil, err
-	}
-
-	return products, nil
+	return r.products.List(ctx, tenantID, filter)
 }
 
 // Stock Movement methods
@@ -413,6 +370,10 @@ func (r *productRepository) FindStockMovements(ctx context.Context, filters Stoc
 		}
 	}
 
+	if filters.Status != "" {
+		filter["status"] = filters.Status
+	}
+
 	// Count total
 	total, err := r.movementsCollection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -439,84 +400,128 @@ func (r *productRepository) FindStockMovements(ctx context.Context, filters Stoc
 	return movements, total, nil
 }
 
-// Category methods
-
-func (r *productRepository) CreateCategory(ctx context.Context, category *Category) error {
-	result, err := r.categoriesCollection.InsertOne(ctx, category)
-	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return ErrCategoryNameExists
-		}
-		return err
-	}
-	category.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-func (r *productRepository) FindCategoryByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Category, error) {
+func (r *productRepository) FindStockMovementByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*StockMovement, error) {
 	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
+		"_id":       id,
+		"tenant_id": tenantID,
 	}
 
-	var category Category
-	err := r.categoriesCollection.FindOne(ctx, filter).Decode(&category)
+	var movement StockMovement
+	err := r.movementsCollection.FindOne(ctx, filter).Decode(&movement)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, ErrCategoryNotFound
+			return nil, ErrStockMovementNotFound
 		}
 		return nil, err
 	}
 
-	return &category, nil
+	return &movement, nil
 }
 
-func (r *productRepository) FindCategories(ctx context.Context, tenantID primitive.ObjectID) ([]Category, error) {
+// MarkMovementReversed flags the movement as reversed. The update only matches
+// movements that are not reversed yet, so two concurrent reversals of the same
+// movement cannot both succeed.
+func (r *productRepository) MarkMovementReversed(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, reversalID primitive.ObjectID, userID primitive.ObjectID, at time.Time) error {
 	filter := bson.M{
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
+		"_id":       id,
+		"tenant_id": tenantID,
+		"reversed":  bson.M{"$ne": true},
 	}
 
-	cursor, err := r.categoriesCollection.Find(ctx, filter)
+	update := bson.M{
+		"$set": bson.M{
+			"reversed":    true,
+			"reversal_id": reversalID,
+			"reversed_by": userID,
+			"reversed_at": at,
+		},
+	}
+
+	result, err := r.movementsCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer cursor.Close(ctx)
 
-	var categories []Category
-	if err := cursor.All(ctx, &categories); err != nil {
-		return nil, err
+	if result.MatchedCount == 0 {
+		return ErrMovementAlreadyReversed
 	}
 
-	return categories, nil
+	return nil
 }
 
-func (r *productRepository) UpdateCategory(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
-	updates["updated_at"] = time.Now()
+// ClearMovementReversal undoes MarkMovementReversed when the reversal could not be applied.
+func (r *productRepository) ClearMovementReversal(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":       id,
+		"tenant_id": tenantID,
+	}
+
+	update := bson.M{
+		"$unset": bson.M{
+			"reversed":    "",
+			"reversal_id": "",
+			"reversed_by": "",
+			"reversed_at": "",
+		},
+	}
+
+	_, err := r.movementsCollection.UpdateOne(ctx, filter, update)
+	return err
+}
 
+// ReviewPendingMovement records the approval or rejection of a pending
+// adjustment. Only pending movements match, so two reviewers acting at the
+// same time cannot both decide it.
+func (r *productRepository) ReviewPendingMovement(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, status StockMovementStatus, userID primitive.ObjectID, notes string, at time.Time) error {
 	filter := bson.M{
-		"_id":        id,
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
+		"_id":       id,
+		"tenant_id": tenantID,
+		"status":    StockMovementPending,
 	}
 
-	result, err := r.categoriesCollection.UpdateOne(ctx, filter, bson.M{"$set": updates})
+	update := bson.M{
+		"$set": bson.M{
+			"status":       status,
+			"reviewed_by":  userID,
+			"reviewed_at":  at,
+			"review_notes": notes,
+		},
+	}
+
+	result, err := r.movementsCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return ErrCategoryNameExists
-		}
 		return err
 	}
 
 	if result.MatchedCount == 0 {
-		return ErrCategoryNotFound
+		return ErrMovementNotPending
 	}
 
 	return nil
 }
 
-func (r *productRepository) DeleteCategory(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+// ReopenPendingMovement undoes ReviewPendingMovement when an approved adjustment could not be applied.
+func (r *productRepository) ReopenPendingMovement(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":       id,
+		"tenant_id": tenantID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{"status": StockMovementPending},
+		"$unset": bson.M{
+			"reviewed_by":  "",
+			"reviewed_at":  "",
+			"review_notes": "",
+		},
+	}
+
+	_, err := r.movementsCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// SetMovementStock records the stock levels around an adjustment applied after approval.
+func (r *productRepository) SetMovementStock(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, before int, after int) error {
 	filter := bson.M{
 		"_id":       id,
 		"tenant_id": tenantID,
@@ -524,23 +529,122 @@ func (r *productRepository) DeleteCategory(ctx context.Context, id primitive.Obj
 
 	update := bson.M{
 		"$set": bson.M{
-			"deleted_at": time.Now(),
-			"updated_at": time.Now(),
+			"stock_before": before,
+			"stock_after":  after,
 		},
 	}
 
-	result, err := r.categoriesCollection.UpdateOne(ctx, filter, update)
+	_, err := r.movementsCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// SumStockOutSince returns, per product, the units consumed by sales and
+// treatments since the given time. Write-offs (expired, damaged, lost) are not
+// usage and are left out so they don't inflate reorder suggestions.
+func (r *productRepository) SumStockOutSince(ctx context.Context, tenantID primitive.ObjectID, productIDs []primitive.ObjectID, since time.Time) (map[primitive.ObjectID]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenant_id":  tenantID,
+			"product_id": bson.M{"$in": productIDs},
+			"type":       StockMovementOut,
+			"reason":     bson.M{"$in": []StockMovementReason{StockReasonSale, StockReasonTreatment}},
+			"reversed":   bson.M{"$ne": true},
+			"created_at": bson.M{"$gte": since},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$product_id",
+			"total": bson.M{"$sum": "$quantity"},
+		}}},
+	}
+
+	cursor, err := r.movementsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	if result.MatchedCount == 0 {
-		return ErrCategoryNotFound
+	var rows []struct {
+		ProductID primitive.ObjectID `bson:"_id"`
+		Total     int                `bson:"total"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[primitive.ObjectID]int, len(rows))
+	for _, row := range rows {
+		usage[row.ProductID] = row.Total
+	}
+
+	return usage, nil
+}
+
+// Expiry write-off methods
+
+func (r *productRepository) CreateExpiryWriteOff(ctx context.Context, writeOff *ExpiryWriteOff) error {
+	_, err := r.writeOffsCollection.InsertOne(ctx, writeOff)
+	return err
+}
+
+func (r *productRepository) FindExpiryWriteOffs(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]ExpiryWriteOff, int64, error) {
+	filter := bson.M{
+		"tenant_id": tenantID,
+	}
+
+	total, err := r.writeOffsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(int64(params.Skip)).
+		SetLimit(int64(params.Limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.writeOffsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
 	}
+	defer cursor.Close(ctx)
 
+	var writeOffs []ExpiryWriteOff
+	if err := cursor.All(ctx, &writeOffs); err != nil {
+		return nil, 0, err
+	}
+
+	return writeOffs, total, nil
+}
+
+// Category methods
+
+func (r *productRepository) CreateCategory(ctx context.Context, category *Category) error {
+	result, err := r.categoriesCollection.InsertOne(ctx, category)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrCategoryNameExists
+		}
+		return err
+	}
+	category.ID = result.InsertedID.(primitive.ObjectID)
 	return nil
 }
 
+func (r *productRepository) FindCategoryByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Category, error) {
+	return r.categories.FindByID(ctx, id, tenantID)
+}
+
+func (r *productRepository) FindCategories(ctx context.Context, tenantID primitive.ObjectID) ([]Category, error) {
+	return r.categories.List(ctx, tenantID, nil)
+}
+
+func (r *productRepository) UpdateCategory(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+	return r.categories.Update(ctx, id, tenantID, updates)
+}
+
+func (r *productRepository) DeleteCategory(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+	return r.categories.SoftDelete(ctx, id, tenantID)
+}
+
 // EnsureIndexes creates required indexes for the inventory collections
 func (r *productRepository) EnsureIndexes(ctx context.Context) error {
 	// Products indexes
@@ -557,7 +661,7 @@ func (r *productRepository) EnsureIndexes(ctx context.Context) error {
 			Options: options.Index().SetUnique(true).SetSparse(true),
 		},
 		{
-			Keys: bson.D{{"name", 1}},
+			Keys: bson.D{{Key: "name", Value: 1}},
 		},
 		{
 			Keys: bson.D{{"category", 1}},
@@ -613,5 +717,17 @@ func (r *productRepository) EnsureIndexes(ctx context.Context) error {
 		return err
 	}
 
+	// Expiry write-offs indexes
+	writeOffsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	_, err = r.writeOffsCollection.Indexes().CreateMany(ctx, writeOffsIndexes, opts)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
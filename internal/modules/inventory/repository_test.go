@@ -0,0 +1,35 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecrementStockQuery_GuardsTheDecrement(t *testing.T) {
+	now := time.Now()
+
+	filter, update := decrementStockQuery(testProductID, 3, testTenantID, now)
+
+	assert.Equal(t, bson.M{
+		"_id":        testProductID,
+		"tenant_id":  testTenantID,
+		"deleted_at": nil,
+		"stock":      bson.M{"$gte": 3},
+	}, filter, "the filter must only match while the product has the units")
+	assert.Equal(t, bson.M{
+		"$inc": bson.M{"stock": -3},
+		"$set": bson.M{"updated_at": now},
+	}, update, "the decrement must be relative so concurrent updates compose")
+}
+
+func TestDecrementStockQuery_ScopedToTenant(t *testing.T) {
+	otherTenant := primitive.NewObjectID()
+
+	filter, _ := decrementStockQuery(testProductID, 1, otherTenant, time.Now())
+
+	assert.Equal(t, otherTenant, filter["tenant_id"])
+}
@@ -4,20 +4,23 @@ import (
 	"context"
 	"log"
 
+	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
 )
 
 // RegisterAdminRoutes registers admin-panel routes under /api/products
-func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, cfg *config.Config) {
 	repo := NewProductRepository(db)
 	userRepo := users.NewRepository(db)
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil,
 	)
@@ -27,13 +30,14 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 		log.Printf("failed to ensure indexes for inventory: %v", err)
 	}
 
-	service := NewService(repo, userRepo, notifSvc)
+	service := NewService(repo, userRepo, notifSvc, tenant.NewTenantRepository(db), cfg)
 	handler := NewHandler(service)
 
 	// Products routes
 	products := private.Group("/products")
 	products.POST("", handler.CreateProduct)
 	products.GET("", handler.ListProducts)
+	products.GET("/export", handler.ExportProducts)
 	products.GET("/:id", handler.GetProduct)
 	products.PUT("/:id", handler.UpdateProduct)
 	products.DELETE("/:id", handler.DeleteProduct)
@@ -42,10 +46,15 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	products.GET("/low-stock", handler.GetLowStockProducts)
 	products.GET("/expiring", handler.GetExpiringProducts)
 	products.GET("/alerts", handler.GetProductAlerts)
+	products.GET("/reorder-suggestions", handler.GetReorderSuggestions)
+	products.GET("/expiry-writeoffs", handler.GetExpiryWriteOffs)
 
 	// Stock movements routes
 	movements := private.Group("/stock-movements")
 	movements.GET("", handler.GetStockMovements)
+	movements.POST("/:id/reverse", handler.ReverseStockMovement)
+	movements.POST("/:id/approve", handler.ApproveStockMovement)
+	movements.POST("/:id/reject", handler.RejectStockMovement)
 
 	// Categories routes
 	categories := private.Group("/categories")
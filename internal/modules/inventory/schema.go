@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
 )
 
 // ProductCategory represents the category of a product
@@ -69,7 +71,7 @@ func IsValidStockMovementType(t string) bool {
 	return false
 }
 
-// IsValidStockMovementReason checks if the movement reason is valid
+// IsValidStockMovementReason checks if the movement reason is a built-in one
 func IsValidStockMovementReason(r string) bool {
 	switch StockMovementReason(r) {
 	case StockReasonPurchase, StockReasonSale, StockReasonTreatment,
@@ -92,9 +94,21 @@ const (
 	StockReasonExpired     StockMovementReason = "expired"
 	StockReasonDamaged     StockMovementReason = "damaged"
 	StockReasonLost        StockMovementReason = "lost"
+	StockReasonReversal    StockMovementReason = "reversal" // Compensates a mistyped movement
+)
+
+// StockMovementStatus tracks the approval of a large manual adjustment.
+// Movements applied as soon as they are recorded have no status.
+type StockMovementStatus string
+
+const (
+	StockMovementPending  StockMovementStatus = "pending"  // Waiting for a second user; stock untouched
+	StockMovementApproved StockMovementStatus = "approved" // Applied to stock on approval
+	StockMovementRejected StockMovementStatus = "rejected" // Never applied
 )
 
-// Product represents a product in the inventory
+// Product represents a product in the inventory. Prices are stored in minor
+// units of Currency (see the money package).
 type Product struct {
 	ID             primitive.ObjectID `bson:"_id" json:"id"`
 	TenantID       primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
@@ -105,36 +119,43 @@ type Product struct {
 	Barcode        string             `bson:"barcode,omitempty" json:"barcode,omitempty"`
 	Category       ProductCategory    `bson:"category" json:"category"`
 	Unit           ProductUnit        `bson:"unit" json:"unit"`
-	PurchasePrice  float64            `bson:"purchase_price" json:"purchase_price"`
-	SalePrice      float64            `bson:"sale_price" json:"sale_price"`
+	PurchasePrice  int64              `bson:"purchase_price" json:"purchase_price"`
+	SalePrice      int64              `bson:"sale_price" json:"sale_price"`
+	Currency       string             `bson:"currency" json:"currency"`
 	Stock          int                `bson:"stock" json:"stock"`
 	MinStock       int                `bson:"min_stock" json:"min_stock"`
 	ExpirationDate *time.Time         `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
 	SupplierID     primitive.ObjectID `bson:"supplier_id,omitempty" json:"supplier_id,omitempty"`
-	Active         bool               `bson:"active" json:"active"`
-	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
-	DeletedAt      *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// ReorderQuantity is how many units above MinStock a restock should reach.
+	ReorderQuantity     int                 `bson:"reorder_quantity,omitempty" json:"reorder_quantity,omitempty"`
+	PreferredSupplierID *primitive.ObjectID `bson:"preferred_supplier_id,omitempty" json:"preferred_supplier_id,omitempty"`
+	Tags                []string            `bson:"tags,omitempty" json:"tags,omitempty"`
+	Active              bool                `bson:"active" json:"active"`
+	CreatedAt           time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt           time.Time           `bson:"updated_at" json:"updated_at"`
+	DeletedAt           *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 }
 
 // ToResponse converts Product to ProductResponse
 func (p *Product) ToResponse() *ProductResponse {
 	resp := &ProductResponse{
-		ID:            p.ID.Hex(),
-		TenantID:      p.TenantID.Hex(),
-		Name:          p.Name,
-		Description:   p.Description,
-		SKU:           p.SKU,
-		Barcode:       p.Barcode,
-		Category:      string(p.Category),
-		Unit:          string(p.Unit),
-		PurchasePrice: p.PurchasePrice,
-		SalePrice:     p.SalePrice,
-		Stock:         p.Stock,
-		MinStock:      p.MinStock,
-		Active:        p.Active,
-		CreatedAt:     p.CreatedAt,
-		UpdatedAt:     p.UpdatedAt,
+		ID:              p.ID.Hex(),
+		TenantID:        p.TenantID.Hex(),
+		Name:            p.Name,
+		Description:     p.Description,
+		SKU:             p.SKU,
+		Barcode:         p.Barcode,
+		Category:        string(p.Category),
+		Unit:            string(p.Unit),
+		PurchasePrice:   money.New(p.PurchasePrice, p.Currency),
+		SalePrice:       money.New(p.SalePrice, p.Currency),
+		Stock:           p.Stock,
+		MinStock:        p.MinStock,
+		ReorderQuantity: p.ReorderQuantity,
+		Tags:            p.Tags,
+		Active:          p.Active,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
 	}
 
 	if p.CategoryID != primitive.NilObjectID {
@@ -149,9 +170,31 @@ func (p *Product) ToResponse() *ProductResponse {
 		resp.SupplierID = p.SupplierID.Hex()
 	}
 
+	if p.PreferredSupplierID != nil {
+		resp.PreferredSupplierID = p.PreferredSupplierID.Hex()
+	}
+
 	return resp
 }
 
+// ReorderSupplierID returns the supplier a restock should be ordered from:
+// the preferred supplier when set, otherwise the regular one.
+func (p *Product) ReorderSupplierID() primitive.ObjectID {
+	if p.PreferredSupplierID != nil {
+		return *p.PreferredSupplierID
+	}
+	return p.SupplierID
+}
+
+// ReorderTarget returns the stock level a restock should bring the product to.
+// Products without an explicit reorder quantity restock to twice their minimum.
+func (p *Product) ReorderTarget() int {
+	if p.ReorderQuantity > 0 {
+		return p.MinStock + p.ReorderQuantity
+	}
+	return p.MinStock * 2
+}
+
 // IsLowStock checks if the product is below minimum stock
 func (p *Product) IsLowStock() bool {
 	return p.Stock <= p.MinStock
@@ -176,24 +219,27 @@ func (p *Product) IsExpired() bool {
 
 // ProductResponse represents a product in API responses
 type ProductResponse struct {
-	ID             string    `json:"id"`
-	TenantID       string    `json:"tenant_id"`
-	CategoryID     string    `json:"category_id,omitempty"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description,omitempty"`
-	SKU            string    `json:"sku"`
-	Barcode        string    `json:"barcode,omitempty"`
-	Category       string    `json:"category"`
-	Unit           string    `json:"unit"`
-	PurchasePrice  float64   `json:"purchase_price"`
-	SalePrice      float64   `json:"sale_price"`
-	Stock          int       `json:"stock"`
-	MinStock       int       `json:"min_stock"`
-	ExpirationDate string    `json:"expiration_date,omitempty"`
-	SupplierID     string    `json:"supplier_id,omitempty"`
-	Active         bool      `json:"active"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                  string       `json:"id"`
+	TenantID            string       `json:"tenant_id"`
+	CategoryID          string       `json:"category_id,omitempty"`
+	Name                string       `json:"name"`
+	Description         string       `json:"description,omitempty"`
+	SKU                 string       `json:"sku"`
+	Barcode             string       `json:"barcode,omitempty"`
+	Category            string       `json:"category"`
+	Unit                string       `json:"unit"`
+	PurchasePrice       money.Amount `json:"purchase_price"`
+	SalePrice           money.Amount `json:"sale_price"`
+	Stock               int          `json:"stock"`
+	MinStock            int          `json:"min_stock"`
+	ExpirationDate      string       `json:"expiration_date,omitempty"`
+	SupplierID          string       `json:"supplier_id,omitempty"`
+	ReorderQuantity     int          `json:"reorder_quantity,omitempty"`
+	PreferredSupplierID string       `json:"preferred_supplier_id,omitempty"`
+	Tags                []string     `json:"tags,omitempty"`
+	Active              bool         `json:"active"`
+	CreatedAt           time.Time    `json:"created_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
 }
 
 // Category represents a product category
@@ -251,6 +297,38 @@ type StockMovement struct {
 	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
 	Notes       string              `bson:"notes,omitempty" json:"notes,omitempty"`
 	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+
+	// Reversal audit trail. A reversal movement points back to the original
+	// through ReferenceID; the original records who reversed it and when.
+	IsReversal bool                `bson:"is_reversal,omitempty" json:"is_reversal,omitempty"`
+	Reversed   bool                `bson:"reversed,omitempty" json:"reversed,omitempty"`
+	ReversalID *primitive.ObjectID `bson:"reversal_id,omitempty" json:"reversal_id,omitempty"`
+	ReversedBy *primitive.ObjectID `bson:"reversed_by,omitempty" json:"reversed_by,omitempty"`
+	ReversedAt *time.Time          `bson:"reversed_at,omitempty" json:"reversed_at,omitempty"`
+
+	// Approval of adjustments above the clinic's threshold. StockBefore and
+	// StockAfter stay zero until the movement is approved and applied.
+	Status      StockMovementStatus `bson:"status,omitempty" json:"status,omitempty"`
+	ReviewedBy  *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	ReviewNotes string              `bson:"review_notes,omitempty" json:"review_notes,omitempty"`
+}
+
+// IsApplied reports whether the movement has changed the product stock
+func (m *StockMovement) IsApplied() bool {
+	return m.Status == "" || m.Status == StockMovementApproved
+}
+
+// ReverseType returns the movement type that undoes this one, and false for
+// adjustments, whose direction is not recorded and so cannot be reversed.
+func (m *StockMovement) ReverseType() (StockMovementType, bool) {
+	switch m.Type {
+	case StockMovementIn:
+		return StockMovementOut, true
+	case StockMovementOut, StockMovementExpired:
+		return StockMovementIn, true
+	}
+	return "", false
 }
 
 // ToResponse converts StockMovement to StockMovementResponse
@@ -267,12 +345,30 @@ func (m *StockMovement) ToResponse() *StockMovementResponse {
 		UserID:      m.UserID.Hex(),
 		Notes:       m.Notes,
 		CreatedAt:   m.CreatedAt,
+		IsReversal:  m.IsReversal,
+		Reversed:    m.Reversed,
+		ReversedAt:  m.ReversedAt,
+		Status:      string(m.Status),
+		ReviewedAt:  m.ReviewedAt,
+		ReviewNotes: m.ReviewNotes,
 	}
 
 	if m.ReferenceID != primitive.NilObjectID {
 		resp.ReferenceID = m.ReferenceID.Hex()
 	}
 
+	if m.ReversalID != nil {
+		resp.ReversalID = m.ReversalID.Hex()
+	}
+
+	if m.ReversedBy != nil {
+		resp.ReversedBy = m.ReversedBy.Hex()
+	}
+
+	if m.ReviewedBy != nil {
+		resp.ReviewedBy = m.ReviewedBy.Hex()
+	}
+
 	return resp
 }
 
@@ -290,15 +386,102 @@ type StockMovementResponse struct {
 	UserID      string    `json:"user_id"`
 	Notes       string    `json:"notes,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	IsReversal bool       `json:"is_reversal,omitempty"`
+	Reversed   bool       `json:"reversed,omitempty"`
+	ReversalID string     `json:"reversal_id,omitempty"`
+	ReversedBy string     `json:"reversed_by,omitempty"`
+	ReversedAt *time.Time `json:"reversed_at,omitempty"`
+
+	Status      string     `json:"status,omitempty"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNotes string     `json:"review_notes,omitempty"`
+}
+
+// ExpiryWriteOffItem is a single product zeroed out by an expiry write-off
+type ExpiryWriteOffItem struct {
+	ProductID      primitive.ObjectID `bson:"product_id" json:"product_id"`
+	ProductName    string             `bson:"product_name" json:"product_name"`
+	SKU            string             `bson:"sku" json:"sku"`
+	Quantity       int                `bson:"quantity" json:"quantity"`
+	UnitCost       int64              `bson:"unit_cost" json:"unit_cost"`
+	Value          int64              `bson:"value" json:"value"`
+	Currency       string             `bson:"currency,omitempty" json:"currency,omitempty"` // Set when it differs from the write-off's
+	ExpirationDate *time.Time         `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
+	MovementID     primitive.ObjectID `bson:"movement_id" json:"movement_id"`
+}
+
+// ExpiryWriteOff records one automatic write-off run for a tenant
+type ExpiryWriteOff struct {
+	ID            primitive.ObjectID   `bson:"_id" json:"id"`
+	TenantID      primitive.ObjectID   `bson:"tenant_id" json:"tenant_id"`
+	Items         []ExpiryWriteOffItem `bson:"items" json:"items"`
+	TotalQuantity int                  `bson:"total_quantity" json:"total_quantity"`
+	TotalValue    int64                `bson:"total_value" json:"total_value"` // At purchase price, minor units of Currency only
+	Currency      string               `bson:"currency" json:"currency"`
+	CreatedAt     time.Time            `bson:"created_at" json:"created_at"`
+}
+
+// ToResponse converts ExpiryWriteOff to ExpiryWriteOffResponse
+func (w *ExpiryWriteOff) ToResponse() *ExpiryWriteOffResponse {
+	items := make([]ExpiryWriteOffItemResponse, len(w.Items))
+	for i, item := range w.Items {
+		currency := w.Currency
+		if item.Currency != "" {
+			currency = item.Currency
+		}
+		items[i] = ExpiryWriteOffItemResponse{
+			ProductID:   item.ProductID.Hex(),
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+			UnitCost:    money.New(item.UnitCost, currency),
+			Value:       money.New(item.Value, currency),
+			MovementID:  item.MovementID.Hex(),
+		}
+		if item.ExpirationDate != nil {
+			items[i].ExpirationDate = item.ExpirationDate.Format(time.RFC3339)
+		}
+	}
+
+	return &ExpiryWriteOffResponse{
+		ID:            w.ID.Hex(),
+		Items:         items,
+		TotalQuantity: w.TotalQuantity,
+		TotalValue:    money.New(w.TotalValue, w.Currency),
+		CreatedAt:     w.CreatedAt,
+	}
+}
+
+// ExpiryWriteOffItemResponse represents a written-off product in API responses
+type ExpiryWriteOffItemResponse struct {
+	ProductID      string       `json:"product_id"`
+	ProductName    string       `json:"product_name"`
+	SKU            string       `json:"sku"`
+	Quantity       int          `json:"quantity"`
+	UnitCost       money.Amount `json:"unit_cost"`
+	Value          money.Amount `json:"value"`
+	ExpirationDate string       `json:"expiration_date,omitempty"`
+	MovementID     string       `json:"movement_id"`
+}
+
+// ExpiryWriteOffResponse represents an expiry write-off in API responses
+type ExpiryWriteOffResponse struct {
+	ID            string                       `json:"id"`
+	Items         []ExpiryWriteOffItemResponse `json:"items"`
+	TotalQuantity int                          `json:"total_quantity"`
+	TotalValue    money.Amount                 `json:"total_value"`
+	CreatedAt     time.Time                    `json:"created_at"`
 }
 
 // StockAlert represents a stock alert (low stock or expiring)
 type StockAlert struct {
-	ProductID   primitive.ObjectID `bson:"product_id" json:"product_id"`
-	ProductName string             `bson:"product_name" json:"product_name"`
-	AlertType   string             `bson:"alert_type" json:"alert_type"` // low_stock, expiring, expired
-	CurrentStock int               `bson:"current_stock" json:"current_stock"`
-	MinStock    int                `bson:"min_stock" json:"min_stock"`
-	ExpirationDate *time.Time      `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
-	DaysUntilExpiry int            `bson:"days_until_expiry,omitempty" json:"days_until_expiry,omitempty"`
+	ProductID       primitive.ObjectID `bson:"product_id" json:"product_id"`
+	ProductName     string             `bson:"product_name" json:"product_name"`
+	AlertType       string             `bson:"alert_type" json:"alert_type"` // low_stock, expiring, expired
+	CurrentStock    int                `bson:"current_stock" json:"current_stock"`
+	MinStock        int                `bson:"min_stock" json:"min_stock"`
+	ExpirationDate  *time.Time         `bson:"expiration_date,omitempty" json:"expiration_date,omitempty"`
+	DaysUntilExpiry int                `bson:"days_until_expiry,omitempty" json:"days_until_expiry,omitempty"`
 }
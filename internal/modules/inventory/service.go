@@ -2,16 +2,31 @@ package inventory
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tags"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/shared/money"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
+// Defaults for reorder suggestions when the caller doesn't override them.
+const (
+	defaultUsageDays    = 30
+	defaultCoverageDays = 30
+)
+
 // NotificationSender defines the interface for sending notifications
 type NotificationSender interface {
 	SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error
@@ -22,20 +37,43 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id string) (*users.User, error)
 }
 
+// TenantReader loads the clinic settings (currency)
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
 // Service provides business logic for inventory
 type Service struct {
 	repo            ProductRepository
 	userRepo        UserRepository
 	notificationSvc NotificationSender
+	tenantRepo      TenantReader
+	cfg             *config.Config
 }
 
 // NewService creates a new inventory service
-func NewService(repo ProductRepository, userRepo UserRepository, notificationSvc NotificationSender) *Service {
+func NewService(repo ProductRepository, userRepo UserRepository, notificationSvc NotificationSender, tenantRepo TenantReader, cfg *config.Config) *Service {
 	return &Service{
 		repo:            repo,
 		userRepo:        userRepo,
 		notificationSvc: notificationSvc,
+		tenantRepo:      tenantRepo,
+		cfg:             cfg,
+	}
+}
+
+// currency returns the clinic's currency, falling back to the default one when
+// it has none or the lookup fails
+func (s *Service) currency(ctx context.Context, tenantID primitive.ObjectID) string {
+	if s.tenantRepo == nil {
+		return money.DefaultCurrency
+	}
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		slog.Warn("failed to load tenant currency, using default", "tenant_id", tenantID.Hex(), "error", err)
+		return money.DefaultCurrency
 	}
+	return money.Normalize(t.Currency)
 }
 
 // CreateProduct creates a new product
@@ -64,8 +102,21 @@ func (s *Service) CreateProduct(ctx context.Context, dto *CreateProductDTO, tena
 		supplierID = supID
 	}
 
+	var preferredSupplierID *primitive.ObjectID
+	if dto.PreferredSupplierID != "" {
+		supID, err := primitive.ObjectIDFromHex(dto.PreferredSupplierID)
+		if err != nil {
+			return nil, ErrValidation("preferred_supplier_id", "invalid supplier ID format")
+		}
+		preferredSupplierID = &supID
+	}
+
+	currency := s.currency(ctx, tenantID)
+	purchasePrice := money.FromMajor(dto.PurchasePrice, currency)
+	salePrice := money.FromMajor(dto.SalePrice, currency)
+
 	// Validate sale price >= purchase price
-	if dto.SalePrice < dto.PurchasePrice {
+	if salePrice < purchasePrice {
 		return nil, ErrSalePriceTooLow
 	}
 
@@ -79,27 +130,36 @@ func (s *Service) CreateProduct(ctx context.Context, dto *CreateProductDTO, tena
 		expirationDate = &expDate
 	}
 
+	tagList, err := tags.Normalize(dto.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create product
 	now := time.Now()
 	product := &Product{
-		ID:             primitive.NewObjectID(),
-		TenantID:       tenantID,
-		CategoryID:     categoryID,
-		Name:           dto.Name,
-		Description:    dto.Description,
-		SKU:            dto.SKU,
-		Barcode:        dto.Barcode,
-		Category:       ProductCategory(dto.Category),
-		Unit:           ProductUnit(dto.Unit),
-		PurchasePrice:  dto.PurchasePrice,
-		SalePrice:      dto.SalePrice,
-		Stock:          dto.Stock,
-		MinStock:       dto.MinStock,
-		ExpirationDate: expirationDate,
-		SupplierID:     supplierID,
-		Active:         dto.Active,
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		ID:                  primitive.NewObjectID(),
+		TenantID:            tenantID,
+		CategoryID:          categoryID,
+		Name:                dto.Name,
+		Description:         dto.Description,
+		SKU:                 dto.SKU,
+		Barcode:             dto.Barcode,
+		Category:            ProductCategory(dto.Category),
+		Unit:                ProductUnit(dto.Unit),
+		PurchasePrice:       purchasePrice,
+		SalePrice:           salePrice,
+		Currency:            currency,
+		Stock:               dto.Stock,
+		MinStock:            dto.MinStock,
+		ExpirationDate:      expirationDate,
+		SupplierID:          supplierID,
+		ReorderQuantity:     dto.ReorderQuantity,
+		PreferredSupplierID: preferredSupplierID,
+		Tags:                tagList,
+		Active:              dto.Active,
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	}
 
 	if err := s.repo.Create(ctx, product); err != nil {
@@ -180,19 +240,25 @@ func (s *Service) UpdateProduct(ctx context.Context, id string, dto *UpdateProdu
 		updates["unit"] = dto.Unit
 	}
 
-	if dto.PurchasePrice > 0 {
-		updates["purchase_price"] = dto.PurchasePrice
+	// Prices are converted with the product's own currency, which may predate
+	// a change of the clinic's
+	currency := money.Normalize(product.Currency)
+	purchasePrice := money.FromMajor(dto.PurchasePrice, currency)
+	salePrice := money.FromMajor(dto.SalePrice, currency)
+
+	if purchasePrice > 0 {
+		updates["purchase_price"] = purchasePrice
 	}
 
-	if dto.SalePrice > 0 {
-		if dto.PurchasePrice > 0 && dto.SalePrice < dto.PurchasePrice {
+	if salePrice > 0 {
+		if purchasePrice > 0 && salePrice < purchasePrice {
 			return nil, ErrSalePriceTooLow
 		}
 		// Also check against existing purchase price if not updating it
-		if dto.PurchasePrice == 0 && dto.SalePrice < product.PurchasePrice {
+		if purchasePrice == 0 && salePrice < product.PurchasePrice {
 			return nil, ErrSalePriceTooLow
 		}
-		updates["sale_price"] = dto.SalePrice
+		updates["sale_price"] = salePrice
 	}
 
 	if dto.MinStock > 0 {
@@ -218,6 +284,26 @@ func (s *Service) UpdateProduct(ctx context.Context, id string, dto *UpdateProdu
 		updates["supplier_id"] = supID
 	}
 
+	if dto.PreferredSupplierID != "" {
+		supID, err := primitive.ObjectIDFromHex(dto.PreferredSupplierID)
+		if err != nil {
+			return nil, ErrValidation("preferred_supplier_id", "invalid supplier ID format")
+		}
+		updates["preferred_supplier_id"] = supID
+	}
+
+	if dto.ReorderQuantity != nil {
+		updates["reorder_quantity"] = *dto.ReorderQuantity
+	}
+
+	if dto.Tags != nil {
+		tagList, err := tags.Normalize(dto.Tags)
+		if err != nil {
+			return nil, err
+		}
+		updates["tags"] = tagList
+	}
+
 	updates["active"] = dto.Active
 
 	if err := s.repo.Update(ctx, productID, updates, tenantID); err != nil {
@@ -260,6 +346,22 @@ func (s *Service) StockIn(ctx context.Context, id string, dto *StockInDTO, tenan
 		return nil, ErrValidation("reason", "invalid stock movement reason")
 	}
 
+	var referenceID primitive.ObjectID
+	if dto.ReferenceID != "" {
+		if refID, err := primitive.ObjectIDFromHex(dto.ReferenceID); err == nil {
+			referenceID = refID
+		}
+	}
+
+	// Large manual adjustments wait for a second user before touching the stock
+	hold, err := s.adjustmentNeedsApproval(ctx, tenantID, dto.Reason, dto.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if hold {
+		return s.holdAdjustment(ctx, product, StockMovementIn, dto.Quantity, referenceID, dto.Notes, userID)
+	}
+
 	// Update stock
 	stockBefore := product.Stock
 	if err := s.repo.UpdateStock(ctx, productID, dto.Quantity, tenantID); err != nil {
@@ -274,13 +376,6 @@ func (s *Service) StockIn(ctx context.Context, id string, dto *StockInDTO, tenan
 	stockAfter := updatedProduct.Stock
 
 	// Create stock movement record
-	var referenceID primitive.ObjectID
-	if dto.ReferenceID != "" {
-		if refID, err := primitive.ObjectIDFromHex(dto.ReferenceID); err == nil {
-			referenceID = refID
-		}
-	}
-
 	movement := &StockMovement{
 		ID:          primitive.NewObjectID(),
 		TenantID:    tenantID,
@@ -303,6 +398,22 @@ func (s *Service) StockIn(ctx context.Context, id string, dto *StockInDTO, tenan
 	return movement, nil
 }
 
+// isStockOutReason accepts the built-in reasons and the ones the clinic
+// configured for its own stock-outs
+func (s *Service) isStockOutReason(ctx context.Context, tenantID primitive.ObjectID, reason string) (bool, error) {
+	if IsValidStockMovementReason(reason) {
+		return true, nil
+	}
+	if s.tenantRepo == nil {
+		return false, nil
+	}
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		return false, err
+	}
+	return t.Settings.AcceptsStockOutReason(reason), nil
+}
+
 // StockOut deducts stock from a product
 func (s *Service) StockOut(ctx context.Context, id string, dto *StockOutDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*StockMovement, error) {
 	productID, err := primitive.ObjectIDFromHex(id)
@@ -310,40 +421,44 @@ func (s *Service) StockOut(ctx context.Context, id string, dto *StockOutDTO, ten
 		return nil, ErrValidation("id", "invalid product ID format")
 	}
 
-	product, err := s.repo.FindByID(ctx, productID, tenantID)
+	// Validate movement type
+	valid, err := s.isStockOutReason(ctx, tenantID, dto.Reason)
 	if err != nil {
 		return nil, err
 	}
-
-	// Check if there's enough stock
-	if product.Stock < dto.Quantity {
-		return nil, ErrInsufficientStock
-	}
-
-	// Validate movement type
-	if !IsValidStockMovementReason(dto.Reason) {
+	if !valid {
 		return nil, ErrValidation("reason", "invalid stock movement reason")
 	}
 
-	// Update stock (negative quantity to deduct)
-	stockBefore := product.Stock
-	if err := s.repo.UpdateStock(ctx, productID, -dto.Quantity, tenantID); err != nil {
-		return nil, err
+	var referenceID primitive.ObjectID
+	if dto.ReferenceID != "" {
+		if refID, err := primitive.ObjectIDFromHex(dto.ReferenceID); err == nil {
+			referenceID = refID
+		}
 	}
 
-	// Get updated product to get new stock
-	updatedProduct, err := s.repo.FindByID(ctx, productID, tenantID)
+	// Large manual adjustments wait for a second user before touching the
+	// stock; the stock check runs again when the adjustment is approved
+	hold, err := s.adjustmentNeedsApproval(ctx, tenantID, dto.Reason, dto.Quantity)
 	if err != nil {
 		return nil, err
 	}
-	stockAfter := updatedProduct.Stock
-
-	// Create stock movement record
-	var referenceID primitive.ObjectID
-	if dto.ReferenceID != "" {
-		if refID, err := primitive.ObjectIDFromHex(dto.ReferenceID); err == nil {
-			referenceID = refID
+	if hold {
+		product, err := s.repo.FindByID(ctx, productID, tenantID)
+		if err != nil {
+			return nil, err
 		}
+		if product.Stock < dto.Quantity {
+			return nil, ErrInsufficientStock
+		}
+		return s.holdAdjustment(ctx, product, StockMovementOut, dto.Quantity, referenceID, dto.Notes, userID)
+	}
+
+	// The guarded decrement is the stock check: reading first and writing
+	// after would let two concurrent stock-outs both pass it
+	stockBefore, stockAfter, err := s.repo.DecrementStock(ctx, productID, dto.Quantity, tenantID)
+	if err != nil {
+		return nil, err
 	}
 
 	movement := &StockMovement{
@@ -368,6 +483,99 @@ func (s *Service) StockOut(ctx context.Context, id string, dto *StockOutDTO, ten
 	return movement, nil
 }
 
+// ReverseStockMovement undoes a mistyped stock-in or stock-out by recording a
+// compensating movement of the opposite type, linked to the original through
+// ReferenceID, and restoring the product stock. History is never edited: the
+// original is only flagged as reversed.
+func (s *Service) ReverseStockMovement(ctx context.Context, id string, dto *ReverseStockMovementDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*StockMovement, error) {
+	movementID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid stock movement ID format")
+	}
+
+	original, err := s.repo.FindStockMovementByID(ctx, movementID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !original.IsApplied() {
+		return nil, ErrMovementNotApplied
+	}
+	if original.IsReversal {
+		return nil, ErrCannotReverseReversal
+	}
+	if original.Reversed {
+		return nil, ErrMovementAlreadyReversed
+	}
+
+	reverseType, ok := original.ReverseType()
+	if !ok {
+		return nil, ErrMovementNotReversible
+	}
+
+	window := time.Duration(s.cfg.StockReversalWindowHours) * time.Hour
+	if time.Since(original.CreatedAt) > window {
+		return nil, ErrReversalWindowExpired
+	}
+
+	product, err := s.repo.FindByID(ctx, original.ProductID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reverseType == StockMovementOut && product.Stock < original.Quantity {
+		return nil, ErrInsufficientStock
+	}
+
+	now := time.Now()
+	reversalID := primitive.NewObjectID()
+
+	// Claim the original first so a concurrent request cannot reverse it twice
+	if err := s.repo.MarkMovementReversed(ctx, movementID, tenantID, reversalID, userID, now); err != nil {
+		return nil, err
+	}
+
+	stockBefore, stockAfter := product.Stock, product.Stock+original.Quantity
+	if reverseType == StockMovementOut {
+		stockBefore, stockAfter, err = s.repo.DecrementStock(ctx, product.ID, original.Quantity, tenantID)
+	} else {
+		err = s.repo.UpdateStock(ctx, product.ID, original.Quantity, tenantID)
+	}
+	if err != nil {
+		_ = s.repo.ClearMovementReversal(ctx, movementID, tenantID)
+		return nil, err
+	}
+
+	reversal := &StockMovement{
+		ID:          reversalID,
+		TenantID:    tenantID,
+		ProductID:   product.ID,
+		Type:        reverseType,
+		Reason:      StockReasonReversal,
+		Quantity:    original.Quantity,
+		StockBefore: stockBefore,
+		StockAfter:  stockAfter,
+		ReferenceID: original.ID,
+		UserID:      userID,
+		Notes:       dto.Notes,
+		CreatedAt:   now,
+		IsReversal:  true,
+	}
+
+	if err := s.repo.CreateStockMovement(ctx, reversal); err != nil {
+		// Undo the stock change and release the claim so the reversal can be retried
+		delta := -original.Quantity
+		if reverseType == StockMovementOut {
+			delta = original.Quantity
+		}
+		_ = s.repo.UpdateStock(ctx, product.ID, delta, tenantID)
+		_ = s.repo.ClearMovementReversal(ctx, movementID, tenantID)
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
 // GetStockMovements lists stock movements with filters
 func (s *Service) GetStockMovements(ctx context.Context, filters StockMovementListFilters, tenantID primitive.ObjectID, params pagination.Params) ([]StockMovement, int64, error) {
 	return s.repo.FindStockMovements(ctx, filters, tenantID, params)
@@ -378,6 +586,93 @@ func (s *Service) GetLowStockProducts(ctx context.Context, tenantID primitive.Ob
 	return s.repo.FindLowStockProducts(ctx, tenantID)
 }
 
+// GetReorderSuggestions lists low-stock products grouped by supplier with the
+// quantity needed to bring each one back to its reorder target. The target is
+// raised when recent consumption (usageDays of stock-outs) projected over
+// coverageDays would exceed it, so fast-moving products are not under-ordered.
+func (s *Service) GetReorderSuggestions(ctx context.Context, tenantID primitive.ObjectID, usageDays, coverageDays int) ([]ReorderSuggestionGroup, error) {
+	products, err := s.repo.FindLowStockProducts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return []ReorderSuggestionGroup{}, nil
+	}
+
+	productIDs := make([]primitive.ObjectID, len(products))
+	for i, p := range products {
+		productIDs[i] = p.ID
+	}
+
+	usage, err := s.repo.SumStockOutSince(ctx, tenantID, productIDs, time.Now().AddDate(0, 0, -usageDays))
+	if err != nil {
+		return nil, err
+	}
+
+	// Products are grouped per supplier and currency, so each group's estimated
+	// cost only adds up amounts of one currency
+	type groupKey struct {
+		supplierID primitive.ObjectID
+		currency   string
+	}
+	groups := make([]ReorderSuggestionGroup, 0)
+	groupIndex := make(map[groupKey]int)
+	supplierCurrency := make(map[primitive.ObjectID]string)
+
+	for _, p := range products {
+		avgDailyUsage := float64(usage[p.ID]) / float64(usageDays)
+
+		target := p.ReorderTarget()
+		if projected := p.MinStock + int(math.Ceil(avgDailyUsage*float64(coverageDays))); projected > target {
+			target = projected
+		}
+
+		suggested := target - p.Stock
+		if suggested < 0 {
+			suggested = 0
+		}
+
+		item := ReorderSuggestionItem{
+			ProductID:         p.ID.Hex(),
+			ProductName:       p.Name,
+			SKU:               p.SKU,
+			Unit:              string(p.Unit),
+			CurrentStock:      p.Stock,
+			MinStock:          p.MinStock,
+			ReorderQuantity:   p.ReorderQuantity,
+			TargetStock:       target,
+			AvgDailyUsage:     math.Round(avgDailyUsage*100) / 100,
+			SuggestedQuantity: suggested,
+			EstimatedCost:     money.New(int64(suggested)*p.PurchasePrice, p.Currency),
+		}
+
+		supplierID := p.ReorderSupplierID()
+		key := groupKey{supplierID: supplierID, currency: item.EstimatedCost.Currency}
+		idx, ok := groupIndex[key]
+		if !ok {
+			if first, seen := supplierCurrency[supplierID]; seen {
+				slog.Warn("reorder suggestions: supplier products priced in several currencies, grouped apart",
+					"tenant_id", tenantID.Hex(), "supplier_id", supplierID.Hex(), "product_id", p.ID.Hex(), "currency", key.currency, "other_currency", first)
+			} else {
+				supplierCurrency[supplierID] = key.currency
+			}
+			group := ReorderSuggestionGroup{Items: make([]ReorderSuggestionItem, 0)}
+			if supplierID != primitive.NilObjectID {
+				group.SupplierID = supplierID.Hex()
+			}
+			groups = append(groups, group)
+			idx = len(groups) - 1
+			groupIndex[key] = idx
+		}
+
+		groups[idx].Items = append(groups[idx].Items, item)
+		groups[idx].TotalQuantity += item.SuggestedQuantity
+		groups[idx].EstimatedCost = money.New(groups[idx].EstimatedCost.Minor+item.EstimatedCost.Minor, key.currency)
+	}
+
+	return groups, nil
+}
+
 // GetExpiringProducts gets products expiring soon
 func (s *Service) GetExpiringProducts(ctx context.Context, tenantID primitive.ObjectID, days int) ([]Product, error) {
 	return s.repo.FindExpiringProducts(ctx, tenantID, days)
@@ -505,6 +800,99 @@ func (s *Service) SendExpiringAlerts(ctx context.Context, tenantID primitive.Obj
 	return nil
 }
 
+// WriteOffExpiredProducts zeroes out the remaining stock of every expired
+// product with an "expired" stock-out, records the run and notifies staff
+// with the list and total value written off. The total only counts products
+// priced in the tenant's currency. It returns nil when there was nothing to
+// write off.
+func (s *Service) WriteOffExpiredProducts(ctx context.Context, tenantID primitive.ObjectID) (*ExpiryWriteOff, error) {
+	products, err := s.GetExpiredProducts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	writeOff := &ExpiryWriteOff{
+		ID:        primitive.NewObjectID(),
+		TenantID:  tenantID,
+		Items:     make([]ExpiryWriteOffItem, 0),
+		Currency:  s.currency(ctx, tenantID),
+		CreatedAt: time.Now(),
+	}
+
+	for _, p := range products {
+		if p.Stock <= 0 {
+			continue
+		}
+
+		movement, err := s.StockOut(ctx, p.ID.Hex(), &StockOutDTO{
+			Quantity:    p.Stock,
+			Reason:      string(StockReasonExpired),
+			ReferenceID: writeOff.ID.Hex(),
+			Notes:       "Baja automática por vencimiento",
+		}, tenantID, primitive.NilObjectID)
+		if err != nil {
+			// Keep going: one product failing shouldn't block the rest
+			slog.Warn("expiry write-off failed", "tenant_id", tenantID.Hex(), "product_id", p.ID.Hex(), "error", err)
+			continue
+		}
+
+		item := ExpiryWriteOffItem{
+			ProductID:      p.ID,
+			ProductName:    p.Name,
+			SKU:            p.SKU,
+			Quantity:       movement.Quantity,
+			UnitCost:       p.PurchasePrice,
+			Value:          int64(movement.Quantity) * p.PurchasePrice,
+			ExpirationDate: p.ExpirationDate,
+			MovementID:     movement.ID,
+		}
+		// Minor units of another currency cannot be added to the total
+		if currency := money.Normalize(p.Currency); currency != writeOff.Currency {
+			item.Currency = currency
+			slog.Warn("expiry write-off: product priced in another currency left out of the total",
+				"tenant_id", tenantID.Hex(), "product_id", p.ID.Hex(), "currency", currency, "tenant_currency", writeOff.Currency)
+		} else {
+			writeOff.TotalValue += item.Value
+		}
+		writeOff.Items = append(writeOff.Items, item)
+		writeOff.TotalQuantity += item.Quantity
+	}
+
+	if len(writeOff.Items) == 0 {
+		return nil, nil
+	}
+
+	if err := s.repo.CreateExpiryWriteOff(ctx, writeOff); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(writeOff.Items))
+	for i, item := range writeOff.Items {
+		names[i] = fmt.Sprintf("%s (%d)", item.ProductName, item.Quantity)
+	}
+
+	s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   primitive.NilObjectID.Hex(), // Broadcast to all staff
+		TenantID: tenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    "Baja de productos vencidos",
+		Body:     fmt.Sprintf("Se dieron de baja %d productos vencidos por un valor de %s: %s", len(writeOff.Items), money.Format(writeOff.TotalValue, writeOff.Currency), strings.Join(names, ", ")),
+		Data: map[string]string{
+			"writeoff_id":    writeOff.ID.Hex(),
+			"total_quantity": strconv.Itoa(writeOff.TotalQuantity),
+			"total_value":    strconv.FormatFloat(money.ToMajor(writeOff.TotalValue, writeOff.Currency), 'f', money.Decimals(writeOff.Currency), 64),
+			"currency":       writeOff.Currency,
+		},
+	})
+
+	return writeOff, nil
+}
+
+// ListExpiryWriteOffs lists past expiry write-offs, newest first
+func (s *Service) ListExpiryWriteOffs(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]ExpiryWriteOff, int64, error) {
+	return s.repo.FindExpiryWriteOffs(ctx, tenantID, params)
+}
+
 // CreateCategory creates a new category
 func (s *Service) CreateCategory(ctx context.Context, dto *CreateCategoryDTO, tenantID primitive.ObjectID) (*Category, error) {
 	// Validate parent category if provided
@@ -0,0 +1,388 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+var (
+	testTenantID  = primitive.NewObjectID()
+	testUserID    = primitive.NewObjectID()
+	testProductID = primitive.NewObjectID()
+)
+
+// mockStockRepo keeps a single product in memory. DecrementStock applies the
+// query the real repository sends to Mongo, under one lock as Mongo applies a
+// single-document update. The embedded interface panics on any method a test
+// did not expect.
+type mockStockRepo struct {
+	ProductRepository
+
+	mu        sync.Mutex
+	stock     int
+	movements []*StockMovement
+	createErr error
+}
+
+func (m *mockStockRepo) FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Product, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id != testProductID {
+		return nil, ErrProductNotFound
+	}
+	return &Product{ID: id, TenantID: tenantID, Stock: m.stock}, nil
+}
+
+func (m *mockStockRepo) DecrementStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) (int, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id != testProductID {
+		return 0, 0, ErrProductNotFound
+	}
+	filter, update := decrementStockQuery(id, quantity, tenantID, time.Now())
+	if m.stock < filter["stock"].(bson.M)["$gte"].(int) {
+		return 0, 0, ErrInsufficientStock
+	}
+	before := m.stock
+	m.stock += update["$inc"].(bson.M)["stock"].(int)
+	return before, m.stock, nil
+}
+
+func (m *mockStockRepo) UpdateStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stock += quantity
+	return nil
+}
+
+func (m *mockStockRepo) CreateStockMovement(ctx context.Context, movement *StockMovement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.movements = append(m.movements, movement)
+	return nil
+}
+
+func (m *mockStockRepo) FindStockMovementByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*StockMovement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mv := range m.movements {
+		if mv.ID == id {
+			copied := *mv
+			return &copied, nil
+		}
+	}
+	return nil, ErrStockMovementNotFound
+}
+
+func (m *mockStockRepo) ReviewPendingMovement(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, status StockMovementStatus, userID primitive.ObjectID, notes string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mv := range m.movements {
+		if mv.ID == id && mv.Status == StockMovementPending {
+			mv.Status = status
+			mv.ReviewedBy = &userID
+			return nil
+		}
+	}
+	return ErrMovementNotPending
+}
+
+func (m *mockStockRepo) ReopenPendingMovement(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mv := range m.movements {
+		if mv.ID == id {
+			mv.Status = StockMovementPending
+			mv.ReviewedBy = nil
+		}
+	}
+	return nil
+}
+
+func (m *mockStockRepo) SetMovementStock(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, before int, after int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mv := range m.movements {
+		if mv.ID == id {
+			mv.StockBefore, mv.StockAfter = before, after
+		}
+	}
+	return nil
+}
+
+func (m *mockStockRepo) MarkMovementReversed(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, reversalID primitive.ObjectID, userID primitive.ObjectID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mv := range m.movements {
+		if mv.ID == id && !mv.Reversed {
+			mv.Reversed = true
+			mv.ReversalID = &reversalID
+			return nil
+		}
+	}
+	return ErrMovementAlreadyReversed
+}
+
+func (m *mockStockRepo) ClearMovementReversal(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mv := range m.movements {
+		if mv.ID == id {
+			mv.Reversed = false
+			mv.ReversalID = nil
+		}
+	}
+	return nil
+}
+
+type mockTenantReader struct {
+	settings tenant.TenantSettings
+}
+
+func (m *mockTenantReader) FindByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	return &tenant.Tenant{Settings: m.settings}, nil
+}
+
+type mockStaffNotifier struct {
+	sent []*notifications.SendStaffDTO
+}
+
+func (m *mockStaffNotifier) SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error {
+	m.sent = append(m.sent, dto)
+	return nil
+}
+
+func TestStockOut_RecordsBeforeAndAfter(t *testing.T) {
+	repo := &mockStockRepo{stock: 10}
+	svc := NewService(repo, nil, nil, nil, nil)
+
+	movement, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 3, Reason: "sale"}, testTenantID, testUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, movement.StockBefore)
+	assert.Equal(t, 7, movement.StockAfter)
+	assert.Equal(t, 7, repo.stock)
+}
+
+func TestStockOut_InsufficientStock(t *testing.T) {
+	repo := &mockStockRepo{stock: 2}
+	svc := NewService(repo, nil, nil, nil, nil)
+
+	movement, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 3, Reason: "sale"}, testTenantID, testUserID)
+
+	assert.Nil(t, movement)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Equal(t, 2, repo.stock)
+	assert.Empty(t, repo.movements)
+}
+
+func TestStockOut_AcceptsClinicReasons(t *testing.T) {
+	repo := &mockStockRepo{stock: 10}
+	tenants := &mockTenantReader{settings: tenant.TenantSettings{StockOutReasons: []string{"donation"}}}
+	svc := NewService(repo, nil, nil, tenants, nil)
+
+	movement, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 2, Reason: "donation"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, StockMovementReason("donation"), movement.Reason)
+
+	_, err = svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 2, Reason: "gift"}, testTenantID, testUserID)
+	assert.Error(t, err)
+	assert.Equal(t, 8, repo.stock)
+}
+
+func TestStockOut_ConcurrentDeductionsDoNotOversell(t *testing.T) {
+	const (
+		initialStock = 10
+		workers      = 25
+		quantity     = 1
+	)
+	repo := &mockStockRepo{stock: initialStock}
+	svc := NewService(repo, nil, nil, nil, nil)
+
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		succeeded    int
+		insufficient int
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: quantity, Reason: "sale"}, testTenantID, testUserID)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrInsufficientStock):
+				insufficient++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, initialStock, succeeded)
+	assert.Equal(t, workers-initialStock, insufficient)
+	assert.Equal(t, 0, repo.stock)
+
+	// Every movement must reflect a distinct, consistent step of the stock
+	seen := make(map[int]bool, len(repo.movements))
+	for _, m := range repo.movements {
+		assert.Equal(t, m.StockBefore-quantity, m.StockAfter)
+		assert.False(t, seen[m.StockBefore], "two movements recorded the same stock_before %d", m.StockBefore)
+		seen[m.StockBefore] = true
+	}
+}
+
+func approvalService(repo *mockStockRepo, notifier *mockStaffNotifier) *Service {
+	tenants := &mockTenantReader{settings: tenant.TenantSettings{
+		RequireStockAdjustmentApproval:   true,
+		StockAdjustmentApprovalThreshold: 5,
+	}}
+	return NewService(repo, nil, notifier, tenants, nil)
+}
+
+func TestStockOut_LargeAdjustmentWaitsForApproval(t *testing.T) {
+	repo := &mockStockRepo{stock: 10}
+	notifier := &mockStaffNotifier{}
+	svc := approvalService(repo, notifier)
+
+	small, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 5, Reason: "adjustment"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Empty(t, small.Status)
+	assert.Equal(t, 5, repo.stock)
+
+	sale, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 4, Reason: "sale"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Empty(t, sale.Status, "only adjustments need approval")
+	assert.Equal(t, 1, repo.stock)
+
+	repo.stock = 10
+	pending, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 6, Reason: "adjustment"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, StockMovementPending, pending.Status)
+	assert.Equal(t, 10, repo.stock, "a pending adjustment must not touch the stock")
+	assert.Len(t, notifier.sent, 1)
+}
+
+func TestApproveStockMovement_RequiresSecondUser(t *testing.T) {
+	repo := &mockStockRepo{stock: 10}
+	notifier := &mockStaffNotifier{}
+	svc := approvalService(repo, notifier)
+
+	pending, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 6, Reason: "adjustment"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+
+	_, err = svc.ApproveStockMovement(context.Background(), pending.ID.Hex(), &ReviewStockMovementDTO{}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrSelfApproval)
+	assert.Equal(t, 10, repo.stock)
+
+	approverID := primitive.NewObjectID()
+	approved, err := svc.ApproveStockMovement(context.Background(), pending.ID.Hex(), &ReviewStockMovementDTO{Notes: "conteo físico"}, testTenantID, approverID)
+	assert.NoError(t, err)
+	assert.Equal(t, StockMovementApproved, approved.Status)
+	assert.Equal(t, 10, approved.StockBefore)
+	assert.Equal(t, 4, approved.StockAfter)
+	assert.Equal(t, 4, repo.stock)
+	assert.Equal(t, testUserID.Hex(), notifier.sent[len(notifier.sent)-1].UserID, "the requester hears about the decision")
+
+	_, err = svc.RejectStockMovement(context.Background(), pending.ID.Hex(), &ReviewStockMovementDTO{}, testTenantID, approverID)
+	assert.ErrorIs(t, err, ErrMovementNotPending)
+}
+
+func TestApproveStockMovement_InsufficientStockKeepsItPending(t *testing.T) {
+	repo := &mockStockRepo{stock: 10}
+	svc := approvalService(repo, &mockStaffNotifier{})
+
+	pending, err := svc.StockOut(context.Background(), testProductID.Hex(), &StockOutDTO{Quantity: 8, Reason: "adjustment"}, testTenantID, testUserID)
+	assert.NoError(t, err)
+
+	repo.stock = 3
+	_, err = svc.ApproveStockMovement(context.Background(), pending.ID.Hex(), &ReviewStockMovementDTO{}, testTenantID, primitive.NewObjectID())
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Equal(t, 3, repo.stock)
+	assert.Equal(t, StockMovementPending, repo.movements[0].Status)
+}
+
+func TestReverseStockMovement_FailedRecordRestoresStock(t *testing.T) {
+	original := &StockMovement{
+		ID:        primitive.NewObjectID(),
+		TenantID:  testTenantID,
+		ProductID: testProductID,
+		Type:      StockMovementOut,
+		Reason:    StockMovementReason("sale"),
+		Quantity:  3,
+		CreatedAt: time.Now(),
+	}
+	repo := &mockStockRepo{stock: 7, movements: []*StockMovement{original}}
+	svc := NewService(repo, nil, nil, nil, &config.Config{StockReversalWindowHours: 24})
+
+	repo.createErr = errors.New("write failed")
+	_, err := svc.ReverseStockMovement(context.Background(), original.ID.Hex(), &ReverseStockMovementDTO{}, testTenantID, testUserID)
+	assert.Error(t, err)
+	assert.Equal(t, 7, repo.stock, "the stock change must be undone")
+	assert.False(t, original.Reversed, "the claim must be released so the reversal can be retried")
+
+	repo.createErr = nil
+	reversal, err := svc.ReverseStockMovement(context.Background(), original.ID.Hex(), &ReverseStockMovementDTO{}, testTenantID, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, StockMovementIn, reversal.Type)
+	assert.Equal(t, 10, repo.stock)
+}
+
+// mockReorderRepo serves a fixed list of low-stock products with no recent usage
+type mockReorderRepo struct {
+	ProductRepository
+	products []Product
+}
+
+func (m *mockReorderRepo) FindLowStockProducts(ctx context.Context, tenantID primitive.ObjectID) ([]Product, error) {
+	return m.products, nil
+}
+
+func (m *mockReorderRepo) SumStockOutSince(ctx context.Context, tenantID primitive.ObjectID, productIDs []primitive.ObjectID, since time.Time) (map[primitive.ObjectID]int, error) {
+	return map[primitive.ObjectID]int{}, nil
+}
+
+// A supplier's products priced in different currencies must not have their
+// minor units added together.
+func TestGetReorderSuggestions_GroupsByCurrency(t *testing.T) {
+	supplierID := primitive.NewObjectID()
+	repo := &mockReorderRepo{products: []Product{
+		{ID: primitive.NewObjectID(), SupplierID: supplierID, MinStock: 5, ReorderQuantity: 10, PurchasePrice: 150000, Currency: "COP"},
+		{ID: primitive.NewObjectID(), SupplierID: supplierID, MinStock: 5, ReorderQuantity: 10, PurchasePrice: 250, Currency: "USD"},
+		{ID: primitive.NewObjectID(), SupplierID: supplierID, MinStock: 5, ReorderQuantity: 10, PurchasePrice: 50000, Currency: "COP"},
+	}}
+	svc := NewService(repo, nil, nil, nil, nil)
+
+	groups, err := svc.GetReorderSuggestions(context.Background(), testTenantID, 30, 30)
+
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "COP", groups[0].EstimatedCost.Currency)
+	assert.Equal(t, int64(15*150000+15*50000), groups[0].EstimatedCost.Minor)
+	assert.Len(t, groups[0].Items, 2)
+	assert.Equal(t, "USD", groups[1].EstimatedCost.Currency)
+	assert.Equal(t, int64(15*250), groups[1].EstimatedCost.Minor)
+	assert.Equal(t, supplierID.Hex(), groups[1].SupplierID)
+}
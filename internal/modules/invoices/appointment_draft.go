@@ -0,0 +1,118 @@
+package invoices
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/medical_records"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// AppointmentRecordReader finds the medical records written during an
+// appointment, implemented by medical_records.MedicalRecordRepository
+type AppointmentRecordReader interface {
+	FindByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) ([]medical_records.MedicalRecord, error)
+}
+
+// ProductReader loads dispensed products for their name and sale price,
+// implemented by inventory.ProductRepository
+type ProductReader interface {
+	FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*inventory.Product, error)
+}
+
+// AppointmentDraft is a completed appointment to bill. Fee is the price of
+// its type in the clinic's currency; zero leaves the visit itself off the bill.
+type AppointmentDraft struct {
+	AppointmentID primitive.ObjectID
+	OwnerID       primitive.ObjectID
+	PatientID     primitive.ObjectID
+	TypeName      string
+	Fee           float64
+}
+
+// WithAppointmentSources lets the service add the products dispensed during
+// an appointment to its draft invoice
+func (s *Service) WithAppointmentSources(records AppointmentRecordReader, products ProductReader) *Service {
+	s.records = records
+	s.products = products
+	return s
+}
+
+// DraftForAppointment creates a draft invoice for a completed appointment with
+// its fee and the products dispensed during it, for staff to review and issue.
+// It returns nil when the appointment already has an invoice or there is
+// nothing to bill.
+func (s *Service) DraftForAppointment(ctx context.Context, tenantID, createdBy primitive.ObjectID, draft *AppointmentDraft) (*Invoice, error) {
+	exists, err := s.repo.ExistsForAppointment(ctx, draft.AppointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, nil
+	}
+
+	var items []CreateInvoiceItemDTO
+	if draft.Fee > 0 {
+		items = append(items, CreateInvoiceItemDTO{Description: draft.TypeName, Quantity: 1, UnitPrice: draft.Fee})
+	}
+
+	dispensed, err := s.dispensedItems(ctx, tenantID, draft.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, dispensed...)
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	return s.CreateInvoice(ctx, &CreateInvoiceDTO{
+		OwnerID:       draft.OwnerID.Hex(),
+		PatientID:     draft.PatientID.Hex(),
+		AppointmentID: draft.AppointmentID.Hex(),
+		Items:         items,
+	}, tenantID, createdBy)
+}
+
+// dispensedItems bills the products dispensed on the appointment's records at
+// their current sale price, one line per product. Products that can no longer
+// be loaded are left for staff to add by hand.
+func (s *Service) dispensedItems(ctx context.Context, tenantID, appointmentID primitive.ObjectID) ([]CreateInvoiceItemDTO, error) {
+	if s.records == nil || s.products == nil {
+		return nil, nil
+	}
+
+	records, err := s.records.FindByAppointment(ctx, appointmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []primitive.ObjectID
+	quantities := make(map[primitive.ObjectID]int)
+	for _, record := range records {
+		for _, d := range record.DispensedProducts {
+			if _, seen := quantities[d.ProductID]; !seen {
+				order = append(order, d.ProductID)
+			}
+			quantities[d.ProductID] += d.Quantity
+		}
+	}
+
+	items := make([]CreateInvoiceItemDTO, 0, len(order))
+	for _, productID := range order {
+		product, err := s.products.FindByID(ctx, productID, tenantID)
+		if err != nil {
+			slog.Warn("failed to load dispensed product for invoice draft", "appointment_id", appointmentID.Hex(), "product_id", productID.Hex(), "error", err)
+			continue
+		}
+		items = append(items, CreateInvoiceItemDTO{
+			Description: product.Name,
+			Quantity:    float64(quantities[productID]),
+			UnitPrice:   money.ToMajor(product.SalePrice, product.Currency),
+		})
+	}
+	return items, nil
+}
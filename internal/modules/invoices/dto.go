@@ -0,0 +1,108 @@
+package invoices
+
+import (
+	"time"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// CreateInvoiceItemDTO represents a line of a new invoice. UnitPrice is in
+// major units of the clinic currency (12500.50) and stored as minor units.
+type CreateInvoiceItemDTO struct {
+	Description string  `json:"description" binding:"required,min=2,max=200"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+	UnitPrice   float64 `json:"unit_price" binding:"gte=0"`
+}
+
+// CreateInvoiceDTO represents the request to create an invoice
+type CreateInvoiceDTO struct {
+	OwnerID       string                 `json:"owner_id" binding:"required"`
+	PatientID     string                 `json:"patient_id,omitempty"`
+	AppointmentID string                 `json:"appointment_id,omitempty"`
+	Items         []CreateInvoiceItemDTO `json:"items" binding:"required,min=1,dive"`
+	Notes         string                 `json:"notes,omitempty" binding:"max=1000"`
+	// Issue creates the invoice directly as issued instead of draft
+	Issue bool `json:"issue,omitempty"`
+}
+
+// CreatePaymentLinkDTO represents the request to generate an online payment link
+type CreatePaymentLinkDTO struct {
+	// Provider overrides the clinic's configured payment provider
+	Provider    string `json:"provider,omitempty" binding:"omitempty,oneof=wompi stripe"`
+	RedirectURL string `json:"redirect_url,omitempty" binding:"omitempty,url"`
+}
+
+// RecordPaymentDTO represents a payment taken at the clinic. Amount is in
+// major units of the invoice currency.
+type RecordPaymentDTO struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Method string  `json:"method" binding:"required,oneof=cash card transfer"`
+	// TransactionID is the card voucher or transfer reference, if any
+	TransactionID string `json:"transaction_id,omitempty" binding:"max=100"`
+	Notes         string `json:"notes,omitempty" binding:"max=500"`
+	// AllowCredit records any amount above the balance as credit instead of rejecting the payment
+	AllowCredit bool `json:"allow_credit,omitempty"`
+}
+
+// InvoiceListFilters represents filters for listing invoices
+type InvoiceListFilters struct {
+	OwnerID string
+	Status  string
+}
+
+// InvoiceItemResponse represents an invoice line in API responses
+type InvoiceItemResponse struct {
+	Description string       `json:"description"`
+	Quantity    float64      `json:"quantity"`
+	UnitPrice   money.Amount `json:"unit_price"`
+	Total       money.Amount `json:"total"`
+}
+
+// InvoiceResponse represents an invoice in API responses
+type InvoiceResponse struct {
+	ID             string                `json:"id"`
+	Number         string                `json:"number,omitempty"`
+	TenantID       string                `json:"tenant_id"`
+	OwnerID        string                `json:"owner_id"`
+	PatientID      string                `json:"patient_id,omitempty"`
+	AppointmentID  string                `json:"appointment_id,omitempty"`
+	Items          []InvoiceItemResponse `json:"items"`
+	Currency       string                `json:"currency"`
+	Total          money.Amount          `json:"total"`
+	AmountPaid     money.Amount          `json:"amount_paid"`
+	Balance        money.Amount          `json:"balance"`
+	Credit         *money.Amount         `json:"credit,omitempty"`
+	Status         string                `json:"status"`
+	Notes          string                `json:"notes,omitempty"`
+	PaymentLinkURL string                `json:"payment_link_url,omitempty"`
+	PaidAt         *time.Time            `json:"paid_at,omitempty"`
+	IssuedAt       *time.Time            `json:"issued_at,omitempty"`
+	CreatedBy      string                `json:"created_by"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	// Payments is the ledger; only included when fetching a single invoice
+	Payments []InvoicePaymentResponse `json:"payments,omitempty"`
+}
+
+// InvoicePaymentResponse represents a ledger entry in API responses
+type InvoicePaymentResponse struct {
+	ID            string        `json:"id"`
+	Amount        money.Amount  `json:"amount"`
+	AppliedAmount money.Amount  `json:"applied_amount"`
+	CreditAmount  *money.Amount `json:"credit_amount,omitempty"`
+	Method        string        `json:"method"`
+	Provider      string        `json:"provider,omitempty"`
+	TransactionID string        `json:"transaction_id,omitempty"`
+	Notes         string        `json:"notes,omitempty"`
+	RecordedBy    string        `json:"recorded_by,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// PaymentLinkResponse represents a generated payment link
+type PaymentLinkResponse struct {
+	InvoiceID string       `json:"invoice_id"`
+	Provider  string       `json:"provider"`
+	URL       string       `json:"url"`
+	Amount    money.Amount `json:"amount"`
+	Currency  string       `json:"currency"`
+}
@@ -0,0 +1,23 @@
+package invoices
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrInvoiceNotFound        = sharedErrors.New(sharedErrors.ErrNotFound, "INVOICE_NOT_FOUND", "invoice not found")
+	ErrOwnerNotFound          = sharedErrors.New(sharedErrors.ErrNotFound, "OWNER_NOT_FOUND", "owner not found")
+	ErrInvoiceNotDraft        = sharedErrors.New(sharedErrors.ErrConflict, "INVOICE_NOT_DRAFT", "only draft invoices can be issued")
+	ErrInvoiceNotPayable      = sharedErrors.New(sharedErrors.ErrConflict, "INVOICE_NOT_PAYABLE", "only issued invoices with an outstanding balance can be paid")
+	ErrOverpayment            = sharedErrors.New(sharedErrors.ErrUnprocessable, "INVOICE_OVERPAYMENT", "payment exceeds the invoice balance, set allow_credit to record the excess as credit")
+	ErrPaymentAlreadyRecorded = sharedErrors.New(sharedErrors.ErrConflict, "INVOICE_PAYMENT_DUPLICATE", "this transaction was already recorded")
+	ErrPaymentConflict        = sharedErrors.New(sharedErrors.ErrConflict, "INVOICE_PAYMENT_CONFLICT", "invoice was updated by another payment, retry")
+	ErrPaymentLinkFailed      = sharedErrors.New(sharedErrors.ErrInternal, "PAYMENT_LINK_FAILED", "failed to create payment link")
+	ErrCurrencyNotDefined     = sharedErrors.New(sharedErrors.ErrUnprocessable, "CURRENCY_NOT_DEFINED", "clinic has no currency configured")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
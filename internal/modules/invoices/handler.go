@@ -0,0 +1,207 @@
+package invoices
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for invoices
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new invoice handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// CreateInvoice creates a new invoice
+// @Summary Create invoice
+// @Description Create an invoice for an owner. It starts as draft unless issue is true
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param invoice body CreateInvoiceDTO true "Invoice data"
+// @Success 201 {object} InvoiceResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/invoices [post]
+func (h *Handler) CreateInvoice(c *gin.Context) (any, error) {
+	var dto CreateInvoiceDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	invoice, err := h.service.CreateInvoice(c.Request.Context(), &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice.ToResponse(), nil
+}
+
+// ListInvoices lists invoices
+// @Summary List invoices
+// @Description List invoices of the clinic with optional filters
+// @Tags invoices
+// @Produce json
+// @Param owner_id query string false "Owner ID"
+// @Param status query string false "Status" Enums(draft, issued, partially_paid, paid, cancelled)
+// @Param skip query int false "Items to skip" default(0)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/invoices [get]
+func (h *Handler) ListInvoices(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	filters := InvoiceListFilters{
+		OwnerID: c.Query("owner_id"),
+		Status:  c.Query("status"),
+	}
+
+	invoices, total, err := h.service.ListInvoices(c.Request.Context(), filters, tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]InvoiceResponse, len(invoices))
+	for i, inv := range invoices {
+		data[i] = *inv.ToResponse()
+	}
+
+	return gin.H{
+		"data":       data,
+		"pagination": pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+// GetInvoice gets an invoice by ID
+// @Summary Get invoice
+// @Description Get invoice details by ID, including its payments ledger
+// @Tags invoices
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} InvoiceResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/invoices/{id} [get]
+func (h *Handler) GetInvoice(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	invoice, err := h.service.GetInvoice(c.Request.Context(), c.Param("id"), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := h.service.GetPayments(c.Request.Context(), invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := invoice.ToResponse()
+	resp.Payments = make([]InvoicePaymentResponse, len(payments))
+	for i, p := range payments {
+		resp.Payments[i] = p.ToResponse(invoice.Currency)
+	}
+	return resp, nil
+}
+
+// IssueInvoice issues a draft invoice
+// @Summary Issue invoice
+// @Description Move a draft invoice to issued so it can be paid
+// @Tags invoices
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} InvoiceResponse
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/invoices/{id}/issue [patch]
+func (h *Handler) IssueInvoice(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	invoice, err := h.service.IssueInvoice(c.Request.Context(), c.Param("id"), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice.ToResponse(), nil
+}
+
+// CreatePaymentLink generates an online payment link for an invoice
+// @Summary Create invoice payment link
+// @Description Generate a hosted checkout link (Wompi/Stripe) for an issued invoice. The provider webhook applies the charged amount to the invoice balance
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Param request body CreatePaymentLinkDTO false "Provider override and redirect URL"
+// @Success 200 {object} PaymentLinkResponse
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/invoices/{id}/payment-link [post]
+func (h *Handler) CreatePaymentLink(c *gin.Context) (any, error) {
+	var dto CreatePaymentLinkDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			return nil, validation.Validate(err)
+		}
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.CreatePaymentLink(c.Request.Context(), c.Param("id"), &dto, tenantID)
+}
+
+// RecordPayment records a payment taken at the clinic
+// @Summary Record invoice payment
+// @Description Record a full or partial payment (cash, card, transfer). The invoice becomes paid once its balance is covered; amounts above the balance require allow_credit
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Param payment body RecordPaymentDTO true "Payment data"
+// @Success 200 {object} InvoiceResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/invoices/{id}/record-payment [post]
+func (h *Handler) RecordPayment(c *gin.Context) (any, error) {
+	var dto RecordPaymentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	invoice, err := h.service.RecordPayment(c.Request.Context(), c.Param("id"), &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice.ToResponse(), nil
+}
@@ -0,0 +1,55 @@
+package invoices
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the invoices and invoice_payments collections
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("invoices").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "deleted_at", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "owner_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "appointment_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "payment_link.link_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			// Invoice numbers never repeat within a clinic; drafts have none
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"number": bson.M{"$type": "string"},
+			}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("invoice_payments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "invoice_id", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			// A provider transaction can only be applied once (webhook redeliveries)
+			Keys: bson.D{{Key: "provider", Value: 1}, {Key: "transaction_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"transaction_id": bson.M{"$exists": true},
+				"provider":       bson.M{"$exists": true},
+			}),
+		},
+	})
+	return err
+}
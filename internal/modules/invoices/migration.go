@@ -0,0 +1,96 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// MinorUnitsMigration reports what MigrateToMinorUnits converted
+type MinorUnitsMigration struct {
+	Invoices int64
+	Payments int64
+}
+
+// MigrateToMinorUnits converts invoice totals, lines and ledger entries stored
+// as decimal floats into integer minor units of each invoice's currency.
+// Invoices without a currency use money.DefaultCurrency, as they are read.
+// Only fields still stored as doubles are touched, so it is safe to run more
+// than once. It must run before deploying code that reads amounts as integers.
+func MigrateToMinorUnits(ctx context.Context, db *database.MongoDB) (*MinorUnitsMigration, error) {
+	invoices := db.Collection("invoices")
+	payments := db.Collection("invoice_payments")
+	result := &MinorUnitsMigration{}
+
+	values, err := invoices.Distinct(ctx, "currency", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	// Missing and empty currencies are migrated together with the default one
+	groups := map[string]bson.A{money.DefaultCurrency: {nil, ""}}
+	for _, v := range values {
+		if c, ok := v.(string); ok && c != "" {
+			code := money.Normalize(c)
+			groups[code] = append(groups[code], c)
+		}
+	}
+
+	for currency, codes := range groups {
+		factor := money.Factor(currency)
+		inCurrency := bson.M{"$in": codes}
+
+		for _, field := range []string{"total", "amount_paid", "credit"} {
+			res, err := invoices.UpdateMany(ctx,
+				bson.M{"currency": inCurrency, field: bson.M{"$type": "double"}},
+				mongo.Pipeline{{{Key: "$set", Value: bson.M{field: money.MinorUnitsExpr("$"+field, factor)}}}},
+			)
+			if err != nil {
+				return result, fmt.Errorf("invoices %s %s: %w", currency, field, err)
+			}
+			result.Invoices += res.ModifiedCount
+		}
+
+		_, err := invoices.UpdateMany(ctx,
+			bson.M{"currency": inCurrency, "items.unit_price": bson.M{"$type": "double"}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{
+				"items": bson.M{"$map": bson.M{
+					"input": "$items",
+					"as":    "item",
+					"in": bson.M{"$mergeObjects": bson.A{"$$item", bson.M{
+						"unit_price": money.MinorUnitsExpr("$$item.unit_price", factor),
+						"total":      money.MinorUnitsExpr("$$item.total", factor),
+					}}},
+				}},
+			}}}},
+		)
+		if err != nil {
+			return result, fmt.Errorf("invoices %s items: %w", currency, err)
+		}
+
+		// Ledger entries carry no currency; they take their invoice's
+		ids, err := invoices.Distinct(ctx, "_id", bson.M{"currency": inCurrency})
+		if err != nil {
+			return result, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		for _, field := range []string{"amount", "applied_amount", "credit_amount"} {
+			res, err := payments.UpdateMany(ctx,
+				bson.M{"invoice_id": bson.M{"$in": ids}, field: bson.M{"$type": "double"}},
+				mongo.Pipeline{{{Key: "$set", Value: bson.M{field: money.MinorUnitsExpr("$"+field, factor)}}}},
+			)
+			if err != nil {
+				return result, fmt.Errorf("invoice payments %s %s: %w", currency, field, err)
+			}
+			result.Payments += res.ModifiedCount
+		}
+	}
+
+	return result, nil
+}
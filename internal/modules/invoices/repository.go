@@ -0,0 +1,227 @@
+package invoices
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// InvoiceRepository defines the interface for invoice data access
+type InvoiceRepository interface {
+	Create(ctx context.Context, invoice *Invoice) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Invoice, error)
+	FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters InvoiceListFilters, params pagination.Params) ([]Invoice, int64, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
+	MarkIssued(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error
+	ExistsForAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (bool, error)
+
+	// Payment reconciliation. Webhooks are not tenant-scoped, so these look
+	// invoices up by the reference or link ID the provider echoes back.
+	FindForPayment(ctx context.Context, id primitive.ObjectID) (*Invoice, error)
+	FindByPaymentLinkID(ctx context.Context, linkID string) (*Invoice, error)
+
+	// Payments ledger
+	RecordPayment(ctx context.Context, previousAmountPaid int64, invoice *Invoice, entry *InvoicePayment) error
+	FindPayments(ctx context.Context, invoiceID, tenantID primitive.ObjectID) ([]InvoicePayment, error)
+}
+
+type invoiceRepository struct {
+	collection *mongo.Collection
+	payments   *mongo.Collection
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *database.MongoDB) InvoiceRepository {
+	return &invoiceRepository{
+		collection: db.Collection("invoices"),
+		payments:   db.Collection("invoice_payments"),
+	}
+}
+
+func (r *invoiceRepository) Create(ctx context.Context, invoice *Invoice) error {
+	result, err := r.collection.InsertOne(ctx, invoice)
+	if err != nil {
+		return err
+	}
+	invoice.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *invoiceRepository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Invoice, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil})
+}
+
+func (r *invoiceRepository) FindForPayment(ctx context.Context, id primitive.ObjectID) (*Invoice, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "deleted_at": nil})
+}
+
+func (r *invoiceRepository) FindByPaymentLinkID(ctx context.Context, linkID string) (*Invoice, error) {
+	return r.findOne(ctx, bson.M{"payment_link.link_id": linkID, "deleted_at": nil})
+}
+
+// ExistsForAppointment reports whether the appointment was already billed.
+// Cancelled invoices do not count, so a voided bill can be drafted again.
+func (r *invoiceRepository) ExistsForAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"tenant_id":      tenantID,
+		"appointment_id": appointmentID,
+		"status":         bson.M{"$ne": InvoiceStatusCancelled},
+		"deleted_at":     nil,
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *invoiceRepository) findOne(ctx context.Context, filter bson.M) (*Invoice, error) {
+	var invoice Invoice
+	if err := r.collection.FindOne(ctx, filter).Decode(&invoice); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters InvoiceListFilters, params pagination.Params) ([]Invoice, int64, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+	}
+
+	if filters.OwnerID != "" {
+		if ownerID, err := primitive.ObjectIDFromHex(filters.OwnerID); err == nil {
+			filter["owner_id"] = ownerID
+		}
+	}
+	if filters.Status != "" {
+		filter["status"] = filters.Status
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []Invoice
+	if err := cursor.All(ctx, &invoices); err != nil {
+		return nil, 0, err
+	}
+
+	return invoices, total, nil
+}
+
+func (r *invoiceRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+	updates["updated_at"] = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInvoiceNotFound
+	}
+	return nil
+}
+
+// MarkIssued moves a draft invoice to issued. Only one of several concurrent
+// calls for the same invoice succeeds; the others get ErrInvoiceNotDraft.
+func (r *invoiceRepository) MarkIssued(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil, "status": InvoiceStatusDraft},
+		bson.M{"$set": bson.M{"status": InvoiceStatusIssued, "issued_at": at, "updated_at": at}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrInvoiceNotDraft
+	}
+	return nil
+}
+
+// RecordPayment inserts entry into the ledger and saves the invoice totals and
+// status computed by the caller. The invoice update only applies if amount_paid
+// still equals previousAmountPaid, so concurrent payments cannot both be
+// applied against the same balance; the losing entry is removed again.
+func (r *invoiceRepository) RecordPayment(ctx context.Context, previousAmountPaid int64, invoice *Invoice, entry *InvoicePayment) error {
+	result, err := r.payments.InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrPaymentAlreadyRecorded
+		}
+		return err
+	}
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+
+	filter := bson.M{"_id": invoice.ID, "tenant_id": invoice.TenantID, "deleted_at": nil, "amount_paid": previousAmountPaid}
+	if previousAmountPaid == 0 {
+		// Invoices created before the ledger existed have no amount_paid field
+		filter["amount_paid"] = bson.M{"$in": bson.A{int64(0), nil}}
+	}
+
+	set := bson.M{
+		"amount_paid": invoice.AmountPaid,
+		"credit":      invoice.Credit,
+		"status":      invoice.Status,
+		"updated_at":  invoice.UpdatedAt,
+	}
+	if invoice.PaidAt != nil {
+		set["paid_at"] = invoice.PaidAt
+	}
+	if invoice.PaymentTransactionID != "" {
+		set["payment_transaction_id"] = invoice.PaymentTransactionID
+	}
+
+	updated, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err == nil && updated.MatchedCount == 0 {
+		err = ErrPaymentConflict
+	}
+	if err != nil {
+		if _, delErr := r.payments.DeleteOne(ctx, bson.M{"_id": entry.ID}); delErr != nil {
+			return errors.Join(err, delErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *invoiceRepository) FindPayments(ctx context.Context, invoiceID, tenantID primitive.ObjectID) ([]InvoicePayment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.payments.Find(ctx, bson.M{"invoice_id": invoiceID, "tenant_id": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	payments := []InvoicePayment{}
+	if err := cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
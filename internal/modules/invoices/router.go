@@ -0,0 +1,27 @@
+package invoices
+
+import (
+	"github.com/eren_dev/go_server/internal/modules/loyalty"
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/platform/payment"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/invoices
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, paymentManager *payment.PaymentManager) {
+	ownerRepo := owners.NewRepository(db)
+	tenantRepo := tenant.NewTenantRepository(db)
+	service := NewService(NewInvoiceRepository(db), ownerRepo, tenantRepo, paymentManager, loyalty.NewService(loyalty.NewRepository(db), ownerRepo, tenantRepo), sequences.NewService(sequences.NewRepository(db)))
+	handler := NewHandler(service)
+
+	invoices := private.Group("/invoices")
+	invoices.POST("", handler.CreateInvoice)
+	invoices.GET("", handler.ListInvoices)
+	invoices.GET("/:id", handler.GetInvoice)
+	invoices.PATCH("/:id/issue", handler.IssueInvoice)
+	invoices.POST("/:id/payment-link", handler.CreatePaymentLink)
+	invoices.POST("/:id/record-payment", handler.RecordPayment)
+}
@@ -0,0 +1,177 @@
+package invoices
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// InvoiceStatus represents the lifecycle of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft         InvoiceStatus = "draft"
+	InvoiceStatusIssued        InvoiceStatus = "issued"
+	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+	InvoiceStatusCancelled     InvoiceStatus = "cancelled"
+)
+
+// PaymentMethod represents how an invoice payment was made
+type PaymentMethod string
+
+const (
+	PaymentMethodCash     PaymentMethod = "cash"
+	PaymentMethodCard     PaymentMethod = "card"
+	PaymentMethodTransfer PaymentMethod = "transfer"
+	PaymentMethodOnline   PaymentMethod = "online"
+)
+
+// InvoiceItem is a billed line. Prices are in minor units of the invoice currency.
+type InvoiceItem struct {
+	Description string  `bson:"description"`
+	Quantity    float64 `bson:"quantity"`
+	UnitPrice   int64   `bson:"unit_price"`
+	Total       int64   `bson:"total"`
+}
+
+// InvoicePaymentLink is the hosted checkout generated for an invoice
+type InvoicePaymentLink struct {
+	Provider  string    `bson:"provider"`
+	LinkID    string    `bson:"link_id"`
+	URL       string    `bson:"url"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// Invoice represents a bill issued by a clinic to an owner. Amounts are stored
+// in minor units of Currency (see the money package).
+type Invoice struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID      primitive.ObjectID  `bson:"tenant_id"`
+	OwnerID       primitive.ObjectID  `bson:"owner_id"`
+	PatientID     *primitive.ObjectID `bson:"patient_id,omitempty"`
+	AppointmentID *primitive.ObjectID `bson:"appointment_id,omitempty"`
+	Items         []InvoiceItem       `bson:"items"`
+	Currency      string              `bson:"currency"`
+	Total         int64               `bson:"total"`
+	// AmountPaid is the part of Total covered by payments; Credit holds any
+	// excess that was explicitly accepted as credit for the owner.
+	AmountPaid int64         `bson:"amount_paid"`
+	Credit     int64         `bson:"credit,omitempty"`
+	Status     InvoiceStatus `bson:"status"`
+	Notes      string        `bson:"notes,omitempty"`
+	// Number is the consecutive invoice number, assigned when it is issued
+	Number string `bson:"number,omitempty"`
+
+	// Online payment
+	PaymentLink          *InvoicePaymentLink `bson:"payment_link,omitempty"`
+	PaymentTransactionID string              `bson:"payment_transaction_id,omitempty"`
+	PaidAt               *time.Time          `bson:"paid_at,omitempty"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by"`
+	IssuedAt  *time.Time         `bson:"issued_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// InvoicePayment is a ledger entry recording one (possibly partial) payment,
+// in minor units of the invoice currency
+type InvoicePayment struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID      primitive.ObjectID  `bson:"tenant_id"`
+	InvoiceID     primitive.ObjectID  `bson:"invoice_id"`
+	Amount        int64               `bson:"amount"`
+	AppliedAmount int64               `bson:"applied_amount"`
+	CreditAmount  int64               `bson:"credit_amount,omitempty"`
+	Method        PaymentMethod       `bson:"method"`
+	Provider      string              `bson:"provider,omitempty"`
+	TransactionID string              `bson:"transaction_id,omitempty"`
+	Notes         string              `bson:"notes,omitempty"`
+	RecordedBy    *primitive.ObjectID `bson:"recorded_by,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at"`
+}
+
+// Balance is the amount still owed on the invoice
+func (i *Invoice) Balance() int64 {
+	balance := i.Total - i.AmountPaid
+	if balance < 0 {
+		return 0
+	}
+	return balance
+}
+
+// IsPayable reports whether the invoice still accepts payments
+func (i *Invoice) IsPayable() bool {
+	return (i.Status == InvoiceStatusIssued || i.Status == InvoiceStatusPartiallyPaid) && i.Balance() > 0
+}
+
+// ToResponse converts an invoice to its API representation
+func (i *Invoice) ToResponse() *InvoiceResponse {
+	resp := &InvoiceResponse{
+		ID:         i.ID.Hex(),
+		Number:     i.Number,
+		TenantID:   i.TenantID.Hex(),
+		OwnerID:    i.OwnerID.Hex(),
+		Items:      make([]InvoiceItemResponse, len(i.Items)),
+		Currency:   i.Currency,
+		Total:      money.New(i.Total, i.Currency),
+		AmountPaid: money.New(i.AmountPaid, i.Currency),
+		Balance:    money.New(i.Balance(), i.Currency),
+		Status:     string(i.Status),
+		Notes:      i.Notes,
+		PaidAt:     i.PaidAt,
+		IssuedAt:   i.IssuedAt,
+		CreatedBy:  i.CreatedBy.Hex(),
+		CreatedAt:  i.CreatedAt,
+		UpdatedAt:  i.UpdatedAt,
+	}
+
+	if i.PatientID != nil {
+		resp.PatientID = i.PatientID.Hex()
+	}
+	if i.AppointmentID != nil {
+		resp.AppointmentID = i.AppointmentID.Hex()
+	}
+	for idx, item := range i.Items {
+		resp.Items[idx] = InvoiceItemResponse{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   money.New(item.UnitPrice, i.Currency),
+			Total:       money.New(item.Total, i.Currency),
+		}
+	}
+	if i.Credit > 0 {
+		credit := money.New(i.Credit, i.Currency)
+		resp.Credit = &credit
+	}
+	if i.PaymentLink != nil {
+		resp.PaymentLinkURL = i.PaymentLink.URL
+	}
+
+	return resp
+}
+
+// ToResponse converts a ledger entry of an invoice in currency to its API representation
+func (p *InvoicePayment) ToResponse(currency string) InvoicePaymentResponse {
+	resp := InvoicePaymentResponse{
+		ID:            p.ID.Hex(),
+		Amount:        money.New(p.Amount, currency),
+		AppliedAmount: money.New(p.AppliedAmount, currency),
+		Method:        string(p.Method),
+		Provider:      p.Provider,
+		TransactionID: p.TransactionID,
+		Notes:         p.Notes,
+		CreatedAt:     p.CreatedAt,
+	}
+	if p.CreditAmount > 0 {
+		credit := money.New(p.CreditAmount, currency)
+		resp.CreditAmount = &credit
+	}
+	if p.RecordedBy != nil {
+		resp.RecordedBy = p.RecordedBy.Hex()
+	}
+	return resp
+}
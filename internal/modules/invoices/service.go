@@ -0,0 +1,401 @@
+package invoices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/platform/payment"
+	"github.com/eren_dev/go_server/internal/shared/money"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// TenantReader loads the clinic settings (currency, payment provider)
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+// PaymentLinkCreator creates hosted checkouts, implemented by payment.PaymentManager
+type PaymentLinkCreator interface {
+	CreatePaymentLink(ctx context.Context, req *payment.PaymentLinkRequest, providerType *payment.ProviderType) (*payment.PaymentLinkResponse, error)
+}
+
+// LoyaltyAccruer grants loyalty points for paid invoices. total is in major
+// units of the invoice currency.
+type LoyaltyAccruer interface {
+	AccruePurchase(ctx context.Context, tenantID, ownerID, invoiceID primitive.ObjectID, total float64) error
+}
+
+// NumberIssuer draws consecutive document numbers, implemented by sequences.Service
+type NumberIssuer interface {
+	Next(ctx context.Context, tenantID primitive.ObjectID, name, format string, at time.Time) (string, error)
+}
+
+// Service provides business logic for invoices
+type Service struct {
+	repo       InvoiceRepository
+	ownerRepo  owners.OwnerRepository
+	tenantRepo TenantReader
+	payments   PaymentLinkCreator
+	loyalty    LoyaltyAccruer
+	numbers    NumberIssuer
+	records    AppointmentRecordReader
+	products   ProductReader
+}
+
+// NewService creates a new invoice service
+func NewService(repo InvoiceRepository, ownerRepo owners.OwnerRepository, tenantRepo TenantReader, payments PaymentLinkCreator, loyalty LoyaltyAccruer, numbers NumberIssuer) *Service {
+	return &Service{
+		repo:       repo,
+		ownerRepo:  ownerRepo,
+		tenantRepo: tenantRepo,
+		payments:   payments,
+		loyalty:    loyalty,
+		numbers:    numbers,
+	}
+}
+
+// CreateInvoice creates a draft (or issued) invoice for an owner of the clinic
+func (s *Service) CreateInvoice(ctx context.Context, dto *CreateInvoiceDTO, tenantID, createdBy primitive.ObjectID) (*Invoice, error) {
+	owner, err := s.ownerRepo.FindByID(ctx, dto.OwnerID)
+	if err != nil || !owner.BelongsToTenant(tenantID) {
+		return nil, ErrOwnerNotFound
+	}
+
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invoice := &Invoice{
+		TenantID:  tenantID,
+		OwnerID:   owner.ID,
+		Items:     make([]InvoiceItem, len(dto.Items)),
+		Currency:  t.Currency,
+		Status:    InvoiceStatusDraft,
+		Notes:     dto.Notes,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if dto.PatientID != "" {
+		patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
+		if err != nil {
+			return nil, ErrValidation("patient_id", "invalid patient ID format")
+		}
+		invoice.PatientID = &patientID
+	}
+	if dto.AppointmentID != "" {
+		appointmentID, err := primitive.ObjectIDFromHex(dto.AppointmentID)
+		if err != nil {
+			return nil, ErrValidation("appointment_id", "invalid appointment ID format")
+		}
+		invoice.AppointmentID = &appointmentID
+	}
+
+	for i, item := range dto.Items {
+		unitPrice := money.FromMajor(item.UnitPrice, invoice.Currency)
+		lineTotal := int64(math.Round(item.Quantity * float64(unitPrice)))
+		invoice.Items[i] = InvoiceItem{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   unitPrice,
+			Total:       lineTotal,
+		}
+		invoice.Total += lineTotal
+	}
+
+	if dto.Issue {
+		number, err := s.numbers.Next(ctx, tenantID, sequences.Invoice, t.Settings.InvoiceNumberFormat, now)
+		if err != nil {
+			return nil, err
+		}
+		invoice.Number = number
+		invoice.Status = InvoiceStatusIssued
+		invoice.IssuedAt = &now
+	}
+
+	if err := s.repo.Create(ctx, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetInvoice gets an invoice by ID
+func (s *Service) GetInvoice(ctx context.Context, id string, tenantID primitive.ObjectID) (*Invoice, error) {
+	invoiceID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid invoice ID format")
+	}
+	return s.repo.FindByID(ctx, invoiceID, tenantID)
+}
+
+// ListInvoices lists invoices with filters
+func (s *Service) ListInvoices(ctx context.Context, filters InvoiceListFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Invoice, int64, error) {
+	return s.repo.FindByFilters(ctx, tenantID, filters, params)
+}
+
+// IssueInvoice moves a draft invoice to issued, making it payable
+func (s *Service) IssueInvoice(ctx context.Context, id string, tenantID primitive.ObjectID) (*Invoice, error) {
+	invoice, err := s.GetInvoice(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != InvoiceStatusDraft {
+		return nil, ErrInvoiceNotDraft
+	}
+
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	// The number is drawn only after winning the draft-to-issued transition,
+	// so concurrent issue requests cannot burn numbers and leave gaps
+	now := time.Now()
+	if err := s.repo.MarkIssued(ctx, invoice.ID, tenantID, now); err != nil {
+		return nil, err
+	}
+	number, err := s.numbers.Next(ctx, tenantID, sequences.Invoice, t.Settings.InvoiceNumberFormat, now)
+	if err != nil {
+		s.returnToDraft(ctx, invoice.ID, tenantID)
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, invoice.ID, bson.M{"number": number}, tenantID); err != nil {
+		// The number is lost, but an issued invoice without one must not remain
+		slog.Error("invoice number drawn but not stored", "invoice_id", invoice.ID.Hex(), "number", number, "error", err)
+		s.returnToDraft(ctx, invoice.ID, tenantID)
+		return nil, err
+	}
+
+	invoice.Number = number
+	invoice.Status = InvoiceStatusIssued
+	invoice.IssuedAt = &now
+	invoice.UpdatedAt = now
+	return invoice, nil
+}
+
+// returnToDraft undoes MarkIssued when issuing fails halfway
+func (s *Service) returnToDraft(ctx context.Context, id, tenantID primitive.ObjectID) {
+	if err := s.repo.Update(ctx, id, bson.M{"status": InvoiceStatusDraft, "issued_at": nil}, tenantID); err != nil {
+		slog.Error("failed to return invoice to draft", "invoice_id", id.Hex(), "error", err)
+	}
+}
+
+// GetPayments returns the payments ledger of an invoice, oldest first
+func (s *Service) GetPayments(ctx context.Context, invoice *Invoice) ([]InvoicePayment, error) {
+	return s.repo.FindPayments(ctx, invoice.ID, invoice.TenantID)
+}
+
+// RecordPayment registers a payment taken at the clinic (cash, card terminal,
+// bank transfer). Amounts above the balance are rejected unless dto.AllowCredit
+// is set, in which case the excess is kept as credit for the owner.
+func (s *Service) RecordPayment(ctx context.Context, id string, dto *RecordPaymentDTO, tenantID, recordedBy primitive.ObjectID) (*Invoice, error) {
+	invoice, err := s.GetInvoice(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := money.FromMajor(dto.Amount, invoice.Currency)
+	if amount <= 0 {
+		return nil, ErrValidation("amount", "amount is below the smallest unit of the invoice currency")
+	}
+
+	entry := &InvoicePayment{
+		Method:        PaymentMethod(dto.Method),
+		TransactionID: dto.TransactionID,
+		Notes:         dto.Notes,
+		RecordedBy:    &recordedBy,
+	}
+	if err := s.applyPayment(ctx, invoice, entry, amount, dto.AllowCredit); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// applyPayment splits amount, in minor units, between the outstanding balance and credit,
+// records the ledger entry and updates invoice in place. The invoice only
+// becomes paid once the balance is fully covered.
+func (s *Service) applyPayment(ctx context.Context, invoice *Invoice, entry *InvoicePayment, amount int64, allowCredit bool) error {
+	// A paid invoice can still receive money as credit, e.g. a link paid twice
+	acceptsCredit := allowCredit && invoice.Status == InvoiceStatusPaid
+	if !invoice.IsPayable() && !acceptsCredit {
+		return ErrInvoiceNotPayable
+	}
+
+	applied, credit := amount, int64(0)
+	if balance := invoice.Balance(); amount > balance {
+		if !allowCredit {
+			return ErrOverpayment
+		}
+		applied, credit = balance, amount-balance
+	}
+
+	now := time.Now()
+	entry.TenantID = invoice.TenantID
+	entry.InvoiceID = invoice.ID
+	entry.Amount = amount
+	entry.AppliedAmount = applied
+	entry.CreditAmount = credit
+	entry.CreatedAt = now
+
+	updated := *invoice
+	updated.AmountPaid = invoice.AmountPaid + applied
+	updated.Credit = invoice.Credit + credit
+	updated.UpdatedAt = now
+	if updated.Status != InvoiceStatusPaid {
+		updated.Status = InvoiceStatusPartiallyPaid
+		if updated.Balance() == 0 {
+			updated.Status = InvoiceStatusPaid
+			updated.PaidAt = &now
+		}
+	}
+	if entry.Provider != "" {
+		updated.PaymentTransactionID = entry.TransactionID
+	}
+
+	if err := s.repo.RecordPayment(ctx, invoice.AmountPaid, &updated, entry); err != nil {
+		return err
+	}
+	becamePaid := invoice.Status != InvoiceStatusPaid && updated.Status == InvoiceStatusPaid
+	*invoice = updated
+
+	if becamePaid {
+		if err := s.loyalty.AccruePurchase(ctx, invoice.TenantID, invoice.OwnerID, invoice.ID, money.ToMajor(invoice.Total, invoice.Currency)); err != nil {
+			slog.Error("failed to accrue loyalty points", "invoice_id", invoice.ID.Hex(), "error", err)
+		}
+	}
+	return nil
+}
+
+// CreatePaymentLink generates a hosted checkout for the invoice balance using
+// the provider in dto, else the clinic's configured provider, else the
+// server default.
+func (s *Service) CreatePaymentLink(ctx context.Context, id string, dto *CreatePaymentLinkDTO, tenantID primitive.ObjectID) (*PaymentLinkResponse, error) {
+	invoice, err := s.GetInvoice(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.IsPayable() {
+		return nil, ErrInvoiceNotPayable
+	}
+	if invoice.Currency == "" {
+		return nil, ErrCurrencyNotDefined
+	}
+
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	var providerType *payment.ProviderType
+	switch {
+	case dto.Provider != "":
+		p := payment.ProviderType(dto.Provider)
+		providerType = &p
+	case t.Settings.PaymentProvider != "":
+		p := payment.ProviderType(t.Settings.PaymentProvider)
+		providerType = &p
+	}
+
+	req := &payment.PaymentLinkRequest{
+		TenantID:    tenantID.Hex(),
+		Reference:   invoice.ID.Hex(),
+		Description: fmt.Sprintf("Factura %s - %s", invoice.ID.Hex(), t.Name),
+		Amount:      invoice.Balance(),
+		Currency:    invoice.Currency,
+		RedirectURL: dto.RedirectURL,
+	}
+	if owner, err := s.ownerRepo.FindByID(ctx, invoice.OwnerID.Hex()); err == nil {
+		req.CustomerEmail = owner.Email
+	}
+
+	link, err := s.payments.CreatePaymentLink(ctx, req, providerType)
+	if err != nil {
+		slog.Error("failed to create invoice payment link", "invoice_id", id, "error", err)
+		return nil, ErrPaymentLinkFailed
+	}
+
+	paymentLink := InvoicePaymentLink{
+		Provider:  string(link.Provider),
+		LinkID:    link.LinkID,
+		URL:       link.URL,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Update(ctx, invoice.ID, bson.M{"payment_link": paymentLink}, tenantID); err != nil {
+		return nil, err
+	}
+
+	return &PaymentLinkResponse{
+		InvoiceID: invoice.ID.Hex(),
+		Provider:  paymentLink.Provider,
+		URL:       paymentLink.URL,
+		Amount:    money.New(invoice.Balance(), invoice.Currency),
+		Currency:  invoice.Currency,
+	}, nil
+}
+
+// ApplyPayment records a successful payment webhook against the referenced
+// invoice. The charged amount is applied to the balance and anything above it
+// is kept as credit, since the money has already been collected. Events
+// without a positive amount leave the invoice unchanged. It reports whether
+// the event belonged to an invoice at all, so the caller can fall back to
+// subscription handling otherwise.
+func (s *Service) ApplyPayment(ctx context.Context, event *payment.WebhookEvent) (bool, error) {
+	invoice := s.findInvoiceForEvent(ctx, event)
+	if invoice == nil {
+		return false, nil
+	}
+
+	// Provider amounts are in minor units of the charged currency. An event
+	// without one cannot be reconciled, so it is left for staff to record by hand
+	// rather than assumed to cover the balance.
+	amount := event.Amount
+	if amount <= 0 {
+		slog.Warn("invoice payment event without amount ignored", "invoice_id", invoice.ID.Hex(), "provider", event.Provider, "transaction_id", event.TransactionID, "amount", event.Amount)
+		return true, nil
+	}
+
+	entry := &InvoicePayment{
+		Method:        PaymentMethodOnline,
+		Provider:      string(event.Provider),
+		TransactionID: event.TransactionID,
+	}
+	err := s.applyPayment(ctx, invoice, entry, amount, true)
+	switch {
+	case errors.Is(err, ErrPaymentAlreadyRecorded):
+		slog.Info("invoice payment already recorded", "invoice_id", invoice.ID.Hex(), "transaction_id", event.TransactionID)
+		return true, nil
+	case errors.Is(err, ErrInvoiceNotPayable):
+		slog.Warn("payment received for invoice that is not payable", "invoice_id", invoice.ID.Hex(), "status", invoice.Status, "transaction_id", event.TransactionID)
+		return true, nil
+	}
+	return true, err
+}
+
+func (s *Service) findInvoiceForEvent(ctx context.Context, event *payment.WebhookEvent) *Invoice {
+	if event.Reference != "" {
+		if id, err := primitive.ObjectIDFromHex(event.Reference); err == nil {
+			if invoice, err := s.repo.FindForPayment(ctx, id); err == nil {
+				return invoice
+			}
+		}
+	}
+	if event.PaymentLinkID != "" {
+		if invoice, err := s.repo.FindByPaymentLinkID(ctx, event.PaymentLinkID); err == nil {
+			return invoice
+		}
+	}
+	return nil
+}
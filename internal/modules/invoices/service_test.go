@@ -0,0 +1,84 @@
+package invoices
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/platform/payment"
+)
+
+// mockInvoiceRepo stores invoices in memory; methods the tests do not use
+// panic through the embedded nil interface.
+type mockInvoiceRepo struct {
+	InvoiceRepository
+	invoices map[primitive.ObjectID]*Invoice
+	payments []*InvoicePayment
+}
+
+func (m *mockInvoiceRepo) FindForPayment(ctx context.Context, id primitive.ObjectID) (*Invoice, error) {
+	invoice, ok := m.invoices[id]
+	if !ok {
+		return nil, ErrInvoiceNotFound
+	}
+	copied := *invoice
+	return &copied, nil
+}
+
+func (m *mockInvoiceRepo) RecordPayment(ctx context.Context, previousAmountPaid int64, invoice *Invoice, entry *InvoicePayment) error {
+	m.payments = append(m.payments, entry)
+	m.invoices[invoice.ID] = invoice
+	return nil
+}
+
+func newIssuedInvoice(total int64) *Invoice {
+	return &Invoice{
+		ID:       primitive.NewObjectID(),
+		TenantID: primitive.NewObjectID(),
+		OwnerID:  primitive.NewObjectID(),
+		Currency: "COP",
+		Total:    total,
+		Status:   InvoiceStatusIssued,
+	}
+}
+
+// Some provider events carry no amount; they must not be taken as payment of
+// the whole balance.
+func TestApplyPayment_ZeroAmountLeavesInvoiceUnchanged(t *testing.T) {
+	invoice := newIssuedInvoice(4500000)
+	repo := &mockInvoiceRepo{invoices: map[primitive.ObjectID]*Invoice{invoice.ID: invoice}}
+	service := NewService(repo, nil, nil, nil, nil, nil)
+
+	handled, err := service.ApplyPayment(context.Background(), &payment.WebhookEvent{
+		Provider:      payment.ProviderWompi,
+		TransactionID: "tx-1",
+		Reference:     invoice.ID.Hex(),
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, handled, "the event belongs to the invoice")
+	assert.Empty(t, repo.payments, "no payment must be recorded")
+	assert.Equal(t, InvoiceStatusIssued, repo.invoices[invoice.ID].Status)
+	assert.Zero(t, repo.invoices[invoice.ID].AmountPaid)
+}
+
+func TestApplyPayment_PartialAmount(t *testing.T) {
+	invoice := newIssuedInvoice(4500000)
+	repo := &mockInvoiceRepo{invoices: map[primitive.ObjectID]*Invoice{invoice.ID: invoice}}
+	service := NewService(repo, nil, nil, nil, nil, nil)
+
+	handled, err := service.ApplyPayment(context.Background(), &payment.WebhookEvent{
+		Provider:      payment.ProviderWompi,
+		TransactionID: "tx-1",
+		Amount:        2000000,
+		Reference:     invoice.ID.Hex(),
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, handled)
+	assert.Len(t, repo.payments, 1)
+	assert.Equal(t, InvoiceStatusPartiallyPaid, repo.invoices[invoice.ID].Status)
+	assert.Equal(t, int64(2500000), repo.invoices[invoice.ID].Balance())
+}
@@ -6,6 +6,8 @@ type CreateLabOrderDTO struct {
 	VeterinarianID string  `json:"veterinarian_id" binding:"required"`
 	LabID          string  `json:"lab_id"`
 	TestType       string  `json:"test_type" binding:"required,oneof=blood urine biopsy stool skin ear other"`
+	LabTestID      string  `json:"lab_test_id"` // Optional catalog test; its turnaround sets the due date
+	HoldFromOwner  bool    `json:"hold_from_owner"` // Don't notify the owner until a vet releases the results
 	Notes          string  `json:"notes" max:"500"`
 	Cost           float64 `json:"cost" binding:"omitempty,min=0"`
 }
@@ -13,6 +15,7 @@ type CreateLabOrderDTO struct {
 // UpdateLabOrderDTO represents the request to update a lab order
 type UpdateLabOrderDTO struct {
 	LabID     string  `json:"lab_id"`
+	HoldFromOwner *bool `json:"hold_from_owner"` // false releases held results to the owner
 	TestType  string  `json:"test_type" oneof=blood urine biopsy stool skin ear other"`
 	Notes     string  `json:"notes" max:"500"`
 	Cost      float64 `json:"cost" binding:"omitempty,min=0"`
@@ -2,7 +2,8 @@ package laboratory
 
 import (
 	"errors"
-	"fmt"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
 )
 
 // Module errors
@@ -20,40 +21,14 @@ var (
 	ErrResultRequired         = errors.New("result file required for processed status")
 )
 
-// ValidationError represents a validation error
-type ValidationError struct {
-	Field   string
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
-}
-
 // ErrValidation creates a new validation error
 func ErrValidation(field, message string) error {
-	return &ValidationError{
-		Field:   field,
-		Message: message,
-	}
-}
-
-// BusinessError represents a business logic error
-type BusinessError struct {
-	Code    string
-	Message string
-}
-
-func (e *BusinessError) Error() string {
-	return fmt.Sprintf("business error: %s - %s", e.Code, e.Message)
+	return sharedErrors.Validation(field, message)
 }
 
 // ErrBusiness creates a new business error
 func ErrBusiness(code, message string) error {
-	return &BusinessError{
-		Code:    code,
-		Message: message,
-	}
+	return sharedErrors.Business(code, message)
 }
 
 // Specific business errors
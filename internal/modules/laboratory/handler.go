@@ -309,7 +309,7 @@ func (h *Handler) GetPatientLabOrders(c *gin.Context) (any, error) {
 
 // GetOverdueLabOrders gets overdue lab orders
 // @Summary Get overdue lab orders
-// @Description Get lab orders that are past their turnaround time
+// @Description Get lab orders that are past their due date (order date + test turnaround)
 // @Tags laboratory
 // @Accept json
 // @Produce json
@@ -319,8 +319,7 @@ func (h *Handler) GetPatientLabOrders(c *gin.Context) (any, error) {
 func (h *Handler) GetOverdueLabOrders(c *gin.Context) (any, error) {
 	tenantID := sharedMiddleware.GetTenantID(c)
 
-	// Default turnaround time: 5 days
-	orders, err := h.service.GetOverdueLabOrders(c.Request.Context(), tenantID, 5)
+	orders, err := h.service.GetOverdueLabOrders(c.Request.Context(), tenantID)
 	if err != nil {
 		return nil, err
 	}
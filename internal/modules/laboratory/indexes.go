@@ -30,6 +30,10 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		{
 			Keys: bson.D{{"test_type", 1}, {"order_date", -1}},
 		},
+		// Overdue / SLA breach lookups
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "due_date", Value: 1}},
+		},
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
@@ -27,8 +27,10 @@ type LabOrderRepository interface {
 	UpdateStatus(ctx context.Context, id primitive.ObjectID, status LabOrderStatus, tenantID primitive.ObjectID) error
 
 	// Alerts
-	FindOverdueOrders(ctx context.Context, tenantID primitive.ObjectID, turnaroundDays int) ([]LabOrder, error)
+	FindOverdueOrders(ctx context.Context, tenantID primitive.ObjectID) ([]LabOrder, error)
 	FindReadyForPickup(ctx context.Context, tenantID primitive.ObjectID) ([]LabOrder, error)
+	FindSLABreaches(ctx context.Context, now time.Time) ([]LabOrder, error)
+	MarkSLAAlerted(ctx context.Context, id primitive.ObjectID, at time.Time) error
 
 	// Lab Test Catalog CRUD
 	CreateLabTest(ctx context.Context, test *LabTest) error
@@ -164,6 +166,12 @@ func (r *labOrderRepository) FindByFilters(ctx context.Context, tenantID primiti
 		}
 	}
 
+	if filters.Overdue {
+		for k, v := range overdueFilter(time.Now()) {
+			filter[k] = v
+		}
+	}
+
 	// Count total
 	total, err := r.ordersCollection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -270,17 +278,29 @@ func (r *labOrderRepository) UpdateStatus(ctx context.Context, id primitive.Obje
 	return nil
 }
 
-func (r *labOrderRepository) FindOverdueOrders(ctx context.Context, tenantID primitive.ObjectID, turnaroundDays int) ([]LabOrder, error) {
-	// Find orders that are not processed and are past their turnaround time
-	filter := bson.M{
-		"tenant_id":  tenantID,
+// overdueFilter matches unprocessed orders whose due date has passed. Orders
+// stored before due_date existed are compared against the default turnaround.
+func overdueFilter(now time.Time) bson.M {
+	return bson.M{
 		"deleted_at": nil,
-		"status": bson.M{
-			"$nin": []string{string(LabOrderStatusProcessed)},
+		"status":     bson.M{"$ne": LabOrderStatusProcessed},
+		"$or": []bson.M{
+			{"due_date": bson.M{"$lt": now}},
+			{
+				"due_date":   bson.M{"$exists": false},
+				"order_date": bson.M{"$lt": now.AddDate(0, 0, -DefaultTurnaroundDays)},
+			},
 		},
 	}
+}
 
-	cursor, err := r.ordersCollection.Find(ctx, filter)
+func (r *labOrderRepository) FindOverdueOrders(ctx context.Context, tenantID primitive.ObjectID) ([]LabOrder, error) {
+	filter := overdueFilter(time.Now())
+	filter["tenant_id"] = tenantID
+
+	opts := options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}})
+
+	cursor, err := r.ordersCollection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -291,15 +311,35 @@ func (r *labOrderRepository) FindOverdueOrders(ctx context.Context, tenantID pri
 		return nil, err
 	}
 
-	// Filter orders that are actually overdue
-	overdueOrders := make([]LabOrder, 0)
-	for _, o := range orders {
-		if o.IsOverdue(turnaroundDays) {
-			overdueOrders = append(overdueOrders, o)
-		}
+	return orders, nil
+}
+
+// FindSLABreaches returns overdue orders across all tenants that have not been alerted yet
+func (r *labOrderRepository) FindSLABreaches(ctx context.Context, now time.Time) ([]LabOrder, error) {
+	filter := overdueFilter(now)
+	filter["sla_alerted_at"] = nil
+
+	cursor, err := r.ordersCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orders []LabOrder
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
 	}
 
-	return overdueOrders, nil
+	return orders, nil
+}
+
+func (r *labOrderRepository) MarkSLAAlerted(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.ordersCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"sla_alerted_at": at}},
+	)
+	return err
 }
 
 func (r *labOrderRepository) FindReadyForPickup(ctx context.Context, tenantID primitive.ObjectID) ([]LabOrder, error) {
@@ -456,6 +496,9 @@ func (r *labOrderRepository) EnsureIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{"test_type", 1}, {"order_date", -1}},
 		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "status", Value: 1}, {Key: "due_date", Value: 1}},
+		},
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
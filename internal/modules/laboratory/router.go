@@ -20,6 +20,7 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil,
 	)
@@ -29,7 +30,7 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 		log.Printf("failed to ensure indexes for laboratory: %v", err)
 	}
 
-	service := NewService(repo, patientRepo, userRepo, notifSvc)
+	service := NewService(repo, patientRepo, userRepo, owners.NewRepository(db), notifSvc)
 	handler := NewHandler(service)
 
 	// Lab Orders routes
@@ -61,11 +62,12 @@ func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil,
 	)
 
-	service := NewService(repo, patientRepo, userRepo, notifSvc)
+	service := NewService(repo, patientRepo, userRepo, owners.NewRepository(db), notifSvc)
 	handler := NewHandler(service)
 
 	// Mobile routes - read only for owners
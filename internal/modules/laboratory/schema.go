@@ -50,6 +50,10 @@ func IsValidLabTestType(t string) bool {
 	return false
 }
 
+// DefaultTurnaroundDays is used when an order is not linked to a catalog test
+// (or the test has no turnaround configured).
+const DefaultTurnaroundDays = 5
+
 // LabOrder represents a laboratory order
 type LabOrder struct {
 	ID            primitive.ObjectID `bson:"_id" json:"id"`
@@ -66,6 +70,13 @@ type LabOrder struct {
 	ResultFileID  string             `bson:"result_file_id,omitempty" json:"result_file_id,omitempty"` // Resource ID
 	Notes         string             `bson:"notes,omitempty" json:"notes,omitempty"`
 	Cost          float64            `bson:"cost,omitempty" json:"cost,omitempty"`
+	LabTestID     *primitive.ObjectID `bson:"lab_test_id,omitempty" json:"lab_test_id,omitempty"` // Catalog test, if any
+	TurnaroundDays int               `bson:"turnaround_days" json:"turnaround_days"`
+	DueDate       time.Time          `bson:"due_date" json:"due_date"` // order_date + turnaround_days
+	SLAAlertedAt  *time.Time         `bson:"sla_alerted_at,omitempty" json:"sla_alerted_at,omitempty"`
+	// HoldFromOwner keeps results from the owner until a vet has reviewed them
+	HoldFromOwner   bool       `bson:"hold_from_owner" json:"hold_from_owner"`
+	OwnerNotifiedAt *time.Time `bson:"owner_notified_at,omitempty" json:"owner_notified_at,omitempty"`
 	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
 	DeletedAt     *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
@@ -86,10 +97,18 @@ func (o *LabOrder) ToResponse() *LabOrderResponse {
 		ResultFileID:   o.ResultFileID,
 		Notes:          o.Notes,
 		Cost:           o.Cost,
+		TurnaroundDays: o.TurnaroundDays,
+		ExpectedCompletionDate: o.ExpectedCompletionDate(),
+		Overdue:        o.IsOverdue(),
+		HoldFromOwner:  o.HoldFromOwner,
 		CreatedAt:      o.CreatedAt,
 		UpdatedAt:      o.UpdatedAt,
 	}
 
+	if o.LabTestID != nil {
+		resp.LabTestID = o.LabTestID.Hex()
+	}
+
 	if o.CollectionDate != nil {
 		resp.CollectionDate = o.CollectionDate.Format(time.RFC3339)
 	}
@@ -101,13 +120,29 @@ func (o *LabOrder) ToResponse() *LabOrderResponse {
 	return resp
 }
 
-// IsOverdue checks if the order is overdue based on turnaround time
-func (o *LabOrder) IsOverdue(turnaroundDays int) bool {
+// ExpectedCompletionDate returns when results are due. Orders created before
+// due dates were stored fall back to the default turnaround.
+func (o *LabOrder) ExpectedCompletionDate() time.Time {
+	if !o.DueDate.IsZero() {
+		return o.DueDate
+	}
+	return o.OrderDate.AddDate(0, 0, DefaultTurnaroundDays)
+}
+
+// IsOverdue checks if the order has passed its due date without being processed
+func (o *LabOrder) IsOverdue() bool {
 	if o.Status == LabOrderStatusProcessed {
 		return false
 	}
-	overdueThreshold := o.OrderDate.AddDate(0, 0, turnaroundDays)
-	return time.Now().After(overdueThreshold)
+	return time.Now().After(o.ExpectedCompletionDate())
+}
+
+// DaysOverdue returns the number of whole days past the due date (0 if not overdue)
+func (o *LabOrder) DaysOverdue() int {
+	if !o.IsOverdue() {
+		return 0
+	}
+	return int(time.Since(o.ExpectedCompletionDate()).Hours() / 24)
 }
 
 // DaysSinceOrder returns the number of days since the order was created
@@ -132,6 +167,11 @@ type LabOrderResponse struct {
 	ResultFileID   string    `json:"result_file_id,omitempty"`
 	Notes          string    `json:"notes,omitempty"`
 	Cost           float64   `json:"cost,omitempty"`
+	LabTestID      string    `json:"lab_test_id,omitempty"`
+	TurnaroundDays int       `json:"turnaround_days"`
+	ExpectedCompletionDate time.Time `json:"expected_completion_date"`
+	Overdue        bool      `json:"overdue"`
+	HoldFromOwner  bool      `json:"hold_from_owner"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -144,7 +184,7 @@ type LabTest struct {
 	Description    string             `bson:"description,omitempty" json:"description,omitempty"`
 	Category       string             `bson:"category" json:"category"` // hematology, biochemistry, urinalysis, etc.
 	Price          float64            `bson:"price" json:"price"`
-	TurnaroundTime int                `bson:"turnaround_time" json:"turnaround_time"` // days
+	TurnaroundTime int                `bson:"turnaround_time" json:"turnaround_time"` // expected days until results
 	Active         bool               `bson:"active" json:"active"`
 	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
@@ -9,8 +9,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/patients"
 	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/platform/logger"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
@@ -18,6 +20,7 @@ import (
 type NotificationSender interface {
 	Send(ctx context.Context, dto *notifications.SendDTO) error
 	SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error
+	RecordFailedStaffSend(ctx context.Context, dto *notifications.SendStaffDTO, source string, err error)
 }
 
 // PatientRepository defines the interface for patient data access
@@ -30,20 +33,27 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id string) (*users.User, error)
 }
 
+// OwnerRepository defines the interface for owner data access
+type OwnerRepository interface {
+	FindByID(ctx context.Context, id string) (*owners.Owner, error)
+}
+
 // Service provides business logic for laboratory
 type Service struct {
 	repo            LabOrderRepository
 	patientRepo     PatientRepository
 	userRepo        UserRepository
+	ownerRepo       OwnerRepository
 	notificationSvc NotificationSender
 }
 
 // NewService creates a new laboratory service
-func NewService(repo LabOrderRepository, patientRepo PatientRepository, userRepo UserRepository, notificationSvc NotificationSender) *Service {
+func NewService(repo LabOrderRepository, patientRepo PatientRepository, userRepo UserRepository, ownerRepo OwnerRepository, notificationSvc NotificationSender) *Service {
 	return &Service{
 		repo:            repo,
 		patientRepo:     patientRepo,
 		userRepo:        userRepo,
+		ownerRepo:       ownerRepo,
 		notificationSvc: notificationSvc,
 	}
 }
@@ -78,6 +88,24 @@ func (s *Service) CreateLabOrder(ctx context.Context, dto *CreateLabOrderDTO, te
 		return nil, ErrValidation("test_type", "invalid test type")
 	}
 
+	// Resolve turnaround from the catalog test, if one was given
+	turnaroundDays := DefaultTurnaroundDays
+	var labTestID *primitive.ObjectID
+	if dto.LabTestID != "" {
+		testID, err := primitive.ObjectIDFromHex(dto.LabTestID)
+		if err != nil {
+			return nil, ErrValidation("lab_test_id", "invalid lab test ID format")
+		}
+		labTest, err := s.repo.FindLabTestByID(ctx, testID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if labTest.TurnaroundTime > 0 {
+			turnaroundDays = labTest.TurnaroundTime
+		}
+		labTestID = &testID
+	}
+
 	// Create lab order
 	now := time.Now()
 	order := &LabOrder{
@@ -92,6 +120,10 @@ func (s *Service) CreateLabOrder(ctx context.Context, dto *CreateLabOrderDTO, te
 		Status:         LabOrderStatusPending,
 		Notes:          dto.Notes,
 		Cost:           dto.Cost,
+		LabTestID:      labTestID,
+		TurnaroundDays: turnaroundDays,
+		HoldFromOwner:  dto.HoldFromOwner,
+		DueDate:        now.AddDate(0, 0, turnaroundDays),
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -155,13 +187,17 @@ func (s *Service) UpdateLabOrder(ctx context.Context, id string, dto *UpdateLabO
 		return nil, ErrValidation("id", "invalid order ID format")
 	}
 
-	_, err = s.repo.FindByID(ctx, orderID, tenantID)
+	order, err := s.repo.FindByID(ctx, orderID, tenantID)
 	if err != nil {
 		return nil, err
 	}
 
 	updates := bson.M{}
 
+	if dto.HoldFromOwner != nil {
+		updates["hold_from_owner"] = *dto.HoldFromOwner
+	}
+
 	if dto.LabID != "" {
 		updates["lab_id"] = dto.LabID
 	}
@@ -190,6 +226,11 @@ func (s *Service) UpdateLabOrder(ctx context.Context, id string, dto *UpdateLabO
 		return nil, err
 	}
 
+	// Releasing a held order tells the owner about results that are already in
+	if order.HoldFromOwner && !updatedOrder.HoldFromOwner {
+		s.notifyResultReady(ctx, updatedOrder)
+	}
+
 	return updatedOrder, nil
 }
 
@@ -216,35 +257,15 @@ func (s *Service) UpdateLabOrderStatus(ctx context.Context, id string, dto *Upda
 		return nil, err
 	}
 
-	// Send notification if status is processed
-	if newStatus == LabOrderStatusProcessed {
-		updatedOrder, err := s.repo.FindByID(ctx, orderID, tenantID)
-		if err != nil {
-			return nil, err
-		}
-
-		patient, err := s.patientRepo.FindByID(ctx, tenantID, updatedOrder.PatientID.Hex())
-		if err == nil {
-			s.notificationSvc.Send(ctx, &notifications.SendDTO{
-				OwnerID:  patient.OwnerID.Hex(),
-				TenantID: tenantID.Hex(),
-				Type:     notifications.TypeGeneral,
-				Title:    "Resultados de Laboratorio Listos",
-				Body:     fmt.Sprintf("Los resultados de %s de %s están listos", updatedOrder.TestType, patient.Name),
-				Data: map[string]string{
-					"order_id":   updatedOrder.ID.Hex(),
-					"patient_id": updatedOrder.PatientID.Hex(),
-				},
-				SendPush: true,
-			})
-		}
-	}
-
 	updatedOrder, err := s.repo.FindByID(ctx, orderID, tenantID)
 	if err != nil {
 		return nil, err
 	}
 
+	if newStatus == LabOrderStatusProcessed {
+		s.notifyResultReady(ctx, updatedOrder)
+	}
+
 	return updatedOrder, nil
 }
 
@@ -282,9 +303,64 @@ func (s *Service) UploadLabResult(ctx context.Context, id string, dto *UploadLab
 		return nil, err
 	}
 
+	s.notifyResultReady(ctx, updatedOrder)
+
 	return updatedOrder, nil
 }
 
+// notifyResultReady tells the owner that results are available. It only fires
+// once, for processed orders with a result file that are not held for vet review,
+// and only if the owner has not muted lab result notifications.
+func (s *Service) notifyResultReady(ctx context.Context, order *LabOrder) {
+	if order.Status != LabOrderStatusProcessed || order.ResultFileID == "" {
+		return
+	}
+	if order.HoldFromOwner || order.OwnerNotifiedAt != nil {
+		return
+	}
+
+	patient, err := s.patientRepo.FindByID(ctx, order.TenantID, order.PatientID.Hex())
+	if err != nil {
+		logger.Default().Error(ctx, "lab_result_notify_failed", "order_id", order.ID.Hex(), "step", "patient", "error", err)
+		return
+	}
+
+	if s.ownerRepo != nil {
+		owner, err := s.ownerRepo.FindByID(ctx, patient.OwnerID.Hex())
+		if err != nil {
+			logger.Default().Error(ctx, "lab_result_notify_failed", "order_id", order.ID.Hex(), "step", "owner", "error", err)
+			return
+		}
+		if !owner.NotificationPrefs.Allows(string(notifications.TypeLabResultReady)) {
+			return
+		}
+	}
+
+	err = s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		OwnerID:  patient.OwnerID.Hex(),
+		TenantID: order.TenantID.Hex(),
+		Type:     notifications.TypeLabResultReady,
+		Template: notifications.TemplateLabResultReady,
+		Vars:     map[string]string{"test_type": string(order.TestType), "patient_name": patient.Name},
+		Data: map[string]string{
+			"order_id":       order.ID.Hex(),
+			"patient_id":     order.PatientID.Hex(),
+			"result_file_id": order.ResultFileID,
+			"deep_link":      fmt.Sprintf("/mobile/lab-orders/patient/%s?order_id=%s", order.PatientID.Hex(), order.ID.Hex()),
+		},
+		SendPush: true,
+	})
+	if err != nil {
+		logger.Default().Error(ctx, "lab_result_notify_failed", "order_id", order.ID.Hex(), "step", "send", "error", err)
+		return
+	}
+
+	if err := s.repo.Update(ctx, order.ID, bson.M{"owner_notified_at": time.Now()}, order.TenantID); err != nil {
+		// The owner may be notified again on the next update of the order
+		logger.Default().Error(ctx, "lab_result_notified_at_update_failed", "order_id", order.ID.Hex(), "error", err)
+	}
+}
+
 // DeleteLabOrder soft deletes a lab order
 func (s *Service) DeleteLabOrder(ctx context.Context, id string, tenantID primitive.ObjectID) error {
 	orderID, err := primitive.ObjectIDFromHex(id)
@@ -295,23 +371,23 @@ func (s *Service) DeleteLabOrder(ctx context.Context, id string, tenantID primit
 	return s.repo.Delete(ctx, orderID, tenantID)
 }
 
-// GetOverdueLabOrders gets overdue lab orders
-func (s *Service) GetOverdueLabOrders(ctx context.Context, tenantID primitive.ObjectID, turnaroundDays int) ([]LabOrder, error) {
-	return s.repo.FindOverdueOrders(ctx, tenantID, turnaroundDays)
+// GetOverdueLabOrders gets lab orders past their due date
+func (s *Service) GetOverdueLabOrders(ctx context.Context, tenantID primitive.ObjectID) ([]LabOrder, error) {
+	return s.repo.FindOverdueOrders(ctx, tenantID)
 }
 
 // SendOverdueReminders sends reminders for overdue lab orders
-func (s *Service) SendOverdueReminders(ctx context.Context, tenantID primitive.ObjectID, turnaroundDays int) error {
-	orders, err := s.GetOverdueLabOrders(ctx, tenantID, turnaroundDays)
+func (s *Service) SendOverdueReminders(ctx context.Context, tenantID primitive.ObjectID) error {
+	orders, err := s.GetOverdueLabOrders(ctx, tenantID)
 	if err != nil {
 		return err
 	}
 
 	for _, o := range orders {
-		daysOverdue := o.DaysSinceOrder() - turnaroundDays
+		daysOverdue := o.DaysOverdue()
 
 		// Send to staff
-		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		alert := &notifications.SendStaffDTO{
 			UserID:   primitive.NilObjectID.Hex(), // Broadcast to all staff
 			TenantID: tenantID.Hex(),
 			Type:     notifications.TypeStaffSystemAlert,
@@ -322,7 +398,10 @@ func (s *Service) SendOverdueReminders(ctx context.Context, tenantID primitive.O
 				"patient_id":     o.PatientID.Hex(),
 				"days_overdue":   fmt.Sprintf("%d", daysOverdue),
 			},
-		})
+		}
+		if err := s.notificationSvc.SendToStaff(ctx, alert); err != nil {
+			s.notificationSvc.RecordFailedStaffSend(ctx, alert, "lab_overdue", err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,33 @@
+package loyalty
+
+import (
+	"time"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// RedeemPointsDTO represents a redemption made at the clinic
+type RedeemPointsDTO struct {
+	Points      int    `json:"points" binding:"required,gt=0"`
+	Description string `json:"description,omitempty" binding:"max=200"`
+}
+
+// TransactionResponse represents a ledger entry in API responses
+type TransactionResponse struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Source       string    `json:"source"`
+	SourceID     string    `json:"source_id,omitempty"`
+	Points       int       `json:"points"`
+	BalanceAfter int       `json:"balance_after"`
+	Description  string    `json:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BalanceResponse is an owner's balance at a clinic with a page of the ledger, newest first
+type BalanceResponse struct {
+	OwnerID    string                    `json:"owner_id"`
+	Balance    int                       `json:"balance"`
+	History    []TransactionResponse     `json:"history"`
+	Pagination pagination.PaginationInfo `json:"pagination"`
+}
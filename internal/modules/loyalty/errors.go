@@ -0,0 +1,17 @@
+package loyalty
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrOwnerNotFound      = sharedErrors.New(sharedErrors.ErrNotFound, "OWNER_NOT_FOUND", "owner not found")
+	ErrInsufficientPoints = sharedErrors.New(sharedErrors.ErrUnprocessable, "INSUFFICIENT_POINTS", "owner does not have enough loyalty points")
+	ErrAlreadyAccrued     = sharedErrors.New(sharedErrors.ErrConflict, "POINTS_ALREADY_ACCRUED", "points were already accrued for this source")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
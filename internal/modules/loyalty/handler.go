@@ -0,0 +1,100 @@
+package loyalty
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for the loyalty program
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new loyalty handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// GetOwnerBalance gets an owner's loyalty balance
+// @Summary Get owner loyalty balance
+// @Description Get the points balance of an owner at the clinic and its ledger, newest first
+// @Tags loyalty
+// @Produce json
+// @Param owner_id path string true "Owner ID"
+// @Param skip query int false "Items to skip" default(0)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} BalanceResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/loyalty/{owner_id} [get]
+func (h *Handler) GetOwnerBalance(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.GetOwnerBalance(c.Request.Context(), c.Param("owner_id"), tenantID, params)
+}
+
+// RedeemPoints deducts points from an owner's balance
+// @Summary Redeem loyalty points
+// @Description Deduct points from an owner's balance, e.g. for a discount or a gift
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param owner_id path string true "Owner ID"
+// @Param redemption body RedeemPointsDTO true "Points to redeem"
+// @Success 200 {object} TransactionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/loyalty/{owner_id}/redeem [post]
+func (h *Handler) RedeemPoints(c *gin.Context) (any, error) {
+	var dto RedeemPointsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	tx, err := h.service.Redeem(c.Request.Context(), c.Param("owner_id"), &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return tx.ToResponse(), nil
+}
+
+// GetMyBalance gets the authenticated owner's loyalty balance
+// @Summary Get my loyalty points
+// @Description Get the authenticated owner's points balance at the clinic and its history
+// @Tags mobile-loyalty
+// @Produce json
+// @Param skip query int false "Items to skip" default(0)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} BalanceResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security MobileBearerAuth
+// @Router /mobile/loyalty [get]
+func (h *Handler) GetMyBalance(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+
+	ownerID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("owner_id", "invalid owner ID format")
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.GetBalance(c.Request.Context(), ownerID, tenantID, params)
+}
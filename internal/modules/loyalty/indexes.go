@@ -0,0 +1,28 @@
+package loyalty
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the loyalty_transactions collection
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("loyalty_transactions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			// An appointment or invoice accrues points only once
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "source", Value: 1}, {Key: "source_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"source_id": bson.M{"$exists": true},
+			}),
+		},
+	})
+	return err
+}
@@ -0,0 +1,122 @@
+package loyalty
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// Repository defines data access for loyalty balances and their ledger.
+// Balances live on the owner document, keyed by clinic, because an owner can
+// be a client of several clinics with independent programs.
+type Repository interface {
+	GetBalance(ctx context.Context, ownerID, tenantID primitive.ObjectID) (int, error)
+	AdjustBalance(ctx context.Context, ownerID, tenantID primitive.ObjectID, delta int) (int, error)
+	CreateTransaction(ctx context.Context, tx *Transaction) error
+	FindTransactions(ctx context.Context, ownerID, tenantID primitive.ObjectID, params pagination.Params) ([]Transaction, int64, error)
+}
+
+type repository struct {
+	owners       *mongo.Collection
+	transactions *mongo.Collection
+}
+
+// NewRepository creates a new loyalty repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		owners:       db.Collection("owners"),
+		transactions: db.Collection("loyalty_transactions"),
+	}
+}
+
+func balanceField(tenantID primitive.ObjectID) string {
+	return "loyalty_points." + tenantID.Hex()
+}
+
+func (r *repository) GetBalance(ctx context.Context, ownerID, tenantID primitive.ObjectID) (int, error) {
+	var doc struct {
+		Points map[string]int `bson:"loyalty_points"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{balanceField(tenantID): 1})
+	if err := r.owners.FindOne(ctx, bson.M{"_id": ownerID, "deleted_at": nil}, opts).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, ErrOwnerNotFound
+		}
+		return 0, err
+	}
+	return doc.Points[tenantID.Hex()], nil
+}
+
+// AdjustBalance atomically adds delta to the owner's balance and returns the
+// new balance. Negative deltas only apply when the balance covers them,
+// otherwise ErrInsufficientPoints is returned and nothing changes.
+func (r *repository) AdjustBalance(ctx context.Context, ownerID, tenantID primitive.ObjectID, delta int) (int, error) {
+	field := balanceField(tenantID)
+	filter := bson.M{"_id": ownerID, "deleted_at": nil}
+	if delta < 0 {
+		filter[field] = bson.M{"$gte": -delta}
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{field: 1})
+
+	var doc struct {
+		Points map[string]int `bson:"loyalty_points"`
+	}
+	err := r.owners.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{field: delta}}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if delta < 0 {
+				return 0, ErrInsufficientPoints
+			}
+			return 0, ErrOwnerNotFound
+		}
+		return 0, err
+	}
+	return doc.Points[tenantID.Hex()], nil
+}
+
+func (r *repository) CreateTransaction(ctx context.Context, tx *Transaction) error {
+	result, err := r.transactions.InsertOne(ctx, tx)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyAccrued
+		}
+		return err
+	}
+	tx.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *repository) FindTransactions(ctx context.Context, ownerID, tenantID primitive.ObjectID, params pagination.Params) ([]Transaction, int64, error) {
+	filter := bson.M{"owner_id": ownerID, "tenant_id": tenantID}
+
+	total, err := r.transactions.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.transactions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	transactions := []Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
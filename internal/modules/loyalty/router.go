@@ -0,0 +1,25 @@
+package loyalty
+
+import (
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/loyalty
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db), owners.NewRepository(db), tenant.NewTenantRepository(db)))
+
+	l := private.Group("/loyalty")
+	l.GET("/:owner_id", handler.GetOwnerBalance)
+	l.POST("/:owner_id/redeem", handler.RedeemPoints)
+}
+
+// RegisterMobileRoutes registers mobile (owner-facing) routes under /mobile/loyalty
+func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db), owners.NewRepository(db), tenant.NewTenantRepository(db)))
+
+	m := mobile.Group("/loyalty")
+	m.GET("", handler.GetMyBalance)
+}
@@ -0,0 +1,58 @@
+package loyalty
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransactionType distinguishes points earned from points spent
+type TransactionType string
+
+const (
+	TransactionAccrual    TransactionType = "accrual"
+	TransactionRedemption TransactionType = "redemption"
+)
+
+// Source identifies what generated a ledger entry
+type Source string
+
+const (
+	SourceAppointment Source = "appointment"
+	SourceInvoice     Source = "invoice"
+	SourceRedemption  Source = "redemption"
+)
+
+// Transaction is an entry of the loyalty ledger. Points is positive for
+// accruals and negative for redemptions; BalanceAfter is the owner's balance
+// at the clinic once the entry was applied.
+type Transaction struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID     primitive.ObjectID  `bson:"tenant_id"`
+	OwnerID      primitive.ObjectID  `bson:"owner_id"`
+	Type         TransactionType     `bson:"type"`
+	Source       Source              `bson:"source"`
+	SourceID     *primitive.ObjectID `bson:"source_id,omitempty"`
+	Points       int                 `bson:"points"`
+	BalanceAfter int                 `bson:"balance_after"`
+	Description  string              `bson:"description,omitempty"`
+	CreatedBy    *primitive.ObjectID `bson:"created_by,omitempty"`
+	CreatedAt    time.Time           `bson:"created_at"`
+}
+
+// ToResponse converts a ledger entry to its API representation
+func (t *Transaction) ToResponse() TransactionResponse {
+	resp := TransactionResponse{
+		ID:           t.ID.Hex(),
+		Type:         string(t.Type),
+		Source:       string(t.Source),
+		Points:       t.Points,
+		BalanceAfter: t.BalanceAfter,
+		Description:  t.Description,
+		CreatedAt:    t.CreatedAt,
+	}
+	if t.SourceID != nil {
+		resp.SourceID = t.SourceID.Hex()
+	}
+	return resp
+}
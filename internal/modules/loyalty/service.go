@@ -0,0 +1,181 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// TenantReader loads the clinic's loyalty rules
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+// Service provides business logic for the loyalty program
+type Service struct {
+	repo       Repository
+	ownerRepo  owners.OwnerRepository
+	tenantRepo TenantReader
+}
+
+// NewService creates a new loyalty service
+func NewService(repo Repository, ownerRepo owners.OwnerRepository, tenantRepo TenantReader) *Service {
+	return &Service{
+		repo:       repo,
+		ownerRepo:  ownerRepo,
+		tenantRepo: tenantRepo,
+	}
+}
+
+// rules returns the clinic's loyalty settings, or nil when accrual is off
+func (s *Service) rules(ctx context.Context, tenantID primitive.ObjectID) *tenant.LoyaltySettings {
+	t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex())
+	if err != nil || !t.Settings.Loyalty.Enabled {
+		return nil
+	}
+	return &t.Settings.Loyalty
+}
+
+// AccrueVisit grants the clinic's per-visit points for a completed
+// appointment. Callers must only invoke it on completion: cancelled and
+// no-show appointments never earn points.
+func (s *Service) AccrueVisit(ctx context.Context, tenantID, ownerID, appointmentID primitive.ObjectID) error {
+	rules := s.rules(ctx, tenantID)
+	if rules == nil || rules.PointsPerVisit <= 0 {
+		return nil
+	}
+	return s.accrue(ctx, tenantID, ownerID, SourceAppointment, appointmentID, rules.PointsPerVisit, "Cita completada")
+}
+
+// AccruePurchase grants points for a fully paid invoice, proportional to its total
+func (s *Service) AccruePurchase(ctx context.Context, tenantID, ownerID, invoiceID primitive.ObjectID, total float64) error {
+	rules := s.rules(ctx, tenantID)
+	if rules == nil || rules.PointsPerCurrencyUnit <= 0 {
+		return nil
+	}
+	points := int(math.Floor(total * rules.PointsPerCurrencyUnit))
+	if points <= 0 {
+		return nil
+	}
+	return s.accrue(ctx, tenantID, ownerID, SourceInvoice, invoiceID, points, "Factura pagada")
+}
+
+// accrue credits points and records them in the ledger. The ledger's unique
+// source index makes repeated calls for the same appointment or invoice a no-op.
+func (s *Service) accrue(ctx context.Context, tenantID, ownerID primitive.ObjectID, source Source, sourceID primitive.ObjectID, points int, description string) error {
+	balance, err := s.repo.AdjustBalance(ctx, ownerID, tenantID, points)
+	if err != nil {
+		return err
+	}
+
+	tx := &Transaction{
+		TenantID:     tenantID,
+		OwnerID:      ownerID,
+		Type:         TransactionAccrual,
+		Source:       source,
+		SourceID:     &sourceID,
+		Points:       points,
+		BalanceAfter: balance,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateTransaction(ctx, tx); err != nil {
+		// Roll the balance back so it always matches the ledger
+		if _, revertErr := s.repo.AdjustBalance(ctx, ownerID, tenantID, -points); revertErr != nil {
+			slog.Error("failed to revert loyalty accrual", "owner_id", ownerID.Hex(), "points", points, "error", revertErr)
+		}
+		if errors.Is(err, ErrAlreadyAccrued) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Redeem deducts points from an owner's balance at the clinic
+func (s *Service) Redeem(ctx context.Context, ownerID string, dto *RedeemPointsDTO, tenantID, redeemedBy primitive.ObjectID) (*Transaction, error) {
+	owner, err := s.findOwner(ctx, ownerID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.repo.AdjustBalance(ctx, owner.ID, tenantID, -dto.Points)
+	if err != nil {
+		return nil, err
+	}
+
+	description := dto.Description
+	if description == "" {
+		description = fmt.Sprintf("Redención de %d puntos", dto.Points)
+	}
+
+	tx := &Transaction{
+		TenantID:     tenantID,
+		OwnerID:      owner.ID,
+		Type:         TransactionRedemption,
+		Source:       SourceRedemption,
+		Points:       -dto.Points,
+		BalanceAfter: balance,
+		Description:  description,
+		CreatedBy:    &redeemedBy,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// GetOwnerBalance returns the balance and ledger of an owner of the clinic (staff view)
+func (s *Service) GetOwnerBalance(ctx context.Context, ownerID string, tenantID primitive.ObjectID, params pagination.Params) (*BalanceResponse, error) {
+	owner, err := s.findOwner(ctx, ownerID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetBalance(ctx, owner.ID, tenantID, params)
+}
+
+// GetBalance returns an owner's balance at the clinic with a page of its ledger
+func (s *Service) GetBalance(ctx context.Context, ownerID, tenantID primitive.ObjectID, params pagination.Params) (*BalanceResponse, error) {
+	balance, err := s.repo.GetBalance(ctx, ownerID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, total, err := s.repo.FindTransactions(ctx, ownerID, tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		history[i] = tx.ToResponse()
+	}
+
+	return &BalanceResponse{
+		OwnerID:    ownerID.Hex(),
+		Balance:    balance,
+		History:    history,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+func (s *Service) findOwner(ctx context.Context, ownerID string, tenantID primitive.ObjectID) (*owners.Owner, error) {
+	if _, err := primitive.ObjectIDFromHex(ownerID); err != nil {
+		return nil, ErrValidation("owner_id", "invalid owner ID format")
+	}
+	owner, err := s.ownerRepo.FindByID(ctx, ownerID)
+	if err != nil || !owner.BelongsToTenant(tenantID) {
+		return nil, ErrOwnerNotFound
+	}
+	return owner, nil
+}
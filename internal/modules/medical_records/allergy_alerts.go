@@ -0,0 +1,84 @@
+package medical_records
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/patients"
+)
+
+// StaffResolver resolves the clinic's current staff holding any of the named roles
+type StaffResolver interface {
+	MemberIDs(ctx context.Context, tenantID primitive.ObjectID, roleNames []string) ([]primitive.ObjectID, error)
+}
+
+// severeAllergyRecipients resolves who must see a severe allergy alert: the
+// roles and users the clinic configured plus the vets of the patient's
+// upcoming appointments. Lookup failures are logged and skipped so the alert
+// still goes out; an empty result means the caller broadcasts.
+func (s *Service) severeAllergyRecipients(ctx context.Context, tenantID, patientID primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool)
+	var recipients []primitive.ObjectID
+	add := func(ids []primitive.ObjectID) {
+		for _, id := range ids {
+			if !id.IsZero() && !seen[id] {
+				seen[id] = true
+				recipients = append(recipients, id)
+			}
+		}
+	}
+
+	if s.tenants != nil {
+		t, err := s.tenants.FindByID(ctx, tenantID.Hex())
+		if err != nil {
+			slog.Warn("failed to load severe allergy alert recipients", "tenant_id", tenantID.Hex(), "error", err)
+		} else {
+			cfg := t.Settings.SevereAllergyAlerts
+			if len(cfg.Roles) > 0 && s.staff != nil {
+				ids, err := s.staff.MemberIDs(ctx, tenantID, cfg.Roles)
+				if err != nil {
+					slog.Warn("failed to resolve severe allergy alert roles", "tenant_id", tenantID.Hex(), "error", err)
+				}
+				add(ids)
+			}
+			add(cfg.UserIDs)
+		}
+	}
+
+	vetIDs, err := s.repo.FindUpcomingVeterinarians(ctx, patientID, tenantID, time.Now())
+	if err != nil {
+		slog.Warn("failed to find vets of upcoming appointments", "patient_id", patientID.Hex(), "error", err)
+	}
+	add(vetIDs)
+
+	return recipients
+}
+
+// alertSevereAllergy notifies the resolved recipients of a new severe
+// allergy, falling back to all staff when nobody specific resolves
+func (s *Service) alertSevereAllergy(ctx context.Context, allergy *Allergy, patient *patients.Patient) {
+	recipients := s.severeAllergyRecipients(ctx, allergy.TenantID, patient.ID)
+	if len(recipients) == 0 {
+		recipients = []primitive.ObjectID{primitive.NilObjectID} // Broadcast to all staff
+	}
+
+	for _, userID := range recipients {
+		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+			UserID:   userID.Hex(),
+			TenantID: allergy.TenantID.Hex(),
+			Type:     notifications.TypeStaffSystemAlert,
+			Title:    "Alerta: Alergia Severa Registrada",
+			Body:     "El paciente " + patient.Name + " tiene una nueva alergia severa: " + allergy.Allergen,
+			Data: map[string]string{
+				"allergy_id": allergy.ID.Hex(),
+				"patient_id": patient.ID.Hex(),
+				"allergen":   allergy.Allergen,
+				"severity":   string(allergy.Severity),
+			},
+		})
+	}
+}
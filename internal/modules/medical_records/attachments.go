@@ -0,0 +1,30 @@
+package medical_records
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// checkAttachmentLimit rejects attachment lists longer than the clinic allows.
+// The error lists the attachments past the limit so the client knows which to
+// drop.
+func (s *Service) checkAttachmentLimit(ctx context.Context, attachmentIDs []string, tenantID primitive.ObjectID) error {
+	limit := tenant.DefaultMaxRecordAttachments
+	if s.tenants != nil {
+		t, err := s.tenants.FindByID(ctx, tenantID.Hex())
+		if err != nil {
+			slog.Warn("failed to load tenant settings, using default attachment limit", "tenant_id", tenantID.Hex(), "error", err)
+		} else {
+			limit = t.Settings.RecordAttachmentLimit()
+		}
+	}
+
+	if len(attachmentIDs) <= limit {
+		return nil
+	}
+	return ErrTooManyAttachments(limit, attachmentIDs[limit:])
+}
@@ -9,8 +9,11 @@ type CreateMedicalRecordDTO struct {
 	PatientID      string       `json:"patient_id" binding:"required"`
 	VeterinarianID string       `json:"veterinarian_id" binding:"required"`
 	AppointmentID  string       `json:"appointment_id"`
-	Type           string       `json:"type" binding:"required,oneof=consultation emergency surgery checkup vaccination"`
-	ChiefComplaint string       `json:"chief_complaint" binding:"required,min=1,max=500"`
+	// Template to start from; its defaults fill whatever the request leaves out
+	TemplateID     string       `json:"template_id"`
+	// Type and chief complaint are required unless the template provides them
+	Type           string       `json:"type" binding:"omitempty,oneof=consultation emergency surgery checkup vaccination"`
+	ChiefComplaint string       `json:"chief_complaint" binding:"omitempty,max=500"`
 	Diagnosis      string       `json:"diagnosis" max:"2000"`
 	Symptoms       string       `json:"symptoms" max:"1000"`
 	Weight         float64      `json:"weight" binding:"omitempty,min=0"`
@@ -20,6 +23,48 @@ type CreateMedicalRecordDTO struct {
 	EvolutionNotes string       `json:"evolution_notes" max:"2000"`
 	AttachmentIDs  []string     `json:"attachment_ids"`
 	NextVisitDate  string       `json:"next_visit_date"` // RFC3339
+	// BookNextVisit puts the next visit on the calendar with the record's vet,
+	// or in the request queue when the vet is not available then
+	BookNextVisit bool `json:"book_next_visit"`
+	// NextVisitType is the appointment type of the next visit, checkup by default
+	NextVisitType string `json:"next_visit_type" binding:"omitempty,max=50"`
+	// Products handed out during the visit; each one is deducted from inventory
+	DispensedProducts []DispensedProductDTO `json:"dispensed_products" binding:"omitempty,dive"`
+	// Referral to an external vet, if the patient is being sent to a specialist
+	Referral *ReferralDTO `json:"referral,omitempty"`
+	// Checklist answers; items are matched to the template's by key
+	Checklist []ChecklistItemDTO `json:"checklist" binding:"omitempty,dive"`
+}
+
+// ChecklistItemDTO is a checklist answer of a medical record
+type ChecklistItemDTO struct {
+	Key     string `json:"key" binding:"required,min=1,max=50"`
+	Label   string `json:"label" binding:"max=200"`
+	Checked bool   `json:"checked"`
+	Notes   string `json:"notes" binding:"max=500"`
+}
+
+// ReferralDTO represents a referral to an external vet. The referring vet
+// defaults to the record's veterinarian.
+type ReferralDTO struct {
+	ReferringVetID   string `json:"referring_vet_id"`
+	ReceivingVet     string `json:"receiving_vet" binding:"required,min=1,max=200"`
+	ReceivingClinic  string `json:"receiving_clinic" binding:"max=200"`
+	ReceivingContact string `json:"receiving_contact" binding:"max=200"`
+	Specialty        string `json:"specialty" binding:"max=100"`
+	Reason           string `json:"reason" binding:"required,min=1,max=2000"`
+}
+
+// ReferralLetterFilters holds the referral letter options
+type ReferralLetterFilters struct {
+	// IncludeLabResults lists the patient's recent lab results with their file reference
+	IncludeLabResults bool `form:"include_lab_results"`
+}
+
+// DispensedProductDTO represents a product dispensed during a visit
+type DispensedProductDTO struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
 }
 
 // MedicationDTO represents a medication in DTOs
@@ -67,6 +112,9 @@ type UpdateMedicalRecordDTO struct {
 	EvolutionNotes string       `json:"evolution_notes" max:"2000"`
 	AttachmentIDs  []string     `json:"attachment_ids"`
 	NextVisitDate  string       `json:"next_visit_date"` // RFC3339
+	Referral       *ReferralDTO `json:"referral,omitempty"`
+	// Replaces the record's checklist when present
+	Checklist []ChecklistItemDTO `json:"checklist" binding:"omitempty,dive"`
 }
 
 // CreateAllergyDTO represents the request to create an allergy
@@ -115,6 +163,7 @@ type MedicalRecordListFilters struct {
 	DateFrom       string // RFC3339
 	DateTo         string // RFC3339
 	HasAttachments bool
+	Search         string // Full text over complaint, diagnosis, symptoms, treatment and evolution notes
 }
 
 // TimelineFilters represents filters for timeline queries
@@ -125,3 +174,78 @@ type TimelineFilters struct {
 	Limit      int
 	Skip       int
 }
+
+// ChecklistTemplateItemDTO is a check a template adds to new records
+type ChecklistTemplateItemDTO struct {
+	Key   string `json:"key" binding:"required,min=1,max=50"`
+	Label string `json:"label" binding:"required,min=1,max=200"`
+}
+
+// CreateRecordTemplateDTO represents the request to create a medical record template
+type CreateRecordTemplateDTO struct {
+	Name           string                     `json:"name" binding:"required,min=1,max=100"`
+	Type           string                     `json:"type" binding:"required,oneof=consultation emergency surgery checkup vaccination"`
+	ChiefComplaint string                     `json:"chief_complaint" binding:"max=500"`
+	Symptoms       string                     `json:"symptoms" binding:"max=1000"`
+	Treatment      string                     `json:"treatment" binding:"max=2000"`
+	Medications    []MedicationDTO            `json:"medications" binding:"omitempty,dive"`
+	Checklist      []ChecklistTemplateItemDTO `json:"checklist" binding:"omitempty,dive"`
+}
+
+// UpdateRecordTemplateDTO represents the request to update a medical record
+// template. Medications and checklist replace the stored lists when present.
+type UpdateRecordTemplateDTO struct {
+	Name           *string                    `json:"name" binding:"omitempty,min=1,max=100"`
+	Type           *string                    `json:"type" binding:"omitempty,oneof=consultation emergency surgery checkup vaccination"`
+	ChiefComplaint *string                    `json:"chief_complaint" binding:"omitempty,max=500"`
+	Symptoms       *string                    `json:"symptoms" binding:"omitempty,max=1000"`
+	Treatment      *string                    `json:"treatment" binding:"omitempty,max=2000"`
+	Medications    []MedicationDTO            `json:"medications" binding:"omitempty,dive"`
+	Checklist      []ChecklistTemplateItemDTO `json:"checklist" binding:"omitempty,dive"`
+}
+
+// RecordTemplateFilters holds the template list filters
+type RecordTemplateFilters struct {
+	Type string `form:"type" binding:"omitempty,oneof=consultation emergency surgery checkup vaccination"`
+}
+
+// RecordTemplateResponse represents a medical record template in API responses
+type RecordTemplateResponse struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Type           string          `json:"type"`
+	ChiefComplaint string          `json:"chief_complaint,omitempty"`
+	Symptoms       string          `json:"symptoms,omitempty"`
+	Treatment      string          `json:"treatment,omitempty"`
+	Medications    []Medication    `json:"medications"`
+	Checklist      []ChecklistItem `json:"checklist"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// toMedications converts medication DTOs to medications
+func toMedications(dtos []MedicationDTO) []Medication {
+	meds := make([]Medication, len(dtos))
+	for i, m := range dtos {
+		meds[i] = Medication{Name: m.Name, Dose: m.Dose, Frequency: m.Frequency, Duration: m.Duration}
+	}
+	return meds
+}
+
+// toChecklist converts checklist answers to checklist items
+func toChecklist(dtos []ChecklistItemDTO) []ChecklistItem {
+	items := make([]ChecklistItem, len(dtos))
+	for i, d := range dtos {
+		items[i] = ChecklistItem{Key: d.Key, Label: d.Label, Checked: d.Checked, Notes: d.Notes}
+	}
+	return items
+}
+
+// toTemplateChecklist converts template checks to unchecked checklist items
+func toTemplateChecklist(dtos []ChecklistTemplateItemDTO) []ChecklistItem {
+	items := make([]ChecklistItem, len(dtos))
+	for i, d := range dtos {
+		items[i] = ChecklistItem{Key: d.Key, Label: d.Label}
+	}
+	return items
+}
@@ -3,6 +3,8 @@ package medical_records
 import (
 	"errors"
 	"fmt"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
 )
 
 // Module errors
@@ -22,46 +24,40 @@ var (
 	ErrInvalidAllergySeverity   = errors.New("invalid allergy severity")
 	ErrAttachmentNotFound    = errors.New("attachment not found")
 	ErrDuplicateHistory      = errors.New("medical history already exists for this patient")
+	ErrRecordTemplateNotFound = errors.New("medical record template not found")
 )
 
-// Error types for validation
-type ValidationError struct {
-	Field   string
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
-}
-
 // ErrValidation creates a new validation error
 func ErrValidation(field, message string) error {
-	return &ValidationError{
-		Field:   field,
-		Message: message,
-	}
-}
-
-// Business logic errors
-type BusinessError struct {
-	Code    string
-	Message string
-}
-
-func (e *BusinessError) Error() string {
-	return fmt.Sprintf("business error: %s - %s", e.Code, e.Message)
+	return sharedErrors.Validation(field, message)
 }
 
 // ErrBusiness creates a new business error
 func ErrBusiness(code, message string) error {
-	return &BusinessError{
-		Code:    code,
-		Message: message,
-	}
+	return sharedErrors.Business(code, message)
 }
 
 // Specific business errors
 var (
 	ErrPatientHasActiveHospitalization = ErrBusiness("PATIENT_HOSPITALIZED", "patient is currently hospitalized")
 	ErrSevereAllergyAlert             = ErrBusiness("SEVERE_ALLERGY", "patient has severe allergies - review before proceeding")
+	ErrNoReferral                     = ErrBusiness("NO_REFERRAL", "medical record has no referral")
 )
+
+// ErrInsufficientStockForProduct reports the dispensed product that could not
+// be deducted; the record is not created and earlier lines are rolled back.
+func ErrInsufficientStockForProduct(productID string) error {
+	err := sharedErrors.New(sharedErrors.ErrConflict, "INSUFFICIENT_STOCK", "insufficient stock for dispensed product")
+	err.Field = "dispensed_products"
+	err.Details = map[string]interface{}{"product_id": productID}
+	return err
+}
+
+// ErrTooManyAttachments reports the attachments past the clinic's limit per
+// medical record.
+func ErrTooManyAttachments(limit int, rejected []string) error {
+	err := sharedErrors.New(sharedErrors.ErrInvalidInput, "TOO_MANY_ATTACHMENTS", fmt.Sprintf("a medical record can have at most %d attachments", limit))
+	err.Field = "attachment_ids"
+	err.Details = map[string]interface{}{"max_attachments": limit, "rejected_attachment_ids": rejected}
+	return err
+}
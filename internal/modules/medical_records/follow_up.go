@@ -0,0 +1,106 @@
+package medical_records
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Next visit outcomes
+const (
+	// NextVisitBooked is an appointment booked with the record's vet
+	NextVisitBooked = "booked"
+	// NextVisitLinked is an appointment the patient already had that day
+	NextVisitLinked = "linked"
+	// NextVisitQueued is an unassigned appointment in the request queue,
+	// created because the vet was not available at that time
+	NextVisitQueued = "queued"
+)
+
+// defaultNextVisitType is the appointment type of a follow-up when the
+// request does not name one
+const defaultNextVisitType = "checkup"
+
+// FollowUpBooker puts the next visit of a record on the clinic calendar,
+// implemented by appointments.Service
+type FollowUpBooker interface {
+	BookFollowUp(ctx context.Context, req FollowUpRequest) (*FollowUpBooking, error)
+	// ReleaseFollowUp removes an appointment booked for a record that could not be saved
+	ReleaseFollowUp(ctx context.Context, appointmentID, tenantID primitive.ObjectID) error
+}
+
+// FollowUpRequest is the next visit a record asks for
+type FollowUpRequest struct {
+	TenantID       primitive.ObjectID
+	RecordID       primitive.ObjectID
+	PatientID      primitive.ObjectID
+	VeterinarianID primitive.ObjectID
+	ScheduledAt    time.Time
+	Type           string
+	Reason         string
+	BookedBy       primitive.ObjectID
+}
+
+// FollowUpBooking is where the next visit ended up
+type FollowUpBooking struct {
+	AppointmentID primitive.ObjectID
+	Outcome       string
+	// Note tells staff what is left to do, e.g. place a queued visit
+	Note string
+}
+
+// WithFollowUps enables booking the next visit of new records on the calendar
+func (s *Service) WithFollowUps(followUps FollowUpBooker) *Service {
+	s.followUps = followUps
+	return s
+}
+
+// bookNextVisit books the next visit of a record about to be created, when
+// the request opted in. The record is not saved yet, so a booking that fails
+// leaves nothing behind and one that succeeds is released by the caller if
+// the record cannot be saved.
+func (s *Service) bookNextVisit(ctx context.Context, dto *CreateMedicalRecordDTO, record *MedicalRecord, userID primitive.ObjectID) (*NextVisitBooking, error) {
+	if !dto.BookNextVisit || s.followUps == nil {
+		return nil, nil
+	}
+	if record.NextVisitDate == nil {
+		return nil, ErrValidation("next_visit_date", "next visit date is required to book the next visit")
+	}
+
+	apptType := dto.NextVisitType
+	if apptType == "" {
+		apptType = defaultNextVisitType
+	}
+
+	booking, err := s.followUps.BookFollowUp(ctx, FollowUpRequest{
+		TenantID:       record.TenantID,
+		RecordID:       record.ID,
+		PatientID:      record.PatientID,
+		VeterinarianID: record.VeterinarianID,
+		ScheduledAt:    *record.NextVisitDate,
+		Type:           apptType,
+		Reason:         "Control: " + record.ChiefComplaint,
+		BookedBy:       userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &NextVisitBooking{
+		AppointmentID: booking.AppointmentID,
+		Outcome:       booking.Outcome,
+		Note:          booking.Note,
+	}, nil
+}
+
+// releaseNextVisit undoes bookNextVisit after the record failed to save. An
+// appointment that was only linked existed before and is kept.
+func (s *Service) releaseNextVisit(ctx context.Context, booking *NextVisitBooking, tenantID primitive.ObjectID) {
+	if booking == nil || booking.Outcome == NextVisitLinked {
+		return
+	}
+	if err := s.followUps.ReleaseFollowUp(ctx, booking.AppointmentID, tenantID); err != nil {
+		slog.Error("failed to release next visit appointment", "appointment_id", booking.AppointmentID.Hex(), "error", err)
+	}
+}
@@ -2,9 +2,13 @@ package medical_records
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
 	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"github.com/eren_dev/go_server/internal/shared/validation"
@@ -26,7 +30,7 @@ func NewHandler(service *Service) *Handler {
 
 // CreateMedicalRecord creates a new medical record
 // @Summary Create medical record
-// @Description Create a new medical record for a patient
+// @Description Create a new medical record for a patient. With template_id, the template's chief complaint, symptoms, treatment, medications and checklist fill whatever the request leaves out. Products listed in dispensed_products are deducted from inventory; if any line lacks stock nothing is deducted and the record is not created.
 // @Tags medical-records
 // @Accept json
 // @Produce json
@@ -34,6 +38,7 @@ func NewHandler(service *Service) *Handler {
 // @Success 201 {object} MedicalRecordResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Security BearerAuth
 // @Router /api/medical-records [post]
 func (h *Handler) CreateMedicalRecord(c *gin.Context) (any, error) {
@@ -43,8 +48,12 @@ func (h *Handler) CreateMedicalRecord(c *gin.Context) (any, error) {
 	}
 
 	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
 
-	record, err := h.service.CreateMedicalRecord(c.Request.Context(), &dto, tenantID)
+	record, err := h.service.CreateMedicalRecord(c.Request.Context(), &dto, tenantID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +68,9 @@ func (h *Handler) CreateMedicalRecord(c *gin.Context) (any, error) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Record ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} MedicalRecordResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Security BearerAuth
@@ -77,7 +88,33 @@ func (h *Handler) GetMedicalRecord(c *gin.Context) (any, error) {
 		return nil, err
 	}
 
-	return record.ToResponse(), nil
+	return &httpx.Tagged{Data: record.ToResponse(), UpdatedAt: record.UpdatedAt}, nil
+}
+
+// GetReferralLetter generates the referral letter of a medical record
+// @Summary Get referral letter
+// @Description Generate a PDF referral letter for the external vet named in the record's referral, with the patient's details, the referral reason and a summary of recent records. Recent lab results can be listed by file reference
+// @Tags medical-records
+// @Produce application/pdf
+// @Param id path string true "Record ID"
+// @Param include_lab_results query bool false "List recent lab results by file reference"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/medical-records/{id}/referral-letter [get]
+func (h *Handler) GetReferralLetter(c *gin.Context) (any, error) {
+	var filters ReferralLetterFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	letter, err := h.service.GenerateReferralLetter(c.Request.Context(), c.Param("id"), filters, sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.File{ContentType: "application/pdf", Filename: letter.Filename, Data: letter.Data}, nil
 }
 
 // ListMedicalRecords lists medical records with filters
@@ -94,6 +131,8 @@ func (h *Handler) GetMedicalRecord(c *gin.Context) (any, error) {
 // @Param date_from query string false "Filter from date (RFC3339)"
 // @Param date_to query string false "Filter to date (RFC3339)"
 // @Param has_attachments query bool false "Filter records with attachments"
+// @Param search query string false "Search chief complaint, diagnosis, symptoms, treatment and evolution notes; results are ranked by relevance unless sort is given"
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending (created_at, type)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Security BearerAuth
@@ -109,6 +148,7 @@ func (h *Handler) ListMedicalRecords(c *gin.Context) (any, error) {
 		DateFrom:       c.Query("date_from"),
 		DateTo:         c.Query("date_to"),
 		HasAttachments: c.Query("has_attachments") == "true",
+		Search:         strings.TrimSpace(c.Query("search")),
 	}
 
 	records, total, err := h.service.ListMedicalRecords(c.Request.Context(), filters, tenantID, params)
@@ -141,6 +181,7 @@ func (h *Handler) ListMedicalRecords(c *gin.Context) (any, error) {
 // @Param patient_id path string true "Patient ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending (created_at, type)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Security BearerAuth
@@ -290,6 +331,87 @@ func (h *Handler) GetPatientTimeline(c *gin.Context) (any, error) {
 	return timeline, nil
 }
 
+// ==================== MEDICAL RECORD TEMPLATES ====================
+
+// ListRecordTemplates lists the clinic's medical record templates
+// @Summary List medical record templates
+// @Description List the clinic's medical record templates, optionally only those of one visit type
+// @Tags medical-record-templates
+// @Produce json
+// @Param type query string false "Visit type" Enums(consultation, emergency, surgery, checkup, vaccination)
+// @Success 200 {array} RecordTemplateResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/medical-record-templates [get]
+func (h *Handler) ListRecordTemplates(c *gin.Context) (any, error) {
+	var filters RecordTemplateFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.ListRecordTemplates(c.Request.Context(), filters, sharedMiddleware.GetTenantID(c))
+}
+
+// CreateRecordTemplate creates a medical record template
+// @Summary Create medical record template
+// @Description Create a template with the chief complaint prompt, default medications and checklist for a visit type
+// @Tags medical-record-templates
+// @Accept json
+// @Produce json
+// @Param template body CreateRecordTemplateDTO true "Template data"
+// @Success 201 {object} RecordTemplateResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/medical-record-templates [post]
+func (h *Handler) CreateRecordTemplate(c *gin.Context) (any, error) {
+	var dto CreateRecordTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.CreateRecordTemplate(c.Request.Context(), dto, sharedMiddleware.GetTenantID(c))
+}
+
+// UpdateRecordTemplate updates a medical record template
+// @Summary Update medical record template
+// @Description Update a template. Records already created from it keep their values
+// @Tags medical-record-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param template body UpdateRecordTemplateDTO true "Updated template data"
+// @Success 200 {object} RecordTemplateResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/medical-record-templates/{id} [put]
+func (h *Handler) UpdateRecordTemplate(c *gin.Context) (any, error) {
+	var dto UpdateRecordTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.UpdateRecordTemplate(c.Request.Context(), c.Param("id"), dto, sharedMiddleware.GetTenantID(c))
+}
+
+// DeleteRecordTemplate deletes a medical record template
+// @Summary Delete medical record template
+// @Description Soft delete a template. Records already created from it keep their values
+// @Tags medical-record-templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/medical-record-templates/{id} [delete]
+func (h *Handler) DeleteRecordTemplate(c *gin.Context) (any, error) {
+	if err := h.service.DeleteRecordTemplate(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c)); err != nil {
+		return nil, err
+	}
+	return gin.H{"message": "Medical record template deleted successfully"}, nil
+}
+
 // ==================== ALLERGIES ====================
 
 // CreateAllergy creates a new allergy
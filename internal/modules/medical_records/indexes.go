@@ -31,6 +31,27 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 			Keys: bson.D{{"appointment_id", 1}},
 			Options: options.Index().SetSparse(true),
 		},
+		{
+			// Full text search of the list; the tenant prefix keeps each
+			// search within one clinic's records
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "chief_complaint", Value: "text"},
+				{Key: "diagnosis", Value: "text"},
+				{Key: "symptoms", Value: "text"},
+				{Key: "treatment", Value: "text"},
+				{Key: "evolution_notes", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("medical_records_text").
+				SetDefaultLanguage("spanish").
+				SetWeights(bson.M{"diagnosis": 5, "chief_complaint": 3, "symptoms": 2, "treatment": 1, "evolution_notes": 1}),
+		},
+		{
+			// Records whose owner notification has not reached the outbox yet
+			Keys:    bson.D{{Key: "pending_notifications.dedupe_key", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
@@ -76,5 +97,18 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		return err
 	}
 
+	// Medical record templates indexes
+	templatesIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "type", Value: 1}, {Key: "deleted_at", Value: 1}},
+		},
+	}
+
+	templatesCollection := db.Collection("medical_record_templates")
+	_, err = templatesCollection.Indexes().CreateMany(ctx, templatesIndexes, opts)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
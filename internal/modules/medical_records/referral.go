@@ -0,0 +1,229 @@
+package medical_records
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/laboratory"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/platform/pdf"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+const (
+	// referralHistoryLimit is how many earlier records the letter summarizes
+	referralHistoryLimit = 5
+	// referralLabLimit is how many recent lab results the letter can reference
+	referralLabLimit = 5
+)
+
+// LabResultReader defines the lab order lookups used by referral letters
+type LabResultReader interface {
+	FindByPatient(ctx context.Context, patientID, tenantID primitive.ObjectID, params pagination.Params) ([]laboratory.LabOrder, int64, error)
+}
+
+// TenantReader defines the tenant lookup used for the letterhead
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+// ReferralLetter is a generated referral letter
+type ReferralLetter struct {
+	Filename string
+	Data     []byte
+}
+
+// buildReferral validates a referral and resolves its referring vet,
+// defaulting to the record's veterinarian
+func (s *Service) buildReferral(ctx context.Context, dto *ReferralDTO, recordVetID primitive.ObjectID, now time.Time) (*Referral, error) {
+	referringID := recordVetID
+	if dto.ReferringVetID != "" {
+		id, err := primitive.ObjectIDFromHex(dto.ReferringVetID)
+		if err != nil {
+			return nil, ErrValidation("referral.referring_vet_id", "invalid veterinarian ID format")
+		}
+		referringID = id
+	}
+	if _, err := s.userRepo.FindByID(ctx, referringID.Hex()); err != nil {
+		return nil, ErrVeterinarianNotFound
+	}
+
+	return &Referral{
+		ReferringVetID:   referringID,
+		ReceivingVet:     dto.ReceivingVet,
+		ReceivingClinic:  dto.ReceivingClinic,
+		ReceivingContact: dto.ReceivingContact,
+		Specialty:        dto.Specialty,
+		Reason:           dto.Reason,
+		ReferredAt:       now,
+	}, nil
+}
+
+// GenerateReferralLetter renders the referral of a record as a PDF letter for
+// the receiving vet: patient details, the reason for referral, the current
+// visit, allergies and chronic conditions, and a summary of the latest
+// earlier records. Recent lab results are listed by file reference when
+// asked, so the files can be sent along with the letter.
+func (s *Service) GenerateReferralLetter(ctx context.Context, id string, filters ReferralLetterFilters, tenantID primitive.ObjectID) (*ReferralLetter, error) {
+	recordID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid record ID format")
+	}
+
+	record, err := s.repo.FindByID(ctx, recordID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Referral == nil {
+		return nil, ErrNoReferral
+	}
+	ref := record.Referral
+
+	patient, err := s.patientRepo.FindByID(ctx, tenantID, record.PatientID.Hex())
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	doc := pdf.New()
+
+	if s.tenants != nil {
+		if t, err := s.tenants.FindByID(ctx, tenantID.Hex()); err == nil {
+			name := t.CommercialName
+			if name == "" {
+				name = t.Name
+			}
+			doc.Title(name)
+			doc.Text(strings.Join(nonEmpty(t.Address, t.Phone, t.Email), " · "))
+			doc.Space()
+		}
+	}
+
+	doc.Heading("Carta de remisión")
+	doc.Field("Fecha", ref.ReferredAt.Format("02/01/2006"))
+	doc.Field("Para", strings.Join(nonEmpty(ref.ReceivingVet, ref.ReceivingClinic), ", "))
+	doc.Field("Especialidad", ref.Specialty)
+	doc.Field("Contacto", ref.ReceivingContact)
+	doc.Field("Remite", s.vetName(ctx, ref.ReferringVetID))
+
+	doc.Heading("Paciente")
+	doc.Field("Nombre", patient.Name)
+	doc.Field("Raza", patient.Breed)
+	doc.Field("Sexo", string(patient.Gender))
+	if patient.BirthDate != nil {
+		doc.Field("Fecha de nacimiento", patient.BirthDate.Format("02/01/2006"))
+	}
+	if patient.Weight > 0 {
+		doc.Field("Peso", fmt.Sprintf("%.1f kg", patient.Weight))
+	}
+	doc.Field("Microchip", patient.Microchip)
+	if patient.Sterilized {
+		doc.Field("Esterilizado", "sí")
+	}
+
+	doc.Heading("Motivo de la remisión")
+	doc.Text(ref.Reason)
+
+	doc.Heading("Consulta actual")
+	doc.Field("Fecha", record.CreatedAt.Format("02/01/2006"))
+	doc.Field("Tipo", string(record.Type))
+	doc.Field("Motivo de consulta", record.ChiefComplaint)
+	doc.Field("Síntomas", record.Symptoms)
+	doc.Field("Diagnóstico", record.Diagnosis)
+	doc.Field("Tratamiento", record.Treatment)
+	if record.Temperature > 0 {
+		doc.Field("Temperatura", fmt.Sprintf("%.1f °C", record.Temperature))
+	}
+	for _, m := range record.Medications {
+		doc.Text(fmt.Sprintf("- %s %s, %s durante %s", m.Name, m.Dose, m.Frequency, m.Duration))
+	}
+
+	allergies, _ := s.repo.FindAllergiesByPatient(ctx, record.PatientID, tenantID)
+	history, _ := s.repo.FindHistoryByPatient(ctx, record.PatientID, tenantID)
+	if len(allergies) > 0 || history != nil {
+		doc.Heading("Antecedentes")
+		for _, a := range allergies {
+			doc.Text(fmt.Sprintf("- Alergia a %s (%s)", a.Allergen, a.Severity))
+		}
+		if history != nil {
+			doc.Field("Condiciones crónicas", strings.Join(history.ChronicConditions, ", "))
+			doc.Field("Cirugías previas", strings.Join(history.PreviousSurgeries, ", "))
+			doc.Field("Factores de riesgo", strings.Join(history.RiskFactors, ", "))
+			doc.Field("Grupo sanguíneo", history.BloodType)
+		}
+	}
+
+	earlier, _, err := s.repo.FindByPatient(ctx, record.PatientID, tenantID, pagination.Params{Limit: 2 * referralHistoryLimit})
+	if err != nil {
+		return nil, err
+	}
+	var previous []MedicalRecord
+	for _, r := range earlier {
+		if r.ID != record.ID && !r.CreatedAt.After(record.CreatedAt) && len(previous) < referralHistoryLimit {
+			previous = append(previous, r)
+		}
+	}
+	if len(previous) > 0 {
+		doc.Heading("Historial reciente")
+		for _, r := range previous {
+			summary := r.Diagnosis
+			if summary == "" {
+				summary = r.ChiefComplaint
+			}
+			doc.Text(fmt.Sprintf("- %s (%s): %s", r.CreatedAt.Format("02/01/2006"), r.Type, summary))
+		}
+	}
+
+	if filters.IncludeLabResults && s.labs != nil {
+		orders, _, err := s.labs.FindByPatient(ctx, record.PatientID, tenantID, pagination.Params{Limit: 4 * referralLabLimit})
+		if err != nil {
+			return nil, err
+		}
+		var attached []string
+		for _, o := range orders {
+			if o.ResultFileID == "" || len(attached) == referralLabLimit {
+				continue
+			}
+			date := o.OrderDate
+			if o.ResultDate != nil {
+				date = *o.ResultDate
+			}
+			attached = append(attached, fmt.Sprintf("- %s (%s): archivo %s", o.TestType, date.Format("02/01/2006"), o.ResultFileID))
+		}
+		if len(attached) > 0 {
+			doc.Heading("Resultados de laboratorio adjuntos")
+			for _, a := range attached {
+				doc.Text(a)
+			}
+		}
+	}
+
+	doc.Space()
+	doc.Space()
+	doc.Text(s.vetName(ctx, ref.ReferringVetID))
+
+	return &ReferralLetter{
+		Filename: fmt.Sprintf("remision-%s-%s.pdf", record.ID.Hex(), ref.ReferredAt.Format("20060102")),
+		Data:     doc.Bytes(),
+	}, nil
+}
+
+func (s *Service) vetName(ctx context.Context, id primitive.ObjectID) string {
+	if u, err := s.userRepo.FindByID(ctx, id.Hex()); err == nil {
+		return u.Name
+	}
+	return ""
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
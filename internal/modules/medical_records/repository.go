@@ -22,6 +22,8 @@ type MedicalRecordRepository interface {
 	FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters MedicalRecordListFilters, params pagination.Params) ([]MedicalRecord, int64, error)
 	Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
 	Delete(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
+	CountByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (int64, error)
+	FindByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) ([]MedicalRecord, error)
 
 	// Timeline
 	FindTimeline(ctx context.Context, patientID, tenantID primitive.ObjectID, filters TimelineFilters) ([]TimelineEntry, int64, error)
@@ -32,6 +34,7 @@ type MedicalRecordRepository interface {
 	FindAllergyByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Allergy, error)
 	UpdateAllergy(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
 	DeleteAllergy(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
+	FindUpcomingVeterinarians(ctx context.Context, patientID, tenantID primitive.ObjectID, from time.Time) ([]primitive.ObjectID, error)
 
 	// Medical History CRUD
 	CreateHistory(ctx context.Context, history *MedicalHistory) error
@@ -43,18 +46,24 @@ type MedicalRecordRepository interface {
 	EnsureIndexes(ctx context.Context) error
 }
 
+// RecordsCollection stores the medical records, along with the owner
+// notifications their writes leave pending for the outbox
+const RecordsCollection = "medical_records"
+
 type medicalRecordRepository struct {
-	recordsCollection   *mongo.Collection
-	allergiesCollection *mongo.Collection
-	historyCollection   *mongo.Collection
+	recordsCollection      *mongo.Collection
+	allergiesCollection    *mongo.Collection
+	historyCollection      *mongo.Collection
+	appointmentsCollection *mongo.Collection
 }
 
 // NewMedicalRecordRepository creates a new medical record repository
 func NewMedicalRecordRepository(db *database.MongoDB) MedicalRecordRepository {
 	return &medicalRecordRepository{
-		recordsCollection:   db.Collection("medical_records"),
-		allergiesCollection: db.Collection("allergies"),
-		historyCollection:   db.Collection("medical_histories"),
+		recordsCollection:      db.Collection(RecordsCollection),
+		allergiesCollection:    db.Collection("allergies"),
+		historyCollection:      db.Collection("medical_histories"),
+		appointmentsCollection: db.Collection("appointments"),
 	}
 }
 
@@ -88,6 +97,9 @@ func (r *medicalRecordRepository) FindByID(ctx context.Context, id primitive.Obj
 	return &record, nil
 }
 
+// medicalRecordSortFields are the fields record lists accept in ?sort=
+var medicalRecordSortFields = []string{"created_at", "type"}
+
 func (r *medicalRecordRepository) FindByPatient(ctx context.Context, patientID, tenantID primitive.ObjectID, params pagination.Params) ([]MedicalRecord, int64, error) {
 	filter := bson.M{
 		"patient_id": patientID,
@@ -101,11 +113,16 @@ func (r *medicalRecordRepository) FindByPatient(ctx context.Context, patientID,
 		return nil, 0, err
 	}
 
+	sort, err := params.SortOrder(medicalRecordSortFields, bson.D{{"created_at", -1}})
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Set pagination options
 	opts := options.Find().
 		SetSkip(int64(params.Skip)).
 		SetLimit(int64(params.Limit)).
-		SetSort(bson.D{{"created_at", -1}})
+		SetSort(sort)
 
 	cursor, err := r.recordsCollection.Find(ctx, filter, opts)
 	if err != nil {
@@ -164,17 +181,32 @@ func (r *medicalRecordRepository) FindByFilters(ctx context.Context, tenantID pr
 		filter["attachment_ids"] = bson.M{"$exists": true, "$ne": []string{}, "$not": bson.M{"$size": 0}}
 	}
 
+	// Searches go through the text index and rank best matches first
+	defaultSort := bson.D{{Key: "created_at", Value: -1}}
+	if filters.Search != "" {
+		filter["$text"] = bson.M{"$search": filters.Search}
+		defaultSort = bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}}
+	}
+
 	// Count total
 	total, err := r.recordsCollection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	sort, err := params.SortOrder(medicalRecordSortFields, defaultSort)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Set pagination options
 	opts := options.Find().
 		SetSkip(int64(params.Skip)).
 		SetLimit(int64(params.Limit)).
-		SetSort(bson.D{{"created_at", -1}})
+		SetSort(sort)
+	if filters.Search != "" {
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+	}
 
 	cursor, err := r.recordsCollection.Find(ctx, filter, opts)
 	if err != nil {
@@ -236,6 +268,34 @@ func (r *medicalRecordRepository) Delete(ctx context.Context, id primitive.Objec
 	return nil
 }
 
+// CountByAppointment counts live medical records linked to an appointment
+func (r *medicalRecordRepository) CountByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) (int64, error) {
+	return r.recordsCollection.CountDocuments(ctx, bson.M{
+		"appointment_id": appointmentID,
+		"tenant_id":      tenantID,
+		"deleted_at":     nil,
+	})
+}
+
+// FindByAppointment returns the records written during an appointment
+func (r *medicalRecordRepository) FindByAppointment(ctx context.Context, appointmentID, tenantID primitive.ObjectID) ([]MedicalRecord, error) {
+	cursor, err := r.recordsCollection.Find(ctx, bson.M{
+		"appointment_id": appointmentID,
+		"tenant_id":      tenantID,
+		"deleted_at":     nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []MedicalRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
 func (r *medicalRecordRepository) FindTimeline(ctx context.Context, patientID, tenantID primitive.ObjectID, filters TimelineFilters) ([]TimelineEntry, int64, error) {
 	filter := bson.M{
 		"patient_id": patientID,
@@ -406,6 +466,31 @@ func (r *medicalRecordRepository) DeleteAllergy(ctx context.Context, id primitiv
 	return nil
 }
 
+// FindUpcomingVeterinarians returns the vets assigned to the patient's
+// appointments that are still open and scheduled from the given time on
+func (r *medicalRecordRepository) FindUpcomingVeterinarians(ctx context.Context, patientID, tenantID primitive.ObjectID, from time.Time) ([]primitive.ObjectID, error) {
+	filter := bson.M{
+		"tenant_id":    tenantID,
+		"patient_id":   patientID,
+		"scheduled_at": bson.M{"$gte": from},
+		"status":       bson.M{"$nin": []string{"completed", "cancelled", "no_show"}},
+		"deleted_at":   nil,
+	}
+
+	values, err := r.appointmentsCollection.Distinct(ctx, "veterinarian_id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	vetIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			vetIDs = append(vetIDs, id)
+		}
+	}
+	return vetIDs, nil
+}
+
 // Medical History methods
 
 func (r *medicalRecordRepository) CreateHistory(ctx context.Context, history *MedicalHistory) error {
@@ -502,6 +587,22 @@ func (r *medicalRecordRepository) EnsureIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{"type", 1}, {"created_at", -1}},
 		},
+		{
+			// Full text search of the list; the tenant prefix keeps each
+			// search within one clinic's records
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "chief_complaint", Value: "text"},
+				{Key: "diagnosis", Value: "text"},
+				{Key: "symptoms", Value: "text"},
+				{Key: "treatment", Value: "text"},
+				{Key: "evolution_notes", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("medical_records_text").
+				SetDefaultLanguage("spanish").
+				SetWeights(bson.M{"diagnosis": 5, "chief_complaint": 3, "symptoms": 2, "treatment": 1, "evolution_notes": 1}),
+		},
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
@@ -4,32 +4,42 @@ import (
 	"context"
 	"log"
 
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/laboratory"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/staff"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
 )
 
-// RegisterAdminRoutes registers admin-panel routes under /api/medical-records
-func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+// RegisterAdminRoutes registers admin-panel routes under /api/medical-records.
+// followUps books the next visit of new records when they ask for it.
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, cfg *config.Config, followUps FollowUpBooker) {
 	repo := NewMedicalRecordRepository(db)
 	patientRepo := patients.NewPatientRepository(db)
 	userRepo := users.NewRepository(db)
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil, // push provider not needed for medical records
-	)
+	).WithOutbox(notifications.NewOutboxRepository(db))
 
 	// Ensure indexes
 	if err := repo.EnsureIndexes(context.Background()); err != nil {
 		log.Printf("failed to ensure indexes for medical_records: %v", err)
 	}
 
-	service := NewService(repo, patientRepo, userRepo, notifSvc)
+	inventorySvc := inventory.NewService(inventory.NewProductRepository(db), userRepo, notifSvc, tenant.NewTenantRepository(db), cfg)
+
+	service := NewService(repo, patientRepo, userRepo, notifSvc, inventorySvc, laboratory.NewLabOrderRepository(db), tenant.NewTenantRepository(db), NewRecordTemplateRepository(db), staff.NewService(staff.NewRepository(db))).
+		WithFollowUps(followUps)
 	handler := NewHandler(service)
 
 	// Medical Records routes
@@ -39,9 +49,17 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	mr.GET("/:id", handler.GetMedicalRecord)
 	mr.PUT("/:id", handler.UpdateMedicalRecord)
 	mr.DELETE("/:id", handler.DeleteMedicalRecord)
+	mr.GET("/:id/referral-letter", handler.GetReferralLetter)
 	mr.GET("/patient/:patient_id", handler.GetPatientRecords)
 	mr.GET("/patient/:patient_id/timeline", handler.GetPatientTimeline)
 
+	// Medical record templates routes
+	templates := private.Group("/medical-record-templates")
+	templates.GET("", handler.ListRecordTemplates)
+	templates.POST("", handler.CreateRecordTemplate)
+	templates.PUT("/:id", handler.UpdateRecordTemplate)
+	templates.DELETE("/:id", handler.DeleteRecordTemplate)
+
 	// Allergies routes
 	allergies := private.Group("/allergies")
 	allergies.POST("", handler.CreateAllergy)
@@ -66,11 +84,12 @@ func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil,
 	)
 
-	service := NewService(repo, patientRepo, userRepo, notifSvc)
+	service := NewService(repo, patientRepo, userRepo, notifSvc, nil, nil, nil, nil, nil) // owners never dispense products, print referrals or record allergies
 	handler := NewHandler(service)
 
 	// Mobile routes - read only for owners
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
 )
 
 // MedicalRecordType represents the type of medical record
@@ -53,6 +55,14 @@ type Medication struct {
 	Duration string `bson:"duration" json:"duration"`
 }
 
+// ChecklistItem is one check of a visit, usually pre-filled from a template
+type ChecklistItem struct {
+	Key     string `bson:"key" json:"key"`
+	Label   string `bson:"label" json:"label"`
+	Checked bool   `bson:"checked" json:"checked"`
+	Notes   string `bson:"notes,omitempty" json:"notes,omitempty"`
+}
+
 // DailyProgress represents a daily progress note in hospitalization
 type DailyProgress struct {
 	Date           time.Time `bson:"date" json:"date"`
@@ -63,6 +73,28 @@ type DailyProgress struct {
 	CreatedAt      time.Time `bson:"created_at" json:"created_at"`
 }
 
+// DispensedProduct is a product handed out during a visit, together with the
+// stock movement that deducted it from inventory
+type DispensedProduct struct {
+	ProductID  primitive.ObjectID `bson:"product_id" json:"product_id"`
+	Quantity   int                `bson:"quantity" json:"quantity"`
+	MovementID primitive.ObjectID `bson:"movement_id" json:"movement_id"`
+	StockAfter int                `bson:"stock_after" json:"stock_after"`
+}
+
+// Referral sends the patient to an external vet or specialist. The referring
+// vet is clinic staff; the receiving vet is outside the system, so only their
+// name and contact details are kept.
+type Referral struct {
+	ReferringVetID   primitive.ObjectID `bson:"referring_vet_id" json:"referring_vet_id"`
+	ReceivingVet     string             `bson:"receiving_vet" json:"receiving_vet"`
+	ReceivingClinic  string             `bson:"receiving_clinic,omitempty" json:"receiving_clinic,omitempty"`
+	ReceivingContact string             `bson:"receiving_contact,omitempty" json:"receiving_contact,omitempty"`
+	Specialty        string             `bson:"specialty,omitempty" json:"specialty,omitempty"`
+	Reason           string             `bson:"reason" json:"reason"`
+	ReferredAt       time.Time          `bson:"referred_at" json:"referred_at"`
+}
+
 // MedicalRecord represents a clinical record entry
 type MedicalRecord struct {
 	ID             primitive.ObjectID  `bson:"_id" json:"id"`
@@ -82,6 +114,16 @@ type MedicalRecord struct {
 	EvolutionNotes string              `bson:"evolution_notes" json:"evolution_notes"`
 	AttachmentIDs  []string            `bson:"attachment_ids,omitempty" json:"attachment_ids,omitempty"`
 	NextVisitDate  *time.Time          `bson:"next_visit_date,omitempty" json:"next_visit_date,omitempty"`
+	// NextVisit is the appointment booked or linked for NextVisitDate, if requested
+	NextVisit *NextVisitBooking `bson:"next_visit,omitempty" json:"next_visit,omitempty"`
+	DispensedProducts []DispensedProduct `bson:"dispensed_products,omitempty" json:"dispensed_products,omitempty"`
+	Referral       *Referral           `bson:"referral,omitempty" json:"referral,omitempty"`
+	// Template the record was started from, if any
+	TemplateID *primitive.ObjectID `bson:"template_id,omitempty" json:"template_id,omitempty"`
+	Checklist  []ChecklistItem     `bson:"checklist,omitempty" json:"checklist,omitempty"`
+	// PendingNotifications are owner notifications written together with the
+	// record, until they are moved into the outbox
+	PendingNotifications []notifications.PendingNotification `bson:"pending_notifications,omitempty" json:"-"`
 	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
 	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
 	DeletedAt      *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
@@ -113,13 +155,62 @@ func (m *MedicalRecord) ToResponse() *MedicalRecordResponse {
 		resp.AppointmentID = m.AppointmentID.Hex()
 	}
 
+	if m.TemplateID != nil {
+		resp.TemplateID = m.TemplateID.Hex()
+	}
+	resp.Checklist = m.Checklist
+
 	if m.NextVisitDate != nil {
 		resp.NextVisitDate = m.NextVisitDate.Format(time.RFC3339)
 	}
 
+	if m.NextVisit != nil {
+		resp.NextVisit = &NextVisitBookingResponse{
+			AppointmentID: m.NextVisit.AppointmentID.Hex(),
+			Outcome:       m.NextVisit.Outcome,
+			Note:          m.NextVisit.Note,
+		}
+	}
+
+	if m.Referral != nil {
+		resp.Referral = &ReferralResponse{
+			ReferringVetID:   m.Referral.ReferringVetID.Hex(),
+			ReceivingVet:     m.Referral.ReceivingVet,
+			ReceivingClinic:  m.Referral.ReceivingClinic,
+			ReceivingContact: m.Referral.ReceivingContact,
+			Specialty:        m.Referral.Specialty,
+			Reason:           m.Referral.Reason,
+			ReferredAt:       m.Referral.ReferredAt,
+		}
+	}
+
+	for _, d := range m.DispensedProducts {
+		resp.DispensedProducts = append(resp.DispensedProducts, DispensedProductResponse{
+			ProductID:  d.ProductID.Hex(),
+			Quantity:   d.Quantity,
+			MovementID: d.MovementID.Hex(),
+			StockAfter: d.StockAfter,
+		})
+	}
+
 	return resp
 }
 
+// NextVisitBooking links a record to the appointment of its next visit
+type NextVisitBooking struct {
+	AppointmentID primitive.ObjectID `bson:"appointment_id"`
+	// Outcome is booked, linked or queued
+	Outcome string `bson:"outcome"`
+	Note    string `bson:"note,omitempty"`
+}
+
+// NextVisitBookingResponse represents the next visit appointment in API responses
+type NextVisitBookingResponse struct {
+	AppointmentID string `json:"appointment_id"`
+	Outcome       string `json:"outcome"`
+	Note          string `json:"note,omitempty"`
+}
+
 // IsEditable checks if the record can be edited (within 24 hours)
 func (m *MedicalRecord) IsEditable() bool {
 	return time.Since(m.CreatedAt) < 24*time.Hour
@@ -144,10 +235,34 @@ type MedicalRecordResponse struct {
 	EvolutionNotes string       `json:"evolution_notes"`
 	AttachmentIDs  []string     `json:"attachment_ids,omitempty"`
 	NextVisitDate  string       `json:"next_visit_date,omitempty"`
+	NextVisit      *NextVisitBookingResponse `json:"next_visit,omitempty"`
+	DispensedProducts []DispensedProductResponse `json:"dispensed_products,omitempty"`
+	Referral       *ReferralResponse `json:"referral,omitempty"`
+	TemplateID     string            `json:"template_id,omitempty"`
+	Checklist      []ChecklistItem   `json:"checklist,omitempty"`
 	CreatedAt      time.Time    `json:"created_at"`
 	UpdatedAt      time.Time    `json:"updated_at"`
 }
 
+// DispensedProductResponse represents a dispensed product in API responses
+type DispensedProductResponse struct {
+	ProductID  string `json:"product_id"`
+	Quantity   int    `json:"quantity"`
+	MovementID string `json:"movement_id"`
+	StockAfter int    `json:"stock_after"` // Product stock right after this line was deducted
+}
+
+// ReferralResponse represents a referral in API responses
+type ReferralResponse struct {
+	ReferringVetID   string    `json:"referring_vet_id"`
+	ReceivingVet     string    `json:"receiving_vet"`
+	ReceivingClinic  string    `json:"receiving_clinic,omitempty"`
+	ReceivingContact string    `json:"receiving_contact,omitempty"`
+	Specialty        string    `json:"specialty,omitempty"`
+	Reason           string    `json:"reason"`
+	ReferredAt       time.Time `json:"referred_at"`
+}
+
 // Allergy represents a patient allergy
 type Allergy struct {
 	ID          primitive.ObjectID `bson:"_id" json:"id"`
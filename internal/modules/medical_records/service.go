@@ -3,11 +3,14 @@ package medical_records
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/modules/inventory"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/patients"
 	"github.com/eren_dev/go_server/internal/modules/users"
@@ -18,6 +21,9 @@ import (
 type NotificationSender interface {
 	Send(ctx context.Context, dto *notifications.SendDTO) error
 	SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error
+	// Enqueue delivers a notification embedded in a document through the
+	// outbox, retrying until the owner is notified
+	Enqueue(ctx context.Context, collection string, docID primitive.ObjectID, pending notifications.PendingNotification) error
 }
 
 // PatientRepository defines the interface for patient data access
@@ -30,26 +36,56 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id string) (*users.User, error)
 }
 
+// StockDispenser defines the inventory operations used to dispense products
+type StockDispenser interface {
+	StockOut(ctx context.Context, id string, dto *inventory.StockOutDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*inventory.StockMovement, error)
+	ReverseStockMovement(ctx context.Context, id string, dto *inventory.ReverseStockMovementDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*inventory.StockMovement, error)
+}
+
 // Service provides business logic for medical records
 type Service struct {
 	repo            MedicalRecordRepository
 	patientRepo     PatientRepository
 	userRepo        UserRepository
 	notificationSvc NotificationSender
+	inventory       StockDispenser
+	labs            LabResultReader
+	tenants         TenantReader
+	templates       RecordTemplateRepository
+	staff           StaffResolver
+	followUps       FollowUpBooker
 }
 
 // NewService creates a new medical records service
-func NewService(repo MedicalRecordRepository, patientRepo PatientRepository, userRepo UserRepository, notificationSvc NotificationSender) *Service {
+func NewService(repo MedicalRecordRepository, patientRepo PatientRepository, userRepo UserRepository, notificationSvc NotificationSender, inventory StockDispenser, labs LabResultReader, tenants TenantReader, templates RecordTemplateRepository, staff StaffResolver) *Service {
 	return &Service{
 		repo:            repo,
 		patientRepo:     patientRepo,
 		userRepo:        userRepo,
 		notificationSvc: notificationSvc,
+		inventory:       inventory,
+		labs:            labs,
+		tenants:         tenants,
+		templates:       templates,
+		staff:           staff,
 	}
 }
 
-// CreateMedicalRecord creates a new medical record
-func (s *Service) CreateMedicalRecord(ctx context.Context, dto *CreateMedicalRecordDTO, tenantID primitive.ObjectID) (*MedicalRecord, error) {
+// CreateMedicalRecord creates a new medical record. Dispensed products are
+// deducted from inventory before the record is saved; if any line fails the
+// lines already deducted are reversed and the record is not created.
+func (s *Service) CreateMedicalRecord(ctx context.Context, dto *CreateMedicalRecordDTO, tenantID primitive.ObjectID, userID primitive.ObjectID) (*MedicalRecord, error) {
+	templateID, checklist, err := s.applyRecordTemplate(ctx, dto, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Type == "" {
+		return nil, ErrValidation("type", "type is required")
+	}
+	if dto.ChiefComplaint == "" {
+		return nil, ErrValidation("chief_complaint", "chief complaint is required")
+	}
+
 	// Validate patient
 	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
 	if err != nil {
@@ -105,8 +141,20 @@ func (s *Service) CreateMedicalRecord(ctx context.Context, dto *CreateMedicalRec
 		return nil, ErrInvalidWeight
 	}
 
+	if err := s.checkAttachmentLimit(ctx, dto.AttachmentIDs, tenantID); err != nil {
+		return nil, err
+	}
+
 	// Create medical record
 	now := time.Now()
+
+	var referral *Referral
+	if dto.Referral != nil {
+		if referral, err = s.buildReferral(ctx, dto.Referral, vetID, now); err != nil {
+			return nil, err
+		}
+	}
+
 	record := &MedicalRecord{
 		ID:             primitive.NewObjectID(),
 		TenantID:       tenantID,
@@ -125,21 +173,36 @@ func (s *Service) CreateMedicalRecord(ctx context.Context, dto *CreateMedicalRec
 		EvolutionNotes: dto.EvolutionNotes,
 		AttachmentIDs:  dto.AttachmentIDs,
 		NextVisitDate:  nextVisitDate,
+		Referral:       referral,
+		TemplateID:     templateID,
+		Checklist:      checklist,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
 
-	if err := s.repo.Create(ctx, record); err != nil {
+	if len(dto.DispensedProducts) > 0 {
+		dispensed, err := s.dispenseProducts(ctx, record.ID, dto.DispensedProducts, tenantID, userID)
+		if err != nil {
+			return nil, err
+		}
+		record.DispensedProducts = dispensed
+	}
+
+	nextVisit, err := s.bookNextVisit(ctx, dto, record, userID)
+	if err != nil {
+		s.rollbackDispensed(ctx, record.DispensedProducts, tenantID, userID)
 		return nil, err
 	}
+	record.NextVisit = nextVisit
 
-	// Send notification to owner about new medical record
-	s.notificationSvc.Send(ctx, &notifications.SendDTO{
+	// The owner notification is stored with the record, so a crash before it
+	// reaches the outbox cannot lose it
+	pending, err := notifications.NewPendingNotification("medical_record_created:"+record.ID.Hex(), "medical_record_created", &notifications.SendDTO{
 		OwnerID:  patient.OwnerID.Hex(),
 		TenantID: tenantID.Hex(),
 		Type:     notifications.TypeMedicalRecordCreated,
-		Title:    "Nuevo registro médico",
-		Body:     "Se ha creado un nuevo registro médico para " + patient.Name,
+		Template: notifications.TemplateMedicalRecordCreated,
+		Vars:     map[string]string{"patient_name": patient.Name},
 		Data: map[string]string{
 			"record_id":   record.ID.Hex(),
 			"patient_id":  record.PatientID.Hex(),
@@ -147,15 +210,30 @@ func (s *Service) CreateMedicalRecord(ctx context.Context, dto *CreateMedicalRec
 		},
 		SendPush: true,
 	})
+	if err != nil {
+		s.rollbackDispensed(ctx, record.DispensedProducts, tenantID, userID)
+		s.releaseNextVisit(ctx, nextVisit, tenantID)
+		return nil, err
+	}
+	record.PendingNotifications = []notifications.PendingNotification{pending}
+
+	if err := s.repo.Create(ctx, record); err != nil {
+		s.rollbackDispensed(ctx, record.DispensedProducts, tenantID, userID)
+		s.releaseNextVisit(ctx, nextVisit, tenantID)
+		return nil, err
+	}
 
-	// Send notification if next visit is scheduled
-	if nextVisitDate != nil {
+	s.notificationSvc.Enqueue(ctx, RecordsCollection, record.ID, pending)
+
+	// Send notification if next visit is scheduled; a visit booked on the
+	// calendar already told the owner through the appointment
+	if nextVisitDate != nil && (nextVisit == nil || nextVisit.Outcome != NextVisitBooked) {
 		s.notificationSvc.Send(ctx, &notifications.SendDTO{
 			OwnerID:  patient.OwnerID.Hex(),
 			TenantID: tenantID.Hex(),
 			Type:     notifications.TypeAppointmentReminder,
-			Title:    "Próxima visita programada",
-			Body:     "Próxima visita programada para el " + nextVisitDate.Format("02/01/2006"),
+			Template: notifications.TemplateNextVisitScheduled,
+			Dates:    map[string]time.Time{"date": *nextVisitDate},
 			Data: map[string]string{
 				"record_id":      record.ID.Hex(),
 				"next_visit":     nextVisitDate.Format(time.RFC3339),
@@ -168,6 +246,56 @@ func (s *Service) CreateMedicalRecord(ctx context.Context, dto *CreateMedicalRec
 	return record, nil
 }
 
+// dispenseProducts deducts each product with a "treatment" stock-out that
+// references the record. On the first failure it reverses what was already
+// deducted, so the visit either dispenses every line or none.
+func (s *Service) dispenseProducts(ctx context.Context, recordID primitive.ObjectID, items []DispensedProductDTO, tenantID, userID primitive.ObjectID) ([]DispensedProduct, error) {
+	dispensed := make([]DispensedProduct, 0, len(items))
+	for i, item := range items {
+		productID, err := primitive.ObjectIDFromHex(item.ProductID)
+		if err != nil {
+			s.rollbackDispensed(ctx, dispensed, tenantID, userID)
+			return nil, ErrValidation(fmt.Sprintf("dispensed_products[%d].product_id", i), "invalid product ID format")
+		}
+
+		movement, err := s.inventory.StockOut(ctx, productID.Hex(), &inventory.StockOutDTO{
+			Quantity:    item.Quantity,
+			Reason:      string(inventory.StockReasonTreatment),
+			ReferenceID: recordID.Hex(),
+			Notes:       "Dispensado en consulta",
+		}, tenantID, userID)
+		if err != nil {
+			s.rollbackDispensed(ctx, dispensed, tenantID, userID)
+			if errors.Is(err, inventory.ErrInsufficientStock) {
+				return nil, ErrInsufficientStockForProduct(productID.Hex())
+			}
+			return nil, err
+		}
+
+		dispensed = append(dispensed, DispensedProduct{
+			ProductID:  productID,
+			Quantity:   item.Quantity,
+			MovementID: movement.ID,
+			StockAfter: movement.StockAfter,
+		})
+	}
+	return dispensed, nil
+}
+
+// rollbackDispensed reverses stock-outs made for a record that was not
+// created. It is best effort: a failed reversal is logged and left for staff
+// to correct from the stock movement history.
+func (s *Service) rollbackDispensed(ctx context.Context, dispensed []DispensedProduct, tenantID, userID primitive.ObjectID) {
+	for _, d := range dispensed {
+		_, err := s.inventory.ReverseStockMovement(ctx, d.MovementID.Hex(), &inventory.ReverseStockMovementDTO{
+			Notes: "Registro médico no creado",
+		}, tenantID, userID)
+		if err != nil {
+			slog.Warn("dispensed product rollback failed", "tenant_id", tenantID.Hex(), "movement_id", d.MovementID.Hex(), "error", err)
+		}
+	}
+}
+
 // GetMedicalRecord gets a medical record by ID
 func (s *Service) GetMedicalRecord(ctx context.Context, id string, tenantID primitive.ObjectID) (*MedicalRecord, error) {
 	recordID, err := primitive.ObjectIDFromHex(id)
@@ -260,7 +388,18 @@ func (s *Service) UpdateMedicalRecord(ctx context.Context, id string, dto *Updat
 		updates["evolution_notes"] = dto.EvolutionNotes
 	}
 
+	if dto.Checklist != nil {
+		checklist := toChecklist(dto.Checklist)
+		if err := validateChecklistKeys(checklist); err != nil {
+			return nil, err
+		}
+		updates["checklist"] = checklist
+	}
+
 	if len(dto.AttachmentIDs) > 0 {
+		if err := s.checkAttachmentLimit(ctx, dto.AttachmentIDs, tenantID); err != nil {
+			return nil, err
+		}
 		updates["attachment_ids"] = dto.AttachmentIDs
 	}
 
@@ -275,6 +414,14 @@ func (s *Service) UpdateMedicalRecord(ctx context.Context, id string, dto *Updat
 		updates["next_visit_date"] = nvd
 	}
 
+	if dto.Referral != nil {
+		referral, err := s.buildReferral(ctx, dto.Referral, record.VeterinarianID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		updates["referral"] = referral
+	}
+
 	if err := s.repo.Update(ctx, recordID, updates, tenantID); err != nil {
 		return nil, err
 	}
@@ -363,19 +510,7 @@ func (s *Service) CreateAllergy(ctx context.Context, dto *CreateAllergyDTO, tena
 
 	// Send alert if severe allergy
 	if allergy.Severity == AllergySeveritySevere {
-		s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
-			UserID:   primitive.NilObjectID.Hex(), // Broadcast to all staff
-			TenantID: tenantID.Hex(),
-			Type:     notifications.TypeStaffSystemAlert,
-			Title:    "Alerta: Alergia Severa Registrada",
-			Body:     "El paciente " + patient.Name + " tiene una nueva alergia severa: " + allergy.Allergen,
-			Data: map[string]string{
-				"allergy_id":  allergy.ID.Hex(),
-				"patient_id":  patient.ID.Hex(),
-				"allergen":    allergy.Allergen,
-				"severity":    string(allergy.Severity),
-			},
-		})
+		s.alertSevereAllergy(ctx, allergy, patient)
 	}
 
 	return allergy, nil
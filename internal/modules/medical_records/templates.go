@@ -0,0 +1,326 @@
+package medical_records
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// RecordTemplate holds the defaults a clinic uses for a kind of visit. Records
+// created from it start with its chief complaint prompt, findings, treatment,
+// medications and checklist.
+type RecordTemplate struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID       primitive.ObjectID `bson:"tenant_id"`
+	Name           string             `bson:"name"`
+	Type           MedicalRecordType  `bson:"type"`
+	ChiefComplaint string             `bson:"chief_complaint,omitempty"`
+	Symptoms       string             `bson:"symptoms,omitempty"`
+	Treatment      string             `bson:"treatment,omitempty"`
+	Medications    []Medication       `bson:"medications,omitempty"`
+	Checklist      []ChecklistItem    `bson:"checklist,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at"`
+	DeletedAt      *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// ToResponse converts RecordTemplate to RecordTemplateResponse
+func (t *RecordTemplate) ToResponse() RecordTemplateResponse {
+	resp := RecordTemplateResponse{
+		ID:             t.ID.Hex(),
+		Name:           t.Name,
+		Type:           string(t.Type),
+		ChiefComplaint: t.ChiefComplaint,
+		Symptoms:       t.Symptoms,
+		Treatment:      t.Treatment,
+		Medications:    t.Medications,
+		Checklist:      t.Checklist,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+	if resp.Medications == nil {
+		resp.Medications = []Medication{}
+	}
+	if resp.Checklist == nil {
+		resp.Checklist = []ChecklistItem{}
+	}
+	return resp
+}
+
+// RecordTemplateRepository stores each clinic's medical record templates
+type RecordTemplateRepository interface {
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID, recordType string) ([]RecordTemplate, error)
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*RecordTemplate, error)
+	Create(ctx context.Context, t *RecordTemplate) error
+	Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error
+	Delete(ctx context.Context, id, tenantID primitive.ObjectID) error
+}
+
+type recordTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRecordTemplateRepository creates a new medical record template repository
+func NewRecordTemplateRepository(db *database.MongoDB) RecordTemplateRepository {
+	return &recordTemplateRepository{collection: db.Collection("medical_record_templates")}
+}
+
+func (r *recordTemplateRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID, recordType string) ([]RecordTemplate, error) {
+	filter := bson.M{"tenant_id": tenantID, "deleted_at": nil}
+	if recordType != "" {
+		filter["type"] = recordType
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "type", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []RecordTemplate{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *recordTemplateRepository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*RecordTemplate, error) {
+	var t RecordTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil}).Decode(&t)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRecordTemplateNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *recordTemplateRepository) Create(ctx context.Context, t *RecordTemplate) error {
+	result, err := r.collection.InsertOne(ctx, t)
+	if err != nil {
+		return err
+	}
+	t.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *recordTemplateRepository) Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRecordTemplateNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes the template; records created from it keep their values
+func (r *recordTemplateRepository) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRecordTemplateNotFound
+	}
+	return nil
+}
+
+// ListRecordTemplates returns the clinic's templates, optionally of one type
+func (s *Service) ListRecordTemplates(ctx context.Context, filters RecordTemplateFilters, tenantID primitive.ObjectID) ([]RecordTemplateResponse, error) {
+	templates, err := s.templates.FindByTenant(ctx, tenantID, filters.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]RecordTemplateResponse, len(templates))
+	for i := range templates {
+		resp[i] = templates[i].ToResponse()
+	}
+	return resp, nil
+}
+
+// CreateRecordTemplate adds a template to the clinic
+func (s *Service) CreateRecordTemplate(ctx context.Context, dto CreateRecordTemplateDTO, tenantID primitive.ObjectID) (*RecordTemplateResponse, error) {
+	checklist := toTemplateChecklist(dto.Checklist)
+	if err := validateChecklistKeys(checklist); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	t := &RecordTemplate{
+		TenantID:       tenantID,
+		Name:           dto.Name,
+		Type:           MedicalRecordType(dto.Type),
+		ChiefComplaint: dto.ChiefComplaint,
+		Symptoms:       dto.Symptoms,
+		Treatment:      dto.Treatment,
+		Medications:    toMedications(dto.Medications),
+		Checklist:      checklist,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.templates.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	resp := t.ToResponse()
+	return &resp, nil
+}
+
+// UpdateRecordTemplate changes a template. Records already created from it
+// are not affected.
+func (s *Service) UpdateRecordTemplate(ctx context.Context, id string, dto UpdateRecordTemplateDTO, tenantID primitive.ObjectID) (*RecordTemplateResponse, error) {
+	templateID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid template ID format")
+	}
+
+	updates := bson.M{"updated_at": time.Now()}
+	if dto.Name != nil {
+		updates["name"] = *dto.Name
+	}
+	if dto.Type != nil {
+		updates["type"] = *dto.Type
+	}
+	if dto.ChiefComplaint != nil {
+		updates["chief_complaint"] = *dto.ChiefComplaint
+	}
+	if dto.Symptoms != nil {
+		updates["symptoms"] = *dto.Symptoms
+	}
+	if dto.Treatment != nil {
+		updates["treatment"] = *dto.Treatment
+	}
+	if dto.Medications != nil {
+		updates["medications"] = toMedications(dto.Medications)
+	}
+	if dto.Checklist != nil {
+		checklist := toTemplateChecklist(dto.Checklist)
+		if err := validateChecklistKeys(checklist); err != nil {
+			return nil, err
+		}
+		updates["checklist"] = checklist
+	}
+
+	if err := s.templates.Update(ctx, templateID, tenantID, updates); err != nil {
+		return nil, err
+	}
+
+	t, err := s.templates.FindByID(ctx, templateID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := t.ToResponse()
+	return &resp, nil
+}
+
+// DeleteRecordTemplate removes a template from the clinic
+func (s *Service) DeleteRecordTemplate(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	templateID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrValidation("id", "invalid template ID format")
+	}
+	return s.templates.Delete(ctx, templateID, tenantID)
+}
+
+// applyRecordTemplate fills the fields the request leaves empty with the
+// template's defaults and returns the template ID and the merged checklist.
+// Values in the request always win over the template's.
+func (s *Service) applyRecordTemplate(ctx context.Context, dto *CreateMedicalRecordDTO, tenantID primitive.ObjectID) (*primitive.ObjectID, []ChecklistItem, error) {
+	provided := toChecklist(dto.Checklist)
+	if err := validateChecklistKeys(provided); err != nil {
+		return nil, nil, err
+	}
+	if dto.TemplateID == "" {
+		return nil, provided, nil
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(dto.TemplateID)
+	if err != nil {
+		return nil, nil, ErrValidation("template_id", "invalid template ID format")
+	}
+	if s.templates == nil {
+		return nil, nil, ErrRecordTemplateNotFound
+	}
+	t, err := s.templates.FindByID(ctx, templateID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dto.Type == "" {
+		dto.Type = string(t.Type)
+	}
+	if dto.ChiefComplaint == "" {
+		dto.ChiefComplaint = t.ChiefComplaint
+	}
+	if dto.Symptoms == "" {
+		dto.Symptoms = t.Symptoms
+	}
+	if dto.Treatment == "" {
+		dto.Treatment = t.Treatment
+	}
+	if len(dto.Medications) == 0 {
+		for _, m := range t.Medications {
+			dto.Medications = append(dto.Medications, MedicationDTO{Name: m.Name, Dose: m.Dose, Frequency: m.Frequency, Duration: m.Duration})
+		}
+	}
+
+	return &templateID, mergeChecklist(t.Checklist, provided), nil
+}
+
+// mergeChecklist keeps the template's order, overlays the answers given for
+// its keys and appends the checks the template does not have.
+func mergeChecklist(template, provided []ChecklistItem) []ChecklistItem {
+	answers := make(map[string]ChecklistItem, len(provided))
+	for _, item := range provided {
+		answers[item.Key] = item
+	}
+
+	merged := make([]ChecklistItem, 0, len(template)+len(provided))
+	seen := make(map[string]bool, len(template))
+	for _, item := range template {
+		if answer, ok := answers[item.Key]; ok {
+			if answer.Label == "" {
+				answer.Label = item.Label
+			}
+			item = answer
+		}
+		merged = append(merged, item)
+		seen[item.Key] = true
+	}
+	for _, item := range provided {
+		if !seen[item.Key] {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// validateChecklistKeys rejects checklists that repeat a key
+func validateChecklistKeys(items []ChecklistItem) error {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if seen[item.Key] {
+			return ErrValidation("checklist", "duplicate checklist key: "+item.Key)
+		}
+		seen[item.Key] = true
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package mobile_auth
 
+import "github.com/eren_dev/go_server/internal/modules/owners"
+
 // --- Input DTOs ---
 
 type RegisterDTO struct {
@@ -12,6 +14,8 @@ type RegisterDTO struct {
 type LoginDTO struct {
 	Email    string `json:"email"    binding:"required,email" example:"juan@example.com"`
 	Password string `json:"password" binding:"required"        example:"secret123"`
+	// Remember keeps the session for MOBILE_JWT_REMEMBER_EXPIRATION_DAYS
+	Remember bool `json:"remember" example:"true"`
 }
 
 type RefreshDTO struct {
@@ -21,9 +25,12 @@ type RefreshDTO struct {
 // --- Response DTOs ---
 
 type TokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int64  `json:"expires_in"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+	// Tenants lists the clinics the owner belongs to (login only)
+	Tenants []owners.TenantSummary `json:"tenants,omitempty"`
 }
 
 type OwnerInfo struct {
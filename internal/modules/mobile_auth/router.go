@@ -3,6 +3,7 @@ package mobile_auth
 import (
 	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
@@ -14,7 +15,8 @@ import (
 func RegisterRoutes(mobilePublic, mobilePrivate *httpx.Router, db *database.MongoDB, cfg *config.Config) {
 	ownerRepo := owners.NewRepository(db)
 	jwtService := sharedAuth.NewJWTService(cfg)
-	service := NewService(ownerRepo, jwtService)
+	ownerSvc := owners.NewService(ownerRepo, tenant.NewTenantRepository(db))
+	service := NewService(ownerRepo, ownerSvc, jwtService)
 	handler := NewHandler(service)
 
 	pub := mobilePublic.Group("/auth")
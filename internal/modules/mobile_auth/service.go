@@ -9,15 +9,22 @@ import (
 	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
 )
 
+// TenantLister resolves the clinics an owner is associated with.
+type TenantLister interface {
+	ListTenants(ctx context.Context, ownerID string) ([]owners.TenantSummary, error)
+}
+
 type Service struct {
-	ownerRepo  owners.OwnerRepository
-	jwtService *sharedAuth.JWTService
+	ownerRepo    owners.OwnerRepository
+	tenantLister TenantLister
+	jwtService   *sharedAuth.JWTService
 }
 
-func NewService(ownerRepo owners.OwnerRepository, jwtService *sharedAuth.JWTService) *Service {
+func NewService(ownerRepo owners.OwnerRepository, tenantLister TenantLister, jwtService *sharedAuth.JWTService) *Service {
 	return &Service{
-		ownerRepo:  ownerRepo,
-		jwtService: jwtService,
+		ownerRepo:    ownerRepo,
+		tenantLister: tenantLister,
+		jwtService:   jwtService,
 	}
 }
 
@@ -40,15 +47,16 @@ func (s *Service) Register(ctx context.Context, dto *RegisterDTO) (*TokenRespons
 		return nil, err
 	}
 
-	tokens, err := s.jwtService.GenerateTokenPair(owner.ID.Hex(), owner.Email, sharedAuth.UserTypeOwner)
+	tokens, err := s.jwtService.GenerateTokenPair(owner.ID.Hex(), owner.Email, sharedAuth.UserTypeOwner, false)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
 	}, nil
 }
 
@@ -65,15 +73,23 @@ func (s *Service) Login(ctx context.Context, dto *LoginDTO) (*TokenResponse, err
 		return nil, ErrInvalidCredentials
 	}
 
-	tokens, err := s.jwtService.GenerateTokenPair(owner.ID.Hex(), owner.Email, sharedAuth.UserTypeOwner)
+	tokens, err := s.jwtService.GenerateTokenPair(owner.ID.Hex(), owner.Email, sharedAuth.UserTypeOwner, dto.Remember)
+	if err != nil {
+		return nil, err
+	}
+
+	// The app picks one of these and sends it as X-Tenant-ID
+	tenants, err := s.tenantLister.ListTenants(ctx, owner.ID.Hex())
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
+		Tenants:          tenants,
 	}, nil
 }
 
@@ -84,9 +100,10 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenRespo
 	}
 
 	return &TokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        tokens.ExpiresIn,
+		RefreshExpiresIn: tokens.RefreshExpiresIn,
 	}, nil
 }
 
@@ -0,0 +1,79 @@
+package notifications
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+type BroadcastHandler struct {
+	service *BroadcastService
+}
+
+func NewBroadcastHandler(service *BroadcastService) *BroadcastHandler {
+	return &BroadcastHandler{service: service}
+}
+
+// Create queues an announcement for every owner matching the audience filter.
+//
+//	@Summary		Broadcast a notification to owners
+//	@Tags			admin/notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string				true	"Tenant ID"
+//	@Param			body		body		CreateBroadcastDTO	true	"Broadcast data"
+//	@Success		200			{object}	BroadcastResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		429			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/broadcast [post]
+func (h *BroadcastHandler) Create(c *gin.Context) (any, error) {
+	userID := sharedAuth.GetUserID(c)
+	if userID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	var dto CreateBroadcastDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Create(c.Request.Context(), sharedMiddleware.GetTenantID(c), userID, &dto)
+}
+
+// List returns the tenant's broadcasts with their delivery counts (newest first).
+//
+//	@Summary		List broadcasts
+//	@Tags			admin/notifications
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			skip		query		int		false	"Skip"
+//	@Param			limit		query		int		false	"Limit"
+//	@Success		200			{object}	PaginatedBroadcastsResponse
+//	@Failure		403			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/broadcast [get]
+func (h *BroadcastHandler) List(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+	return h.service.List(c.Request.Context(), sharedMiddleware.GetTenantID(c), params)
+}
+
+// Get returns a single broadcast and its delivery counts.
+//
+//	@Summary		Get broadcast
+//	@Tags			admin/notifications
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			id			path		string	true	"Broadcast ID"
+//	@Success		200			{object}	BroadcastResponse
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/broadcast/{id} [get]
+func (h *BroadcastHandler) Get(c *gin.Context) (any, error) {
+	return h.service.GetByID(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+}
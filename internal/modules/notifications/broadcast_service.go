@@ -0,0 +1,281 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/platform/ratelimit"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// broadcastOutboxSource names broadcast deliveries in the outbox and dead letters
+const broadcastOutboxSource = "broadcast"
+
+// broadcastInsertBatch bounds how many outbox entries one insert writes
+const broadcastInsertBatch = 1000
+
+// BroadcastService sends one announcement to many owners of a tenant.
+// Each delivery is recorded in the notification outbox and sent by the
+// scheduler's outbox sweep, which retries failures and survives restarts.
+type BroadcastService struct {
+	repo      BroadcastRepository
+	outbox    OutboxRepository
+	ownerRepo owners.OwnerRepository
+	limiter   *ratelimit.Limiter
+}
+
+func NewBroadcastService(repo BroadcastRepository, outbox OutboxRepository, ownerRepo owners.OwnerRepository, limiter *ratelimit.Limiter) *BroadcastService {
+	return &BroadcastService{
+		repo:      repo,
+		outbox:    outbox,
+		ownerRepo: ownerRepo,
+		limiter:   limiter,
+	}
+}
+
+// NewBroadcastLimiter builds a per-tenant limiter allowing perHour broadcasts per hour.
+func NewBroadcastLimiter(perHour int) *ratelimit.Limiter {
+	if perHour <= 0 {
+		perHour = 1
+	}
+	return ratelimit.NewLimiter(ratelimit.Config{
+		Enabled:     true,
+		TenantRPS:   float64(perHour) / 3600,
+		TenantBurst: perHour,
+		GlobalRPS:   10,
+		GlobalBurst: 100,
+	})
+}
+
+// Create records the broadcast, resolves its audience and queues the fan-out.
+// The returned counters reflect the queued state; poll GetByID for progress.
+// A fan-out that fails halfway leaves the broadcast failed; see enqueue.
+func (s *BroadcastService) Create(ctx context.Context, tenantID primitive.ObjectID, userID string, dto *CreateBroadcastDTO) (*BroadcastResponse, error) {
+	createdBy, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var speciesID *primitive.ObjectID
+	if dto.Audience == AudienceSpecies {
+		if dto.SpeciesID == "" {
+			return nil, ErrSpeciesRequired
+		}
+		sid, err := primitive.ObjectIDFromHex(dto.SpeciesID)
+		if err != nil {
+			return nil, ErrSpeciesRequired
+		}
+		speciesID = &sid
+	}
+	vaccine := strings.TrimSpace(dto.VaccineName)
+	if dto.Audience == AudienceVaccineOverdue && vaccine == "" {
+		return nil, ErrVaccineRequired
+	}
+
+	notifType := dto.Type
+	if notifType == "" {
+		notifType = TypeAnnouncement
+	}
+
+	recipients, err := s.resolveAudience(ctx, tenantID, dto.Audience, speciesID, vaccine)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only broadcasts that are about to be sent count against the limit
+	if s.limiter != nil {
+		if allowed, _ := s.limiter.Allow(tenantID.Hex()); !allowed {
+			return nil, ErrBroadcastRateLimited
+		}
+	}
+
+	b := &Broadcast{
+		ID:          primitive.NewObjectID(),
+		TenantID:    tenantID,
+		CreatedBy:   createdBy,
+		Type:        notifType,
+		Title:       dto.Title,
+		Body:        dto.Body,
+		Audience:    dto.Audience,
+		SpeciesID:   speciesID,
+		VaccineName: vaccine,
+		SendPush:    dto.SendPush,
+		Status:      BroadcastStatusQueued,
+		Recipients:  len(recipients),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.repo.Create(ctx, b); err != nil {
+		return nil, err
+	}
+
+	if err := s.enqueue(ctx, b, recipients); err != nil {
+		return nil, err
+	}
+
+	resp := toBroadcastResponse(b)
+	return &resp, nil
+}
+
+func (s *BroadcastService) GetByID(ctx context.Context, id string, tenantID primitive.ObjectID) (*BroadcastResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBroadcastID
+	}
+
+	b, err := s.repo.FindByID(ctx, oid, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshProgress(ctx, b); err != nil {
+		return nil, err
+	}
+
+	resp := toBroadcastResponse(b)
+	return &resp, nil
+}
+
+func (s *BroadcastService) List(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) (*PaginatedBroadcastsResponse, error) {
+	items, total, err := s.repo.FindByTenant(ctx, tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]BroadcastResponse, len(items))
+	for i := range items {
+		if err := s.refreshProgress(ctx, &items[i]); err != nil {
+			return nil, err
+		}
+		data[i] = toBroadcastResponse(&items[i])
+	}
+
+	return &PaginatedBroadcastsResponse{
+		Data:       data,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+// resolveAudience returns the tenant owners matching the audience filter.
+func (s *BroadcastService) resolveAudience(ctx context.Context, tenantID primitive.ObjectID, audience BroadcastAudience, speciesID *primitive.ObjectID, vaccine string) ([]*owners.Owner, error) {
+	tenantOwners, err := s.ownerRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []primitive.ObjectID
+	switch audience {
+	case AudienceAllOwners:
+		return tenantOwners, nil
+	case AudienceUpcomingAppointments:
+		ids, err = s.repo.FindOwnerIDsWithUpcomingAppointments(ctx, tenantID, time.Now())
+	case AudienceSpecies:
+		ids, err = s.repo.FindOwnerIDsBySpecies(ctx, tenantID, *speciesID)
+	case AudienceVaccineOverdue:
+		ids, err = s.repo.FindOwnerIDsWithOverdueVaccine(ctx, tenantID, vaccine, time.Now())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[primitive.ObjectID]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+
+	matched := make([]*owners.Owner, 0, len(ids))
+	for _, o := range tenantOwners {
+		if _, ok := wanted[o.ID]; ok {
+			matched = append(matched, o)
+		}
+	}
+	return matched, nil
+}
+
+// enqueue records one outbox entry per recipient, skipping owners whose
+// preferences mute this notification type. If an insert fails the broadcast
+// is marked failed with the entries stored so far, which are still sent;
+// creating it again would notify those owners twice.
+func (s *BroadcastService) enqueue(ctx context.Context, b *Broadcast, recipients []*owners.Owner) error {
+	now := time.Now()
+	entries := make([]*OutboxEntry, 0, len(recipients))
+	for _, owner := range recipients {
+		if !owner.NotificationPrefs.Allows(string(b.Type)) {
+			b.Skipped++
+			continue
+		}
+
+		dto := &SendDTO{
+			OwnerID:  owner.ID.Hex(),
+			TenantID: b.TenantID.Hex(),
+			Type:     b.Type,
+			Title:    b.Title,
+			Body:     b.Body,
+			Data:     map[string]string{"broadcast_id": b.ID.Hex()},
+			SendPush: b.SendPush,
+		}
+		pending := PendingNotification{
+			DedupeKey: "broadcast:" + b.ID.Hex() + ":" + owner.ID.Hex(),
+			Source:    broadcastOutboxSource,
+			TenantID:  b.TenantID,
+			Message:   outboxMessageFromDTO(dto),
+			CreatedAt: now,
+		}
+		entries = append(entries, pending.entry(now))
+	}
+
+	for start := 0; start < len(entries); start += broadcastInsertBatch {
+		end := min(start+broadcastInsertBatch, len(entries))
+		if err := s.outbox.InsertMany(ctx, entries[start:end]); err != nil {
+			b.Status = BroadcastStatusFailed
+			b.Enqueued = start
+			b.CompletedAt = &now
+			if uerr := s.repo.UpdateProgress(ctx, b); uerr != nil {
+				slog.Error("broadcast: failed to record failed fan-out", "broadcast_id", b.ID.Hex(), "error", uerr)
+			}
+			return err
+		}
+	}
+
+	b.Enqueued = len(entries)
+	if len(entries) == 0 {
+		b.Status = BroadcastStatusCompleted
+		b.CompletedAt = &now
+	}
+	return s.repo.UpdateProgress(ctx, b)
+}
+
+// refreshProgress reads the delivery counters of an unfinished broadcast from
+// its outbox entries and stores them once every entry is sent or failed.
+func (s *BroadcastService) refreshProgress(ctx context.Context, b *Broadcast) error {
+	if b.Status == BroadcastStatusCompleted {
+		return nil
+	}
+
+	delivered, failed, err := s.repo.CountDeliveries(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+	b.Delivered, b.Failed = delivered, failed
+	if b.Status == BroadcastStatusFailed {
+		return nil
+	}
+	if delivered+failed > 0 {
+		b.Status = BroadcastStatusProcessing
+	}
+	if b.Delivered+b.Failed+b.Skipped < b.Recipients {
+		return nil
+	}
+
+	now := time.Now()
+	b.Status = BroadcastStatusCompleted
+	b.CompletedAt = &now
+	if err := s.repo.UpdateProgress(ctx, b); err != nil {
+		slog.Error("broadcast: failed to record delivery counts", "broadcast_id", b.ID.Hex(), "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// Dead-letter sources name the sweep or step that failed to deliver
+const (
+	DeadLetterSourcePush = "push"
+)
+
+// DeadLetter is a notification that could not be delivered, kept so a
+// misconfigured provider or template shows up instead of silently dropping
+// reminders. Exactly one of OwnerID and UserID is set.
+type DeadLetter struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID       primitive.ObjectID  `bson:"tenant_id"`
+	OwnerID        *primitive.ObjectID `bson:"owner_id,omitempty"`
+	UserID         *primitive.ObjectID `bson:"user_id,omitempty"`
+	NotificationID *primitive.ObjectID `bson:"notification_id,omitempty"`
+	Type           string              `bson:"type"`
+	Title          string              `bson:"title,omitempty"`
+	Data           map[string]string   `bson:"data,omitempty"`
+	Source         string              `bson:"source"`
+	Reason         string              `bson:"reason"`
+	CreatedAt      time.Time           `bson:"created_at"`
+}
+
+// DropMetrics counts notifications that were dropped. metrics.Metrics implements it.
+type DropMetrics interface {
+	IncNotificationDropped(notificationType, source string)
+}
+
+// DeadLetterRepository stores undelivered notifications
+type DeadLetterRepository interface {
+	Create(ctx context.Context, d *DeadLetter) error
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID, filters DeadLetterFilters, params pagination.Params) ([]DeadLetter, int64, error)
+}
+
+type deadLetterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDeadLetterRepository(db *database.MongoDB) DeadLetterRepository {
+	return &deadLetterRepository{collection: db.Collection("notification_dead_letters")}
+}
+
+func (r *deadLetterRepository) Create(ctx context.Context, d *DeadLetter) error {
+	_, err := r.collection.InsertOne(ctx, d)
+	return err
+}
+
+func (r *deadLetterRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID, filters DeadLetterFilters, params pagination.Params) ([]DeadLetter, int64, error) {
+	filter := bson.M{"tenant_id": tenantID}
+	if filters.Type != "" {
+		filter["type"] = filters.Type
+	}
+	if filters.Source != "" {
+		filter["source"] = filters.Source
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []DeadLetter{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// WithDeadLetters records failed deliveries in repo and counts them in m.
+// Either may be nil.
+func (s *Service) WithDeadLetters(repo DeadLetterRepository, m DropMetrics) *Service {
+	s.deadLetters = repo
+	s.dropMetrics = m
+	return s
+}
+
+// RecordFailedSend dead-letters an owner notification whose Send returned err.
+// source names the caller, e.g. the scheduler sweep that sent it.
+func (s *Service) RecordFailedSend(ctx context.Context, dto *SendDTO, source string, err error) {
+	d := &DeadLetter{
+		Type:   string(dto.Type),
+		Title:  dto.Title,
+		Data:   dto.Data,
+		Source: source,
+		Reason: err.Error(),
+	}
+	if id, perr := primitive.ObjectIDFromHex(dto.OwnerID); perr == nil {
+		d.OwnerID = &id
+	}
+	d.TenantID, _ = primitive.ObjectIDFromHex(dto.TenantID)
+	if d.Type == "" {
+		d.Type = string(dto.Template)
+	}
+	s.recordDrop(ctx, d)
+}
+
+// RecordFailedStaffSend dead-letters a staff notification whose SendToStaff returned err
+func (s *Service) RecordFailedStaffSend(ctx context.Context, dto *SendStaffDTO, source string, err error) {
+	d := &DeadLetter{
+		Type:   string(dto.Type),
+		Title:  dto.Title,
+		Data:   dto.Data,
+		Source: source,
+		Reason: err.Error(),
+	}
+	if id, perr := primitive.ObjectIDFromHex(dto.UserID); perr == nil {
+		d.UserID = &id
+	}
+	d.TenantID, _ = primitive.ObjectIDFromHex(dto.TenantID)
+	s.recordDrop(ctx, d)
+}
+
+// recordDrop counts and stores a dead letter. A failure to store it is only
+// logged: the caller is already handling a failed delivery.
+func (s *Service) recordDrop(ctx context.Context, d *DeadLetter) {
+	slog.Error("notification dropped", "tenant_id", d.TenantID.Hex(), "type", d.Type, "source", d.Source, "reason", d.Reason)
+
+	if s.dropMetrics != nil {
+		s.dropMetrics.IncNotificationDropped(d.Type, d.Source)
+	}
+	if s.deadLetters == nil {
+		return
+	}
+	d.ID = primitive.NewObjectID()
+	d.CreatedAt = time.Now()
+	if err := s.deadLetters.Create(ctx, d); err != nil {
+		slog.Error("failed to store notification dead letter", "tenant_id", d.TenantID.Hex(), "type", d.Type, "error", err)
+	}
+}
+
+// ListDeadLetters returns the clinic's undelivered notifications, newest first
+func (s *Service) ListDeadLetters(ctx context.Context, tenantID primitive.ObjectID, filters DeadLetterFilters, params pagination.Params) (*PaginatedDeadLettersResponse, error) {
+	items, total, err := s.deadLetters.FindByTenant(ctx, tenantID, filters, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]DeadLetterResponse, len(items))
+	for i := range items {
+		data[i] = toDeadLetterResponse(&items[i])
+	}
+	return &PaginatedDeadLettersResponse{
+		Data:       data,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+func toDeadLetterResponse(d *DeadLetter) DeadLetterResponse {
+	resp := DeadLetterResponse{
+		ID:        d.ID.Hex(),
+		Type:      d.Type,
+		Title:     d.Title,
+		Data:      d.Data,
+		Source:    d.Source,
+		Reason:    d.Reason,
+		CreatedAt: d.CreatedAt,
+	}
+	if d.OwnerID != nil {
+		resp.OwnerID = d.OwnerID.Hex()
+	}
+	if d.UserID != nil {
+		resp.UserID = d.UserID.Hex()
+	}
+	if d.NotificationID != nil {
+		resp.NotificationID = d.NotificationID.Hex()
+	}
+	return resp
+}
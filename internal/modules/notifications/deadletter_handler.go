@@ -0,0 +1,36 @@
+package notifications
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+type DeadLetterHandler struct {
+	service *Service
+}
+
+func NewDeadLetterHandler(service *Service) *DeadLetterHandler {
+	return &DeadLetterHandler{service: service}
+}
+
+// List returns the clinic's notifications that could not be delivered.
+//
+//	@Summary		List undelivered notifications
+//	@Tags			admin/notifications
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			type		query		string	false	"Notification type"
+//	@Param			source		query		string	false	"Sending job or step, e.g. push or vaccination_due"
+//	@Param			skip		query		int		false	"Skip"
+//	@Param			limit		query		int		false	"Limit"
+//	@Success		200			{object}	PaginatedDeadLettersResponse
+//	@Failure		403			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/dead-letters [get]
+func (h *DeadLetterHandler) List(c *gin.Context) (any, error) {
+	filters := DeadLetterFilters{Type: c.Query("type"), Source: c.Query("source")}
+	params := pagination.FromContext(c)
+	return h.service.ListDeadLetters(c.Request.Context(), sharedMiddleware.GetTenantID(c), filters, params)
+}
@@ -0,0 +1,90 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kinds of items a due digest can group.
+const (
+	DueKindVaccination = "vaccination"
+	DueKindAppointment = "appointment"
+)
+
+// dueKinds lists the digest kinds in summary order with their Spanish
+// singular and plural nouns.
+var dueKinds = []struct{ kind, one, many string }{
+	{DueKindVaccination, "vacuna", "vacunas"},
+	{DueKindAppointment, "cita", "citas"},
+}
+
+// DueItem is one thing an owner has due, summarized in a due digest. Data is
+// the item's own deep-link payload, the same one a per-item reminder carries.
+type DueItem struct {
+	Kind string
+	Data map[string]string
+}
+
+// SendDueDigest sends the owner a single push summarizing everything due today
+// ("2 vacunas y 1 cita vencen hoy") instead of one per item. The items travel
+// in Data["items"] as a JSON array, each with its kind, so the app can still
+// deep-link into every one of them.
+func (s *Service) SendDueDigest(ctx context.Context, ownerID, tenantID string, items []DueItem) error {
+	payload := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		entry := make(map[string]string, len(item.Data)+1)
+		for k, v := range item.Data {
+			entry[k] = v
+		}
+		entry["kind"] = item.Kind
+		payload = append(payload, entry)
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return s.Send(ctx, &SendDTO{
+		OwnerID:  ownerID,
+		TenantID: tenantID,
+		Type:     TypeDueDigest,
+		Title:    "Pendientes para hoy",
+		Body:     dueDigestBody(items),
+		Data: map[string]string{
+			"action": "due_today",
+			"count":  strconv.Itoa(len(items)),
+			"items":  string(encoded),
+		},
+		SendPush: true,
+	})
+}
+
+// dueDigestBody counts the items by kind, e.g. "2 vacunas y 1 cita vencen hoy".
+func dueDigestBody(items []DueItem) string {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[item.Kind]++
+	}
+
+	var parts []string
+	for _, k := range dueKinds {
+		switch n := counts[k.kind]; {
+		case n == 1:
+			parts = append(parts, "1 "+k.one)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %s", n, k.many))
+		}
+	}
+
+	summary := strings.Join(parts, ", ")
+	if len(parts) > 1 {
+		summary = strings.Join(parts[:len(parts)-1], ", ") + " y " + parts[len(parts)-1]
+	}
+	if len(items) == 1 {
+		return summary + " vence hoy"
+	}
+	return summary + " vencen hoy"
+}
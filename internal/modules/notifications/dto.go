@@ -13,10 +13,26 @@ type SendDTO struct {
 	Type     NotificationType
 	Title    string
 	Body     string
+	// Template, when set, renders Title/Body (and Type, if empty) from the
+	// tenant's notification templates in the owner's locale. Vars fill the
+	// {{placeholders}}; Dates (date only) and Times (date and time) are
+	// formatted following the conventions of that locale.
+	Template TemplateKey
+	Vars     map[string]string
+	Dates    map[string]time.Time
+	Times    map[string]time.Time
 	// Data is forwarded as FCM data payload and stored for deep-linking.
 	Data     map[string]string
 	// SendPush controls whether a push notification is also sent via FCM.
 	SendPush bool
+	// SendEmail and SendSMS also deliver the message to the owner's email or
+	// phone when those channels are configured. Clinics that require verified
+	// contacts skip channels the owner has not verified.
+	SendEmail bool
+	SendSMS   bool
+	// OutboxID is set when delivering from the outbox; a notification already
+	// stored for it is not stored or delivered again.
+	OutboxID string
 }
 
 // --- Response DTOs ---
@@ -37,9 +53,17 @@ type UnreadCountResponse struct {
 	Count int64 `json:"count"`
 }
 
+// InboxFilters narrows an owner's notification listing. Zero values mean "no filter".
+type InboxFilters struct {
+	Read     *bool
+	Type     NotificationType
+	DateFrom *time.Time
+}
+
 type PaginatedNotificationsResponse struct {
-	Data       []NotificationResponse    `json:"data"`
-	Pagination pagination.PaginationInfo `json:"pagination"`
+	Data        []NotificationResponse    `json:"data"`
+	Pagination  pagination.PaginationInfo `json:"pagination"`
+	UnreadCount int64                     `json:"unread_count"`
 }
 
 func toResponse(n *Notification) NotificationResponse {
@@ -66,6 +90,7 @@ type SendStaffDTO struct {
 	Title    string
 	Body     string
 	Data     map[string]string
+	Urgent   bool
 }
 
 type StaffNotificationResponse struct {
@@ -74,6 +99,7 @@ type StaffNotificationResponse struct {
 	Title     string                `json:"title"`
 	Body      string                `json:"body"`
 	Data      map[string]string     `json:"data,omitempty"`
+	Urgent    bool                  `json:"urgent,omitempty"`
 	Read      bool                  `json:"read"`
 	ReadAt    *time.Time            `json:"read_at,omitempty"`
 	CreatedAt time.Time             `json:"created_at"`
@@ -91,8 +117,142 @@ func toStaffResponse(n *StaffNotification) StaffNotificationResponse {
 		Title:     n.Title,
 		Body:      n.Body,
 		Data:      n.Data,
+		Urgent:    n.Urgent,
 		Read:      n.Read,
 		ReadAt:    n.ReadAt,
 		CreatedAt: n.CreatedAt,
 	}
 }
+
+// --- Broadcast DTOs ---
+
+type CreateBroadcastDTO struct {
+	Title     string            `json:"title"      binding:"required,max=120"                                example:"Cerrado por festivo"`
+	Body      string            `json:"body"       binding:"required,max=1000"                               example:"La clínica estará cerrada el lunes 12 de octubre."`
+	Type      NotificationType  `json:"type"       binding:"omitempty,oneof=announcement general"            example:"announcement"`
+	Audience  BroadcastAudience `json:"audience"   binding:"required,oneof=all upcoming_appointments species vaccine_overdue" example:"all"`
+	SpeciesID string            `json:"species_id"                                                           example:"507f1f77bcf86cd799439011"`
+	SendPush  bool              `json:"send_push"                                                            example:"true"`
+	// VaccineName is required for the vaccine_overdue audience
+	VaccineName string `json:"vaccine_name" binding:"max=100" example:"Rabia"`
+}
+
+type BroadcastResponse struct {
+	ID          string            `json:"id"`
+	Type        NotificationType  `json:"type"`
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Audience    BroadcastAudience `json:"audience"`
+	SpeciesID   string            `json:"species_id,omitempty"`
+	VaccineName string            `json:"vaccine_name,omitempty"`
+	SendPush    bool              `json:"send_push"`
+	Status      BroadcastStatus   `json:"status"`
+	CreatedBy   string            `json:"created_by"`
+	Recipients  int               `json:"recipients"`
+	Enqueued    int               `json:"enqueued"`
+	Delivered   int               `json:"delivered"`
+	Skipped     int               `json:"skipped"`
+	Failed      int               `json:"failed"`
+	CreatedAt   time.Time         `json:"created_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+type PaginatedBroadcastsResponse struct {
+	Data       []BroadcastResponse       `json:"data"`
+	Pagination pagination.PaginationInfo `json:"pagination"`
+}
+
+func toBroadcastResponse(b *Broadcast) BroadcastResponse {
+	resp := BroadcastResponse{
+		ID:          b.ID.Hex(),
+		Type:        b.Type,
+		Title:       b.Title,
+		Body:        b.Body,
+		Audience:    b.Audience,
+		VaccineName: b.VaccineName,
+		SendPush:    b.SendPush,
+		Status:      b.Status,
+		CreatedBy:   b.CreatedBy.Hex(),
+		Recipients:  b.Recipients,
+		Enqueued:    b.Enqueued,
+		Delivered:   b.Delivered,
+		Skipped:     b.Skipped,
+		Failed:      b.Failed,
+		CreatedAt:   b.CreatedAt,
+		CompletedAt: b.CompletedAt,
+	}
+	if b.SpeciesID != nil {
+		resp.SpeciesID = b.SpeciesID.Hex()
+	}
+	return resp
+}
+
+// --- Template DTOs ---
+
+type CreateTemplateDTO struct {
+	Key    TemplateKey `json:"key"    binding:"required"               example:"appointment_confirmed"`
+	Locale string      `json:"locale" binding:"required,min=2,max=5"   example:"es"`
+	Title  string      `json:"title"  binding:"required,max=120"       example:"¡Cita confirmada!"`
+	Body   string      `json:"body"   binding:"required,max=1000"      example:"Te esperamos el {{date}}"`
+}
+
+type UpdateTemplateDTO struct {
+	Title string `json:"title" binding:"required,max=120"  example:"¡Cita confirmada!"`
+	Body  string `json:"body"  binding:"required,max=1000" example:"Te esperamos el {{date}}"`
+}
+
+// TemplateResponse is the effective wording of a message. ID is empty and
+// Custom is false when the built-in default applies.
+type TemplateResponse struct {
+	ID        string           `json:"id,omitempty"`
+	Key       TemplateKey      `json:"key"`
+	Type      NotificationType `json:"type"`
+	Locale    string           `json:"locale"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	Variables []string         `json:"variables"`
+	Custom    bool             `json:"custom"`
+	UpdatedAt *time.Time       `json:"updated_at,omitempty"`
+}
+
+func toTemplateResponse(t *NotificationTemplate, def templateDefinition) TemplateResponse {
+	updatedAt := t.UpdatedAt
+	return TemplateResponse{
+		ID:        t.ID.Hex(),
+		Key:       t.Key,
+		Type:      def.Type,
+		Locale:    t.Locale,
+		Title:     t.Title,
+		Body:      t.Body,
+		Variables: def.Variables,
+		Custom:    true,
+		UpdatedAt: &updatedAt,
+	}
+}
+
+// --- Dead-letter DTOs ---
+
+// DeadLetterFilters narrows the dead-letter listing. Empty fields match any.
+type DeadLetterFilters struct {
+	Type   string
+	Source string
+}
+
+// DeadLetterResponse is a notification that could not be delivered
+type DeadLetterResponse struct {
+	ID             string            `json:"id"`
+	OwnerID        string            `json:"owner_id,omitempty"`
+	UserID         string            `json:"user_id,omitempty"`
+	NotificationID string            `json:"notification_id,omitempty"`
+	Type           string            `json:"type" example:"vaccination_due"`
+	Title          string            `json:"title,omitempty"`
+	Data           map[string]string `json:"data,omitempty"`
+	Source         string            `json:"source" example:"appointment_reminder"`
+	Reason         string            `json:"reason" example:"push: invalid credentials"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+type PaginatedDeadLettersResponse struct {
+	Data       []DeadLetterResponse      `json:"data"`
+	Pagination pagination.PaginationInfo `json:"pagination"`
+}
@@ -1,8 +1,37 @@
 package notifications
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	ErrNotificationNotFound  = errors.New("notification not found")
 	ErrInvalidNotificationID = errors.New("invalid notification id")
+	ErrInvalidReadFilter     = errors.New("invalid read filter, use true or false")
+	ErrInvalidDateFrom       = errors.New("invalid date_from, use RFC3339")
+
+	ErrBroadcastNotFound    = errors.New("broadcast not found")
+	ErrInvalidBroadcastID   = errors.New("invalid broadcast id")
+	ErrSpeciesRequired      = errors.New("validation error: species_id is required for the species audience")
+	ErrVaccineRequired      = errors.New("validation error: vaccine_name is required for the vaccine_overdue audience")
+	ErrBroadcastRateLimited = errors.New("broadcast rate limit exceeded, try again later")
+
+	ErrTemplateNotFound      = errors.New("notification template not found")
+	ErrInvalidTemplateID     = errors.New("invalid template id")
+	ErrTemplateAlreadyExists = errors.New("notification template already exists for this key and locale")
+	ErrUnknownTemplateKey    = errors.New("validation error: unknown template key")
 )
+
+// ErrTemplateVariablesMissing is returned at render time when the caller did
+// not supply every variable the template needs.
+func ErrTemplateVariablesMissing(names []string) error {
+	return fmt.Errorf("notification template variables missing: %s", strings.Join(names, ", "))
+}
+
+// ErrTemplateUnknownVariable is returned when a custom wording references a
+// variable the message does not provide.
+func ErrTemplateUnknownVariable(name string) error {
+	return fmt.Errorf("validation error: template uses unknown variable %q", name)
+}
@@ -1,10 +1,14 @@
 package notifications
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
 
 	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
 	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
@@ -16,15 +20,20 @@ func NewHandler(service *Service) *Handler {
 	return &Handler{service: service}
 }
 
-// GetAll returns the owner's notifications (paginated, newest first).
+// GetAll returns the owner's notifications for the current tenant (paginated, newest first).
 //
 //	@Summary		List my notifications
 //	@Tags			mobile/notifications
 //	@Produce		json
-//	@Param			skip	query		int	false	"Skip"
-//	@Param			limit	query		int	false	"Limit"
-//	@Success		200		{object}	PaginatedNotificationsResponse
-//	@Failure		401		{object}	map[string]string
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			read		query		bool	false	"Filter by read state"
+//	@Param			type		query		string	false	"Filter by notification type"
+//	@Param			date_from	query		string	false	"Only notifications created from this date (RFC3339)"
+//	@Param			skip		query		int		false	"Skip"
+//	@Param			limit		query		int		false	"Limit"
+//	@Success		200			{object}	PaginatedNotificationsResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
 //	@Security		Bearer
 //	@Router			/mobile/notifications [get]
 func (h *Handler) GetAll(c *gin.Context) (any, error) {
@@ -32,8 +41,28 @@ func (h *Handler) GetAll(c *gin.Context) (any, error) {
 	if ownerID == "" {
 		return nil, sharedErrors.ErrUnauthorized
 	}
+
+	var filters InboxFilters
+	if v := c.Query("read"); v != "" {
+		read, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, ErrInvalidReadFilter
+		}
+		filters.Read = &read
+	}
+	if v := c.Query("type"); v != "" {
+		filters.Type = NotificationType(v)
+	}
+	if v := c.Query("date_from"); v != "" {
+		df, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, ErrInvalidDateFrom
+		}
+		filters.DateFrom = &df
+	}
+
 	params := pagination.FromContext(c)
-	return h.service.GetForOwner(c.Request.Context(), ownerID, params)
+	return h.service.GetForOwner(c.Request.Context(), ownerID, sharedMiddleware.GetTenantID(c), filters, params)
 }
 
 // GetUnreadCount returns the number of unread notifications (for badge).
@@ -41,8 +70,9 @@ func (h *Handler) GetAll(c *gin.Context) (any, error) {
 //	@Summary		Unread notification count
 //	@Tags			mobile/notifications
 //	@Produce		json
-//	@Success		200	{object}	UnreadCountResponse
-//	@Failure		401	{object}	map[string]string
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Success		200			{object}	UnreadCountResponse
+//	@Failure		401			{object}	map[string]string
 //	@Security		Bearer
 //	@Router			/mobile/notifications/unread-count [get]
 func (h *Handler) GetUnreadCount(c *gin.Context) (any, error) {
@@ -50,7 +80,7 @@ func (h *Handler) GetUnreadCount(c *gin.Context) (any, error) {
 	if ownerID == "" {
 		return nil, sharedErrors.ErrUnauthorized
 	}
-	return h.service.GetUnreadCount(c.Request.Context(), ownerID)
+	return h.service.GetUnreadCount(c.Request.Context(), ownerID, sharedMiddleware.GetTenantID(c))
 }
 
 // MarkAsRead marks a single notification as read.
@@ -58,9 +88,10 @@ func (h *Handler) GetUnreadCount(c *gin.Context) (any, error) {
 //	@Summary		Mark notification as read
 //	@Tags			mobile/notifications
 //	@Produce		json
-//	@Param			id	path		string	true	"Notification ID"
-//	@Success		200	{object}	map[string]string
-//	@Failure		404	{object}	map[string]string
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			id			path		string	true	"Notification ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
 //	@Security		Bearer
 //	@Router			/mobile/notifications/{id}/read [patch]
 func (h *Handler) MarkAsRead(c *gin.Context) (any, error) {
@@ -68,7 +99,7 @@ func (h *Handler) MarkAsRead(c *gin.Context) (any, error) {
 	if ownerID == "" {
 		return nil, sharedErrors.ErrUnauthorized
 	}
-	if err := h.service.MarkAsRead(c.Request.Context(), ownerID, c.Param("id")); err != nil {
+	if err := h.service.MarkAsRead(c.Request.Context(), ownerID, sharedMiddleware.GetTenantID(c), c.Param("id")); err != nil {
 		return nil, err
 	}
 	return gin.H{"message": "notification marked as read"}, nil
@@ -79,16 +110,17 @@ func (h *Handler) MarkAsRead(c *gin.Context) (any, error) {
 //	@Summary		Mark all notifications as read
 //	@Tags			mobile/notifications
 //	@Produce		json
-//	@Success		200	{object}	map[string]string
-//	@Failure		401	{object}	map[string]string
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
 //	@Security		Bearer
-//	@Router			/mobile/notifications/read-all [patch]
+//	@Router			/mobile/notifications/read-all [post]
 func (h *Handler) MarkAllAsRead(c *gin.Context) (any, error) {
 	ownerID := sharedAuth.GetUserID(c)
 	if ownerID == "" {
 		return nil, sharedErrors.ErrUnauthorized
 	}
-	if err := h.service.MarkAllAsRead(c.Request.Context(), ownerID); err != nil {
+	if err := h.service.MarkAllAsRead(c.Request.Context(), ownerID, sharedMiddleware.GetTenantID(c)); err != nil {
 		return nil, err
 	}
 	return gin.H{"message": "all notifications marked as read"}, nil
@@ -0,0 +1,107 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the notification collections
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	inboxIndexes := []mongo.IndexModel{
+		// Owner inbox listing, newest first
+		{
+			Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		// Unread badge count and read-all
+		{
+			Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "read", Value: 1}},
+		},
+		// Notifications held by quiet hours, swept by the scheduler
+		{
+			Keys:    bson.D{{Key: "deferred_until", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		// One notification per outbox entry
+		{
+			Keys:    bson.D{{Key: "outbox_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}
+
+	_, err := db.Collection("notifications").Indexes().CreateMany(ctx, inboxIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create notification indexes: %w", err)
+	}
+
+	outboxIndexes := []mongo.IndexModel{
+		// One entry per event
+		{
+			Keys:    bson.D{{Key: "dedupe_key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// Due entries, claimed oldest first by the outbox worker
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		// Delivery counts of a broadcast
+		{
+			Keys: bson.D{{Key: "message.data.broadcast_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"message.data.broadcast_id": bson.M{"$exists": true},
+			}),
+		},
+	}
+
+	_, err = db.Collection("notification_outbox").Indexes().CreateMany(ctx, outboxIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create notification outbox indexes: %w", err)
+	}
+
+	broadcastIndexes := []mongo.IndexModel{
+		// Broadcast history per tenant, newest first
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	_, err = db.Collection("notification_broadcasts").Indexes().CreateMany(ctx, broadcastIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create broadcast indexes: %w", err)
+	}
+
+	templateIndexes := []mongo.IndexModel{
+		// One override per tenant, message and locale
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "key", Value: 1}, {Key: "locale", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = db.Collection("notification_templates").Indexes().CreateMany(ctx, templateIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create notification template indexes: %w", err)
+	}
+
+	deadLetterIndexes := []mongo.IndexModel{
+		// Dead-letter listing per tenant, newest first
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	_, err = db.Collection("notification_dead_letters").Indexes().CreateMany(ctx, deadLetterIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create notification dead-letter indexes: %w", err)
+	}
+
+	return nil
+}
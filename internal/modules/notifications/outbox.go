@@ -0,0 +1,416 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Outbox entry statuses
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+const (
+	// outboxBatchSize bounds how many entries one sweep delivers
+	outboxBatchSize = 200
+	// outboxMaxAttempts is how often an entry is tried before it is dead-lettered
+	outboxMaxAttempts = 5
+	// outboxLease is how long a claimed entry is hidden from other workers;
+	// an entry whose worker died is retried once it passes
+	outboxLease = 2 * time.Minute
+)
+
+// OutboxEntry is an owner notification delivered from the outbox, so a failed
+// send or a crash before sending is retried instead of lost. DedupeKey names
+// the event, e.g. "appointment_cancelled:<id>:<unix nanos>", and is unique:
+// recording the same event twice keeps one entry.
+type OutboxEntry struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID      primitive.ObjectID `bson:"tenant_id"`
+	DedupeKey     string             `bson:"dedupe_key"`
+	Source        string             `bson:"source"`
+	Message       OutboxMessage      `bson:"message"`
+	Status        string             `bson:"status"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	LastError     string             `bson:"last_error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty"`
+}
+
+// OutboxMessage is the stored form of a SendDTO
+type OutboxMessage struct {
+	OwnerID   string               `bson:"owner_id"`
+	Type      NotificationType     `bson:"type,omitempty"`
+	Title     string               `bson:"title,omitempty"`
+	Body      string               `bson:"body,omitempty"`
+	Template  TemplateKey          `bson:"template,omitempty"`
+	Vars      map[string]string    `bson:"vars,omitempty"`
+	Dates     map[string]time.Time `bson:"dates,omitempty"`
+	Times     map[string]time.Time `bson:"times,omitempty"`
+	Data      map[string]string    `bson:"data,omitempty"`
+	SendPush  bool                 `bson:"send_push"`
+	SendEmail bool                 `bson:"send_email"`
+	SendSMS   bool                 `bson:"send_sms"`
+}
+
+// PendingNotificationsField is where a document embeds the notifications its
+// write triggers
+const PendingNotificationsField = "pending_notifications"
+
+// PendingNotification is an outbox entry embedded in the document whose write
+// triggers it, stored in the same single-document write as the change. A
+// crash after the write therefore cannot lose the notification: Enqueue moves
+// it into the outbox, and DispatchOutbox sweeps whatever Enqueue did not.
+type PendingNotification struct {
+	DedupeKey string             `bson:"dedupe_key"`
+	Source    string             `bson:"source"`
+	TenantID  primitive.ObjectID `bson:"tenant_id"`
+	Message   OutboxMessage      `bson:"message"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// NewPendingNotification prepares dto for embedding under dedupeKey. source
+// names the event for dead letters.
+func NewPendingNotification(dedupeKey, source string, dto *SendDTO) (PendingNotification, error) {
+	tenantID, err := primitive.ObjectIDFromHex(dto.TenantID)
+	if err != nil {
+		return PendingNotification{}, err
+	}
+	return PendingNotification{
+		DedupeKey: dedupeKey,
+		Source:    source,
+		TenantID:  tenantID,
+		Message:   outboxMessageFromDTO(dto),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// SendDTO rebuilds the notification for sending without an outbox
+func (p PendingNotification) SendDTO() *SendDTO {
+	dto := p.entry(time.Now()).sendDTO()
+	dto.OutboxID = ""
+	return dto
+}
+
+func (p PendingNotification) entry(now time.Time) *OutboxEntry {
+	return &OutboxEntry{
+		ID:            primitive.NewObjectID(),
+		TenantID:      p.TenantID,
+		DedupeKey:     p.DedupeKey,
+		Source:        p.Source,
+		Message:       p.Message,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// PendingDocument is a document with notifications not yet in the outbox
+type PendingDocument struct {
+	ID      primitive.ObjectID    `bson:"_id"`
+	Pending []PendingNotification `bson:"pending_notifications"`
+}
+
+func outboxMessageFromDTO(dto *SendDTO) OutboxMessage {
+	return OutboxMessage{
+		OwnerID:   dto.OwnerID,
+		Type:      dto.Type,
+		Title:     dto.Title,
+		Body:      dto.Body,
+		Template:  dto.Template,
+		Vars:      dto.Vars,
+		Dates:     dto.Dates,
+		Times:     dto.Times,
+		Data:      dto.Data,
+		SendPush:  dto.SendPush,
+		SendEmail: dto.SendEmail,
+		SendSMS:   dto.SendSMS,
+	}
+}
+
+func (e *OutboxEntry) sendDTO() *SendDTO {
+	m := e.Message
+	return &SendDTO{
+		OwnerID:   m.OwnerID,
+		TenantID:  e.TenantID.Hex(),
+		Type:      m.Type,
+		Title:     m.Title,
+		Body:      m.Body,
+		Template:  m.Template,
+		Vars:      m.Vars,
+		Dates:     m.Dates,
+		Times:     m.Times,
+		Data:      m.Data,
+		SendPush:  m.SendPush,
+		SendEmail: m.SendEmail,
+		SendSMS:   m.SendSMS,
+		OutboxID:  e.ID.Hex(),
+	}
+}
+
+// OutboxRepository stores notifications waiting for delivery
+type OutboxRepository interface {
+	// Insert records the entry; an entry with the same DedupeKey is kept instead
+	Insert(ctx context.Context, e *OutboxEntry) error
+	// InsertMany records the entries, keeping any already stored under the same DedupeKey
+	InsertMany(ctx context.Context, entries []*OutboxEntry) error
+	// Claim leases the pending entry if it is due, returning nil when another
+	// worker holds it or it was already delivered
+	Claim(ctx context.Context, id primitive.ObjectID, now time.Time) (*OutboxEntry, error)
+	// ClaimNext leases the oldest due pending entry, or returns nil when none is due
+	ClaimNext(ctx context.Context, now time.Time) (*OutboxEntry, error)
+	MarkSent(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// Retry releases the entry for another attempt at next
+	Retry(ctx context.Context, id primitive.ObjectID, next time.Time, reason string) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error
+	// FindPending returns documents of collection that still embed pending notifications
+	FindPending(ctx context.Context, collection string, limit int64) ([]PendingDocument, error)
+	// ClearPending removes a pending notification once it is in the outbox
+	ClearPending(ctx context.Context, collection string, docID primitive.ObjectID, dedupeKey string) error
+}
+
+type outboxRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(db *database.MongoDB) OutboxRepository {
+	return &outboxRepository{db: db, collection: db.Collection("notification_outbox")}
+}
+
+func (r *outboxRepository) Insert(ctx context.Context, e *OutboxEntry) error {
+	_, err := r.collection.InsertOne(ctx, e)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *outboxRepository) InsertMany(ctx context.Context, entries []*OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(entries))
+	for i, e := range entries {
+		docs[i] = e
+	}
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if mongo.IsDuplicateKeyError(err) {
+		// Unordered inserts still store every entry that is not a duplicate
+		return nil
+	}
+	return err
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, id primitive.ObjectID, now time.Time) (*OutboxEntry, error) {
+	return r.claim(ctx, bson.M{"_id": id, "status": OutboxStatusPending, "next_attempt_at": bson.M{"$lte": now}}, now)
+}
+
+func (r *outboxRepository) ClaimNext(ctx context.Context, now time.Time) (*OutboxEntry, error) {
+	return r.claim(ctx, bson.M{"status": OutboxStatusPending, "next_attempt_at": bson.M{"$lte": now}}, now)
+}
+
+func (r *outboxRepository) claim(ctx context.Context, filter bson.M, now time.Time) (*OutboxEntry, error) {
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(outboxLease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	var e OutboxEntry
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&e); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": OutboxStatusSent, "sent_at": at}, "$unset": bson.M{"last_error": ""}},
+	)
+	return err
+}
+
+func (r *outboxRepository) Retry(ctx context.Context, id primitive.ObjectID, next time.Time, reason string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"next_attempt_at": next, "last_error": reason}},
+	)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": OutboxStatusFailed, "last_error": reason}},
+	)
+	return err
+}
+
+func (r *outboxRepository) FindPending(ctx context.Context, collection string, limit int64) ([]PendingDocument, error) {
+	filter := bson.M{PendingNotificationsField + ".dedupe_key": bson.M{"$exists": true}}
+	opts := options.Find().
+		SetProjection(bson.M{PendingNotificationsField: 1}).
+		SetLimit(limit)
+
+	cursor, err := r.db.Collection(collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []PendingDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *outboxRepository) ClearPending(ctx context.Context, collection string, docID primitive.ObjectID, dedupeKey string) error {
+	_, err := r.db.Collection(collection).UpdateOne(ctx,
+		bson.M{"_id": docID},
+		bson.M{"$pull": bson.M{PendingNotificationsField: bson.M{"dedupe_key": dedupeKey}}},
+	)
+	return err
+}
+
+// WithOutbox makes Enqueue record notifications in repo before delivering them
+func (s *Service) WithOutbox(repo OutboxRepository) *Service {
+	s.outbox = repo
+	return s
+}
+
+// WithOutboxSources lists the collections whose documents embed pending
+// notifications, for DispatchOutbox to sweep
+func (s *Service) WithOutboxSources(collections ...string) *Service {
+	s.outboxSources = collections
+	return s
+}
+
+// Enqueue moves a notification already embedded in docID of collection into
+// the outbox and tries to deliver it right away; failed deliveries are
+// retried by DispatchOutbox. Without an outbox the notification is sent
+// directly.
+func (s *Service) Enqueue(ctx context.Context, collection string, docID primitive.ObjectID, pending PendingNotification) error {
+	if s.outbox == nil {
+		return s.Send(ctx, pending.SendDTO())
+	}
+
+	now := time.Now()
+	entry, err := s.promote(ctx, collection, docID, pending, now)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := s.outbox.Claim(ctx, entry.ID, now)
+	if err != nil || claimed == nil {
+		return err
+	}
+	return s.deliverOutbox(ctx, claimed)
+}
+
+// promote inserts a pending notification into the outbox and removes it from
+// its document. An entry already in the outbox under the same key is kept, so
+// promoting twice does not send twice.
+func (s *Service) promote(ctx context.Context, collection string, docID primitive.ObjectID, pending PendingNotification, now time.Time) (*OutboxEntry, error) {
+	entry := pending.entry(now)
+	if err := s.outbox.Insert(ctx, entry); err != nil {
+		return nil, err
+	}
+	if err := s.outbox.ClearPending(ctx, collection, docID, pending.DedupeKey); err != nil {
+		// The next sweep promotes it again and the outbox keeps one entry
+		slog.Error("failed to clear pending notification", "collection", collection, "id", docID.Hex(), "dedupe_key", pending.DedupeKey, "error", err)
+	}
+	return entry, nil
+}
+
+// promotePending sweeps the notifications left embedded in their documents,
+// e.g. by a crash between a write and its Enqueue
+func (s *Service) promotePending(ctx context.Context) error {
+	for _, collection := range s.outboxSources {
+		docs, err := s.outbox.FindPending(ctx, collection, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			for _, pending := range doc.Pending {
+				if _, err := s.promote(ctx, collection, doc.ID, pending, time.Now()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DispatchOutbox moves the notifications still pending in their documents
+// into the outbox and delivers the entries that are due, returning how many
+// were sent
+func (s *Service) DispatchOutbox(ctx context.Context) (int, error) {
+	if s.outbox == nil {
+		return 0, nil
+	}
+	if err := s.promotePending(ctx); err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := 0; i < outboxBatchSize; i++ {
+		entry, err := s.outbox.ClaimNext(ctx, time.Now())
+		if err != nil {
+			return sent, err
+		}
+		if entry == nil {
+			break
+		}
+		if s.deliverOutbox(ctx, entry) == nil {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliverOutbox sends a claimed entry. Send skips entries already stored as
+// a notification, so a worker that died after sending does not send twice.
+// Failures are retried with a growing delay and dead-lettered after the
+// last attempt.
+func (s *Service) deliverOutbox(ctx context.Context, entry *OutboxEntry) error {
+	dto := entry.sendDTO()
+	sendErr := s.Send(ctx, dto)
+	if sendErr == nil {
+		if err := s.outbox.MarkSent(ctx, entry.ID, time.Now()); err != nil {
+			slog.Error("failed to mark outbox entry sent", "id", entry.ID.Hex(), "error", err)
+		}
+		return nil
+	}
+
+	if entry.Attempts >= outboxMaxAttempts {
+		if err := s.outbox.MarkFailed(ctx, entry.ID, sendErr.Error()); err != nil {
+			slog.Error("failed to mark outbox entry failed", "id", entry.ID.Hex(), "error", err)
+		}
+		s.RecordFailedSend(ctx, dto, entry.Source, sendErr)
+		return sendErr
+	}
+
+	next := time.Now().Add(time.Duration(entry.Attempts*entry.Attempts) * time.Minute)
+	if err := s.outbox.Retry(ctx, entry.ID, next, sendErr.Error()); err != nil {
+		slog.Error("failed to reschedule outbox entry", "id", entry.ID.Hex(), "error", err)
+	}
+	return sendErr
+}
@@ -0,0 +1,156 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eren_dev/go_server/internal/modules/owners"
+)
+
+// deferredBatchSize bounds how many held notifications one sweep delivers.
+const deferredBatchSize = 500
+
+// TenantQuietHours mirrors the clinic's quiet hours settings, read straight
+// from the tenants collection together with its timezone.
+type TenantQuietHours struct {
+	TimeZone    string   `bson:"-"`
+	Enabled     bool     `bson:"enabled"`
+	Start       string   `bson:"start"`
+	End         string   `bson:"end"`
+	UrgentTypes []string `bson:"urgent_types"`
+}
+
+// quietUntil returns when the quiet window covering now ends, or nil when the
+// notification can go out right away. The owner's own window wins over the
+// clinic's; urgent types set by the clinic are never held.
+func (s *Service) quietUntil(ctx context.Context, notif *Notification, now time.Time) *time.Time {
+	clinic, err := s.tenants.FindTenantQuietHours(ctx, notif.TenantID)
+	if err != nil {
+		slog.Warn("quiet hours: failed to load tenant settings, sending now", "tenant_id", notif.TenantID.Hex(), "error", err)
+		return nil
+	}
+	for _, t := range clinic.UrgentTypes {
+		if t == string(notif.Type) {
+			return nil
+		}
+	}
+
+	start, end := "", ""
+	if clinic.Enabled {
+		start, end = clinic.Start, clinic.End
+	}
+	if owner, err := s.ownerRepo.FindByID(ctx, notif.OwnerID.Hex()); err == nil && owner.NotificationPrefs.QuietHours.Enabled {
+		start, end = owner.NotificationPrefs.QuietHours.Start, owner.NotificationPrefs.QuietHours.End
+	}
+	if start == "" || end == "" {
+		return nil
+	}
+
+	loc := time.UTC
+	if clinic.TimeZone != "" {
+		if l, err := time.LoadLocation(clinic.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	until, ok := quietWindowEnd(start, end, now.In(loc))
+	if !ok {
+		return nil
+	}
+	return &until
+}
+
+// quietWindowEnd reports whether now falls in the daily window from start to
+// end (HH:MM, in now's location) and when that window ends. A start later than
+// end wraps past midnight.
+func quietWindowEnd(start, end string, now time.Time) (time.Time, bool) {
+	from, ok1 := minuteOfDay(start)
+	to, ok2 := minuteOfDay(end)
+	if !ok1 || !ok2 || from == to {
+		return time.Time{}, false
+	}
+
+	m := now.Hour()*60 + now.Minute()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endToday := day.Add(time.Duration(to) * time.Minute)
+
+	if from < to {
+		if m >= from && m < to {
+			return endToday, true
+		}
+		return time.Time{}, false
+	}
+	switch {
+	case m >= from:
+		return day.AddDate(0, 0, 1).Add(time.Duration(to) * time.Minute), true
+	case m < to:
+		return endToday, true
+	}
+	return time.Time{}, false
+}
+
+func minuteOfDay(hhmm string) (int, bool) {
+	h, m, found := strings.Cut(hhmm, ":")
+	if !found {
+		return 0, false
+	}
+	hour, err1 := strconv.Atoi(h)
+	minute, err2 := strconv.Atoi(m)
+	if err1 != nil || err2 != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// DeliverDeferred sends the notifications held by quiet hours whose window has
+// ended, over the channels recorded when they were created. It returns how many
+// were released.
+func (s *Service) DeliverDeferred(ctx context.Context) (int, error) {
+	due, err := s.repo.FindDeferredDue(ctx, time.Now(), deferredBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range due {
+		notif := &due[i]
+		ok, err := s.repo.ReleaseDeferred(ctx, notif.ID)
+		if err != nil {
+			slog.Error("quiet hours: failed to release notification", "notification_id", notif.ID.Hex(), "error", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		released++
+		s.dispatch(notif, notif.DeferredChannels)
+	}
+	return released, nil
+}
+
+// dispatch delivers notif over the given channels, skipping any whose sender
+// is not configured.
+func (s *Service) dispatch(notif *Notification, channels []string) {
+	var contact []string
+	for _, channel := range channels {
+		switch channel {
+		case ChannelPush:
+			if s.pushProvider != nil && s.pushProvider.IsEnabled() {
+				s.sendPushAsync(notif)
+			}
+		case owners.ContactChannelEmail:
+			if s.emailSender != nil && s.emailSender.IsEnabled() {
+				contact = append(contact, channel)
+			}
+		case owners.ContactChannelPhone:
+			if s.smsSender != nil && s.smsSender.IsEnabled() {
+				contact = append(contact, channel)
+			}
+		}
+	}
+	if len(contact) > 0 {
+		go s.sendContactChannels(notif, contact)
+	}
+}
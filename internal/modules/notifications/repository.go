@@ -2,6 +2,7 @@ package notifications
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,11 +16,18 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, n *Notification) error
-	FindByOwner(ctx context.Context, ownerID primitive.ObjectID, params pagination.Params) ([]Notification, int64, error)
-	CountUnread(ctx context.Context, ownerID primitive.ObjectID) (int64, error)
-	MarkAsRead(ctx context.Context, ownerID, notifID primitive.ObjectID) error
-	MarkAllAsRead(ctx context.Context, ownerID primitive.ObjectID) error
+	FindByOwner(ctx context.Context, ownerID, tenantID primitive.ObjectID, filters InboxFilters, params pagination.Params) ([]Notification, int64, error)
+	CountUnread(ctx context.Context, ownerID, tenantID primitive.ObjectID) (int64, error)
+	MarkAsRead(ctx context.Context, ownerID, tenantID, notifID primitive.ObjectID) error
+	MarkAllAsRead(ctx context.Context, ownerID, tenantID primitive.ObjectID) error
 	MarkPushSent(ctx context.Context, id primitive.ObjectID) error
+	// FindDeferredDue returns notifications held by quiet hours whose window has ended.
+	FindDeferredDue(ctx context.Context, now time.Time, limit int64) ([]Notification, error)
+	// ReleaseDeferred clears the hold and reports whether this caller did it,
+	// so concurrent sweeps deliver each notification once.
+	ReleaseDeferred(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// ExistsForOutbox reports whether the outbox entry was already stored as a notification
+	ExistsForOutbox(ctx context.Context, outboxID primitive.ObjectID) (bool, error)
 }
 
 type repository struct {
@@ -37,8 +45,17 @@ func (r *repository) Create(ctx context.Context, n *Notification) error {
 	return err
 }
 
-func (r *repository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID, params pagination.Params) ([]Notification, int64, error) {
-	filter := bson.M{"owner_id": ownerID}
+func (r *repository) FindByOwner(ctx context.Context, ownerID, tenantID primitive.ObjectID, filters InboxFilters, params pagination.Params) ([]Notification, int64, error) {
+	filter := bson.M{"owner_id": ownerID, "tenant_id": tenantID}
+	if filters.Read != nil {
+		filter["read"] = *filters.Read
+	}
+	if filters.Type != "" {
+		filter["type"] = filters.Type
+	}
+	if filters.DateFrom != nil {
+		filter["created_at"] = bson.M{"$gte": *filters.DateFrom}
+	}
 
 	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -64,18 +81,19 @@ func (r *repository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID
 	return results, total, nil
 }
 
-func (r *repository) CountUnread(ctx context.Context, ownerID primitive.ObjectID) (int64, error) {
+func (r *repository) CountUnread(ctx context.Context, ownerID, tenantID primitive.ObjectID) (int64, error) {
 	return r.collection.CountDocuments(ctx, bson.M{
-		"owner_id": ownerID,
-		"read":     false,
+		"owner_id":  ownerID,
+		"tenant_id": tenantID,
+		"read":      false,
 	})
 }
 
-func (r *repository) MarkAsRead(ctx context.Context, ownerID, notifID primitive.ObjectID) error {
+func (r *repository) MarkAsRead(ctx context.Context, ownerID, tenantID, notifID primitive.ObjectID) error {
 	now := time.Now()
 	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": notifID, "owner_id": ownerID},
+		bson.M{"_id": notifID, "owner_id": ownerID, "tenant_id": tenantID},
 		bson.M{"$set": bson.M{"read": true, "read_at": now}},
 	)
 	if err != nil {
@@ -87,11 +105,11 @@ func (r *repository) MarkAsRead(ctx context.Context, ownerID, notifID primitive.
 	return nil
 }
 
-func (r *repository) MarkAllAsRead(ctx context.Context, ownerID primitive.ObjectID) error {
+func (r *repository) MarkAllAsRead(ctx context.Context, ownerID, tenantID primitive.ObjectID) error {
 	now := time.Now()
 	_, err := r.collection.UpdateMany(
 		ctx,
-		bson.M{"owner_id": ownerID, "read": false},
+		bson.M{"owner_id": ownerID, "tenant_id": tenantID, "read": false},
 		bson.M{"$set": bson.M{"read": true, "read_at": now}},
 	)
 	return err
@@ -107,8 +125,46 @@ func (r *repository) MarkPushSent(ctx context.Context, id primitive.ObjectID) er
 	return err
 }
 
+func (r *repository) FindDeferredDue(ctx context.Context, now time.Time, limit int64) ([]Notification, error) {
+	opts := options.Find().
+		SetLimit(limit).
+		SetSort(bson.D{{Key: "deferred_until", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"deferred_until": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []Notification
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *repository) ReleaseDeferred(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deferred_until": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deferred_until": "", "deferred_channels": ""}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
 // --- Staff repository ---
 
+func (r *repository) ExistsForOutbox(ctx context.Context, outboxID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"outbox_id": outboxID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 type StaffRepository interface {
 	CreateStaff(ctx context.Context, n *StaffNotification) error
 	FindByUser(ctx context.Context, userID primitive.ObjectID, params pagination.Params) ([]StaffNotification, int64, error)
@@ -191,3 +247,369 @@ func (r *staffRepository) MarkAllStaffAsRead(ctx context.Context, userID primiti
 	)
 	return err
 }
+
+// --- Broadcast repository ---
+
+type BroadcastRepository interface {
+	Create(ctx context.Context, b *Broadcast) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Broadcast, error)
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]Broadcast, int64, error)
+	UpdateProgress(ctx context.Context, b *Broadcast) error
+	// CountDeliveries counts the broadcast's outbox entries that were sent and
+	// that failed for good
+	CountDeliveries(ctx context.Context, id primitive.ObjectID) (delivered, failed int, err error)
+	// Audience lookups read the appointments and patients collections directly so
+	// this package does not have to import those modules (they import notifications).
+	FindOwnerIDsWithUpcomingAppointments(ctx context.Context, tenantID primitive.ObjectID, from time.Time) ([]primitive.ObjectID, error)
+	FindOwnerIDsBySpecies(ctx context.Context, tenantID, speciesID primitive.ObjectID) ([]primitive.ObjectID, error)
+	FindOwnerIDsWithOverdueVaccine(ctx context.Context, tenantID primitive.ObjectID, vaccine string, asOf time.Time) ([]primitive.ObjectID, error)
+}
+
+type broadcastRepository struct {
+	collection   *mongo.Collection
+	outbox       *mongo.Collection
+	appointments *mongo.Collection
+	patients     *mongo.Collection
+}
+
+func NewBroadcastRepository(db *database.MongoDB) BroadcastRepository {
+	return &broadcastRepository{
+		collection:   db.Collection("notification_broadcasts"),
+		outbox:       db.Collection("notification_outbox"),
+		appointments: db.Collection("appointments"),
+		patients:     db.Collection("patients"),
+	}
+}
+
+func (r *broadcastRepository) Create(ctx context.Context, b *Broadcast) error {
+	_, err := r.collection.InsertOne(ctx, b)
+	return err
+}
+
+func (r *broadcastRepository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Broadcast, error) {
+	var b Broadcast
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID}).Decode(&b)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrBroadcastNotFound
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *broadcastRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]Broadcast, int64, error) {
+	filter := bson.M{"tenant_id": tenantID}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []Broadcast
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+func (r *broadcastRepository) UpdateProgress(ctx context.Context, b *Broadcast) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": b.ID},
+		bson.M{"$set": bson.M{
+			"status":       b.Status,
+			"recipients":   b.Recipients,
+			"enqueued":     b.Enqueued,
+			"delivered":    b.Delivered,
+			"skipped":      b.Skipped,
+			"failed":       b.Failed,
+			"completed_at": b.CompletedAt,
+		}},
+	)
+	return err
+}
+
+func (r *broadcastRepository) CountDeliveries(ctx context.Context, id primitive.ObjectID) (int, int, error) {
+	cursor, err := r.outbox.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"source":                    broadcastOutboxSource,
+			"message.data.broadcast_id": id.Hex(),
+			"status":                    bson.M{"$in": []string{OutboxStatusSent, OutboxStatusFailed}},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Status string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, 0, err
+	}
+
+	var delivered, failed int
+	for _, row := range rows {
+		switch row.Status {
+		case OutboxStatusSent:
+			delivered = row.Count
+		case OutboxStatusFailed:
+			failed = row.Count
+		}
+	}
+	return delivered, failed, nil
+}
+
+func (r *broadcastRepository) FindOwnerIDsWithUpcomingAppointments(ctx context.Context, tenantID primitive.ObjectID, from time.Time) ([]primitive.ObjectID, error) {
+	values, err := r.appointments.Distinct(ctx, "owner_id", bson.M{
+		"tenant_id":    tenantID,
+		"scheduled_at": bson.M{"$gte": from},
+		"status":       bson.M{"$in": []string{"scheduled", "confirmed"}},
+		"deleted_at":   nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toObjectIDs(values), nil
+}
+
+func (r *broadcastRepository) FindOwnerIDsBySpecies(ctx context.Context, tenantID, speciesID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	values, err := r.patients.Distinct(ctx, "owner_id", bson.M{
+		"tenant_id":  tenantID,
+		"species_id": speciesID,
+		"deleted_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toObjectIDs(values), nil
+}
+
+// FindOwnerIDsWithOverdueVaccine returns the owners of active patients whose
+// latest dose of the vaccine (matched by name, case-insensitive) is past due
+func (r *broadcastRepository) FindOwnerIDsWithOverdueVaccine(ctx context.Context, tenantID primitive.ObjectID, vaccine string, asOf time.Time) ([]primitive.ObjectID, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tenant_id": tenantID, "active": true, "deleted_at": nil}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "vaccinations",
+			"let":  bson.M{"patient_id": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"tenant_id":    tenantID,
+					"deleted_at":   nil,
+					"vaccine_name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(vaccine) + "$", Options: "i"},
+					"$expr":        bson.M{"$eq": bson.A{"$patient_id", "$$patient_id"}},
+				}},
+				bson.M{"$sort": bson.M{"application_date": -1}},
+				bson.M{"$limit": 1},
+			},
+			"as": "latest",
+		}}},
+		{{Key: "$match", Value: bson.M{"latest.0.next_due_date": bson.M{"$lt": asOf}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$owner_id"}}},
+	}
+
+	cursor, err := r.patients.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids, nil
+}
+
+func toObjectIDs(values []interface{}) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// --- Template repository ---
+
+type TemplateRepository interface {
+	Create(ctx context.Context, t *NotificationTemplate) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*NotificationTemplate, error)
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]NotificationTemplate, error)
+	// FindOne returns the tenant's override for key/locale, or nil when none exists.
+	FindOne(ctx context.Context, tenantID primitive.ObjectID, key TemplateKey, locale string) (*NotificationTemplate, error)
+	Update(ctx context.Context, t *NotificationTemplate) error
+	Delete(ctx context.Context, id, tenantID primitive.ObjectID) error
+	// FindTenantDefaultLocale reads the tenants collection directly because the
+	// tenant module cannot be imported from here without a cycle.
+	FindTenantDefaultLocale(ctx context.Context, tenantID primitive.ObjectID) (string, error)
+	// FindTenantRequiresVerifiedContacts reads the same way whether the clinic
+	// only emails or texts owners on verified contacts.
+	FindTenantRequiresVerifiedContacts(ctx context.Context, tenantID primitive.ObjectID) (bool, error)
+	// FindTenantQuietHours reads the clinic's timezone and quiet hours.
+	FindTenantQuietHours(ctx context.Context, tenantID primitive.ObjectID) (TenantQuietHours, error)
+}
+
+type templateRepository struct {
+	collection *mongo.Collection
+	tenants    *mongo.Collection
+}
+
+func NewTemplateRepository(db *database.MongoDB) TemplateRepository {
+	return &templateRepository{
+		collection: db.Collection("notification_templates"),
+		tenants:    db.Collection("tenants"),
+	}
+}
+
+func (r *templateRepository) Create(ctx context.Context, t *NotificationTemplate) error {
+	_, err := r.collection.InsertOne(ctx, t)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrTemplateAlreadyExists
+	}
+	return err
+}
+
+func (r *templateRepository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*NotificationTemplate, error) {
+	var t NotificationTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID}).Decode(&t)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *templateRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]NotificationTemplate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "key", Value: 1}, {Key: "locale", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []NotificationTemplate
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *templateRepository) FindOne(ctx context.Context, tenantID primitive.ObjectID, key TemplateKey, locale string) (*NotificationTemplate, error) {
+	var t NotificationTemplate
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "key": key, "locale": locale}).Decode(&t)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *templateRepository) Update(ctx context.Context, t *NotificationTemplate) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": t.ID, "tenant_id": t.TenantID},
+		bson.M{"$set": bson.M{"title": t.Title, "body": t.Body, "updated_at": t.UpdatedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// Delete removes the override so the built-in default applies again.
+func (r *templateRepository) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "tenant_id": tenantID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (r *templateRepository) FindTenantDefaultLocale(ctx context.Context, tenantID primitive.ObjectID) (string, error) {
+	var doc struct {
+		Settings struct {
+			DefaultLocale string `bson:"default_locale"`
+		} `bson:"settings"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"settings.default_locale": 1})
+	err := r.tenants.FindOne(ctx, bson.M{"_id": tenantID}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		return "", err
+	}
+	return doc.Settings.DefaultLocale, nil
+}
+
+func (r *templateRepository) FindTenantRequiresVerifiedContacts(ctx context.Context, tenantID primitive.ObjectID) (bool, error) {
+	var doc struct {
+		Settings struct {
+			RequireVerifiedContacts bool `bson:"require_verified_contacts"`
+		} `bson:"settings"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"settings.require_verified_contacts": 1})
+	err := r.tenants.FindOne(ctx, bson.M{"_id": tenantID}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+	return doc.Settings.RequireVerifiedContacts, nil
+}
+
+func (r *templateRepository) FindTenantQuietHours(ctx context.Context, tenantID primitive.ObjectID) (TenantQuietHours, error) {
+	var doc struct {
+		TimeZone string `bson:"time_zone"`
+		Settings struct {
+			QuietHours TenantQuietHours `bson:"quiet_hours"`
+		} `bson:"settings"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"time_zone": 1, "settings.quiet_hours": 1})
+	err := r.tenants.FindOne(ctx, bson.M{"_id": tenantID}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return TenantQuietHours{}, nil
+		}
+		return TenantQuietHours{}, err
+	}
+	doc.Settings.QuietHours.TimeZone = doc.TimeZone
+	return doc.Settings.QuietHours, nil
+}
@@ -1,6 +1,7 @@
 package notifications
 
 import (
+	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	platformNotifications "github.com/eren_dev/go_server/internal/platform/notifications"
 	"github.com/eren_dev/go_server/internal/shared/database"
@@ -11,18 +12,22 @@ func newService(db *database.MongoDB, pushProvider platformNotifications.PushPro
 	return NewService(
 		NewRepository(db),
 		NewStaffRepository(db),
+		NewTemplateRepository(db),
 		owners.NewRepository(db),
 		pushProvider,
 	)
 }
 
-func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider) {
+// RegisterMobileRoutes registers the owner inbox (JWT + Tenant + OwnerGuard).
+// Every query is scoped to both the authenticated owner and the X-Tenant-ID tenant.
+func RegisterMobileRoutes(mobileTenant *httpx.Router, db *database.MongoDB, pushProvider platformNotifications.PushProvider) {
 	service := newService(db, pushProvider)
 	handler := NewHandler(service)
 
-	notifs := mobile.Group("/notifications")
+	notifs := mobileTenant.Group("/notifications")
 	notifs.GET("", handler.GetAll)
 	notifs.GET("/unread-count", handler.GetUnreadCount)
+	notifs.POST("/read-all", handler.MarkAllAsRead)
 	notifs.PATCH("/read-all", handler.MarkAllAsRead)
 	notifs.PATCH("/:id/read", handler.MarkAsRead)
 }
@@ -37,3 +42,41 @@ func RegisterAdminRoutes(authPrivate *httpx.Router, db *database.MongoDB, pushPr
 	notifs.PATCH("/read-all", handler.MarkAllAsRead)
 	notifs.PATCH("/:id/read", handler.MarkAsRead)
 }
+
+// RegisterBroadcastRoutes registers owner broadcast routes (JWT + Tenant + RBAC).
+// RBAC resource "broadcast" is only granted to the admin role.
+func RegisterBroadcastRoutes(privateTenant *httpx.Router, db *database.MongoDB, cfg *config.Config) {
+	service := NewBroadcastService(
+		NewBroadcastRepository(db),
+		NewOutboxRepository(db),
+		owners.NewRepository(db),
+		NewBroadcastLimiter(cfg.NotificationBroadcastsPerHour),
+	)
+	handler := NewBroadcastHandler(service)
+
+	broadcasts := privateTenant.Group("/notifications/broadcast")
+	broadcasts.POST("", handler.Create)
+	broadcasts.GET("", handler.List)
+	broadcasts.GET("/:id", handler.Get)
+}
+
+// RegisterTemplateRoutes registers notification template management (JWT + Tenant + RBAC).
+// RBAC resource "templates" is only granted to the admin role.
+func RegisterTemplateRoutes(privateTenant *httpx.Router, db *database.MongoDB) {
+	handler := NewTemplateHandler(NewTemplateService(NewTemplateRepository(db)))
+
+	templates := privateTenant.Group("/notifications/templates")
+	templates.GET("", handler.List)
+	templates.POST("", handler.Create)
+	templates.PUT("/:id", handler.Update)
+	templates.DELETE("/:id", handler.Delete)
+}
+
+// RegisterDeadLetterRoutes registers the listing of undelivered notifications (JWT + Tenant + RBAC).
+// RBAC resource "dead-letters" is only granted to the admin role.
+func RegisterDeadLetterRoutes(privateTenant *httpx.Router, db *database.MongoDB) {
+	service := newService(db, nil).WithDeadLetters(NewDeadLetterRepository(db), nil)
+	handler := NewDeadLetterHandler(service)
+
+	privateTenant.GET("/notifications/dead-letters", handler.List)
+}
@@ -15,9 +15,13 @@ const (
 	TypeAppointmentCancelled NotificationType = "appointment_cancelled"
 	TypeAppointmentReminder  NotificationType = "appointment_reminder"
 	TypeVaccinationDue       NotificationType = "vaccination_due"
+	TypeDueDigest            NotificationType = "due_digest"
 	TypeMedicalRecordCreated NotificationType = "medical_record_created"
 	TypeMedicalRecordUpdated NotificationType = "medical_record_updated"
 	TypePrescriptionReady    NotificationType = "prescription_ready"
+	TypeLabResultReady       NotificationType = "lab_result_ready"
+	TypeCareReminder         NotificationType = "care_reminder"
+	TypeAnnouncement         NotificationType = "announcement"
 	TypeGeneral              NotificationType = "general"
 )
 
@@ -34,9 +38,19 @@ type Notification struct {
 	ReadAt     *time.Time        `bson:"read_at,omitempty"`
 	PushSent   bool              `bson:"push_sent"`
 	PushSentAt *time.Time        `bson:"push_sent_at,omitempty"`
-	CreatedAt  time.Time         `bson:"created_at"`
+	// DeferredUntil is set when the notification was created during quiet
+	// hours; DeferredChannels (push, email, phone) go out once it passes.
+	DeferredUntil    *time.Time `bson:"deferred_until,omitempty"`
+	DeferredChannels []string   `bson:"deferred_channels,omitempty"`
+	// OutboxID links a notification delivered from the outbox to its entry
+	OutboxID  *primitive.ObjectID `bson:"outbox_id,omitempty"`
+	CreatedAt time.Time           `bson:"created_at"`
 }
 
+// ChannelPush names push delivery among a notification's deferred channels;
+// email and phone reuse the owners contact channel names.
+const ChannelPush = "push"
+
 // --- Staff notification types ---
 
 type StaffNotificationType string
@@ -46,7 +60,10 @@ const (
 	TypeStaffPaymentReceived StaffNotificationType = "payment_received"
 	TypeStaffNewPatient      StaffNotificationType = "new_patient"
 	TypeStaffSystemAlert     StaffNotificationType = "system_alert"
+	TypeStaffShiftPublished  StaffNotificationType = "shift_published"
 	TypeStaffGeneral         StaffNotificationType = "general"
+	// TypeStaffAppointmentStatus goes to staff subscribed to appointment status changes
+	TypeStaffAppointmentStatus StaffNotificationType = "appointment_status"
 )
 
 // StaffNotification is stored in the staff_notifications collection.
@@ -59,7 +76,58 @@ type StaffNotification struct {
 	Title     string                `bson:"title"`
 	Body      string                `bson:"body"`
 	Data      map[string]string     `bson:"data,omitempty"`
+	Urgent    bool                  `bson:"urgent,omitempty"` // highlighted in the admin panel, e.g. emergency appointments
 	Read      bool                  `bson:"read"`
 	ReadAt    *time.Time            `bson:"read_at,omitempty"`
 	CreatedAt time.Time             `bson:"created_at"`
 }
+
+// --- Broadcasts ---
+
+// BroadcastAudience selects which owners of a tenant receive a broadcast.
+type BroadcastAudience string
+
+const (
+	AudienceAllOwners            BroadcastAudience = "all"
+	AudienceUpcomingAppointments BroadcastAudience = "upcoming_appointments"
+	AudienceSpecies              BroadcastAudience = "species"
+	// AudienceVaccineOverdue reaches owners of active patients whose latest dose
+	// of VaccineName is past due, as counted by the vaccination coverage report
+	AudienceVaccineOverdue BroadcastAudience = "vaccine_overdue"
+)
+
+type BroadcastStatus string
+
+const (
+	BroadcastStatusQueued     BroadcastStatus = "queued"
+	BroadcastStatusProcessing BroadcastStatus = "processing"
+	BroadcastStatusCompleted  BroadcastStatus = "completed"
+	// BroadcastStatusFailed means the fan-out could not be fully queued; the
+	// Enqueued owners are still notified
+	BroadcastStatusFailed BroadcastStatus = "failed"
+)
+
+// Broadcast is stored in the notification_broadcasts collection.
+// It records a one-to-many announcement and the outcome of its fan-out.
+type Broadcast struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID    primitive.ObjectID  `bson:"tenant_id"`
+	CreatedBy   primitive.ObjectID  `bson:"created_by"`
+	Type        NotificationType    `bson:"type"`
+	Title       string              `bson:"title"`
+	Body        string              `bson:"body"`
+	Audience    BroadcastAudience   `bson:"audience"`
+	SpeciesID   *primitive.ObjectID `bson:"species_id,omitempty"`
+	VaccineName string              `bson:"vaccine_name,omitempty"`
+	SendPush    bool                `bson:"send_push"`
+	Status      BroadcastStatus     `bson:"status"`
+	// Delivery counters. Skipped counts owners who muted this notification type,
+	// Enqueued the outbox entries recorded for the others.
+	Recipients  int        `bson:"recipients"`
+	Enqueued    int        `bson:"enqueued"`
+	Delivered   int        `bson:"delivered"`
+	Skipped     int        `bson:"skipped"`
+	Failed      int        `bson:"failed"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty"`
+}
@@ -2,32 +2,54 @@ package notifications
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/platform/email"
 	"github.com/eren_dev/go_server/internal/platform/notifications"
+	"github.com/eren_dev/go_server/internal/platform/sms"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
 type Service struct {
 	repo         Repository
 	staffRepo    StaffRepository
+	templates    *TemplateService
 	ownerRepo    owners.OwnerRepository
 	pushProvider notifications.PushProvider
+	tenants      TemplateRepository
+	emailSender  email.EmailSender
+	smsSender    sms.SMSSender
+	deadLetters  DeadLetterRepository
+	dropMetrics  DropMetrics
+	outbox       OutboxRepository
+	// outboxSources are the collections swept for embedded pending notifications
+	outboxSources []string
 }
 
-func NewService(repo Repository, staffRepo StaffRepository, ownerRepo owners.OwnerRepository, pushProvider notifications.PushProvider) *Service {
+func NewService(repo Repository, staffRepo StaffRepository, templateRepo TemplateRepository, ownerRepo owners.OwnerRepository, pushProvider notifications.PushProvider) *Service {
 	return &Service{
 		repo:         repo,
 		staffRepo:    staffRepo,
+		templates:    NewTemplateService(templateRepo),
 		ownerRepo:    ownerRepo,
 		pushProvider: pushProvider,
+		tenants:      templateRepo,
 	}
 }
 
+// WithContactChannels enables email and SMS delivery for SendDTO.SendEmail
+// and SendDTO.SendSMS. Either sender may be nil.
+func (s *Service) WithContactChannels(emailSender email.EmailSender, smsSender sms.SMSSender) *Service {
+	s.emailSender = emailSender
+	s.smsSender = smsSender
+	return s
+}
+
 // Send persists the notification and optionally delivers a push via FCM.
 // This is the single entry point for ALL other modules to trigger notifications.
 func (s *Service) Send(ctx context.Context, dto *SendDTO) error {
@@ -40,6 +62,33 @@ func (s *Service) Send(ctx context.Context, dto *SendDTO) error {
 		return err
 	}
 
+	var outboxID *primitive.ObjectID
+	if dto.OutboxID != "" {
+		id, err := primitive.ObjectIDFromHex(dto.OutboxID)
+		if err != nil {
+			return err
+		}
+		sent, err := s.repo.ExistsForOutbox(ctx, id)
+		if err != nil || sent {
+			return err
+		}
+		outboxID = &id
+	}
+
+	if dto.Template != "" {
+		data := TemplateData{Vars: dto.Vars, Dates: dto.Dates, Times: dto.Times}
+		rendered, err := s.templates.Render(ctx, tenantID, dto.Template, s.localesFor(ctx, ownerID, tenantID), data)
+		if err != nil {
+			slog.Error("notification template render failed", "template", dto.Template, "tenant_id", dto.TenantID, "error", err)
+			return err
+		}
+		dto.Title = rendered.Title
+		dto.Body = rendered.Body
+		if dto.Type == "" {
+			dto.Type = rendered.Type
+		}
+	}
+
 	notif := &Notification{
 		ID:        primitive.NewObjectID(),
 		OwnerID:   ownerID,
@@ -50,67 +99,209 @@ func (s *Service) Send(ctx context.Context, dto *SendDTO) error {
 		Data:      dto.Data,
 		Read:      false,
 		PushSent:  false,
+		OutboxID:  outboxID,
 		CreatedAt: time.Now(),
 	}
 
+	var channels []string
+	if dto.SendPush {
+		channels = append(channels, ChannelPush)
+	}
+	if dto.SendEmail {
+		channels = append(channels, owners.ContactChannelEmail)
+	}
+	if dto.SendSMS {
+		channels = append(channels, owners.ContactChannelPhone)
+	}
+
+	// During quiet hours the notification is stored now and delivered by the
+	// scheduler once the window ends
+	if len(channels) > 0 {
+		if until := s.quietUntil(ctx, notif, notif.CreatedAt); until != nil {
+			notif.DeferredUntil = until
+			notif.DeferredChannels = channels
+			return s.repo.Create(ctx, notif)
+		}
+	}
+
 	if err := s.repo.Create(ctx, notif); err != nil {
 		return err
 	}
 
-	if dto.SendPush && s.pushProvider != nil && s.pushProvider.IsEnabled() {
-		s.sendPushAsync(notif)
+	s.dispatch(notif, channels)
+	return nil
+}
+
+// sendContactChannels delivers the notification by email and/or SMS. When the
+// clinic requires verified contacts, channels the owner has not verified are
+// skipped so messages do not bounce or reach a wrong number.
+func (s *Service) sendContactChannels(notif *Notification, channels []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	owner, err := s.ownerRepo.FindByID(ctx, notif.OwnerID.Hex())
+	if err != nil {
+		slog.Warn("contact: owner not found", "owner_id", notif.OwnerID.Hex())
+		return
+	}
+	requireVerified, err := s.tenants.FindTenantRequiresVerifiedContacts(ctx, notif.TenantID)
+	if err != nil {
+		slog.Warn("contact: failed to load tenant settings, requiring verified contacts", "tenant_id", notif.TenantID.Hex(), "error", err)
+		requireVerified = true
 	}
 
-	return nil
+	for _, channel := range channels {
+		target := owner.Contact(channel)
+		if target == "" {
+			continue
+		}
+		if requireVerified && !owner.ContactVerified(channel) {
+			slog.Info("contact: skipping unverified channel", "notification_id", notif.ID.Hex(), "channel", channel)
+			continue
+		}
+
+		if channel == owners.ContactChannelPhone {
+			err = s.smsSender.Send(ctx, sms.Message{To: target, Body: notif.Title + ": " + notif.Body})
+		} else {
+			err = s.emailSender.Send(ctx, email.Message{To: []string{target}, Subject: notif.Title, Body: notif.Body})
+		}
+		if err != nil {
+			slog.Error("contact: send failed", "notification_id", notif.ID.Hex(), "channel", channel, "error", err)
+		}
+	}
 }
 
-// sendPushAsync collects active push tokens for the owner and fires FCM in the background.
+// localesFor returns the owner's preferred locale followed by the clinic's
+// default; the template service appends DefaultLocale as the last resort.
+func (s *Service) localesFor(ctx context.Context, ownerID, tenantID primitive.ObjectID) []string {
+	var locales []string
+	if owner, err := s.ownerRepo.FindByID(ctx, ownerID.Hex()); err == nil && owner.Locale != "" {
+		locales = append(locales, owner.Locale)
+	}
+	if locale := s.templates.DefaultLocaleFor(ctx, tenantID); locale != "" {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// sendPushAsync hands the push to the push pool when the provider has one, so
+// bulk sweeps queue behind a bounded number of FCM calls instead of opening
+// one per notification. The owner's tokens are looked up on the pool worker
+// and the enqueue runs in the background, so neither waits on the caller.
 func (s *Service) sendPushAsync(notif *Notification) {
+	if pool, ok := s.pushProvider.(notifications.AsyncPushProvider); ok {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			err := pool.Enqueue(ctx, notifications.PushJob{
+				Tokens: func(ctx context.Context) []string {
+					return s.activePushTokens(ctx, notif)
+				},
+				Payload: pushPayload(notif),
+				Done: func(tokens []string, err error) {
+					s.afterPush(notif, tokens, err)
+				},
+			})
+			if err != nil {
+				slog.Error("push: failed to enqueue", "notification_id", notif.ID.Hex(), "error", err)
+			}
+		}()
+		return
+	}
+
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		owner, err := s.ownerRepo.FindByID(ctx, notif.OwnerID.Hex())
-		if err != nil {
-			slog.Warn("push: owner not found", "owner_id", notif.OwnerID.Hex())
+		tokens := s.activePushTokens(ctx, notif)
+		if len(tokens) == 0 {
 			return
 		}
+		s.afterPush(notif, tokens, s.pushProvider.Send(ctx, tokens, pushPayload(notif)))
+	}()
+}
 
-		tokens := make([]string, 0, len(owner.PushTokens))
-		for _, pt := range owner.PushTokens {
-			if pt.Active {
-				tokens = append(tokens, pt.Token)
-			}
-		}
+func (s *Service) activePushTokens(ctx context.Context, notif *Notification) []string {
+	owner, err := s.ownerRepo.FindByID(ctx, notif.OwnerID.Hex())
+	if err != nil {
+		slog.Warn("push: owner not found", "owner_id", notif.OwnerID.Hex())
+		return nil
+	}
 
-		if len(tokens) == 0 {
-			return
+	tokens := make([]string, 0, len(owner.PushTokens))
+	for _, pt := range owner.PushTokens {
+		if pt.Active {
+			tokens = append(tokens, pt.Token)
 		}
+	}
+	return tokens
+}
 
-		payload := notifications.PushPayload{
-			Title: notif.Title,
-			Body:  notif.Body,
-			Data:  notif.Data,
-		}
+func pushPayload(notif *Notification) notifications.PushPayload {
+	return notifications.PushPayload{
+		Title: notif.Title,
+		Body:  notif.Body,
+		Data:  notif.Data,
+	}
+}
 
-		if err := s.pushProvider.Send(ctx, tokens, payload); err != nil {
-			slog.Error("push: FCM send failed", "notification_id", notif.ID.Hex(), "error", err)
-			return
+// afterPush records the outcome of a push send. Tokens FCM no longer knows are
+// removed from the owner; the send only counts as failed when none was left.
+func (s *Service) afterPush(notif *Notification, tokens []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var invalid *notifications.InvalidTokensError
+	if errors.As(err, &invalid) {
+		s.removeInvalidTokens(ctx, notif.OwnerID, invalid.Tokens)
+		if len(invalid.Tokens) < len(tokens) {
+			err = nil
 		}
+	}
+
+	if err != nil {
+		slog.Error("push: FCM send failed", "notification_id", notif.ID.Hex(), "error", err)
+		s.recordDrop(ctx, &DeadLetter{
+			TenantID:       notif.TenantID,
+			OwnerID:        &notif.OwnerID,
+			NotificationID: &notif.ID,
+			Type:           string(notif.Type),
+			Title:          notif.Title,
+			Data:           notif.Data,
+			Source:         DeadLetterSourcePush,
+			Reason:         err.Error(),
+		})
+		return
+	}
+	if err := s.repo.MarkPushSent(ctx, notif.ID); err != nil {
+		slog.Warn("push: failed to mark push_sent", "notification_id", notif.ID.Hex())
+	}
+}
 
-		if err := s.repo.MarkPushSent(ctx, notif.ID); err != nil {
-			slog.Warn("push: failed to mark push_sent", "notification_id", notif.ID.Hex())
+func (s *Service) removeInvalidTokens(ctx context.Context, ownerID primitive.ObjectID, tokens []string) {
+	for _, token := range tokens {
+		if err := s.ownerRepo.RemovePushToken(ctx, ownerID.Hex(), token); err != nil {
+			slog.Warn("push: failed to remove invalid token", "owner_id", ownerID.Hex(), "error", err)
 		}
-	}()
+	}
+	slog.Info("push: removed invalid tokens", "owner_id", ownerID.Hex(), "count", len(tokens))
 }
 
-func (s *Service) GetForOwner(ctx context.Context, ownerID string, params pagination.Params) (*PaginatedNotificationsResponse, error) {
+// GetForOwner lists the owner's notifications within a tenant, newest first,
+// together with the owner's unread count for that tenant.
+func (s *Service) GetForOwner(ctx context.Context, ownerID string, tenantID primitive.ObjectID, filters InboxFilters, params pagination.Params) (*PaginatedNotificationsResponse, error) {
 	oid, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
 		return nil, err
 	}
 
-	items, total, err := s.repo.FindByOwner(ctx, oid, params)
+	items, total, err := s.repo.FindByOwner(ctx, oid, tenantID, filters, params)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.repo.CountUnread(ctx, oid, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -121,18 +312,19 @@ func (s *Service) GetForOwner(ctx context.Context, ownerID string, params pagina
 	}
 
 	return &PaginatedNotificationsResponse{
-		Data:       data,
-		Pagination: pagination.NewPaginationInfo(params, total),
+		Data:        data,
+		Pagination:  pagination.NewPaginationInfo(params, total),
+		UnreadCount: unread,
 	}, nil
 }
 
-func (s *Service) GetUnreadCount(ctx context.Context, ownerID string) (*UnreadCountResponse, error) {
+func (s *Service) GetUnreadCount(ctx context.Context, ownerID string, tenantID primitive.ObjectID) (*UnreadCountResponse, error) {
 	oid, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
 		return nil, err
 	}
 
-	count, err := s.repo.CountUnread(ctx, oid)
+	count, err := s.repo.CountUnread(ctx, oid, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +332,7 @@ func (s *Service) GetUnreadCount(ctx context.Context, ownerID string) (*UnreadCo
 	return &UnreadCountResponse{Count: count}, nil
 }
 
-func (s *Service) MarkAsRead(ctx context.Context, ownerID, notifID string) error {
+func (s *Service) MarkAsRead(ctx context.Context, ownerID string, tenantID primitive.ObjectID, notifID string) error {
 	oid, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
 		return err
@@ -149,15 +341,15 @@ func (s *Service) MarkAsRead(ctx context.Context, ownerID, notifID string) error
 	if err != nil {
 		return ErrInvalidNotificationID
 	}
-	return s.repo.MarkAsRead(ctx, oid, nid)
+	return s.repo.MarkAsRead(ctx, oid, tenantID, nid)
 }
 
-func (s *Service) MarkAllAsRead(ctx context.Context, ownerID string) error {
+func (s *Service) MarkAllAsRead(ctx context.Context, ownerID string, tenantID primitive.ObjectID) error {
 	oid, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
 		return err
 	}
-	return s.repo.MarkAllAsRead(ctx, oid)
+	return s.repo.MarkAllAsRead(ctx, oid, tenantID)
 }
 
 // --- Staff notification methods ---
@@ -180,6 +372,7 @@ func (s *Service) SendToStaff(ctx context.Context, dto *SendStaffDTO) error {
 		Title:     dto.Title,
 		Body:      dto.Body,
 		Data:      dto.Data,
+		Urgent:    dto.Urgent,
 		Read:      false,
 		CreatedAt: time.Now(),
 	}
@@ -0,0 +1,300 @@
+package notifications
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TemplateKey names a notification message. A NotificationType can be used by
+// more than one message (e.g. a manual and an automatic cancellation), so
+// templates are keyed by message rather than by type.
+type TemplateKey string
+
+const (
+	TemplateAppointmentScheduled     TemplateKey = "appointment_scheduled"
+	TemplateAppointmentConfirmed     TemplateKey = "appointment_confirmed"
+	TemplateAppointmentAutoConfirmed TemplateKey = "appointment_auto_confirmed"
+	TemplateAppointmentCancelled     TemplateKey = "appointment_cancelled"
+	TemplateAppointmentAutoCancelled TemplateKey = "appointment_auto_cancelled"
+	TemplateAppointmentReminder      TemplateKey = "appointment_reminder"
+	TemplateAppointmentDepositDue    TemplateKey = "appointment_deposit_due"
+	TemplateAppointmentDepositPaid   TemplateKey = "appointment_deposit_paid"
+	TemplateAppointmentDepositLapsed TemplateKey = "appointment_deposit_lapsed"
+	TemplateAppointmentRescheduled   TemplateKey = "appointment_rescheduled"
+	TemplateAppointmentDelayed       TemplateKey = "appointment_delayed"
+	TemplateRescheduleDeclined       TemplateKey = "appointment_reschedule_declined"
+	TemplateVaccinationRegistered    TemplateKey = "vaccination_registered"
+	TemplateVaccinationOverdue       TemplateKey = "vaccination_overdue"
+	TemplateMedicalRecordCreated     TemplateKey = "medical_record_created"
+	TemplateNextVisitScheduled       TemplateKey = "next_visit_scheduled"
+	TemplateLabResultReady           TemplateKey = "lab_result_ready"
+	TemplateAgeReminder              TemplateKey = "age_reminder"
+)
+
+// DefaultLocale is used when neither the owner nor the tenant specify one.
+const DefaultLocale = "es"
+
+// localeFormat holds the date layouts used when rendering dates for a locale.
+type localeFormat struct {
+	Date     string
+	DateTime string
+}
+
+var localeFormats = map[string]localeFormat{
+	"es": {Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"en": {Date: "01/02/2006", DateTime: "01/02/2006 3:04 PM"},
+}
+
+// TemplateData carries the values substituted into a template. Dates and
+// Times are formatted with the conventions of the locale that is finally
+// rendered (date only and date with time, respectively).
+type TemplateData struct {
+	Vars  map[string]string
+	Dates map[string]time.Time
+	Times map[string]time.Time
+}
+
+// values merges Vars with the dates formatted for locale.
+func (d TemplateData) values(locale string) map[string]string {
+	format, ok := localeFormats[locale]
+	if !ok {
+		format = localeFormats[DefaultLocale]
+	}
+
+	values := make(map[string]string, len(d.Vars)+len(d.Dates)+len(d.Times))
+	for k, v := range d.Vars {
+		values[k] = v
+	}
+	for k, t := range d.Dates {
+		values[k] = t.Format(format.Date)
+	}
+	for k, t := range d.Times {
+		values[k] = t.Format(format.DateTime)
+	}
+	return values
+}
+
+// NotificationTemplate is a tenant-specific override stored in the
+// notification_templates collection. Built-in defaults are never persisted.
+type NotificationTemplate struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  primitive.ObjectID `bson:"tenant_id"`
+	Key       TemplateKey        `bson:"key"`
+	Locale    string             `bson:"locale"`
+	Title     string             `bson:"title"`
+	Body      string             `bson:"body"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// templateDefinition describes a message: its notification type, the
+// variables callers must supply and the built-in wording per locale.
+type templateDefinition struct {
+	Type      NotificationType
+	Variables []string
+	Defaults  map[string]templateText
+}
+
+type templateText struct {
+	Title string
+	Body  string
+}
+
+var templateDefinitions = map[TemplateKey]templateDefinition{
+	TemplateAppointmentScheduled: {
+		Type:      TypeAppointmentReminder,
+		Variables: []string{"patient_name", "date"},
+		Defaults: map[string]templateText{
+			"es": {"Nueva cita agendada", "Se ha agendado una cita para {{patient_name}} el {{date}}"},
+			"en": {"New appointment scheduled", "An appointment for {{patient_name}} was scheduled for {{date}}"},
+		},
+	},
+	TemplateAppointmentConfirmed: {
+		Type:      TypeAppointmentConfirmed,
+		Variables: []string{"date"},
+		Defaults: map[string]templateText{
+			"es": {"Cita confirmada", "Tu cita del {{date}} ha sido confirmada"},
+			"en": {"Appointment confirmed", "Your appointment on {{date}} has been confirmed"},
+		},
+	},
+	TemplateAppointmentAutoConfirmed: {
+		Type:      TypeAppointmentConfirmed,
+		Variables: []string{"patient_name", "date"},
+		Defaults: map[string]templateText{
+			"es": {"Cita confirmada", "Tu cita para {{patient_name}} del {{date}} ha sido confirmada"},
+			"en": {"Appointment confirmed", "Your appointment for {{patient_name}} on {{date}} has been confirmed"},
+		},
+	},
+	TemplateAppointmentCancelled: {
+		Type:      TypeAppointmentCancelled,
+		Variables: []string{"date", "reason"},
+		Defaults: map[string]templateText{
+			"es": {"Cita cancelada", "La cita del {{date}} ha sido cancelada. Razón: {{reason}}"},
+			"en": {"Appointment cancelled", "Your appointment on {{date}} has been cancelled. Reason: {{reason}}"},
+		},
+	},
+	TemplateAppointmentAutoCancelled: {
+		Type:      TypeAppointmentCancelled,
+		Variables: []string{"date"},
+		Defaults: map[string]templateText{
+			"es": {"Cita cancelada automáticamente", "La cita del {{date}} fue cancelada por no ser confirmada en 24 horas"},
+			"en": {"Appointment cancelled automatically", "Your appointment on {{date}} was cancelled because it was not confirmed within 24 hours"},
+		},
+	},
+	TemplateAppointmentReminder: {
+		Type: TypeAppointmentReminder,
+		// timeframe ("24 horas") predates hours and is still supplied for
+		// tenant overrides written with it
+		Variables: []string{"hours", "date", "timeframe"},
+		Defaults: map[string]templateText{
+			"es": {"Recordatorio de cita", "Tu cita es en {{hours}} horas ({{date}})"},
+			"en": {"Appointment reminder", "Your appointment is in {{hours}} hours ({{date}})"},
+		},
+	},
+	TemplateAppointmentDepositDue: {
+		Type:      TypeAppointmentReminder,
+		Variables: []string{"patient_name", "date", "amount", "expires_at"},
+		Defaults: map[string]templateText{
+			"es": {"Anticipo pendiente", "Para reservar la cita de {{patient_name}} del {{date}} paga el anticipo de {{amount}} antes del {{expires_at}}"},
+			"en": {"Deposit required", "To book the appointment for {{patient_name}} on {{date}}, pay the {{amount}} deposit before {{expires_at}}"},
+		},
+	},
+	TemplateAppointmentDepositPaid: {
+		Type:      TypeAppointmentConfirmed,
+		Variables: []string{"date"},
+		Defaults: map[string]templateText{
+			"es": {"Anticipo recibido", "Recibimos tu anticipo; la cita del {{date}} quedó reservada"},
+			"en": {"Deposit received", "We received your deposit; your appointment on {{date}} is booked"},
+		},
+	},
+	TemplateAppointmentDepositLapsed: {
+		Type:      TypeAppointmentCancelled,
+		Variables: []string{"date"},
+		Defaults: map[string]templateText{
+			"es": {"Cita liberada", "La cita del {{date}} se canceló porque el anticipo no se pagó a tiempo"},
+			"en": {"Appointment released", "Your appointment on {{date}} was cancelled because the deposit was not paid in time"},
+		},
+	},
+	TemplateAppointmentRescheduled: {
+		Type:      TypeAppointmentConfirmed,
+		Variables: []string{"patient_name", "date"},
+		Defaults: map[string]templateText{
+			"es": {"Cita reprogramada", "La cita de {{patient_name}} quedó para el {{date}}"},
+			"en": {"Appointment rescheduled", "The appointment for {{patient_name}} was moved to {{date}}"},
+		},
+	},
+	TemplateRescheduleDeclined: {
+		Type:      TypeAppointmentReminder,
+		Variables: []string{"patient_name", "date"},
+		Defaults: map[string]templateText{
+			"es": {"Reprogramación no aprobada", "No pudimos mover la cita de {{patient_name}}; se mantiene el {{date}}"},
+			"en": {"Reschedule not approved", "We could not move the appointment for {{patient_name}}; it stays on {{date}}"},
+		},
+	},
+	TemplateVaccinationRegistered: {
+		Type:      TypeVaccinationDue,
+		Variables: []string{"vaccine_name", "patient_name"},
+		Defaults: map[string]templateText{
+			"es": {"Vacunación Registrada", "Se ha registrado la vacunación {{vaccine_name}} para {{patient_name}}"},
+			"en": {"Vaccination recorded", "The {{vaccine_name}} vaccination was recorded for {{patient_name}}"},
+		},
+	},
+	TemplateVaccinationOverdue: {
+		Type:      TypeVaccinationDue,
+		Variables: []string{"vaccine_name", "days_overdue"},
+		Defaults: map[string]templateText{
+			"es": {"Vacuna Vencida", "La vacuna {{vaccine_name}} de tu mascota venció hace {{days_overdue}} días. ¡Programa una cita!"},
+			"en": {"Vaccine overdue", "Your pet's {{vaccine_name}} vaccine expired {{days_overdue}} days ago. Book an appointment!"},
+		},
+	},
+	TemplateMedicalRecordCreated: {
+		Type:      TypeMedicalRecordCreated,
+		Variables: []string{"patient_name"},
+		Defaults: map[string]templateText{
+			"es": {"Nuevo registro médico", "Se ha creado un nuevo registro médico para {{patient_name}}"},
+			"en": {"New medical record", "A new medical record was created for {{patient_name}}"},
+		},
+	},
+	TemplateNextVisitScheduled: {
+		Type:      TypeAppointmentReminder,
+		Variables: []string{"date"},
+		Defaults: map[string]templateText{
+			"es": {"Próxima visita programada", "Próxima visita programada para el {{date}}"},
+			"en": {"Next visit scheduled", "Your next visit is scheduled for {{date}}"},
+		},
+	},
+	TemplateLabResultReady: {
+		Type:      TypeLabResultReady,
+		Variables: []string{"test_type", "patient_name"},
+		Defaults: map[string]templateText{
+			"es": {"Resultados listos", "Los resultados de {{test_type}} de {{patient_name}} están listos"},
+			"en": {"Results ready", "The {{test_type}} results for {{patient_name}} are ready"},
+		},
+	},
+	TemplateAppointmentDelayed: {
+		Type:      TypeAppointmentReminder,
+		Variables: []string{"minutes", "date"},
+		Defaults: map[string]templateText{
+			"es": {"Tu cita viene con retraso", "La consulta anterior se está alargando: tu cita del {{date}} empezará unos {{minutes}} minutos tarde"},
+			"en": {"Your appointment is running late", "The previous visit is running long: your appointment on {{date}} will start about {{minutes}} minutes late"},
+		},
+	},
+	TemplateAgeReminder: {
+		Type:      TypeCareReminder,
+		Variables: []string{"patient_name", "label"},
+		Defaults: map[string]templateText{
+			"es": {"Cuidado recomendado por edad", "{{patient_name}} ya está en edad de {{label}}. ¡Programa una cita!"},
+			"en": {"Care due for age", "{{patient_name}} has reached the age for {{label}}. Book an appointment!"},
+		},
+	},
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// placeholders returns the distinct variable names referenced by text.
+func placeholders(text string) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, m := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		if _, ok := seen[m[1]]; !ok {
+			seen[m[1]] = struct{}{}
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// renderTemplate substitutes {{variable}} placeholders. Every variable the
+// message declares, plus any placeholder used by a custom wording, must be
+// present in vars.
+func renderTemplate(def templateDefinition, text templateText, vars map[string]string) (string, string, error) {
+	required := append([]string{}, def.Variables...)
+	required = append(required, placeholders(text.Title)...)
+	required = append(required, placeholders(text.Body)...)
+
+	var missing []string
+	checked := make(map[string]struct{}, len(required))
+	for _, name := range required {
+		if _, done := checked[name]; done {
+			continue
+		}
+		checked[name] = struct{}{}
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", "", ErrTemplateVariablesMissing(missing)
+	}
+
+	replace := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+			return vars[placeholderPattern.FindStringSubmatch(m)[1]]
+		})
+	}
+	return replace(text.Title), replace(text.Body), nil
+}
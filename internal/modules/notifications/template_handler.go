@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+type TemplateHandler struct {
+	service *TemplateService
+}
+
+func NewTemplateHandler(service *TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+// List returns the effective wording of every notification message for the tenant.
+//
+//	@Summary		List notification templates
+//	@Tags			admin/notifications
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Success		200			{array}		TemplateResponse
+//	@Failure		403			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/templates [get]
+func (h *TemplateHandler) List(c *gin.Context) (any, error) {
+	return h.service.List(c.Request.Context(), sharedMiddleware.GetTenantID(c))
+}
+
+// Create overrides the built-in wording of a message for one locale.
+//
+//	@Summary		Create notification template
+//	@Tags			admin/notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string				true	"Tenant ID"
+//	@Param			body		body		CreateTemplateDTO	true	"Template"
+//	@Success		200			{object}	TemplateResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/templates [post]
+func (h *TemplateHandler) Create(c *gin.Context) (any, error) {
+	var dto CreateTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+	return h.service.Create(c.Request.Context(), sharedMiddleware.GetTenantID(c), &dto)
+}
+
+// Update changes the wording of a tenant template.
+//
+//	@Summary		Update notification template
+//	@Tags			admin/notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string				true	"Tenant ID"
+//	@Param			id			path		string				true	"Template ID"
+//	@Param			body		body		UpdateTemplateDTO	true	"Template"
+//	@Success		200			{object}	TemplateResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/templates/{id} [put]
+func (h *TemplateHandler) Update(c *gin.Context) (any, error) {
+	var dto UpdateTemplateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+	return h.service.Update(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c), &dto)
+}
+
+// Delete removes a tenant template; the built-in default applies again.
+//
+//	@Summary		Delete notification template
+//	@Tags			admin/notifications
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			id			path		string	true	"Template ID"
+//	@Success		200			{object}	map[string]bool
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/notifications/templates/{id} [delete]
+func (h *TemplateHandler) Delete(c *gin.Context) (any, error) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c)); err != nil {
+		return nil, err
+	}
+	return gin.H{"deleted": true}, nil
+}
@@ -0,0 +1,219 @@
+package notifications
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TemplateService resolves notification wording: a tenant override when one
+// exists, otherwise the built-in default shipped in templateDefinitions.
+type TemplateService struct {
+	repo TemplateRepository
+}
+
+func NewTemplateService(repo TemplateRepository) *TemplateService {
+	return &TemplateService{repo: repo}
+}
+
+// RenderedTemplate is the final text of a notification.
+type RenderedTemplate struct {
+	Type   NotificationType
+	Locale string
+	Title  string
+	Body   string
+}
+
+// Render fills the template for key using the first locale in preferred that
+// has either a tenant override or a built-in text, then DefaultLocale.
+// Typical order: owner locale, tenant default locale.
+func (s *TemplateService) Render(ctx context.Context, tenantID primitive.ObjectID, key TemplateKey, preferred []string, data TemplateData) (*RenderedTemplate, error) {
+	def, ok := templateDefinitions[key]
+	if !ok {
+		return nil, ErrUnknownTemplateKey
+	}
+
+	locale, text, err := s.resolve(ctx, tenantID, key, def, preferred)
+	if err != nil {
+		return nil, err
+	}
+
+	title, body, err := renderTemplate(def, text, data.values(locale))
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedTemplate{Type: def.Type, Locale: locale, Title: title, Body: body}, nil
+}
+
+// DefaultLocaleFor returns the tenant's configured notification locale, if any.
+func (s *TemplateService) DefaultLocaleFor(ctx context.Context, tenantID primitive.ObjectID) string {
+	locale, err := s.repo.FindTenantDefaultLocale(ctx, tenantID)
+	if err != nil {
+		return ""
+	}
+	return locale
+}
+
+func (s *TemplateService) resolve(ctx context.Context, tenantID primitive.ObjectID, key TemplateKey, def templateDefinition, preferred []string) (string, templateText, error) {
+	tried := make(map[string]struct{}, len(preferred)+1)
+	for _, loc := range append(preferred, DefaultLocale) {
+		if _, done := tried[loc]; done || loc == "" {
+			continue
+		}
+		tried[loc] = struct{}{}
+
+		override, err := s.repo.FindOne(ctx, tenantID, key, loc)
+		if err != nil {
+			return "", templateText{}, err
+		}
+		if override != nil {
+			return loc, templateText{Title: override.Title, Body: override.Body}, nil
+		}
+		if text, ok := def.Defaults[loc]; ok {
+			return loc, text, nil
+		}
+	}
+	return DefaultLocale, def.Defaults[DefaultLocale], nil
+}
+
+// List returns the effective template for every message and locale: the
+// tenant's override where present, the built-in default otherwise.
+func (s *TemplateService) List(ctx context.Context, tenantID primitive.ObjectID) ([]TemplateResponse, error) {
+	overrides, err := s.repo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	type slot struct {
+		key    TemplateKey
+		locale string
+	}
+	custom := make(map[slot]NotificationTemplate, len(overrides))
+	for _, t := range overrides {
+		custom[slot{t.Key, t.Locale}] = t
+	}
+
+	var result []TemplateResponse
+	seen := make(map[slot]struct{})
+	add := func(key TemplateKey, locale string) {
+		sl := slot{key, locale}
+		if _, ok := seen[sl]; ok {
+			return
+		}
+		seen[sl] = struct{}{}
+
+		def := templateDefinitions[key]
+		if t, ok := custom[sl]; ok {
+			result = append(result, toTemplateResponse(&t, def))
+			return
+		}
+		text := def.Defaults[locale]
+		result = append(result, TemplateResponse{
+			Key:       key,
+			Type:      def.Type,
+			Locale:    locale,
+			Title:     text.Title,
+			Body:      text.Body,
+			Variables: def.Variables,
+		})
+	}
+
+	for key, def := range templateDefinitions {
+		for locale := range def.Defaults {
+			add(key, locale)
+		}
+	}
+	for _, t := range overrides {
+		if _, ok := templateDefinitions[t.Key]; ok {
+			add(t.Key, t.Locale)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Key != result[j].Key {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Locale < result[j].Locale
+	})
+	return result, nil
+}
+
+func (s *TemplateService) Create(ctx context.Context, tenantID primitive.ObjectID, dto *CreateTemplateDTO) (*TemplateResponse, error) {
+	def, ok := templateDefinitions[dto.Key]
+	if !ok {
+		return nil, ErrUnknownTemplateKey
+	}
+	if err := validateTemplateVariables(def, dto.Title, dto.Body); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	t := &NotificationTemplate{
+		ID:        primitive.NewObjectID(),
+		TenantID:  tenantID,
+		Key:       dto.Key,
+		Locale:    dto.Locale,
+		Title:     dto.Title,
+		Body:      dto.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	resp := toTemplateResponse(t, def)
+	return &resp, nil
+}
+
+func (s *TemplateService) Update(ctx context.Context, id string, tenantID primitive.ObjectID, dto *UpdateTemplateDTO) (*TemplateResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidTemplateID
+	}
+
+	t, err := s.repo.FindByID(ctx, oid, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	def := templateDefinitions[t.Key]
+	if err := validateTemplateVariables(def, dto.Title, dto.Body); err != nil {
+		return nil, err
+	}
+
+	t.Title = dto.Title
+	t.Body = dto.Body
+	t.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	resp := toTemplateResponse(t, def)
+	return &resp, nil
+}
+
+func (s *TemplateService) Delete(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidTemplateID
+	}
+	return s.repo.Delete(ctx, oid, tenantID)
+}
+
+// validateTemplateVariables rejects custom wording that references a variable
+// the message never supplies, which would otherwise fail on every send.
+func validateTemplateVariables(def templateDefinition, title, body string) error {
+	allowed := make(map[string]struct{}, len(def.Variables))
+	for _, v := range def.Variables {
+		allowed[v] = struct{}{}
+	}
+	for _, name := range placeholders(title + " " + body) {
+		if _, ok := allowed[name]; !ok {
+			return ErrTemplateUnknownVariable(name)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
 // --- Input DTOs ---
@@ -13,6 +15,7 @@ type UpdateOwnerDTO struct {
 	Phone     string `json:"phone"      example:"+57 300 123 4567"`
 	AvatarURL string `json:"avatar_url" example:"https://cdn.example.com/avatar.jpg"`
 	Address   string `json:"address"    example:"Calle 10 # 20-30, Medellín"`
+	Locale    string `json:"locale"     binding:"omitempty,oneof=es en" example:"en"`
 }
 
 type RegisterPushTokenDTO struct {
@@ -24,26 +27,74 @@ type RemovePushTokenDTO struct {
 	Token string `json:"token" binding:"required" example:"fcm-token-abc123"`
 }
 
+type UpdateNotificationPrefsDTO struct {
+	Muted      bool     `json:"muted"       example:"false"`
+	MutedTypes []string `json:"muted_types" example:"announcement"`
+	// QuietHours is left unchanged when omitted.
+	QuietHours *QuietHoursDTO `json:"quiet_hours,omitempty"`
+}
+
+// QuietHoursDTO sets the owner's own quiet hours, as HH:MM in the clinic's timezone.
+type QuietHoursDTO struct {
+	Enabled bool   `json:"enabled" example:"true"`
+	Start   string `json:"start"   binding:"omitempty,datetime=15:04" example:"22:00"`
+	End     string `json:"end"     binding:"omitempty,datetime=15:04" example:"07:00"`
+}
+
+// ConfirmContactDTO carries the code sent to the email or phone being verified.
+type ConfirmContactDTO struct {
+	Code string `json:"code" binding:"required,len=6,numeric" example:"482913"`
+}
+
+// MergeOwnersDTO names the owner record to keep and the duplicates folded into it.
+type MergeOwnersDTO struct {
+	SurvivorID   string   `json:"survivor_id"   binding:"required" example:"507f1f77bcf86cd799439011"`
+	DuplicateIDs []string `json:"duplicate_ids" binding:"required,min=1,max=20,dive,required" example:"507f1f77bcf86cd799439012"`
+}
+
+// SubmitIntakeDTO is the new client intake form an owner fills in for a clinic.
+type SubmitIntakeDTO struct {
+	ConsentTreatment      bool                `json:"consent_treatment"       example:"true"`
+	ConsentDataProcessing bool                `json:"consent_data_processing" example:"true"`
+	EmergencyContact      EmergencyContactDTO `json:"emergency_contact"       binding:"required"`
+	ReferralSource        string              `json:"referral_source"         binding:"required,oneof=friend_family social_media search_engine walk_in another_vet other" example:"friend_family"`
+	ReferralDetail        string              `json:"referral_detail"         binding:"omitempty,max=200" example:"Recomendado por María"`
+	Notes                 string              `json:"notes"                   binding:"omitempty,max=1000" example:"Prefiere ser contactado por WhatsApp"`
+}
+
+type EmergencyContactDTO struct {
+	Name         string `json:"name"         binding:"required,max=100" example:"Ana Pérez"`
+	Phone        string `json:"phone"        binding:"required,max=30"  example:"+57 300 765 4321"`
+	Relationship string `json:"relationship" binding:"omitempty,max=50" example:"Hermana"`
+}
+
 // --- Response DTOs ---
 
 type PushTokenResponse struct {
-	Token     string    `json:"token"`
-	Platform  string    `json:"platform"`
-	Active    bool      `json:"active"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Token      string    `json:"token"`
+	Platform   string    `json:"platform"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at,omitzero"`
+	LastSeenAt time.Time `json:"last_seen_at,omitzero"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 type OwnerResponse struct {
-	ID         string              `json:"id"`
-	Name       string              `json:"name"`
-	Email      string              `json:"email"`
-	Phone      string              `json:"phone"`
-	AvatarURL  string              `json:"avatar_url,omitempty"`
-	Address    string              `json:"address,omitempty"`
-	TenantIds  []string            `json:"tenant_ids"`
-	PushTokens []PushTokenResponse `json:"push_tokens"`
-	CreatedAt  time.Time           `json:"created_at"`
-	UpdatedAt  time.Time           `json:"updated_at"`
+	ID                string                  `json:"id"`
+	Name              string                  `json:"name"`
+	Email             string                  `json:"email"`
+	Phone             string                  `json:"phone"`
+	AvatarURL         string                  `json:"avatar_url,omitempty"`
+	Address           string                  `json:"address,omitempty"`
+	TenantIds         []string                `json:"tenant_ids"`
+	PushTokens        []PushTokenResponse     `json:"push_tokens"`
+	NotificationPrefs NotificationPreferences `json:"notification_prefs"`
+	Locale            string                  `json:"locale,omitempty"`
+	EmailVerified     bool                    `json:"email_verified"`
+	PhoneVerified     bool                    `json:"phone_verified"`
+	IntakeCompletedAt map[string]time.Time    `json:"intake_completed_at,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
 }
 
 func ToResponse(o *Owner) *OwnerResponse {
@@ -55,27 +106,55 @@ func ToResponse(o *Owner) *OwnerResponse {
 	pushTokens := make([]PushTokenResponse, len(o.PushTokens))
 	for i, pt := range o.PushTokens {
 		pushTokens[i] = PushTokenResponse{
-			Token:     pt.Token,
-			Platform:  pt.Platform,
-			Active:    pt.Active,
-			UpdatedAt: pt.UpdatedAt,
+			Token:      pt.Token,
+			Platform:   pt.Platform,
+			Active:     pt.Active,
+			CreatedAt:  pt.CreatedAt,
+			LastSeenAt: pt.LastSeenAt,
+			UpdatedAt:  pt.UpdatedAt,
 		}
 	}
 
 	return &OwnerResponse{
-		ID:         o.ID.Hex(),
-		Name:       o.Name,
-		Email:      o.Email,
-		Phone:      o.Phone,
-		AvatarURL:  o.AvatarURL,
-		Address:    o.Address,
-		TenantIds:  tenantIDs,
-		PushTokens: pushTokens,
-		CreatedAt:  o.CreatedAt,
-		UpdatedAt:  o.UpdatedAt,
+		ID:                o.ID.Hex(),
+		Name:              o.Name,
+		Email:             o.Email,
+		Phone:             o.Phone,
+		AvatarURL:         o.AvatarURL,
+		Address:           o.Address,
+		TenantIds:         tenantIDs,
+		PushTokens:        pushTokens,
+		NotificationPrefs: o.NotificationPrefs,
+		Locale:            o.Locale,
+		EmailVerified:     o.EmailVerified,
+		PhoneVerified:     o.PhoneVerified,
+		IntakeCompletedAt: o.IntakeCompletedAt,
+		CreatedAt:         o.CreatedAt,
+		UpdatedAt:         o.UpdatedAt,
 	}
 }
 
+// MergeOwnersResponse is the surviving owner after a merge. Reassigned counts
+// the documents moved to it per collection; a retried merge reports only what
+// was still left to move.
+type MergeOwnersResponse struct {
+	Owner        *OwnerResponse   `json:"owner"`
+	DuplicateIDs []string         `json:"duplicate_ids"`
+	Reassigned   map[string]int64 `json:"reassigned"`
+}
+
+// TenantSummary is a clinic an owner is associated with, as shown in the mobile app
+type TenantSummary struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	CommercialName string `json:"commercial_name"`
+	Logo           string `json:"logo,omitempty"`
+	// IntakeRequired is true when the clinic needs the intake form before a
+	// first appointment is confirmed; IntakeCompleted when the owner sent it
+	IntakeRequired  bool `json:"intake_required"`
+	IntakeCompleted bool `json:"intake_completed"`
+}
+
 // CreateOwnerDTO is used internally (registration flow uses mobile_auth)
 type CreateOwnerDTO struct {
 	Name     string
@@ -89,3 +168,41 @@ type ownerDoc struct {
 	ID        primitive.ObjectID `bson:"_id"`
 	TenantIds []primitive.ObjectID
 }
+
+// IntakeResponse is an owner's new client intake form for a clinic
+type IntakeResponse struct {
+	ID                    string              `json:"id"`
+	OwnerID               string              `json:"owner_id"`
+	ConsentTreatment      bool                `json:"consent_treatment"`
+	ConsentDataProcessing bool                `json:"consent_data_processing"`
+	EmergencyContact      EmergencyContactDTO `json:"emergency_contact"`
+	ReferralSource        string              `json:"referral_source"`
+	ReferralDetail        string              `json:"referral_detail,omitempty"`
+	Notes                 string              `json:"notes,omitempty"`
+	SubmittedAt           time.Time           `json:"submitted_at"`
+	UpdatedAt             time.Time           `json:"updated_at"`
+}
+
+type PaginatedIntakesResponse struct {
+	Data       []*IntakeResponse         `json:"data"`
+	Pagination pagination.PaginationInfo `json:"pagination"`
+}
+
+func toIntakeResponse(i *Intake) *IntakeResponse {
+	return &IntakeResponse{
+		ID:                    i.ID.Hex(),
+		OwnerID:               i.OwnerID.Hex(),
+		ConsentTreatment:      i.ConsentTreatment,
+		ConsentDataProcessing: i.ConsentDataProcessing,
+		EmergencyContact: EmergencyContactDTO{
+			Name:         i.EmergencyContact.Name,
+			Phone:        i.EmergencyContact.Phone,
+			Relationship: i.EmergencyContact.Relationship,
+		},
+		ReferralSource: i.ReferralSource,
+		ReferralDetail: i.ReferralDetail,
+		Notes:          i.Notes,
+		SubmittedAt:    i.SubmittedAt,
+		UpdatedAt:      i.UpdatedAt,
+	}
+}
@@ -6,4 +6,23 @@ var (
 	ErrOwnerNotFound  = errors.New("owner not found")
 	ErrEmailExists    = errors.New("email already exists")
 	ErrInvalidOwnerID = errors.New("invalid owner id")
+
+	ErrInvalidContactChannel    = errors.New("invalid contact channel, use email or phone")
+	ErrContactMissing           = errors.New("invalid contact: the owner has no address on file for this channel")
+	ErrContactChannelDisabled   = errors.New("invalid contact channel: delivery is not configured on this server")
+	ErrVerificationNotFound     = errors.New("verification code not found or expired")
+	ErrInvalidVerificationCode  = errors.New("invalid verification code")
+	ErrVerificationRateLimited  = errors.New("verification rate limit exceeded, try again later")
+	ErrVerificationAttemptsUsed = errors.New("verification rate limit exceeded: too many attempts, request a new code")
+	ErrContactChanged           = errors.New("invalid verification: the contact changed after the code was sent")
+
+	ErrInvalidQuietHours = errors.New("invalid quiet hours: start and end are required and must differ when enabled")
+
+	ErrMergeSurvivorListed = errors.New("invalid merge: the surviving owner is also listed as a duplicate")
+	ErrMergeOtherClinic    = errors.New("invalid merge: every owner must belong to this clinic")
+	ErrMergeSharedOwner    = errors.New("invalid merge: a duplicate is also registered with other clinics")
+	ErrMergedElsewhere     = errors.New("invalid merge: a duplicate was already merged into another owner")
+
+	ErrIntakeNotFound        = errors.New("intake not found")
+	ErrIntakeConsentRequired = errors.New("invalid intake: treatment and data processing consent are required")
 )
@@ -4,9 +4,11 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
 	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"github.com/eren_dev/go_server/internal/shared/validation"
 )
@@ -36,6 +38,23 @@ func (h *Handler) GetMe(c *gin.Context) (any, error) {
 	return h.service.GetMe(c.Request.Context(), ownerID)
 }
 
+// ListTenants returns the clinics the authenticated owner is associated with.
+//
+//	@Summary		List my clinics
+//	@Tags			mobile/owners
+//	@Produce		json
+//	@Success		200	{array}		TenantSummary
+//	@Failure		401	{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/mobile/tenants [get]
+func (h *Handler) ListTenants(c *gin.Context) (any, error) {
+	ownerID := sharedAuth.GetUserID(c)
+	if ownerID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+	return h.service.ListTenants(c.Request.Context(), ownerID)
+}
+
 // UpdateMe updates the authenticated owner's profile.
 //
 //	@Summary		Update my profile
@@ -121,6 +140,33 @@ func (h *Handler) RemovePushToken(c *gin.Context) (any, error) {
 	return gin.H{"message": "push token removed"}, nil
 }
 
+// UpdateNotificationPrefs replaces the owner's notification preferences (mute
+// flags, and quiet hours when sent).
+//
+//	@Summary		Update notification preferences
+//	@Tags			mobile/owners
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		UpdateNotificationPrefsDTO	true	"Notification preferences"
+//	@Success		200		{object}	OwnerResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/mobile/owners/me/notification-preferences [put]
+func (h *Handler) UpdateNotificationPrefs(c *gin.Context) (any, error) {
+	ownerID := sharedAuth.GetUserID(c)
+	if ownerID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	var dto UpdateNotificationPrefsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.UpdateNotificationPrefs(c.Request.Context(), ownerID, &dto)
+}
+
 // FindAll returns a paginated list of owners (admin panel use).
 //
 //	@Summary		List owners
@@ -167,3 +213,39 @@ func (h *Handler) Delete(c *gin.Context) (any, error) {
 	}
 	return gin.H{"message": "owner deleted"}, nil
 }
+
+type MergeHandler struct {
+	service *MergeService
+}
+
+func NewMergeHandler(service *MergeService) *MergeHandler {
+	return &MergeHandler{service: service}
+}
+
+// Merge folds duplicate owner records into a surviving one.
+//
+//	@Summary		Merge duplicate owners
+//	@Description	Moves the duplicates' patients, appointments, vaccinations and records in this clinic to the surviving owner, merges their push tokens, clinics and loyalty points, and soft-deletes them. Safe to retry.
+//	@Tags			owners
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string			true	"Tenant ID"
+//	@Param			body		body		MergeOwnersDTO	true	"Surviving owner and duplicates"
+//	@Success		200			{object}	MergeOwnersResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/owners/merge [post]
+func (h *MergeHandler) Merge(c *gin.Context) (any, error) {
+	var dto MergeOwnersDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(sharedAuth.GetUserID(c))
+	if err != nil {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	return h.service.MergeOwners(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c), userID)
+}
@@ -0,0 +1,55 @@
+package owners
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the owner collections
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	// Registering a token looks it up on every other owner to move the device
+	_, err := db.Collection("owners").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "push_tokens.token", Value: 1}},
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create owner push token indexes: %w", err)
+	}
+
+	verificationIndexes := []mongo.IndexModel{
+		// One pending code per owner and channel
+		{
+			Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "channel", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// Expired codes are removed by MongoDB
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	_, err = db.Collection("contact_verifications").Indexes().CreateMany(ctx, verificationIndexes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create contact verification indexes: %w", err)
+	}
+
+	// One intake form per owner and clinic
+	_, err = db.Collection("owner_intakes").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "owner_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create owner intake indexes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package owners
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// Intake is the new client form an owner fills in for a clinic. There is one
+// per owner and clinic; submitting again updates it.
+type Intake struct {
+	ID                    primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID              primitive.ObjectID `bson:"tenant_id"`
+	OwnerID               primitive.ObjectID `bson:"owner_id"`
+	ConsentTreatment      bool               `bson:"consent_treatment"`
+	ConsentDataProcessing bool               `bson:"consent_data_processing"`
+	EmergencyContact      EmergencyContact   `bson:"emergency_contact"`
+	ReferralSource        string             `bson:"referral_source"`
+	ReferralDetail        string             `bson:"referral_detail,omitempty"`
+	Notes                 string             `bson:"notes,omitempty"`
+	SubmittedAt           time.Time          `bson:"submitted_at"`
+	UpdatedAt             time.Time          `bson:"updated_at"`
+}
+
+// EmergencyContact is who the clinic calls when the owner cannot be reached
+type EmergencyContact struct {
+	Name         string `bson:"name"`
+	Phone        string `bson:"phone"`
+	Relationship string `bson:"relationship,omitempty"`
+}
+
+type IntakeRepository interface {
+	// Upsert stores the owner's intake for the clinic, keeping the first SubmittedAt
+	Upsert(ctx context.Context, intake *Intake) (*Intake, error)
+	FindByOwner(ctx context.Context, tenantID, ownerID primitive.ObjectID) (*Intake, error)
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID, ownerID *primitive.ObjectID, params pagination.Params) ([]Intake, int64, error)
+	// MarkCompleted records on the owner when the intake for the clinic was first submitted
+	MarkCompleted(ctx context.Context, ownerID, tenantID primitive.ObjectID, at time.Time) error
+}
+
+type intakeRepository struct {
+	collection *mongo.Collection
+	owners     *mongo.Collection
+}
+
+func NewIntakeRepository(db *database.MongoDB) IntakeRepository {
+	return &intakeRepository{collection: db.Collection("owner_intakes"), owners: db.Collection("owners")}
+}
+
+func (r *intakeRepository) Upsert(ctx context.Context, intake *Intake) (*Intake, error) {
+	filter := bson.M{"tenant_id": intake.TenantID, "owner_id": intake.OwnerID}
+	update := bson.M{
+		"$set": bson.M{
+			"consent_treatment":       intake.ConsentTreatment,
+			"consent_data_processing": intake.ConsentDataProcessing,
+			"emergency_contact":       intake.EmergencyContact,
+			"referral_source":         intake.ReferralSource,
+			"referral_detail":         intake.ReferralDetail,
+			"notes":                   intake.Notes,
+			"updated_at":              intake.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"submitted_at": intake.SubmittedAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var stored Intake
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *intakeRepository) FindByOwner(ctx context.Context, tenantID, ownerID primitive.ObjectID) (*Intake, error) {
+	var intake Intake
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "owner_id": ownerID}).Decode(&intake)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrIntakeNotFound
+		}
+		return nil, err
+	}
+	return &intake, nil
+}
+
+func (r *intakeRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID, ownerID *primitive.ObjectID, params pagination.Params) ([]Intake, int64, error) {
+	filter := bson.M{"tenant_id": tenantID}
+	if ownerID != nil {
+		filter["owner_id"] = *ownerID
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "submitted_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	intakes := []Intake{}
+	if err := cursor.All(ctx, &intakes); err != nil {
+		return nil, 0, err
+	}
+	return intakes, total, nil
+}
+
+func (r *intakeRepository) MarkCompleted(ctx context.Context, ownerID, tenantID primitive.ObjectID, at time.Time) error {
+	field := "intake_completed_at." + tenantID.Hex()
+	_, err := r.owners.UpdateOne(ctx,
+		bson.M{"_id": ownerID, field: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{field: at, "updated_at": at}},
+	)
+	return err
+}
+
+// IntakeService handles the new client intake forms of a clinic
+type IntakeService struct {
+	repo IntakeRepository
+}
+
+func NewIntakeService(repo IntakeRepository) *IntakeService {
+	return &IntakeService{repo: repo}
+}
+
+// Submit stores the owner's intake for the clinic and marks it completed on
+// the owner. Both consents are required.
+func (s *IntakeService) Submit(ctx context.Context, dto *SubmitIntakeDTO, tenantID primitive.ObjectID, ownerID string) (*IntakeResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, ErrInvalidOwnerID
+	}
+	if !dto.ConsentTreatment || !dto.ConsentDataProcessing {
+		return nil, ErrIntakeConsentRequired
+	}
+
+	now := time.Now()
+	intake, err := s.repo.Upsert(ctx, &Intake{
+		TenantID:              tenantID,
+		OwnerID:               oid,
+		ConsentTreatment:      dto.ConsentTreatment,
+		ConsentDataProcessing: dto.ConsentDataProcessing,
+		EmergencyContact: EmergencyContact{
+			Name:         dto.EmergencyContact.Name,
+			Phone:        dto.EmergencyContact.Phone,
+			Relationship: dto.EmergencyContact.Relationship,
+		},
+		ReferralSource: dto.ReferralSource,
+		ReferralDetail: dto.ReferralDetail,
+		Notes:          dto.Notes,
+		SubmittedAt:    now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.MarkCompleted(ctx, oid, tenantID, intake.SubmittedAt); err != nil {
+		return nil, err
+	}
+	return toIntakeResponse(intake), nil
+}
+
+// GetMine returns the owner's intake for the clinic
+func (s *IntakeService) GetMine(ctx context.Context, tenantID primitive.ObjectID, ownerID string) (*IntakeResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, ErrInvalidOwnerID
+	}
+	intake, err := s.repo.FindByOwner(ctx, tenantID, oid)
+	if err != nil {
+		return nil, err
+	}
+	return toIntakeResponse(intake), nil
+}
+
+// List returns the intakes submitted to the clinic, newest first, optionally
+// for a single owner
+func (s *IntakeService) List(ctx context.Context, tenantID primitive.ObjectID, ownerID string, params pagination.Params) (*PaginatedIntakesResponse, error) {
+	var filter *primitive.ObjectID
+	if ownerID != "" {
+		oid, err := primitive.ObjectIDFromHex(ownerID)
+		if err != nil {
+			return nil, ErrInvalidOwnerID
+		}
+		filter = &oid
+	}
+
+	intakes, total, err := s.repo.FindByTenant(ctx, tenantID, filter, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]*IntakeResponse, len(intakes))
+	for i := range intakes {
+		data[i] = toIntakeResponse(&intakes[i])
+	}
+	return &PaginatedIntakesResponse{
+		Data:       data,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package owners
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+type IntakeHandler struct {
+	service *IntakeService
+}
+
+func NewIntakeHandler(service *IntakeService) *IntakeHandler {
+	return &IntakeHandler{service: service}
+}
+
+// Submit stores the owner's new client intake form for the clinic.
+//
+//	@Summary		Submit intake form
+//	@Description	Submitting again updates the form. Clinics may require it before a first appointment is confirmed.
+//	@Tags			mobile/owners
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string			true	"Tenant ID"
+//	@Param			body		body		SubmitIntakeDTO	true	"Intake form"
+//	@Success		200			{object}	IntakeResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/mobile/intake [post]
+func (h *IntakeHandler) Submit(c *gin.Context) (any, error) {
+	ownerID := sharedAuth.GetUserID(c)
+	if ownerID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	var dto SubmitIntakeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Submit(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c), ownerID)
+}
+
+// GetMine returns the owner's intake form for the clinic.
+//
+//	@Summary		Get my intake form
+//	@Tags			mobile/owners
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Success		200			{object}	IntakeResponse
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/mobile/intake [get]
+func (h *IntakeHandler) GetMine(c *gin.Context) (any, error) {
+	ownerID := sharedAuth.GetUserID(c)
+	if ownerID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+	return h.service.GetMine(c.Request.Context(), sharedMiddleware.GetTenantID(c), ownerID)
+}
+
+// List returns the intake forms submitted to the clinic.
+//
+//	@Summary		List intake forms
+//	@Tags			owners
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			owner_id	query		string	false	"Only this owner's form"
+//	@Param			skip		query		int		false	"Skip"
+//	@Param			limit		query		int		false	"Limit"
+//	@Success		200			{object}	PaginatedIntakesResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/owners/intakes [get]
+func (h *IntakeHandler) List(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+	return h.service.List(c.Request.Context(), sharedMiddleware.GetTenantID(c), c.Query("owner_id"), params)
+}
@@ -0,0 +1,293 @@
+package owners
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// ownedCollections are the tenant-scoped collections whose documents point
+// at an owner through owner_id, and so move to the survivor of a merge
+var ownedCollections = []string{
+	"patients",
+	"appointments",
+	"vaccinations",
+	"medical_records",
+	"lab_orders",
+	"invoices",
+	"loyalty_transactions",
+	"consents",
+	"notifications",
+}
+
+// MergeRepository holds the writes of an owner merge. Each step can run again
+// without effect, so a merge that failed halfway is finished by retrying it.
+type MergeRepository interface {
+	// FindOwners returns the owners with the given IDs, soft-deleted ones included
+	FindOwners(ctx context.Context, ids []primitive.ObjectID) ([]Owner, error)
+	// ReassignOwner moves the tenant's documents from one owner to another,
+	// returning how many moved per collection
+	ReassignOwner(ctx context.Context, tenantID, from, to primitive.ObjectID) (map[string]int64, error)
+	// AbsorbDuplicate adds the duplicate's push tokens, clinics, loyalty
+	// balance and intake in tenantID to the survivor, once per duplicate
+	AbsorbDuplicate(ctx context.Context, survivorID primitive.ObjectID, duplicate *Owner, tokens []PushToken, tenantID primitive.ObjectID) error
+	// MarkMerged soft-deletes the duplicate and records the owner it went into
+	MarkMerged(ctx context.Context, duplicateID, survivorID primitive.ObjectID, at time.Time) error
+}
+
+type mergeRepository struct {
+	db     *database.MongoDB
+	owners *mongo.Collection
+}
+
+func NewMergeRepository(db *database.MongoDB) MergeRepository {
+	return &mergeRepository{db: db, owners: db.Collection("owners")}
+}
+
+func (r *mergeRepository) FindOwners(ctx context.Context, ids []primitive.ObjectID) ([]Owner, error) {
+	cursor, err := r.owners.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var owners []Owner
+	if err := cursor.All(ctx, &owners); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+func (r *mergeRepository) ReassignOwner(ctx context.Context, tenantID, from, to primitive.ObjectID) (map[string]int64, error) {
+	moved := make(map[string]int64, len(ownedCollections))
+	for _, name := range ownedCollections {
+		result, err := r.db.Collection(name).UpdateMany(ctx,
+			bson.M{"tenant_id": tenantID, "owner_id": from},
+			bson.M{"$set": bson.M{"owner_id": to}},
+		)
+		if err != nil {
+			return moved, fmt.Errorf("reassign %s: %w", name, err)
+		}
+		moved[name] = result.ModifiedCount
+	}
+	return moved, nil
+}
+
+func (r *mergeRepository) AbsorbDuplicate(ctx context.Context, survivorID primitive.ObjectID, duplicate *Owner, tokens []PushToken, tenantID primitive.ObjectID) error {
+	update := bson.M{
+		"$addToSet": bson.M{
+			"merged_owner_ids": duplicate.ID,
+			"tenant_ids":       bson.M{"$each": duplicate.TenantIds},
+			"push_tokens":      bson.M{"$each": tokens},
+		},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	if points := duplicate.LoyaltyPoints[tenantID.Hex()]; points != 0 {
+		update["$inc"] = bson.M{"loyalty_points." + tenantID.Hex(): points}
+	}
+	// The survivor keeps the earliest completion of the two
+	if at := duplicate.IntakeCompletedAt[tenantID.Hex()]; !at.IsZero() {
+		update["$min"] = bson.M{"intake_completed_at." + tenantID.Hex(): at}
+	}
+
+	// The merged_owner_ids guard makes a repeated call match nothing
+	_, err := r.owners.UpdateOne(ctx,
+		bson.M{"_id": survivorID, "merged_owner_ids": bson.M{"$ne": duplicate.ID}},
+		update,
+	)
+	if err != nil {
+		return err
+	}
+
+	// There is one intake per owner and clinic, so the duplicate's only moves
+	// when the survivor has none; otherwise the unique index refuses it and
+	// the survivor's own intake is kept.
+	_, err = r.db.Collection("owner_intakes").UpdateOne(ctx,
+		bson.M{"tenant_id": tenantID, "owner_id": duplicate.ID},
+		bson.M{"$set": bson.M{"owner_id": survivorID}},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *mergeRepository) MarkMerged(ctx context.Context, duplicateID, survivorID primitive.ObjectID, at time.Time) error {
+	_, err := r.owners.UpdateOne(ctx,
+		bson.M{"_id": duplicateID, "deleted_at": nil},
+		bson.M{
+			"$set": bson.M{
+				"merged_into": survivorID,
+				"push_tokens": []PushToken{},
+				"deleted_at":  at,
+				"updated_at":  at,
+			},
+			"$unset": bson.M{"loyalty_points": ""},
+		},
+	)
+	return err
+}
+
+// AuditLogger records the merge in the clinic's audit trail
+type AuditLogger interface {
+	Log(ctx context.Context, tenantID, userID primitive.ObjectID, eventType audit.EventType, resource, action, description string, opts *audit.LogOptions) error
+}
+
+// MergeService folds owner records created twice by mistake into one
+type MergeService struct {
+	repo     OwnerRepository
+	merges   MergeRepository
+	auditLog AuditLogger
+}
+
+func NewMergeService(repo OwnerRepository, merges MergeRepository, auditLog AuditLogger) *MergeService {
+	return &MergeService{repo: repo, merges: merges, auditLog: auditLog}
+}
+
+// MergeOwners moves the duplicates' patients, appointments and history in
+// this clinic to the survivor, hands it their push tokens, clinics and
+// loyalty points, and soft-deletes them. Owners are shared across clinics, so
+// every owner must belong to this one and a duplicate registered with another
+// clinic is refused: its records there are out of this clinic's reach.
+// Duplicates already merged into the survivor are finished off again, which
+// makes a failed merge safe to retry.
+func (s *MergeService) MergeOwners(ctx context.Context, dto *MergeOwnersDTO, tenantID, mergedBy primitive.ObjectID) (*MergeOwnersResponse, error) {
+	survivorID, err := primitive.ObjectIDFromHex(dto.SurvivorID)
+	if err != nil {
+		return nil, ErrInvalidOwnerID
+	}
+	ids := []primitive.ObjectID{survivorID}
+	var duplicateIDs []primitive.ObjectID
+	seen := map[primitive.ObjectID]bool{}
+	for _, raw := range dto.DuplicateIDs {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			return nil, ErrInvalidOwnerID
+		}
+		if id == survivorID {
+			return nil, ErrMergeSurvivorListed
+		}
+		if !seen[id] {
+			seen[id] = true
+			duplicateIDs = append(duplicateIDs, id)
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := s.merges.FindOwners(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*Owner, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+
+	survivor := byID[survivorID]
+	if survivor == nil || survivor.DeletedAt != nil {
+		return nil, ErrOwnerNotFound
+	}
+	if !survivor.belongsTo(tenantID) {
+		return nil, ErrMergeOtherClinic
+	}
+	for _, id := range duplicateIDs {
+		dup := byID[id]
+		switch {
+		case dup == nil:
+			return nil, ErrOwnerNotFound
+		case dup.DeletedAt != nil && (dup.MergedInto == nil || *dup.MergedInto != survivorID):
+			if dup.MergedInto != nil {
+				return nil, ErrMergedElsewhere
+			}
+			return nil, ErrOwnerNotFound
+		case !dup.belongsTo(tenantID):
+			return nil, ErrMergeOtherClinic
+		case len(dup.TenantIds) > 1:
+			return nil, ErrMergeSharedOwner
+		}
+	}
+
+	reassigned := make(map[string]int64, len(ownedCollections))
+	tokens := make(map[string]bool, len(survivor.PushTokens))
+	for _, t := range survivor.PushTokens {
+		tokens[t.Token] = true
+	}
+	now := time.Now()
+	for _, id := range duplicateIDs {
+		dup := byID[id]
+
+		moved, err := s.merges.ReassignOwner(ctx, tenantID, id, survivorID)
+		for name, n := range moved {
+			reassigned[name] += n
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var newTokens []PushToken
+		for _, t := range dup.PushTokens {
+			if !tokens[t.Token] {
+				tokens[t.Token] = true
+				newTokens = append(newTokens, t)
+			}
+		}
+		if err := s.merges.AbsorbDuplicate(ctx, survivorID, dup, nonNilTokens(newTokens), tenantID); err != nil {
+			return nil, err
+		}
+		if err := s.merges.MarkMerged(ctx, id, survivorID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	hexIDs := make([]string, len(duplicateIDs))
+	for i, id := range duplicateIDs {
+		hexIDs[i] = id.Hex()
+	}
+	description := fmt.Sprintf("Merged owners %s into %s", strings.Join(hexIDs, ", "), survivorID.Hex())
+	if err := s.auditLog.Log(ctx, tenantID, mergedBy, audit.EventOwnerMerged, "owner", "merge", description, &audit.LogOptions{
+		ResourceID: survivorID,
+		Metadata: map[string]interface{}{
+			"duplicate_ids": hexIDs,
+			"reassigned":    reassigned,
+		},
+	}); err != nil {
+		slog.Error("failed to audit owner merge", "tenant_id", tenantID.Hex(), "survivor_id", survivorID.Hex(), "error", err)
+	}
+
+	merged, err := s.repo.FindByID(ctx, survivorID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return &MergeOwnersResponse{
+		Owner:        ToResponse(merged),
+		DuplicateIDs: hexIDs,
+		Reassigned:   reassigned,
+	}, nil
+}
+
+// belongsTo reports whether the owner is registered with the clinic
+func (o *Owner) belongsTo(tenantID primitive.ObjectID) bool {
+	for _, id := range o.TenantIds {
+		if id == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNilTokens keeps $each from receiving a null array
+func nonNilTokens(tokens []PushToken) []PushToken {
+	if tokens == nil {
+		return []PushToken{}
+	}
+	return tokens
+}
@@ -0,0 +1,79 @@
+package owners
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/audit"
+)
+
+// mockMergeRepo serves owners from memory and reports one document moved in
+// every owned collection.
+type mockMergeRepo struct {
+	owners     []Owner
+	reassigned []string
+}
+
+func (m *mockMergeRepo) FindOwners(ctx context.Context, ids []primitive.ObjectID) ([]Owner, error) {
+	return m.owners, nil
+}
+
+func (m *mockMergeRepo) ReassignOwner(ctx context.Context, tenantID, from, to primitive.ObjectID) (map[string]int64, error) {
+	moved := make(map[string]int64, len(ownedCollections))
+	for _, name := range ownedCollections {
+		m.reassigned = append(m.reassigned, name)
+		moved[name] = 1
+	}
+	return moved, nil
+}
+
+func (m *mockMergeRepo) AbsorbDuplicate(ctx context.Context, survivorID primitive.ObjectID, duplicate *Owner, tokens []PushToken, tenantID primitive.ObjectID) error {
+	return nil
+}
+
+func (m *mockMergeRepo) MarkMerged(ctx context.Context, duplicateID, survivorID primitive.ObjectID, at time.Time) error {
+	return nil
+}
+
+type noopAuditLogger struct{}
+
+func (noopAuditLogger) Log(ctx context.Context, tenantID, userID primitive.ObjectID, eventType audit.EventType, resource, action, description string, opts *audit.LogOptions) error {
+	return nil
+}
+
+// Every tenant-scoped record pointing at the duplicate must move to the
+// survivor, consents included.
+func TestMergeOwners_ReassignsOwnedCollections(t *testing.T) {
+	tenantID := primitive.NewObjectID()
+	survivor := Owner{ID: primitive.NewObjectID(), TenantIds: []primitive.ObjectID{tenantID}}
+	duplicate := Owner{ID: primitive.NewObjectID(), TenantIds: []primitive.ObjectID{tenantID}}
+	merges := &mockMergeRepo{owners: []Owner{survivor, duplicate}}
+	repo := &mockOwnerRepo{owners: map[string]*Owner{survivor.ID.Hex(): &survivor}}
+	service := NewMergeService(repo, merges, noopAuditLogger{})
+
+	resp, err := service.MergeOwners(context.Background(), &MergeOwnersDTO{
+		SurvivorID:   survivor.ID.Hex(),
+		DuplicateIDs: []string{duplicate.ID.Hex()},
+	}, tenantID, primitive.NewObjectID())
+
+	assert.NoError(t, err)
+	expected := []string{
+		"patients",
+		"appointments",
+		"vaccinations",
+		"medical_records",
+		"lab_orders",
+		"invoices",
+		"loyalty_transactions",
+		"consents",
+		"notifications",
+	}
+	assert.Equal(t, expected, merges.reassigned)
+	for _, name := range expected {
+		assert.Equal(t, int64(1), resp.Reassigned[name], "%s was not reassigned", name)
+	}
+}
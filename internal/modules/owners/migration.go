@@ -0,0 +1,49 @@
+package owners
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// BackfillTenantIDs links every owner to the tenants where they have an
+// active patient. Owners created before tenant_ids was maintained have an
+// empty list, which locks them out of tenant-scoped mobile routes and
+// broadcasts. $addToSet makes it safe to run more than once. It returns how
+// many owners gained at least one tenant.
+func BackfillTenantIDs(ctx context.Context, db *database.MongoDB) (int64, error) {
+	cursor, err := db.Collection("patients").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": nil, "owner_id": bson.M{"$ne": nil}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$owner_id", "tenant_ids": bson.M{"$addToSet": "$tenant_id"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	owners := db.Collection("owners")
+	var updated int64
+	for cursor.Next(ctx) {
+		var row struct {
+			OwnerID   primitive.ObjectID   `bson:"_id"`
+			TenantIDs []primitive.ObjectID `bson:"tenant_ids"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return updated, err
+		}
+
+		res, err := owners.UpdateOne(ctx,
+			bson.M{"_id": row.OwnerID},
+			bson.M{"$addToSet": bson.M{"tenant_ids": bson.M{"$each": row.TenantIDs}}},
+		)
+		if err != nil {
+			return updated, err
+		}
+		updated += res.ModifiedCount
+	}
+	return updated, cursor.Err()
+}
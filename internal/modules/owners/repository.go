@@ -22,16 +22,29 @@ type OwnerRepository interface {
 	Delete(ctx context.Context, id string) error
 	AddPushToken(ctx context.Context, id string, token PushToken) error
 	RemovePushToken(ctx context.Context, id string, token string) error
+	// PrunePushTokens removes tokens whose device was last seen before cutoff
+	// and returns how many owners lost at least one token.
+	PrunePushTokens(ctx context.Context, cutoff time.Time) (int64, error)
 	AddTenantID(ctx context.Context, id string, tenantID primitive.ObjectID) error
+	// PatientTenantIDs returns the tenants where the owner has an active
+	// patient, which links owners whose tenant_ids were never filled in.
+	PatientTenantIDs(ctx context.Context, id string) ([]primitive.ObjectID, error)
+	UpdateNotificationPrefs(ctx context.Context, id string, prefs NotificationPreferences) error
+	FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]*Owner, error)
+	// MarkContactVerified flags the channel as verified only while the owner's
+	// contact still equals target, so a code sent to an old address is useless.
+	MarkContactVerified(ctx context.Context, id string, channel, target string) error
 }
 
 type ownerRepository struct {
 	collection *mongo.Collection
+	patients   *mongo.Collection
 }
 
 func NewRepository(db *database.MongoDB) OwnerRepository {
 	return &ownerRepository{
 		collection: db.Collection("owners"),
+		patients:   db.Collection("patients"),
 	}
 }
 
@@ -130,6 +143,13 @@ func (r *ownerRepository) Update(ctx context.Context, id string, dto *UpdateOwne
 	}
 	if dto.Phone != "" {
 		set["phone"] = dto.Phone
+		// A new number has to be verified again
+		if _, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": objectID, "phone": bson.M{"$ne": dto.Phone}},
+			bson.M{"$set": bson.M{"phone_verified": false}},
+		); err != nil {
+			return nil, err
+		}
 	}
 	if dto.AvatarURL != "" {
 		set["avatar_url"] = dto.AvatarURL
@@ -137,6 +157,9 @@ func (r *ownerRepository) Update(ctx context.Context, id string, dto *UpdateOwne
 	if dto.Address != "" {
 		set["address"] = dto.Address
 	}
+	if dto.Locale != "" {
+		set["locale"] = dto.Locale
+	}
 
 	result, err := r.collection.UpdateOne(
 		ctx,
@@ -180,18 +203,39 @@ func (r *ownerRepository) AddPushToken(ctx context.Context, id string, token Pus
 		return ErrInvalidOwnerID
 	}
 
-	// Remove existing entry for the same token string, then add the new one
-	_, err = r.collection.UpdateOne(
+	// A device belongs to whoever signed in on it last, so the token leaves
+	// any other owner it was registered to
+	_, err = r.collection.UpdateMany(
 		ctx,
-		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{"_id": bson.M{"$ne": objectID}, "push_tokens.token": token.Token},
 		bson.D{
 			{Key: "$pull", Value: bson.M{"push_tokens": bson.M{"token": token.Token}}},
+			{Key: "$set", Value: bson.M{"updated_at": time.Now()}},
 		},
 	)
 	if err != nil {
 		return err
 	}
 
+	// Registering a known token again only refreshes it, keeping created_at
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": nil, "push_tokens.token": token.Token},
+		bson.M{"$set": bson.M{
+			"push_tokens.$.platform":     token.Platform,
+			"push_tokens.$.active":       token.Active,
+			"push_tokens.$.last_seen_at": token.LastSeenAt,
+			"push_tokens.$.updated_at":   token.UpdatedAt,
+			"updated_at":                 time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
 	_, err = r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": objectID, "deleted_at": nil},
@@ -220,6 +264,26 @@ func (r *ownerRepository) RemovePushToken(ctx context.Context, id string, token
 	return err
 }
 
+func (r *ownerRepository) PrunePushTokens(ctx context.Context, cutoff time.Time) (int64, error) {
+	stale := bson.M{"$or": bson.A{
+		bson.M{"last_seen_at": bson.M{"$lt": cutoff}},
+		bson.M{"last_seen_at": bson.M{"$exists": false}, "updated_at": bson.M{"$lt": cutoff}},
+	}}
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"push_tokens": bson.M{"$elemMatch": stale}},
+		bson.D{
+			{Key: "$pull", Value: bson.M{"push_tokens": stale}},
+			{Key: "$set", Value: bson.M{"updated_at": time.Now()}},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
 func (r *ownerRepository) AddTenantID(ctx context.Context, id string, tenantID primitive.ObjectID) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -236,3 +300,92 @@ func (r *ownerRepository) AddTenantID(ctx context.Context, id string, tenantID p
 	)
 	return err
 }
+
+func (r *ownerRepository) PatientTenantIDs(ctx context.Context, id string) ([]primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidOwnerID
+	}
+
+	values, err := r.patients.Distinct(ctx, "tenant_id", bson.M{"owner_id": objectID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if tenantID, ok := v.(primitive.ObjectID); ok {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	return tenantIDs, nil
+}
+
+func (r *ownerRepository) UpdateNotificationPrefs(ctx context.Context, id string, prefs NotificationPreferences) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidOwnerID
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"notification_prefs": prefs, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrOwnerNotFound
+	}
+	return nil
+}
+
+// FindByTenant returns every active owner linked to the given tenant, either
+// through tenant_ids or through an active patient in it.
+func (r *ownerRepository) FindByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]*Owner, error) {
+	values, err := r.patients.Distinct(ctx, "owner_id", bson.M{"tenant_id": tenantID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"$or":        bson.A{bson.M{"tenant_ids": tenantID}, bson.M{"_id": bson.M{"$in": values}}},
+		"deleted_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var owners []*Owner
+	if err := cursor.All(ctx, &owners); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+func (r *ownerRepository) MarkContactVerified(ctx context.Context, id string, channel, target string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidOwnerID
+	}
+
+	field, flag := "email", "email_verified"
+	if channel == ContactChannelPhone {
+		field, flag = "phone", "phone_verified"
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, field: target, "deleted_at": nil},
+		bson.M{"$set": bson.M{flag: true, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrContactChanged
+	}
+	return nil
+}
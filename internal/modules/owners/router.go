@@ -1,27 +1,38 @@
 package owners
 
 import (
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/platform/email/smtp"
+	"github.com/eren_dev/go_server/internal/platform/sms/gateway"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
 )
 
 // RegisterMobileRoutes registers mobile (owner-facing) routes under /mobile/owners
-func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
+func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB, cfg *config.Config) {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, tenant.NewTenantRepository(db))
 	handler := NewHandler(service)
+	verification := NewVerificationHandler(NewVerificationService(repo, NewVerificationRepository(db), smtp.NewSender(cfg), gateway.NewSender(cfg), cfg))
 
 	me := mobile.Group("/owners/me")
 	me.GET("", handler.GetMe)
 	me.PATCH("", handler.UpdateMe)
 	me.POST("/push-tokens", handler.AddPushToken)
 	me.DELETE("/push-tokens/:token", handler.RemovePushToken)
+	me.PUT("/notification-preferences", handler.UpdateNotificationPrefs)
+	me.POST("/verify/:channel", verification.SendCode)
+	me.POST("/verify/:channel/confirm", verification.Confirm)
+
+	mobile.GET("/tenants", handler.ListTenants)
 }
 
 // RegisterAdminRoutes registers admin-panel routes under /api/owners (JWT + RBAC)
 func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, tenant.NewTenantRepository(db))
 	handler := NewHandler(service)
 
 	owners := private.Group("/owners")
@@ -29,3 +40,28 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	owners.GET("/:id", handler.FindByID)
 	owners.DELETE("/:id", handler.Delete)
 }
+
+// RegisterMergeRoutes registers the owner merge under /api/owners, scoped to
+// the clinic in X-Tenant-ID (JWT + Tenant + RBAC)
+func RegisterMergeRoutes(privateTenant *httpx.Router, db *database.MongoDB, auditService *audit.Service) {
+	handler := NewMergeHandler(NewMergeService(NewRepository(db), NewMergeRepository(db), auditService))
+
+	privateTenant.POST("/owners/merge", handler.Merge)
+}
+
+// RegisterIntakeMobileRoutes registers the new client intake form under
+// /mobile/intake, scoped to the clinic in X-Tenant-ID
+func RegisterIntakeMobileRoutes(mobileTenant *httpx.Router, db *database.MongoDB) {
+	handler := NewIntakeHandler(NewIntakeService(NewIntakeRepository(db)))
+
+	mobileTenant.POST("/intake", handler.Submit)
+	mobileTenant.GET("/intake", handler.GetMine)
+}
+
+// RegisterIntakeRoutes registers the clinic's intake forms under
+// /api/owners/intakes (JWT + Tenant + RBAC)
+func RegisterIntakeRoutes(privateTenant *httpx.Router, db *database.MongoDB) {
+	handler := NewIntakeHandler(NewIntakeService(NewIntakeRepository(db)))
+
+	privateTenant.GET("/owners/intakes", handler.List)
+}
@@ -6,13 +6,55 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// PushToken is a device registered for push. The app registers its token on
+// every launch, so LastSeenAt tells whether the device is still in use; tokens
+// registered before it existed fall back to UpdatedAt.
 type PushToken struct {
-	Token     string    `bson:"token"      json:"token"`
-	Platform  string    `bson:"platform"   json:"platform"` // "ios" | "android"
-	Active    bool      `bson:"active"     json:"active"`
-	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	Token      string    `bson:"token"                  json:"token"`
+	Platform   string    `bson:"platform"               json:"platform"` // "ios" | "android"
+	Active     bool      `bson:"active"                 json:"active"`
+	CreatedAt  time.Time `bson:"created_at,omitempty"   json:"created_at"`
+	LastSeenAt time.Time `bson:"last_seen_at,omitempty" json:"last_seen_at"`
+	UpdatedAt  time.Time `bson:"updated_at"             json:"updated_at"`
 }
 
+// NotificationPreferences lets an owner opt out of notifications.
+// Muted silences everything; MutedTypes silences individual notification types.
+type NotificationPreferences struct {
+	Muted      bool     `bson:"muted"                 json:"muted"`
+	MutedTypes []string `bson:"muted_types,omitempty" json:"muted_types,omitempty"`
+	// QuietHours overrides the clinic's quiet hours when enabled.
+	QuietHours QuietHours `bson:"quiet_hours" json:"quiet_hours"`
+}
+
+// QuietHours is a daily window, in the clinic's timezone, during which push,
+// email and SMS delivery is held back until the window ends. Start after End
+// spans midnight ("22:00" to "07:00").
+type QuietHours struct {
+	Enabled bool   `bson:"enabled"         json:"enabled"`
+	Start   string `bson:"start,omitempty" json:"start,omitempty"`
+	End     string `bson:"end,omitempty"   json:"end,omitempty"`
+}
+
+// Allows reports whether a notification of the given type may be delivered.
+func (p NotificationPreferences) Allows(notifType string) bool {
+	if p.Muted {
+		return false
+	}
+	for _, t := range p.MutedTypes {
+		if t == notifType {
+			return false
+		}
+	}
+	return true
+}
+
+// Contact channels an owner can verify and be reached on besides push.
+const (
+	ContactChannelEmail = "email"
+	ContactChannelPhone = "phone"
+)
+
 type Owner struct {
 	ID         primitive.ObjectID   `bson:"_id,omitempty"`
 	Name       string               `bson:"name"`
@@ -23,7 +65,59 @@ type Owner struct {
 	Address    string               `bson:"address,omitempty"`
 	PushTokens []PushToken          `bson:"push_tokens"`
 	TenantIds  []primitive.ObjectID `bson:"tenant_ids"`
-	CreatedAt  time.Time            `bson:"created_at"`
-	UpdatedAt  time.Time            `bson:"updated_at"`
-	DeletedAt  *time.Time           `bson:"deleted_at,omitempty"`
+	// NotificationPrefs defaults to the zero value (nothing muted) for existing documents.
+	NotificationPrefs NotificationPreferences `bson:"notification_prefs"`
+	// Locale selects the language of notifications; empty means the clinic's default.
+	Locale string `bson:"locale,omitempty"`
+	// EmailVerified and PhoneVerified are set by confirming a code sent to the
+	// contact; changing the phone clears PhoneVerified.
+	EmailVerified bool `bson:"email_verified"`
+	PhoneVerified bool `bson:"phone_verified"`
+	// IntakeCompletedAt is when the owner first submitted the new client
+	// intake form, per clinic, keyed by tenant ID hex.
+	IntakeCompletedAt map[string]time.Time `bson:"intake_completed_at,omitempty"`
+	// LoyaltyPoints is the points balance per clinic, keyed by tenant ID hex.
+	// It is only changed through the loyalty module, which keeps the ledger.
+	LoyaltyPoints map[string]int `bson:"loyalty_points,omitempty"`
+	CreatedAt     time.Time      `bson:"created_at"`
+	UpdatedAt     time.Time      `bson:"updated_at"`
+	DeletedAt     *time.Time     `bson:"deleted_at,omitempty"`
+
+	// MergedInto is set on a duplicate soft-deleted by a merge, and
+	// MergedOwnerIDs on the owner it was merged into; together they let a
+	// retried merge skip work that already happened.
+	MergedInto     *primitive.ObjectID  `bson:"merged_into,omitempty"`
+	MergedOwnerIDs []primitive.ObjectID `bson:"merged_owner_ids,omitempty"`
+}
+
+// Contact returns the owner's address for a contact channel.
+func (o *Owner) Contact(channel string) string {
+	switch channel {
+	case ContactChannelEmail:
+		return o.Email
+	case ContactChannelPhone:
+		return o.Phone
+	}
+	return ""
+}
+
+// ContactVerified reports whether the owner confirmed the given contact channel.
+func (o *Owner) ContactVerified(channel string) bool {
+	switch channel {
+	case ContactChannelEmail:
+		return o.EmailVerified
+	case ContactChannelPhone:
+		return o.PhoneVerified
+	}
+	return false
+}
+
+// BelongsToTenant reports whether the owner is associated with the given clinic.
+func (o *Owner) BelongsToTenant(tenantID primitive.ObjectID) bool {
+	for _, id := range o.TenantIds {
+		if id == tenantID {
+			return true
+		}
+	}
+	return false
 }
@@ -2,8 +2,12 @@ package owners
 
 import (
 	"context"
+	"slices"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
@@ -13,11 +17,12 @@ type PaginatedOwnersResponse struct {
 }
 
 type Service struct {
-	repo OwnerRepository
+	repo       OwnerRepository
+	tenantRepo tenant.TenantRepository
 }
 
-func NewService(repo OwnerRepository) *Service {
-	return &Service{repo: repo}
+func NewService(repo OwnerRepository, tenantRepo tenant.TenantRepository) *Service {
+	return &Service{repo: repo, tenantRepo: tenantRepo}
 }
 
 func (s *Service) GetMe(ctx context.Context, ownerID string) (*OwnerResponse, error) {
@@ -36,12 +41,88 @@ func (s *Service) UpdateMe(ctx context.Context, ownerID string, dto *UpdateOwner
 	return ToResponse(owner), nil
 }
 
+// ListTenants returns the clinics the owner is associated with. The mobile app
+// sends the chosen one back as X-Tenant-ID on tenant-scoped requests.
+func (s *Service) ListTenants(ctx context.Context, ownerID string) ([]TenantSummary, error) {
+	owner, err := s.repo.FindByID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIDs, err := s.tenantIDs(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := s.tenantRepo.FindByIDs(ctx, tenantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]TenantSummary, len(tenants))
+	for i, t := range tenants {
+		summaries[i] = TenantSummary{
+			ID:             t.ID.Hex(),
+			Name:           t.Name,
+			CommercialName: t.CommercialName,
+			Logo:           t.Logo,
+
+			IntakeRequired:  t.Settings.RequireIntakeForFirstAppointment,
+			IntakeCompleted: !owner.IntakeCompletedAt[t.ID.Hex()].IsZero(),
+		}
+	}
+	return summaries, nil
+}
+
+// BelongsToTenant reports whether the owner is associated with the given clinic.
+func (s *Service) BelongsToTenant(ctx context.Context, ownerID string, tenantID primitive.ObjectID) (bool, error) {
+	owner, err := s.repo.FindByID(ctx, ownerID)
+	if err != nil {
+		return false, err
+	}
+	if owner.BelongsToTenant(tenantID) {
+		return true, nil
+	}
+
+	tenantIDs, err := s.tenantIDs(ctx, owner)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(tenantIDs, tenantID), nil
+}
+
+// tenantIDs returns the owner's tenants, adding those where the owner has an
+// active patient. Owners created before tenant_ids was maintained only link to
+// their clinics through their patients; the missing links are stored so the
+// lookup is not repeated.
+func (s *Service) tenantIDs(ctx context.Context, owner *Owner) ([]primitive.ObjectID, error) {
+	patientTenants, err := s.repo.PatientTenantIDs(ctx, owner.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIDs := slices.Clone(owner.TenantIds)
+	for _, tenantID := range patientTenants {
+		if slices.Contains(tenantIDs, tenantID) {
+			continue
+		}
+		if err := s.repo.AddTenantID(ctx, owner.ID.Hex(), tenantID); err != nil {
+			return nil, err
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	return tenantIDs, nil
+}
+
 func (s *Service) AddPushToken(ctx context.Context, ownerID string, dto *RegisterPushTokenDTO) error {
+	now := time.Now()
 	token := PushToken{
-		Token:     dto.Token,
-		Platform:  dto.Platform,
-		Active:    true,
-		UpdatedAt: time.Now(),
+		Token:      dto.Token,
+		Platform:   dto.Platform,
+		Active:     true,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		UpdatedAt:  now,
 	}
 	return s.repo.AddPushToken(ctx, ownerID, token)
 }
@@ -50,6 +131,29 @@ func (s *Service) RemovePushToken(ctx context.Context, ownerID string, token str
 	return s.repo.RemovePushToken(ctx, ownerID, token)
 }
 
+func (s *Service) UpdateNotificationPrefs(ctx context.Context, ownerID string, dto *UpdateNotificationPrefsDTO) (*OwnerResponse, error) {
+	owner, err := s.repo.FindByID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := NotificationPreferences{
+		Muted:      dto.Muted,
+		MutedTypes: dto.MutedTypes,
+		QuietHours: owner.NotificationPrefs.QuietHours,
+	}
+	if q := dto.QuietHours; q != nil {
+		if q.Enabled && (q.Start == "" || q.End == "" || q.Start == q.End) {
+			return nil, ErrInvalidQuietHours
+		}
+		prefs.QuietHours = QuietHours{Enabled: q.Enabled, Start: q.Start, End: q.End}
+	}
+	if err := s.repo.UpdateNotificationPrefs(ctx, ownerID, prefs); err != nil {
+		return nil, err
+	}
+	return s.GetMe(ctx, ownerID)
+}
+
 // FindAll is for admin panel usage (staff with RBAC)
 func (s *Service) FindAll(ctx context.Context, params pagination.Params) (*PaginatedOwnersResponse, error) {
 	owners, total, err := s.repo.FindAll(ctx, params)
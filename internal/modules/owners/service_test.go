@@ -0,0 +1,70 @@
+package owners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockOwnerRepo stores owners in memory; methods the tests do not use panic
+// through the embedded nil interface.
+type mockOwnerRepo struct {
+	OwnerRepository
+	owners         map[string]*Owner
+	patientTenants map[string][]primitive.ObjectID
+}
+
+func (m *mockOwnerRepo) FindByID(ctx context.Context, id string) (*Owner, error) {
+	owner, ok := m.owners[id]
+	if !ok {
+		return nil, ErrOwnerNotFound
+	}
+	return owner, nil
+}
+
+func (m *mockOwnerRepo) AddTenantID(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	owner := m.owners[id]
+	if !owner.BelongsToTenant(tenantID) {
+		owner.TenantIds = append(owner.TenantIds, tenantID)
+	}
+	return nil
+}
+
+func (m *mockOwnerRepo) PatientTenantIDs(ctx context.Context, id string) ([]primitive.ObjectID, error) {
+	return m.patientTenants[id], nil
+}
+
+// An owner registered before tenant_ids was maintained has an empty list and
+// is only linked to the clinic through a patient.
+func TestBelongsToTenant_OwnerWithoutTenantIDs(t *testing.T) {
+	tenantID := primitive.NewObjectID()
+	owner := &Owner{ID: primitive.NewObjectID()}
+	repo := &mockOwnerRepo{
+		owners:         map[string]*Owner{owner.ID.Hex(): owner},
+		patientTenants: map[string][]primitive.ObjectID{owner.ID.Hex(): {tenantID}},
+	}
+	service := NewService(repo, nil)
+
+	ok, err := service.BelongsToTenant(context.Background(), owner.ID.Hex(), tenantID)
+
+	assert.NoError(t, err)
+	assert.True(t, ok, "an active patient in the tenant must grant access")
+	assert.Equal(t, []primitive.ObjectID{tenantID}, owner.TenantIds, "the missing link must be stored")
+}
+
+func TestBelongsToTenant_OtherTenant(t *testing.T) {
+	tenantID := primitive.NewObjectID()
+	owner := &Owner{ID: primitive.NewObjectID(), TenantIds: []primitive.ObjectID{primitive.NewObjectID()}}
+	repo := &mockOwnerRepo{
+		owners:         map[string]*Owner{owner.ID.Hex(): owner},
+		patientTenants: map[string][]primitive.ObjectID{owner.ID.Hex(): {primitive.NewObjectID()}},
+	}
+	service := NewService(repo, nil)
+
+	ok, err := service.BelongsToTenant(context.Background(), owner.ID.Hex(), tenantID)
+
+	assert.NoError(t, err)
+	assert.False(t, ok, "an owner without patients in the tenant must be rejected")
+}
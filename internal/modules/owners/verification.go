@@ -0,0 +1,238 @@
+package owners
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/platform/email"
+	"github.com/eren_dev/go_server/internal/platform/ratelimit"
+	"github.com/eren_dev/go_server/internal/platform/sms"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// ContactVerification is the pending code for one owner and channel. Only the
+// hash of the code is stored; documents expire through a TTL index.
+type ContactVerification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	OwnerID   primitive.ObjectID `bson:"owner_id"`
+	Channel   string             `bson:"channel"`
+	Target    string             `bson:"target"`
+	CodeHash  string             `bson:"code_hash"`
+	Attempts  int                `bson:"attempts"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+type VerificationRepository interface {
+	// Replace stores v as the only pending code for its owner and channel.
+	Replace(ctx context.Context, v *ContactVerification) error
+	// FindActive returns the unexpired code for the owner and channel.
+	FindActive(ctx context.Context, ownerID primitive.ObjectID, channel string) (*ContactVerification, error)
+	IncrementAttempts(ctx context.Context, id primitive.ObjectID) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type verificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewVerificationRepository(db *database.MongoDB) VerificationRepository {
+	return &verificationRepository{collection: db.Collection("contact_verifications")}
+}
+
+func (r *verificationRepository) Replace(ctx context.Context, v *ContactVerification) error {
+	filter := bson.M{"owner_id": v.OwnerID, "channel": v.Channel}
+	_, err := r.collection.ReplaceOne(ctx, filter, v, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *verificationRepository) FindActive(ctx context.Context, ownerID primitive.ObjectID, channel string) (*ContactVerification, error) {
+	filter := bson.M{
+		"owner_id":   ownerID,
+		"channel":    channel,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	var v ContactVerification
+	if err := r.collection.FindOne(ctx, filter).Decode(&v); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrVerificationNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *verificationRepository) IncrementAttempts(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"attempts": 1}})
+	return err
+}
+
+func (r *verificationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// VerificationService proves an owner can be reached on their email or phone
+// by sending a one-time code there and checking it back.
+type VerificationService struct {
+	repo        OwnerRepository
+	codes       VerificationRepository
+	email       email.EmailSender
+	sms         sms.SMSSender
+	limiter     *ratelimit.Limiter
+	ttl         time.Duration
+	maxAttempts int
+}
+
+func NewVerificationService(repo OwnerRepository, codes VerificationRepository, emailSender email.EmailSender, smsSender sms.SMSSender, cfg *config.Config) *VerificationService {
+	return &VerificationService{
+		repo:        repo,
+		codes:       codes,
+		email:       emailSender,
+		sms:         smsSender,
+		limiter:     NewVerificationLimiter(cfg.ContactVerificationPerHour),
+		ttl:         time.Duration(cfg.ContactVerificationTTLMinutes) * time.Minute,
+		maxAttempts: cfg.ContactVerificationMaxAttempts,
+	}
+}
+
+// NewVerificationLimiter builds a limiter allowing perHour codes per hour for
+// each owner and channel.
+func NewVerificationLimiter(perHour int) *ratelimit.Limiter {
+	if perHour <= 0 {
+		perHour = 1
+	}
+	return ratelimit.NewLimiter(ratelimit.Config{
+		Enabled:     true,
+		TenantRPS:   float64(perHour) / 3600,
+		TenantBurst: perHour,
+		GlobalRPS:   20,
+		GlobalBurst: 200,
+	})
+}
+
+// SendCode sends a fresh code to the owner's current address for channel,
+// replacing any code still pending for it.
+func (s *VerificationService) SendCode(ctx context.Context, ownerID, channel string) error {
+	if channel != ContactChannelEmail && channel != ContactChannelPhone {
+		return ErrInvalidContactChannel
+	}
+	if !s.channelEnabled(channel) {
+		return ErrContactChannelDisabled
+	}
+
+	owner, err := s.repo.FindByID(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	target := owner.Contact(channel)
+	if target == "" {
+		return ErrContactMissing
+	}
+
+	if allowed, _ := s.limiter.Allow(owner.ID.Hex() + ":" + channel); !allowed {
+		return ErrVerificationRateLimited
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := s.codes.Replace(ctx, &ContactVerification{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   owner.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  hashCode(code),
+		ExpiresAt: now.Add(s.ttl),
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+
+	minutes := int(s.ttl / time.Minute)
+	if channel == ContactChannelPhone {
+		return s.sms.Send(ctx, sms.Message{
+			To:   target,
+			Body: fmt.Sprintf("Tu código de verificación es %s. Vence en %d minutos.", code, minutes),
+		})
+	}
+	return s.email.Send(ctx, email.Message{
+		To:      []string{target},
+		Subject: "Código de verificación",
+		Body:    fmt.Sprintf("Hola %s,\n\nTu código de verificación es %s. Vence en %d minutos.\n\nSi no lo solicitaste, ignora este mensaje.", owner.Name, code, minutes),
+	})
+}
+
+// Confirm checks code against the pending one and marks the channel verified.
+// Each wrong guess counts; once the attempts run out the code is dropped and a
+// new one must be requested.
+func (s *VerificationService) Confirm(ctx context.Context, ownerID, channel, code string) (*OwnerResponse, error) {
+	if channel != ContactChannelEmail && channel != ContactChannelPhone {
+		return nil, ErrInvalidContactChannel
+	}
+	oid, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, ErrInvalidOwnerID
+	}
+
+	pending, err := s.codes.FindActive(ctx, oid, channel)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Attempts >= s.maxAttempts {
+		_ = s.codes.Delete(ctx, pending.ID)
+		return nil, ErrVerificationAttemptsUsed
+	}
+	if subtle.ConstantTimeCompare([]byte(hashCode(code)), []byte(pending.CodeHash)) != 1 {
+		if err := s.codes.IncrementAttempts(ctx, pending.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidVerificationCode
+	}
+
+	if err := s.repo.MarkContactVerified(ctx, ownerID, channel, pending.Target); err != nil {
+		return nil, err
+	}
+	_ = s.codes.Delete(ctx, pending.ID)
+
+	owner, err := s.repo.FindByID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return ToResponse(owner), nil
+}
+
+func (s *VerificationService) channelEnabled(channel string) bool {
+	if channel == ContactChannelPhone {
+		return s.sms != nil && s.sms.IsEnabled()
+	}
+	return s.email != nil && s.email.IsEnabled()
+}
+
+// newVerificationCode returns a random 6-digit code.
+func newVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
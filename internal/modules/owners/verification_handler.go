@@ -0,0 +1,72 @@
+package owners
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+type VerificationHandler struct {
+	service *VerificationService
+}
+
+func NewVerificationHandler(service *VerificationService) *VerificationHandler {
+	return &VerificationHandler{service: service}
+}
+
+// SendCode sends a verification code to the owner's email or phone.
+//
+//	@Summary		Send contact verification code
+//	@Description	Sends a 6-digit code to the owner's current email or phone. Codes expire and only a few can be requested per hour.
+//	@Tags			mobile/owners
+//	@Produce		json
+//	@Param			channel	path		string	true	"Contact channel"	Enums(email, phone)
+//	@Success		200		{object}	map[string]string
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		429		{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/mobile/owners/me/verify/{channel} [post]
+func (h *VerificationHandler) SendCode(c *gin.Context) (any, error) {
+	ownerID := sharedAuth.GetUserID(c)
+	if ownerID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	if err := h.service.SendCode(c.Request.Context(), ownerID, c.Param("channel")); err != nil {
+		return nil, err
+	}
+
+	return gin.H{"message": "verification code sent"}, nil
+}
+
+// Confirm checks the code and marks the contact channel as verified.
+//
+//	@Summary		Confirm contact verification code
+//	@Tags			mobile/owners
+//	@Accept			json
+//	@Produce		json
+//	@Param			channel	path		string				true	"Contact channel"	Enums(email, phone)
+//	@Param			body	body		ConfirmContactDTO	true	"Verification code"
+//	@Success		200		{object}	OwnerResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		429		{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/mobile/owners/me/verify/{channel}/confirm [post]
+func (h *VerificationHandler) Confirm(c *gin.Context) (any, error) {
+	ownerID := sharedAuth.GetUserID(c)
+	if ownerID == "" {
+		return nil, sharedErrors.ErrUnauthorized
+	}
+
+	var dto ConfirmContactDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Confirm(c.Request.Context(), ownerID, c.Param("channel"), dto.Code)
+}
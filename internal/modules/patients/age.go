@@ -0,0 +1,91 @@
+package patients
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PatientAge is a patient's age in whole years and months since birth.
+type PatientAge struct {
+	Years  int `json:"years"`
+	Months int `json:"months"`
+	// TotalMonths is the age in months, the unit age thresholds are set in
+	TotalMonths int `json:"total_months"`
+}
+
+// AgeAt returns the patient's age on the day of at, or nil when the birth
+// date is unknown or later than at. A month is counted once its day of the
+// month is reached; births on the 29th-31st count it on the last day of
+// shorter months.
+func (p *Patient) AgeAt(at time.Time) *PatientAge {
+	if p.BirthDate == nil {
+		return nil
+	}
+	months := monthsBetween(*p.BirthDate, at)
+	if months < 0 {
+		return nil
+	}
+	return &PatientAge{Years: months / 12, Months: months % 12, TotalMonths: months}
+}
+
+// monthsBetween counts the whole months from birth to at, comparing calendar
+// dates in at's location.
+func monthsBetween(birth, at time.Time) int {
+	birth = birth.In(at.Location())
+	months := (at.Year()-birth.Year())*12 + int(at.Month()) - int(birth.Month())
+
+	day := birth.Day()
+	if last := daysIn(at.Year(), at.Month()); day > last {
+		day = last
+	}
+	if at.Day() < day {
+		months--
+	}
+	return months
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// FindReachingAge returns the clinic's active patients born in (bornAfter,
+// bornUntil] that have not been reminded of the age milestone key yet. A
+// zero speciesID matches every species.
+func (r *patientRepository) FindReachingAge(ctx context.Context, tenantID, speciesID primitive.ObjectID, bornAfter, bornUntil time.Time, key string) ([]Patient, error) {
+	filter := bson.M{
+		"tenant_id":          tenantID,
+		"deleted_at":         nil,
+		"active":             true,
+		"status":             bson.M{"$ne": PatientStatusDeceased},
+		"birth_date":         bson.M{"$gt": bornAfter, "$lte": bornUntil},
+		"age_reminders_sent": bson.M{"$ne": key},
+	}
+	if !speciesID.IsZero() {
+		filter["species_id"] = speciesID
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []Patient
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// MarkAgeReminderSent records that the owner was reminded of the age
+// milestone key, so the patient is not reminded of it again.
+func (r *patientRepository) MarkAgeReminderSent(ctx context.Context, tenantID, patientID primitive.ObjectID, key string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": patientID, "tenant_id": tenantID},
+		bson.M{"$addToSet": bson.M{"age_reminders_sent": key}},
+	)
+	return err
+}
@@ -3,6 +3,7 @@ package patients
 import (
 	"time"
 
+	"github.com/eren_dev/go_server/internal/modules/tags"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
@@ -50,6 +51,9 @@ type CreatePatientDTO struct {
 	Sterilized bool       `json:"sterilized"`
 	AvatarURL  string     `json:"avatar_url"`
 	Notes      string     `json:"notes"`
+	Tags       []string   `json:"tags"                                             example:"diabetic,senior"`
+	// ConfirmCreate skips the duplicate check once staff confirmed it is a different pet
+	ConfirmCreate bool `json:"confirm_create"`
 }
 
 type UpdatePatientDTO struct {
@@ -65,26 +69,50 @@ type UpdatePatientDTO struct {
 	AvatarURL  string     `json:"avatar_url"`
 	Notes      string     `json:"notes"`
 	Active     *bool      `json:"active"`
+	// Tags replaces the patient's tags when present; send [] to clear them
+	Tags []string `json:"tags"`
+}
+
+// PatientFilters narrows the patient list
+type PatientFilters struct {
+	Tags tags.Filter
+}
+
+// MarkDeceasedDTO records a patient's death. DeceasedAt defaults to now.
+type MarkDeceasedDTO struct {
+	DeceasedAt *time.Time `json:"deceased_at"`
+}
+
+// MarkDeceasedResponse is the updated patient with how many upcoming
+// appointments were cancelled.
+type MarkDeceasedResponse struct {
+	Patient               PatientResponse `json:"patient"`
+	CancelledAppointments int64           `json:"cancelled_appointments"`
 }
 
 type PatientResponse struct {
-	ID         string     `json:"id"`
-	TenantID   string     `json:"tenant_id"`
-	OwnerID    string     `json:"owner_id"`
-	SpeciesID  string     `json:"species_id"`
-	Name       string     `json:"name"`
-	Breed      string     `json:"breed,omitempty"`
-	Color      string     `json:"color,omitempty"`
-	BirthDate  *time.Time `json:"birth_date,omitempty"`
-	Gender     Gender     `json:"gender"`
-	Weight     float64    `json:"weight"`
-	Microchip  string     `json:"microchip,omitempty"`
-	Sterilized bool       `json:"sterilized"`
-	AvatarURL  string     `json:"avatar_url,omitempty"`
-	Notes      string     `json:"notes,omitempty"`
-	Active     bool       `json:"active"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID         string        `json:"id"`
+	TenantID   string        `json:"tenant_id"`
+	OwnerID    string        `json:"owner_id"`
+	SpeciesID  string        `json:"species_id"`
+	Name       string        `json:"name"`
+	Breed      string        `json:"breed,omitempty"`
+	Color      string        `json:"color,omitempty"`
+	BirthDate  *time.Time    `json:"birth_date,omitempty"`
+	// Age is omitted when the birth date is unknown
+	Age        *PatientAge   `json:"age,omitempty"`
+	Gender     Gender        `json:"gender"`
+	Weight     float64       `json:"weight"`
+	Microchip  string        `json:"microchip,omitempty"`
+	Sterilized bool          `json:"sterilized"`
+	AvatarURL  string        `json:"avatar_url,omitempty"`
+	Notes      string        `json:"notes,omitempty"`
+	Tags       []string      `json:"tags,omitempty"`
+	Active     bool          `json:"active"`
+	Status     PatientStatus `json:"status"`
+	DeceasedAt *time.Time    `json:"deceased_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
 }
 
 type PaginatedPatientsResponse struct {
@@ -102,13 +130,17 @@ func toPatientResponse(p *Patient) PatientResponse {
 		Breed:      p.Breed,
 		Color:      p.Color,
 		BirthDate:  p.BirthDate,
+		Age:        p.AgeAt(time.Now()),
 		Gender:     p.Gender,
 		Weight:     p.Weight,
 		Microchip:  p.Microchip,
 		Sterilized: p.Sterilized,
 		AvatarURL:  p.AvatarURL,
 		Notes:      p.Notes,
+		Tags:       p.Tags,
 		Active:     p.Active,
+		Status:     p.EffectiveStatus(),
+		DeceasedAt: p.DeceasedAt,
 		CreatedAt:  p.CreatedAt,
 		UpdatedAt:  p.UpdatedAt,
 	}
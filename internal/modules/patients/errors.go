@@ -1,6 +1,11 @@
 package patients
 
-import "errors"
+import (
+	"errors"
+	"strings"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
 
 var (
 	ErrPatientNotFound  = errors.New("patient not found")
@@ -12,3 +17,24 @@ var (
 	ErrSpeciesNotFound  = errors.New("species not found")
 	ErrSpeciesConflict  = errors.New("similar species already exists")
 )
+
+var (
+	ErrInvalidQRToken = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_QR_TOKEN", "QR code is not valid for this clinic")
+	ErrQRTokenExpired = sharedErrors.New(sharedErrors.ErrUnprocessable, "QR_TOKEN_EXPIRED", "QR code has expired")
+
+	ErrPatientDeceased     = sharedErrors.New(sharedErrors.ErrConflict, "PATIENT_DECEASED", "patient is already marked as deceased")
+	ErrDeceasedReactivated = sharedErrors.New(sharedErrors.ErrUnprocessable, "PATIENT_DECEASED", "a deceased patient cannot be reactivated or deactivated")
+	ErrDeceasedInFuture    = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_DECEASED_AT", "deceased_at cannot be in the future")
+
+	ErrImplausibleWeight  = sharedErrors.New(sharedErrors.ErrInvalidInput, "IMPLAUSIBLE_WEIGHT", "weight must be between 0.01 and 1500 kg")
+	ErrMeasuredAtInFuture = sharedErrors.New(sharedErrors.ErrInvalidInput, "INVALID_MEASURED_AT", "measured_at cannot be in the future")
+)
+
+// ErrPossibleDuplicate reports patients that look like the one being created.
+// It is a soft check: resending the request with confirm_create creates it anyway.
+func ErrPossibleDuplicate(candidateIDs []string) error {
+	err := sharedErrors.New(sharedErrors.ErrConflict, "POSSIBLE_DUPLICATE_PATIENT",
+		"a patient with the same name and species already exists for this owner, set confirm_create to create it anyway")
+	err.Details = map[string]interface{}{"candidate_ids": strings.Join(candidateIDs, ",")}
+	return err
+}
@@ -3,13 +3,17 @@ package patients
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/tags"
 	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
 	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
 	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 	"github.com/eren_dev/go_server/internal/shared/validation"
@@ -18,13 +22,15 @@ import (
 type Handler struct {
 	service        *PatientService
 	speciesService *SpeciesService
+	weights        *WeightService
 	ownerRepo      owners.OwnerRepository
 }
 
-func NewHandler(service *PatientService, speciesService *SpeciesService, ownerRepo owners.OwnerRepository) *Handler {
+func NewHandler(service *PatientService, speciesService *SpeciesService, weights *WeightService, ownerRepo owners.OwnerRepository) *Handler {
 	return &Handler{
 		service:        service,
 		speciesService: speciesService,
+		weights:        weights,
 		ownerRepo:      ownerRepo,
 	}
 }
@@ -34,6 +40,7 @@ func NewHandler(service *PatientService, speciesService *SpeciesService, ownerRe
 // Create creates a new patient.
 //
 //	@Summary		Create patient
+//	@Description	Returns 409 POSSIBLE_DUPLICATE_PATIENT with the candidate IDs in details when the owner already has a pet of the same species and name; resend with confirm_create to create it anyway.
 //	@Tags			patients
 //	@Accept			json
 //	@Produce		json
@@ -54,7 +61,7 @@ func (h *Handler) Create(c *gin.Context) (any, error) {
 	return h.service.Create(c.Request.Context(), tenantID, &dto)
 }
 
-// FindAll lists all patients for a tenant.
+// FindAll lists all patients for a tenant, optionally filtered by tags.
 //
 //	@Summary		List patients
 //	@Tags			patients
@@ -62,6 +69,8 @@ func (h *Handler) Create(c *gin.Context) (any, error) {
 //	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
 //	@Param			skip		query		int		false	"Skip"
 //	@Param			limit		query		int		false	"Limit"
+//	@Param			tags		query		string	false	"Comma-separated tags"
+//	@Param			tags_mode	query		string	false	"any (default) or all"
 //	@Success		200			{object}	PaginatedPatientsResponse
 //	@Failure		400			{object}	map[string]string
 //	@Security		Bearer
@@ -69,7 +78,11 @@ func (h *Handler) Create(c *gin.Context) (any, error) {
 func (h *Handler) FindAll(c *gin.Context) (any, error) {
 	tenantID := sharedMiddleware.GetTenantID(c)
 	params := pagination.FromContext(c)
-	return h.service.FindAll(c.Request.Context(), tenantID, params)
+	tagFilter, err := tags.FromQuery(c)
+	if err != nil {
+		return nil, err
+	}
+	return h.service.FindAll(c.Request.Context(), tenantID, PatientFilters{Tags: tagFilter}, params)
 }
 
 // FindByID returns a patient by ID.
@@ -79,13 +92,84 @@ func (h *Handler) FindAll(c *gin.Context) (any, error) {
 //	@Produce		json
 //	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
 //	@Param			id			path		string	true	"Patient ID"
+//	@Param			If-None-Match	header	string	false	"ETag from a previous response"
 //	@Success		200			{object}	PatientResponse
+//	@Success		304			"Not modified"
 //	@Failure		404			{object}	map[string]string
 //	@Security		Bearer
 //	@Router			/api/patients/{id} [get]
 func (h *Handler) FindByID(c *gin.Context) (any, error) {
 	tenantID := sharedMiddleware.GetTenantID(c)
-	return h.service.FindByID(c.Request.Context(), tenantID, c.Param("id"))
+	resp, err := h.service.FindByID(c.Request.Context(), tenantID, c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	return &httpx.Tagged{Data: resp, UpdatedAt: resp.UpdatedAt}, nil
+}
+
+// QRCode returns a PNG QR code for the patient's tag.
+//
+//	@Summary		Patient QR code
+//	@Description	PNG QR encoding a signed reference to the patient that only resolves at this clinic. Set expires_in_hours for a temporary visitor pass; omit it for a permanent tag.
+//	@Tags			patients
+//	@Produce		png
+//	@Param			X-Tenant-ID			header		string	true	"Tenant ID"
+//	@Param			id					path		string	true	"Patient ID"
+//	@Param			expires_in_hours	query		int		false	"Hours until the code expires (1-720)"
+//	@Param			size				query		int		false	"Image size in pixels (128-1024, default 256)"
+//	@Success		200					{file}		binary
+//	@Failure		400					{object}	map[string]string
+//	@Failure		404					{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/patients/{id}/qr [get]
+func (h *Handler) QRCode(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	var validFor time.Duration
+	if raw := c.Query("expires_in_hours"); raw != "" {
+		hours, err := strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > 720 {
+			return nil, sharedErrors.Validation("expires_in_hours", "must be between 1 and 720")
+		}
+		validFor = time.Duration(hours) * time.Hour
+	}
+
+	size := 256
+	if raw := c.Query("size"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 128 || n > 1024 {
+			return nil, sharedErrors.Validation("size", "must be between 128 and 1024")
+		}
+		size = n
+	}
+
+	png, err := h.service.QRCode(c.Request.Context(), tenantID, c.Param("id"), validFor, size)
+	if err != nil {
+		return nil, err
+	}
+	return &httpx.File{ContentType: "image/png", Filename: "patient-" + c.Param("id") + ".png", Data: png}, nil
+}
+
+// ResolveQR returns the patient referenced by a scanned QR code.
+//
+//	@Summary		Resolve patient QR code
+//	@Tags			patients
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			token		query		string	true	"Token read from the QR code"
+//	@Success		200			{object}	PatientResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		422			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/patients/resolve-qr [get]
+func (h *Handler) ResolveQR(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	token := c.Query("token")
+	if token == "" {
+		return nil, sharedErrors.Validation("token", "is required")
+	}
+	return h.service.ResolveQR(c.Request.Context(), tenantID, token)
 }
 
 // Update updates a patient.
@@ -113,6 +197,89 @@ func (h *Handler) Update(c *gin.Context) (any, error) {
 	return h.service.Update(c.Request.Context(), tenantID, c.Param("id"), &dto)
 }
 
+// MarkDeceased records a patient's death, cancelling its upcoming appointments
+// and stopping its reminders.
+//
+//	@Summary		Mark patient as deceased
+//	@Tags			patients
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string			true	"Tenant ID"
+//	@Param			id			path		string			true	"Patient ID"
+//	@Param			body		body		MarkDeceasedDTO	false	"Date of death (defaults to now)"
+//	@Success		200			{object}	MarkDeceasedResponse
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/patients/{id}/mark-deceased [post]
+func (h *Handler) MarkDeceased(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, _ := primitive.ObjectIDFromHex(sharedAuth.GetUserID(c))
+
+	var dto MarkDeceasedDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			return nil, validation.Validate(err)
+		}
+	}
+
+	return h.service.MarkDeceased(c.Request.Context(), tenantID, userID, c.Param("id"), &dto)
+}
+
+// RecordWeight stores a quick weight reading taken outside a medical record.
+//
+//	@Summary		Record patient weight
+//	@Tags			patients
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string			true	"Tenant ID"
+//	@Param			id			path		string			true	"Patient ID"
+//	@Param			body		body		RecordWeightDTO	true	"Weight in kg"
+//	@Success		200			{object}	WeightMeasurementResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/patients/{id}/weight [post]
+func (h *Handler) RecordWeight(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, _ := primitive.ObjectIDFromHex(sharedAuth.GetUserID(c))
+
+	var dto RecordWeightDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.weights.Record(c.Request.Context(), tenantID, userID, c.Param("id"), &dto)
+}
+
+// WeightHistory returns the patient's weight trend from quick readings and medical records.
+//
+//	@Summary		Patient weight history
+//	@Tags			patients
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
+//	@Param			id			path		string	true	"Patient ID"
+//	@Param			from		query		string	false	"Only readings since this date (RFC3339)"
+//	@Success		200			{object}	WeightHistoryResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Security		Bearer
+//	@Router			/api/patients/{id}/weight [get]
+func (h *Handler) WeightHistory(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	var from *time.Time
+	if raw := c.Query("from"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, sharedErrors.Validation("from", "must be an RFC3339 date")
+		}
+		from = &t
+	}
+
+	return h.weights.History(c.Request.Context(), tenantID, c.Param("id"), from)
+}
+
 // Delete soft-deletes a patient.
 //
 //	@Summary		Delete patient
@@ -216,7 +383,9 @@ func (h *Handler) MobileFindAll(c *gin.Context) (any, error) {
 //	@Produce		json
 //	@Param			X-Tenant-ID	header		string	true	"Tenant ID"
 //	@Param			id			path		string	true	"Patient ID"
+//	@Param			If-None-Match	header	string	false	"ETag from a previous response"
 //	@Success		200			{object}	PatientResponse
+//	@Success		304			"Not modified"
 //	@Failure		401			{object}	map[string]string
 //	@Failure		403			{object}	map[string]string
 //	@Failure		404			{object}	map[string]string
@@ -240,5 +409,5 @@ func (h *Handler) MobileFindByID(c *gin.Context) (any, error) {
 		return nil, sharedErrors.ErrForbidden
 	}
 
-	return resp, nil
+	return &httpx.Tagged{Data: resp, UpdatedAt: resp.UpdatedAt}, nil
 }
@@ -0,0 +1,35 @@
+package patients
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the patients and weight_measurements collections
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("patients").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Tag filters on the list and tag autocomplete
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "tags", Value: 1}},
+		},
+		{
+			// Patients reaching an age milestone, for the age reminders job
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "birth_date", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("weight_measurements").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Weight history and the latest reading per patient
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "patient_id", Value: 1}, {Key: "measured_at", Value: -1}},
+		},
+	})
+	return err
+}
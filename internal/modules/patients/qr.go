@@ -0,0 +1,89 @@
+package patients
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QR tokens look like "<patient_id>.<expires_unix>.<signature>", with 0 as
+// expiry for permanent tags. The tenant is not part of the token but is part
+// of the signed message, so a tag only resolves at the clinic that printed it.
+const qrTokenParts = 3
+
+func qrSignature(secret []byte, tenantID primitive.ObjectID, patientHex, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "patient-qr|%s|%s|%s", tenantID.Hex(), patientHex, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signQRToken(secret []byte, tenantID, patientID primitive.ObjectID, expiresAt *time.Time) string {
+	expires := "0"
+	if expiresAt != nil {
+		expires = strconv.FormatInt(expiresAt.Unix(), 10)
+	}
+	return strings.Join([]string{patientID.Hex(), expires, qrSignature(secret, tenantID, patientID.Hex(), expires)}, ".")
+}
+
+// parseQRToken verifies the signature for tenantID and the expiry, and
+// returns the patient the token points to.
+func parseQRToken(secret []byte, tenantID primitive.ObjectID, token string, now time.Time) (primitive.ObjectID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != qrTokenParts {
+		return primitive.NilObjectID, ErrInvalidQRToken
+	}
+	patientHex, expires, signature := parts[0], parts[1], parts[2]
+
+	expected := qrSignature(secret, tenantID, patientHex, expires)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return primitive.NilObjectID, ErrInvalidQRToken
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidQRToken
+	}
+	if expiresUnix != 0 && now.Unix() > expiresUnix {
+		return primitive.NilObjectID, ErrQRTokenExpired
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(patientHex)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidQRToken
+	}
+	return patientID, nil
+}
+
+// QRCode renders a PNG QR encoding a signed reference to the patient. A
+// positive validFor makes a temporary pass; zero makes a permanent tag.
+func (s *PatientService) QRCode(ctx context.Context, tenantID primitive.ObjectID, id string, validFor time.Duration, size int) ([]byte, error) {
+	p, err := s.repo.FindByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if validFor > 0 {
+		t := time.Now().Add(validFor)
+		expiresAt = &t
+	}
+
+	return qrcode.Encode(signQRToken(s.qrSecret, tenantID, p.ID, expiresAt), qrcode.Medium, size)
+}
+
+// ResolveQR returns the patient referenced by a scanned QR token.
+func (s *PatientService) ResolveQR(ctx context.Context, tenantID primitive.ObjectID, token string) (*PatientResponse, error) {
+	patientID, err := parseQRToken(s.qrSecret, tenantID, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.FindByID(ctx, tenantID, patientID.Hex())
+}
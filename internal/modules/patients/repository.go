@@ -2,6 +2,8 @@ package patients
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -9,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/eren_dev/go_server/internal/platform/cache"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
@@ -22,6 +25,14 @@ type SpeciesRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*Species, error)
 }
 
+// Species are read on every patient listing and vaccination check but only
+// ever created, so both lookups are cached and Create invalidates the
+// tenant's list.
+var (
+	speciesListCache = cache.NewReference[[]Species]()
+	speciesCache     = cache.NewReference[Species]()
+)
+
 type speciesRepository struct {
 	collection *mongo.Collection
 }
@@ -34,6 +45,7 @@ func NewSpeciesRepository(db *database.MongoDB) SpeciesRepository {
 
 func (r *speciesRepository) Create(ctx context.Context, s *Species) error {
 	_, err := r.collection.InsertOne(ctx, s)
+	speciesListCache.Delete(cache.TenantKey(s.TenantID.Hex(), "species"))
 	return err
 }
 
@@ -54,29 +66,40 @@ func (r *speciesRepository) FindByNormalizedName(ctx context.Context, tenantID p
 }
 
 func (r *speciesRepository) FindAllByTenant(ctx context.Context, tenantID primitive.ObjectID) ([]Species, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"tenant_id":  tenantID,
-		"deleted_at": nil,
-	}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
+	results, err := speciesListCache.Load(cache.TenantKey(tenantID.Hex(), "species"), func() ([]Species, error) {
+		cursor, err := r.collection.Find(ctx, bson.M{
+			"tenant_id":  tenantID,
+			"deleted_at": nil,
+		}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
 
-	var results []Species
-	if err := cursor.All(ctx, &results); err != nil {
+		var results []Species
+		if err := cursor.All(ctx, &results); err != nil {
+			return nil, err
+		}
+		return results, nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	return results, nil
+	// Callers get their own slice so they cannot change the cached one
+	return append([]Species(nil), results...), nil
 }
 
 func (r *speciesRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*Species, error) {
-	var s Species
-	err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&s)
-	if err != nil {
+	// Like the query, the key needs no tenant: species IDs are unique across tenants
+	s, err := speciesCache.Load("species:"+id.Hex(), func() (Species, error) {
+		var s Species
+		err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&s)
 		if err == mongo.ErrNoDocuments {
-			return nil, ErrSpeciesNotFound
+			return s, ErrSpeciesNotFound
 		}
+		return s, err
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &s, nil
@@ -86,20 +109,29 @@ func (r *speciesRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 
 type PatientRepository interface {
 	Create(ctx context.Context, p *Patient) error
-	FindAll(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]Patient, int64, error)
+	FindAll(ctx context.Context, tenantID primitive.ObjectID, filters PatientFilters, params pagination.Params) ([]Patient, int64, error)
 	FindByID(ctx context.Context, tenantID primitive.ObjectID, id string) (*Patient, error)
 	FindByOwner(ctx context.Context, tenantID primitive.ObjectID, ownerID primitive.ObjectID, params pagination.Params) ([]Patient, int64, error)
+	FindPossibleDuplicates(ctx context.Context, tenantID, ownerID, speciesID primitive.ObjectID, name string) ([]Patient, error)
 	Update(ctx context.Context, tenantID primitive.ObjectID, id string, dto *UpdatePatientDTO) (*Patient, error)
 	Delete(ctx context.Context, tenantID primitive.ObjectID, id string) error
+	MarkDeceased(ctx context.Context, tenantID primitive.ObjectID, id string, deceasedAt time.Time) (*Patient, error)
+	CancelFutureAppointments(ctx context.Context, tenantID, patientID, changedBy primitive.ObjectID, reason string, now time.Time) (int64, error)
+	FindReachingAge(ctx context.Context, tenantID, speciesID primitive.ObjectID, bornAfter, bornUntil time.Time, key string) ([]Patient, error)
+	MarkAgeReminderSent(ctx context.Context, tenantID, patientID primitive.ObjectID, key string) error
 }
 
 type patientRepository struct {
-	collection *mongo.Collection
+	collection             *mongo.Collection
+	appointments           *mongo.Collection
+	appointmentTransitions *mongo.Collection
 }
 
 func NewPatientRepository(db *database.MongoDB) PatientRepository {
 	return &patientRepository{
-		collection: db.Collection("patients"),
+		collection:             db.Collection("patients"),
+		appointments:           db.Collection("appointments"),
+		appointmentTransitions: db.Collection("appointment_status_transitions"),
 	}
 }
 
@@ -114,8 +146,9 @@ func (r *patientRepository) Create(ctx context.Context, p *Patient) error {
 	return nil
 }
 
-func (r *patientRepository) FindAll(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) ([]Patient, int64, error) {
+func (r *patientRepository) FindAll(ctx context.Context, tenantID primitive.ObjectID, filters PatientFilters, params pagination.Params) ([]Patient, int64, error) {
 	filter := bson.M{"tenant_id": tenantID, "deleted_at": nil}
+	filters.Tags.Apply(filter)
 
 	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -194,6 +227,33 @@ func (r *patientRepository) FindByOwner(ctx context.Context, tenantID primitive.
 	return results, total, nil
 }
 
+// FindPossibleDuplicates returns the owner's patients of the same species
+// whose name matches case-insensitively, ignoring surrounding whitespace.
+func (r *patientRepository) FindPossibleDuplicates(ctx context.Context, tenantID, ownerID, speciesID primitive.ObjectID, name string) ([]Patient, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"owner_id":   ownerID,
+		"species_id": speciesID,
+		"deleted_at": nil,
+		"name": primitive.Regex{
+			Pattern: `^\s*` + regexp.QuoteMeta(strings.TrimSpace(name)) + `\s*$`,
+			Options: "i",
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(10))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []Patient
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (r *patientRepository) Update(ctx context.Context, tenantID primitive.ObjectID, id string, dto *UpdatePatientDTO) (*Patient, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -239,8 +299,16 @@ func (r *patientRepository) Update(ctx context.Context, tenantID primitive.Objec
 	if dto.Notes != "" {
 		set["notes"] = dto.Notes
 	}
+	if dto.Tags != nil {
+		set["tags"] = dto.Tags
+	}
 	if dto.Active != nil {
 		set["active"] = *dto.Active
+		if *dto.Active {
+			set["status"] = PatientStatusActive
+		} else {
+			set["status"] = PatientStatusInactive
+		}
 	}
 
 	filter := bson.M{"_id": oid, "tenant_id": tenantID, "deleted_at": nil}
@@ -279,3 +347,96 @@ func (r *patientRepository) Delete(ctx context.Context, tenantID primitive.Objec
 
 	return nil
 }
+
+func (r *patientRepository) MarkDeceased(ctx context.Context, tenantID primitive.ObjectID, id string, deceasedAt time.Time) (*Patient, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidPatientID
+	}
+
+	filter := bson.M{
+		"_id":        oid,
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+		"status":     bson.M{"$ne": PatientStatusDeceased},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":      PatientStatusDeceased,
+		"active":      false,
+		"deceased_at": deceasedAt,
+		"updated_at":  time.Now(),
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.FindByID(ctx, tenantID, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrPatientDeceased
+	}
+
+	return r.FindByID(ctx, tenantID, id)
+}
+
+// openAppointmentStatuses are the appointment statuses that still expect the
+// patient to come in.
+var openAppointmentStatuses = []string{"scheduled", "confirmed", "awaiting_deposit"}
+
+// CancelFutureAppointments cancels the patient's open appointments from now on
+// and returns how many it cancelled. The appointments module builds on this
+// one, so its collections are written directly here; each cancellation still
+// lands in the appointment's status history.
+func (r *patientRepository) CancelFutureAppointments(ctx context.Context, tenantID, patientID, changedBy primitive.ObjectID, reason string, now time.Time) (int64, error) {
+	cursor, err := r.appointments.Find(ctx, bson.M{
+		"tenant_id":    tenantID,
+		"patient_id":   patientID,
+		"status":       bson.M{"$in": openAppointmentStatuses},
+		"scheduled_at": bson.M{"$gte": now},
+	}, options.Find().SetProjection(bson.M{"_id": 1, "status": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var open []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Status string             `bson:"status"`
+	}
+	if err := cursor.All(ctx, &open); err != nil {
+		return 0, err
+	}
+
+	var cancelled int64
+	for _, a := range open {
+		result, err := r.appointments.UpdateOne(ctx,
+			bson.M{"_id": a.ID, "tenant_id": tenantID, "status": a.Status},
+			bson.M{"$set": bson.M{
+				"status":        "cancelled",
+				"cancelled_at":  now,
+				"cancel_reason": reason,
+				"updated_at":    now,
+			}},
+		)
+		if err != nil {
+			return cancelled, err
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+		cancelled++
+
+		if _, err := r.appointmentTransitions.InsertOne(ctx, bson.M{
+			"tenant_id":      tenantID,
+			"appointment_id": a.ID,
+			"from_status":    a.Status,
+			"to_status":      "cancelled",
+			"changed_by":     changedBy,
+			"reason":         reason,
+			"created_at":     now,
+		}); err != nil {
+			return cancelled, err
+		}
+	}
+	return cancelled, nil
+}
@@ -1,30 +1,41 @@
 package patients
 
 import (
+	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
 )
 
-func newDeps(db *database.MongoDB) (*PatientService, *SpeciesService, owners.OwnerRepository) {
+func newDeps(db *database.MongoDB, qrSecret []byte) (*PatientService, *SpeciesService, *WeightService, owners.OwnerRepository) {
 	patientRepo := NewPatientRepository(db)
 	speciesRepo := NewSpeciesRepository(db)
 	ownerRepo := owners.NewRepository(db)
 	speciesSvc := NewSpeciesService(speciesRepo)
-	patientSvc := NewService(patientRepo, speciesSvc)
-	return patientSvc, speciesSvc, ownerRepo
+	patientSvc := NewService(patientRepo, speciesSvc, ownerRepo, qrSecret)
+	weightSvc := NewWeightService(NewWeightRepository(db), patientRepo)
+	return patientSvc, speciesSvc, weightSvc, ownerRepo
 }
 
 // RegisterAdminRoutes registers admin-panel routes (JWT + RBAC).
-func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
-	patientSvc, speciesSvc, ownerRepo := newDeps(db)
-	h := NewHandler(patientSvc, speciesSvc, ownerRepo)
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, cfg *config.Config) {
+	qrSecret := cfg.PatientQRSecret
+	if qrSecret == "" {
+		qrSecret = cfg.JWTSecret
+	}
+	patientSvc, speciesSvc, weightSvc, ownerRepo := newDeps(db, []byte(qrSecret))
+	h := NewHandler(patientSvc, speciesSvc, weightSvc, ownerRepo)
 
 	p := private.Group("/patients")
 	p.POST("", h.Create)
 	p.GET("", h.FindAll)
+	p.GET("/resolve-qr", h.ResolveQR)
 	p.GET("/:id", h.FindByID)
+	p.GET("/:id/qr", h.QRCode)
 	p.PATCH("/:id", h.Update)
+	p.POST("/:id/mark-deceased", h.MarkDeceased)
+	p.POST("/:id/weight", h.RecordWeight)
+	p.GET("/:id/weight", h.WeightHistory)
 	p.DELETE("/:id", h.Delete)
 
 	s := private.Group("/species")
@@ -34,8 +45,8 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 
 // RegisterMobileRoutes registers mobile routes (JWT + OwnerGuard).
 func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
-	patientSvc, speciesSvc, ownerRepo := newDeps(db)
-	h := NewHandler(patientSvc, speciesSvc, ownerRepo)
+	patientSvc, speciesSvc, weightSvc, ownerRepo := newDeps(db, nil) // QR codes are staff-only
+	h := NewHandler(patientSvc, speciesSvc, weightSvc, ownerRepo)
 
 	mp := mobile.Group("/patients")
 	mp.GET("", h.MobileFindAll)
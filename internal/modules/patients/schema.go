@@ -14,6 +14,16 @@ const (
 	GenderUnknown Gender = "unknown"
 )
 
+// PatientStatus is where a patient is in its lifecycle. Patients stored before
+// it existed have no status; EffectiveStatus derives it from Active.
+type PatientStatus string
+
+const (
+	PatientStatusActive   PatientStatus = "active"
+	PatientStatusInactive PatientStatus = "inactive"
+	PatientStatusDeceased PatientStatus = "deceased"
+)
+
 // Species is a tenant-scoped tag with trigram-based deduplication.
 type Species struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty"`
@@ -41,8 +51,30 @@ type Patient struct {
 	Sterilized bool               `bson:"sterilized"`
 	AvatarURL  string             `bson:"avatar_url,omitempty"`
 	Notes      string             `bson:"notes,omitempty"`
+	Tags       []string           `bson:"tags,omitempty"`
 	Active     bool               `bson:"active"`
-	CreatedAt  time.Time          `bson:"created_at"`
-	UpdatedAt  time.Time          `bson:"updated_at"`
-	DeletedAt  *time.Time         `bson:"deleted_at,omitempty"`
+	Status     PatientStatus      `bson:"status,omitempty"`
+	DeceasedAt *time.Time         `bson:"deceased_at,omitempty"`
+	// AgeRemindersSent keys of the age milestones the owner was already reminded of
+	AgeRemindersSent []string   `bson:"age_reminders_sent,omitempty"`
+	CreatedAt        time.Time  `bson:"created_at"`
+	UpdatedAt        time.Time  `bson:"updated_at"`
+	DeletedAt        *time.Time `bson:"deleted_at,omitempty"`
+}
+
+// EffectiveStatus returns the lifecycle status, falling back to the Active
+// flag for patients stored without one.
+func (p *Patient) EffectiveStatus() PatientStatus {
+	if p.Status != "" {
+		return p.Status
+	}
+	if p.Active {
+		return PatientStatusActive
+	}
+	return PatientStatusInactive
+}
+
+// IsDeceased reports whether the patient has been marked as deceased.
+func (p *Patient) IsDeceased() bool {
+	return p.Status == PatientStatusDeceased
 }
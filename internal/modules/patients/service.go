@@ -2,22 +2,30 @@ package patients
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/tags"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
 type PatientService struct {
 	repo           PatientRepository
 	speciesService *SpeciesService
+	ownerRepo      owners.OwnerRepository
+	qrSecret       []byte
 }
 
-func NewService(repo PatientRepository, speciesService *SpeciesService) *PatientService {
+func NewService(repo PatientRepository, speciesService *SpeciesService, ownerRepo owners.OwnerRepository, qrSecret []byte) *PatientService {
 	return &PatientService{
 		repo:           repo,
 		speciesService: speciesService,
+		ownerRepo:      ownerRepo,
+		qrSecret:       qrSecret,
 	}
 }
 
@@ -36,6 +44,25 @@ func (s *PatientService) Create(ctx context.Context, tenantID primitive.ObjectID
 		return nil, err
 	}
 
+	if !dto.ConfirmCreate {
+		duplicates, err := s.repo.FindPossibleDuplicates(ctx, tenantID, ownerID, speciesID, dto.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) > 0 {
+			ids := make([]string, len(duplicates))
+			for i, d := range duplicates {
+				ids[i] = d.ID.Hex()
+			}
+			return nil, ErrPossibleDuplicate(ids)
+		}
+	}
+
+	tagList, err := tags.Normalize(dto.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	patient := &Patient{
 		ID:         primitive.NewObjectID(),
@@ -52,6 +79,7 @@ func (s *PatientService) Create(ctx context.Context, tenantID primitive.ObjectID
 		Sterilized: dto.Sterilized,
 		AvatarURL:  dto.AvatarURL,
 		Notes:      dto.Notes,
+		Tags:       tagList,
 		Active:     true,
 		CreatedAt:  now,
 		UpdatedAt:  now,
@@ -61,12 +89,18 @@ func (s *PatientService) Create(ctx context.Context, tenantID primitive.ObjectID
 		return nil, err
 	}
 
+	// Registering a pet at a clinic is what associates the owner with it,
+	// making the clinic selectable from the mobile app.
+	if err := s.ownerRepo.AddTenantID(ctx, ownerID.Hex(), tenantID); err != nil {
+		slog.Warn("failed to associate owner with tenant", "owner_id", ownerID.Hex(), "tenant_id", tenantID.Hex(), "error", err)
+	}
+
 	resp := toPatientResponse(patient)
 	return &resp, nil
 }
 
-func (s *PatientService) FindAll(ctx context.Context, tenantID primitive.ObjectID, params pagination.Params) (*PaginatedPatientsResponse, error) {
-	items, total, err := s.repo.FindAll(ctx, tenantID, params)
+func (s *PatientService) FindAll(ctx context.Context, tenantID primitive.ObjectID, filters PatientFilters, params pagination.Params) (*PaginatedPatientsResponse, error) {
+	items, total, err := s.repo.FindAll(ctx, tenantID, filters, params)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +154,24 @@ func (s *PatientService) Update(ctx context.Context, tenantID primitive.ObjectID
 		}
 	}
 
+	if dto.Tags != nil {
+		tagList, err := tags.Normalize(dto.Tags)
+		if err != nil {
+			return nil, err
+		}
+		dto.Tags = tagList
+	}
+
+	if dto.Active != nil {
+		current, err := s.repo.FindByID(ctx, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		if current.IsDeceased() {
+			return nil, ErrDeceasedReactivated
+		}
+	}
+
 	p, err := s.repo.Update(ctx, tenantID, id, dto)
 	if err != nil {
 		return nil, err
@@ -128,6 +180,37 @@ func (s *PatientService) Update(ctx context.Context, tenantID primitive.ObjectID
 	return &resp, nil
 }
 
+// MarkDeceased records a patient's death and winds down what was pending for
+// it: upcoming appointments are cancelled with a note addressed to the owner,
+// and the reminder jobs leave the patient out from then on. No cancellation
+// notices are pushed; the clinic is expected to reach the family personally.
+func (s *PatientService) MarkDeceased(ctx context.Context, tenantID, userID primitive.ObjectID, id string, dto *MarkDeceasedDTO) (*MarkDeceasedResponse, error) {
+	now := time.Now()
+	deceasedAt := now
+	if dto.DeceasedAt != nil {
+		if dto.DeceasedAt.After(now) {
+			return nil, ErrDeceasedInFuture
+		}
+		deceasedAt = *dto.DeceasedAt
+	}
+
+	p, err := s.repo.MarkDeceased(ctx, tenantID, id, deceasedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("Lamentamos profundamente la partida de %s. Hemos cancelado esta cita; no es necesario que hagas nada.", p.Name)
+	cancelled, err := s.repo.CancelFutureAppointments(ctx, tenantID, p.ID, userID, reason, now)
+	if err != nil {
+		slog.Error("failed to cancel appointments of deceased patient", "patient_id", p.ID.Hex(), "cancelled", cancelled, "error", err)
+	}
+
+	return &MarkDeceasedResponse{
+		Patient:               toPatientResponse(p),
+		CancelledAppointments: cancelled,
+	}, nil
+}
+
 func (s *PatientService) Delete(ctx context.Context, tenantID primitive.ObjectID, id string) error {
 	return s.repo.Delete(ctx, tenantID, id)
 }
@@ -0,0 +1,277 @@
+package patients
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Where a weight reading came from
+const (
+	WeightSourceManual        = "manual"
+	WeightSourceAppointment   = "appointment"
+	WeightSourceMedicalRecord = "medical_record"
+)
+
+// Plausible weight bounds in kg, wide enough for a hamster and a horse
+const (
+	MinWeightKg = 0.01
+	MaxWeightKg = 1500
+)
+
+// WeightMeasurement is a quick weight reading taken outside a full medical
+// record, e.g. at check-in for a grooming visit. Stored in weight_measurements.
+type WeightMeasurement struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID   primitive.ObjectID  `bson:"tenant_id"`
+	PatientID  primitive.ObjectID  `bson:"patient_id"`
+	Weight     float64             `bson:"weight"` // kg
+	MeasuredAt time.Time           `bson:"measured_at"`
+	Source     string              `bson:"source"`
+	SourceID   *primitive.ObjectID `bson:"source_id,omitempty"` // appointment or medical record
+	RecordedBy primitive.ObjectID  `bson:"recorded_by,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at"`
+}
+
+// RecordWeightDTO is a weight reading entered by staff
+type RecordWeightDTO struct {
+	Weight     float64    `json:"weight" binding:"required,gt=0,max=1500" example:"12.4"`
+	MeasuredAt *time.Time `json:"measured_at,omitempty"` // defaults to now
+}
+
+// WeightMeasurementResponse is one point of a patient's weight history
+type WeightMeasurementResponse struct {
+	ID         string    `json:"id,omitempty"`
+	Weight     float64   `json:"weight"`
+	MeasuredAt time.Time `json:"measured_at"`
+	Source     string    `json:"source"`
+	SourceID   string    `json:"source_id,omitempty"`
+}
+
+// WeightHistoryResponse is a patient's weight over time, oldest first
+type WeightHistoryResponse struct {
+	PatientID    string                      `json:"patient_id"`
+	Measurements []WeightMeasurementResponse `json:"measurements"`
+}
+
+func toWeightResponse(m *WeightMeasurement) WeightMeasurementResponse {
+	resp := WeightMeasurementResponse{
+		Weight:     m.Weight,
+		MeasuredAt: m.MeasuredAt,
+		Source:     m.Source,
+	}
+	if !m.ID.IsZero() {
+		resp.ID = m.ID.Hex()
+	}
+	if m.SourceID != nil {
+		resp.SourceID = m.SourceID.Hex()
+	}
+	return resp
+}
+
+// WeightRepository stores weight readings. History also reads the weights
+// written on medical records, so the trend covers every visit.
+type WeightRepository interface {
+	Create(ctx context.Context, m *WeightMeasurement) error
+	Latest(ctx context.Context, tenantID, patientID primitive.ObjectID) (*WeightMeasurement, error)
+	History(ctx context.Context, tenantID, patientID primitive.ObjectID, from *time.Time) ([]WeightMeasurement, error)
+}
+
+type weightRepository struct {
+	collection     *mongo.Collection
+	medicalRecords *mongo.Collection
+}
+
+func NewWeightRepository(db *database.MongoDB) WeightRepository {
+	return &weightRepository{
+		collection:     db.Collection("weight_measurements"),
+		medicalRecords: db.Collection("medical_records"),
+	}
+}
+
+func (r *weightRepository) Create(ctx context.Context, m *WeightMeasurement) error {
+	_, err := r.collection.InsertOne(ctx, m)
+	return err
+}
+
+// Latest returns the most recent reading, or nil when the patient has none
+func (r *weightRepository) Latest(ctx context.Context, tenantID, patientID primitive.ObjectID) (*WeightMeasurement, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "measured_at", Value: -1}})
+
+	var m WeightMeasurement
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenantID, "patient_id": patientID}, opts).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *weightRepository) History(ctx context.Context, tenantID, patientID primitive.ObjectID, from *time.Time) ([]WeightMeasurement, error) {
+	filter := bson.M{"tenant_id": tenantID, "patient_id": patientID}
+	if from != nil {
+		filter["measured_at"] = bson.M{"$gte": *from}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var readings []WeightMeasurement
+	if err := cursor.All(ctx, &readings); err != nil {
+		return nil, err
+	}
+
+	recordFilter := bson.M{
+		"tenant_id":  tenantID,
+		"patient_id": patientID,
+		"weight":     bson.M{"$gt": 0},
+		"deleted_at": nil,
+	}
+	if from != nil {
+		recordFilter["created_at"] = bson.M{"$gte": *from}
+	}
+	opts := options.Find().SetProjection(bson.M{"weight": 1, "created_at": 1})
+
+	cursor, err = r.medicalRecords.Find(ctx, recordFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var records []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		Weight    float64            `bson:"weight"`
+		CreatedAt time.Time          `bson:"created_at"`
+	}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range records {
+		recordID := rec.ID
+		readings = append(readings, WeightMeasurement{
+			TenantID:   tenantID,
+			PatientID:  patientID,
+			Weight:     rec.Weight,
+			MeasuredAt: rec.CreatedAt,
+			Source:     WeightSourceMedicalRecord,
+			SourceID:   &recordID,
+		})
+	}
+
+	sort.Slice(readings, func(i, j int) bool { return readings[i].MeasuredAt.Before(readings[j].MeasuredAt) })
+	return readings, nil
+}
+
+// WeightService records quick weight readings and serves the weight trend
+type WeightService struct {
+	repo        WeightRepository
+	patientRepo PatientRepository
+}
+
+func NewWeightService(repo WeightRepository, patientRepo PatientRepository) *WeightService {
+	return &WeightService{repo: repo, patientRepo: patientRepo}
+}
+
+// Record stores a reading entered by staff on the patient's page
+func (s *WeightService) Record(ctx context.Context, tenantID, userID primitive.ObjectID, id string, dto *RecordWeightDTO) (*WeightMeasurementResponse, error) {
+	patient, err := s.patientRepo.FindByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	measuredAt := time.Now()
+	if dto.MeasuredAt != nil {
+		if dto.MeasuredAt.After(measuredAt) {
+			return nil, ErrMeasuredAtInFuture
+		}
+		measuredAt = *dto.MeasuredAt
+	}
+
+	m := &WeightMeasurement{
+		TenantID:   tenantID,
+		PatientID:  patient.ID,
+		Weight:     dto.Weight,
+		MeasuredAt: measuredAt,
+		Source:     WeightSourceManual,
+		RecordedBy: userID,
+	}
+	if err := s.record(ctx, m); err != nil {
+		return nil, err
+	}
+
+	resp := toWeightResponse(m)
+	return &resp, nil
+}
+
+// RecordFromAppointment stores the reading taken at check-in or when the
+// appointment was completed
+func (s *WeightService) RecordFromAppointment(ctx context.Context, tenantID, patientID, appointmentID, userID primitive.ObjectID, weight float64) error {
+	return s.record(ctx, &WeightMeasurement{
+		TenantID:   tenantID,
+		PatientID:  patientID,
+		Weight:     weight,
+		MeasuredAt: time.Now(),
+		Source:     WeightSourceAppointment,
+		SourceID:   &appointmentID,
+		RecordedBy: userID,
+	})
+}
+
+// History returns the patient's weight trend, oldest first, from quick
+// readings and medical records alike
+func (s *WeightService) History(ctx context.Context, tenantID primitive.ObjectID, id string, from *time.Time) (*WeightHistoryResponse, error) {
+	patient, err := s.patientRepo.FindByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	readings, err := s.repo.History(ctx, tenantID, patient.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &WeightHistoryResponse{
+		PatientID:    patient.ID.Hex(),
+		Measurements: make([]WeightMeasurementResponse, len(readings)),
+	}
+	for i := range readings {
+		resp.Measurements[i] = toWeightResponse(&readings[i])
+	}
+	return resp, nil
+}
+
+// record validates and stores a reading, and keeps the patient's current
+// weight in step when the reading is the newest one
+func (s *WeightService) record(ctx context.Context, m *WeightMeasurement) error {
+	if m.Weight < MinWeightKg || m.Weight > MaxWeightKg {
+		return ErrImplausibleWeight
+	}
+
+	latest, err := s.repo.Latest(ctx, m.TenantID, m.PatientID)
+	if err != nil {
+		return err
+	}
+
+	m.ID = primitive.NewObjectID()
+	m.CreatedAt = time.Now()
+	if err := s.repo.Create(ctx, m); err != nil {
+		return err
+	}
+
+	if latest == nil || !m.MeasuredAt.Before(latest.MeasuredAt) {
+		if _, err := s.patientRepo.Update(ctx, m.TenantID, m.PatientID.Hex(), &UpdatePatientDTO{Weight: m.Weight}); err != nil {
+			slog.Warn("failed to update patient weight", "patient_id", m.PatientID.Hex(), "error", err)
+		}
+	}
+	return nil
+}
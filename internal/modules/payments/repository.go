@@ -16,6 +16,7 @@ type PaymentRepository interface {
 	Create(ctx context.Context, payment *Payment) error
 	FindByID(ctx context.Context, id string) (*Payment, error)
 	FindByTenantID(ctx context.Context, tenantID string, limit int) ([]Payment, error)
+	FindByExternalTransactionID(ctx context.Context, transactionID string, status PaymentStatus) (*Payment, error)
 	UpdateStatus(ctx context.Context, id string, status PaymentStatus, processedAt *time.Time, failureReason string) error
 }
 
@@ -74,6 +75,19 @@ func (r *paymentRepository) FindByTenantID(ctx context.Context, tenantID string,
 	return payments, nil
 }
 
+// FindByExternalTransactionID finds the payment recorded for a provider transaction in a given status
+func (r *paymentRepository) FindByExternalTransactionID(ctx context.Context, transactionID string, status PaymentStatus) (*Payment, error) {
+	var payment Payment
+	err := r.collection.FindOne(ctx, bson.M{"external_transaction_id": transactionID, "status": status}).Decode(&payment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
 func (r *paymentRepository) UpdateStatus(ctx context.Context, id string, status PaymentStatus, processedAt *time.Time, failureReason string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
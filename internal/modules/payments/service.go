@@ -80,6 +80,15 @@ func (s *PaymentService) FindByTenantID(ctx context.Context, tenantID string, li
 	return ToResponseList(payments), nil
 }
 
+// FindByExternalTransactionID finds the payment recorded for a provider transaction in a given status
+func (s *PaymentService) FindByExternalTransactionID(ctx context.Context, transactionID string, status PaymentStatus) (*PaymentResponse, error) {
+	payment, err := s.repo.FindByExternalTransactionID(ctx, transactionID, status)
+	if err != nil {
+		return nil, err
+	}
+	return ToResponse(payment), nil
+}
+
 func (s *PaymentService) UpdateStatus(ctx context.Context, id string, status PaymentStatus, processedAt *time.Time, failureReason string) error {
 	return s.repo.UpdateStatus(ctx, id, status, processedAt, failureReason)
 }
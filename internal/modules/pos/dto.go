@@ -0,0 +1,66 @@
+package pos
+
+import (
+	"time"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// QuickSaleDTO represents a counter sale paid on the spot
+type QuickSaleDTO struct {
+	Items         []SaleItemDTO `json:"items" binding:"required,min=1,max=50,dive"`
+	PaymentMethod string        `json:"payment_method" binding:"required,oneof=cash card transfer" example:"cash"`
+	// TransactionID is the card terminal or bank reference, when there is one
+	TransactionID string `json:"transaction_id,omitempty" binding:"max=100" example:"POS-889231"`
+	Notes         string `json:"notes,omitempty" binding:"max=500"`
+}
+
+// SaleItemDTO is a product and how many units are sold
+type SaleItemDTO struct {
+	ProductID string `json:"product_id" binding:"required" example:"507f1f77bcf86cd799439011"`
+	Quantity  int    `json:"quantity" binding:"required,min=1,max=1000" example:"2"`
+}
+
+// SaleListFilters represents filters for listing sales
+type SaleListFilters struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// SaleResponse represents a sale, which doubles as its receipt, in API responses
+type SaleResponse struct {
+	ID        string              `json:"id"`
+	Number    string              `json:"number"`
+	Items     []SaleItemResponse  `json:"items"`
+	Currency  string              `json:"currency"`
+	Total     money.Amount        `json:"total"`
+	Payment   SalePaymentResponse `json:"payment"`
+	Notes     string              `json:"notes,omitempty"`
+	SoldBy    string              `json:"sold_by"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// SaleItemResponse is a sold product line
+type SaleItemResponse struct {
+	ProductID  string       `json:"product_id"`
+	Name       string       `json:"name"`
+	SKU        string       `json:"sku"`
+	Quantity   int          `json:"quantity"`
+	UnitPrice  money.Amount `json:"unit_price"`
+	Total      money.Amount `json:"total"`
+	MovementID string       `json:"movement_id,omitempty"`
+}
+
+// SalePaymentResponse is the payment taken for a sale
+type SalePaymentResponse struct {
+	Method        string       `json:"method"`
+	Amount        money.Amount `json:"amount"`
+	TransactionID string       `json:"transaction_id,omitempty"`
+	ReceivedAt    time.Time    `json:"received_at"`
+}
+
+// Receipt is a rendered sale receipt
+type Receipt struct {
+	Filename string
+	Data     []byte
+}
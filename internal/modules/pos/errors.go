@@ -0,0 +1,19 @@
+package pos
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrSaleNotFound       = sharedErrors.New(sharedErrors.ErrNotFound, "SALE_NOT_FOUND", "sale not found")
+	ErrProductInactive    = sharedErrors.New(sharedErrors.ErrUnprocessable, "PRODUCT_INACTIVE", "inactive products cannot be sold")
+	ErrProductNotPriced   = sharedErrors.New(sharedErrors.ErrUnprocessable, "PRODUCT_NOT_PRICED", "product has no sale price")
+	ErrMixedCurrencies    = sharedErrors.New(sharedErrors.ErrUnprocessable, "SALE_MIXED_CURRENCIES", "all products of a sale must be priced in the same currency")
+	ErrPaymentNotRecorded = sharedErrors.New(sharedErrors.ErrInternal, "SALE_PAYMENT_FAILED", "the payment could not be recorded, stock was restored")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
@@ -0,0 +1,143 @@
+package pos
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for counter sales
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new point-of-sale handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// CreateSale records a counter sale
+// @Summary Quick sale
+// @Description Sell products over the counter without an invoice. Stock of every line is taken with reason "sale", the total is computed from each product's sale price and the payment is recorded with the sale. If a product is short of stock or the payment cannot be recorded, no stock is taken
+// @Tags pos
+// @Accept json
+// @Produce json
+// @Param sale body QuickSaleDTO true "Sale data"
+// @Success 200 {object} SaleResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/pos/sale [post]
+func (h *Handler) CreateSale(c *gin.Context) (any, error) {
+	var dto QuickSaleDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	sale, err := h.service.CreateSale(c.Request.Context(), &dto, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return sale.ToResponse(), nil
+}
+
+// ListSales lists counter sales
+// @Summary List sales
+// @Description List the clinic's counter sales, newest first
+// @Tags pos
+// @Produce json
+// @Param date_from query string false "Filter from date (RFC3339)"
+// @Param date_to query string false "Filter to date (RFC3339)"
+// @Param skip query int false "Items to skip" default(0)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/pos/sales [get]
+func (h *Handler) ListSales(c *gin.Context) (any, error) {
+	params := pagination.FromContext(c)
+
+	var filters SaleListFilters
+	if raw := c.Query("date_from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, ErrValidation("date_from", "invalid date format, use RFC3339")
+		}
+		filters.From = &from
+	}
+	if raw := c.Query("date_to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, ErrValidation("date_to", "invalid date format, use RFC3339")
+		}
+		filters.To = &to
+	}
+
+	sales, total, err := h.service.ListSales(c.Request.Context(), filters, sharedMiddleware.GetTenantID(c), params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]SaleResponse, len(sales))
+	for i, sale := range sales {
+		data[i] = *sale.ToResponse()
+	}
+
+	return gin.H{
+		"data":       data,
+		"pagination": pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+// GetSale gets a counter sale
+// @Summary Get sale
+// @Description Get a counter sale by ID
+// @Tags pos
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Success 200 {object} SaleResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/pos/sales/{id} [get]
+func (h *Handler) GetSale(c *gin.Context) (any, error) {
+	sale, err := h.service.GetSale(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return sale.ToResponse(), nil
+}
+
+// GetReceipt renders the receipt of a counter sale
+// @Summary Get sale receipt
+// @Description Printable PDF receipt of a counter sale
+// @Tags pos
+// @Produce application/pdf
+// @Param id path string true "Sale ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/pos/sales/{id}/receipt.pdf [get]
+func (h *Handler) GetReceipt(c *gin.Context) (any, error) {
+	receipt, err := h.service.GenerateReceipt(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return &httpx.File{ContentType: "application/pdf", Filename: receipt.Filename, Data: receipt.Data}, nil
+}
@@ -0,0 +1,27 @@
+package pos
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the pos_sales collection
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("pos_sales").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Listing the clinic's sales, newest first
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			// Receipt numbers are unique per clinic
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	return err
+}
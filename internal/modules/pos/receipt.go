@@ -0,0 +1,73 @@
+package pos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/platform/pdf"
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+var paymentMethodLabels = map[PaymentMethod]string{
+	PaymentMethodCash:     "Efectivo",
+	PaymentMethodCard:     "Tarjeta",
+	PaymentMethodTransfer: "Transferencia",
+}
+
+// GenerateReceipt renders a sale as a printable PDF receipt with the clinic's
+// details, the sold lines, the total and how it was paid
+func (s *Service) GenerateReceipt(ctx context.Context, id string, tenantID primitive.ObjectID) (*Receipt, error) {
+	sale, err := s.GetSale(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+
+	if s.tenantRepo != nil {
+		if t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex()); err == nil {
+			name := t.CommercialName
+			if name == "" {
+				name = t.Name
+			}
+			doc.Title(name)
+			doc.Field("NIT", t.IdentificationNumber)
+			doc.Text(strings.Join(nonEmpty(t.Address, t.Phone, t.Email), " · "))
+			doc.Space()
+		}
+	}
+
+	doc.Heading("Recibo de venta")
+	doc.Field("Número", sale.Number)
+	doc.Field("Fecha", sale.CreatedAt.Format("02/01/2006 15:04"))
+
+	doc.Heading("Productos")
+	for _, item := range sale.Items {
+		doc.Text(fmt.Sprintf("%d x %s (%s) a %s: %s", item.Quantity, item.Name, item.SKU,
+			money.Format(item.UnitPrice, sale.Currency), money.Format(item.Total, sale.Currency)))
+	}
+
+	doc.Space()
+	doc.Field("Total", money.Format(sale.Total, sale.Currency))
+	doc.Field("Medio de pago", paymentMethodLabels[sale.Payment.Method])
+	doc.Field("Referencia", sale.Payment.TransactionID)
+	doc.Field("Notas", sale.Notes)
+
+	return &Receipt{
+		Filename: fmt.Sprintf("recibo-%s.pdf", sale.Number),
+		Data:     doc.Bytes(),
+	}, nil
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package pos
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// Repository defines the interface for sale data access
+type Repository interface {
+	Create(ctx context.Context, sale *Sale) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Sale, error)
+	List(ctx context.Context, filters SaleListFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Sale, int64, error)
+}
+
+type repository struct {
+	base *database.BaseRepository[Sale]
+}
+
+// NewRepository creates a new sale repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		base: database.NewBaseRepository[Sale](db.Collection("pos_sales"), ErrSaleNotFound),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, sale *Sale) error {
+	result, err := r.base.Collection.InsertOne(ctx, sale)
+	if err != nil {
+		return err
+	}
+	sale.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Sale, error) {
+	return r.base.FindByID(ctx, id, tenantID)
+}
+
+func (r *repository) List(ctx context.Context, filters SaleListFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Sale, int64, error) {
+	filter := bson.M{}
+	if filters.From != nil || filters.To != nil {
+		created := bson.M{}
+		if filters.From != nil {
+			created["$gte"] = *filters.From
+		}
+		if filters.To != nil {
+			created["$lt"] = *filters.To
+		}
+		filter["created_at"] = created
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	sales, total, err := r.base.Page(ctx, tenantID, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sales == nil {
+		sales = []Sale{}
+	}
+	return sales, total, nil
+}
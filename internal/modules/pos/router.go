@@ -0,0 +1,26 @@
+package pos
+
+import (
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/pos
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	service := NewService(
+		NewRepository(db),
+		inventory.NewProductRepository(db),
+		sequences.NewService(sequences.NewRepository(db)),
+		tenant.NewTenantRepository(db),
+	)
+	handler := NewHandler(service)
+
+	pos := private.Group("/pos")
+	pos.POST("/sale", handler.CreateSale)
+	pos.GET("/sales", handler.ListSales)
+	pos.GET("/sales/:id", handler.GetSale)
+	pos.GET("/sales/:id/receipt.pdf", handler.GetReceipt)
+}
@@ -0,0 +1,92 @@
+package pos
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
+)
+
+// PaymentMethod is how a counter sale was paid
+type PaymentMethod string
+
+const (
+	PaymentMethodCash     PaymentMethod = "cash"
+	PaymentMethodCard     PaymentMethod = "card"
+	PaymentMethodTransfer PaymentMethod = "transfer"
+)
+
+// SaleItem is one product line of a sale, priced at the product's sale price
+// when it was sold. Amounts are minor units of the sale's currency.
+type SaleItem struct {
+	ProductID  primitive.ObjectID `bson:"product_id"`
+	Name       string             `bson:"name"`
+	SKU        string             `bson:"sku"`
+	Quantity   int                `bson:"quantity"`
+	UnitPrice  int64              `bson:"unit_price"`
+	Total      int64              `bson:"total"`
+	MovementID primitive.ObjectID `bson:"movement_id,omitempty"`
+}
+
+// SalePayment is the payment taken at the counter for a sale
+type SalePayment struct {
+	Method        PaymentMethod `bson:"method"`
+	Amount        int64         `bson:"amount"`
+	TransactionID string        `bson:"transaction_id,omitempty"`
+	ReceivedAt    time.Time     `bson:"received_at"`
+}
+
+// Sale is an over-the-counter product sale (food, toys, accessories) paid on
+// the spot, without an invoice. Stock leaves through "sale" movements that
+// point back to it.
+type Sale struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID primitive.ObjectID `bson:"tenant_id"`
+	// Number is the consecutive receipt number
+	Number   string      `bson:"number"`
+	Items    []SaleItem  `bson:"items"`
+	Currency string      `bson:"currency"`
+	Total    int64       `bson:"total"`
+	Payment  SalePayment `bson:"payment"`
+	Notes    string      `bson:"notes,omitempty"`
+
+	SoldBy    primitive.ObjectID `bson:"sold_by"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// ToResponse converts a sale to its API representation
+func (s *Sale) ToResponse() *SaleResponse {
+	resp := &SaleResponse{
+		ID:       s.ID.Hex(),
+		Number:   s.Number,
+		Items:    make([]SaleItemResponse, len(s.Items)),
+		Currency: s.Currency,
+		Total:    money.New(s.Total, s.Currency),
+		Payment: SalePaymentResponse{
+			Method:        string(s.Payment.Method),
+			Amount:        money.New(s.Payment.Amount, s.Currency),
+			TransactionID: s.Payment.TransactionID,
+			ReceivedAt:    s.Payment.ReceivedAt,
+		},
+		Notes:     s.Notes,
+		SoldBy:    s.SoldBy.Hex(),
+		CreatedAt: s.CreatedAt,
+	}
+	for i, item := range s.Items {
+		resp.Items[i] = SaleItemResponse{
+			ProductID: item.ProductID.Hex(),
+			Name:      item.Name,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: money.New(item.UnitPrice, s.Currency),
+			Total:     money.New(item.Total, s.Currency),
+		}
+		if !item.MovementID.IsZero() {
+			resp.Items[i].MovementID = item.MovementID.Hex()
+		}
+	}
+	return resp
+}
@@ -0,0 +1,208 @@
+package pos
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/shared/money"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// ProductStore is the part of the inventory repository a sale needs
+type ProductStore interface {
+	FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*inventory.Product, error)
+	DecrementStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) (int, int, error)
+	UpdateStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) error
+	CreateStockMovement(ctx context.Context, movement *inventory.StockMovement) error
+}
+
+// NumberIssuer draws consecutive document numbers, implemented by sequences.Service
+type NumberIssuer interface {
+	Next(ctx context.Context, tenantID primitive.ObjectID, name, format string, at time.Time) (string, error)
+}
+
+// TenantReader loads the clinic details printed on receipts
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+// Service provides business logic for counter sales
+type Service struct {
+	repo       Repository
+	products   ProductStore
+	numbers    NumberIssuer
+	tenantRepo TenantReader
+}
+
+// NewService creates a new point-of-sale service
+func NewService(repo Repository, products ProductStore, numbers NumberIssuer, tenantRepo TenantReader) *Service {
+	return &Service{
+		repo:       repo,
+		products:   products,
+		numbers:    numbers,
+		tenantRepo: tenantRepo,
+	}
+}
+
+// soldLine is a sale line whose stock has already been taken
+type soldLine struct {
+	item        *SaleItem
+	stockBefore int
+	stockAfter  int
+}
+
+// CreateSale sells products over the counter: it takes the stock of every
+// line, records the payment with the sale and writes a "sale" stock movement
+// per line. Stock is taken first so two clerks cannot sell the last unit
+// twice; if a line is short or the payment cannot be recorded, the stock
+// already taken is put back and nothing is sold.
+func (s *Service) CreateSale(ctx context.Context, dto *QuickSaleDTO, tenantID, userID primitive.ObjectID) (*Sale, error) {
+	items, currency, total, err := s.priceItems(ctx, dto.Items, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sold := make([]soldLine, 0, len(items))
+	for i := range items {
+		before, after, err := s.products.DecrementStock(ctx, items[i].ProductID, items[i].Quantity, tenantID)
+		if err != nil {
+			s.restoreStock(ctx, sold, tenantID)
+			return nil, err
+		}
+		sold = append(sold, soldLine{item: &items[i], stockBefore: before, stockAfter: after})
+	}
+
+	now := time.Now()
+	number, err := s.numbers.Next(ctx, tenantID, sequences.Receipt, "", now)
+	if err != nil {
+		s.restoreStock(ctx, sold, tenantID)
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].MovementID = primitive.NewObjectID()
+	}
+	sale := &Sale{
+		ID:       primitive.NewObjectID(),
+		TenantID: tenantID,
+		Number:   number,
+		Items:    items,
+		Currency: currency,
+		Total:    total,
+		Payment: SalePayment{
+			Method:        PaymentMethod(dto.PaymentMethod),
+			Amount:        total,
+			TransactionID: dto.TransactionID,
+			ReceivedAt:    now,
+		},
+		Notes:     dto.Notes,
+		SoldBy:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, sale); err != nil {
+		slog.Error("failed to record counter sale payment", "tenant_id", tenantID.Hex(), "error", err)
+		s.restoreStock(ctx, sold, tenantID)
+		return nil, ErrPaymentNotRecorded
+	}
+
+	// The sale is paid; a movement that fails to save is logged rather than
+	// undoing a sale the customer has already walked out with
+	for _, line := range sold {
+		movement := &inventory.StockMovement{
+			ID:          line.item.MovementID,
+			TenantID:    tenantID,
+			ProductID:   line.item.ProductID,
+			Type:        inventory.StockMovementOut,
+			Reason:      inventory.StockReasonSale,
+			Quantity:    line.item.Quantity,
+			StockBefore: line.stockBefore,
+			StockAfter:  line.stockAfter,
+			ReferenceID: sale.ID,
+			UserID:      userID,
+			Notes:       "Venta de mostrador " + number,
+			CreatedAt:   now,
+		}
+		if err := s.products.CreateStockMovement(ctx, movement); err != nil {
+			slog.Error("failed to record stock movement for counter sale", "sale_id", sale.ID.Hex(), "product_id", line.item.ProductID.Hex(), "error", err)
+		}
+	}
+
+	return sale, nil
+}
+
+// priceItems loads the products of a sale, merging repeated products into one
+// line, and prices them at their current sale price
+func (s *Service) priceItems(ctx context.Context, lines []SaleItemDTO, tenantID primitive.ObjectID) ([]SaleItem, string, int64, error) {
+	items := make([]SaleItem, 0, len(lines))
+	index := make(map[primitive.ObjectID]int, len(lines))
+	for _, line := range lines {
+		productID, err := primitive.ObjectIDFromHex(line.ProductID)
+		if err != nil {
+			return nil, "", 0, ErrValidation("items", "invalid product ID format: "+line.ProductID)
+		}
+		if i, ok := index[productID]; ok {
+			items[i].Quantity += line.Quantity
+			continue
+		}
+		index[productID] = len(items)
+		items = append(items, SaleItem{ProductID: productID, Quantity: line.Quantity})
+	}
+
+	var currency string
+	var total int64
+	for i := range items {
+		product, err := s.products.FindByID(ctx, items[i].ProductID, tenantID)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		if !product.Active {
+			return nil, "", 0, ErrProductInactive
+		}
+		if product.SalePrice <= 0 {
+			return nil, "", 0, ErrProductNotPriced
+		}
+		productCurrency := money.Normalize(product.Currency)
+		if currency == "" {
+			currency = productCurrency
+		} else if productCurrency != currency {
+			return nil, "", 0, ErrMixedCurrencies
+		}
+
+		items[i].Name = product.Name
+		items[i].SKU = product.SKU
+		items[i].UnitPrice = product.SalePrice
+		items[i].Total = product.SalePrice * int64(items[i].Quantity)
+		total += items[i].Total
+	}
+	return items, currency, total, nil
+}
+
+// restoreStock puts back the stock taken for a sale that did not go through
+func (s *Service) restoreStock(ctx context.Context, sold []soldLine, tenantID primitive.ObjectID) {
+	for _, line := range sold {
+		if err := s.products.UpdateStock(ctx, line.item.ProductID, line.item.Quantity, tenantID); err != nil {
+			slog.Error("failed to restore stock of a cancelled counter sale", "product_id", line.item.ProductID.Hex(), "quantity", line.item.Quantity, "error", err)
+		}
+	}
+}
+
+// GetSale returns a sale by ID
+func (s *Service) GetSale(ctx context.Context, id string, tenantID primitive.ObjectID) (*Sale, error) {
+	saleID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid sale ID format")
+	}
+	return s.repo.FindByID(ctx, saleID, tenantID)
+}
+
+// ListSales lists the clinic's sales, newest first
+func (s *Service) ListSales(ctx context.Context, filters SaleListFilters, tenantID primitive.ObjectID, params pagination.Params) ([]Sale, int64, error) {
+	return s.repo.List(ctx, filters, tenantID, params)
+}
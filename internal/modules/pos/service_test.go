@@ -0,0 +1,166 @@
+package pos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+)
+
+var (
+	testTenantID = primitive.NewObjectID()
+	testUserID   = primitive.NewObjectID()
+	foodID       = primitive.NewObjectID()
+	toyID        = primitive.NewObjectID()
+)
+
+// mockProducts keeps products and their stock in memory
+type mockProducts struct {
+	products  map[primitive.ObjectID]*inventory.Product
+	movements []*inventory.StockMovement
+}
+
+func newMockProducts() *mockProducts {
+	return &mockProducts{products: map[primitive.ObjectID]*inventory.Product{
+		foodID: {ID: foodID, Name: "Alimento 2kg", SKU: "ALI-2", SalePrice: 4500000, Currency: "COP", Stock: 5, Active: true},
+		toyID:  {ID: toyID, Name: "Pelota", SKU: "JUG-1", SalePrice: 1250050, Currency: "COP", Stock: 1, Active: true},
+	}}
+}
+
+func (m *mockProducts) FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*inventory.Product, error) {
+	p, ok := m.products[id]
+	if !ok {
+		return nil, inventory.ErrProductNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (m *mockProducts) DecrementStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) (int, int, error) {
+	p := m.products[id]
+	if p.Stock < quantity {
+		return 0, 0, inventory.ErrInsufficientStock
+	}
+	p.Stock -= quantity
+	return p.Stock + quantity, p.Stock, nil
+}
+
+func (m *mockProducts) UpdateStock(ctx context.Context, id primitive.ObjectID, quantity int, tenantID primitive.ObjectID) error {
+	m.products[id].Stock += quantity
+	return nil
+}
+
+func (m *mockProducts) CreateStockMovement(ctx context.Context, movement *inventory.StockMovement) error {
+	m.movements = append(m.movements, movement)
+	return nil
+}
+
+// mockSales is a sale repository whose Create can be made to fail. The
+// embedded interface panics on any method a test did not expect.
+type mockSales struct {
+	Repository
+	createErr error
+	created   []*Sale
+}
+
+func (m *mockSales) Create(ctx context.Context, sale *Sale) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.created = append(m.created, sale)
+	return nil
+}
+
+type mockNumbers struct{}
+
+func (mockNumbers) Next(ctx context.Context, tenantID primitive.ObjectID, name, format string, at time.Time) (string, error) {
+	return "REC-2026-000001", nil
+}
+
+func newTestService(products *mockProducts, sales *mockSales) *Service {
+	return NewService(sales, products, mockNumbers{}, nil)
+}
+
+func TestCreateSale_TakesStockAndRecordsPayment(t *testing.T) {
+	products, sales := newMockProducts(), &mockSales{}
+	svc := newTestService(products, sales)
+
+	sale, err := svc.CreateSale(context.Background(), &QuickSaleDTO{
+		Items: []SaleItemDTO{
+			{ProductID: foodID.Hex(), Quantity: 1},
+			{ProductID: toyID.Hex(), Quantity: 1},
+			{ProductID: foodID.Hex(), Quantity: 1},
+		},
+		PaymentMethod: "cash",
+	}, testTenantID, testUserID)
+	require.NoError(t, err)
+
+	// Repeated products are merged into one line
+	require.Len(t, sale.Items, 2)
+	assert.Equal(t, 2, sale.Items[0].Quantity)
+	assert.Equal(t, int64(2*4500000+1250050), sale.Total)
+	assert.Equal(t, sale.Total, sale.Payment.Amount)
+	assert.Equal(t, PaymentMethodCash, sale.Payment.Method)
+
+	assert.Equal(t, 3, products.products[foodID].Stock)
+	assert.Equal(t, 0, products.products[toyID].Stock)
+	require.Len(t, products.movements, 2)
+	for i, m := range products.movements {
+		assert.Equal(t, inventory.StockReasonSale, m.Reason)
+		assert.Equal(t, sale.ID, m.ReferenceID)
+		assert.Equal(t, sale.Items[i].MovementID, m.ID)
+	}
+}
+
+func TestCreateSale_InsufficientStockRestoresEarlierLines(t *testing.T) {
+	products, sales := newMockProducts(), &mockSales{}
+	svc := newTestService(products, sales)
+
+	_, err := svc.CreateSale(context.Background(), &QuickSaleDTO{
+		Items: []SaleItemDTO{
+			{ProductID: foodID.Hex(), Quantity: 2},
+			{ProductID: toyID.Hex(), Quantity: 3},
+		},
+		PaymentMethod: "card",
+	}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+
+	assert.Equal(t, 5, products.products[foodID].Stock)
+	assert.Equal(t, 1, products.products[toyID].Stock)
+	assert.Empty(t, products.movements)
+	assert.Empty(t, sales.created)
+}
+
+func TestCreateSale_PaymentFailureRestoresStock(t *testing.T) {
+	products, sales := newMockProducts(), &mockSales{createErr: errors.New("write failed")}
+	svc := newTestService(products, sales)
+
+	_, err := svc.CreateSale(context.Background(), &QuickSaleDTO{
+		Items:         []SaleItemDTO{{ProductID: foodID.Hex(), Quantity: 2}, {ProductID: toyID.Hex(), Quantity: 1}},
+		PaymentMethod: "transfer",
+	}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrPaymentNotRecorded)
+
+	assert.Equal(t, 5, products.products[foodID].Stock)
+	assert.Equal(t, 1, products.products[toyID].Stock)
+	assert.Empty(t, products.movements)
+}
+
+func TestCreateSale_RejectsInactiveProduct(t *testing.T) {
+	products, sales := newMockProducts(), &mockSales{}
+	products.products[toyID].Active = false
+	svc := newTestService(products, sales)
+
+	_, err := svc.CreateSale(context.Background(), &QuickSaleDTO{
+		Items:         []SaleItemDTO{{ProductID: foodID.Hex(), Quantity: 1}, {ProductID: toyID.Hex(), Quantity: 1}},
+		PaymentMethod: "cash",
+	}, testTenantID, testUserID)
+	assert.ErrorIs(t, err, ErrProductInactive)
+	assert.Equal(t, 5, products.products[foodID].Stock)
+}
@@ -0,0 +1,77 @@
+package reports
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// NoShowQuery holds the filters of the no-show report
+type NoShowQuery struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	// LateCancelWindow counts cancellations made less than this long before
+	// the appointment as no-shows. Earlier cancellations are ignored.
+	LateCancelWindow time.Duration
+	// Owners at or above both thresholds are flagged
+	FlagMinCount int
+	FlagMinRate  float64
+}
+
+// OwnerNoShowResponse is one row of the no-show report
+type OwnerNoShowResponse struct {
+	OwnerID    string `json:"owner_id"`
+	OwnerName  string `json:"owner_name,omitempty"`
+	OwnerEmail string `json:"owner_email,omitempty"`
+	OwnerPhone string `json:"owner_phone,omitempty"`
+	// TotalAppointments every appointment booked in the period, for context
+	TotalAppointments int `json:"total_appointments" example:"12"`
+	// AdvanceCancellations cancelled ahead of the late-cancel window; not counted against the owner
+	AdvanceCancellations int `json:"advance_cancellations" example:"2"`
+	NoShows              int `json:"no_shows" example:"3"`
+	LateCancellations    int `json:"late_cancellations" example:"1"`
+	// NoShowCount no-shows plus late cancellations
+	NoShowCount int `json:"no_show_count" example:"4"`
+	// NoShowRate NoShowCount over the appointments the owner was expected to attend
+	NoShowRate float64 `json:"no_show_rate" example:"0.4"`
+	Flagged    bool    `json:"flagged" example:"true"`
+}
+
+// NoShowReportResponse is the paginated no-show report
+type NoShowReportResponse struct {
+	DateFrom        time.Time                 `json:"date_from"`
+	DateTo          time.Time                 `json:"date_to"`
+	LateCancelHours int                       `json:"late_cancel_hours" example:"24"`
+	FlagMinCount    int                       `json:"flag_min_count" example:"3"`
+	FlagMinRate     float64                   `json:"flag_min_rate" example:"0.3"`
+	Data            []OwnerNoShowResponse     `json:"data"`
+	Pagination      pagination.PaginationInfo `json:"pagination"`
+}
+
+// CoverageQuery holds the filters of the vaccination coverage report
+type CoverageQuery struct {
+	Vaccine   string
+	SpeciesID *primitive.ObjectID
+	AsOf      time.Time
+}
+
+// CoverageBucket is one slice of the patient population
+type CoverageBucket struct {
+	Count int `json:"count" example:"120"`
+	// Percentage of the active patients in the report, 0 to 100
+	Percentage float64 `json:"percentage" example:"64.5"`
+}
+
+// VaccinationCoverageResponse splits the clinic's active patients by the state
+// of their latest dose of a vaccine
+type VaccinationCoverageResponse struct {
+	Vaccine         string         `json:"vaccine" example:"Rabia"`
+	SpeciesID       string         `json:"species_id,omitempty"`
+	AsOf            time.Time      `json:"as_of"`
+	ActivePatients  int            `json:"active_patients" example:"186"`
+	UpToDate        CoverageBucket `json:"up_to_date"`
+	Overdue         CoverageBucket `json:"overdue"`
+	NeverVaccinated CoverageBucket `json:"never_vaccinated"`
+}
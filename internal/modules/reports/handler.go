@@ -0,0 +1,120 @@
+package reports
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// Handler handles HTTP requests for reports
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new reports handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// NoShows returns the owner no-show report
+// @Summary Owner no-show report
+// @Description Rank owners by no-shows in the period (default: last 90 days). Cancellations made less than late_cancel_hours before the appointment count as no-shows; earlier ones are ignored. Owners with at least flag_min_count no-shows and a rate of at least flag_min_rate are flagged
+// @Tags reports
+// @Produce json
+// @Param date_from query string false "Period start (RFC3339)"
+// @Param date_to query string false "Period end (RFC3339, default now)"
+// @Param late_cancel_hours query int false "Late cancellation window in hours, 0 to ignore cancellations (default 24)"
+// @Param flag_min_count query int false "Minimum no-shows to flag an owner (default 3)"
+// @Param flag_min_rate query number false "Minimum no-show rate between 0 and 1 to flag an owner (default 0.3)"
+// @Param skip query int false "Number of records to skip"
+// @Param limit query int false "Number of records to return"
+// @Success 200 {object} NoShowReportResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/reports/no-shows [get]
+func (h *Handler) NoShows(c *gin.Context) (any, error) {
+	q := NoShowQuery{
+		DateTo:           time.Now(),
+		LateCancelWindow: DefaultLateCancelWindow,
+		FlagMinCount:     DefaultFlagMinCount,
+		FlagMinRate:      DefaultFlagMinRate,
+	}
+
+	if v := c.Query("date_to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, ErrValidation("date_to", "invalid date format, expected RFC3339")
+		}
+		q.DateTo = t
+	}
+	q.DateFrom = q.DateTo.Add(-DefaultNoShowPeriod)
+	if v := c.Query("date_from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, ErrValidation("date_from", "invalid date format, expected RFC3339")
+		}
+		q.DateFrom = t
+	}
+
+	if v := c.Query("late_cancel_hours"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil || hours < 0 || hours > 168 {
+			return nil, ErrValidation("late_cancel_hours", "must be between 0 and 168")
+		}
+		q.LateCancelWindow = time.Duration(hours) * time.Hour
+	}
+	if v := c.Query("flag_min_count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, ErrValidation("flag_min_count", "must be a positive integer")
+		}
+		q.FlagMinCount = n
+	}
+	if v := c.Query("flag_min_rate"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, ErrValidation("flag_min_rate", "must be between 0 and 1")
+		}
+		q.FlagMinRate = rate
+	}
+
+	return h.service.NoShows(c.Request.Context(), sharedMiddleware.GetTenantID(c), q, pagination.FromContext(c))
+}
+
+// VaccinationCoverage returns the vaccine coverage of the clinic's patients
+// @Summary Vaccination coverage report
+// @Description Split the clinic's active patients, optionally of one species, into up to date, overdue and never vaccinated according to their latest dose of the vaccine. Overdue owners can be messaged with a broadcast to the vaccine_overdue audience
+// @Tags reports
+// @Produce json
+// @Param vaccine query string true "Vaccine name (case-insensitive)"
+// @Param species query string false "Species ID"
+// @Success 200 {object} VaccinationCoverageResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/reports/vaccination-coverage [get]
+func (h *Handler) VaccinationCoverage(c *gin.Context) (any, error) {
+	q := CoverageQuery{
+		Vaccine: strings.TrimSpace(c.Query("vaccine")),
+		AsOf:    time.Now(),
+	}
+	if q.Vaccine == "" {
+		return nil, ErrValidation("vaccine", "vaccine is required")
+	}
+	if v := c.Query("species"); v != "" {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return nil, ErrValidation("species", "invalid species ID format")
+		}
+		q.SpeciesID = &id
+	}
+
+	return h.service.VaccinationCoverage(c.Request.Context(), sharedMiddleware.GetTenantID(c), q)
+}
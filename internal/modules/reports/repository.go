@@ -0,0 +1,201 @@
+package reports
+
+import (
+	"context"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// OwnerNoShowRow is the per-owner aggregate behind the no-show report
+type OwnerNoShowRow struct {
+	OwnerID              primitive.ObjectID `bson:"_id"`
+	OwnerName            string             `bson:"owner_name"`
+	OwnerEmail           string             `bson:"owner_email"`
+	OwnerPhone           string             `bson:"owner_phone"`
+	Total                int                `bson:"total"`
+	AdvanceCancellations int                `bson:"advance_cancellations"`
+	NoShows              int                `bson:"no_shows"`
+	LateCancellations    int                `bson:"late_cancellations"`
+	NoShowCount          int                `bson:"no_show_count"`
+	NoShowRate           float64            `bson:"no_show_rate"`
+}
+
+// CoverageRow is the aggregate behind the vaccination coverage report
+type CoverageRow struct {
+	Total   int `bson:"total"`
+	Never   int `bson:"never"`
+	Overdue int `bson:"overdue"`
+}
+
+// Repository runs the read-only aggregations behind the reports
+type Repository interface {
+	OwnerNoShows(ctx context.Context, tenantID primitive.ObjectID, q NoShowQuery, params pagination.Params) ([]OwnerNoShowRow, int64, error)
+	VaccinationCoverage(ctx context.Context, tenantID primitive.ObjectID, q CoverageQuery) (*CoverageRow, error)
+}
+
+type repository struct {
+	appointments *mongo.Collection
+	patients     *mongo.Collection
+}
+
+// NewRepository creates a new reports repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		appointments: db.Collection("appointments"),
+		patients:     db.Collection("patients"),
+	}
+}
+
+// OwnerNoShows groups the tenant's appointments in the period by owner and
+// returns the owners with at least one no-show, worst first. A cancellation
+// is late when cancelled_at falls inside the window before scheduled_at.
+func (r *repository) OwnerNoShows(ctx context.Context, tenantID primitive.ObjectID, q NoShowQuery, params pagination.Params) ([]OwnerNoShowRow, int64, error) {
+	cancelled := bson.M{"$eq": bson.A{"$status", appointments.AppointmentStatusCancelled}}
+	lateCancel := bson.M{"$and": bson.A{
+		cancelled,
+		bson.M{"$gt": bson.A{q.LateCancelWindow.Milliseconds(), 0}},
+		bson.M{"$gte": bson.A{
+			bson.M{"$ifNull": bson.A{"$cancelled_at", "$updated_at"}},
+			bson.M{"$subtract": bson.A{"$scheduled_at", q.LateCancelWindow.Milliseconds()}},
+		}},
+	}}
+	countIf := func(cond any) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{cond, 1, 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenant_id":    tenantID,
+			"deleted_at":   nil,
+			"scheduled_at": bson.M{"$gte": q.DateFrom, "$lt": q.DateTo},
+		}}},
+		{{Key: "$addFields", Value: bson.M{"late_cancel": lateCancel}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                   "$owner_id",
+			"total":                 bson.M{"$sum": 1},
+			"no_shows":              countIf(bson.M{"$eq": bson.A{"$status", appointments.AppointmentStatusNoShow}}),
+			"late_cancellations":    countIf("$late_cancel"),
+			"advance_cancellations": countIf(bson.M{"$and": bson.A{cancelled, bson.M{"$not": bson.A{"$late_cancel"}}}}),
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"no_show_count": bson.M{"$add": bson.A{"$no_shows", "$late_cancellations"}},
+		}}},
+		{{Key: "$match", Value: bson.M{"no_show_count": bson.M{"$gt": 0}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"no_show_rate": bson.M{"$divide": bson.A{
+				"$no_show_count",
+				bson.M{"$max": bson.A{bson.M{"$subtract": bson.A{"$total", "$advance_cancellations"}}, 1}},
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "no_show_count", Value: -1}, {Key: "no_show_rate", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"rows": bson.A{
+				bson.M{"$skip": params.Skip},
+				bson.M{"$limit": params.Limit},
+				bson.M{"$lookup": bson.M{
+					"from":         "owners",
+					"localField":   "_id",
+					"foreignField": "_id",
+					"as":           "owner",
+				}},
+				bson.M{"$addFields": bson.M{
+					"owner_name":  bson.M{"$first": "$owner.name"},
+					"owner_email": bson.M{"$first": "$owner.email"},
+					"owner_phone": bson.M{"$first": "$owner.phone"},
+				}},
+				bson.M{"$project": bson.M{"owner": 0}},
+			},
+		}}},
+	}
+
+	cursor, err := r.appointments.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Rows []OwnerNoShowRow `bson:"rows"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, 0, err
+	}
+	if len(result) == 0 || len(result[0].Total) == 0 {
+		return []OwnerNoShowRow{}, 0, nil
+	}
+
+	return result[0].Rows, result[0].Total[0].Count, nil
+}
+
+// VaccinationCoverage joins each active patient with its latest dose of the
+// vaccine (matched by name, case-insensitive) and counts the patients never
+// vaccinated and those whose latest dose is past its next due date.
+func (r *repository) VaccinationCoverage(ctx context.Context, tenantID primitive.ObjectID, q CoverageQuery) (*CoverageRow, error) {
+	match := bson.M{
+		"tenant_id":  tenantID,
+		"active":     true,
+		"deleted_at": nil,
+	}
+	if q.SpeciesID != nil {
+		match["species_id"] = *q.SpeciesID
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "vaccinations",
+			"let":  bson.M{"patient_id": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"tenant_id":    tenantID,
+					"deleted_at":   nil,
+					"vaccine_name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(q.Vaccine) + "$", Options: "i"},
+					"$expr":        bson.M{"$eq": bson.A{"$patient_id", "$$patient_id"}},
+				}},
+				bson.M{"$sort": bson.M{"application_date": -1}},
+				bson.M{"$limit": 1},
+				bson.M{"$project": bson.M{"next_due_date": 1}},
+			},
+			"as": "latest",
+		}}},
+		{{Key: "$addFields", Value: bson.M{"latest": bson.M{"$first": "$latest"}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": 1},
+			"never": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{bson.M{"$type": "$latest"}, "missing"}}, 1, 0}}},
+			"overdue": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{"$latest.next_due_date", nil}}, nil}},
+					bson.M{"$lt": bson.A{"$latest.next_due_date", q.AsOf}},
+				}},
+				1, 0,
+			}}},
+		}}},
+	}
+
+	cursor, err := r.patients.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []CoverageRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &CoverageRow{}, nil
+	}
+	return &rows[0], nil
+}
@@ -0,0 +1,15 @@
+package reports
+
+import (
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/reports
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db)))
+
+	r := private.Group("/reports")
+	r.GET("/no-shows", handler.NoShows)
+	r.GET("/vaccination-coverage", handler.VaccinationCoverage)
+}
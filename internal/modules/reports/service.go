@@ -0,0 +1,100 @@
+package reports
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+// Report defaults
+const (
+	DefaultNoShowPeriod     = 90 * 24 * time.Hour
+	DefaultLateCancelWindow = 24 * time.Hour
+	DefaultFlagMinCount     = 3
+	DefaultFlagMinRate      = 0.3
+)
+
+// Service provides the clinic's business reports
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new reports service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// NoShows ranks the clinic's owners by no-shows in the period and flags those
+// above the thresholds, so staff can ask them for a deposit.
+func (s *Service) NoShows(ctx context.Context, tenantID primitive.ObjectID, q NoShowQuery, params pagination.Params) (*NoShowReportResponse, error) {
+	if !q.DateFrom.Before(q.DateTo) {
+		return nil, ErrValidation("date_to", "date_to must be after date_from")
+	}
+
+	rows, total, err := s.repo.OwnerNoShows(ctx, tenantID, q, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]OwnerNoShowResponse, len(rows))
+	for i, row := range rows {
+		data[i] = OwnerNoShowResponse{
+			OwnerID:              row.OwnerID.Hex(),
+			OwnerName:            row.OwnerName,
+			OwnerEmail:           row.OwnerEmail,
+			OwnerPhone:           row.OwnerPhone,
+			TotalAppointments:    row.Total,
+			AdvanceCancellations: row.AdvanceCancellations,
+			NoShows:              row.NoShows,
+			LateCancellations:    row.LateCancellations,
+			NoShowCount:          row.NoShowCount,
+			NoShowRate:           row.NoShowRate,
+			Flagged:              row.NoShowCount >= q.FlagMinCount && row.NoShowRate >= q.FlagMinRate,
+		}
+	}
+
+	return &NoShowReportResponse{
+		DateFrom:        q.DateFrom,
+		DateTo:          q.DateTo,
+		LateCancelHours: int(q.LateCancelWindow / time.Hour),
+		FlagMinCount:    q.FlagMinCount,
+		FlagMinRate:     q.FlagMinRate,
+		Data:            data,
+		Pagination:      pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+// VaccinationCoverage reports how many of the clinic's active patients are up
+// to date, overdue or never vaccinated against a vaccine. A latest dose with
+// no next due date counts as up to date.
+func (s *Service) VaccinationCoverage(ctx context.Context, tenantID primitive.ObjectID, q CoverageQuery) (*VaccinationCoverageResponse, error) {
+	row, err := s.repo.VaccinationCoverage(ctx, tenantID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &VaccinationCoverageResponse{
+		Vaccine:         q.Vaccine,
+		AsOf:            q.AsOf,
+		ActivePatients:  row.Total,
+		UpToDate:        coverageBucket(row.Total-row.Never-row.Overdue, row.Total),
+		Overdue:         coverageBucket(row.Overdue, row.Total),
+		NeverVaccinated: coverageBucket(row.Never, row.Total),
+	}
+	if q.SpeciesID != nil {
+		resp.SpeciesID = q.SpeciesID.Hex()
+	}
+	return resp, nil
+}
+
+func coverageBucket(count, total int) CoverageBucket {
+	b := CoverageBucket{Count: count}
+	if total > 0 {
+		b.Percentage = math.Round(float64(count)*1000/float64(total)) / 10
+	}
+	return b
+}
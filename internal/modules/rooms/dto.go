@@ -0,0 +1,29 @@
+package rooms
+
+import "time"
+
+// CreateRoomDTO represents the request to add a room
+type CreateRoomDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=100" example:"Quirófano 1"`
+	Kind     string `json:"kind" binding:"required,max=50" example:"surgery"`
+	Capacity int    `json:"capacity" binding:"omitempty,min=1,max=50" example:"1"`
+}
+
+// UpdateRoomDTO represents the request to update a room
+type UpdateRoomDTO struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=2,max=100" example:"Quirófano 1"`
+	Kind     *string `json:"kind,omitempty" binding:"omitempty,max=50" example:"surgery"`
+	Capacity *int    `json:"capacity,omitempty" binding:"omitempty,min=1,max=50" example:"1"`
+	Active   *bool   `json:"active,omitempty" example:"true"`
+}
+
+// RoomResponse represents a room in API responses
+type RoomResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Capacity  int       `json:"capacity"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
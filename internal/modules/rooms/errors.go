@@ -0,0 +1,16 @@
+package rooms
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrRoomNotFound = sharedErrors.New(sharedErrors.ErrNotFound, "ROOM_NOT_FOUND", "room not found")
+	ErrRoomExists   = sharedErrors.New(sharedErrors.ErrConflict, "ROOM_EXISTS", "a room with this name already exists")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
@@ -0,0 +1,127 @@
+package rooms
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for clinic rooms
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new room handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create adds a room
+// @Summary Create room
+// @Description Add a bookable room or piece of equipment. Capacity is how many appointments it holds at the same time (1 by default)
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Param room body CreateRoomDTO true "Room data"
+// @Success 200 {object} RoomResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/rooms [post]
+func (h *Handler) Create(c *gin.Context) (any, error) {
+	var dto CreateRoomDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	room, err := h.service.Create(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return room.ToResponse(), nil
+}
+
+// List lists rooms
+// @Summary List rooms
+// @Description List the clinic's rooms and equipment ordered by name
+// @Tags rooms
+// @Produce json
+// @Success 200 {array} RoomResponse
+// @Security BearerAuth
+// @Router /api/rooms [get]
+func (h *Handler) List(c *gin.Context) (any, error) {
+	rooms, err := h.service.List(c.Request.Context(), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]RoomResponse, len(rooms))
+	for i, room := range rooms {
+		data[i] = room.ToResponse()
+	}
+	return data, nil
+}
+
+// Get gets a room
+// @Summary Get room
+// @Description Get a room by ID
+// @Tags rooms
+// @Produce json
+// @Param id path string true "Room ID"
+// @Success 200 {object} RoomResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/rooms/{id} [get]
+func (h *Handler) Get(c *gin.Context) (any, error) {
+	room, err := h.service.Get(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return room.ToResponse(), nil
+}
+
+// Update updates a room
+// @Summary Update room
+// @Description Update the name, kind, capacity or active flag of a room. Inactive rooms cannot be booked
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Param id path string true "Room ID"
+// @Param room body UpdateRoomDTO true "Fields to update"
+// @Success 200 {object} RoomResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/rooms/{id} [put]
+func (h *Handler) Update(c *gin.Context) (any, error) {
+	var dto UpdateRoomDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	room, err := h.service.Update(c.Request.Context(), c.Param("id"), &dto, sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return room.ToResponse(), nil
+}
+
+// Delete deletes a room
+// @Summary Delete room
+// @Description Remove a room. Appointments that reserved it keep the reference
+// @Tags rooms
+// @Param id path string true "Room ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/rooms/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) (any, error) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c)); err != nil {
+		return nil, err
+	}
+	return gin.H{"message": "Room deleted successfully"}, nil
+}
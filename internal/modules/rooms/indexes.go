@@ -0,0 +1,25 @@
+package rooms
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates required indexes for the rooms collection
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection("rooms").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Room names are unique among the clinic's live rooms
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"deleted_at": bson.M{"$exists": false},
+			}),
+		},
+	})
+	return err
+}
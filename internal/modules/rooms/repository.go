@@ -0,0 +1,67 @@
+package rooms
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Repository defines the interface for room data access
+type Repository interface {
+	Create(ctx context.Context, room *Room) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Room, error)
+	FindAll(ctx context.Context, tenantID primitive.ObjectID) ([]Room, error)
+	Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error
+	Delete(ctx context.Context, id, tenantID primitive.ObjectID) error
+}
+
+type repository struct {
+	base *database.BaseRepository[Room]
+}
+
+// NewRepository creates a new room repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		base: database.NewBaseRepository[Room](db.Collection("rooms"), ErrRoomNotFound).WithDuplicate(ErrRoomExists),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, room *Room) error {
+	result, err := r.base.Collection.InsertOne(ctx, room)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrRoomExists
+		}
+		return err
+	}
+	room.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Room, error) {
+	return r.base.FindByID(ctx, id, tenantID)
+}
+
+func (r *repository) FindAll(ctx context.Context, tenantID primitive.ObjectID) ([]Room, error) {
+	results, err := r.base.List(ctx, tenantID, nil, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []Room{}
+	}
+	return results, nil
+}
+
+func (r *repository) Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error {
+	return r.base.Update(ctx, id, tenantID, updates)
+}
+
+func (r *repository) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	return r.base.SoftDelete(ctx, id, tenantID)
+}
@@ -0,0 +1,18 @@
+package rooms
+
+import (
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/rooms
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db)))
+
+	r := private.Group("/rooms")
+	r.POST("", handler.Create)
+	r.GET("", handler.List)
+	r.GET("/:id", handler.Get)
+	r.PUT("/:id", handler.Update)
+	r.DELETE("/:id", handler.Delete)
+}
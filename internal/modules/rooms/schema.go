@@ -0,0 +1,35 @@
+package rooms
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Room is a bookable clinic room or piece of equipment, such as an operating
+// room or an X-ray machine. Capacity is how many appointments it can hold at
+// the same time.
+type Room struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  primitive.ObjectID `bson:"tenant_id"`
+	Name      string             `bson:"name"`
+	Kind      string             `bson:"kind"`
+	Capacity  int                `bson:"capacity"`
+	Active    bool               `bson:"active"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// ToResponse converts a room to its API representation
+func (r *Room) ToResponse() RoomResponse {
+	return RoomResponse{
+		ID:        r.ID.Hex(),
+		Name:      r.Name,
+		Kind:      r.Kind,
+		Capacity:  r.Capacity,
+		Active:    r.Active,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
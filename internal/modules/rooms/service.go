@@ -0,0 +1,100 @@
+package rooms
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultCapacity is how many appointments a room holds at once unless configured
+const DefaultCapacity = 1
+
+// Service provides business logic for the clinic's rooms and equipment
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new room service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create adds a room
+func (s *Service) Create(ctx context.Context, dto *CreateRoomDTO, tenantID primitive.ObjectID) (*Room, error) {
+	capacity := dto.Capacity
+	if capacity == 0 {
+		capacity = DefaultCapacity
+	}
+
+	now := time.Now()
+	room := &Room{
+		TenantID:  tenantID,
+		Name:      dto.Name,
+		Kind:      dto.Kind,
+		Capacity:  capacity,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// List returns the clinic's rooms ordered by name
+func (s *Service) List(ctx context.Context, tenantID primitive.ObjectID) ([]Room, error) {
+	return s.repo.FindAll(ctx, tenantID)
+}
+
+// Get returns a room by ID
+func (s *Service) Get(ctx context.Context, id string, tenantID primitive.ObjectID) (*Room, error) {
+	roomID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid room ID format")
+	}
+	return s.repo.FindByID(ctx, roomID, tenantID)
+}
+
+// FindByID returns a room by ID, for other modules booking it
+func (s *Service) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Room, error) {
+	return s.repo.FindByID(ctx, id, tenantID)
+}
+
+// Update changes a room. Lowering the capacity does not affect appointments
+// already booked; it only applies to new bookings.
+func (s *Service) Update(ctx context.Context, id string, dto *UpdateRoomDTO, tenantID primitive.ObjectID) (*Room, error) {
+	room, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := bson.M{}
+	if dto.Name != nil {
+		updates["name"] = *dto.Name
+	}
+	if dto.Kind != nil {
+		updates["kind"] = *dto.Kind
+	}
+	if dto.Capacity != nil {
+		updates["capacity"] = *dto.Capacity
+	}
+	if dto.Active != nil {
+		updates["active"] = *dto.Active
+	}
+	if err := s.repo.Update(ctx, room.ID, tenantID, updates); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, room.ID, tenantID)
+}
+
+// Delete removes a room. Appointments that reserved it keep the reference.
+func (s *Service) Delete(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	roomID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrValidation("id", "invalid room ID format")
+	}
+	return s.repo.Delete(ctx, roomID, tenantID)
+}
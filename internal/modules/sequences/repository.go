@@ -0,0 +1,59 @@
+package sequences
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Counter is the last number handed out for a clinic's sequence
+type Counter struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  primitive.ObjectID `bson:"tenant_id"`
+	Key       string             `bson:"key"`
+	Value     int64              `bson:"value"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// Repository hands out sequence numbers
+type Repository interface {
+	Next(ctx context.Context, tenantID primitive.ObjectID, key string) (int64, error)
+}
+
+type repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates a new sequence counter repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{collection: db.Collection("sequence_counters")}
+}
+
+// Next increments the counter atomically and returns its new value, creating
+// it at 1 on first use. Two requests creating the same counter at once race
+// on the upsert; the unique index rejects the loser, which then increments
+// the counter the winner created.
+func (r *repository) Next(ctx context.Context, tenantID primitive.ObjectID, key string) (int64, error) {
+	filter := bson.M{"tenant_id": tenantID, "key": key}
+	update := bson.M{
+		"$inc": bson.M{"value": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter Counter
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counter.Value, nil
+}
@@ -0,0 +1,106 @@
+package sequences
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Documents numbered from a sequence
+const (
+	Invoice     = "invoice"
+	Certificate = "certificate"
+	Receipt     = "receipt"
+)
+
+// Formats used when the clinic does not configure one
+const (
+	DefaultInvoiceFormat     = "FAC-{yyyy}-{seq:6}"
+	DefaultCertificateFormat = "VAC-{seq:6}"
+	DefaultReceiptFormat     = "REC-{yyyy}-{seq:6}"
+)
+
+// maxPadding bounds {seq:N}, far above any realistic count of documents
+const maxPadding = 12
+
+// ErrInvalidFormat is returned for formats without exactly one {seq} token or
+// with an unknown token
+var ErrInvalidFormat = errors.New("invalid number format")
+
+var tokenPattern = regexp.MustCompile(`\{([a-z]+)(?::(\d+))?\}`)
+
+// DefaultFormat returns the built-in format for a sequence
+func DefaultFormat(name string) string {
+	switch name {
+	case Certificate:
+		return DefaultCertificateFormat
+	case Receipt:
+		return DefaultReceiptFormat
+	}
+	return DefaultInvoiceFormat
+}
+
+// ValidateFormat checks a number format. Besides literal text it accepts
+// {seq} or {seq:N} (zero-padded to N digits) once, and {yyyy}, {yy} and {mm}.
+func ValidateFormat(format string) error {
+	seqs := 0
+	for _, m := range tokenPattern.FindAllStringSubmatch(format, -1) {
+		switch m[1] {
+		case "seq":
+			seqs++
+			if m[2] != "" {
+				if n, _ := strconv.Atoi(m[2]); n < 1 || n > maxPadding {
+					return ErrInvalidFormat
+				}
+			}
+		case "yyyy", "yy", "mm":
+			if m[2] != "" {
+				return ErrInvalidFormat
+			}
+		default:
+			return ErrInvalidFormat
+		}
+	}
+	if seqs != 1 {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+// Format renders seq with the format's tokens filled in for at
+func Format(format string, seq int64, at time.Time) string {
+	return tokenPattern.ReplaceAllStringFunc(format, func(token string) string {
+		m := tokenPattern.FindStringSubmatch(token)
+		switch m[1] {
+		case "seq":
+			if m[2] == "" {
+				return strconv.FormatInt(seq, 10)
+			}
+			n, _ := strconv.Atoi(m[2])
+			return fmt.Sprintf("%0*d", n, seq)
+		case "yyyy":
+			return at.Format("2006")
+		case "yy":
+			return at.Format("06")
+		case "mm":
+			return at.Format("01")
+		}
+		return token
+	})
+}
+
+// counterKey names the counter a number is drawn from. Formats showing the
+// month or the year restart their count every month or year, so numbers stay
+// consecutive within the period the reader sees.
+func counterKey(name, format string, at time.Time) string {
+	switch {
+	case strings.Contains(format, "{mm}"):
+		return name + ":" + at.Format("2006-01")
+	case strings.Contains(format, "{yyyy}"), strings.Contains(format, "{yy}"):
+		return name + ":" + at.Format("2006")
+	}
+	return name
+}
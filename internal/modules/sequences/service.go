@@ -0,0 +1,33 @@
+package sequences
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Service numbers documents from per-clinic sequences
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new sequence service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Next takes the next number of the clinic's sequence and renders it with
+// format, or with the sequence's default format when empty. A number taken
+// and then not stored leaves a gap, so callers should draw it as the last
+// step before saving.
+func (s *Service) Next(ctx context.Context, tenantID primitive.ObjectID, name, format string, at time.Time) (string, error) {
+	if format == "" {
+		format = DefaultFormat(name)
+	}
+	seq, err := s.repo.Next(ctx, tenantID, counterKey(name, format, at))
+	if err != nil {
+		return "", err
+	}
+	return Format(format, seq, at), nil
+}
@@ -0,0 +1,72 @@
+package shifts
+
+import "time"
+
+// CreateShiftDTO represents the request to add a shift to the roster
+type CreateShiftDTO struct {
+	UserID  string    `json:"user_id" binding:"required" example:"507f1f77bcf86cd799439012"`
+	StartAt time.Time `json:"start_at" binding:"required" example:"2025-06-02T08:00:00Z"`
+	EndAt   time.Time `json:"end_at" binding:"required" example:"2025-06-02T16:00:00Z"`
+	Role    string    `json:"role" binding:"required,max=50" example:"veterinarian"`
+	Notes   string    `json:"notes,omitempty" binding:"max=500" example:"Guardia de urgencias"`
+}
+
+// UpdateShiftDTO represents the request to change a shift
+type UpdateShiftDTO struct {
+	UserID  *string    `json:"user_id,omitempty" example:"507f1f77bcf86cd799439012"`
+	StartAt *time.Time `json:"start_at,omitempty" example:"2025-06-02T08:00:00Z"`
+	EndAt   *time.Time `json:"end_at,omitempty" example:"2025-06-02T16:00:00Z"`
+	Role    *string    `json:"role,omitempty" binding:"omitempty,max=50" example:"veterinarian"`
+	Notes   *string    `json:"notes,omitempty" binding:"omitempty,max=500"`
+}
+
+// PublishRosterDTO publishes every draft shift starting in the period
+type PublishRosterDTO struct {
+	DateFrom time.Time `json:"date_from" binding:"required" example:"2025-06-02T00:00:00Z"`
+	DateTo   time.Time `json:"date_to" binding:"required" example:"2025-06-09T00:00:00Z"`
+}
+
+// RosterQuery filters the roster view
+type RosterQuery struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	UserID   *string
+	Status   string
+}
+
+// ShiftWarning flags a problem that does not block saving the shift
+type ShiftWarning struct {
+	Code           string `json:"code" example:"OVERLAPPING_SHIFT"`
+	Message        string `json:"message"`
+	OtherShiftID   string `json:"other_shift_id,omitempty"`
+	OverlapMinutes int    `json:"overlap_minutes,omitempty" example:"60"`
+}
+
+// ShiftResponse represents a shift in API responses
+type ShiftResponse struct {
+	ID          string         `json:"id"`
+	UserID      string         `json:"user_id"`
+	UserName    string         `json:"user_name,omitempty"`
+	StartAt     time.Time      `json:"start_at"`
+	EndAt       time.Time      `json:"end_at"`
+	Role        string         `json:"role"`
+	Notes       string         `json:"notes,omitempty"`
+	Status      string         `json:"status" example:"draft"`
+	PublishedAt *time.Time     `json:"published_at,omitempty"`
+	Warnings    []ShiftWarning `json:"warnings,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// RosterResponse is the roster of the requested period ordered by start
+type RosterResponse struct {
+	DateFrom time.Time       `json:"date_from"`
+	DateTo   time.Time       `json:"date_to"`
+	Shifts   []ShiftResponse `json:"shifts"`
+}
+
+// PublishRosterResponse summarizes a roster publication
+type PublishRosterResponse struct {
+	Published     int `json:"published" example:"12"`
+	StaffNotified int `json:"staff_notified" example:"4"`
+}
@@ -0,0 +1,16 @@
+package shifts
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrShiftNotFound = sharedErrors.New(sharedErrors.ErrNotFound, "SHIFT_NOT_FOUND", "shift not found")
+	ErrStaffNotFound = sharedErrors.New(sharedErrors.ErrNotFound, "STAFF_NOT_FOUND", "staff member not found in this clinic")
+)
+
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
+}
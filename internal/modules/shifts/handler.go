@@ -0,0 +1,161 @@
+package shifts
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for the staff roster
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new shift handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create adds a shift
+// @Summary Create shift
+// @Description Add a draft shift to the roster. Overlaps with other shifts of the same staff member are saved and returned in warnings
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Param shift body CreateShiftDTO true "Shift data"
+// @Success 200 {object} ShiftResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/shifts [post]
+func (h *Handler) Create(c *gin.Context) (any, error) {
+	var dto CreateShiftDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	createdBy, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+
+	return h.service.Create(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c), createdBy)
+}
+
+// Roster lists the roster of a period
+// @Summary Get roster
+// @Description List the shifts overlapping the period ordered by start, flagging overlapping shifts of the same staff member
+// @Tags shifts
+// @Produce json
+// @Param date_from query string true "Period start (RFC3339)"
+// @Param date_to query string true "Period end (RFC3339)"
+// @Param user_id query string false "Only shifts of this staff member"
+// @Param status query string false "draft or published"
+// @Success 200 {object} RosterResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/shifts [get]
+func (h *Handler) Roster(c *gin.Context) (any, error) {
+	var q RosterQuery
+	var err error
+	if q.DateFrom, err = time.Parse(time.RFC3339, c.Query("date_from")); err != nil {
+		return nil, ErrValidation("date_from", "required, expected RFC3339")
+	}
+	if q.DateTo, err = time.Parse(time.RFC3339, c.Query("date_to")); err != nil {
+		return nil, ErrValidation("date_to", "required, expected RFC3339")
+	}
+	if v := c.Query("user_id"); v != "" {
+		q.UserID = &v
+	}
+	if v := c.Query("status"); v != "" {
+		if v != ShiftStatusDraft && v != ShiftStatusPublished {
+			return nil, ErrValidation("status", "must be draft or published")
+		}
+		q.Status = v
+	}
+
+	return h.service.Roster(c.Request.Context(), q, sharedMiddleware.GetTenantID(c))
+}
+
+// Get gets a shift
+// @Summary Get shift
+// @Description Get a shift by ID
+// @Tags shifts
+// @Produce json
+// @Param id path string true "Shift ID"
+// @Success 200 {object} ShiftResponse
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/shifts/{id} [get]
+func (h *Handler) Get(c *gin.Context) (any, error) {
+	shift, err := h.service.Get(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c))
+	if err != nil {
+		return nil, err
+	}
+	return shift.ToResponse(), nil
+}
+
+// Update updates a shift
+// @Summary Update shift
+// @Description Update a shift. Published shifts stay published
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Param id path string true "Shift ID"
+// @Param shift body UpdateShiftDTO true "Fields to update"
+// @Success 200 {object} ShiftResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/shifts/{id} [put]
+func (h *Handler) Update(c *gin.Context) (any, error) {
+	var dto UpdateShiftDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Update(c.Request.Context(), c.Param("id"), &dto, sharedMiddleware.GetTenantID(c))
+}
+
+// Delete deletes a shift
+// @Summary Delete shift
+// @Description Remove a shift from the roster
+// @Tags shifts
+// @Param id path string true "Shift ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/shifts/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) (any, error) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), sharedMiddleware.GetTenantID(c)); err != nil {
+		return nil, err
+	}
+	return gin.H{"message": "Shift deleted successfully"}, nil
+}
+
+// Publish publishes the roster of a period
+// @Summary Publish roster
+// @Description Publish the draft shifts starting in the period and notify each staff member of their upcoming shifts. Once a day has published shifts, appointments that day can only be booked with veterinarians on duty
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Param period body PublishRosterDTO true "Period to publish"
+// @Success 200 {object} PublishRosterResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/shifts/publish [post]
+func (h *Handler) Publish(c *gin.Context) (any, error) {
+	var dto PublishRosterDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Publish(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c))
+}
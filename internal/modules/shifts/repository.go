@@ -0,0 +1,142 @@
+package shifts
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// shiftFilter selects shifts overlapping [From, To). UserID and Status are
+// optional.
+type shiftFilter struct {
+	From      time.Time
+	To        time.Time
+	UserID    *primitive.ObjectID
+	Status    string
+	ExcludeID *primitive.ObjectID
+}
+
+// Repository defines the interface for shift data access
+type Repository interface {
+	Create(ctx context.Context, s *Shift) error
+	FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Shift, error)
+	Find(ctx context.Context, tenantID primitive.ObjectID, f shiftFilter) ([]Shift, error)
+	Update(ctx context.Context, s *Shift) error
+	Delete(ctx context.Context, id, tenantID primitive.ObjectID) error
+	// Publish marks the given draft shifts as published
+	Publish(ctx context.Context, ids []primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error
+}
+
+type repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates a new shift repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		collection: db.Collection("shifts"),
+	}
+}
+
+func (r *repository) Create(ctx context.Context, s *Shift) error {
+	result, err := r.collection.InsertOne(ctx, s)
+	if err != nil {
+		return err
+	}
+	s.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*Shift, error) {
+	var s Shift
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil}).Decode(&s)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrShiftNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *repository) Find(ctx context.Context, tenantID primitive.ObjectID, f shiftFilter) ([]Shift, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+		"start_at":   bson.M{"$lt": f.To},
+		"end_at":     bson.M{"$gt": f.From},
+	}
+	if f.UserID != nil {
+		filter["user_id"] = *f.UserID
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.ExcludeID != nil {
+		filter["_id"] = bson.M{"$ne": *f.ExcludeID}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_at", Value: 1}, {Key: "user_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []Shift{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *repository) Update(ctx context.Context, s *Shift) error {
+	s.UpdatedAt = time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": s.ID, "tenant_id": s.TenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{
+			"user_id":    s.UserID,
+			"start_at":   s.StartAt,
+			"end_at":     s.EndAt,
+			"role":       s.Role,
+			"notes":      s.Notes,
+			"updated_at": s.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrShiftNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrShiftNotFound
+	}
+	return nil
+}
+
+func (r *repository) Publish(ctx context.Context, ids []primitive.ObjectID, tenantID primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "tenant_id": tenantID, "status": ShiftStatusDraft, "deleted_at": nil},
+		bson.M{"$set": bson.M{"status": ShiftStatusPublished, "published_at": at, "updated_at": at}},
+	)
+	return err
+}
@@ -0,0 +1,29 @@
+package shifts
+
+import (
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/shifts
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	notifSvc := notifications.NewService(
+		notifications.NewRepository(db),
+		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
+		owners.NewRepository(db),
+		nil,
+	)
+	handler := NewHandler(NewService(NewRepository(db), users.NewRepository(db), notifSvc))
+
+	s := private.Group("/shifts")
+	s.POST("", handler.Create)
+	s.GET("", handler.Roster)
+	s.POST("/publish", handler.Publish)
+	s.GET("/:id", handler.Get)
+	s.PUT("/:id", handler.Update)
+	s.DELETE("/:id", handler.Delete)
+}
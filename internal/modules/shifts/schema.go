@@ -0,0 +1,57 @@
+package shifts
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Shift status values. Draft shifts are the roster being planned: staff are
+// not told about them and they do not restrict appointments until published.
+const (
+	ShiftStatusDraft     = "draft"
+	ShiftStatusPublished = "published"
+)
+
+// Shift is one block of time a staff member is on duty
+type Shift struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID    primitive.ObjectID `bson:"tenant_id"`
+	UserID      primitive.ObjectID `bson:"user_id"`
+	StartAt     time.Time          `bson:"start_at"`
+	EndAt       time.Time          `bson:"end_at"`
+	Role        string             `bson:"role"`
+	Notes       string             `bson:"notes,omitempty"`
+	Status      string             `bson:"status"`
+	PublishedAt *time.Time         `bson:"published_at,omitempty"`
+	CreatedBy   primitive.ObjectID `bson:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	DeletedAt   *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// Covers reports whether the shift spans the whole of [start, end)
+func (s *Shift) Covers(start, end time.Time) bool {
+	return !s.StartAt.After(start) && !s.EndAt.Before(end)
+}
+
+// Overlaps reports whether the shift shares any time with [start, end)
+func (s *Shift) Overlaps(start, end time.Time) bool {
+	return s.StartAt.Before(end) && s.EndAt.After(start)
+}
+
+// ToResponse converts a shift to its API representation
+func (s *Shift) ToResponse() ShiftResponse {
+	return ShiftResponse{
+		ID:          s.ID.Hex(),
+		UserID:      s.UserID.Hex(),
+		StartAt:     s.StartAt,
+		EndAt:       s.EndAt,
+		Role:        s.Role,
+		Notes:       s.Notes,
+		Status:      s.Status,
+		PublishedAt: s.PublishedAt,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
@@ -0,0 +1,323 @@
+package shifts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/users"
+)
+
+// WarningOverlappingShift flags a shift that overlaps another one of the same staff member
+const WarningOverlappingShift = "OVERLAPPING_SHIFT"
+
+// maxShiftLength keeps typos in the end date from creating week-long shifts
+const maxShiftLength = 24 * time.Hour
+
+// UserRepository defines the interface for user data access
+type UserRepository interface {
+	FindByID(ctx context.Context, id string) (*users.User, error)
+}
+
+// NotificationSender defines the interface for staff notifications
+type NotificationSender interface {
+	SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error
+}
+
+// Service provides business logic for the staff roster
+type Service struct {
+	repo            Repository
+	userRepo        UserRepository
+	notificationSvc NotificationSender
+}
+
+// NewService creates a new shift service
+func NewService(repo Repository, userRepo UserRepository, notificationSvc NotificationSender) *Service {
+	return &Service{
+		repo:            repo,
+		userRepo:        userRepo,
+		notificationSvc: notificationSvc,
+	}
+}
+
+// Create adds a draft shift to the roster. Overlaps with other shifts of the
+// same staff member are saved anyway and returned as warnings.
+func (s *Service) Create(ctx context.Context, dto *CreateShiftDTO, tenantID, createdBy primitive.ObjectID) (*ShiftResponse, error) {
+	user, err := s.staffMember(ctx, dto.UserID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePeriod(dto.StartAt, dto.EndAt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shift := &Shift{
+		TenantID:  tenantID,
+		UserID:    user.ID,
+		StartAt:   dto.StartAt,
+		EndAt:     dto.EndAt,
+		Role:      dto.Role,
+		Notes:     dto.Notes,
+		Status:    ShiftStatusDraft,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, shift); err != nil {
+		return nil, err
+	}
+	return s.withWarnings(ctx, shift, user.Name)
+}
+
+// Get returns a shift by ID
+func (s *Service) Get(ctx context.Context, id string, tenantID primitive.ObjectID) (*Shift, error) {
+	shiftID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid shift ID format")
+	}
+	return s.repo.FindByID(ctx, shiftID, tenantID)
+}
+
+// Update changes a shift. A published shift stays published.
+func (s *Service) Update(ctx context.Context, id string, dto *UpdateShiftDTO, tenantID primitive.ObjectID) (*ShiftResponse, error) {
+	shift, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := shift.UserID.Hex()
+	if dto.UserID != nil {
+		userID = *dto.UserID
+	}
+	user, err := s.staffMember(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	shift.UserID = user.ID
+
+	if dto.StartAt != nil {
+		shift.StartAt = *dto.StartAt
+	}
+	if dto.EndAt != nil {
+		shift.EndAt = *dto.EndAt
+	}
+	if err := validatePeriod(shift.StartAt, shift.EndAt); err != nil {
+		return nil, err
+	}
+	if dto.Role != nil {
+		shift.Role = *dto.Role
+	}
+	if dto.Notes != nil {
+		shift.Notes = *dto.Notes
+	}
+
+	if err := s.repo.Update(ctx, shift); err != nil {
+		return nil, err
+	}
+	return s.withWarnings(ctx, shift, user.Name)
+}
+
+// Delete removes a shift from the roster
+func (s *Service) Delete(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	shiftID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrValidation("id", "invalid shift ID format")
+	}
+	return s.repo.Delete(ctx, shiftID, tenantID)
+}
+
+// Roster returns the shifts overlapping the period, each flagged with the
+// other shifts of the same staff member it overlaps.
+func (s *Service) Roster(ctx context.Context, q RosterQuery, tenantID primitive.ObjectID) (*RosterResponse, error) {
+	if !q.DateTo.After(q.DateFrom) {
+		return nil, ErrValidation("date_to", "must be after date_from")
+	}
+	f := shiftFilter{From: q.DateFrom, To: q.DateTo, Status: q.Status}
+	if q.UserID != nil {
+		uid, err := primitive.ObjectIDFromHex(*q.UserID)
+		if err != nil {
+			return nil, ErrValidation("user_id", "invalid user ID format")
+		}
+		f.UserID = &uid
+	}
+
+	shifts, err := s.repo.Find(ctx, tenantID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[primitive.ObjectID]string{}
+	resp := &RosterResponse{DateFrom: q.DateFrom, DateTo: q.DateTo, Shifts: make([]ShiftResponse, len(shifts))}
+	for i := range shifts {
+		r := shifts[i].ToResponse()
+		r.UserName = s.userName(ctx, shifts[i].UserID, names)
+		r.Warnings = overlapWarnings(&shifts[i], shifts)
+		resp.Shifts[i] = r
+	}
+	return resp, nil
+}
+
+// Publish publishes the draft shifts starting in the period and tells each
+// staff member about their upcoming shifts. Notification failures are logged
+// and do not undo the publication.
+func (s *Service) Publish(ctx context.Context, dto *PublishRosterDTO, tenantID primitive.ObjectID) (*PublishRosterResponse, error) {
+	if !dto.DateTo.After(dto.DateFrom) {
+		return nil, ErrValidation("date_to", "must be after date_from")
+	}
+
+	drafts, err := s.repo.Find(ctx, tenantID, shiftFilter{From: dto.DateFrom, To: dto.DateTo, Status: ShiftStatusDraft})
+	if err != nil {
+		return nil, err
+	}
+	// Only shifts that start in the period; one that started earlier belongs
+	// to the previous roster
+	drafts = slices.DeleteFunc(drafts, func(sh Shift) bool { return sh.StartAt.Before(dto.DateFrom) })
+	if len(drafts) == 0 {
+		return &PublishRosterResponse{}, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(drafts))
+	byUser := map[primitive.ObjectID][]Shift{}
+	for i, sh := range drafts {
+		ids[i] = sh.ID
+		byUser[sh.UserID] = append(byUser[sh.UserID], sh)
+	}
+	if err := s.repo.Publish(ctx, ids, tenantID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	notified := 0
+	for userID, upcoming := range byUser {
+		if err := s.notifyUpcoming(ctx, tenantID, userID, upcoming); err != nil {
+			slog.Warn("failed to notify published shifts", "tenant_id", tenantID.Hex(), "user_id", userID.Hex(), "error", err)
+			continue
+		}
+		notified++
+	}
+	return &PublishRosterResponse{Published: len(drafts), StaffNotified: notified}, nil
+}
+
+// OnDuty reports whether the staff member can take work during [start, end).
+// The roster only applies on days with published shifts: on other days
+// everyone counts as available, so clinics that do not use it are unaffected.
+func (s *Service) OnDuty(ctx context.Context, tenantID, userID primitive.ObjectID, start, end time.Time) (bool, error) {
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	published, err := s.repo.Find(ctx, tenantID, shiftFilter{From: dayStart, To: dayStart.AddDate(0, 0, 1), Status: ShiftStatusPublished})
+	if err != nil {
+		return false, err
+	}
+	if len(published) == 0 {
+		return true, nil
+	}
+	for i := range published {
+		if published[i].UserID == userID && published[i].Covers(start, end) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) notifyUpcoming(ctx context.Context, tenantID, userID primitive.ObjectID, upcoming []Shift) error {
+	first := upcoming[0]
+	body := fmt.Sprintf("Tu próximo turno: %s, de %s a %s (%s).",
+		first.StartAt.Format("02/01/2006"), first.StartAt.Format("15:04"), first.EndAt.Format("15:04"), first.Role)
+	if len(upcoming) > 1 {
+		body += fmt.Sprintf(" Tienes %d turnos en total en el nuevo cuadro.", len(upcoming))
+	}
+	return s.notificationSvc.SendToStaff(ctx, &notifications.SendStaffDTO{
+		UserID:   userID.Hex(),
+		TenantID: tenantID.Hex(),
+		Type:     notifications.TypeStaffShiftPublished,
+		Title:    "Cuadro de turnos publicado",
+		Body:     body,
+		Data: map[string]string{
+			"shift_id": first.ID.Hex(),
+			"start_at": first.StartAt.Format(time.RFC3339),
+			"shifts":   fmt.Sprint(len(upcoming)),
+		},
+	})
+}
+
+// staffMember loads the user and checks they belong to the clinic
+func (s *Service) staffMember(ctx context.Context, id string, tenantID primitive.ObjectID) (*users.User, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, ErrValidation("user_id", "invalid user ID format")
+	}
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrStaffNotFound
+	}
+	if !slices.Contains(user.TenantIds, tenantID) {
+		return nil, ErrStaffNotFound
+	}
+	return user, nil
+}
+
+func (s *Service) userName(ctx context.Context, id primitive.ObjectID, cache map[primitive.ObjectID]string) string {
+	if name, ok := cache[id]; ok {
+		return name
+	}
+	name := ""
+	if user, err := s.userRepo.FindByID(ctx, id.Hex()); err == nil {
+		name = user.Name
+	}
+	cache[id] = name
+	return name
+}
+
+// withWarnings builds the response of a saved shift, checking it against the
+// staff member's other shifts
+func (s *Service) withWarnings(ctx context.Context, shift *Shift, userName string) (*ShiftResponse, error) {
+	others, err := s.repo.Find(ctx, shift.TenantID, shiftFilter{From: shift.StartAt, To: shift.EndAt, UserID: &shift.UserID, ExcludeID: &shift.ID})
+	if err != nil {
+		return nil, err
+	}
+	resp := shift.ToResponse()
+	resp.UserName = userName
+	resp.Warnings = overlapWarnings(shift, others)
+	return &resp, nil
+}
+
+// overlapWarnings lists the shifts in others of the same staff member that
+// overlap shift
+func overlapWarnings(shift *Shift, others []Shift) []ShiftWarning {
+	var warnings []ShiftWarning
+	for i := range others {
+		other := &others[i]
+		if other.ID == shift.ID || other.UserID != shift.UserID || !other.Overlaps(shift.StartAt, shift.EndAt) {
+			continue
+		}
+		from := shift.StartAt
+		if other.StartAt.After(from) {
+			from = other.StartAt
+		}
+		to := shift.EndAt
+		if other.EndAt.Before(to) {
+			to = other.EndAt
+		}
+		minutes := int(to.Sub(from) / time.Minute)
+		warnings = append(warnings, ShiftWarning{
+			Code:           WarningOverlappingShift,
+			Message:        fmt.Sprintf("Overlaps another shift of the same staff member by %d minutes", minutes),
+			OtherShiftID:   other.ID.Hex(),
+			OverlapMinutes: minutes,
+		})
+	}
+	return warnings
+}
+
+func validatePeriod(start, end time.Time) error {
+	if !end.After(start) {
+		return ErrValidation("end_at", "must be after start_at")
+	}
+	if end.Sub(start) > maxShiftLength {
+		return ErrValidation("end_at", "a shift cannot last more than 24 hours")
+	}
+	return nil
+}
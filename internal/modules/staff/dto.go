@@ -0,0 +1,39 @@
+package staff
+
+import "time"
+
+// DirectoryQuery filters the staff directory
+type DirectoryQuery struct {
+	Role   string `form:"role" binding:"omitempty,max=50" example:"veterinarian"`
+	Active *bool  `form:"active" example:"true"`
+}
+
+// AvailabilitySummary is what the published roster says about a staff member
+// for the coming week. Clinics that do not publish shifts get no shifts here;
+// their staff are bookable at any time within business hours.
+type AvailabilitySummary struct {
+	OnDutyNow      bool       `json:"on_duty_now"`
+	NextShiftStart *time.Time `json:"next_shift_start,omitempty"`
+	UpcomingShifts int        `json:"upcoming_shifts"`
+}
+
+// StaffMemberResponse is a staff member in the admin directory
+type StaffMemberResponse struct {
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Email        string              `json:"email"`
+	Phone        string              `json:"phone,omitempty"`
+	Roles        []string            `json:"roles"`
+	Specialties  []string            `json:"specialties"`
+	AvatarURL    string              `json:"avatar_url,omitempty"`
+	Active       bool                `json:"active"`
+	Availability AvailabilitySummary `json:"availability"`
+}
+
+// VetResponse is a veterinarian as shown to owners choosing who sees their pet
+type VetResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Specialties []string `json:"specialties"`
+	AvatarURL   string   `json:"avatar_url,omitempty"`
+}
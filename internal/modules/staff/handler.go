@@ -0,0 +1,52 @@
+package staff
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for the staff directory
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new staff directory handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Directory lists the clinic's staff
+// @Summary Staff directory
+// @Description List the clinic's staff with their roles, specialties and a summary of their published shifts for the next 7 days. Only current staff unless active=false
+// @Tags staff
+// @Produce json
+// @Param role query string false "Role name" example(veterinarian)
+// @Param active query bool false "true for current staff (default), false for removed staff"
+// @Success 200 {array} StaffMemberResponse
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/staff [get]
+func (h *Handler) Directory(c *gin.Context) (any, error) {
+	var q DirectoryQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Directory(c.Request.Context(), q, sharedMiddleware.GetTenantID(c))
+}
+
+// Vets lists the clinic's veterinarians for owners
+// @Summary List veterinarians
+// @Description List the clinic's veterinarians with their specialties and photo, for owners choosing who sees their pet
+// @Tags mobile-vets
+// @Produce json
+// @Success 200 {array} VetResponse
+// @Security BearerAuth
+// @Router /mobile/vets [get]
+func (h *Handler) Vets(c *gin.Context) (any, error) {
+	return h.service.Vets(c.Request.Context(), sharedMiddleware.GetTenantID(c))
+}
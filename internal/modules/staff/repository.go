@@ -0,0 +1,112 @@
+package staff
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/modules/roles"
+	"github.com/eren_dev/go_server/internal/modules/shifts"
+	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// memberFilter selects a clinic's staff. RoleIDs, when not nil, keeps only
+// members holding one of them; Active picks current or removed members.
+type memberFilter struct {
+	RoleIDs []primitive.ObjectID
+	Active  bool
+}
+
+// Repository reads the clinic's staff, their roles and their shifts
+type Repository interface {
+	FindMembers(ctx context.Context, tenantID primitive.ObjectID, f memberFilter) ([]users.User, error)
+	FindRoles(ctx context.Context, tenantID primitive.ObjectID) ([]roles.Role, error)
+	FindPublishedShifts(ctx context.Context, tenantID primitive.ObjectID, userIDs []primitive.ObjectID, from, to time.Time) ([]shifts.Shift, error)
+}
+
+type repository struct {
+	users  *mongo.Collection
+	roles  *mongo.Collection
+	shifts *mongo.Collection
+}
+
+// NewRepository creates a new staff directory repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		users:  db.Collection("users"),
+		roles:  db.Collection("roles"),
+		shifts: db.Collection("shifts"),
+	}
+}
+
+func (r *repository) FindMembers(ctx context.Context, tenantID primitive.ObjectID, f memberFilter) ([]users.User, error) {
+	filter := bson.M{"tenant_ids": tenantID}
+	if f.Active {
+		filter["deleted_at"] = nil
+	} else {
+		filter["deleted_at"] = bson.M{"$ne": nil}
+	}
+	if f.RoleIDs != nil {
+		filter["role_ids"] = bson.M{"$in": f.RoleIDs}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetProjection(bson.M{"password": 0})
+	cursor, err := r.users.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []users.User{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *repository) FindRoles(ctx context.Context, tenantID primitive.ObjectID) ([]roles.Role, error) {
+	cursor, err := r.roles.Find(ctx, bson.M{"tenant_id": tenantID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []roles.Role{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FindPublishedShifts returns the published shifts of the given members
+// overlapping [from, to), earliest first
+func (r *repository) FindPublishedShifts(ctx context.Context, tenantID primitive.ObjectID, userIDs []primitive.ObjectID, from, to time.Time) ([]shifts.Shift, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"deleted_at": nil,
+		"status":     shifts.ShiftStatusPublished,
+		"user_id":    bson.M{"$in": userIDs},
+		"start_at":   bson.M{"$lt": to},
+		"end_at":     bson.M{"$gt": from},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_at", Value: 1}})
+	cursor, err := r.shifts.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []shifts.Shift{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
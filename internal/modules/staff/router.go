@@ -0,0 +1,22 @@
+package staff
+
+import (
+	"github.com/eren_dev/go_server/internal/shared/database"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// RegisterAdminRoutes registers admin-panel routes under /api/staff
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db)))
+
+	s := private.Group("/staff")
+	s.GET("", handler.Directory)
+}
+
+// RegisterMobileRoutes registers owner-facing routes under /mobile/vets
+func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
+	handler := NewHandler(NewService(NewRepository(db)))
+
+	m := mobile.Group("/vets")
+	m.GET("", handler.Vets)
+}
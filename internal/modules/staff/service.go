@@ -0,0 +1,169 @@
+package staff
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/shifts"
+	"github.com/eren_dev/go_server/internal/modules/users"
+)
+
+// RoleVeterinarian is the role name of the staff owners can book with
+const RoleVeterinarian = "veterinarian"
+
+// availabilityWindow is how far ahead the availability summary looks
+const availabilityWindow = 7 * 24 * time.Hour
+
+// Service builds the clinic's staff directory
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new staff directory service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Directory lists the clinic's staff ordered by name with their roles,
+// specialties and availability. Without q.Active only current staff are
+// listed.
+func (s *Service) Directory(ctx context.Context, q DirectoryQuery, tenantID primitive.ObjectID) ([]StaffMemberResponse, error) {
+	active := q.Active == nil || *q.Active
+	members, roleNames, err := s.members(ctx, tenantID, q.Role, active)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return []StaffMemberResponse{}, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(members))
+	for i := range members {
+		ids[i] = members[i].ID
+	}
+	now := time.Now()
+	upcoming, err := s.repo.FindPublishedShifts(ctx, tenantID, ids, now, now.Add(availabilityWindow))
+	if err != nil {
+		return nil, err
+	}
+	shiftsByUser := make(map[primitive.ObjectID][]shifts.Shift, len(members))
+	for _, shift := range upcoming {
+		shiftsByUser[shift.UserID] = append(shiftsByUser[shift.UserID], shift)
+	}
+
+	resp := make([]StaffMemberResponse, len(members))
+	for i, m := range members {
+		memberRoles := []string{}
+		for _, roleID := range m.RoleIds {
+			if name, ok := roleNames[roleID]; ok {
+				memberRoles = append(memberRoles, name)
+			}
+		}
+		resp[i] = StaffMemberResponse{
+			ID:           m.ID.Hex(),
+			Name:         m.Name,
+			Email:        m.Email,
+			Phone:        m.Phone,
+			Roles:        memberRoles,
+			Specialties:  specialties(&m),
+			AvatarURL:    m.AvatarURL,
+			Active:       m.DeletedAt == nil,
+			Availability: summarize(shiftsByUser[m.ID], now),
+		}
+	}
+	return resp, nil
+}
+
+// Vets lists the clinic's current veterinarians with only what owners need
+// to pick one
+func (s *Service) Vets(ctx context.Context, tenantID primitive.ObjectID) ([]VetResponse, error) {
+	members, _, err := s.members(ctx, tenantID, RoleVeterinarian, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]VetResponse, len(members))
+	for i, m := range members {
+		resp[i] = VetResponse{
+			ID:          m.ID.Hex(),
+			Name:        m.Name,
+			Specialties: specialties(&m),
+			AvatarURL:   m.AvatarURL,
+		}
+	}
+	return resp, nil
+}
+
+// MemberIDs returns the clinic's current staff holding any of the roles
+// named in roleNames, for targeting staff alerts
+func (s *Service) MemberIDs(ctx context.Context, tenantID primitive.ObjectID, roleNames []string) ([]primitive.ObjectID, error) {
+	var ids []primitive.ObjectID
+	for _, name := range roleNames {
+		members, _, err := s.members(ctx, tenantID, name, true)
+		if err != nil {
+			return nil, err
+		}
+		for i := range members {
+			ids = append(ids, members[i].ID)
+		}
+	}
+	return ids, nil
+}
+
+// members loads the clinic's staff, optionally only those holding the role
+// named roleName, together with the names of the clinic's roles by ID
+func (s *Service) members(ctx context.Context, tenantID primitive.ObjectID, roleName string, active bool) ([]users.User, map[primitive.ObjectID]string, error) {
+	clinicRoles, err := s.repo.FindRoles(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	roleNames := make(map[primitive.ObjectID]string, len(clinicRoles))
+	for _, role := range clinicRoles {
+		roleNames[role.ID] = role.Name
+	}
+
+	f := memberFilter{Active: active}
+	if roleName != "" {
+		f.RoleIDs = []primitive.ObjectID{}
+		for _, role := range clinicRoles {
+			if role.Name == roleName {
+				f.RoleIDs = append(f.RoleIDs, role.ID)
+			}
+		}
+		if len(f.RoleIDs) == 0 {
+			return nil, roleNames, nil
+		}
+	}
+
+	members, err := s.repo.FindMembers(ctx, tenantID, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return members, roleNames, nil
+}
+
+// summarize condenses a member's upcoming published shifts, earliest first
+func summarize(upcoming []shifts.Shift, now time.Time) AvailabilitySummary {
+	var summary AvailabilitySummary
+	for i := range upcoming {
+		if upcoming[i].Covers(now, now) {
+			summary.OnDutyNow = true
+			continue
+		}
+		if summary.NextShiftStart == nil {
+			start := upcoming[i].StartAt
+			summary.NextShiftStart = &start
+		}
+		summary.UpcomingShifts++
+	}
+	return summary
+}
+
+func specialties(u *users.User) []string {
+	if u.Specialties == nil {
+		return []string{}
+	}
+	return u.Specialties
+}
@@ -0,0 +1,14 @@
+package tags
+
+// SuggestDTO represents the autocomplete query for tags
+type SuggestDTO struct {
+	Entity string `form:"entity" binding:"required,oneof=patient product" example:"patient"`
+	Prefix string `form:"prefix" binding:"max=40" example:"dia"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=50" example:"10"`
+}
+
+// Suggestion is a tag in use and how many records carry it
+type Suggestion struct {
+	Tag   string `json:"tag" example:"diabetic"`
+	Count int64  `json:"count" example:"12"`
+}
@@ -0,0 +1,10 @@
+package tags
+
+import (
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// Module errors
+var (
+	ErrUnknownEntity = sharedErrors.Validation("entity", "must be one of: patient, product")
+)
@@ -0,0 +1,41 @@
+package tags
+
+import (
+	"github.com/gin-gonic/gin"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+	"github.com/eren_dev/go_server/internal/shared/validation"
+)
+
+// Handler handles HTTP requests for tags
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new tag handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Suggest autocompletes tags
+// @Summary Autocomplete tags
+// @Description List the clinic's tags for patients or products that start with the prefix, most used first
+// @Tags tags
+// @Produce json
+// @Param entity query string true "Tagged entity" Enums(patient, product)
+// @Param prefix query string false "Tag prefix"
+// @Param limit query int false "Maximum suggestions (default 10, max 50)"
+// @Success 200 {array} Suggestion
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/tags [get]
+func (h *Handler) Suggest(c *gin.Context) (any, error) {
+	var dto SuggestDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	return h.service.Suggest(c.Request.Context(), &dto, sharedMiddleware.GetTenantID(c))
+}
@@ -0,0 +1,75 @@
+package tags
+
+import (
+	"context"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// Entities that carry tags
+const (
+	EntityPatient = "patient"
+	EntityProduct = "product"
+)
+
+// Repository reads the tags in use across a clinic's patients and products
+type Repository interface {
+	Suggest(ctx context.Context, tenantID primitive.ObjectID, entity, prefix string, limit int) ([]Suggestion, error)
+}
+
+type repository struct {
+	collections map[string]*mongo.Collection
+}
+
+// NewRepository creates a new tag repository
+func NewRepository(db *database.MongoDB) Repository {
+	return &repository{
+		collections: map[string]*mongo.Collection{
+			EntityPatient: db.Collection("patients"),
+			EntityProduct: db.Collection("products"),
+		},
+	}
+}
+
+// Suggest returns the tags starting with prefix, most used first
+func (r *repository) Suggest(ctx context.Context, tenantID primitive.ObjectID, entity, prefix string, limit int) ([]Suggestion, error) {
+	collection, ok := r.collections[entity]
+	if !ok {
+		return nil, ErrUnknownEntity
+	}
+
+	match := bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tenant_id": tenantID, "deleted_at": nil, "tags": match}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$match", Value: bson.M{"tags": match}}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Tag   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	results := make([]Suggestion, len(rows))
+	for i, row := range rows {
+		results[i] = Suggestion{Tag: row.Tag, Count: row.Count}
+	}
+	return results, nil
+}
@@ -0,0 +1,29 @@
+package tags
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultSuggestLimit is how many tags autocomplete returns when no limit is given
+const DefaultSuggestLimit = 10
+
+// Service provides tag autocomplete
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new tag service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Suggest returns the clinic's tags for an entity that start with the prefix
+func (s *Service) Suggest(ctx context.Context, dto *SuggestDTO, tenantID primitive.ObjectID) ([]Suggestion, error) {
+	limit := dto.Limit
+	if limit == 0 {
+		limit = DefaultSuggestLimit
+	}
+	return s.repo.Suggest(ctx, tenantID, dto.Entity, normalizeOne(dto.Prefix), limit)
+}
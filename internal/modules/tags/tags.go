@@ -0,0 +1,87 @@
+package tags
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+const (
+	// MaxTags is how many tags a single patient or product may carry
+	MaxTags = 20
+	// MaxTagLength is the longest tag accepted, in characters
+	MaxTagLength = 40
+)
+
+// Normalize lowercases and trims each tag, collapses inner whitespace and
+// drops empty and repeated tags, keeping the order they were given in. It
+// rejects lists with too many tags or with a tag that is too long.
+func Normalize(raw []string) ([]string, error) {
+	result := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		t = normalizeOne(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		if len([]rune(t)) > MaxTagLength {
+			return nil, sharedErrors.Validation("tags", "tag too long: "+t)
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	if len(result) > MaxTags {
+		return nil, sharedErrors.Validation("tags", "too many tags")
+	}
+	return result, nil
+}
+
+func normalizeOne(t string) string {
+	return strings.Join(strings.Fields(strings.ToLower(t)), " ")
+}
+
+// Filter selects documents by their tags. With MatchAll a document must
+// carry every tag; otherwise any one of them is enough.
+type Filter struct {
+	Tags     []string
+	MatchAll bool
+}
+
+// FromQuery reads ?tags=a,b (or repeated ?tags=) and ?tags_mode=any|all
+func FromQuery(c *gin.Context) (Filter, error) {
+	var raw []string
+	for _, v := range c.QueryArray("tags") {
+		raw = append(raw, strings.Split(v, ",")...)
+	}
+
+	var f Filter
+	switch c.DefaultQuery("tags_mode", "any") {
+	case "any":
+	case "all":
+		f.MatchAll = true
+	default:
+		return Filter{}, sharedErrors.Validation("tags_mode", "must be one of: any, all")
+	}
+
+	tags, err := Normalize(raw)
+	if err != nil {
+		return Filter{}, err
+	}
+	f.Tags = tags
+	return f, nil
+}
+
+// Apply adds the tag condition to a Mongo filter; it is a no-op without tags
+func (f Filter) Apply(filter bson.M) {
+	if len(f.Tags) == 0 {
+		return
+	}
+	if f.MatchAll {
+		filter["tags"] = bson.M{"$all": f.Tags}
+		return
+	}
+	filter["tags"] = bson.M{"$in": f.Tags}
+}
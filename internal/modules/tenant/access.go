@@ -0,0 +1,38 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sharedMiddleware "github.com/eren_dev/go_server/internal/shared/middleware"
+)
+
+// AccessChecker expone el estado del tenant a TenantAccessMiddleware
+type AccessChecker struct {
+	repo TenantRepository
+}
+
+// NewAccessChecker crea el verificador de acceso a partir del repositorio
+func NewAccessChecker(repo TenantRepository) *AccessChecker {
+	return &AccessChecker{repo: repo}
+}
+
+// TenantState informa si el tenant existe, si puede operar y quién es su propietario
+func (a *AccessChecker) TenantState(ctx context.Context, tenantID primitive.ObjectID) (sharedMiddleware.TenantState, error) {
+	t, err := a.repo.FindByID(ctx, tenantID.Hex())
+	if err != nil {
+		if errors.Is(err, ErrTenantNotFound) {
+			return sharedMiddleware.TenantState{}, nil
+		}
+		return sharedMiddleware.TenantState{}, err
+	}
+
+	return sharedMiddleware.TenantState{
+		Found:     t.DeletedAt == nil,
+		Active:    t.Status == Active || t.Status == Trial,
+		Suspended: t.Status == Suspended,
+		OwnerID:   t.OwnerID.Hex(),
+	}, nil
+}
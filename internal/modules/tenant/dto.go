@@ -2,6 +2,9 @@ package tenant
 
 import (
 	"time"
+
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/shared/money"
 )
 
 // CreateTenantDTO request para crear tenant
@@ -26,7 +29,7 @@ type CreateTenantDTO struct {
 	// Técnico
 	Domain   string `json:"domain" binding:"required" example:"vetvida"`
 	TimeZone string `json:"timezone" binding:"required" example:"America/Bogota"`
-	Currency string `json:"currency" binding:"required" example:"COP"`
+	Currency string `json:"currency" binding:"required,iso4217" example:"COP"`
 	Logo     string `json:"logo,omitempty" example:"https://example.com/logo.png"`
 }
 
@@ -43,8 +46,114 @@ type UpdateTenantDTO struct {
 	Address              string `json:"address,omitempty" example:"Calle 123 #45-67"`
 	Country              string `json:"country,omitempty" example:"Colombia"`
 	TimeZone             string `json:"timezone,omitempty" example:"America/Bogota"`
-	Currency             string `json:"currency,omitempty" example:"COP"`
+	Currency             string `json:"currency,omitempty" binding:"omitempty,iso4217" example:"COP"`
 	Logo                 string `json:"logo,omitempty" example:"https://example.com/logo.png"`
+
+	// Configuración
+	AutoWriteOffExpired     *bool    `json:"auto_writeoff_expired,omitempty" example:"true"`
+	AutoConfirmAppointments *bool    `json:"auto_confirm_appointments,omitempty" example:"false"`
+	DefaultLocale           string   `json:"default_locale,omitempty" binding:"omitempty,oneof=es en" example:"es"`
+	MaxAdvanceBookingDays   *int     `json:"max_advance_booking_days,omitempty" binding:"omitempty,min=0,max=730" example:"90"`
+	MinBookingNoticeHours   *int     `json:"min_booking_notice_hours,omitempty" binding:"omitempty,min=0,max=168" example:"2"`
+	AllowOwnerConfirmation  *bool    `json:"allow_owner_confirmation,omitempty" example:"true"`
+	PreventPatientOverlap   *bool    `json:"prevent_patient_overlap,omitempty" example:"true"`
+	PatientAppointmentGap   *int     `json:"patient_appointment_gap_minutes,omitempty" binding:"omitempty,min=0,max=1440" example:"30"`
+	LateArrivalTolerance    *int     `json:"late_arrival_tolerance_minutes,omitempty" binding:"omitempty,min=0,max=120" example:"15"`
+	SlotGranularity         *int     `json:"slot_granularity_minutes,omitempty" binding:"omitempty,oneof=0 5 10 15 20 30 60" example:"15"`
+	RequireIntake           *bool    `json:"require_intake_first_appointment,omitempty" example:"true"`
+	RequireVerifiedContacts *bool    `json:"require_verified_contacts,omitempty" example:"true"`
+	InvoicePaymentProvider  string   `json:"invoice_payment_provider,omitempty" binding:"omitempty,oneof=wompi stripe" example:"wompi"`
+	LoyaltyEnabled          *bool    `json:"loyalty_enabled,omitempty" example:"true"`
+	LoyaltyPointsPerVisit   *int     `json:"loyalty_points_per_visit,omitempty" binding:"omitempty,min=0,max=10000" example:"10"`
+	LoyaltyPointsPerUnit    *float64 `json:"loyalty_points_per_currency_unit,omitempty" binding:"omitempty,min=0" example:"0.001"`
+	// Estilos del calendario: se combinan con los actuales por clave; un estilo vacío vuelve al valor por defecto
+	CalendarTypeStyles     map[string]CalendarStyleDTO `json:"calendar_type_styles,omitempty" binding:"omitempty,dive"`
+	CalendarStatusStyles   map[string]CalendarStyleDTO `json:"calendar_status_styles,omitempty" binding:"omitempty,dive"`
+	CalendarPriorityStyles map[string]CalendarStyleDTO `json:"calendar_priority_styles,omitempty" binding:"omitempty,dive"`
+	// Anticipos por tipo de cita: se combinan con los actuales por tipo; un monto 0 deja de exigirlo
+	AppointmentDeposits map[string]AppointmentDepositDTO `json:"appointment_deposits,omitempty" binding:"omitempty,dive"`
+	// Horario de silencio: reemplaza la configuración completa
+	QuietHours *QuietHoursDTO `json:"quiet_hours,omitempty"`
+	// Recordatorios de vencimiento: "per_item" (uno por vacuna) o "digest" (un resumen por propietario)
+	ReminderDelivery string `json:"reminder_delivery,omitempty" binding:"omitempty,oneof=per_item digest" example:"digest"`
+	// Reprogramación de citas desde la app del propietario
+	AllowOwnerReschedule       *bool `json:"allow_owner_reschedule,omitempty" example:"true"`
+	OwnerRescheduleCutoffHours *int  `json:"owner_reschedule_cutoff_hours,omitempty" binding:"omitempty,min=0,max=168" example:"24"`
+	// Adjuntos permitidos por historia clínica
+	MaxRecordAttachments *int `json:"max_record_attachments,omitempty" binding:"omitempty,min=1,max=100" example:"10"`
+	// Numeración consecutiva: texto libre con {seq} o {seq:N} una vez y opcionalmente {yyyy}, {yy}, {mm}
+	InvoiceNumberFormat     string `json:"invoice_number_format,omitempty" binding:"omitempty,max=40" example:"FAC-{yyyy}-{seq:6}"`
+	CertificateNumberFormat string `json:"certificate_number_format,omitempty" binding:"omitempty,max=40" example:"VAC-{seq:6}"`
+	// Asignación automática de veterinario a las solicitudes de cita: "none", "round_robin", "least_loaded" o "by_specialty"
+	AppointmentAutoAssign string `json:"appointment_auto_assign,omitempty" binding:"omitempty,oneof=none round_robin least_loaded by_specialty" example:"least_loaded"`
+	// Citas que siguen activas después de su hora de fin: aviso al veterinario y, opcionalmente, cierre automático de las que están en curso
+	StaleAppointmentAlertMinutes  *int  `json:"stale_appointment_alert_minutes,omitempty" binding:"omitempty,min=0,max=1440" example:"60"`
+	AutoCompleteStaleAppointments *bool `json:"auto_complete_stale_appointments,omitempty" example:"false"`
+	// Efectos de la prioridad de las citas: reemplaza la configuración completa
+	Priority *PrioritySettingsDTO `json:"priority,omitempty"`
+	// Aprobación de ajustes de inventario por un segundo usuario cuando superan el umbral de unidades
+	RequireStockAdjustmentApproval   *bool `json:"require_stock_adjustment_approval,omitempty" example:"true"`
+	StockAdjustmentApprovalThreshold *int  `json:"stock_adjustment_approval_threshold,omitempty" binding:"omitempty,min=0" example:"20"`
+	// Destinatarios de las alertas de alergias severas: reemplaza la configuración completa
+	SevereAllergyAlerts *AlertRecipientsDTO `json:"severe_allergy_alerts,omitempty"`
+	// Factura en borrador automática al completar una cita
+	AutoDraftInvoiceOnCompletion *bool `json:"auto_draft_invoice_on_completion,omitempty" example:"true"`
+	// Avisos por edad de la mascota: reemplaza la configuración completa; una lista vacía los desactiva
+	AgeReminders []AgeReminderDTO `json:"age_reminders,omitempty" binding:"omitempty,max=20,dive"`
+	// Aviso de retraso a los propietarios que esperan cuando una cita en curso se alarga: retraso mínimo para avisar (0 = no avisar) y minutos entre avisos de la misma cita
+	OverrunNoticeMinutes         *int `json:"overrun_notice_minutes,omitempty" binding:"omitempty,min=0,max=240" example:"15"`
+	OverrunNoticeIntervalMinutes *int `json:"overrun_notice_interval_minutes,omitempty" binding:"omitempty,min=5,max=240" example:"30"`
+	// Motivos propios para las salidas de inventario: reemplaza la lista completa; una lista vacía deja solo los predefinidos
+	StockOutReasons []string `json:"stock_out_reasons,omitempty" binding:"omitempty,max=30,dive,min=1,max=40" example:"donation"`
+}
+
+// AlertRecipientsDTO roles (por nombre) y usuarios que reciben una alerta para el staff
+type AlertRecipientsDTO struct {
+	Roles   []string `json:"roles" binding:"omitempty,max=20,dive,min=1,max=50" example:"veterinarian"`
+	UserIDs []string `json:"user_ids" binding:"omitempty,max=50" example:"507f1f77bcf86cd799439011"`
+}
+
+// AgeReminderDTO aviso al propietario cuando la mascota cumple una edad
+type AgeReminderDTO struct {
+	SpeciesID string `json:"species_id,omitempty" example:"507f1f77bcf86cd799439011"`
+	AgeMonths int    `json:"age_months" binding:"required,min=1,max=360" example:"84"`
+	Label     string `json:"label" binding:"required,max=60" example:"chequeo geriátrico"`
+}
+
+// AppointmentDepositDTO anticipo exigido para un tipo de cita; Amount va en
+// unidades de la moneda de la clínica (50000.50) y se guarda en centavos
+type AppointmentDepositDTO struct {
+	Amount              float64 `json:"amount" binding:"min=0" example:"50000"`
+	ExpiresAfterMinutes int     `json:"expires_after_minutes" binding:"omitempty,min=5,max=10080" example:"60"`
+}
+
+// AppointmentDepositResponse anticipo de un tipo de cita en la API
+type AppointmentDepositResponse struct {
+	Amount              money.Amount `json:"amount"`
+	ExpiresAfterMinutes int          `json:"expires_after_minutes"`
+}
+
+// PrioritySettingsDTO desde qué prioridad aplica cada efecto; vacío usa el
+// valor por defecto y "none" lo desactiva
+type PrioritySettingsDTO struct {
+	BypassNoticeFrom string `json:"bypass_notice_from" binding:"omitempty,oneof=none low normal high emergency" example:"high"`
+	UrgentAlertFrom  string `json:"urgent_alert_from" binding:"omitempty,oneof=none low normal high emergency" example:"emergency"`
+	QueueFirstFrom   string `json:"queue_first_from" binding:"omitempty,oneof=none low normal high emergency" example:"high"`
+	OwnerMaxPriority string `json:"owner_max_priority" binding:"omitempty,oneof=low normal high emergency" example:"normal"`
+}
+
+// QuietHoursDTO horario de silencio de notificaciones, en HH:MM
+type QuietHoursDTO struct {
+	Enabled     bool     `json:"enabled" example:"true"`
+	Start       string   `json:"start" binding:"omitempty,datetime=15:04" example:"22:00"`
+	End         string   `json:"end" binding:"omitempty,datetime=15:04" example:"07:00"`
+	UrgentTypes []string `json:"urgent_types" example:"appointment_cancelled"`
+}
+
+// CalendarStyleDTO color y etiqueta de un tipo, estado o prioridad de cita
+type CalendarStyleDTO struct {
+	Color string `json:"color" binding:"omitempty,hexcolor" example:"#EF4444"`
+	Label string `json:"label" binding:"omitempty,max=40" example:"Urgente"`
 }
 
 // UpdateStatusTenantDTO request para actualizar estado del tenant
@@ -76,6 +185,45 @@ type TenantSubscriptionResponse struct {
 	MRR                    float64    `json:"mrr"`
 }
 
+// TenantSettingsResponse respuesta de configuración
+type TenantSettingsResponse struct {
+	AutoWriteOffExpired     bool                                  `json:"auto_writeoff_expired"`
+	AutoConfirmAppointments bool                                  `json:"auto_confirm_appointments"`
+	DefaultLocale           string                                `json:"default_locale"`
+	MaxAdvanceBookingDays   int                                   `json:"max_advance_booking_days"`
+	MinBookingNoticeHours   int                                   `json:"min_booking_notice_hours"`
+	AllowOwnerConfirmation  bool                                  `json:"allow_owner_confirmation"`
+	PreventPatientOverlap   bool                                  `json:"prevent_patient_overlap"`
+	PatientAppointmentGap   int                                   `json:"patient_appointment_gap_minutes"`
+	LateArrivalTolerance    int                                   `json:"late_arrival_tolerance_minutes"`
+	SlotGranularity         int                                   `json:"slot_granularity_minutes"`
+	RequireIntake           bool                                  `json:"require_intake_first_appointment"`
+	RequireVerifiedContacts bool                                  `json:"require_verified_contacts"`
+	InvoicePaymentProvider  string                                `json:"invoice_payment_provider,omitempty"`
+	Loyalty                 LoyaltySettings                       `json:"loyalty"`
+	Calendar                CalendarSettings                      `json:"calendar"`
+	AppointmentDeposits     map[string]AppointmentDepositResponse `json:"appointment_deposits,omitempty"`
+	QuietHours              QuietHoursSettings                    `json:"quiet_hours"`
+	ReminderDelivery        string                                `json:"reminder_delivery"`
+	MaxRecordAttachments    int                                   `json:"max_record_attachments"`
+	AllowOwnerReschedule    bool                                  `json:"allow_owner_reschedule"`
+	OwnerRescheduleCutoff   int                                   `json:"owner_reschedule_cutoff_hours"`
+	InvoiceNumberFormat     string                                `json:"invoice_number_format"`
+	CertificateNumberFormat string                                `json:"certificate_number_format"`
+	AppointmentAutoAssign   string                                `json:"appointment_auto_assign"`
+	StaleAlertMinutes       int                                   `json:"stale_appointment_alert_minutes"`
+	AutoCompleteStale       bool                                  `json:"auto_complete_stale_appointments"`
+	Priority                PrioritySettings                      `json:"priority"`
+	StockApprovalRequired   bool                                  `json:"require_stock_adjustment_approval"`
+	StockApprovalThreshold  int                                   `json:"stock_adjustment_approval_threshold"`
+	SevereAllergyAlerts     AlertRecipients                       `json:"severe_allergy_alerts"`
+	AutoDraftInvoice        bool                                  `json:"auto_draft_invoice_on_completion"`
+	AgeReminders            []AgeReminder                         `json:"age_reminders"`
+	OverrunNoticeMinutes    int                                   `json:"overrun_notice_minutes"`
+	OverrunNoticeInterval   int                                   `json:"overrun_notice_interval_minutes"`
+	StockOutReasons         []string                              `json:"stock_out_reasons"`
+}
+
 // TenantUsageResponse respuesta de uso
 type TenantUsageResponse struct {
 	UsersCount     int       `json:"users_count"`
@@ -105,6 +253,7 @@ type TenantResponse struct {
 	Logo                 string                      `json:"logo,omitempty" example:"https://example.com/logo.png"`
 	Subscription         TenantSubscriptionResponse  `json:"subscription"`
 	Usage                TenantUsageResponse         `json:"usage"`
+	Settings             TenantSettingsResponse      `json:"settings"`
 	Status               TenantStatus                `json:"status" example:"active"`
 	CreatedAt            time.Time                   `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt            time.Time                   `json:"updated_at" example:"2024-01-01T00:00:00Z"`
@@ -146,6 +295,43 @@ func ToResponse(t *Tenant) *TenantResponse {
 			StorageLimitMB: t.Usage.StorageLimitMB,
 			LastResetDate:  t.Usage.LastResetDate,
 		},
+		Settings: TenantSettingsResponse{
+			AutoWriteOffExpired:     t.Settings.AutoWriteOffExpired,
+			AutoConfirmAppointments: t.Settings.AutoConfirmAppointments,
+			DefaultLocale:           t.Settings.DefaultLocale,
+			MaxAdvanceBookingDays:   t.Settings.MaxAdvanceBookingDays,
+			MinBookingNoticeHours:   t.Settings.MinBookingNoticeHours,
+			AllowOwnerConfirmation:  t.Settings.AllowOwnerConfirmation,
+			PreventPatientOverlap:   t.Settings.PreventPatientOverlap,
+			PatientAppointmentGap:   t.Settings.PatientAppointmentGapMinutes,
+			LateArrivalTolerance:    t.Settings.LateArrivalToleranceMinutes,
+			SlotGranularity:         t.Settings.SlotGranularityMinutes,
+			RequireIntake:           t.Settings.RequireIntakeForFirstAppointment,
+			RequireVerifiedContacts: t.Settings.RequireVerifiedContacts,
+			InvoicePaymentProvider:  t.Settings.PaymentProvider,
+			Loyalty:                 t.Settings.Loyalty,
+			Calendar:                t.Settings.Calendar,
+			AppointmentDeposits:     appointmentDepositResponses(t.Settings.AppointmentDeposits),
+			QuietHours:              t.Settings.QuietHours,
+			ReminderDelivery:        reminderDelivery(t.Settings.ReminderDelivery),
+			MaxRecordAttachments:    t.Settings.RecordAttachmentLimit(),
+			AllowOwnerReschedule:    t.Settings.AllowOwnerReschedule,
+			OwnerRescheduleCutoff:   t.Settings.OwnerRescheduleCutoffHours,
+			InvoiceNumberFormat:     numberFormat(t.Settings.InvoiceNumberFormat, sequences.Invoice),
+			CertificateNumberFormat: numberFormat(t.Settings.CertificateNumberFormat, sequences.Certificate),
+			AppointmentAutoAssign:   autoAssign(t.Settings.AppointmentAutoAssign),
+			StaleAlertMinutes:       t.Settings.StaleAppointmentAlertMinutes,
+			AutoCompleteStale:       t.Settings.AutoCompleteStaleAppointments,
+			Priority:                t.Settings.Priority.Effective(),
+			StockApprovalRequired:   t.Settings.RequireStockAdjustmentApproval,
+			StockApprovalThreshold:  t.Settings.StockAdjustmentApprovalThreshold,
+			SevereAllergyAlerts:     t.Settings.SevereAllergyAlerts,
+			AutoDraftInvoice:        t.Settings.AutoDraftInvoiceOnCompletion,
+			AgeReminders:            ageReminders(t.Settings.AgeReminders),
+			OverrunNoticeMinutes:    t.Settings.OverrunNoticeMinutes,
+			OverrunNoticeInterval:   int(t.Settings.OverrunNoticeInterval() / time.Minute),
+			StockOutReasons:         stockOutReasons(t.Settings.StockOutReasons),
+		},
 	}
 	
 	// Agregar PlanID solo si existe
@@ -164,4 +350,56 @@ func ToResponseList(tenants []Tenant) []*TenantResponse {
 		responses[i] = ToResponse(&t)
 	}
 	return responses
-}
\ No newline at end of file
+}
+
+// reminderDelivery devuelve el modo efectivo de recordatorios
+func reminderDelivery(mode string) string {
+	if mode == "" {
+		return ReminderDeliveryPerItem
+	}
+	return mode
+}
+
+// autoAssign devuelve la estrategia efectiva de asignación de veterinario
+func autoAssign(strategy string) string {
+	if strategy == "" {
+		return AutoAssignNone
+	}
+	return strategy
+}
+
+// ageReminders devuelve los avisos por edad como lista, vacía si no hay
+func ageReminders(reminders []AgeReminder) []AgeReminder {
+	if reminders == nil {
+		return []AgeReminder{}
+	}
+	return reminders
+}
+
+// stockOutReasons devuelve los motivos propios de salida de inventario, nunca nil
+func stockOutReasons(reasons []string) []string {
+	if reasons == nil {
+		return []string{}
+	}
+	return reasons
+}
+
+// numberFormat devuelve el formato efectivo de una numeración
+func numberFormat(format, sequence string) string {
+	if format == "" {
+		return sequences.DefaultFormat(sequence)
+	}
+	return format
+}
+
+// appointmentDepositResponses convierte los anticipos por tipo de cita a la API
+func appointmentDepositResponses(deposits map[string]AppointmentDeposit) map[string]AppointmentDepositResponse {
+	if len(deposits) == 0 {
+		return nil
+	}
+	resp := make(map[string]AppointmentDepositResponse, len(deposits))
+	for k, d := range deposits {
+		resp[k] = d.ToResponse()
+	}
+	return resp
+}
@@ -7,4 +7,9 @@ var (
 	ErrInvalidTenantID = errors.New("invalid tenant id")
 	ErrOwnerNotFound   = errors.New("owner not found")
 	ErrInvalidOwnerID  = errors.New("invalid owner id")
+
+	ErrInvalidQuietHours   = errors.New("invalid quiet hours: start and end are required and must differ when enabled")
+	ErrInvalidAlertUserID  = errors.New("invalid alert recipients: user_ids must be valid user ids")
+	ErrInvalidAgeReminders = errors.New("invalid age reminders: species_id must be a valid species id and each species and age may appear once")
+	ErrInvalidNumberFormat = errors.New("invalid number format: it must contain {seq} or {seq:N} exactly once and only the tokens {yyyy}, {yy}, {mm}")
 )
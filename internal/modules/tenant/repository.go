@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/eren_dev/go_server/internal/platform/cache"
 	"github.com/eren_dev/go_server/internal/shared/database"
 )
 
@@ -16,10 +17,19 @@ type TenantRepository interface {
 	FindByID(ctx context.Context, id string) (*Tenant, error)
 	FindByExternalSubscriptionID(ctx context.Context, subscriptionID string) (*Tenant, error)
 	FindAll(ctx context.Context) ([]Tenant, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]Tenant, error)
 	Update(ctx context.Context, tenant *Tenant) error
 	Delete(ctx context.Context, id string) error
 }
 
+// tenantCache guarda los tenants por ID: sus settings se leen en casi todas
+// las peticiones. Update y Delete invalidan la entrada.
+var tenantCache = cache.NewReference[Tenant]()
+
+func tenantCacheKey(id primitive.ObjectID) string {
+	return cache.TenantKey(id.Hex(), "tenant")
+}
+
 type tenantRepository struct {
 	collection *mongo.Collection
 }
@@ -41,12 +51,15 @@ func (r *tenantRepository) FindByID(ctx context.Context, id string) (*Tenant, er
 		return nil, ErrInvalidTenantID
 	}
 
-	var tenant Tenant
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tenant)
-	if err != nil {
+	tenant, err := tenantCache.Load(tenantCacheKey(objectID), func() (Tenant, error) {
+		var tenant Tenant
+		err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tenant)
 		if err == mongo.ErrNoDocuments {
-			return nil, ErrTenantNotFound
+			return tenant, ErrTenantNotFound
 		}
+		return tenant, err
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &tenant, nil
@@ -82,6 +95,30 @@ func (r *tenantRepository) FindAll(ctx context.Context) ([]Tenant, error) {
 	return tenants, nil
 }
 
+// FindByIDs devuelve los tenants no eliminados cuyos IDs están en la lista
+func (r *tenantRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]Tenant, error) {
+	tenants := []Tenant{}
+	if len(ids) == 0 {
+		return tenants, nil
+	}
+
+	filter := bson.M{
+		"_id":        bson.M{"$in": ids},
+		"deleted_at": nil,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
 func (r *tenantRepository) Update(ctx context.Context, tenant *Tenant) error {
 	tenant.UpdatedAt = time.Now()
 	_, err := r.collection.UpdateOne(
@@ -89,6 +126,7 @@ func (r *tenantRepository) Update(ctx context.Context, tenant *Tenant) error {
 		bson.M{"_id": tenant.ID},
 		bson.M{"$set": tenant},
 	)
+	tenantCache.Delete(tenantCacheKey(tenant.ID))
 	if err != nil {
 		return err
 	}
@@ -110,6 +148,7 @@ func (r *tenantRepository) Delete(ctx context.Context, id string) error {
 			"deleted_at": now,
 		}},
 	)
+	tenantCache.Delete(tenantCacheKey(objectID))
 	if err != nil {
 		return err
 	}
@@ -1,9 +1,13 @@
 package tenant
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/shared/money"
 )
 
 // TenantSubscription información de suscripción embebida
@@ -26,6 +30,251 @@ type TenantUsage struct {
 	LastResetDate  time.Time `bson:"last_reset_date" json:"last_reset_date"`
 }
 
+// LoyaltySettings reglas de acumulación de puntos de fidelización
+type LoyaltySettings struct {
+	// Enabled activa la acumulación de puntos; los puntos ya acumulados se pueden redimir igual
+	Enabled bool `bson:"enabled" json:"enabled"`
+	// PointsPerVisit puntos otorgados por cada cita completada
+	PointsPerVisit int `bson:"points_per_visit" json:"points_per_visit"`
+	// PointsPerCurrencyUnit puntos por unidad de moneda en facturas pagadas (p. ej. 0.001 = 1 punto por cada 1000)
+	PointsPerCurrencyUnit float64 `bson:"points_per_currency_unit" json:"points_per_currency_unit"`
+}
+
+// CalendarStyle color y etiqueta con que el calendario pinta una cita
+type CalendarStyle struct {
+	Color string `bson:"color,omitempty" json:"color,omitempty"` // hexadecimal, p. ej. "#3B82F6"
+	Label string `bson:"label,omitempty" json:"label,omitempty"`
+}
+
+// CalendarSettings estilos propios de la clínica que reemplazan los valores por
+// defecto, por tipo, estado o prioridad de la cita
+type CalendarSettings struct {
+	Types      map[string]CalendarStyle `bson:"types,omitempty" json:"types,omitempty"`
+	Statuses   map[string]CalendarStyle `bson:"statuses,omitempty" json:"statuses,omitempty"`
+	Priorities map[string]CalendarStyle `bson:"priorities,omitempty" json:"priorities,omitempty"`
+}
+
+// AppointmentDeposit anticipo que un tipo de cita exige antes de quedar agendada
+type AppointmentDeposit struct {
+	// Amount monto en unidades menores (centavos) de Currency
+	Amount int64 `bson:"amount" json:"amount"`
+	// Currency moneda de la clínica al configurar el anticipo
+	Currency string `bson:"currency,omitempty" json:"currency,omitempty"`
+	// ExpiresAfterMinutes plazo para pagar antes de liberar el horario (60 si es 0)
+	ExpiresAfterMinutes int `bson:"expires_after_minutes" json:"expires_after_minutes"`
+}
+
+// ToResponse convierte el anticipo a su representación en la API
+func (d AppointmentDeposit) ToResponse() AppointmentDepositResponse {
+	return AppointmentDepositResponse{
+		Amount:              money.New(d.Amount, d.Currency),
+		ExpiresAfterMinutes: d.ExpiresAfterMinutes,
+	}
+}
+
+// TenantSettings preferencias operativas de la clínica
+type TenantSettings struct {
+	// AutoWriteOffExpired da de baja automáticamente el stock de productos vencidos
+	AutoWriteOffExpired bool `bson:"auto_writeoff_expired" json:"auto_writeoff_expired"`
+	// AutoConfirmAppointments crea las citas del staff directamente como confirmadas
+	AutoConfirmAppointments bool `bson:"auto_confirm_appointments" json:"auto_confirm_appointments"`
+	// DefaultLocale idioma de las notificaciones para propietarios sin preferencia ("es" si está vacío)
+	DefaultLocale string `bson:"default_locale,omitempty" json:"default_locale,omitempty"`
+	// MaxAdvanceBookingDays cuántos días a futuro se pueden agendar citas (0 = sin límite)
+	MaxAdvanceBookingDays int `bson:"max_advance_booking_days" json:"max_advance_booking_days"`
+	// MinBookingNoticeHours antelación mínima en horas para agendar una cita (0 = sin mínimo)
+	MinBookingNoticeHours int `bson:"min_booking_notice_hours" json:"min_booking_notice_hours"`
+	// AllowOwnerConfirmation permite que el propietario confirme una cita agendada al acusar recibo del recordatorio
+	AllowOwnerConfirmation bool `bson:"allow_owner_confirmation" json:"allow_owner_confirmation"`
+	// PreventPatientOverlap rechaza citas de un paciente que se crucen con otra suya, aunque sean con otro veterinario
+	PreventPatientOverlap bool `bson:"prevent_patient_overlap" json:"prevent_patient_overlap"`
+	// PatientAppointmentGapMinutes separación mínima entre dos citas del mismo paciente cuando PreventPatientOverlap está activo
+	PatientAppointmentGapMinutes int `bson:"patient_appointment_gap_minutes" json:"patient_appointment_gap_minutes"`
+	// LateArrivalToleranceMinutes cuánto tarde puede llegar un paciente y aún correr el inicio de su cita (0 = no se permite)
+	LateArrivalToleranceMinutes int `bson:"late_arrival_tolerance_minutes" json:"late_arrival_tolerance_minutes"`
+	// SlotGranularityMinutes obliga a que las citas empiecen en múltiplos de estos minutos, p. ej. 15 para :00/:15/:30/:45 (0 = cualquier hora)
+	SlotGranularityMinutes int `bson:"slot_granularity_minutes" json:"slot_granularity_minutes"`
+	// RequireIntakeForFirstAppointment no deja confirmar la primera cita de un cliente nuevo hasta que llene el formulario de ingreso
+	RequireIntakeForFirstAppointment bool `bson:"require_intake_first_appointment" json:"require_intake_first_appointment"`
+	// RequireVerifiedContacts solo envía email/SMS a propietarios que verificaron ese contacto
+	RequireVerifiedContacts bool `bson:"require_verified_contacts" json:"require_verified_contacts"`
+	// PaymentProvider proveedor para cobrar facturas a propietarios (vacío = proveedor por defecto del servidor)
+	PaymentProvider string `bson:"payment_provider,omitempty" json:"payment_provider,omitempty"`
+	// Loyalty reglas del programa de puntos para propietarios
+	Loyalty LoyaltySettings `bson:"loyalty" json:"loyalty"`
+	// Calendar colores y etiquetas de las citas en el calendario
+	Calendar CalendarSettings `bson:"calendar" json:"calendar"`
+	// AppointmentDeposits anticipos por tipo de cita; los tipos sin entrada no exigen anticipo
+	AppointmentDeposits map[string]AppointmentDeposit `bson:"appointment_deposits,omitempty" json:"appointment_deposits,omitempty"`
+	// QuietHours franja en la que no se envían push, email ni SMS a propietarios
+	QuietHours QuietHoursSettings `bson:"quiet_hours" json:"quiet_hours"`
+	// ReminderDelivery cómo se envían los recordatorios de vencimiento: uno por ítem o un resumen diario por propietario
+	ReminderDelivery string `bson:"reminder_delivery,omitempty" json:"reminder_delivery,omitempty"`
+	// AllowOwnerReschedule permite que el propietario mueva él mismo una cita agendada o confirmada; si no, su cambio queda como solicitud para el staff
+	AllowOwnerReschedule bool `bson:"allow_owner_reschedule" json:"allow_owner_reschedule"`
+	// OwnerRescheduleCutoffHours horas antes de la cita a partir de las cuales el propietario ya no puede reprogramarla (0 = sin límite)
+	OwnerRescheduleCutoffHours int `bson:"owner_reschedule_cutoff_hours" json:"owner_reschedule_cutoff_hours"`
+	// MaxRecordAttachments cuántos adjuntos admite cada historia clínica (DefaultMaxRecordAttachments si es 0)
+	MaxRecordAttachments int `bson:"max_record_attachments,omitempty" json:"max_record_attachments,omitempty"`
+	// InvoiceNumberFormat formato del número de factura, p. ej. "FAC-{yyyy}-{seq:6}" (vacío = formato por defecto)
+	InvoiceNumberFormat string `bson:"invoice_number_format,omitempty" json:"invoice_number_format,omitempty"`
+	// CertificateNumberFormat formato del número de certificado de vacunación; siempre se le agrega un sufijo aleatorio (vacío = formato por defecto)
+	CertificateNumberFormat string `bson:"certificate_number_format,omitempty" json:"certificate_number_format,omitempty"`
+	// AppointmentAutoAssign estrategia para asignar veterinario a las solicitudes de cita desde la app (vacío = AutoAssignNone)
+	AppointmentAutoAssign string `bson:"appointment_auto_assign,omitempty" json:"appointment_auto_assign,omitempty"`
+	// StaleAppointmentAlertMinutes minutos después del fin de una cita aún activa para pedirle al veterinario que actualice su estado (0 = no avisar)
+	StaleAppointmentAlertMinutes int `bson:"stale_appointment_alert_minutes" json:"stale_appointment_alert_minutes"`
+	// AutoCompleteStaleAppointments completa las citas en curso que pasan ese umbral; las que nunca iniciaron solo se avisan
+	AutoCompleteStaleAppointments bool `bson:"auto_complete_stale_appointments" json:"auto_complete_stale_appointments"`
+	// Priority efectos de la prioridad de las citas (antelación, avisos, cola de atención, triage)
+	Priority PrioritySettings `bson:"priority" json:"priority"`
+	// RequireStockAdjustmentApproval deja pendientes los ajustes de inventario grandes hasta que otro usuario los apruebe
+	RequireStockAdjustmentApproval bool `bson:"require_stock_adjustment_approval" json:"require_stock_adjustment_approval"`
+	// StockAdjustmentApprovalThreshold unidades a partir de las cuales un ajuste necesita aprobación (0 = todos los ajustes)
+	StockAdjustmentApprovalThreshold int `bson:"stock_adjustment_approval_threshold" json:"stock_adjustment_approval_threshold"`
+	// SevereAllergyAlerts quién recibe las alertas de alergias severas, además de los veterinarios de las próximas citas del paciente
+	SevereAllergyAlerts AlertRecipients `bson:"severe_allergy_alerts" json:"severe_allergy_alerts"`
+	// AutoDraftInvoiceOnCompletion crea una factura en borrador para el propietario al completar una cita, con la tarifa del tipo de cita y los productos dispensados
+	AutoDraftInvoiceOnCompletion bool `bson:"auto_draft_invoice_on_completion" json:"auto_draft_invoice_on_completion"`
+	// AgeReminders avisos a los propietarios cuando su mascota cumple una edad, p. ej. chequeo geriátrico a los 7 años (vacío = sin avisos)
+	AgeReminders []AgeReminder `bson:"age_reminders,omitempty" json:"age_reminders,omitempty"`
+	// OverrunNoticeMinutes retraso estimado, en minutos, a partir del cual se avisa a los propietarios de las siguientes citas del veterinario cuando una cita en curso se pasa de su hora de fin (0 = no avisar)
+	OverrunNoticeMinutes int `bson:"overrun_notice_minutes" json:"overrun_notice_minutes"`
+	// OverrunNoticeIntervalMinutes minutos mínimos entre dos avisos de retraso de la misma cita (0 = DefaultOverrunNoticeIntervalMinutes)
+	OverrunNoticeIntervalMinutes int `bson:"overrun_notice_interval_minutes,omitempty" json:"overrun_notice_interval_minutes,omitempty"`
+	// StockOutReasons motivos propios de la clínica para las salidas de inventario, además de los predefinidos (vacío = solo los predefinidos)
+	StockOutReasons []string `bson:"stock_out_reasons,omitempty" json:"stock_out_reasons,omitempty"`
+}
+
+// AgeReminder cuidado que se recomienda al propietario cuando la mascota
+// cumple AgeMonths meses. Cada paciente recibe cada aviso una sola vez.
+type AgeReminder struct {
+	// SpeciesID especie a la que aplica (nil = todas)
+	SpeciesID *primitive.ObjectID `bson:"species_id,omitempty" json:"species_id,omitempty"`
+	// AgeMonths edad en meses que dispara el aviso, p. ej. 84 para 7 años
+	AgeMonths int `bson:"age_months" json:"age_months"`
+	// Label cuidado recomendado, p. ej. "chequeo geriátrico"
+	Label string `bson:"label" json:"label"`
+}
+
+// Key identifica el aviso entre los ya enviados a un paciente
+func (r AgeReminder) Key() string {
+	species := "all"
+	if r.SpeciesID != nil {
+		species = r.SpeciesID.Hex()
+	}
+	return fmt.Sprintf("%s:%d", species, r.AgeMonths)
+}
+
+// AlertRecipients destinatarios de una alerta para el staff: todos los
+// usuarios activos con alguno de los roles (por nombre) más usuarios puntuales.
+// Si no se resuelve ninguno, la alerta se envía a todo el staff.
+type AlertRecipients struct {
+	Roles   []string             `bson:"roles,omitempty" json:"roles,omitempty"`
+	UserIDs []primitive.ObjectID `bson:"user_ids,omitempty" json:"user_ids,omitempty"`
+}
+
+// NeedsStockAdjustmentApproval indica si un ajuste de inventario de esa cantidad debe esperar aprobación
+func (s TenantSettings) NeedsStockAdjustmentApproval(quantity int) bool {
+	return s.RequireStockAdjustmentApproval && quantity > s.StockAdjustmentApprovalThreshold
+}
+
+// DefaultMaxRecordAttachments límite de adjuntos por historia clínica cuando la clínica no define uno
+const DefaultMaxRecordAttachments = 10
+
+// RecordAttachmentLimit devuelve el límite efectivo de adjuntos por historia clínica
+func (s TenantSettings) RecordAttachmentLimit() int {
+	if s.MaxRecordAttachments <= 0 {
+		return DefaultMaxRecordAttachments
+	}
+	return s.MaxRecordAttachments
+}
+
+// DefaultOverrunNoticeIntervalMinutes separación entre avisos de retraso cuando la clínica no define una
+const DefaultOverrunNoticeIntervalMinutes = 30
+
+// AcceptsStockOutReason indica si la clínica definió ese motivo para las salidas de inventario
+func (s TenantSettings) AcceptsStockOutReason(reason string) bool {
+	return slices.Contains(s.StockOutReasons, reason)
+}
+
+// OverrunNoticeInterval devuelve la separación efectiva entre avisos de retraso de una misma cita
+func (s TenantSettings) OverrunNoticeInterval() time.Duration {
+	minutes := s.OverrunNoticeIntervalMinutes
+	if minutes <= 0 {
+		minutes = DefaultOverrunNoticeIntervalMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Estrategias de AppointmentAutoAssign; vacío equivale a AutoAssignNone
+const (
+	AutoAssignNone        = "none"
+	AutoAssignRoundRobin  = "round_robin"
+	AutoAssignLeastLoaded = "least_loaded"
+	AutoAssignSpecialty   = "by_specialty"
+)
+
+// Modos de ReminderDelivery; vacío equivale a ReminderDeliveryPerItem
+const (
+	ReminderDeliveryPerItem = "per_item"
+	ReminderDeliveryDigest  = "digest"
+)
+
+// PrioritySettings define desde qué prioridad de cita ("low", "normal", "high"
+// o "emergency") aplica cada efecto. Vacío usa el valor por defecto y
+// PriorityNone desactiva el efecto.
+type PrioritySettings struct {
+	// BypassNoticeFrom exime de la antelación mínima para agendar
+	BypassNoticeFrom string `bson:"bypass_notice_from,omitempty" json:"bypass_notice_from,omitempty"`
+	// UrgentAlertFrom marca como urgentes los avisos al staff sobre la cita
+	UrgentAlertFrom string `bson:"urgent_alert_from,omitempty" json:"urgent_alert_from,omitempty"`
+	// QueueFirstFrom pone la cita al frente de la cola de atención del día
+	QueueFirstFrom string `bson:"queue_first_from,omitempty" json:"queue_first_from,omitempty"`
+	// OwnerMaxPriority prioridad máxima que un propietario asigna desde la app;
+	// si pide una mayor, la cita queda con esta y pasa a triage del staff
+	OwnerMaxPriority string `bson:"owner_max_priority,omitempty" json:"owner_max_priority,omitempty"`
+}
+
+// PriorityNone desactiva un efecto de PrioritySettings
+const PriorityNone = "none"
+
+// Valores por defecto de PrioritySettings
+const (
+	DefaultBypassNoticeFrom = "high"
+	DefaultUrgentAlertFrom  = "emergency"
+	DefaultQueueFirstFrom   = "high"
+	DefaultOwnerMaxPriority = "normal"
+)
+
+// Effective devuelve la configuración con los valores por defecto aplicados
+func (p PrioritySettings) Effective() PrioritySettings {
+	if p.BypassNoticeFrom == "" {
+		p.BypassNoticeFrom = DefaultBypassNoticeFrom
+	}
+	if p.UrgentAlertFrom == "" {
+		p.UrgentAlertFrom = DefaultUrgentAlertFrom
+	}
+	if p.QueueFirstFrom == "" {
+		p.QueueFirstFrom = DefaultQueueFirstFrom
+	}
+	if p.OwnerMaxPriority == "" {
+		p.OwnerMaxPriority = DefaultOwnerMaxPriority
+	}
+	return p
+}
+
+// QuietHoursSettings franja diaria (HH:MM, zona horaria de la clínica) en la que
+// las notificaciones a propietarios se difieren hasta su fin. Si Start es mayor
+// que End la franja cruza la medianoche. Desactivada por defecto.
+type QuietHoursSettings struct {
+	Enabled bool   `bson:"enabled" json:"enabled"`
+	Start   string `bson:"start,omitempty" json:"start,omitempty"`
+	End     string `bson:"end,omitempty" json:"end,omitempty"`
+	// UrgentTypes tipos de notificación que se envían aun dentro de la franja
+	UrgentTypes []string `bson:"urgent_types,omitempty" json:"urgent_types,omitempty"`
+}
+
 type Tenant struct {
 	ID                   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	OwnerID              primitive.ObjectID `bson:"owner_id" json:"owner_id"`
@@ -54,6 +303,9 @@ type Tenant struct {
 	
 	// Uso (embebido)
 	Usage TenantUsage `bson:"usage" json:"usage"`
+
+	// Configuración (embebido)
+	Settings TenantSettings `bson:"settings" json:"settings"`
 	
 	// Estado
 	Status    TenantStatus `bson:"status" json:"status"`
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,8 +15,10 @@ import (
 	"github.com/eren_dev/go_server/internal/modules/audit"
 	"github.com/eren_dev/go_server/internal/modules/payments"
 	"github.com/eren_dev/go_server/internal/modules/plans"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/platform/payment"
+	"github.com/eren_dev/go_server/internal/shared/money"
 )
 
 var ErrPlanNotVisible = errors.New("plan is not available for purchase")
@@ -263,6 +267,149 @@ func (s *TenantService) Update(ctx context.Context, id string, dto *UpdateTenant
 	if dto.Logo != "" {
 		tenant.Logo = dto.Logo
 	}
+	if dto.AutoWriteOffExpired != nil {
+		tenant.Settings.AutoWriteOffExpired = *dto.AutoWriteOffExpired
+	}
+	if dto.AutoConfirmAppointments != nil {
+		tenant.Settings.AutoConfirmAppointments = *dto.AutoConfirmAppointments
+	}
+	if dto.DefaultLocale != "" {
+		tenant.Settings.DefaultLocale = dto.DefaultLocale
+	}
+	if dto.MaxAdvanceBookingDays != nil {
+		tenant.Settings.MaxAdvanceBookingDays = *dto.MaxAdvanceBookingDays
+	}
+	if dto.MinBookingNoticeHours != nil {
+		tenant.Settings.MinBookingNoticeHours = *dto.MinBookingNoticeHours
+	}
+	if dto.AllowOwnerConfirmation != nil {
+		tenant.Settings.AllowOwnerConfirmation = *dto.AllowOwnerConfirmation
+	}
+	if dto.PreventPatientOverlap != nil {
+		tenant.Settings.PreventPatientOverlap = *dto.PreventPatientOverlap
+	}
+	if dto.PatientAppointmentGap != nil {
+		tenant.Settings.PatientAppointmentGapMinutes = *dto.PatientAppointmentGap
+	}
+	if dto.LateArrivalTolerance != nil {
+		tenant.Settings.LateArrivalToleranceMinutes = *dto.LateArrivalTolerance
+	}
+	if dto.SlotGranularity != nil {
+		tenant.Settings.SlotGranularityMinutes = *dto.SlotGranularity
+	}
+	if dto.RequireIntake != nil {
+		tenant.Settings.RequireIntakeForFirstAppointment = *dto.RequireIntake
+	}
+	if dto.RequireVerifiedContacts != nil {
+		tenant.Settings.RequireVerifiedContacts = *dto.RequireVerifiedContacts
+	}
+	if dto.InvoicePaymentProvider != "" {
+		tenant.Settings.PaymentProvider = dto.InvoicePaymentProvider
+	}
+	if dto.LoyaltyEnabled != nil {
+		tenant.Settings.Loyalty.Enabled = *dto.LoyaltyEnabled
+	}
+	if dto.LoyaltyPointsPerVisit != nil {
+		tenant.Settings.Loyalty.PointsPerVisit = *dto.LoyaltyPointsPerVisit
+	}
+	if dto.LoyaltyPointsPerUnit != nil {
+		tenant.Settings.Loyalty.PointsPerCurrencyUnit = *dto.LoyaltyPointsPerUnit
+	}
+	if dto.CalendarTypeStyles != nil {
+		tenant.Settings.Calendar.Types = mergeCalendarStyles(tenant.Settings.Calendar.Types, dto.CalendarTypeStyles)
+	}
+	if dto.CalendarStatusStyles != nil {
+		tenant.Settings.Calendar.Statuses = mergeCalendarStyles(tenant.Settings.Calendar.Statuses, dto.CalendarStatusStyles)
+	}
+	if dto.CalendarPriorityStyles != nil {
+		tenant.Settings.Calendar.Priorities = mergeCalendarStyles(tenant.Settings.Calendar.Priorities, dto.CalendarPriorityStyles)
+	}
+	if dto.AppointmentDeposits != nil {
+		tenant.Settings.AppointmentDeposits = mergeAppointmentDeposits(tenant.Settings.AppointmentDeposits, dto.AppointmentDeposits, tenant.Currency)
+	}
+	if q := dto.QuietHours; q != nil {
+		if q.Enabled && (q.Start == "" || q.End == "" || q.Start == q.End) {
+			return nil, ErrInvalidQuietHours
+		}
+		tenant.Settings.QuietHours = QuietHoursSettings{Enabled: q.Enabled, Start: q.Start, End: q.End, UrgentTypes: q.UrgentTypes}
+	}
+	if dto.ReminderDelivery != "" {
+		tenant.Settings.ReminderDelivery = dto.ReminderDelivery
+	}
+	if dto.AllowOwnerReschedule != nil {
+		tenant.Settings.AllowOwnerReschedule = *dto.AllowOwnerReschedule
+	}
+	if dto.OwnerRescheduleCutoffHours != nil {
+		tenant.Settings.OwnerRescheduleCutoffHours = *dto.OwnerRescheduleCutoffHours
+	}
+	if dto.MaxRecordAttachments != nil {
+		tenant.Settings.MaxRecordAttachments = *dto.MaxRecordAttachments
+	}
+	if dto.InvoiceNumberFormat != "" {
+		if err := sequences.ValidateFormat(dto.InvoiceNumberFormat); err != nil {
+			return nil, ErrInvalidNumberFormat
+		}
+		tenant.Settings.InvoiceNumberFormat = dto.InvoiceNumberFormat
+	}
+	if dto.CertificateNumberFormat != "" {
+		if err := sequences.ValidateFormat(dto.CertificateNumberFormat); err != nil {
+			return nil, ErrInvalidNumberFormat
+		}
+		tenant.Settings.CertificateNumberFormat = dto.CertificateNumberFormat
+	}
+	if dto.AppointmentAutoAssign != "" {
+		tenant.Settings.AppointmentAutoAssign = dto.AppointmentAutoAssign
+	}
+	if dto.StaleAppointmentAlertMinutes != nil {
+		tenant.Settings.StaleAppointmentAlertMinutes = *dto.StaleAppointmentAlertMinutes
+	}
+	if dto.AutoCompleteStaleAppointments != nil {
+		tenant.Settings.AutoCompleteStaleAppointments = *dto.AutoCompleteStaleAppointments
+	}
+	if p := dto.Priority; p != nil {
+		tenant.Settings.Priority = PrioritySettings{
+			BypassNoticeFrom: p.BypassNoticeFrom,
+			UrgentAlertFrom:  p.UrgentAlertFrom,
+			QueueFirstFrom:   p.QueueFirstFrom,
+			OwnerMaxPriority: p.OwnerMaxPriority,
+		}
+	}
+	if dto.RequireStockAdjustmentApproval != nil {
+		tenant.Settings.RequireStockAdjustmentApproval = *dto.RequireStockAdjustmentApproval
+	}
+	if dto.StockAdjustmentApprovalThreshold != nil {
+		tenant.Settings.StockAdjustmentApprovalThreshold = *dto.StockAdjustmentApprovalThreshold
+	}
+	if a := dto.SevereAllergyAlerts; a != nil {
+		userIDs := make([]primitive.ObjectID, 0, len(a.UserIDs))
+		for _, hex := range a.UserIDs {
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return nil, ErrInvalidAlertUserID
+			}
+			userIDs = append(userIDs, id)
+		}
+		tenant.Settings.SevereAllergyAlerts = AlertRecipients{Roles: a.Roles, UserIDs: userIDs}
+	}
+	if dto.AutoDraftInvoiceOnCompletion != nil {
+		tenant.Settings.AutoDraftInvoiceOnCompletion = *dto.AutoDraftInvoiceOnCompletion
+	}
+	if dto.AgeReminders != nil {
+		reminders, err := toAgeReminders(dto.AgeReminders)
+		if err != nil {
+			return nil, err
+		}
+		tenant.Settings.AgeReminders = reminders
+	}
+	if dto.OverrunNoticeMinutes != nil {
+		tenant.Settings.OverrunNoticeMinutes = *dto.OverrunNoticeMinutes
+	}
+	if dto.OverrunNoticeIntervalMinutes != nil {
+		tenant.Settings.OverrunNoticeIntervalMinutes = *dto.OverrunNoticeIntervalMinutes
+	}
+	if dto.StockOutReasons != nil {
+		tenant.Settings.StockOutReasons = toStockOutReasons(dto.StockOutReasons)
+	}
 
 	tenant.UpdatedAt = time.Now()
 
@@ -272,6 +419,80 @@ func (s *TenantService) Update(ctx context.Context, id string, dto *UpdateTenant
 	return ToResponse(tenant), nil
 }
 
+// mergeCalendarStyles aplica los estilos recibidos sobre los actuales; un
+// estilo sin color ni etiqueta elimina la clave y vuelve al valor por defecto
+func mergeCalendarStyles(current map[string]CalendarStyle, updates map[string]CalendarStyleDTO) map[string]CalendarStyle {
+	merged := make(map[string]CalendarStyle, len(current)+len(updates))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range updates {
+		if v.Color == "" && v.Label == "" {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = CalendarStyle{Color: v.Color, Label: v.Label}
+	}
+	return merged
+}
+
+// mergeAppointmentDeposits aplica los anticipos recibidos, convertidos a
+// centavos de currency, sobre los actuales; un monto 0 elimina el tipo y sus
+// citas vuelven a agendarse sin anticipo
+func mergeAppointmentDeposits(current map[string]AppointmentDeposit, updates map[string]AppointmentDepositDTO, currency string) map[string]AppointmentDeposit {
+	merged := make(map[string]AppointmentDeposit, len(current)+len(updates))
+	for k, v := range current {
+		merged[k] = v
+	}
+	currency = money.Normalize(currency)
+	for k, v := range updates {
+		amount := money.FromMajor(v.Amount, currency)
+		if amount <= 0 {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = AppointmentDeposit{Amount: amount, Currency: currency, ExpiresAfterMinutes: v.ExpiresAfterMinutes}
+	}
+	return merged
+}
+
+// toAgeReminders convierte los avisos por edad recibidos, rechazando especies
+// inválidas y avisos repetidos para la misma especie y edad
+func toAgeReminders(dtos []AgeReminderDTO) ([]AgeReminder, error) {
+	reminders := make([]AgeReminder, 0, len(dtos))
+	seen := make(map[string]bool, len(dtos))
+	for _, d := range dtos {
+		reminder := AgeReminder{AgeMonths: d.AgeMonths, Label: d.Label}
+		if d.SpeciesID != "" {
+			id, err := primitive.ObjectIDFromHex(d.SpeciesID)
+			if err != nil {
+				return nil, ErrInvalidAgeReminders
+			}
+			reminder.SpeciesID = &id
+		}
+		if seen[reminder.Key()] {
+			return nil, ErrInvalidAgeReminders
+		}
+		seen[reminder.Key()] = true
+		reminders = append(reminders, reminder)
+	}
+	return reminders, nil
+}
+
+// toStockOutReasons normaliza los motivos propios de salida de inventario:
+// minúsculas, sin espacios sobrantes ni repetidos
+func toStockOutReasons(in []string) []string {
+	reasons := make([]string, 0, len(in))
+	for _, r := range in {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if r == "" || slices.Contains(reasons, r) {
+			continue
+		}
+		reasons = append(reasons, r)
+	}
+	return reasons
+}
+
 func (s *TenantService) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
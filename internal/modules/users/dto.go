@@ -24,6 +24,10 @@ type UpdateUserDTO struct {
 	Name string `json:"name" example:"Jane Doe"`
 	// Email del usuario
 	Email string `json:"email" binding:"omitempty,email" example:"jane@example.com"`
+	// Especialidades; si viene reemplaza las actuales ([] las borra)
+	Specialties []string `json:"specialties" binding:"omitempty,max=10,dive,min=2,max=50" example:"cirugía,dermatología"`
+	// URL de la foto de perfil
+	AvatarURL string `json:"avatar_url" binding:"omitempty,url" example:"https://example.com/avatar.png"`
 }
 
 // UserResponse respuesta de usuario
@@ -35,6 +39,10 @@ type UserResponse struct {
 	Name string `json:"name" example:"John Doe"`
 	// Email del usuario
 	Email string `json:"email" example:"john@example.com"`
+	// Especialidades del usuario
+	Specialties []string `json:"specialties,omitempty" example:"cirugía,dermatología"`
+	// URL de la foto de perfil
+	AvatarURL string `json:"avatar_url,omitempty" example:"https://example.com/avatar.png"`
 	// Fecha de creación
 	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	// Fecha de actualización
@@ -52,11 +60,13 @@ type PaginatedUsersResponse struct {
 
 func ToResponse(u *User) *UserResponse {
 	return &UserResponse{
-		ID:        u.ID.Hex(),
-		Name:      u.Name,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:          u.ID.Hex(),
+		Name:        u.Name,
+		Email:       u.Email,
+		Specialties: u.Specialties,
+		AvatarURL:   u.AvatarURL,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
 	}
 }
 
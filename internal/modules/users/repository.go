@@ -23,6 +23,9 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	Update(ctx context.Context, id string, dto *UpdateUserDTO) (*User, error)
 	Delete(ctx context.Context, id string) error
+	UpdateNotificationPrefs(ctx context.Context, id string, prefs NotificationPreferences) error
+	FindWeeklyDigestSubscribers(ctx context.Context) ([]*User, error)
+	RemoveTenant(ctx context.Context, id string, tenantID primitive.ObjectID) error
 }
 
 type userRepository struct {
@@ -179,6 +182,12 @@ func (r *userRepository) Update(ctx context.Context, id string, dto *UpdateUserD
 	if dto.Email != "" {
 		update["$set"].(bson.M)["email"] = dto.Email
 	}
+	if dto.Specialties != nil {
+		update["$set"].(bson.M)["specialties"] = dto.Specialties
+	}
+	if dto.AvatarURL != "" {
+		update["$set"].(bson.M)["avatar_url"] = dto.AvatarURL
+	}
 
 	var user User
 	err = r.collection.FindOneAndUpdate(
@@ -223,3 +232,66 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (r *userRepository) UpdateNotificationPrefs(ctx context.Context, id string, prefs NotificationPreferences) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"notification_prefs": prefs, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// RemoveTenant revokes the user's membership in a clinic; the account and its
+// access to other clinics are untouched.
+func (r *userRepository) RemoveTenant(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{
+			"$pull": bson.M{"tenant_ids": tenantID},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// FindWeeklyDigestSubscribers returns active users opted in to the weekly schedule digest.
+func (r *userRepository) FindWeeklyDigestSubscribers(ctx context.Context) ([]*User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"deleted_at":                       nil,
+		"notification_prefs.weekly_digest": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -15,7 +15,17 @@ type User struct {
 	TenantIds []primitive.ObjectID `bson:"tenant_ids"`
 	RoleIds      []primitive.ObjectID `bson:"role_ids"`
 	IsSuperAdmin bool             `bson:"is_super_admin"`
+	// Specialties and AvatarURL are shown in the staff directory and the owners' vet picker.
+	Specialties []string `bson:"specialties,omitempty"`
+	AvatarURL   string   `bson:"avatar_url,omitempty"`
+	// NotificationPrefs defaults to the zero value (everything opted out) for existing documents.
+	NotificationPrefs NotificationPreferences `bson:"notification_prefs"`
 	CreatedAt time.Time          `bson:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
 	DeletedAt *time.Time          `bson:"deleted_at,omitempty"`
 }
+
+// NotificationPreferences holds the staff member's opt-in email notifications.
+type NotificationPreferences struct {
+	WeeklyDigest bool `bson:"weekly_digest" json:"weekly_digest"`
+}
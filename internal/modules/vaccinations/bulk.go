@@ -0,0 +1,125 @@
+package vaccinations
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/patients"
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+)
+
+// CreateBulkVaccinations records one vaccine for many patients. The vet,
+// dates and every patient are checked before anything is written, so a typo
+// in one ID does not leave half the group recorded. After that each patient
+// succeeds or fails on its own (e.g. a species the vaccine is not for), and
+// each owner gets a single notification for all of their animals.
+func (s *Service) CreateBulkVaccinations(ctx context.Context, dto *BulkVaccinationDTO, tenantID primitive.ObjectID) (*BulkVaccinationResponse, error) {
+	vetID, applicationDate, nextDueDate, err := s.validateApplication(ctx, dto.forPatient(""))
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.loadBulkPatients(ctx, dto.PatientIDs, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &BulkVaccinationResponse{Results: make([]BulkVaccinationResult, 0, len(group))}
+	recorded := make(map[primitive.ObjectID][]*Vaccination)
+	names := make(map[primitive.ObjectID][]string)
+	var ownerOrder []primitive.ObjectID
+
+	for _, patient := range group {
+		result := BulkVaccinationResult{PatientID: patient.ID.Hex()}
+
+		vaccination, err := s.bulkVaccinate(ctx, dto, patient, vetID, applicationDate, nextDueDate, tenantID)
+		if err != nil {
+			result.Error = err.Error()
+			var appErr *sharedErrors.Error
+			if errors.As(err, &appErr) {
+				result.ErrorCode = appErr.Code
+			}
+			response.Failed++
+		} else {
+			result.Vaccination = vaccination.ToResponse()
+			response.Created++
+
+			if _, seen := recorded[patient.OwnerID]; !seen {
+				ownerOrder = append(ownerOrder, patient.OwnerID)
+			}
+			recorded[patient.OwnerID] = append(recorded[patient.OwnerID], vaccination)
+			names[patient.OwnerID] = append(names[patient.OwnerID], patient.Name)
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	for _, ownerID := range ownerOrder {
+		vaccinations := recorded[ownerID]
+		ids := make([]string, len(vaccinations))
+		patientIDs := make([]string, len(vaccinations))
+		for i, v := range vaccinations {
+			ids[i] = v.ID.Hex()
+			patientIDs[i] = v.PatientID.Hex()
+		}
+		s.notificationSvc.Send(ctx, &notifications.SendDTO{
+			OwnerID:  ownerID.Hex(),
+			TenantID: tenantID.Hex(),
+			Type:     notifications.TypeVaccinationDue,
+			Template: notifications.TemplateVaccinationRegistered,
+			Vars:     map[string]string{"vaccine_name": dto.VaccineName, "patient_name": strings.Join(names[ownerID], ", ")},
+			Data: map[string]string{
+				"vaccination_ids": strings.Join(ids, ","),
+				"patient_ids":     strings.Join(patientIDs, ","),
+				"vaccine_name":    dto.VaccineName,
+			},
+			SendPush: true,
+		})
+	}
+
+	return response, nil
+}
+
+// bulkVaccinate records the vaccine for one patient of a bulk request
+func (s *Service) bulkVaccinate(ctx context.Context, dto *BulkVaccinationDTO, patient *patients.Patient, vetID primitive.ObjectID, applicationDate time.Time, nextDueDate *time.Time, tenantID primitive.ObjectID) (*Vaccination, error) {
+	if err := s.checkSpecies(ctx, dto.VaccineName, patient, tenantID); err != nil {
+		return nil, err
+	}
+	return s.recordVaccination(ctx, dto.forPatient(patient.ID.Hex()), patient, vetID, applicationDate, nextDueDate, tenantID)
+}
+
+// loadBulkPatients resolves every patient of a bulk request, in order and
+// without repeats, failing with all the invalid or unknown IDs at once
+func (s *Service) loadBulkPatients(ctx context.Context, rawIDs []string, tenantID primitive.ObjectID) ([]*patients.Patient, error) {
+	seen := make(map[string]bool, len(rawIDs))
+	group := make([]*patients.Patient, 0, len(rawIDs))
+	var invalid, missing []string
+
+	for _, raw := range rawIDs {
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+
+		patientID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			invalid = append(invalid, raw)
+			continue
+		}
+		patient, err := s.patientRepo.FindByID(ctx, tenantID, patientID.Hex())
+		if err != nil {
+			missing = append(missing, raw)
+			continue
+		}
+		group = append(group, patient)
+	}
+
+	if len(invalid) > 0 || len(missing) > 0 {
+		return nil, ErrBulkPatients(invalid, missing)
+	}
+	return group, nil
+}
@@ -0,0 +1,133 @@
+package vaccinations
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+)
+
+// certificateRandomLen base32 characters (50 bits) make certificate numbers
+// unguessable, since anyone can verify them without authenticating
+const certificateRandomLen = 10
+
+// legacyCertificatePattern matches the numbers issued before the random
+// suffix existed, which were shared by every vaccination of a clinic on a day
+var legacyCertificatePattern = regexp.MustCompile(`^VAC-[0-9a-f]{4}-[0-9]{8}$`)
+
+// newCertificateNumber builds VAC-TENANT-YYYYMMDD-RANDOM,
+// e.g. VAC-507f-20260224-K3J9QX2M7P. New vaccinations are numbered from the
+// clinic's certificate sequence instead; this only reissues legacy numbers.
+func newCertificateNumber(tenantHex string, applicationDate time.Time) string {
+	return fmt.Sprintf("VAC-%s-%s-%s",
+		tenantHex[:4],
+		applicationDate.Format("20060102"),
+		rand.Text()[:certificateRandomLen],
+	)
+}
+
+// generateCertificateNumber draws the clinic's next certificate number and
+// appends a random suffix: certificates are verified without authenticating,
+// so a bare consecutive number would let anyone enumerate them
+func (s *Service) generateCertificateNumber(ctx context.Context, tenantID primitive.ObjectID) (string, error) {
+	var format string
+	if t, err := s.tenants.FindByID(ctx, tenantID.Hex()); err == nil {
+		format = t.Settings.CertificateNumberFormat
+	}
+
+	number, err := s.numbers.Next(ctx, tenantID, sequences.Certificate, format, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return number + "-" + rand.Text()[:certificateRandomLen], nil
+}
+
+// certificateNumberIndex makes certificate numbers unique; records without one
+// are left out of the index
+var certificateNumberIndex = mongo.IndexModel{
+	Keys: bson.D{{Key: "certificate_number", Value: 1}},
+	Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+		bson.D{{Key: "certificate_number", Value: bson.D{{Key: "$type", Value: "string"}}}},
+	),
+}
+
+// reissueLegacyCertificates gives every vaccination still carrying a legacy
+// number a new unique one, so the unique index can be built. Legacy numbers
+// could never be verified anyway, as they did not identify a single record.
+func reissueLegacyCertificates(ctx context.Context, collection *mongo.Collection) error {
+	filter := bson.M{"certificate_number": bson.M{"$regex": legacyCertificatePattern.String()}}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"tenant_id": 1, "application_date": 1, "certificate_number": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	reissued := 0
+	for cursor.Next(ctx) {
+		var v Vaccination
+		if err := cursor.Decode(&v); err != nil {
+			return err
+		}
+		update := bson.M{"$set": bson.M{"certificate_number": newCertificateNumber(v.TenantID.Hex(), v.ApplicationDate)}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": v.ID}, update); err != nil {
+			return err
+		}
+		reissued++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	if reissued > 0 {
+		slog.Info("reissued legacy vaccination certificate numbers", "count", reissued)
+	}
+	return nil
+}
+
+// VerifyCertificate checks a certificate number for third parties such as
+// boarding facilities. Unknown numbers and current vaccinations are told apart
+// only by Valid; nothing beyond the minimal payload is disclosed.
+func (s *Service) VerifyCertificate(ctx context.Context, certificateNumber string) (*CertificateVerificationResponse, error) {
+	certificateNumber = strings.TrimSpace(certificateNumber)
+	if certificateNumber == "" {
+		return nil, ErrValidation("certificate", "certificate number is required")
+	}
+
+	vaccination, err := s.repo.FindByCertificateNumber(ctx, certificateNumber)
+	if err != nil {
+		if err == ErrCertificateNotFound {
+			return &CertificateVerificationResponse{Valid: false}, nil
+		}
+		return nil, err
+	}
+
+	resp := &CertificateVerificationResponse{
+		Valid:             vaccination.NextDueDate == nil || vaccination.NextDueDate.After(time.Now()),
+		CertificateNumber: vaccination.CertificateNumber,
+		VaccineName:       vaccination.VaccineName,
+		ApplicationDate:   vaccination.ApplicationDate,
+		NextDueDate:       vaccination.NextDueDate,
+	}
+	if patient, err := s.patientRepo.FindByID(ctx, vaccination.TenantID, vaccination.PatientID.Hex()); err == nil {
+		resp.PatientName = firstName(patient.Name)
+	}
+	return resp, nil
+}
+
+func firstName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
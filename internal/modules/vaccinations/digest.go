@@ -0,0 +1,102 @@
+package vaccinations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// sendDueDigests sends one digest to each owner with two or more items due
+// today, counting the vaccinations due and any open appointments of the day,
+// and returns the vaccinations left for per-item reminders. Clinics not in
+// digest mode get every vaccination back untouched.
+func (s *Service) sendDueDigests(ctx context.Context, tenantID primitive.ObjectID, vaccinations []Vaccination) ([]Vaccination, error) {
+	if s.tenants == nil {
+		return vaccinations, nil
+	}
+	t, err := s.tenants.FindByID(ctx, tenantID.Hex())
+	if err != nil || t.Settings.ReminderDelivery != tenant.ReminderDeliveryDigest {
+		return vaccinations, nil
+	}
+
+	loc := time.UTC
+	if t.TimeZone != "" {
+		if l, err := time.LoadLocation(t.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	now := time.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	items := make(map[primitive.ObjectID][]notifications.DueItem)
+	dueToday := make(map[primitive.ObjectID]bool)
+	for _, v := range vaccinations {
+		if v.NextDueDate == nil || v.NextDueDate.After(dayEnd) {
+			continue
+		}
+		dueToday[v.ID] = true
+		items[v.OwnerID] = append(items[v.OwnerID], notifications.DueItem{
+			Kind: notifications.DueKindVaccination,
+			Data: map[string]string{
+				"vaccination_id": v.ID.Hex(),
+				"patient_id":     v.PatientID.Hex(),
+				"vaccine_name":   v.VaccineName,
+			},
+		})
+	}
+	if len(items) == 0 {
+		return vaccinations, nil
+	}
+
+	if s.appointments != nil {
+		todays, err := s.appointments.FindByDateRange(ctx, dayStart, dayEnd, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range todays {
+			if _, ok := items[a.OwnerID]; !ok {
+				continue
+			}
+			if a.DisableReminders || (a.Status != appointments.AppointmentStatusScheduled && a.Status != appointments.AppointmentStatusConfirmed) {
+				continue
+			}
+			items[a.OwnerID] = append(items[a.OwnerID], notifications.DueItem{
+				Kind: notifications.DueKindAppointment,
+				Data: map[string]string{
+					"appointment_id": a.ID.Hex(),
+					"patient_id":     a.PatientID.Hex(),
+					"scheduled_at":   a.ScheduledAt.Format(time.RFC3339),
+				},
+			})
+		}
+	}
+
+	digested := make(map[primitive.ObjectID]bool)
+	for ownerID, ownerItems := range items {
+		if len(ownerItems) < 2 {
+			continue
+		}
+		if err := s.notificationSvc.SendDueDigest(ctx, ownerID.Hex(), tenantID.Hex(), ownerItems); err != nil {
+			// fall back to per-item reminders for this owner
+			slog.Warn("failed to send due digest", "owner_id", ownerID.Hex(), "error", err)
+			continue
+		}
+		digested[ownerID] = true
+	}
+
+	remaining := make([]Vaccination, 0, len(vaccinations))
+	for _, v := range vaccinations {
+		if dueToday[v.ID] && digested[v.OwnerID] {
+			continue
+		}
+		remaining = append(remaining, v)
+	}
+	return remaining, nil
+}
@@ -33,6 +33,49 @@ func (d *CreateVaccinationDTO) ParseNextDueDate() (*time.Time, error) {
 	return &t, nil
 }
 
+// BulkVaccinationDTO records the same vaccine for several patients seen in
+// one visit, e.g. a shelter or farm. They share the vet, dates and lot; each
+// gets its own certificate number.
+type BulkVaccinationDTO struct {
+	PatientIDs      []string `json:"patient_ids" binding:"required,min=1,max=100,dive,required"`
+	VeterinarianID  string   `json:"veterinarian_id" binding:"required"`
+	VaccineName     string   `json:"vaccine_name" binding:"required,min=1,max=100"`
+	Manufacturer    string   `json:"manufacturer" binding:"omitempty,max=100"`
+	LotNumber       string   `json:"lot_number" binding:"omitempty,max=50"`
+	ApplicationDate string   `json:"application_date" binding:"required"` // RFC3339
+	NextDueDate     string   `json:"next_due_date"`                       // RFC3339
+	Notes           string   `json:"notes" binding:"omitempty,max=500"`
+}
+
+// forPatient returns the single-patient form of the request
+func (d *BulkVaccinationDTO) forPatient(patientID string) *CreateVaccinationDTO {
+	return &CreateVaccinationDTO{
+		PatientID:       patientID,
+		VeterinarianID:  d.VeterinarianID,
+		VaccineName:     d.VaccineName,
+		Manufacturer:    d.Manufacturer,
+		LotNumber:       d.LotNumber,
+		ApplicationDate: d.ApplicationDate,
+		NextDueDate:     d.NextDueDate,
+		Notes:           d.Notes,
+	}
+}
+
+// BulkVaccinationResult is the outcome for one patient of a bulk request
+type BulkVaccinationResult struct {
+	PatientID   string               `json:"patient_id"`
+	Vaccination *VaccinationResponse `json:"vaccination,omitempty"`
+	ErrorCode   string               `json:"error_code,omitempty" example:"VACCINE_SPECIES_MISMATCH"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// BulkVaccinationResponse lists the outcome per patient, in request order
+type BulkVaccinationResponse struct {
+	Created int                     `json:"created" example:"11"`
+	Failed  int                     `json:"failed" example:"1"`
+	Results []BulkVaccinationResult `json:"results"`
+}
+
 // UpdateVaccinationDTO represents the request to update a vaccination
 type UpdateVaccinationDTO struct {
 	VaccineName     string `json:"vaccine_name" max:"100"`
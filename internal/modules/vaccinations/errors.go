@@ -2,7 +2,8 @@ package vaccinations
 
 import (
 	"errors"
-	"fmt"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
 )
 
 // Module errors
@@ -17,43 +18,86 @@ var (
 	ErrInvalidNextDueDate     = errors.New("next due date must be after application date")
 	ErrInvalidStatus          = errors.New("invalid vaccination status")
 	ErrInvalidDoseType        = errors.New("invalid dose type")
-	ErrSpeciesMismatch        = errors.New("vaccine is not for this species")
 	ErrCertificateNotFound    = errors.New("certificate not found")
+	ErrProtocolNotFound       = errors.New("vaccine protocol not found")
+	ErrProtocolNameExists     = errors.New("vaccine protocol name already exists")
 )
 
-// ValidationError represents a validation error
-type ValidationError struct {
-	Field   string
-	Message string
+// ErrValidation creates a new validation error
+func ErrValidation(field, message string) error {
+	return sharedErrors.Validation(field, message)
 }
 
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
+// ErrBusiness creates a new business error
+func ErrBusiness(code, message string) error {
+	return sharedErrors.Business(code, message)
 }
 
-// ErrValidation creates a new validation error
-func ErrValidation(field, message string) error {
-	return &ValidationError{
-		Field:   field,
-		Message: message,
+// ErrSpeciesMismatch is returned when the catalog entry of a vaccine targets
+// other species than the patient's
+func ErrSpeciesMismatch(vaccine, species string, targetSpecies []string) error {
+	return &sharedErrors.Error{
+		Kind:    sharedErrors.ErrUnprocessable,
+		Code:    "VACCINE_SPECIES_MISMATCH",
+		Message: "vaccine is not for this species",
+		Field:   "vaccine_name",
+		Details: map[string]interface{}{
+			"vaccine":        vaccine,
+			"species":        species,
+			"target_species": targetSpecies,
+		},
 	}
 }
 
-// BusinessError represents a business logic error
-type BusinessError struct {
-	Code    string
-	Message string
+// ErrProtocolSpeciesMismatch is returned when a protocol targets other species
+// than the patient's
+func ErrProtocolSpeciesMismatch(protocol, species string, targetSpecies []string) error {
+	return &sharedErrors.Error{
+		Kind:    sharedErrors.ErrUnprocessable,
+		Code:    "PROTOCOL_SPECIES_MISMATCH",
+		Message: "vaccine protocol is not for this species",
+		Field:   "protocol_id",
+		Details: map[string]interface{}{
+			"protocol":       protocol,
+			"species":        species,
+			"target_species": targetSpecies,
+		},
+	}
 }
 
-func (e *BusinessError) Error() string {
-	return fmt.Sprintf("business error: %s - %s", e.Code, e.Message)
+// ErrProtocolAgeMismatch is returned when the patient is too young or too old
+// for a protocol on its start date. A zero maximum means no upper limit.
+func ErrProtocolAgeMismatch(protocol string, ageWeeks, minWeeks, maxWeeks int) error {
+	return &sharedErrors.Error{
+		Kind:    sharedErrors.ErrUnprocessable,
+		Code:    "PROTOCOL_AGE_MISMATCH",
+		Message: "patient age does not fit the vaccine protocol",
+		Field:   "start_date",
+		Details: map[string]interface{}{
+			"protocol":      protocol,
+			"age_weeks":     ageWeeks,
+			"min_age_weeks": minWeeks,
+			"max_age_weeks": maxWeeks,
+		},
+	}
 }
 
-// ErrBusiness creates a new business error
-func ErrBusiness(code, message string) error {
-	return &BusinessError{
-		Code:    code,
-		Message: message,
+// ErrBulkPatients is returned when some patients of a bulk request are
+// malformed or not of this clinic; nothing is recorded in that case
+func ErrBulkPatients(invalid, missing []string) error {
+	details := map[string]interface{}{}
+	if len(invalid) > 0 {
+		details["invalid_ids"] = invalid
+	}
+	if len(missing) > 0 {
+		details["not_found_ids"] = missing
+	}
+	return &sharedErrors.Error{
+		Kind:    sharedErrors.ErrInvalidInput,
+		Code:    "BULK_PATIENTS_INVALID",
+		Message: "some patients are invalid or do not belong to this clinic",
+		Field:   "patient_ids",
+		Details: details,
 	}
 }
 
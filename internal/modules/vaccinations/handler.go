@@ -50,6 +50,29 @@ func (h *Handler) CreateVaccination(c *gin.Context) (any, error) {
 	return vaccination.ToResponse(), nil
 }
 
+// CreateBulkVaccinations records a vaccine for several patients
+// @Summary Create vaccinations in bulk
+// @Description Record the same vaccine, vet, dates and lot for up to 100 patients of one visit, each with its own certificate number. All patients must belong to the clinic or nothing is recorded; after that each patient succeeds or fails on its own. Owners get one notification for all their animals
+// @Tags vaccinations
+// @Accept json
+// @Produce json
+// @Param vaccinations body BulkVaccinationDTO true "Bulk vaccination data"
+// @Success 201 {object} BulkVaccinationResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/vaccinations/bulk [post]
+func (h *Handler) CreateBulkVaccinations(c *gin.Context) (any, error) {
+	var dto BulkVaccinationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.CreateBulkVaccinations(c.Request.Context(), &dto, tenantID)
+}
+
 // GetVaccination gets a vaccination by ID
 // @Summary Get vaccination
 // @Description Get vaccination details by ID
@@ -323,6 +346,19 @@ func (h *Handler) GetOverdueVaccinations(c *gin.Context) (any, error) {
 	return gin.H{"data": data}, nil
 }
 
+// VerifyCertificate checks a vaccination certificate without authentication
+// @Summary Verify vaccination certificate
+// @Description Public check for boarding facilities and travel. Returns whether the certificate exists and is current, with the patient's first name, vaccine and dates
+// @Tags vaccinations
+// @Produce json
+// @Param certificate query string true "Certificate number"
+// @Success 200 {object} CertificateVerificationResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/vaccinations/verify [get]
+func (h *Handler) VerifyCertificate(c *gin.Context) (any, error) {
+	return h.service.VerifyCertificate(c.Request.Context(), c.Query("certificate"))
+}
+
 // ==================== VACCINE CATALOG ====================
 
 // CreateVaccine creates a new vaccine in the catalog
@@ -481,3 +517,155 @@ func (h *Handler) DeleteVaccine(c *gin.Context) (any, error) {
 
 	return gin.H{"message": "Vaccine deleted successfully"}, nil
 }
+
+// ==================== VACCINE PROTOCOLS ====================
+
+// CreateVaccineProtocol creates a multi-dose protocol in the catalog
+// @Summary Create vaccine protocol
+// @Description Define a multi-dose vaccine series (e.g. puppy protocol) with the interval of each dose, the species it is for and the patient age range at the first dose
+// @Tags vaccinations
+// @Accept json
+// @Produce json
+// @Param protocol body VaccineProtocolDTO true "Protocol data"
+// @Success 201 {object} VaccineProtocolResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/vaccine-protocols [post]
+func (h *Handler) CreateVaccineProtocol(c *gin.Context) (any, error) {
+	var dto VaccineProtocolDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	protocol, err := h.service.CreateProtocol(c.Request.Context(), &dto, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.ToResponse(), nil
+}
+
+// ListVaccineProtocols lists the clinic's vaccine protocols
+// @Summary List vaccine protocols
+// @Description Get the clinic's vaccine protocols by name
+// @Tags vaccinations
+// @Produce json
+// @Success 200 {object} []VaccineProtocolResponse
+// @Security BearerAuth
+// @Router /api/vaccine-protocols [get]
+func (h *Handler) ListVaccineProtocols(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	protocols, err := h.service.ListProtocols(c.Request.Context(), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]VaccineProtocolResponse, len(protocols))
+	for i, p := range protocols {
+		data[i] = *p.ToResponse()
+	}
+
+	return gin.H{"data": data}, nil
+}
+
+// GetVaccineProtocol gets a vaccine protocol by ID
+// @Summary Get vaccine protocol
+// @Description Get vaccine protocol details by ID
+// @Tags vaccinations
+// @Produce json
+// @Param id path string true "Protocol ID"
+// @Success 200 {object} VaccineProtocolResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/vaccine-protocols/{id} [get]
+func (h *Handler) GetVaccineProtocol(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	protocol, err := h.service.GetProtocol(c.Request.Context(), c.Param("id"), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.ToResponse(), nil
+}
+
+// UpdateVaccineProtocol replaces a vaccine protocol
+// @Summary Update vaccine protocol
+// @Description Replace the definition of a protocol. Doses already scheduled from it are not changed
+// @Tags vaccinations
+// @Accept json
+// @Produce json
+// @Param id path string true "Protocol ID"
+// @Param protocol body VaccineProtocolDTO true "Protocol data"
+// @Success 200 {object} VaccineProtocolResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/vaccine-protocols/{id} [put]
+func (h *Handler) UpdateVaccineProtocol(c *gin.Context) (any, error) {
+	var dto VaccineProtocolDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	protocol, err := h.service.UpdateProtocol(c.Request.Context(), c.Param("id"), &dto, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.ToResponse(), nil
+}
+
+// DeleteVaccineProtocol deletes a vaccine protocol
+// @Summary Delete vaccine protocol
+// @Description Soft delete a protocol from the catalog. Doses already scheduled from it are kept
+// @Tags vaccinations
+// @Produce json
+// @Param id path string true "Protocol ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/vaccine-protocols/{id} [delete]
+func (h *Handler) DeleteVaccineProtocol(c *gin.Context) (any, error) {
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	if err := h.service.DeleteProtocol(c.Request.Context(), c.Param("id"), tenantID); err != nil {
+		return nil, err
+	}
+
+	return gin.H{"message": "Vaccine protocol deleted successfully"}, nil
+}
+
+// ScheduleVaccineProtocol generates a patient's doses of a protocol
+// @Summary Schedule vaccine protocol
+// @Description Create every dose of the protocol for the patient as scheduled vaccinations linked by a series, due on the start date plus each dose's interval. Each dose gets its own due reminders; marking it applied records it and moves the reminder to the next dose. The patient's species and age are checked against the protocol when known
+// @Tags vaccinations
+// @Accept json
+// @Produce json
+// @Param id path string true "Protocol ID"
+// @Param schedule body ScheduleProtocolDTO true "Patient, vet and start date"
+// @Success 200 {object} ProtocolScheduleResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /api/vaccine-protocols/{id}/schedule [post]
+func (h *Handler) ScheduleVaccineProtocol(c *gin.Context) (any, error) {
+	var dto ScheduleProtocolDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		return nil, validation.Validate(err)
+	}
+
+	tenantID := sharedMiddleware.GetTenantID(c)
+
+	return h.service.ScheduleProtocol(c.Request.Context(), c.Param("id"), &dto, tenantID)
+}
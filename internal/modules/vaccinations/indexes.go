@@ -30,10 +30,15 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		{
 			Keys: bson.D{{"veterinarian_id", 1}, {"application_date", -1}},
 		},
+		seriesIndex,
+		certificateNumberIndex,
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
 	vaccinationsCollection := db.Collection("vaccinations")
+	if err := reissueLegacyCertificates(ctx, vaccinationsCollection); err != nil {
+		return err
+	}
 	_, err := vaccinationsCollection.Indexes().CreateMany(ctx, vaccinationsIndexes, opts)
 	if err != nil {
 		return err
@@ -59,5 +64,10 @@ func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
 		return err
 	}
 
+	_, err = db.Collection("vaccine_protocols").Indexes().CreateMany(ctx, protocolIndexes, opts)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,395 @@
+package vaccinations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/modules/patients"
+)
+
+// VaccineProtocol is a multi-dose series from the clinic's catalog, e.g. the
+// puppy protocol of three doses three to four weeks apart. Stored in
+// vaccine_protocols.
+type VaccineProtocol struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	TenantID      primitive.ObjectID `bson:"tenant_id"`
+	Name          string             `bson:"name"`
+	Description   string             `bson:"description,omitempty"`
+	TargetSpecies []string           `bson:"target_species,omitempty"` // empty fits every species
+	MinAgeWeeks   int                `bson:"min_age_weeks,omitempty"`  // age at the first dose
+	MaxAgeWeeks   int                `bson:"max_age_weeks,omitempty"`  // 0 = no upper limit
+	Doses         []ProtocolDose     `bson:"doses"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at"`
+	DeletedAt     *time.Time         `bson:"deleted_at,omitempty"`
+}
+
+// ProtocolDose is one dose of a protocol. IntervalDays counts from the
+// previous dose; for the first dose it is the offset from the start date.
+type ProtocolDose struct {
+	VaccineName  string `bson:"vaccine_name" json:"vaccine_name" binding:"required,min=1,max=100" example:"Parvovirus"`
+	IntervalDays int    `bson:"interval_days" json:"interval_days" binding:"min=0,max=1825" example:"21"`
+}
+
+// protocolIndexes allow one live protocol per name and clinic; deleted ones
+// keep their deleted_at
+var protocolIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	},
+}
+
+// seriesIndex lists the doses of a series in order
+var seriesIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "series_id", Value: 1}, {Key: "series_dose", Value: 1}},
+	Options: options.Index().SetSparse(true),
+}
+
+// VaccineProtocolDTO defines a protocol; updates replace the whole definition
+type VaccineProtocolDTO struct {
+	Name          string         `json:"name" binding:"required,min=2,max=100" example:"Cachorro - Quíntuple"`
+	Description   string         `json:"description" binding:"omitempty,max=500"`
+	TargetSpecies []string       `json:"target_species" binding:"omitempty,dive,required" example:"Perro"`
+	MinAgeWeeks   int            `json:"min_age_weeks" binding:"min=0" example:"6"`
+	MaxAgeWeeks   int            `json:"max_age_weeks" binding:"omitempty,gtefield=MinAgeWeeks" example:"20"`
+	Doses         []ProtocolDose `json:"doses" binding:"required,min=1,max=20,dive"`
+}
+
+// ScheduleProtocolDTO generates a patient's doses of a protocol
+type ScheduleProtocolDTO struct {
+	PatientID      string `json:"patient_id" binding:"required"`
+	VeterinarianID string `json:"veterinarian_id" binding:"required"`
+	StartDate      string `json:"start_date"` // RFC3339, defaults to now
+	Notes          string `json:"notes" binding:"omitempty,max=500"`
+}
+
+// VaccineProtocolResponse represents a protocol in API responses
+type VaccineProtocolResponse struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	TargetSpecies []string       `json:"target_species,omitempty"`
+	MinAgeWeeks   int            `json:"min_age_weeks,omitempty"`
+	MaxAgeWeeks   int            `json:"max_age_weeks,omitempty"`
+	Doses         []ProtocolDose `json:"doses"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// ToResponse converts VaccineProtocol to VaccineProtocolResponse
+func (p *VaccineProtocol) ToResponse() *VaccineProtocolResponse {
+	return &VaccineProtocolResponse{
+		ID:            p.ID.Hex(),
+		Name:          p.Name,
+		Description:   p.Description,
+		TargetSpecies: p.TargetSpecies,
+		MinAgeWeeks:   p.MinAgeWeeks,
+		MaxAgeWeeks:   p.MaxAgeWeeks,
+		Doses:         p.Doses,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// ProtocolScheduleResponse lists the doses generated for a patient, in order
+type ProtocolScheduleResponse struct {
+	SeriesID   string                `json:"series_id"`
+	ProtocolID string                `json:"protocol_id"`
+	PatientID  string                `json:"patient_id"`
+	Doses      []VaccinationResponse `json:"doses"`
+}
+
+// Protocol repository methods
+
+func (r *vaccinationRepository) CreateProtocol(ctx context.Context, protocol *VaccineProtocol) error {
+	if _, err := r.protocolsCollection.InsertOne(ctx, protocol); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrProtocolNameExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *vaccinationRepository) FindProtocolByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*VaccineProtocol, error) {
+	var protocol VaccineProtocol
+	err := r.protocolsCollection.FindOne(ctx, bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil}).Decode(&protocol)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrProtocolNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &protocol, nil
+}
+
+func (r *vaccinationRepository) FindProtocols(ctx context.Context, tenantID primitive.ObjectID) ([]VaccineProtocol, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.protocolsCollection.Find(ctx, bson.M{"tenant_id": tenantID, "deleted_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	protocols := []VaccineProtocol{}
+	if err := cursor.All(ctx, &protocols); err != nil {
+		return nil, err
+	}
+	return protocols, nil
+}
+
+func (r *vaccinationRepository) UpdateProtocol(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error {
+	updates["updated_at"] = time.Now()
+
+	result, err := r.protocolsCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrProtocolNameExists
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrProtocolNotFound
+	}
+	return nil
+}
+
+// DeleteProtocol soft-deletes the protocol; doses already scheduled from it are kept
+func (r *vaccinationRepository) DeleteProtocol(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.protocolsCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "tenant_id": tenantID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrProtocolNotFound
+	}
+	return nil
+}
+
+// CreateSeries stores every dose of a series at once
+func (r *vaccinationRepository) CreateSeries(ctx context.Context, doses []Vaccination) error {
+	docs := make([]interface{}, len(doses))
+	for i := range doses {
+		docs[i] = doses[i]
+	}
+	_, err := r.vaccinationsCollection.InsertMany(ctx, docs)
+	return err
+}
+
+// Protocol service methods
+
+// CreateProtocol adds a protocol to the clinic's catalog
+func (s *Service) CreateProtocol(ctx context.Context, dto *VaccineProtocolDTO, tenantID primitive.ObjectID) (*VaccineProtocol, error) {
+	now := time.Now()
+	protocol := &VaccineProtocol{
+		ID:            primitive.NewObjectID(),
+		TenantID:      tenantID,
+		Name:          dto.Name,
+		Description:   dto.Description,
+		TargetSpecies: dto.TargetSpecies,
+		MinAgeWeeks:   dto.MinAgeWeeks,
+		MaxAgeWeeks:   dto.MaxAgeWeeks,
+		Doses:         dto.Doses,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repo.CreateProtocol(ctx, protocol); err != nil {
+		return nil, err
+	}
+	return protocol, nil
+}
+
+// GetProtocol gets a protocol by ID
+func (s *Service) GetProtocol(ctx context.Context, id string, tenantID primitive.ObjectID) (*VaccineProtocol, error) {
+	protocolID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid protocol ID format")
+	}
+	return s.repo.FindProtocolByID(ctx, protocolID, tenantID)
+}
+
+// ListProtocols lists the clinic's protocols by name
+func (s *Service) ListProtocols(ctx context.Context, tenantID primitive.ObjectID) ([]VaccineProtocol, error) {
+	return s.repo.FindProtocols(ctx, tenantID)
+}
+
+// UpdateProtocol replaces a protocol's definition. Series already scheduled
+// keep the doses they were generated with.
+func (s *Service) UpdateProtocol(ctx context.Context, id string, dto *VaccineProtocolDTO, tenantID primitive.ObjectID) (*VaccineProtocol, error) {
+	protocolID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrValidation("id", "invalid protocol ID format")
+	}
+
+	updates := bson.M{
+		"name":           dto.Name,
+		"description":    dto.Description,
+		"target_species": dto.TargetSpecies,
+		"min_age_weeks":  dto.MinAgeWeeks,
+		"max_age_weeks":  dto.MaxAgeWeeks,
+		"doses":          dto.Doses,
+	}
+	if err := s.repo.UpdateProtocol(ctx, protocolID, updates, tenantID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindProtocolByID(ctx, protocolID, tenantID)
+}
+
+// DeleteProtocol soft deletes a protocol
+func (s *Service) DeleteProtocol(ctx context.Context, id string, tenantID primitive.ObjectID) error {
+	protocolID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrValidation("id", "invalid protocol ID format")
+	}
+	return s.repo.DeleteProtocol(ctx, protocolID, tenantID)
+}
+
+// ScheduleProtocol generates every dose of a protocol for a patient as
+// scheduled vaccinations linked by a series, with due dates computed from the
+// start date and the intervals. Each dose carries its own due date, so the
+// regular due reminders fire for it.
+func (s *Service) ScheduleProtocol(ctx context.Context, id string, dto *ScheduleProtocolDTO, tenantID primitive.ObjectID) (*ProtocolScheduleResponse, error) {
+	protocol, err := s.GetProtocol(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
+	if err != nil {
+		return nil, ErrValidation("patient_id", "invalid patient ID format")
+	}
+	patient, err := s.patientRepo.FindByID(ctx, tenantID, patientID.Hex())
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+	if !patient.Active || patient.IsDeceased() {
+		return nil, ErrPatientInactive
+	}
+
+	vetID, err := primitive.ObjectIDFromHex(dto.VeterinarianID)
+	if err != nil {
+		return nil, ErrValidation("veterinarian_id", "invalid veterinarian ID format")
+	}
+	if _, err := s.userRepo.FindByID(ctx, vetID.Hex()); err != nil {
+		return nil, ErrVeterinarianNotFound
+	}
+
+	start := time.Now()
+	if dto.StartDate != "" {
+		if start, err = time.Parse(time.RFC3339, dto.StartDate); err != nil {
+			return nil, ErrValidation("start_date", "invalid date format, use RFC3339")
+		}
+	}
+
+	if err := s.checkProtocolFit(ctx, protocol, patient, start); err != nil {
+		return nil, err
+	}
+	for _, dose := range protocol.Doses {
+		if err := s.checkSpecies(ctx, dose.VaccineName, patient, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	seriesID := primitive.NewObjectID()
+	now := time.Now()
+	due := start
+	doses := make([]Vaccination, len(protocol.Doses))
+	for i, dose := range protocol.Doses {
+		due = due.AddDate(0, 0, dose.IntervalDays)
+		dueDate := due
+		doses[i] = Vaccination{
+			ID:             primitive.NewObjectID(),
+			TenantID:       tenantID,
+			PatientID:      patient.ID,
+			OwnerID:        patient.OwnerID,
+			VeterinarianID: vetID,
+			VaccineName:    dose.VaccineName,
+			NextDueDate:    &dueDate,
+			Status:         VaccinationStatusScheduled,
+			Notes:          dto.Notes,
+			SeriesID:       &seriesID,
+			ProtocolID:     &protocol.ID,
+			SeriesDose:     i + 1,
+			SeriesDoses:    len(protocol.Doses),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	}
+
+	if err := s.repo.CreateSeries(ctx, doses); err != nil {
+		return nil, err
+	}
+
+	resp := &ProtocolScheduleResponse{
+		SeriesID:   seriesID.Hex(),
+		ProtocolID: protocol.ID.Hex(),
+		PatientID:  patient.ID.Hex(),
+		Doses:      make([]VaccinationResponse, len(doses)),
+	}
+	for i := range doses {
+		resp.Doses[i] = *doses[i].ToResponse()
+	}
+	return resp, nil
+}
+
+// applyScheduledDose records a scheduled dose as given today and issues its
+// certificate. Its own due date is cleared, since the next dose of the series
+// carries the following reminder; the last dose is due again when its catalog
+// entry expires, if the vaccine is in the catalog.
+func (s *Service) applyScheduledDose(ctx context.Context, vaccination *Vaccination, tenantID primitive.ObjectID) error {
+	certificateNumber, err := s.generateCertificateNumber(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var nextDueDate *time.Time
+	if vaccination.SeriesDose == vaccination.SeriesDoses {
+		if vaccine, err := s.repo.FindVaccineByName(ctx, vaccination.VaccineName, tenantID); err == nil && vaccine.ValidityMonths > 0 {
+			due := now.AddDate(0, vaccine.ValidityMonths, 0)
+			nextDueDate = &due
+		}
+	}
+
+	return s.repo.Update(ctx, vaccination.ID, bson.M{
+		"status":             VaccinationStatusApplied,
+		"application_date":   now,
+		"next_due_date":      nextDueDate,
+		"certificate_number": certificateNumber,
+	}, tenantID)
+}
+
+// checkProtocolFit rejects a protocol meant for other species or ages than
+// the patient's. Patients without a birth date or with an unknown species
+// skip that part of the check.
+func (s *Service) checkProtocolFit(ctx context.Context, protocol *VaccineProtocol, patient *patients.Patient, start time.Time) error {
+	if len(protocol.TargetSpecies) > 0 {
+		species, err := s.speciesRepo.FindByID(ctx, patient.SpeciesID)
+		if err == nil && !speciesMatches(protocol.TargetSpecies, species) {
+			return ErrProtocolSpeciesMismatch(protocol.Name, species.Name, protocol.TargetSpecies)
+		}
+	}
+
+	if patient.BirthDate != nil && (protocol.MinAgeWeeks > 0 || protocol.MaxAgeWeeks > 0) {
+		ageWeeks := int(start.Sub(*patient.BirthDate).Hours() / 24 / 7)
+		if ageWeeks < protocol.MinAgeWeeks || (protocol.MaxAgeWeeks > 0 && ageWeeks > protocol.MaxAgeWeeks) {
+			return ErrProtocolAgeMismatch(protocol.Name, ageWeeks, protocol.MinAgeWeeks, protocol.MaxAgeWeeks)
+		}
+	}
+	return nil
+}
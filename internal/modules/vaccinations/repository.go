@@ -2,6 +2,8 @@ package vaccinations
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -9,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/eren_dev/go_server/internal/platform/cache"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
@@ -18,6 +21,7 @@ type VaccinationRepository interface {
 	// Vaccination CRUD
 	Create(ctx context.Context, vaccination *Vaccination) error
 	FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Vaccination, error)
+	FindByCertificateNumber(ctx context.Context, certificateNumber string) (*Vaccination, error)
 	FindByPatient(ctx context.Context, patientID, tenantID primitive.ObjectID, params pagination.Params) ([]Vaccination, int64, error)
 	FindByFilters(ctx context.Context, tenantID primitive.ObjectID, filters VaccinationListFilters, params pagination.Params) ([]Vaccination, int64, error)
 	Update(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
@@ -33,10 +37,19 @@ type VaccinationRepository interface {
 	// Vaccine Catalog CRUD
 	CreateVaccine(ctx context.Context, vaccine *Vaccine) error
 	FindVaccineByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Vaccine, error)
+	FindVaccineByName(ctx context.Context, name string, tenantID primitive.ObjectID) (*Vaccine, error)
 	FindVaccines(ctx context.Context, tenantID primitive.ObjectID, filters VaccineListFilters) ([]Vaccine, error)
 	UpdateVaccine(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
 	DeleteVaccine(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
 
+	// Vaccine protocols and the dose series generated from them
+	CreateProtocol(ctx context.Context, protocol *VaccineProtocol) error
+	FindProtocolByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*VaccineProtocol, error)
+	FindProtocols(ctx context.Context, tenantID primitive.ObjectID) ([]VaccineProtocol, error)
+	UpdateProtocol(ctx context.Context, id primitive.ObjectID, updates bson.M, tenantID primitive.ObjectID) error
+	DeleteProtocol(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) error
+	CreateSeries(ctx context.Context, doses []Vaccination) error
+
 	// Indexes
 	EnsureIndexes(ctx context.Context) error
 }
@@ -44,6 +57,7 @@ type VaccinationRepository interface {
 type vaccinationRepository struct {
 	vaccinationsCollection *mongo.Collection
 	vaccinesCollection     *mongo.Collection
+	protocolsCollection    *mongo.Collection
 }
 
 // NewVaccinationRepository creates a new vaccination repository
@@ -51,6 +65,7 @@ func NewVaccinationRepository(db *database.MongoDB) VaccinationRepository {
 	return &vaccinationRepository{
 		vaccinationsCollection: db.Collection("vaccinations"),
 		vaccinesCollection:     db.Collection("vaccines"),
+		protocolsCollection:    db.Collection("vaccine_protocols"),
 	}
 }
 
@@ -65,6 +80,25 @@ func (r *vaccinationRepository) Create(ctx context.Context, vaccination *Vaccina
 	return nil
 }
 
+// FindByCertificateNumber finds a vaccination in any tenant by its certificate number
+func (r *vaccinationRepository) FindByCertificateNumber(ctx context.Context, certificateNumber string) (*Vaccination, error) {
+	filter := bson.M{
+		"certificate_number": certificateNumber,
+		"deleted_at":         nil,
+	}
+
+	var vaccination Vaccination
+	err := r.vaccinationsCollection.FindOne(ctx, filter).Decode(&vaccination)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, err
+	}
+
+	return &vaccination, nil
+}
+
 func (r *vaccinationRepository) FindByID(ctx context.Context, id primitive.ObjectID, tenantID primitive.ObjectID) (*Vaccination, error) {
 	filter := bson.M{
 		"_id":        id,
@@ -320,8 +354,21 @@ func (r *vaccinationRepository) FindOverdueVaccinations(ctx context.Context, ten
 
 // Vaccine methods
 
+// vaccineCache keeps catalog entries looked up by ID or name, which every
+// vaccination checks. Any catalog write drops the tenant's entries.
+var vaccineCache = cache.NewReference[Vaccine]()
+
+func vaccineCacheKey(tenantID primitive.ObjectID, parts ...string) string {
+	return cache.TenantKey(tenantID.Hex(), append([]string{"vaccine"}, parts...)...)
+}
+
+func invalidateVaccines(tenantID primitive.ObjectID) {
+	vaccineCache.DeletePrefix(vaccineCacheKey(tenantID))
+}
+
 func (r *vaccinationRepository) CreateVaccine(ctx context.Context, vaccine *Vaccine) error {
 	result, err := r.vaccinesCollection.InsertOne(ctx, vaccine)
+	invalidateVaccines(vaccine.TenantID)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return ErrVaccineNameExists
@@ -339,12 +386,31 @@ func (r *vaccinationRepository) FindVaccineByID(ctx context.Context, id primitiv
 		"deleted_at": nil,
 	}
 
-	var vaccine Vaccine
-	err := r.vaccinesCollection.FindOne(ctx, filter).Decode(&vaccine)
-	if err != nil {
+	return r.findVaccine(ctx, vaccineCacheKey(tenantID, "id", id.Hex()), filter)
+}
+
+// FindVaccineByName finds a catalog entry by its exact name, ignoring case
+func (r *vaccinationRepository) FindVaccineByName(ctx context.Context, name string, tenantID primitive.ObjectID) (*Vaccine, error) {
+	filter := bson.M{
+		"tenant_id":  tenantID,
+		"name":       bson.M{"$regex": "^" + regexp.QuoteMeta(strings.TrimSpace(name)) + "$", "$options": "i"},
+		"deleted_at": nil,
+	}
+
+	return r.findVaccine(ctx, vaccineCacheKey(tenantID, "name", strings.ToLower(strings.TrimSpace(name))), filter)
+}
+
+// findVaccine loads one catalog entry through the cache
+func (r *vaccinationRepository) findVaccine(ctx context.Context, key string, filter bson.M) (*Vaccine, error) {
+	vaccine, err := vaccineCache.Load(key, func() (Vaccine, error) {
+		var vaccine Vaccine
+		err := r.vaccinesCollection.FindOne(ctx, filter).Decode(&vaccine)
 		if err == mongo.ErrNoDocuments {
-			return nil, ErrVaccineNotFound
+			return vaccine, ErrVaccineNotFound
 		}
+		return vaccine, err
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -400,6 +466,7 @@ func (r *vaccinationRepository) UpdateVaccine(ctx context.Context, id primitive.
 	}
 
 	result, err := r.vaccinesCollection.UpdateOne(ctx, filter, bson.M{"$set": updates})
+	invalidateVaccines(tenantID)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return ErrVaccineNameExists
@@ -428,6 +495,7 @@ func (r *vaccinationRepository) DeleteVaccine(ctx context.Context, id primitive.
 	}
 
 	result, err := r.vaccinesCollection.UpdateOne(ctx, filter, update)
+	invalidateVaccines(tenantID)
 	if err != nil {
 		return err
 	}
@@ -458,6 +526,12 @@ func (r *vaccinationRepository) EnsureIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{"veterinarian_id", 1}, {"application_date", -1}},
 		},
+		seriesIndex,
+		certificateNumberIndex,
+	}
+
+	if err := reissueLegacyCertificates(ctx, r.vaccinationsCollection); err != nil {
+		return err
 	}
 
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
@@ -485,5 +559,10 @@ func (r *vaccinationRepository) EnsureIndexes(ctx context.Context) error {
 		return err
 	}
 
+	_, err = r.protocolsCollection.Indexes().CreateMany(ctx, protocolIndexes, opts)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"log"
 
+	"github.com/eren_dev/go_server/internal/modules/appointments"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
@@ -20,6 +23,7 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil,
 	)
@@ -29,12 +33,13 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 		log.Printf("failed to ensure indexes for vaccinations: %v", err)
 	}
 
-	service := NewService(repo, patientRepo, userRepo, notifSvc)
+	service := NewService(repo, patientRepo, patients.NewSpeciesRepository(db), userRepo, notifSvc, tenant.NewTenantRepository(db), appointments.NewAppointmentRepository(db), sequences.NewService(sequences.NewRepository(db)))
 	handler := NewHandler(service)
 
 	// Vaccinations routes
 	vaccinations := private.Group("/vaccinations")
 	vaccinations.POST("", handler.CreateVaccination)
+	vaccinations.POST("/bulk", handler.CreateBulkVaccinations)
 	vaccinations.GET("", handler.ListVaccinations)
 	vaccinations.GET("/:id", handler.GetVaccination)
 	vaccinations.PUT("/:id", handler.UpdateVaccination)
@@ -51,6 +56,15 @@ func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB) {
 	vaccines.GET("/:id", handler.GetVaccine)
 	vaccines.PUT("/:id", handler.UpdateVaccine)
 	vaccines.DELETE("/:id", handler.DeleteVaccine)
+
+	// Multi-dose protocols
+	protocols := private.Group("/vaccine-protocols")
+	protocols.POST("", handler.CreateVaccineProtocol)
+	protocols.GET("", handler.ListVaccineProtocols)
+	protocols.GET("/:id", handler.GetVaccineProtocol)
+	protocols.PUT("/:id", handler.UpdateVaccineProtocol)
+	protocols.DELETE("/:id", handler.DeleteVaccineProtocol)
+	protocols.POST("/:id/schedule", handler.ScheduleVaccineProtocol)
 }
 
 // RegisterMobileRoutes registers mobile (owner-facing) routes
@@ -61,14 +75,23 @@ func RegisterMobileRoutes(mobile *httpx.Router, db *database.MongoDB) {
 	notifSvc := notifications.NewService(
 		notifications.NewRepository(db),
 		notifications.NewStaffRepository(db),
+		notifications.NewTemplateRepository(db),
 		owners.NewRepository(db),
 		nil,
 	)
 
-	service := NewService(repo, patientRepo, userRepo, notifSvc)
+	service := NewService(repo, patientRepo, patients.NewSpeciesRepository(db), userRepo, notifSvc, tenant.NewTenantRepository(db), appointments.NewAppointmentRepository(db), sequences.NewService(sequences.NewRepository(db)))
 	handler := NewHandler(service)
 
 	// Mobile routes - read only for owners
 	m := mobile.Group("/vaccinations")
 	m.GET("/patient/:patient_id", handler.GetPatientVaccinations)
 }
+
+// RegisterPublicRoutes registers unauthenticated routes under /api/vaccinations
+func RegisterPublicRoutes(public *httpx.Router, db *database.MongoDB) {
+	service := NewService(NewVaccinationRepository(db), patients.NewPatientRepository(db), patients.NewSpeciesRepository(db), users.NewRepository(db), nil, nil, nil, nil)
+	handler := NewHandler(service)
+
+	public.GET("/vaccinations/verify", handler.VerifyCertificate)
+}
@@ -13,12 +13,15 @@ const (
 	VaccinationStatusApplied   VaccinationStatus = "applied"
 	VaccinationStatusDue       VaccinationStatus = "due"
 	VaccinationStatusOverdue   VaccinationStatus = "overdue"
+	// VaccinationStatusScheduled is a dose planned by a protocol that has not
+	// been applied yet; its next due date is the day it should be given
+	VaccinationStatusScheduled VaccinationStatus = "scheduled"
 )
 
 // IsValidVaccinationStatus checks if the status is valid
 func IsValidVaccinationStatus(s string) bool {
 	switch VaccinationStatus(s) {
-	case VaccinationStatusApplied, VaccinationStatusDue, VaccinationStatusOverdue:
+	case VaccinationStatusApplied, VaccinationStatusDue, VaccinationStatusOverdue, VaccinationStatusScheduled:
 		return true
 	}
 	return false
@@ -57,6 +60,12 @@ type Vaccination struct {
 	Status          VaccinationStatus   `bson:"status" json:"status"`
 	CertificateNumber string            `bson:"certificate_number,omitempty" json:"certificate_number,omitempty"`
 	Notes           string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	// Doses generated from a protocol share a series; SeriesDose is the
+	// position of this dose (1-based) out of SeriesDoses
+	SeriesID        *primitive.ObjectID `bson:"series_id,omitempty" json:"series_id,omitempty"`
+	ProtocolID      *primitive.ObjectID `bson:"protocol_id,omitempty" json:"protocol_id,omitempty"`
+	SeriesDose      int                 `bson:"series_dose,omitempty" json:"series_dose,omitempty"`
+	SeriesDoses     int                 `bson:"series_doses,omitempty" json:"series_doses,omitempty"`
 	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
 	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
 	DeletedAt       *time.Time          `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
@@ -77,6 +86,8 @@ func (v *Vaccination) ToResponse() *VaccinationResponse {
 		Status:          string(v.Status),
 		CertificateNumber: v.CertificateNumber,
 		Notes:           v.Notes,
+		SeriesDose:      v.SeriesDose,
+		SeriesDoses:     v.SeriesDoses,
 		CreatedAt:       v.CreatedAt,
 		UpdatedAt:       v.UpdatedAt,
 	}
@@ -84,6 +95,12 @@ func (v *Vaccination) ToResponse() *VaccinationResponse {
 	if v.NextDueDate != nil {
 		resp.NextDueDate = v.NextDueDate.Format(time.RFC3339)
 	}
+	if v.SeriesID != nil {
+		resp.SeriesID = v.SeriesID.Hex()
+	}
+	if v.ProtocolID != nil {
+		resp.ProtocolID = v.ProtocolID.Hex()
+	}
 
 	return resp
 }
@@ -125,11 +142,15 @@ type VaccinationResponse struct {
 	VaccineName       string    `json:"vaccine_name"`
 	Manufacturer      string    `json:"manufacturer,omitempty"`
 	LotNumber         string    `json:"lot_number,omitempty"`
-	ApplicationDate   time.Time `json:"application_date"`
+	ApplicationDate   time.Time `json:"application_date,omitzero"` // Unset while the dose is scheduled
 	NextDueDate       string    `json:"next_due_date,omitempty"`
 	Status            string    `json:"status"`
 	CertificateNumber string    `json:"certificate_number,omitempty"`
 	Notes             string    `json:"notes,omitempty"`
+	SeriesID          string    `json:"series_id,omitempty"`
+	ProtocolID        string    `json:"protocol_id,omitempty"`
+	SeriesDose        int       `json:"series_dose,omitempty" example:"2"`
+	SeriesDoses       int       `json:"series_doses,omitempty" example:"3"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
@@ -195,3 +216,15 @@ type VaccinationAlert struct {
 	DaysUntilDue    int        `json:"days_until_due,omitempty"`
 	DaysOverdue     int        `json:"days_overdue,omitempty"`
 }
+
+// CertificateVerificationResponse is the public answer to a certificate check.
+// Valid is false for unknown numbers and for vaccinations past their due date;
+// the other fields are only set when the certificate exists.
+type CertificateVerificationResponse struct {
+	Valid             bool       `json:"valid"`
+	CertificateNumber string     `json:"certificate_number,omitempty"`
+	PatientName       string     `json:"patient_name,omitempty"` // First name only
+	VaccineName       string     `json:"vaccine_name,omitempty"`
+	ApplicationDate   time.Time  `json:"application_date,omitzero"`
+	NextDueDate       *time.Time `json:"next_due_date,omitempty"`
+}
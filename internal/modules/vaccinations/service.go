@@ -2,14 +2,19 @@ package vaccinations
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/modules/appointments"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
 	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
 	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
@@ -18,6 +23,8 @@ import (
 type NotificationSender interface {
 	Send(ctx context.Context, dto *notifications.SendDTO) error
 	SendToStaff(ctx context.Context, dto *notifications.SendStaffDTO) error
+	SendDueDigest(ctx context.Context, ownerID, tenantID string, items []notifications.DueItem) error
+	RecordFailedSend(ctx context.Context, dto *notifications.SendDTO, source string, err error)
 }
 
 // PatientRepository defines the interface for patient data access
@@ -25,26 +32,55 @@ type PatientRepository interface {
 	FindByID(ctx context.Context, tenantID primitive.ObjectID, id string) (*patients.Patient, error)
 }
 
+// SpeciesRepository resolves the species a patient belongs to
+type SpeciesRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*patients.Species, error)
+}
+
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	FindByID(ctx context.Context, id string) (*users.User, error)
 }
 
+// TenantReader resolves the clinic settings that shape due reminders
+type TenantReader interface {
+	FindByID(ctx context.Context, id string) (*tenant.Tenant, error)
+}
+
+// AppointmentReader lists a clinic's appointments in a time range, to fold
+// today's visits into due digests
+type AppointmentReader interface {
+	FindByDateRange(ctx context.Context, from, to time.Time, tenantID primitive.ObjectID) ([]appointments.Appointment, error)
+}
+
+// NumberIssuer draws consecutive document numbers, implemented by sequences.Service
+type NumberIssuer interface {
+	Next(ctx context.Context, tenantID primitive.ObjectID, name, format string, at time.Time) (string, error)
+}
+
 // Service provides business logic for vaccinations
 type Service struct {
 	repo            VaccinationRepository
 	patientRepo     PatientRepository
+	speciesRepo     SpeciesRepository
 	userRepo        UserRepository
 	notificationSvc NotificationSender
+	tenants         TenantReader
+	appointments    AppointmentReader
+	numbers         NumberIssuer
 }
 
 // NewService creates a new vaccinations service
-func NewService(repo VaccinationRepository, patientRepo PatientRepository, userRepo UserRepository, notificationSvc NotificationSender) *Service {
+func NewService(repo VaccinationRepository, patientRepo PatientRepository, speciesRepo SpeciesRepository, userRepo UserRepository, notificationSvc NotificationSender, tenants TenantReader, appointments AppointmentReader, numbers NumberIssuer) *Service {
 	return &Service{
 		repo:            repo,
 		patientRepo:     patientRepo,
+		speciesRepo:     speciesRepo,
 		userRepo:        userRepo,
 		notificationSvc: notificationSvc,
+		tenants:         tenants,
+		appointments:    appointments,
+		numbers:         numbers,
 	}
 }
 
@@ -61,38 +97,78 @@ func (s *Service) CreateVaccination(ctx context.Context, dto *CreateVaccinationD
 		return nil, ErrPatientNotFound
 	}
 
+	if err := s.checkSpecies(ctx, dto.VaccineName, patient, tenantID); err != nil {
+		return nil, err
+	}
+
+	vetID, applicationDate, nextDueDate, err := s.validateApplication(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	vaccination, err := s.recordVaccination(ctx, dto, patient, vetID, applicationDate, nextDueDate, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send notification to owner
+	s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		OwnerID:  patient.OwnerID.Hex(),
+		TenantID: tenantID.Hex(),
+		Type:     notifications.TypeVaccinationDue,
+		Template: notifications.TemplateVaccinationRegistered,
+		Vars:     map[string]string{"vaccine_name": dto.VaccineName, "patient_name": patient.Name},
+		Data: map[string]string{
+			"vaccination_id": vaccination.ID.Hex(),
+			"patient_id":     vaccination.PatientID.Hex(),
+			"vaccine_name":   dto.VaccineName,
+		},
+		SendPush: true,
+	})
+
+	return vaccination, nil
+}
+
+// validateApplication checks the vet and dates of a vaccination being recorded
+func (s *Service) validateApplication(ctx context.Context, dto *CreateVaccinationDTO) (primitive.ObjectID, time.Time, *time.Time, error) {
 	// Validate veterinarian
 	vetID, err := primitive.ObjectIDFromHex(dto.VeterinarianID)
 	if err != nil {
-		return nil, ErrValidation("veterinarian_id", "invalid veterinarian ID format")
+		return primitive.NilObjectID, time.Time{}, nil, ErrValidation("veterinarian_id", "invalid veterinarian ID format")
 	}
 
 	_, err = s.userRepo.FindByID(ctx, vetID.Hex())
 	if err != nil {
-		return nil, ErrVeterinarianNotFound
+		return primitive.NilObjectID, time.Time{}, nil, ErrVeterinarianNotFound
 	}
 
 	// Parse and validate application date
 	applicationDate, err := dto.ParseApplicationDate()
 	if err != nil {
-		return nil, ErrValidation("application_date", "invalid date format, use RFC3339")
+		return primitive.NilObjectID, time.Time{}, nil, ErrValidation("application_date", "invalid date format, use RFC3339")
 	}
 
 	// Application date cannot be in the future
 	if applicationDate.After(time.Now()) {
-		return nil, ErrInvalidApplicationDate
+		return primitive.NilObjectID, time.Time{}, nil, ErrInvalidApplicationDate
 	}
 
 	// Parse and validate next due date
 	nextDueDate, err := dto.ParseNextDueDate()
 	if err != nil {
-		return nil, ErrValidation("next_due_date", "invalid date format, use RFC3339")
+		return primitive.NilObjectID, time.Time{}, nil, ErrValidation("next_due_date", "invalid date format, use RFC3339")
 	}
 
 	if nextDueDate != nil && nextDueDate.Before(applicationDate) {
-		return nil, ErrInvalidNextDueDate
+		return primitive.NilObjectID, time.Time{}, nil, ErrInvalidNextDueDate
 	}
 
+	return vetID, applicationDate, nextDueDate, nil
+}
+
+// recordVaccination stores a validated vaccination of patient, deriving its
+// status from the dates and issuing its certificate number
+func (s *Service) recordVaccination(ctx context.Context, dto *CreateVaccinationDTO, patient *patients.Patient, vetID primitive.ObjectID, applicationDate time.Time, nextDueDate *time.Time, tenantID primitive.ObjectID) (*Vaccination, error) {
 	// Determine status based on dates
 	status := VaccinationStatusApplied
 	if nextDueDate != nil {
@@ -104,14 +180,16 @@ func (s *Service) CreateVaccination(ctx context.Context, dto *CreateVaccinationD
 	}
 
 	// Generate certificate number
-	certificateNumber := s.generateCertificateNumber(tenantID, applicationDate)
+	certificateNumber, err := s.generateCertificateNumber(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create vaccination
 	now := time.Now()
 	vaccination := &Vaccination{
 		ID:                primitive.NewObjectID(),
 		TenantID:          tenantID,
-		PatientID:         patientID,
+		PatientID:         patient.ID,
 		OwnerID:           patient.OwnerID,
 		VeterinarianID:    vetID,
 		VaccineName:       dto.VaccineName,
@@ -129,23 +207,47 @@ func (s *Service) CreateVaccination(ctx context.Context, dto *CreateVaccinationD
 	if err := s.repo.Create(ctx, vaccination); err != nil {
 		return nil, err
 	}
+	return vaccination, nil
+}
 
-	// Send notification to owner
-	s.notificationSvc.Send(ctx, &notifications.SendDTO{
-		OwnerID:  patient.OwnerID.Hex(),
-		TenantID: tenantID.Hex(),
-		Type:     notifications.TypeVaccinationDue,
-		Title:    "Vacunación Registrada",
-		Body:     fmt.Sprintf("Se ha registrado la vacunación %s para %s", dto.VaccineName, patient.Name),
-		Data: map[string]string{
-			"vaccination_id": vaccination.ID.Hex(),
-			"patient_id":     vaccination.PatientID.Hex(),
-			"vaccine_name":   dto.VaccineName,
-		},
-		SendPush: true,
-	})
+// checkSpecies rejects a vaccine whose catalog entry explicitly targets other
+// species than the patient's. Vaccines missing from the catalog, entries with
+// no target species and patients whose species can't be resolved are let
+// through with a warning, since the clinic may record vaccines it doesn't stock.
+func (s *Service) checkSpecies(ctx context.Context, vaccineName string, patient *patients.Patient, tenantID primitive.ObjectID) error {
+	vaccine, err := s.repo.FindVaccineByName(ctx, vaccineName, tenantID)
+	if err != nil {
+		if !errors.Is(err, ErrVaccineNotFound) {
+			return err
+		}
+		slog.Warn("vaccine not in catalog, skipping species check", "tenant_id", tenantID.Hex(), "vaccine_name", vaccineName, "patient_id", patient.ID.Hex())
+		return nil
+	}
+	if len(vaccine.TargetSpecies) == 0 {
+		return nil
+	}
 
-	return vaccination, nil
+	species, err := s.speciesRepo.FindByID(ctx, patient.SpeciesID)
+	if err != nil {
+		slog.Warn("patient species not found, skipping species check", "patient_id", patient.ID.Hex(), "species_id", patient.SpeciesID.Hex(), "error", err)
+		return nil
+	}
+
+	if speciesMatches(vaccine.TargetSpecies, species) {
+		return nil
+	}
+	return ErrSpeciesMismatch(vaccine.Name, species.Name, vaccine.TargetSpecies)
+}
+
+// speciesMatches reports whether any target names the species, by ID or name
+func speciesMatches(targets []string, species *patients.Species) bool {
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == species.ID.Hex() || strings.EqualFold(target, species.Name) || strings.EqualFold(target, species.NormalizedName) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetVaccination gets a vaccination by ID
@@ -250,7 +352,16 @@ func (s *Service) UpdateVaccinationStatus(ctx context.Context, id string, dto *U
 		return nil, ErrInvalidStatus
 	}
 
-	if err := s.repo.UpdateStatus(ctx, vaccinationID, status, tenantID); err != nil {
+	vaccination, err := s.repo.FindByID(ctx, vaccinationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if vaccination.Status == VaccinationStatusScheduled && status == VaccinationStatusApplied {
+		if err := s.applyScheduledDose(ctx, vaccination, tenantID); err != nil {
+			return nil, err
+		}
+	} else if err := s.repo.UpdateStatus(ctx, vaccinationID, status, tenantID); err != nil {
 		return nil, err
 	}
 
@@ -282,12 +393,20 @@ func (s *Service) GetOverdueVaccinations(ctx context.Context, tenantID primitive
 	return s.repo.FindOverdueVaccinations(ctx, tenantID)
 }
 
-// SendDueReminders sends reminders for vaccinations due within the specified days
+// SendDueReminders sends reminders for vaccinations due within the specified
+// days. Clinics in digest mode send owners with several things due today one
+// consolidated notification instead; everything else still goes per item.
 func (s *Service) SendDueReminders(ctx context.Context, tenantID primitive.ObjectID, days int) error {
 	vaccinations, err := s.GetDueVaccinations(ctx, tenantID, days)
 	if err != nil {
 		return err
 	}
+	vaccinations = s.forActivePatients(ctx, tenantID, vaccinations)
+
+	vaccinations, err = s.sendDueDigests(ctx, tenantID, vaccinations)
+	if err != nil {
+		return err
+	}
 
 	for _, v := range vaccinations {
 		daysUntil := v.DaysUntilDue()
@@ -304,7 +423,7 @@ func (s *Service) SendDueReminders(ctx context.Context, tenantID primitive.Objec
 			body = fmt.Sprintf("La vacuna %s de tu mascota vence en %d días", v.VaccineName, daysUntil)
 		}
 
-		s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		reminder := &notifications.SendDTO{
 			OwnerID:  v.OwnerID.Hex(),
 			TenantID: tenantID.Hex(),
 			Type:     notifications.TypeVaccinationDue,
@@ -317,7 +436,10 @@ func (s *Service) SendDueReminders(ctx context.Context, tenantID primitive.Objec
 				"days_until_due": fmt.Sprintf("%d", daysUntil),
 			},
 			SendPush: true,
-		})
+		}
+		if err := s.notificationSvc.Send(ctx, reminder); err != nil {
+			s.notificationSvc.RecordFailedSend(ctx, reminder, "vaccination_due", err)
+		}
 	}
 
 	return nil
@@ -329,16 +451,17 @@ func (s *Service) SendOverdueReminders(ctx context.Context, tenantID primitive.O
 	if err != nil {
 		return err
 	}
+	vaccinations = s.forActivePatients(ctx, tenantID, vaccinations)
 
 	for _, v := range vaccinations {
 		daysOverdue := -v.DaysUntilDue()
 
-		s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		reminder := &notifications.SendDTO{
 			OwnerID:  v.OwnerID.Hex(),
 			TenantID: tenantID.Hex(),
 			Type:     notifications.TypeVaccinationDue,
-			Title:    "Vacuna Vencida",
-			Body:     fmt.Sprintf("La vacuna %s de tu mascota venció hace %d días. ¡Programa una cita!", v.VaccineName, daysOverdue),
+			Template: notifications.TemplateVaccinationOverdue,
+			Vars:     map[string]string{"vaccine_name": v.VaccineName, "days_overdue": fmt.Sprintf("%d", daysOverdue)},
 			Data: map[string]string{
 				"vaccination_id": v.ID.Hex(),
 				"patient_id":     v.PatientID.Hex(),
@@ -346,12 +469,34 @@ func (s *Service) SendOverdueReminders(ctx context.Context, tenantID primitive.O
 				"days_overdue":   fmt.Sprintf("%d", daysOverdue),
 			},
 			SendPush: true,
-		})
+		}
+		if err := s.notificationSvc.Send(ctx, reminder); err != nil {
+			s.notificationSvc.RecordFailedSend(ctx, reminder, "vaccination_overdue", err)
+		}
 	}
 
 	return nil
 }
 
+// forActivePatients drops vaccinations of patients that are inactive, deceased
+// or gone, so their owners get no more reminders
+func (s *Service) forActivePatients(ctx context.Context, tenantID primitive.ObjectID, vaccinations []Vaccination) []Vaccination {
+	active := make(map[primitive.ObjectID]bool)
+	kept := make([]Vaccination, 0, len(vaccinations))
+	for _, v := range vaccinations {
+		ok, seen := active[v.PatientID]
+		if !seen {
+			patient, err := s.patientRepo.FindByID(ctx, tenantID, v.PatientID.Hex())
+			ok = err == nil && patient.Active && !patient.IsDeceased()
+			active[v.PatientID] = ok
+		}
+		if ok {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
 // CreateVaccine creates a new vaccine in the catalog
 func (s *Service) CreateVaccine(ctx context.Context, dto *CreateVaccineDTO, tenantID primitive.ObjectID) (*Vaccine, error) {
 	// Validate dose type
@@ -465,13 +610,3 @@ func (s *Service) DeleteVaccine(ctx context.Context, id string, tenantID primiti
 
 	return s.repo.DeleteVaccine(ctx, vaccineID, tenantID)
 }
-
-// generateCertificateNumber generates a unique certificate number
-func (s *Service) generateCertificateNumber(tenantID primitive.ObjectID, applicationDate time.Time) string {
-	// Format: VAC-TENANT-YYYYMMDD-RANDOM
-	// Example: VAC-507f-20260224-A1B2
-	return fmt.Sprintf("VAC-%s-%s",
-		tenantID.Hex()[:4],
-		applicationDate.Format("20060102"),
-	)
-}
@@ -0,0 +1,254 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/platform/payment"
+	"github.com/eren_dev/go_server/internal/shared/database"
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
+)
+
+const eventCollection = "webhook_events"
+
+// Outcomes of processing a stored webhook event
+const (
+	EventProcessed = "processed"
+	EventFailed    = "failed"
+)
+
+var (
+	ErrWebhookEventNotFound  = sharedErrors.New(sharedErrors.ErrNotFound, "WEBHOOK_EVENT_NOT_FOUND", "webhook event not found")
+	ErrInvalidWebhookEventID = sharedErrors.New(sharedErrors.ErrBadRequest, "INVALID_WEBHOOK_EVENT_ID", "invalid webhook event ID format")
+	ErrInvalidUserID         = sharedErrors.New(sharedErrors.ErrUnauthorized, "INVALID_USER_ID", "invalid user ID format")
+	ErrInvalidEventOutcome   = sharedErrors.New(sharedErrors.ErrBadRequest, "INVALID_WEBHOOK_EVENT_OUTCOME", "outcome must be processed or failed")
+	ErrEventAlreadyProcessed = sharedErrors.New(sharedErrors.ErrConflict, "WEBHOOK_EVENT_ALREADY_PROCESSED", "the webhook event was already applied, only failed events can be reprocessed")
+	ErrSuperAdminRequired    = sharedErrors.New(sharedErrors.ErrForbidden, "SUPER_ADMIN_REQUIRED", "stored payment webhooks are only available to platform administrators")
+)
+
+// WebhookEventRecord is a payment webhook as parsed on arrival. It is the
+// idempotency store: a delivery the provider repeats after it was processed
+// is not applied again, and a failed one can be replayed after a fix without
+// asking the provider to resend. The parsed event is kept rather than
+// re-parsed because provider signatures expire minutes after sending.
+type WebhookEventRecord struct {
+	ID primitive.ObjectID `bson:"_id,omitempty"`
+	// DedupeKey identifies a delivery; empty for events without a transaction
+	DedupeKey      string                 `bson:"dedupe_key,omitempty"`
+	Provider       string                 `bson:"provider"`
+	EventType      string                 `bson:"event_type"`
+	SubscriptionID string                 `bson:"subscription_id,omitempty"`
+	TransactionID  string                 `bson:"transaction_id,omitempty"`
+	Status         string                 `bson:"status,omitempty"`
+	Amount         int64                  `bson:"amount"`
+	Currency       string                 `bson:"currency,omitempty"`
+	Reference      string                 `bson:"reference,omitempty"`
+	PaymentLinkID  string                 `bson:"payment_link_id,omitempty"`
+	Metadata       map[string]interface{} `bson:"metadata,omitempty"`
+	// Outcome is processed or failed; Error is the last failure
+	Outcome     string          `bson:"outcome"`
+	Error       string          `bson:"error,omitempty"`
+	Attempts    int             `bson:"attempts"`
+	Replays     []WebhookReplay `bson:"replays,omitempty"`
+	ReceivedAt  time.Time       `bson:"received_at"`
+	ProcessedAt time.Time       `bson:"processed_at"`
+}
+
+// WebhookReplay is an operator's reprocessing of a stored event
+type WebhookReplay struct {
+	By      primitive.ObjectID `bson:"by"`
+	At      time.Time          `bson:"at"`
+	Outcome string             `bson:"outcome"`
+	Error   string             `bson:"error,omitempty"`
+}
+
+func newEventRecord(event *payment.WebhookEvent) *WebhookEventRecord {
+	record := &WebhookEventRecord{
+		ID:             primitive.NewObjectID(),
+		Provider:       string(event.Provider),
+		EventType:      event.EventType,
+		SubscriptionID: event.SubscriptionID,
+		TransactionID:  event.TransactionID,
+		Status:         event.Status,
+		Amount:         event.Amount,
+		Currency:       event.Currency,
+		Reference:      event.Reference,
+		PaymentLinkID:  event.PaymentLinkID,
+		Metadata:       event.Metadata,
+		ReceivedAt:     time.Now(),
+	}
+	if event.TransactionID != "" {
+		record.DedupeKey = strings.Join([]string{record.Provider, event.EventType, event.TransactionID, event.Status}, ":")
+	}
+	return record
+}
+
+// event rebuilds the parsed webhook event for processing
+func (r *WebhookEventRecord) event() *payment.WebhookEvent {
+	return &payment.WebhookEvent{
+		Provider:       payment.ProviderType(r.Provider),
+		EventType:      r.EventType,
+		SubscriptionID: r.SubscriptionID,
+		TransactionID:  r.TransactionID,
+		Status:         r.Status,
+		Amount:         r.Amount,
+		Currency:       r.Currency,
+		Metadata:       r.Metadata,
+		Reference:      r.Reference,
+		PaymentLinkID:  r.PaymentLinkID,
+	}
+}
+
+// WebhookEventResponse is a stored webhook event as shown to admins
+type WebhookEventResponse struct {
+	ID            string                  `json:"id"`
+	Provider      string                  `json:"provider"`
+	EventType     string                  `json:"event_type"`
+	TransactionID string                  `json:"transaction_id,omitempty"`
+	Status        string                  `json:"status,omitempty"`
+	Amount        int64                   `json:"amount"`
+	Currency      string                  `json:"currency,omitempty"`
+	Reference     string                  `json:"reference,omitempty"`
+	Outcome       string                  `json:"outcome"`
+	Error         string                  `json:"error,omitempty"`
+	Attempts      int                     `json:"attempts"`
+	Replays       []WebhookReplayResponse `json:"replays"`
+	ReceivedAt    time.Time               `json:"received_at"`
+	ProcessedAt   time.Time               `json:"processed_at"`
+}
+
+type WebhookReplayResponse struct {
+	By      string    `json:"by"`
+	At      time.Time `json:"at"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// PaginatedWebhookEventsResponse is a page of stored webhook events
+type PaginatedWebhookEventsResponse struct {
+	Data       []WebhookEventResponse    `json:"data"`
+	Pagination pagination.PaginationInfo `json:"pagination"`
+}
+
+func (r *WebhookEventRecord) ToResponse() WebhookEventResponse {
+	replays := make([]WebhookReplayResponse, len(r.Replays))
+	for i, replay := range r.Replays {
+		replays[i] = WebhookReplayResponse{By: replay.By.Hex(), At: replay.At, Outcome: replay.Outcome, Error: replay.Error}
+	}
+	return WebhookEventResponse{
+		ID:            r.ID.Hex(),
+		Provider:      r.Provider,
+		EventType:     r.EventType,
+		TransactionID: r.TransactionID,
+		Status:        r.Status,
+		Amount:        r.Amount,
+		Currency:      r.Currency,
+		Reference:     r.Reference,
+		Outcome:       r.Outcome,
+		Error:         r.Error,
+		Attempts:      r.Attempts,
+		Replays:       replays,
+		ReceivedAt:    r.ReceivedAt,
+		ProcessedAt:   r.ProcessedAt,
+	}
+}
+
+// EventRepository stores received webhook events
+type EventRepository interface {
+	// Save stores a received event. When the same delivery was received
+	// before it stores nothing and returns the earlier record instead.
+	Save(ctx context.Context, record *WebhookEventRecord) (*WebhookEventRecord, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*WebhookEventRecord, error)
+	List(ctx context.Context, outcome string, params pagination.Params) ([]WebhookEventRecord, int64, error)
+	// SetOutcome records the result of an attempt, and the replay that made it if any
+	SetOutcome(ctx context.Context, id primitive.ObjectID, outcome, errMsg string, replay *WebhookReplay) error
+}
+
+type eventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEventRepository(db *database.MongoDB) EventRepository {
+	return &eventRepository{collection: db.Collection(eventCollection)}
+}
+
+func (r *eventRepository) Save(ctx context.Context, record *WebhookEventRecord) (*WebhookEventRecord, error) {
+	_, err := r.collection.InsertOne(ctx, record)
+	if err == nil || !mongo.IsDuplicateKeyError(err) || record.DedupeKey == "" {
+		return nil, err
+	}
+
+	var existing WebhookEventRecord
+	if err := r.collection.FindOne(ctx, bson.M{"dedupe_key": record.DedupeKey}).Decode(&existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (r *eventRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*WebhookEventRecord, error) {
+	var record WebhookEventRecord
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWebhookEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *eventRepository) List(ctx context.Context, outcome string, params pagination.Params) ([]WebhookEventRecord, int64, error) {
+	filter := bson.M{}
+	if outcome != "" {
+		filter["outcome"] = outcome
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(params.Skip).
+		SetLimit(params.Limit).
+		SetSort(bson.D{{Key: "received_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []WebhookEventRecord{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+func (r *eventRepository) SetOutcome(ctx context.Context, id primitive.ObjectID, outcome, errMsg string, replay *WebhookReplay) error {
+	update := bson.M{
+		"$set": bson.M{"outcome": outcome, "error": errMsg, "processed_at": time.Now()},
+		"$inc": bson.M{"attempts": 1},
+	}
+	if replay != nil {
+		update["$push"] = bson.M{"replays": replay}
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrWebhookEventNotFound
+	}
+	return nil
+}
@@ -8,36 +8,56 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
 	"github.com/eren_dev/go_server/internal/modules/payments"
 	"github.com/eren_dev/go_server/internal/modules/plans"
 	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/modules/users"
 	"github.com/eren_dev/go_server/internal/platform/logger"
 	"github.com/eren_dev/go_server/internal/platform/payment"
 	"github.com/eren_dev/go_server/internal/platform/webhook"
+	"github.com/eren_dev/go_server/internal/shared/auth"
+	"github.com/eren_dev/go_server/internal/shared/pagination"
 )
 
 type WebhookHandler struct {
 	paymentManager *payment.PaymentManager
 	paymentService *payments.PaymentService
+	invoiceService *invoices.Service
 	tenantRepo     tenant.TenantRepository
 	planRepo       plans.PlanRepository
 	validator      *webhook.SignatureValidator
+	events         EventRepository
+	userRepo       users.UserRepository
+
+	appointmentService *appointments.Service
 }
 
 func NewWebhookHandler(
 	paymentManager *payment.PaymentManager,
 	paymentService *payments.PaymentService,
+	invoiceService *invoices.Service,
+	appointmentService *appointments.Service,
 	tenantRepo tenant.TenantRepository,
 	planRepo plans.PlanRepository,
 	validator *webhook.SignatureValidator,
+	events EventRepository,
+	userRepo users.UserRepository,
 ) *WebhookHandler {
 	return &WebhookHandler{
 		paymentManager: paymentManager,
 		paymentService: paymentService,
+		invoiceService: invoiceService,
 		tenantRepo:     tenantRepo,
 		planRepo:       planRepo,
 		validator:      validator,
+		events:         events,
+		userRepo:       userRepo,
+
+		appointmentService: appointmentService,
 	}
 }
 
@@ -107,9 +127,26 @@ func (h *WebhookHandler) ProcessWebhook(c *gin.Context) (any, error) {
 		"status", event.Status,
 	)
 
-	// Procesar el evento según el tipo
+	// Guardar el evento; una entrega repetida de un evento ya procesado no se aplica de nuevo
 	ctx := context.Background()
-	if err := h.handleWebhookEvent(ctx, event); err != nil {
+	record := newEventRecord(event)
+	existing, err := h.events.Save(ctx, record)
+	if err != nil {
+		logger.Default().Error(ctx, "webhook_store_error", "error", err, "transaction_id", event.TransactionID)
+		record = nil
+	} else if existing != nil {
+		if existing.Outcome == EventProcessed {
+			logger.Default().Info(ctx, "webhook_duplicate", "event_id", existing.ID.Hex(), "transaction_id", event.TransactionID)
+			return gin.H{
+				"status": "duplicate",
+				"event":  event.EventType,
+			}, nil
+		}
+		record = existing
+	}
+
+	// Procesar el evento según el tipo
+	if err := h.processEvent(ctx, event, record, nil); err != nil {
 		logger.Default().Error(ctx, "webhook_handler_error", "error", err)
 		// No retornamos error al provider para evitar reintentos
 	}
@@ -120,6 +157,130 @@ func (h *WebhookHandler) ProcessWebhook(c *gin.Context) (any, error) {
 	}, nil
 }
 
+// ListEvents godoc
+// @Summary      Listar webhooks de pago recibidos
+// @Description  Lista los eventos de webhook guardados de todas las clínicas, los más recientes primero. Solo super administradores.
+// @Tags         webhooks
+// @Produce      json
+// @Param        outcome query string false "Resultado del procesamiento" Enums(processed, failed)
+// @Param        skip query int false "Skip"
+// @Param        limit query int false "Limit"
+// @Success      200 {object} PaginatedWebhookEventsResponse
+// @Failure      403 {object} map[string]string
+// @Security     Bearer
+// @Router       /api/payments/webhooks [get]
+func (h *WebhookHandler) ListEvents(c *gin.Context) (any, error) {
+	if _, err := h.requireSuperAdmin(c); err != nil {
+		return nil, err
+	}
+
+	outcome := c.Query("outcome")
+	if outcome != "" && outcome != EventProcessed && outcome != EventFailed {
+		return nil, ErrInvalidEventOutcome
+	}
+
+	params := pagination.FromContext(c)
+	records, total, err := h.events.List(c.Request.Context(), outcome, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]WebhookEventResponse, len(records))
+	for i := range records {
+		data[i] = records[i].ToResponse()
+	}
+	return &PaginatedWebhookEventsResponse{
+		Data:       data,
+		Pagination: pagination.NewPaginationInfo(params, total),
+	}, nil
+}
+
+// ReprocessEvent godoc
+// @Summary      Reprocesar un webhook de pago
+// @Description  Vuelve a aplicar un evento de webhook que falló, por ejemplo tras corregir la causa. Los eventos ya procesados no se reaplican, porque podrían revertir cambios posteriores como la suspensión de una suscripción. Solo super administradores.
+// @Tags         webhooks
+// @Produce      json
+// @Param        event_id path string true "ID del evento de webhook"
+// @Success      200 {object} WebhookEventResponse
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Security     Bearer
+// @Router       /api/payments/webhooks/{event_id}/reprocess [post]
+func (h *WebhookHandler) ReprocessEvent(c *gin.Context) (any, error) {
+	eventID, err := primitive.ObjectIDFromHex(c.Param("event_id"))
+	if err != nil {
+		return nil, ErrInvalidWebhookEventID
+	}
+	userID, err := h.requireSuperAdmin(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := c.Request.Context()
+	record, err := h.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Outcome == EventProcessed {
+		return nil, ErrEventAlreadyProcessed
+	}
+
+	replay := &WebhookReplay{By: userID, At: time.Now()}
+	processErr := h.processEvent(ctx, record.event(), record, replay)
+	logger.Default().Info(ctx, "webhook_replayed",
+		"event_id", record.ID.Hex(),
+		"user_id", userID.Hex(),
+		"previous_outcome", record.Outcome,
+		"outcome", replay.Outcome,
+		"error", processErr,
+	)
+
+	updated, err := h.events.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// requireSuperAdmin returns the caller's ID when they are a platform super
+// admin. Stored webhooks belong to every clinic, so the per-tenant admin role
+// is not enough.
+func (h *WebhookHandler) requireSuperAdmin(c *gin.Context) (primitive.ObjectID, error) {
+	userID, err := primitive.ObjectIDFromHex(auth.GetUserID(c))
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidUserID
+	}
+	user, err := h.userRepo.FindByID(c.Request.Context(), userID.Hex())
+	if err != nil || !user.IsSuperAdmin {
+		return primitive.NilObjectID, ErrSuperAdminRequired
+	}
+	return userID, nil
+}
+
+// processEvent applies an event and records the outcome on its stored record,
+// if it has one. The handlers are idempotent per transaction, so applying an
+// event again does not charge or credit twice.
+func (h *WebhookHandler) processEvent(ctx context.Context, event *payment.WebhookEvent, record *WebhookEventRecord, replay *WebhookReplay) error {
+	processErr := h.handleWebhookEvent(ctx, event)
+	if record == nil {
+		return processErr
+	}
+
+	outcome, errMsg := EventProcessed, ""
+	if processErr != nil {
+		outcome, errMsg = EventFailed, processErr.Error()
+	}
+	if replay != nil {
+		replay.Outcome, replay.Error = outcome, errMsg
+	}
+	if err := h.events.SetOutcome(ctx, record.ID, outcome, errMsg, replay); err != nil {
+		logger.Default().Error(ctx, "webhook_store_error", "error", err, "event_id", record.ID.Hex())
+	}
+	return processErr
+}
+
 // validateSignature validates the webhook signature based on provider
 func (h *WebhookHandler) validateSignature(provider, signature, payload string) error {
 	if h.validator == nil {
@@ -144,6 +305,8 @@ func (h *WebhookHandler) handleWebhookEvent(ctx context.Context, event *payment.
 		if event.Status == "DECLINED" || event.Status == "ERROR" || event.Status == "VOIDED" {
 			return h.handlePaymentFailed(ctx, event)
 		}
+	case "checkout.session.completed":
+		return h.handlePaymentSucceeded(ctx, event)
 	case "payment.failed":
 		return h.handlePaymentFailed(ctx, event)
 	case "subscription.canceled":
@@ -155,6 +318,22 @@ func (h *WebhookHandler) handleWebhookEvent(ctx context.Context, event *payment.
 }
 
 func (h *WebhookHandler) handlePaymentSucceeded(ctx context.Context, event *payment.WebhookEvent) error {
+	// Los anticipos de citas llevan su propia referencia
+	if handled, err := h.appointmentService.ApplyDepositPayment(ctx, event); handled || err != nil {
+		if err == nil {
+			logger.Default().Info(ctx, "appointment_deposit_processed", "reference", event.Reference, "payment_link_id", event.PaymentLinkID, "amount", event.Amount)
+		}
+		return err
+	}
+
+	// Los pagos de facturas a propietarios no corresponden a suscripciones
+	if handled, err := h.invoiceService.ApplyPayment(ctx, event); handled || err != nil {
+		if err == nil {
+			logger.Default().Info(ctx, "invoice_payment_processed", "reference", event.Reference, "payment_link_id", event.PaymentLinkID, "amount", event.Amount)
+		}
+		return err
+	}
+
 	// Buscar el payment pendiente por external_transaction_id (payment link ID)
 	tenantObj, err := h.findTenantBySubscriptionID(ctx, event.SubscriptionID)
 	if err != nil {
@@ -195,6 +374,14 @@ func (h *WebhookHandler) handlePaymentFailed(ctx context.Context, event *payment
 		return nil
 	}
 
+	// Un intento fallido ya registrado no se duplica al reprocesar el evento
+	if event.TransactionID != "" {
+		if _, err := h.paymentService.FindByExternalTransactionID(ctx, event.TransactionID, payments.PaymentFailed); err == nil {
+			logger.Default().Info(ctx, "payment_failed_already_recorded", "transaction_id", event.TransactionID)
+			return nil
+		}
+	}
+
 	// Registrar pago fallido
 	paymentDTO := &payments.CreatePaymentDTO{
 		TenantID:              tenantObj.ID.Hex(),
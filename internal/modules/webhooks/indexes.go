@@ -0,0 +1,29 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+// EnsureIndexes creates the indexes of the webhook event store
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection(eventCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		// One record per delivery; events without a transaction are not deduplicated
+		{
+			Keys:    bson.D{{Key: "dedupe_key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"dedupe_key": bson.M{"$exists": true}}),
+		},
+		{Keys: bson.D{{Key: "outcome", Value: 1}, {Key: "received_at", Value: -1}}},
+	}, options.CreateIndexes().SetMaxTime(10*time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook event indexes: %w", err)
+	}
+	return nil
+}
@@ -2,16 +2,42 @@ package webhooks
 
 import (
 	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/invoices"
+	"github.com/eren_dev/go_server/internal/modules/loyalty"
+	"github.com/eren_dev/go_server/internal/modules/owners"
 	"github.com/eren_dev/go_server/internal/modules/payments"
 	"github.com/eren_dev/go_server/internal/modules/plans"
+	"github.com/eren_dev/go_server/internal/modules/sequences"
 	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/modules/users"
+	platformNotifications "github.com/eren_dev/go_server/internal/platform/notifications"
 	"github.com/eren_dev/go_server/internal/platform/payment"
 	"github.com/eren_dev/go_server/internal/platform/webhook"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"github.com/eren_dev/go_server/internal/shared/httpx"
 )
 
-func RegisterRoutes(r *httpx.Router, db *database.MongoDB, paymentManager *payment.PaymentManager, cfg *config.Config) {
+func RegisterRoutes(r *httpx.Router, db *database.MongoDB, paymentManager *payment.PaymentManager, pushProvider platformNotifications.PushProvider, cfg *config.Config) {
+	handler := newHandler(db, paymentManager, pushProvider, cfg)
+
+	// Rutas públicas de webhooks (sin autenticación)
+	webhooks := r.Group("/webhooks")
+	webhooks.POST("/:provider", handler.ProcessWebhook)
+}
+
+// RegisterAdminRoutes registra la consulta y el reproceso de webhooks guardados (JWT + RBAC).
+// Los webhooks son de todas las clínicas, así que además del rol admin el
+// handler exige que el usuario sea super admin.
+func RegisterAdminRoutes(private *httpx.Router, db *database.MongoDB, paymentManager *payment.PaymentManager, pushProvider platformNotifications.PushProvider, cfg *config.Config) {
+	handler := newHandler(db, paymentManager, pushProvider, cfg)
+
+	events := private.Group("/payments/webhooks")
+	events.GET("", handler.ListEvents)
+	events.POST("/:event_id/reprocess", handler.ReprocessEvent)
+}
+
+func newHandler(db *database.MongoDB, paymentManager *payment.PaymentManager, pushProvider platformNotifications.PushProvider, cfg *config.Config) *WebhookHandler {
 	// Inicializar dependencias
 	paymentRepo := payments.NewPaymentRepository(db)
 	paymentService := payments.NewPaymentService(paymentRepo)
@@ -27,9 +53,10 @@ func RegisterRoutes(r *httpx.Router, db *database.MongoDB, paymentManager *payme
 		validator.RegisterSecret("stripe", cfg.StripeWebhookSecret)
 	}
 
-	handler := NewWebhookHandler(paymentManager, paymentService, tenantRepo, planRepo, validator)
+	ownerRepo := owners.NewRepository(db)
+	invoiceService := invoices.NewService(invoices.NewInvoiceRepository(db), ownerRepo, tenantRepo, paymentManager, loyalty.NewService(loyalty.NewRepository(db), ownerRepo, tenantRepo), sequences.NewService(sequences.NewRepository(db)))
 
-	// Rutas públicas de webhooks (sin autenticación)
-	webhooks := r.Group("/webhooks")
-	webhooks.POST("/:provider", handler.ProcessWebhook)
+	appointmentService := appointments.BuildService(db, pushProvider, paymentManager, cfg)
+
+	return NewWebhookHandler(paymentManager, paymentService, invoiceService, appointmentService, tenantRepo, planRepo, validator, NewEventRepository(db), users.NewRepository(db))
 }
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process TTL cache for reference data that is read on
+// nearly every request but rarely written, such as tenant settings. Each
+// process keeps its own copy, so a write made by another instance is seen
+// once the entry expires; writes made through this process should invalidate
+// the affected keys right away.
+//
+// A Memory with a TTL of zero or less is a pass-through: Get always misses
+// and Set stores nothing.
+type Memory[V any] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]memoryEntry[V]
+}
+
+type memoryEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewMemory creates an in-memory cache whose entries live for ttl
+func NewMemory[V any](ttl time.Duration) *Memory[V] {
+	return &Memory[V]{ttl: ttl, entries: make(map[string]memoryEntry[V])}
+}
+
+// SetTTL changes the TTL of new entries and drops the cached ones
+func (m *Memory[V]) SetTTL(ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ttl = ttl
+	m.entries = make(map[string]memoryEntry[V])
+}
+
+// Get returns the cached value for key, if present and not expired
+func (m *Memory[V]) Get(key string) (V, bool) {
+	m.mu.RLock()
+	e, ok := m.entries[key]
+	m.mu.RUnlock()
+	if !ok || time.Now().After(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key for the cache's TTL
+func (m *Memory[V]) Set(key string, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl <= 0 {
+		return
+	}
+	m.entries[key] = memoryEntry[V]{value: value, expiresAt: time.Now().Add(m.ttl)}
+	if len(m.entries)%memorySweepEvery == 0 {
+		m.sweep()
+	}
+}
+
+// Load returns the cached value for key, calling load and caching its result
+// on a miss. Errors are returned as is and never cached.
+func (m *Memory[V]) Load(key string, load func() (V, error)) (V, error) {
+	if v, ok := m.Get(key); ok {
+		return v, nil
+	}
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+	m.Set(key, v)
+	return v, nil
+}
+
+// Delete drops key from the cache
+func (m *Memory[V]) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// DeletePrefix drops every key starting with prefix, e.g. all the entries of
+// a tenant built with TenantKey
+func (m *Memory[V]) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// memorySweepEvery is how many entries may pile up between sweeps of the
+// expired ones, so keys that are never read again do not grow the map forever
+const memorySweepEvery = 1024
+
+func (m *Memory[V]) sweep() {
+	now := time.Now()
+	for key, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// TenantKey builds a cache key scoped to a tenant, so two tenants never share
+// an entry. All of a tenant's keys start with TenantPrefix(tenantID).
+func TenantKey(tenantID string, parts ...string) string {
+	return TenantPrefix(tenantID) + strings.Join(parts, ":")
+}
+
+// TenantPrefix is the prefix of every key TenantKey builds for tenantID
+func TenantPrefix(tenantID string) string {
+	return "tenant:" + tenantID + ":"
+}
+
+var (
+	referenceMu    sync.Mutex
+	referenceTTL   time.Duration
+	referenceCache []interface{ SetTTL(time.Duration) }
+)
+
+// NewReference creates a Memory for hot reference data. Its TTL is set,
+// together with every other reference cache, by ConfigureReference; until
+// then it is a pass-through, which keeps tests and one-off commands reading
+// straight from the database.
+func NewReference[V any]() *Memory[V] {
+	referenceMu.Lock()
+	defer referenceMu.Unlock()
+	m := NewMemory[V](referenceTTL)
+	referenceCache = append(referenceCache, m)
+	return m
+}
+
+// ConfigureReference sets the TTL of every reference cache. It is called once
+// at startup; a TTL of zero or less disables them.
+func ConfigureReference(ttl time.Duration) {
+	referenceMu.Lock()
+	defer referenceMu.Unlock()
+	referenceTTL = ttl
+	for _, m := range referenceCache {
+		m.SetTTL(ttl)
+	}
+}
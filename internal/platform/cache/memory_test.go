@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemory_LoadCachesUntilInvalidated(t *testing.T) {
+	m := NewMemory[int](time.Minute)
+	calls := 0
+	load := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, err := m.Load("k", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	v, _ = m.Load("k", load)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, calls)
+
+	m.Delete("k")
+	v, _ = m.Load("k", load)
+	assert.Equal(t, 2, v)
+}
+
+func TestMemory_ErrorsAreNotCached(t *testing.T) {
+	m := NewMemory[int](time.Minute)
+	_, err := m.Load("k", func() (int, error) { return 0, errors.New("boom") })
+	assert.Error(t, err)
+
+	_, ok := m.Get("k")
+	assert.False(t, ok)
+}
+
+func TestMemory_ExpiredEntriesMiss(t *testing.T) {
+	m := NewMemory[string](time.Millisecond)
+	m.Set("k", "v")
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := m.Get("k")
+	assert.False(t, ok)
+}
+
+func TestMemory_ZeroTTLPassesThrough(t *testing.T) {
+	m := NewMemory[string](0)
+	m.Set("k", "v")
+
+	_, ok := m.Get("k")
+	assert.False(t, ok)
+}
+
+func TestMemory_DeletePrefixIsTenantScoped(t *testing.T) {
+	m := NewMemory[string](time.Minute)
+	m.Set(TenantKey("a", "vaccine", "rabies"), "a")
+	m.Set(TenantKey("ab", "vaccine", "rabies"), "ab")
+
+	m.DeletePrefix(TenantPrefix("a"))
+
+	_, ok := m.Get(TenantKey("a", "vaccine", "rabies"))
+	assert.False(t, ok)
+	v, ok := m.Get(TenantKey("ab", "vaccine", "rabies"))
+	assert.True(t, ok)
+	assert.Equal(t, "ab", v)
+}
+
+func TestConfigureReference_AppliesToExistingCaches(t *testing.T) {
+	m := NewReference[string]()
+	m.Set("k", "v")
+	_, ok := m.Get("k")
+	assert.False(t, ok)
+
+	ConfigureReference(time.Minute)
+	defer ConfigureReference(0)
+
+	m.Set("k", "v")
+	_, ok = m.Get("k")
+	assert.True(t, ok)
+}
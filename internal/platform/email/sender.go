@@ -0,0 +1,19 @@
+package email
+
+import "context"
+
+// Message is a plain-text email.
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers transactional emails.
+// The implementation is nil-safe: callers should check IsEnabled() before sending.
+type EmailSender interface {
+	// Send delivers the message to every recipient in To.
+	Send(ctx context.Context, msg Message) error
+	// IsEnabled returns false when the sender was not configured (e.g. no SMTP host).
+	IsEnabled() bool
+}
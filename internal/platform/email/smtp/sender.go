@@ -0,0 +1,64 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	netsmtp "net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/platform/email"
+)
+
+type smtpSender struct {
+	addr string
+	auth netsmtp.Auth
+	from string
+}
+
+// NewSender builds an SMTP-backed EmailSender from config.
+// Returns a disabled sender if SMTP_HOST is not set.
+func NewSender(cfg *config.Config) email.EmailSender {
+	if cfg.SMTPHost == "" {
+		slog.Info("email disabled: SMTP_HOST not set")
+		return &smtpSender{}
+	}
+
+	var auth netsmtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = netsmtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	slog.Info("email enabled", "host", cfg.SMTPHost)
+	return &smtpSender{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		auth: auth,
+		from: cfg.SMTPFrom,
+	}
+}
+
+func (s *smtpSender) IsEnabled() bool {
+	return s.addr != ""
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg email.Message) error {
+	if !s.IsEnabled() || len(msg.To) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return netsmtp.SendMail(s.addr, s.auth, s.from, msg.To, []byte(b.String()))
+}
@@ -2,4 +2,8 @@ package logger
 
 type contextKey string
 
-const requestIDKey contextKey = "request_id"
+const (
+	requestIDKey contextKey = "request_id"
+	tenantIDKey  contextKey = "tenant_id"
+	principalKey contextKey = "principal_id"
+)
@@ -10,3 +10,24 @@ func RequestIDFromContext(ctx context.Context) (string, bool) {
 	id, ok := ctx.Value(requestIDKey).(string)
 	return id, ok
 }
+
+// WithTenantID tags every log line written with ctx with the tenant.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey, tenantID)
+}
+
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantIDKey).(string)
+	return id, ok
+}
+
+// WithPrincipalID tags every log line written with ctx with the authenticated
+// user or owner.
+func WithPrincipalID(ctx context.Context, principalID string) context.Context {
+	return context.WithValue(ctx, principalKey, principalID)
+}
+
+func PrincipalIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(principalKey).(string)
+	return id, ok
+}
@@ -30,7 +30,13 @@ func NewSlogLogger(env string) Logger {
 
 func (l *SlogLogger) withContext(ctx context.Context, attrs []any) []any {
 	if rid, ok := RequestIDFromContext(ctx); ok {
-		return append(attrs, "request_id", rid)
+		attrs = append(attrs, "request_id", rid)
+	}
+	if tid, ok := TenantIDFromContext(ctx); ok {
+		attrs = append(attrs, "tenant_id", tid)
+	}
+	if pid, ok := PrincipalIDFromContext(ctx); ok {
+		attrs = append(attrs, "principal_id", pid)
 	}
 	return attrs
 }
@@ -21,10 +21,16 @@ type Metrics struct {
 	RBACChecksTotal        *prometheus.CounterVec
 	RBACChecksDeniedTotal  *prometheus.CounterVec
 	NotificationsTotal     *prometheus.CounterVec
+	NotificationsDropped   *prometheus.CounterVec
+
+	// Push delivery metrics
+	PushQueueDepth   prometheus.Gauge
+	PushSendDuration *prometheus.HistogramVec
 
 	// Database metrics
 	DBQueryDuration *prometheus.HistogramVec
 	DBQueriesTotal  *prometheus.CounterVec
+	DBSlowQueries   *prometheus.CounterVec
 }
 
 // NewMetrics creates and registers all Prometheus metrics
@@ -111,6 +117,29 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"type", "channel"},
 		),
+		NotificationsDropped: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "notifications_dropped_total",
+				Help: "Total number of notifications that could not be delivered by type and source",
+			},
+			[]string{"type", "source"},
+		),
+
+		// Push delivery metrics
+		PushQueueDepth: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "push_queue_depth",
+				Help: "Number of push notifications waiting for a send worker",
+			},
+		),
+		PushSendDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "push_send_duration_seconds",
+				Help:    "Push provider send latency in seconds by result",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"result"},
+		),
 
 		// Database metrics
 		DBQueryDuration: promauto.NewHistogramVec(
@@ -128,6 +157,13 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"collection", "operation"},
 		),
+		DBSlowQueries: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "db_slow_queries_total",
+				Help: "Total number of database queries slower than the configured threshold",
+			},
+			[]string{"collection"},
+		),
 	}
 
 	return m
@@ -166,12 +202,32 @@ func (m *Metrics) IncNotification(notificationType, channel string) {
 	m.NotificationsTotal.WithLabelValues(notificationType, channel).Inc()
 }
 
+// IncNotificationDropped increments the dropped notifications counter
+func (m *Metrics) IncNotificationDropped(notificationType, source string) {
+	m.NotificationsDropped.WithLabelValues(notificationType, source).Inc()
+}
+
+// SetPushQueueDepth sets the number of queued push sends
+func (m *Metrics) SetPushQueueDepth(depth float64) {
+	m.PushQueueDepth.Set(depth)
+}
+
+// ObservePushSend observes the latency of one push provider call
+func (m *Metrics) ObservePushSend(result string, seconds float64) {
+	m.PushSendDuration.WithLabelValues(result).Observe(seconds)
+}
+
 // ObserveDBQuery observes a database query
 func (m *Metrics) ObserveDBQuery(collection, operation string, duration float64) {
 	m.DBQueryDuration.WithLabelValues(collection, operation).Observe(duration)
 	m.DBQueriesTotal.WithLabelValues(collection, operation).Inc()
 }
 
+// IncSlowQuery increments the slow queries counter
+func (m *Metrics) IncSlowQuery(collection string) {
+	m.DBSlowQueries.WithLabelValues(collection).Inc()
+}
+
 // IncTenant sets the tenant gauge
 func (m *Metrics) SetTenants(status string, count float64) {
 	m.TenantsTotal.WithLabelValues(status).Set(count)
@@ -71,13 +71,21 @@ func (p *fcmProvider) Send(ctx context.Context, tokens []string, payload notific
 		return nil
 	}
 
-	_, err := circuitbreaker.ExecuteWithFCMBreaker(func() (interface{}, error) {
-		return nil, p.sendImpl(ctx, tokens, payload)
+	// Invalid tokens are a problem of the owner's devices, not of FCM, so they
+	// come back as the result and never trip the breaker
+	invalid, err := circuitbreaker.ExecuteWithFCMBreaker(func() (interface{}, error) {
+		return p.sendImpl(ctx, tokens, payload)
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if invalid, ok := invalid.([]string); ok && len(invalid) > 0 {
+		return &notifications.InvalidTokensError{Tokens: invalid}
+	}
+	return nil
 }
 
-func (p *fcmProvider) sendImpl(ctx context.Context, tokens []string, payload notifications.PushPayload) error {
+func (p *fcmProvider) sendImpl(ctx context.Context, tokens []string, payload notifications.PushPayload) ([]string, error) {
 	// Use SendEachForMulticast for batching (up to 500 tokens per call)
 	message := &messaging.MulticastMessage{
 		Tokens: tokens,
@@ -103,19 +111,27 @@ func (p *fcmProvider) sendImpl(ctx context.Context, tokens []string, payload not
 
 	resp, err := p.client.SendEachForMulticast(ctx, message)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var invalid []string
 	if resp.FailureCount > 0 {
 		for i, r := range resp.Responses {
-			if !r.Success {
-				slog.Warn("FCM send failed for token",
-					"token_index", i,
-					"error", r.Error,
-				)
+			if r.Success {
+				continue
 			}
+			// INVALID_ARGUMENT can also mean a bad payload, so only tokens FCM
+			// reports as unregistered are dropped
+			if messaging.IsUnregistered(r.Error) {
+				invalid = append(invalid, tokens[i])
+				continue
+			}
+			slog.Warn("FCM send failed for token",
+				"token_index", i,
+				"error", r.Error,
+			)
 		}
 	}
 
-	return nil
+	return invalid, nil
 }
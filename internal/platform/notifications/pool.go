@@ -0,0 +1,201 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned by Enqueue once the pool has been shut down.
+var ErrPoolClosed = errors.New("push pool closed")
+
+// PoolConfig bounds how hard the pool pushes on the provider.
+type PoolConfig struct {
+	// MaxConcurrent is the number of sends in flight at once.
+	MaxConcurrent int
+	// QueueSize is how many sends may wait for a worker before Enqueue blocks.
+	QueueSize int
+	// SendTimeout caps each individual provider call.
+	SendTimeout time.Duration
+}
+
+// lookupTimeout caps the token lookup of a queued job.
+const lookupTimeout = 10 * time.Second
+
+// PoolMetrics receives queue depth and send latency. metrics.Metrics implements it.
+type PoolMetrics interface {
+	SetPushQueueDepth(depth float64)
+	ObservePushSend(result string, seconds float64)
+}
+
+// AsyncPushProvider is a PushProvider that can also queue sends.
+// Callers that fan out (reminder sweeps, broadcasts) should prefer Enqueue.
+type AsyncPushProvider interface {
+	PushProvider
+	// Enqueue schedules a send and returns once it is queued, blocking while
+	// the queue is full until ctx is done.
+	Enqueue(ctx context.Context, job PushJob) error
+}
+
+// PushJob is a queued send. Its tokens are looked up on the worker, so the
+// caller does no database work for it.
+type PushJob struct {
+	// Tokens returns the device tokens to send to; none skips the send.
+	Tokens  func(ctx context.Context) []string
+	Payload PushPayload
+	// Done, if set, runs on the worker with the tokens and the send result.
+	Done func(tokens []string, err error)
+}
+
+// Pool wraps a PushProvider with a bounded worker pool and a per-send timeout,
+// so a burst of notifications cannot open unbounded concurrent FCM calls.
+type Pool struct {
+	provider PushProvider
+	cfg      PoolConfig
+	metrics  PoolMetrics
+
+	jobs chan PushJob
+	wg   sync.WaitGroup
+
+	// quit is closed when Close starts, releasing Enqueue calls blocked on a
+	// full queue so Close can take mu
+	quit      chan struct{}
+	closeOnce sync.Once
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPool starts cfg.MaxConcurrent workers sending through provider.
+// metrics may be nil.
+func NewPool(provider PushProvider, cfg PoolConfig, metrics PoolMetrics) *Pool {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 1
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+
+	p := &Pool{
+		provider: provider,
+		cfg:      cfg,
+		metrics:  metrics,
+		jobs:     make(chan PushJob, cfg.QueueSize),
+		quit:     make(chan struct{}),
+	}
+
+	p.wg.Add(cfg.MaxConcurrent)
+	for i := 0; i < cfg.MaxConcurrent; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *Pool) IsEnabled() bool {
+	return p.provider != nil && p.provider.IsEnabled()
+}
+
+// Send delivers synchronously, still bounded by the per-send timeout.
+func (p *Pool) Send(ctx context.Context, tokens []string, payload PushPayload) error {
+	return p.send(ctx, tokens, payload)
+}
+
+func (p *Pool) Enqueue(ctx context.Context, job PushJob) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.jobs <- job:
+		p.reportDepth()
+		return nil
+	case <-p.quit:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting sends and waits for queued ones to finish or for
+// ctx to expire.
+func (p *Pool) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() { close(p.quit) })
+
+	finished := make(chan struct{})
+	go func() {
+		p.mu.Lock()
+		if !p.closed {
+			p.closed = true
+			close(p.jobs)
+		}
+		p.mu.Unlock()
+
+		p.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		p.reportDepth()
+		p.run(job)
+	}
+}
+
+func (p *Pool) run(job PushJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	tokens := job.Tokens(ctx)
+	cancel()
+	if len(tokens) == 0 {
+		return
+	}
+
+	err := p.send(context.Background(), tokens, job.Payload)
+	if job.Done != nil {
+		job.Done(tokens, err)
+	}
+}
+
+func (p *Pool) send(ctx context.Context, tokens []string, payload PushPayload) error {
+	if p.cfg.SendTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.SendTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := p.provider.Send(ctx, tokens, payload)
+
+	result := "success"
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result = "timeout"
+		slog.Warn("push: send timed out", "timeout", p.cfg.SendTimeout, "tokens", len(tokens))
+	case errors.As(err, new(*InvalidTokensError)):
+		result = "invalid_tokens"
+	case err != nil:
+		result = "error"
+	}
+	if p.metrics != nil {
+		p.metrics.ObservePushSend(result, time.Since(start).Seconds())
+	}
+	return err
+}
+
+func (p *Pool) reportDepth() {
+	if p.metrics != nil {
+		p.metrics.SetPushQueueDepth(float64(len(p.jobs)))
+	}
+}
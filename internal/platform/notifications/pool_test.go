@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingProvider holds every send until release is closed
+type blockingProvider struct {
+	release chan struct{}
+}
+
+func (p *blockingProvider) Send(ctx context.Context, tokens []string, payload PushPayload) error {
+	<-p.release
+	return nil
+}
+
+func (p *blockingProvider) IsEnabled() bool { return true }
+
+func tokenJob() PushJob {
+	return PushJob{Tokens: func(context.Context) []string { return []string{"t"} }}
+}
+
+// An Enqueue blocked on a full queue must not keep Close past its deadline.
+func TestPool_CloseHonorsDeadlineWithBlockedEnqueue(t *testing.T) {
+	provider := &blockingProvider{release: make(chan struct{})}
+	defer close(provider.release)
+	pool := NewPool(provider, PoolConfig{MaxConcurrent: 1, QueueSize: 1}, nil)
+
+	ctx := context.Background()
+	assert.NoError(t, pool.Enqueue(ctx, tokenJob())) // taken by the worker
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, pool.Enqueue(ctx, tokenJob())) // fills the queue
+
+	blocked := make(chan error, 1)
+	go func() { blocked <- pool.Enqueue(ctx, tokenJob()) }()
+	time.Sleep(10 * time.Millisecond)
+
+	closeCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := pool.Close(closeCtx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	select {
+	case err := <-blocked:
+		assert.True(t, errors.Is(err, ErrPoolClosed))
+	case <-time.After(time.Second):
+		t.Fatal("blocked Enqueue was not released by Close")
+	}
+}
+
+func TestPool_EnqueueSkipsJobsWithoutTokens(t *testing.T) {
+	provider := &blockingProvider{release: make(chan struct{})}
+	pool := NewPool(provider, PoolConfig{MaxConcurrent: 1}, nil)
+
+	done := false
+	err := pool.Enqueue(context.Background(), PushJob{
+		Tokens: func(context.Context) []string { return nil },
+		Done:   func([]string, error) { done = true },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, pool.Close(context.Background()))
+	assert.False(t, done, "a job without tokens must not be sent")
+}
@@ -1,6 +1,9 @@
 package notifications
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // PushPayload is the data sent to the device.
 type PushPayload struct {
@@ -14,8 +17,19 @@ type PushPayload struct {
 // The implementation is nil-safe: callers should check IsEnabled() before sending.
 type PushProvider interface {
 	// Send delivers a push notification to one or more FCM/APNs tokens.
-	// Tokens that are no longer valid should be silently ignored by the implementation.
+	// Tokens that are no longer valid are reported with *InvalidTokensError.
 	Send(ctx context.Context, tokens []string, payload PushPayload) error
 	// IsEnabled returns false when the provider was not configured (e.g. no credentials).
 	IsEnabled() bool
 }
+
+// InvalidTokensError lists the tokens the push service reported as no longer
+// registered. The remaining tokens were sent, so callers should
+// forget the listed ones rather than treat the whole send as failed.
+type InvalidTokensError struct {
+	Tokens []string
+}
+
+func (e *InvalidTokensError) Error() string {
+	return fmt.Sprintf("push: %d invalid tokens", len(e.Tokens))
+}
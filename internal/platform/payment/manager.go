@@ -73,6 +73,24 @@ func (m *PaymentManager) CreateSubscription(ctx context.Context, req *Subscripti
 	return provider.CreateSubscription(ctx, req)
 }
 
+// CreatePaymentLink crea un link de pago usando el proveedor especificado o el default
+func (m *PaymentManager) CreatePaymentLink(ctx context.Context, req *PaymentLinkRequest, providerType *ProviderType) (*PaymentLinkResponse, error) {
+	var provider PaymentProvider
+	var err error
+	
+	if providerType != nil && *providerType != "" {
+		provider, err = m.GetProvider(*providerType)
+	} else {
+		provider, err = m.GetDefaultProvider()
+	}
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	return provider.CreatePaymentLink(ctx, req)
+}
+
 // CancelSubscription cancela una suscripción
 func (m *PaymentManager) CancelSubscription(ctx context.Context, subscriptionID string, providerType ProviderType) error {
 	provider, err := m.GetProvider(providerType)
@@ -21,7 +21,7 @@ type SubscriptionRequest struct {
 	CustomerEmail string
 	CustomerName  string
 	BillingPeriod string // monthly, annual
-	Amount        int64  // en centavos
+	Amount        int64  // en unidades menores de Currency (ver money.Factor)
 	Currency      string
 	RedirectURL   string // URL de redirección post-pago
 }
@@ -37,6 +37,26 @@ type SubscriptionResponse struct {
 	PaymentLinkURL string // URL de checkout para redirigir al usuario
 }
 
+// PaymentLinkRequest datos para crear un link de pago único (p. ej. una factura)
+type PaymentLinkRequest struct {
+	TenantID      string
+	Reference     string // identificador propio (ID de la factura), se devuelve en el webhook
+	Description   string
+	CustomerEmail string
+	Amount        int64 // en unidades menores de Currency (ver money.Factor)
+	Currency      string
+	RedirectURL   string
+}
+
+// PaymentLinkResponse link de pago creado
+type PaymentLinkResponse struct {
+	Provider ProviderType
+	LinkID   string
+	URL      string
+	Amount   int64
+	Currency string
+}
+
 // WebhookEvent evento de webhook
 type WebhookEvent struct {
 	Provider      ProviderType
@@ -48,6 +68,9 @@ type WebhookEvent struct {
 	Currency      string
 	Metadata      map[string]interface{}
 	RawPayload    []byte
+	// Reference y PaymentLinkID permiten asociar el pago a un link creado con CreatePaymentLink
+	Reference     string
+	PaymentLinkID string
 }
 
 // PaymentProvider interfaz que todos los proveedores deben implementar
@@ -61,6 +84,9 @@ type PaymentProvider interface {
 	// GetSubscription obtiene información de una suscripción
 	GetSubscription(ctx context.Context, subscriptionID string) (*SubscriptionResponse, error)
 	
+	// CreatePaymentLink crea un link de pago único por un monto fijo
+	CreatePaymentLink(ctx context.Context, req *PaymentLinkRequest) (*PaymentLinkResponse, error)
+	
 	// ProcessWebhook procesa un webhook del proveedor
 	ProcessWebhook(ctx context.Context, payload []byte, signature string) (*WebhookEvent, error)
 	
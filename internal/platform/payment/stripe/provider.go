@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/stripe/stripe-go/v76"
@@ -125,6 +126,52 @@ func (s *StripeProvider) CreateSubscription(ctx context.Context, req *payment.Su
 	}, nil
 }
 
+// CreatePaymentLink crea una sesión de checkout de pago único. La referencia
+// se envía como client_reference_id y vuelve en checkout.session.completed.
+func (s *StripeProvider) CreatePaymentLink(ctx context.Context, req *payment.PaymentLinkRequest) (*payment.PaymentLinkResponse, error) {
+	params := &stripe.CheckoutSessionParams{
+		SuccessURL: stripe.String(req.RedirectURL),
+		CancelURL:  stripe.String(req.RedirectURL),
+		PaymentMethodTypes: stripe.StringSlice([]string{
+			"card",
+		}),
+		Mode:              stripe.String(string(stripe.CheckoutSessionModePayment)),
+		ClientReferenceID: stripe.String(req.Reference),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency: stripe.String(strings.ToLower(req.Currency)),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String(req.Description),
+					},
+					UnitAmount: stripe.Int64(req.Amount),
+				},
+				Quantity: stripe.Int64(1),
+			},
+		},
+		Metadata: map[string]string{
+			"tenant_id": req.TenantID,
+			"reference": req.Reference,
+		},
+	}
+	if req.CustomerEmail != "" {
+		params.CustomerEmail = stripe.String(req.CustomerEmail)
+	}
+
+	stripeSession, err := session.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create checkout session: %v", ErrStripeAPI, err)
+	}
+
+	return &payment.PaymentLinkResponse{
+		Provider: payment.ProviderStripe,
+		LinkID:   stripeSession.ID,
+		URL:      stripeSession.URL,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}, nil
+}
+
 // CancelSubscription cancela una suscripción de Stripe
 func (s *StripeProvider) CancelSubscription(ctx context.Context, subscriptionID string) error {
 	// Extraer el ID de suscripción del ID de sesión si es necesario
@@ -219,6 +266,16 @@ func (s *StripeProvider) ProcessWebhook(ctx context.Context, payload []byte, sig
 			if sessionObj, ok := sessionData["object"].(map[string]interface{}); ok {
 				if id, ok := sessionObj["id"].(string); ok {
 					webhookEvent.TransactionID = id
+					webhookEvent.PaymentLinkID = id
+				}
+				if reference, ok := sessionObj["client_reference_id"].(string); ok {
+					webhookEvent.Reference = reference
+				}
+				if amount, ok := sessionObj["amount_total"].(float64); ok {
+					webhookEvent.Amount = int64(amount)
+				}
+				if currency, ok := sessionObj["currency"].(string); ok {
+					webhookEvent.Currency = strings.ToUpper(currency)
 				}
 				if subscription, ok := sessionObj["subscription"].(string); ok {
 					webhookEvent.SubscriptionID = subscription
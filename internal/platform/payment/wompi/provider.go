@@ -15,6 +15,7 @@ import (
 
 	"github.com/eren_dev/go_server/internal/platform/circuitbreaker"
 	"github.com/eren_dev/go_server/internal/platform/payment"
+	"github.com/eren_dev/go_server/internal/shared/signature"
 )
 
 var (
@@ -117,6 +118,34 @@ func (w *WompiProvider) createSubscriptionImpl(ctx context.Context, req *payment
 		Sku:             fmt.Sprintf("plan_%s_%s", req.PlanID, req.TenantID),
 	}
 
+	linkResp, err := w.createPaymentLink(ctx, linkReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calcular próxima fecha de facturación
+	var nextBilling *time.Time
+	switch req.BillingPeriod {
+	case "monthly":
+		next := time.Now().AddDate(0, 1, 0)
+		nextBilling = &next
+	case "annual":
+		next := time.Now().AddDate(1, 0, 0)
+		nextBilling = &next
+	}
+
+	return &payment.SubscriptionResponse{
+		SubscriptionID: linkResp.Data.ID,
+		Status:         "PENDING",
+		NextBillingAt:  nextBilling,
+		Amount:         linkResp.Data.Amount,
+		Currency:       linkResp.Data.Currency,
+		PaymentLinkURL: fmt.Sprintf("%s/%s", checkoutURL, linkResp.Data.ID),
+	}, nil
+}
+
+// createPaymentLink llama al endpoint /payment_links de Wompi
+func (w *WompiProvider) createPaymentLink(ctx context.Context, linkReq paymentLinkRequest) (*paymentLinkResponse, error) {
 	jsonData, err := json.Marshal(linkReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -150,25 +179,44 @@ func (w *WompiProvider) createSubscriptionImpl(ctx context.Context, req *payment
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	// Calcular próxima fecha de facturación
-	var nextBilling *time.Time
-	switch req.BillingPeriod {
-	case "monthly":
-		next := time.Now().AddDate(0, 1, 0)
-		nextBilling = &next
-	case "annual":
-		next := time.Now().AddDate(1, 0, 0)
-		nextBilling = &next
-	}
+	return &linkResp, nil
+}
 
-	return &payment.SubscriptionResponse{
-		SubscriptionID: linkResp.Data.ID,
-		Status:         "PENDING",
-		NextBillingAt:  nextBilling,
-		Amount:         linkResp.Data.Amount,
-		Currency:       linkResp.Data.Currency,
-		PaymentLinkURL: fmt.Sprintf("%s/%s", checkoutURL, linkResp.Data.ID),
-	}, nil
+// CreatePaymentLink crea un Payment Link de uso único por el monto indicado.
+// La referencia viaja en el SKU y el webhook trae el payment_link_id.
+func (w *WompiProvider) CreatePaymentLink(ctx context.Context, req *payment.PaymentLinkRequest) (*payment.PaymentLinkResponse, error) {
+	result, err := circuitbreaker.ExecuteWithPaymentBreaker(func() (interface{}, error) {
+		redirectURL := req.RedirectURL
+		if redirectURL == "" {
+			redirectURL = w.redirectURL
+		}
+
+		linkResp, err := w.createPaymentLink(ctx, paymentLinkRequest{
+			Name:            req.Description,
+			Description:     fmt.Sprintf("%s | Tenant %s", req.Description, req.TenantID),
+			SingleUse:       true,
+			CollectShipping: false,
+			Currency:        req.Currency,
+			AmountInCents:   req.Amount,
+			RedirectURL:     redirectURL,
+			Sku:             req.Reference,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &payment.PaymentLinkResponse{
+			Provider: payment.ProviderWompi,
+			LinkID:   linkResp.Data.ID,
+			URL:      fmt.Sprintf("%s/%s", checkoutURL, linkResp.Data.ID),
+			Amount:   linkResp.Data.Amount,
+			Currency: linkResp.Data.Currency,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*payment.PaymentLinkResponse), nil
 }
 
 // CancelSubscription cancela una suscripción (se maneja en la DB, Wompi no tiene suscripciones nativas)
@@ -277,6 +325,9 @@ func (w *WompiProvider) ProcessWebhook(ctx context.Context, payload []byte, _ st
 			if reference, ok := txData["reference"].(string); ok {
 				event.SubscriptionID = reference
 			}
+			if linkID, ok := txData["payment_link_id"].(string); ok {
+				event.PaymentLinkID = linkID
+			}
 		}
 	}
 
@@ -305,7 +356,7 @@ func (w *WompiProvider) validateSignature(data map[string]interface{}, propertie
 	hash := sha256.Sum256([]byte(concat))
 	calculated := hex.EncodeToString(hash[:])
 
-	if calculated != checksum {
+	if !signature.Equal(calculated, checksum) {
 		return ErrInvalidSignature
 	}
 
@@ -0,0 +1,191 @@
+// Package pdf writes simple text documents (letters, summaries) as PDF using
+// the standard Helvetica fonts, so no font files or external tools are needed.
+// Text is encoded as WinAnsi: Spanish accents render, other runes become "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page in points, with the margins and sizes used for every document.
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	margin       = 50.0
+	bodySize     = 10.5
+	headingSize  = 13.0
+	titleSize    = 16.0
+	lineSpacing  = 1.4
+	avgCharWidth = 0.5 // Helvetica average glyph width, in ems
+)
+
+type line struct {
+	text string
+	size float64
+	bold bool
+	gap  float64 // extra space before the line
+}
+
+// Document collects lines of text and lays them out on as many pages as needed.
+type Document struct {
+	lines []line
+}
+
+// New returns an empty document.
+func New() *Document {
+	return &Document{}
+}
+
+// Title adds a large bold line, typically once at the top.
+func (d *Document) Title(text string) {
+	d.lines = append(d.lines, line{text: text, size: titleSize, bold: true})
+}
+
+// Heading starts a section with a bold line.
+func (d *Document) Heading(text string) {
+	d.lines = append(d.lines, line{text: text, size: headingSize, bold: true, gap: bodySize})
+}
+
+// Text adds a paragraph, wrapped to the page width. Newlines start new lines.
+func (d *Document) Text(text string) {
+	for _, para := range strings.Split(text, "\n") {
+		for _, l := range wrap(para, bodySize) {
+			d.lines = append(d.lines, line{text: l, size: bodySize})
+		}
+	}
+}
+
+// Field adds a "label: value" paragraph; empty values are skipped.
+func (d *Document) Field(label, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	d.Text(label + ": " + value)
+}
+
+// Space adds a blank line.
+func (d *Document) Space() {
+	d.lines = append(d.lines, line{size: bodySize})
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	var pages [][]line
+	var current []line
+	y := pageHeight - margin
+	for _, l := range d.lines {
+		h := l.size*lineSpacing + l.gap
+		if y-h < margin && len(current) > 0 {
+			pages = append(pages, current)
+			current = nil
+			y = pageHeight - margin
+		}
+		current = append(current, l)
+		y -= h
+	}
+	if len(current) > 0 || len(pages) == 0 {
+		pages = append(pages, current)
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+
+	// Objects 1-4 are fixed; each page then takes a page and a content object
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+
+		var content bytes.Buffer
+		y := pageHeight - margin
+		for _, l := range page {
+			y -= l.size*lineSpacing + l.gap
+			if l.text == "" {
+				continue
+			}
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %g Tf %g %g Td (%s) Tj ET\n", font, l.size, margin, y, escape(l.text))
+		}
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// wrap splits text into lines that fit the page width at the given size.
+func wrap(text string, size float64) []string {
+	maxChars := int((pageWidth - 2*margin) / (size * avgCharWidth))
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, w := range words {
+		for len([]rune(w)) > maxChars {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			r := []rune(w)
+			lines = append(lines, string(r[:maxChars]))
+			w = string(r[maxChars:])
+		}
+		switch {
+		case current == "":
+			current = w
+		case len([]rune(current))+1+len([]rune(w)) <= maxChars:
+			current += " " + w
+		default:
+			lines = append(lines, current)
+			current = w
+		}
+	}
+	return append(lines, current)
+}
+
+// escape encodes text as a WinAnsi PDF string literal body.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 0x20 && r < 0x7f:
+			b.WriteByte(byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/platform/sms"
+)
+
+type gatewaySender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewSender builds an SMSSender that posts each message as JSON to an HTTP
+// gateway. Returns a disabled sender if SMS_GATEWAY_URL is not set.
+func NewSender(cfg *config.Config) sms.SMSSender {
+	if cfg.SMSGatewayURL == "" {
+		slog.Info("sms disabled: SMS_GATEWAY_URL not set")
+		return &gatewaySender{}
+	}
+
+	slog.Info("sms enabled", "gateway", cfg.SMSGatewayURL)
+	return &gatewaySender{
+		url:    cfg.SMSGatewayURL,
+		token:  cfg.SMSGatewayToken,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *gatewaySender) IsEnabled() bool {
+	return s.url != ""
+}
+
+func (s *gatewaySender) Send(ctx context.Context, msg sms.Message) error {
+	if !s.IsEnabled() || msg.To == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"to": msg.To, "body": msg.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package sms
+
+import "context"
+
+// Message is a text message to a single phone number.
+type Message struct {
+	To   string
+	Body string
+}
+
+// SMSSender delivers text messages.
+// The implementation is nil-safe: callers should check IsEnabled() before sending.
+type SMSSender interface {
+	// Send delivers the message to the number in To.
+	Send(ctx context.Context, msg Message) error
+	// IsEnabled returns false when the sender was not configured (e.g. no gateway URL).
+	IsEnabled() bool
+}
@@ -1,19 +1,17 @@
 package webhook
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/eren_dev/go_server/internal/shared/signature"
 )
 
 var (
 	// ErrMissingSignature is returned when the signature header is empty
-	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrMissingSignature = signature.ErrMissingSignature
 	// ErrInvalidSignature is returned when the signature validation fails
-	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidSignature = signature.ErrInvalidSignature
 )
 
 // SignatureValidator validates webhook signatures for different providers
@@ -52,19 +50,9 @@ func (v *SignatureValidator) Validate(provider, signature, payload string) error
 	return validateHMACSignature(signature, payload, secret)
 }
 
-// validateHMACSignature validates HMAC-SHA256 signature
-func validateHMACSignature(signature, payload, secret string) error {
-	// Compute expected signature
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(payload))
-	expectedSignature := hex.EncodeToString(h.Sum(nil))
-
-	// Compare signatures using constant-time comparison
-	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
-		return fmt.Errorf("%w: expected %s, got %s", ErrInvalidSignature, expectedSignature, signature)
-	}
-
-	return nil
+// validateHMACSignature validates a hex HMAC-SHA256 signature
+func validateHMACSignature(sig, payload, secret string) error {
+	return signature.NewHMAC(secret).Verify([]byte(payload), sig)
 }
 
 // ValidateWompi validates Wompi webhook signature
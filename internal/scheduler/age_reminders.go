@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// ageReminderWindowDays cuántos días después de cumplir la edad todavía se
+// envía el aviso, para no perderlo si el scheduler no corrió ese día
+const ageReminderWindowDays = 30
+
+// processAgeReminders avisa a los propietarios cuando su mascota cumple una
+// de las edades configuradas por la clínica (p. ej. chequeo geriátrico a los
+// 7 años en perros). Los pacientes sin fecha de nacimiento no se avisan y cada
+// paciente recibe cada aviso una sola vez. Corre una vez al día.
+func (s *Scheduler) processAgeReminders(ctx context.Context) {
+	today := time.Now().Format("2006-01-02")
+	if !s.claimDay(ctx, "age_reminders", today, &s.lastAgeReminderDay) {
+		return
+	}
+
+	tenants, err := s.tenantRepo.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("failed to list tenants for age reminders", "error", err)
+		return
+	}
+
+	for _, t := range tenants {
+		for _, reminder := range t.Settings.AgeReminders {
+			s.sendAgeReminders(ctx, &t, reminder)
+		}
+	}
+}
+
+func (s *Scheduler) sendAgeReminders(ctx context.Context, t *tenant.Tenant, reminder tenant.AgeReminder) {
+	now := time.Now()
+	bornUntil := now.AddDate(0, -reminder.AgeMonths, 0)
+	bornAfter := bornUntil.AddDate(0, 0, -ageReminderWindowDays)
+
+	speciesID := primitive.NilObjectID
+	if reminder.SpeciesID != nil {
+		speciesID = *reminder.SpeciesID
+	}
+
+	key := reminder.Key()
+	due, err := s.patientRepo.FindReachingAge(ctx, t.ID, speciesID, bornAfter, bornUntil, key)
+	if err != nil {
+		s.logger.Error("failed to find patients reaching age", "tenant_id", t.ID.Hex(), "reminder", key, "error", err)
+		return
+	}
+
+	for _, patient := range due {
+		owner, err := s.ownerRepo.FindByID(ctx, patient.OwnerID.Hex())
+		if err == nil && owner.NotificationPrefs.Allows(string(notifications.TypeCareReminder)) {
+			s.send(ctx, "age_reminder", &notifications.SendDTO{
+				OwnerID:  patient.OwnerID.Hex(),
+				TenantID: t.ID.Hex(),
+				Type:     notifications.TypeCareReminder,
+				Template: notifications.TemplateAgeReminder,
+				Vars:     map[string]string{"patient_name": patient.Name, "label": reminder.Label},
+				Data: map[string]string{
+					"patient_id": patient.ID.Hex(),
+					"age_months": strconv.Itoa(reminder.AgeMonths),
+				},
+				SendPush: true,
+			})
+		}
+
+		if err := s.patientRepo.MarkAgeReminderSent(ctx, t.ID, patient.ID, key); err != nil {
+			s.logger.Error("failed to mark age reminder sent", "patient_id", patient.ID.Hex(), "reminder", key, "error", err)
+		}
+	}
+}
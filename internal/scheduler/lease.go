@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/eren_dev/go_server/internal/shared/database"
+)
+
+const leaseCollection = "scheduler_leases"
+
+// EnsureIndexes creates the TTL index that removes expired scheduler leases
+func EnsureIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection(leaseCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}, options.CreateIndexes().SetMaxTime(10*time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to create scheduler lease indexes: %w", err)
+	}
+	return nil
+}
+
+// leaseStore hands out named leases shared by every instance, one document
+// per job. A lease belongs to its holder until it expires; the TTL index only
+// cleans up, expiry is checked on acquire.
+type leaseStore struct {
+	coll   *mongo.Collection
+	holder string
+}
+
+func newLeaseStore(db *database.MongoDB) *leaseStore {
+	host, _ := os.Hostname()
+	return &leaseStore{
+		coll:   db.Collection(leaseCollection),
+		holder: fmt.Sprintf("%s-%d-%s", host, os.Getpid(), primitive.NewObjectID().Hex()[18:]),
+	}
+}
+
+// acquire takes the lease for ttl if it is free, expired or already ours.
+// Another holder's live lease makes the upsert hit the _id, so it returns false.
+func (l *leaseStore) acquire(ctx context.Context, job string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": job,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": now}},
+			bson.M{"holder": l.holder},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": l.holder, "acquired_at": now, "expires_at": now.Add(ttl)}}
+
+	_, err := l.coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// renew extends a lease we hold, returning false when it was lost
+func (l *leaseStore) renew(ctx context.Context, job string, ttl time.Duration) (bool, error) {
+	res, err := l.coll.UpdateOne(ctx,
+		bson.M{"_id": job, "holder": l.holder},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(ttl)}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// release gives a lease we hold back before it expires
+func (l *leaseStore) release(ctx context.Context, job string) error {
+	_, err := l.coll.DeleteOne(ctx, bson.M{"_id": job, "holder": l.holder})
+	return err
+}
+
+// runJob runs a job on this instance only if it holds the job's lease. With
+// locking disabled every instance runs it. The lease is renewed while the job
+// runs and, once it finishes, kept until it expires so the other instances
+// skip the rest of the interval. A job cut short by shutdown releases it, so
+// another instance can pick the work up on its next tick.
+func (s *Scheduler) runJob(ctx context.Context, job string, fn func(context.Context)) {
+	if s.leases == nil {
+		fn(ctx)
+		return
+	}
+
+	ok, err := s.leases.acquire(ctx, job, s.leaseTTL)
+	if err != nil {
+		s.logger.Error("failed to acquire scheduler lease, skipping job", "job", job, "error", err)
+		return
+	}
+	if !ok {
+		s.logger.Debug("scheduler job held by another instance", "job", job)
+		return
+	}
+
+	done := make(chan struct{})
+	go s.renewLease(ctx, job, done)
+	fn(ctx)
+	close(done)
+
+	if ctx.Err() != nil {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.leases.release(releaseCtx, job); err != nil {
+			s.logger.Warn("failed to release scheduler lease", "job", job, "error", err)
+		}
+	}
+}
+
+// renewLease extends the lease every half TTL until done is closed
+func (s *Scheduler) renewLease(ctx context.Context, job string, done <-chan struct{}) {
+	ticker := time.NewTicker(s.leaseTTL / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ok, err := s.leases.renew(ctx, job, s.leaseTTL)
+			if err != nil {
+				s.logger.Warn("failed to renew scheduler lease", "job", job, "error", err)
+			} else if !ok {
+				s.logger.Warn("scheduler lease lost while the job was running", "job", job)
+				return
+			}
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// claimDay makes a once-a-day job run on a single instance: the first one to
+// take the job's lease for the day runs it and the others skip the day. The
+// day is recorded in last once decided, so later ticks skip without asking;
+// a failed lookup leaves it for the next tick.
+func (s *Scheduler) claimDay(ctx context.Context, job, day string, last *string) bool {
+	if *last == day {
+		return false
+	}
+	if s.leases == nil {
+		*last = day
+		return true
+	}
+
+	ok, err := s.leases.acquire(ctx, job+":"+day, 24*time.Hour)
+	if err != nil {
+		s.logger.Error("failed to acquire scheduler lease, skipping job", "job", job, "error", err)
+		return false
+	}
+	*last = day
+	return ok
+}
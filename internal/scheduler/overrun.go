@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// processOverrunAppointments avisa a los propietarios que esperan cuando una
+// cita en curso se pasa de su hora de fin. El retraso de cada cita siguiente
+// del mismo veterinario ese día se estima suponiendo que la cita en curso
+// termina ahora y que las siguientes duran lo agendado. Solo aplica a las
+// clínicas que configuraron un retraso mínimo para avisar.
+func (s *Scheduler) processOverrunAppointments(ctx context.Context) {
+	tenants, err := s.tenantRepo.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("failed to list tenants for overrun appointments", "error", err)
+		return
+	}
+
+	for _, t := range tenants {
+		if t.Settings.OverrunNoticeMinutes <= 0 {
+			continue
+		}
+		s.processOverrunAppointmentsForTenant(ctx, &t)
+	}
+}
+
+func (s *Scheduler) processOverrunAppointmentsForTenant(ctx context.Context, t *tenant.Tenant) {
+	now := time.Now()
+	threshold := time.Duration(t.Settings.OverrunNoticeMinutes) * time.Minute
+
+	running, err := s.appointmentRepo.FindOverrunning(ctx, t.ID, now.Add(-threshold))
+	if err != nil {
+		s.logger.Error("failed to find overrunning appointments", "tenant_id", t.ID.Hex(), "error", err)
+		return
+	}
+
+	// Un veterinario con dos citas en curso retrasa una sola agenda
+	seen := make(map[primitive.ObjectID]bool)
+	for _, appt := range running {
+		if now.Sub(appt.EndsAt()) < threshold || seen[appt.VeterinarianID] {
+			continue
+		}
+		seen[appt.VeterinarianID] = true
+		s.noticeDelays(ctx, t, &appt, now, threshold)
+	}
+}
+
+// noticeDelays avisa el retraso estimado a los propietarios de las citas
+// siguientes del veterinario. Cada cita se vuelve a avisar solo si pasó el
+// intervalo de la clínica y el retraso creció al menos el umbral.
+func (s *Scheduler) noticeDelays(ctx context.Context, t *tenant.Tenant, running *appointments.Appointment, now time.Time, threshold time.Duration) {
+	y, m, d := running.ScheduledAt.In(now.Location()).Date()
+	endOfDay := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()).Add(-time.Nanosecond)
+
+	agenda, err := s.appointmentRepo.FindByVeterinarian(ctx, running.VeterinarianID, running.ScheduledAt, endOfDay, t.ID)
+	if err != nil {
+		s.logger.Error("failed to load veterinarian agenda for overrun", "appointment_id", running.ID.Hex(), "error", err)
+		return
+	}
+
+	interval := t.Settings.OverrunNoticeInterval()
+	free := now
+	for _, next := range agenda {
+		if next.ID == running.ID || (next.Status != appointments.AppointmentStatusScheduled && next.Status != appointments.AppointmentStatusConfirmed) {
+			continue
+		}
+
+		start := next.ScheduledAt
+		if free.After(start) {
+			start = free
+		}
+		free = start.Add(time.Duration(next.Duration) * time.Minute)
+
+		delay := start.Sub(next.ScheduledAt)
+		if delay < threshold {
+			// La agenda se pone al día: las citas siguientes empiezan a tiempo
+			break
+		}
+
+		minutes := delayMinutes(delay)
+		if next.DelayNotifiedAt != nil && (now.Sub(*next.DelayNotifiedAt) < interval || minutes-next.DelayNoticeMinutes < t.Settings.OverrunNoticeMinutes) {
+			continue
+		}
+		s.sendDelayNotice(ctx, &next, minutes)
+
+		if err := s.appointmentRepo.MarkDelayNotified(ctx, next.ID, next.TenantID, minutes, now); err != nil {
+			s.logger.Error("failed to mark delay notified", "appointment_id", next.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// delayMinutes redondea el retraso hacia arriba a múltiplos de 5 minutos,
+// para no prometer más precisión de la que tiene la estimación
+func delayMinutes(delay time.Duration) int {
+	minutes := int((delay + time.Minute - 1) / time.Minute)
+	return (minutes + 4) / 5 * 5
+}
+
+func (s *Scheduler) sendDelayNotice(ctx context.Context, appt *appointments.Appointment, minutes int) {
+	owner, err := s.ownerRepo.FindByID(ctx, appt.OwnerID.Hex())
+	if err != nil || !owner.NotificationPrefs.Allows(string(notifications.TypeAppointmentReminder)) {
+		return
+	}
+
+	s.send(ctx, "appointment_delayed", &notifications.SendDTO{
+		OwnerID:  appt.OwnerID.Hex(),
+		TenantID: appt.TenantID.Hex(),
+		Type:     notifications.TypeAppointmentReminder,
+		Template: notifications.TemplateAppointmentDelayed,
+		Vars:     map[string]string{"minutes": strconv.Itoa(minutes)},
+		Times:    map[string]time.Time{"date": appt.ScheduledAt},
+		Data: map[string]string{
+			"appointment_id": appt.ID.Hex(),
+			"delay_minutes":  strconv.Itoa(minutes),
+		},
+		SendPush: true,
+	})
+}
@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// processStalePushTokens borra los tokens push de dispositivos que no volvieron
+// a registrarse dentro del TTL, para no seguir enviando a apps desinstaladas.
+// Corre una vez al día, en el primer tick del día.
+func (s *Scheduler) processStalePushTokens(ctx context.Context) {
+	if s.pushTokenTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	if !s.claimDay(ctx, "push_token_prune", today, &s.lastTokenPruneDay) {
+		return
+	}
+
+	owners, err := s.ownerRepo.PrunePushTokens(ctx, now.Add(-s.pushTokenTTL))
+	if err != nil {
+		s.logger.Error("failed to prune stale push tokens", "error", err)
+		return
+	}
+	if owners > 0 {
+		s.logger.Info("stale push tokens pruned", "owners", owners, "ttl", s.pushTokenTTL)
+	}
+}
@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/audit"
+)
+
+// purgeableCollections are the tenant data collections that soft-delete with
+// deleted_at. Platform collections (tenants, users, RBAC) are never purged.
+// Children come before their parents so a parent can go in the same run.
+var purgeableCollections = []string{
+	"appointments",
+	"medical_records",
+	"medical_histories",
+	"allergies",
+	"vaccinations",
+	"vaccines",
+	"lab_orders",
+	"lab_tests",
+	"products",
+	"product_categories",
+	"holidays",
+	"patients",
+	"owners",
+}
+
+// childReference is a field of a collection pointing at a parent document
+type childReference struct {
+	collection string
+	field      string
+}
+
+// purgeChildren lists, per parent collection, the documents referencing it.
+// A parent is only purged once none of them is left, so purging never leaves
+// a retained record pointing at a missing patient or owner.
+var purgeChildren = map[string][]childReference{
+	"patients": {
+		{"appointments", "patient_id"},
+		{"medical_records", "patient_id"},
+		{"medical_histories", "patient_id"},
+		{"allergies", "patient_id"},
+		{"vaccinations", "patient_id"},
+		{"lab_orders", "patient_id"},
+		{"consents", "patient_id"},
+		{"invoices", "patient_id"},
+		{"weight_measurements", "patient_id"},
+	},
+	"owners": {
+		{"patients", "owner_id"},
+		{"appointments", "owner_id"},
+		{"medical_records", "owner_id"},
+		{"vaccinations", "owner_id"},
+		{"lab_orders", "owner_id"},
+		{"invoices", "owner_id"},
+		{"consents", "owner_id"},
+		{"loyalty_transactions", "owner_id"},
+		{"owner_intakes", "owner_id"},
+	},
+}
+
+// retentionDays returns how long soft-deleted documents of a collection are
+// kept; 0 means forever.
+func (s *Scheduler) retentionDays(collection string) int {
+	if days, ok := s.retention[collection]; ok {
+		return days
+	}
+	return s.retentionDefault
+}
+
+// processRetentionPurge hard-deletes documents soft-deleted longer ago than
+// their collection's retention. It runs once a day, on the first tick inside
+// the configured hour. In dry-run mode it only counts what would go.
+func (s *Scheduler) processRetentionPurge(ctx context.Context) {
+	now := time.Now()
+	if now.Hour() != s.purgeHour {
+		return
+	}
+	today := now.Format("2006-01-02")
+	if !s.claimDay(ctx, "retention_purge", today, &s.lastPurgeDay) {
+		return
+	}
+
+	counts := make(map[string]interface{}, len(purgeableCollections))
+	var total int64
+	for _, name := range purgeableCollections {
+		days := s.retentionDays(name)
+		if days <= 0 {
+			continue
+		}
+
+		filter := bson.M{"deleted_at": bson.M{"$lt": now.AddDate(0, 0, -days)}}
+		coll := s.db.Collection(name)
+
+		if refs, ok := purgeChildren[name]; ok {
+			ids, err := s.unreferencedIDs(ctx, name, filter, refs)
+			if err != nil {
+				s.logger.Error("failed to check purgeable documents for children", "collection", name, "error", err)
+				continue
+			}
+			filter = bson.M{"_id": bson.M{"$in": ids}}
+		}
+
+		var n int64
+		if s.retentionDryRun {
+			c, err := coll.CountDocuments(ctx, filter)
+			if err != nil {
+				s.logger.Error("failed to count purgeable documents", "collection", name, "error", err)
+				continue
+			}
+			n = c
+		} else {
+			res, err := coll.DeleteMany(ctx, filter)
+			if err != nil {
+				s.logger.Error("failed to purge soft-deleted documents", "collection", name, "error", err)
+				continue
+			}
+			n = res.DeletedCount
+		}
+
+		counts[name] = n
+		total += n
+	}
+
+	action := "purge"
+	description := fmt.Sprintf("Purged %d soft-deleted documents past retention", total)
+	if s.retentionDryRun {
+		action = "purge_dry_run"
+		description = fmt.Sprintf("Dry run: %d soft-deleted documents past retention would be purged", total)
+	}
+	s.logger.Info("retention purge finished", "dry_run", s.retentionDryRun, "total", total)
+
+	err := s.auditSvc.Log(ctx, primitive.NilObjectID, primitive.NilObjectID, audit.EventDataPurged, "retention", action, description, &audit.LogOptions{
+		Metadata: map[string]interface{}{
+			"dry_run":      s.retentionDryRun,
+			"default_days": s.retentionDefault,
+			"overrides":    s.retention,
+			"counts":       counts,
+			"total":        total,
+		},
+	})
+	if err != nil {
+		s.logger.Error("failed to audit retention purge", "error", err)
+	}
+}
+
+// unreferencedIDs returns the IDs of documents of collection matching filter
+// that no document in refs still points at, deleted or not.
+func (s *Scheduler) unreferencedIDs(ctx context.Context, collection string, filter bson.M, refs []childReference) ([]primitive.ObjectID, error) {
+	values, err := s.db.Collection(collection).Distinct(ctx, "_id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			candidates = append(candidates, id)
+		}
+	}
+
+	for _, ref := range refs {
+		if len(candidates) == 0 {
+			break
+		}
+		referenced, err := s.db.Collection(ref.collection).Distinct(ctx, ref.field, bson.M{ref.field: bson.M{"$in": candidates}})
+		if err != nil {
+			return nil, err
+		}
+		if len(referenced) == 0 {
+			continue
+		}
+
+		kept := make(map[primitive.ObjectID]bool, len(referenced))
+		for _, v := range referenced {
+			if id, ok := v.(primitive.ObjectID); ok {
+				kept[id] = true
+			}
+		}
+		candidates = slices.DeleteFunc(candidates, func(id primitive.ObjectID) bool { return kept[id] })
+	}
+	return candidates, nil
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// referencingSchemas maps every module struct carrying a patient_id or
+// owner_id field to the collection it is stored in.
+var referencingSchemas = map[string]string{
+	"appointments.Appointment":       "appointments",
+	"consents.Consent":               "consents",
+	"invoices.Invoice":               "invoices",
+	"laboratory.LabOrder":            "lab_orders",
+	"loyalty.Transaction":            "loyalty_transactions",
+	"medical_records.Allergy":        "allergies",
+	"medical_records.MedicalHistory": "medical_histories",
+	"medical_records.MedicalRecord":  "medical_records",
+	"owners.Intake":                  "owner_intakes",
+	"patients.Patient":               "patients",
+	"patients.WeightMeasurement":     "weight_measurements",
+	"vaccinations.Vaccination":       "vaccinations",
+}
+
+// unguardedSchemas carry a patient_id or owner_id that must not keep a
+// parent from being purged.
+var unguardedSchemas = map[string]string{
+	"tenant.Tenant":               "owner_id is the owning user, not a clinic owner",
+	"owners.ContactVerification":  "short-lived codes that expire on their own",
+	"notifications.Notification":  "delivery log",
+	"notifications.OutboxMessage": "delivery queue",
+	"notifications.DeadLetter":    "delivery log",
+}
+
+// Every collection referencing a patient or an owner must be checked before
+// the parent is purged, or purging would leave it dangling.
+func TestPurgeChildren_CoverEveryReference(t *testing.T) {
+	found := referencingStructs(t, filepath.Join("..", "modules"))
+	assert.NotEmpty(t, found)
+
+	for name, fields := range found {
+		if _, ok := unguardedSchemas[name]; ok {
+			continue
+		}
+		collection, ok := referencingSchemas[name]
+		if !ok {
+			t.Errorf("%s references %v: add its collection to referencingSchemas and purgeChildren", name, fields)
+			continue
+		}
+		for _, field := range fields {
+			parent := "patients"
+			if field == "owner_id" {
+				parent = "owners"
+			}
+			assert.Contains(t, purgeChildren[parent], childReference{collection, field},
+				"%s.%s is not checked before purging %s", collection, field, parent)
+		}
+	}
+}
+
+// referencingStructs returns, per package-qualified struct name, the
+// patient_id and owner_id bson fields declared by the non-test sources under root.
+func referencingStructs(t *testing.T, root string) map[string][]string {
+	t.Helper()
+	found := map[string][]string{}
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if field.Tag == nil {
+						continue
+					}
+					tag, _ := strconv.Unquote(field.Tag.Value)
+					name, _, _ := strings.Cut(reflect.StructTag(tag).Get("bson"), ",")
+					if name == "patient_id" || name == "owner_id" {
+						key := file.Name.Name + "." + ts.Name.Name
+						found[key] = append(found[key], name)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
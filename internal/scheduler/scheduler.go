@@ -4,33 +4,97 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/eren_dev/go_server/internal/app/lifecycle"
 	"github.com/eren_dev/go_server/internal/config"
 	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/audit"
+	"github.com/eren_dev/go_server/internal/modules/inventory"
+	"github.com/eren_dev/go_server/internal/modules/laboratory"
 	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/owners"
+	"github.com/eren_dev/go_server/internal/modules/patients"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/platform/email"
 	"github.com/eren_dev/go_server/internal/shared/database"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Scheduler struct {
+	db              *database.MongoDB
 	appointmentRepo appointments.AppointmentRepository
+	labOrderRepo    laboratory.LabOrderRepository
+	tenantRepo      tenant.TenantRepository
+	userRepo        users.UserRepository
+	patientRepo     patients.PatientRepository
+	ownerRepo       owners.OwnerRepository
+	inventorySvc    *inventory.Service
 	notificationSvc *notifications.Service
+	auditSvc        *audit.Service
+	emailSender     email.EmailSender
 	interval        time.Duration
+	digestWeekday   int
+	digestHour      int
+	lastDigestDay   string
 	logger          *slog.Logger
 	stopCh          chan struct{}
+
+	retentionDefault int
+	retention        map[string]int
+	retentionDryRun  bool
+	purgeHour        int
+	lastPurgeDay     string
+
+	pushTokenTTL      time.Duration
+	lastTokenPruneDay string
+
+	lastAgeReminderDay string
+
+	// leases is nil when locking is off and every instance runs every job
+	leases   *leaseStore
+	leaseTTL time.Duration
 }
 
-func New(db *database.MongoDB, notificationSvc *notifications.Service, logger *slog.Logger, cfg *config.Config) *Scheduler {
-	return &Scheduler{
+func New(db *database.MongoDB, notificationSvc *notifications.Service, emailSender email.EmailSender, logger *slog.Logger, cfg *config.Config) *Scheduler {
+	userRepo := users.NewRepository(db)
+	s := &Scheduler{
+		db:              db,
 		appointmentRepo: appointments.NewAppointmentRepository(db),
+		labOrderRepo:    laboratory.NewLabOrderRepository(db),
+		tenantRepo:      tenant.NewTenantRepository(db),
+		userRepo:        userRepo,
+		patientRepo:     patients.NewPatientRepository(db),
+		ownerRepo:       owners.NewRepository(db),
+		inventorySvc:    inventory.NewService(inventory.NewProductRepository(db), userRepo, notificationSvc, tenant.NewTenantRepository(db), cfg),
 		notificationSvc: notificationSvc,
+		auditSvc:        audit.NewService(audit.NewRepository(db)),
+		emailSender:     emailSender,
 		interval:        time.Duration(cfg.SchedulerIntervalMinutes) * time.Minute,
+		digestWeekday:   cfg.WeeklyDigestWeekday,
+		digestHour:      cfg.WeeklyDigestHour,
 		logger:          logger,
 		stopCh:          make(chan struct{}),
+
+		retentionDefault: cfg.RetentionDefaultDays,
+		retention:        cfg.RetentionDays,
+		retentionDryRun:  cfg.RetentionDryRun,
+		purgeHour:        cfg.RetentionPurgeHour,
+
+		pushTokenTTL: time.Duration(cfg.PushTokenTTLDays) * 24 * time.Hour,
 	}
+
+	if cfg.SchedulerLockEnabled {
+		s.leases = newLeaseStore(db)
+		s.leaseTTL = s.interval
+		if cfg.SchedulerLockTTLSeconds > 0 {
+			s.leaseTTL = time.Duration(cfg.SchedulerLockTTLSeconds) * time.Second
+		}
+	}
+	return s
 }
 
 func (s *Scheduler) Start(ctx context.Context, workers *lifecycle.Workers) {
@@ -40,13 +104,24 @@ func (s *Scheduler) Start(ctx context.Context, workers *lifecycle.Workers) {
 		ticker := time.NewTicker(s.interval)
 		defer ticker.Stop()
 
-		s.logger.Info("appointment scheduler started", "interval", s.interval)
+		s.logger.Info("appointment scheduler started", "interval", s.interval, "locking", s.leases != nil)
 
 		for {
 			select {
 			case <-ticker.C:
-				s.processReminders(ctx)
-				s.processAutoCancellations(ctx)
+				s.runJob(ctx, "reminders", s.processReminders)
+				s.runJob(ctx, "auto_cancellations", s.processAutoCancellations)
+				s.runJob(ctx, "expired_deposits", s.processExpiredDeposits)
+				s.runJob(ctx, "lab_sla_breaches", s.processLabSLABreaches)
+				s.runJob(ctx, "expiry_writeoffs", s.processExpiryWriteOffs)
+				s.runJob(ctx, "weekly_digests", s.processWeeklyDigests)
+				s.runJob(ctx, "retention_purge", s.processRetentionPurge)
+				s.runJob(ctx, "deferred_notifications", s.processDeferredNotifications)
+				s.runJob(ctx, "outbox", s.processOutbox)
+				s.runJob(ctx, "stale_appointments", s.processStaleAppointments)
+				s.runJob(ctx, "overrun_appointments", s.processOverrunAppointments)
+				s.runJob(ctx, "stale_push_tokens", s.processStalePushTokens)
+				s.runJob(ctx, "age_reminders", s.processAgeReminders)
 			case <-s.stopCh:
 				s.logger.Info("appointment scheduler stopped")
 				return
@@ -63,7 +138,8 @@ func (s *Scheduler) Stop() {
 }
 
 func (s *Scheduler) processReminders(ctx context.Context) {
-	// Buscar citas próximas en las siguientes 24h que estén confirmed
+	// Buscar citas próximas en las siguientes 24h; las agendadas también reciben
+	// recordatorio para que el propietario pueda acusar recibo o confirmarlas
 	upcoming, err := s.appointmentRepo.FindUpcoming(ctx, primitive.NilObjectID, 24)
 	if err != nil {
 		s.logger.Error("failed to find upcoming appointments", "error", err)
@@ -72,7 +148,15 @@ func (s *Scheduler) processReminders(ctx context.Context) {
 
 	now := time.Now()
 	for _, appt := range upcoming {
-		if appt.Status != "confirmed" {
+		if appt.Status != appointments.AppointmentStatusConfirmed && appt.Status != appointments.AppointmentStatusScheduled {
+			continue
+		}
+		// El propietario pidió no recibir recordatorios de esta cita
+		if appt.DisableReminders {
+			continue
+		}
+		// No recordar citas de pacientes inactivos o fallecidos
+		if patient, err := s.patientRepo.FindByID(ctx, appt.TenantID, appt.PatientID.Hex()); err != nil || !patient.Active || patient.IsDeceased() {
 			continue
 		}
 
@@ -80,28 +164,38 @@ func (s *Scheduler) processReminders(ctx context.Context) {
 
 		// Recordatorio 24h (entre 23h30m y 24h30m)
 		if timeUntil >= 23*time.Hour+30*time.Minute && timeUntil <= 24*time.Hour+30*time.Minute {
-			s.sendReminder(ctx, &appt, "24 horas")
+			s.sendReminder(ctx, &appt, 24)
 		}
 
 		// Recordatorio 2h (entre 1h30m y 2h30m)
 		if timeUntil >= 1*time.Hour+30*time.Minute && timeUntil <= 2*time.Hour+30*time.Minute {
-			s.sendReminder(ctx, &appt, "2 horas")
+			s.sendReminder(ctx, &appt, 2)
 		}
 	}
 }
 
-func (s *Scheduler) sendReminder(ctx context.Context, appt *appointments.Appointment, timeframe string) {
-	s.notificationSvc.Send(ctx, &notifications.SendDTO{
+func (s *Scheduler) sendReminder(ctx context.Context, appt *appointments.Appointment, hours int) {
+	s.send(ctx, "appointment_reminder", &notifications.SendDTO{
 		OwnerID:  appt.OwnerID.Hex(),
 		TenantID: appt.TenantID.Hex(),
 		Type:     notifications.TypeAppointmentReminder,
-		Title:    "Recordatorio de cita",
-		Body:     fmt.Sprintf("Tu cita es en %s (%s)", timeframe, appt.ScheduledAt.Format("02/01/2006 15:04")),
-		Data:     map[string]string{"appointment_id": appt.ID.Hex()},
+		Template: notifications.TemplateAppointmentReminder,
+		Vars:     map[string]string{"hours": strconv.Itoa(hours), "timeframe": fmt.Sprintf("%d horas", hours)},
+		Times:    map[string]time.Time{"date": appt.ScheduledAt},
+		// action tells the app to open the acknowledge deep link for appointment_id
+		Data:     map[string]string{"appointment_id": appt.ID.Hex(), "action": "acknowledge"},
 		SendPush: true,
 	})
 }
 
+// send delivers an owner notification and dead-letters it when Send fails, so
+// a broken template or store shows up instead of silently dropping the sweep
+func (s *Scheduler) send(ctx context.Context, source string, dto *notifications.SendDTO) {
+	if err := s.notificationSvc.Send(ctx, dto); err != nil {
+		s.notificationSvc.RecordFailedSend(ctx, dto, source, err)
+	}
+}
+
 func (s *Scheduler) processAutoCancellations(ctx context.Context) {
 	cutoff := time.Now().Add(-24 * time.Hour)
 
@@ -125,12 +219,12 @@ func (s *Scheduler) processAutoCancellations(ctx context.Context) {
 			continue
 		}
 
-		s.notificationSvc.Send(ctx, &notifications.SendDTO{
+		s.send(ctx, "appointment_auto_cancel", &notifications.SendDTO{
 			OwnerID:  appt.OwnerID.Hex(),
 			TenantID: appt.TenantID.Hex(),
 			Type:     notifications.TypeAppointmentCancelled,
-			Title:    "Cita cancelada automáticamente",
-			Body:     fmt.Sprintf("La cita del %s fue cancelada por no ser confirmada en 24 horas", appt.ScheduledAt.Format("02/01/2006 15:04")),
+			Template: notifications.TemplateAppointmentAutoCancelled,
+			Times:    map[string]time.Time{"date": appt.ScheduledAt},
 			Data:     map[string]string{"appointment_id": appt.ID.Hex()},
 			SendPush: true,
 		})
@@ -138,3 +232,145 @@ func (s *Scheduler) processAutoCancellations(ctx context.Context) {
 		s.logger.Info("auto-cancelled unconfirmed appointment", "id", appt.ID.Hex())
 	}
 }
+
+// processExpiredDeposits libera las citas cuyo anticipo no se pagó a tiempo.
+// Solo cancela si siguen en espera, por si el webhook del pago llega a la vez.
+func (s *Scheduler) processExpiredDeposits(ctx context.Context) {
+	expired, err := s.appointmentRepo.FindExpiredDeposits(ctx, time.Now())
+	if err != nil {
+		s.logger.Error("failed to find expired appointment deposits", "error", err)
+		return
+	}
+
+	for _, appt := range expired {
+		now := time.Now()
+		applied, err := s.appointmentRepo.UpdateIfStatus(ctx, appt.ID, appt.TenantID, appointments.AppointmentStatusAwaitingDeposit, bson.M{
+			"status":        appointments.AppointmentStatusCancelled,
+			"cancelled_at":  now,
+			"cancel_reason": "Auto-cancelada: anticipo no pagado a tiempo",
+		})
+		if err != nil {
+			s.logger.Error("failed to release appointment with expired deposit", "id", appt.ID.Hex(), "error", err)
+			continue
+		}
+		if !applied {
+			continue
+		}
+
+		s.appointmentRepo.CreateStatusTransition(ctx, &appointments.AppointmentStatusTransition{
+			TenantID:      appt.TenantID,
+			AppointmentID: appt.ID,
+			FromStatus:    appointments.AppointmentStatusAwaitingDeposit,
+			ToStatus:      appointments.AppointmentStatusCancelled,
+			ChangedBy:     primitive.NilObjectID,
+			Reason:        "Anticipo no pagado a tiempo",
+			CreatedAt:     now,
+		})
+
+		s.send(ctx, "deposit_lapsed", &notifications.SendDTO{
+			OwnerID:  appt.OwnerID.Hex(),
+			TenantID: appt.TenantID.Hex(),
+			Type:     notifications.TypeAppointmentCancelled,
+			Template: notifications.TemplateAppointmentDepositLapsed,
+			Times:    map[string]time.Time{"date": appt.ScheduledAt},
+			Data:     map[string]string{"appointment_id": appt.ID.Hex()},
+			SendPush: true,
+		})
+
+		s.logger.Info("released appointment with unpaid deposit", "id", appt.ID.Hex())
+	}
+}
+
+// processDeferredNotifications delivers owner notifications held back by
+// quiet hours whose window has ended.
+func (s *Scheduler) processDeferredNotifications(ctx context.Context) {
+	released, err := s.notificationSvc.DeliverDeferred(ctx)
+	if err != nil {
+		s.logger.Error("failed to deliver deferred notifications", "error", err)
+		return
+	}
+	if released > 0 {
+		s.logger.Info("delivered notifications deferred by quiet hours", "count", released)
+	}
+}
+
+// processOutbox retries owner notifications recorded in the outbox whose
+// delivery failed or was interrupted.
+func (s *Scheduler) processOutbox(ctx context.Context) {
+	sent, err := s.notificationSvc.DispatchOutbox(ctx)
+	if err != nil {
+		s.logger.Error("failed to dispatch notification outbox", "error", err)
+	}
+	if sent > 0 {
+		s.logger.Info("delivered notifications from the outbox", "count", sent)
+	}
+}
+
+// processLabSLABreaches alerts the ordering vet once when a lab order passes its due date.
+func (s *Scheduler) processLabSLABreaches(ctx context.Context) {
+	now := time.Now()
+
+	breached, err := s.labOrderRepo.FindSLABreaches(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to find lab SLA breaches", "error", err)
+		return
+	}
+
+	for _, order := range breached {
+		alert := &notifications.SendStaffDTO{
+			UserID:   order.VeterinarianID.Hex(),
+			TenantID: order.TenantID.Hex(),
+			Type:     notifications.TypeStaffSystemAlert,
+			Title:    "Orden de laboratorio fuera de plazo",
+			Body:     fmt.Sprintf("La orden de %s debía estar lista el %s", order.TestType, order.ExpectedCompletionDate().Format("02/01/2006")),
+			Data: map[string]string{
+				"order_id":   order.ID.Hex(),
+				"patient_id": order.PatientID.Hex(),
+				"due_date":   order.ExpectedCompletionDate().Format(time.RFC3339),
+			},
+		}
+		if err := s.notificationSvc.SendToStaff(ctx, alert); err != nil {
+			s.logger.Error("failed to send lab SLA alert", "order_id", order.ID.Hex(), "error", err)
+			s.notificationSvc.RecordFailedStaffSend(ctx, alert, "lab_sla_breach", err)
+			continue
+		}
+
+		if err := s.labOrderRepo.MarkSLAAlerted(ctx, order.ID, now); err != nil {
+			s.logger.Error("failed to mark lab SLA alerted", "order_id", order.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// processExpiryWriteOffs zeroes out expired stock for tenants that opted in.
+// Clinics that manage expiry by hand leave the setting off and are skipped.
+func (s *Scheduler) processExpiryWriteOffs(ctx context.Context) {
+	tenants, err := s.tenantRepo.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("failed to list tenants for expiry write-off", "error", err)
+		return
+	}
+
+	for _, t := range tenants {
+		if !t.Settings.AutoWriteOffExpired {
+			continue
+		}
+
+		writeOff, err := s.inventorySvc.WriteOffExpiredProducts(ctx, t.ID)
+		if err != nil {
+			s.logger.Error("failed to write off expired products", "tenant_id", t.ID.Hex(), "error", err)
+			continue
+		}
+		if writeOff == nil {
+			continue
+		}
+
+		s.logger.Info("wrote off expired products",
+			"tenant_id", t.ID.Hex(),
+			"writeoff_id", writeOff.ID.Hex(),
+			"products", len(writeOff.Items),
+			"total_quantity", writeOff.TotalQuantity,
+			"total_value_minor", writeOff.TotalValue,
+			"currency", writeOff.Currency,
+		)
+	}
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/notifications"
+	"github.com/eren_dev/go_server/internal/modules/tenant"
+)
+
+// processStaleAppointments avisa al veterinario de las citas que siguen
+// agendadas, confirmadas o en curso pasado el umbral de la clínica desde su
+// hora de fin, para que registre cómo terminaron. Si la clínica lo permite,
+// las que están en curso se completan solas; las que nunca iniciaron solo se
+// avisan, porque pudieron ser inasistencias. Cada cita se avisa una sola vez.
+func (s *Scheduler) processStaleAppointments(ctx context.Context) {
+	tenants, err := s.tenantRepo.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("failed to list tenants for stale appointments", "error", err)
+		return
+	}
+
+	for _, t := range tenants {
+		if t.Settings.StaleAppointmentAlertMinutes <= 0 {
+			continue
+		}
+		s.processStaleAppointmentsForTenant(ctx, &t)
+	}
+}
+
+func (s *Scheduler) processStaleAppointmentsForTenant(ctx context.Context, t *tenant.Tenant) {
+	now := time.Now()
+	threshold := time.Duration(t.Settings.StaleAppointmentAlertMinutes) * time.Minute
+
+	stale, err := s.appointmentRepo.FindStaleActive(ctx, t.ID, now.Add(-threshold))
+	if err != nil {
+		s.logger.Error("failed to find stale appointments", "tenant_id", t.ID.Hex(), "error", err)
+		return
+	}
+
+	for _, appt := range stale {
+		if appt.EndsAt().Add(threshold).After(now) {
+			continue
+		}
+
+		autoCompleted := false
+		if t.Settings.AutoCompleteStaleAppointments && appt.Status == appointments.AppointmentStatusInProgress {
+			autoCompleted = s.autoCompleteStale(ctx, &appt, now)
+		}
+
+		if !appt.VeterinarianID.IsZero() {
+			s.alertStaleAppointment(ctx, &appt, autoCompleted)
+		}
+
+		if err := s.appointmentRepo.MarkStaleAlerted(ctx, appt.ID, appt.TenantID, now); err != nil {
+			s.logger.Error("failed to mark stale appointment alerted", "id", appt.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// autoCompleteStale cierra una cita en curso olvidada; solo aplica si nadie la
+// cambió mientras tanto
+func (s *Scheduler) autoCompleteStale(ctx context.Context, appt *appointments.Appointment, now time.Time) bool {
+	applied, err := s.appointmentRepo.UpdateIfStatus(ctx, appt.ID, appt.TenantID, appointments.AppointmentStatusInProgress, bson.M{
+		"status":       appointments.AppointmentStatusCompleted,
+		"completed_at": now,
+	})
+	if err != nil {
+		s.logger.Error("failed to auto-complete stale appointment", "id", appt.ID.Hex(), "error", err)
+		return false
+	}
+	if !applied {
+		return false
+	}
+
+	s.appointmentRepo.CreateStatusTransition(ctx, &appointments.AppointmentStatusTransition{
+		TenantID:      appt.TenantID,
+		AppointmentID: appt.ID,
+		FromStatus:    appointments.AppointmentStatusInProgress,
+		ToStatus:      appointments.AppointmentStatusCompleted,
+		ChangedBy:     primitive.NilObjectID,
+		Reason:        "Auto-completada: seguía en curso después de su hora de fin",
+		CreatedAt:     now,
+	})
+
+	s.logger.Info("auto-completed stale appointment", "id", appt.ID.Hex())
+	return true
+}
+
+func (s *Scheduler) alertStaleAppointment(ctx context.Context, appt *appointments.Appointment, autoCompleted bool) {
+	when := appt.ScheduledAt.Format("02/01/2006 15:04")
+	title := "Cita sin actualizar"
+	body := fmt.Sprintf("La cita del %s sigue como %s. Actualiza su estado: en curso, completada o inasistencia", when, appt.Status)
+	action := "update_status"
+	if autoCompleted {
+		title = "Cita completada automáticamente"
+		body = fmt.Sprintf("La cita del %s seguía en curso y se marcó como completada. Revísala si no fue así", when)
+		action = "review"
+	}
+
+	alert := &notifications.SendStaffDTO{
+		UserID:   appt.VeterinarianID.Hex(),
+		TenantID: appt.TenantID.Hex(),
+		Type:     notifications.TypeStaffSystemAlert,
+		Title:    title,
+		Body:     body,
+		Data: map[string]string{
+			"appointment_id": appt.ID.Hex(),
+			"status":         appt.Status,
+			"action":         action,
+		},
+	}
+	if err := s.notificationSvc.SendToStaff(ctx, alert); err != nil {
+		s.logger.Error("failed to send stale appointment alert", "id", appt.ID.Hex(), "error", err)
+		s.notificationSvc.RecordFailedStaffSend(ctx, alert, "stale_appointment", err)
+	}
+}
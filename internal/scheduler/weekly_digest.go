@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eren_dev/go_server/internal/modules/appointments"
+	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/platform/email"
+)
+
+const digestHorizon = 7 * 24 * time.Hour
+
+var spanishWeekdays = [...]string{"Domingo", "Lunes", "Martes", "Miércoles", "Jueves", "Viernes", "Sábado"}
+
+// processWeeklyDigests emails each opted-in veterinarian their agenda for the
+// coming week. It fires once, on the first tick inside the configured weekday/hour.
+func (s *Scheduler) processWeeklyDigests(ctx context.Context) {
+	if s.emailSender == nil || !s.emailSender.IsEnabled() {
+		return
+	}
+
+	now := time.Now()
+	if int(now.Weekday()) != s.digestWeekday || now.Hour() != s.digestHour {
+		return
+	}
+	today := now.Format("2006-01-02")
+	if !s.claimDay(ctx, "weekly_digest", today, &s.lastDigestDay) {
+		return
+	}
+
+	vets, err := s.userRepo.FindWeeklyDigestSubscribers(ctx)
+	if err != nil {
+		s.logger.Error("failed to find weekly digest subscribers", "error", err)
+		return
+	}
+
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	to := from.Add(digestHorizon)
+
+	// Unassigned requests are shared by every vet of a clinic; look them up once per run.
+	unassigned := make(map[string][]appointments.Appointment)
+
+	sent := 0
+	for _, vet := range vets {
+		body, total := s.buildWeeklyDigest(ctx, vet, from, to, unassigned)
+		if total == 0 {
+			continue
+		}
+
+		err := s.emailSender.Send(ctx, email.Message{
+			To:      []string{vet.Email},
+			Subject: fmt.Sprintf("Tu agenda de la semana: %d citas", total),
+			Body:    body,
+		})
+		if err != nil {
+			s.logger.Error("failed to send weekly digest", "user_id", vet.ID.Hex(), "error", err)
+			continue
+		}
+		sent++
+	}
+
+	s.logger.Info("weekly digests sent", "subscribers", len(vets), "sent", sent)
+}
+
+// buildWeeklyDigest renders the vet's agenda across all their clinics and
+// returns the body together with the number of appointments it lists.
+func (s *Scheduler) buildWeeklyDigest(ctx context.Context, vet *users.User, from, to time.Time, unassigned map[string][]appointments.Appointment) (string, int) {
+	var b strings.Builder
+	total := 0
+
+	fmt.Fprintf(&b, "Hola %s,\n\nEste es tu resumen de citas del %s al %s.\n",
+		vet.Name, from.Format("02/01/2006"), to.Add(-time.Second).Format("02/01/2006"))
+
+	for _, tenantID := range vet.TenantIds {
+		appts, err := s.appointmentRepo.FindByVeterinarian(ctx, vet.ID, from, to, tenantID)
+		if err != nil {
+			s.logger.Error("failed to load vet appointments for digest", "user_id", vet.ID.Hex(), "tenant_id", tenantID.Hex(), "error", err)
+			continue
+		}
+
+		active := make([]appointments.Appointment, 0, len(appts))
+		for _, a := range appts {
+			if a.IsActive() {
+				active = append(active, a)
+			}
+		}
+		if len(active) == 0 {
+			continue
+		}
+		total += len(active)
+
+		clinicName := tenantID.Hex()
+		if t, err := s.tenantRepo.FindByID(ctx, tenantID.Hex()); err == nil {
+			clinicName = t.Name
+		}
+		fmt.Fprintf(&b, "\n== %s: %d citas ==\n", clinicName, len(active))
+
+		currentDay := ""
+		for _, a := range active {
+			day := fmt.Sprintf("%s %s", spanishWeekdays[a.ScheduledAt.Weekday()], a.ScheduledAt.Format("02/01"))
+			if day != currentDay {
+				fmt.Fprintf(&b, "\n%s\n", day)
+				currentDay = day
+			}
+			patientName, ownerName := s.digestNames(ctx, &a)
+			fmt.Fprintf(&b, "  %s  %s (%s) - %s, %s\n", a.ScheduledAt.Format("15:04"), patientName, ownerName, a.Type, a.Reason)
+		}
+
+		key := tenantID.Hex()
+		pending, cached := unassigned[key]
+		if !cached {
+			pending, err = s.appointmentRepo.FindUnassigned(ctx, tenantID, from, to)
+			if err != nil {
+				s.logger.Error("failed to load unassigned appointments for digest", "tenant_id", key, "error", err)
+			}
+			unassigned[key] = pending
+		}
+		if len(pending) > 0 {
+			fmt.Fprintf(&b, "\nSolicitudes sin veterinario asignado: %d\n", len(pending))
+			for _, a := range pending {
+				patientName, ownerName := s.digestNames(ctx, &a)
+				fmt.Fprintf(&b, "  %s  %s (%s) - %s\n", a.ScheduledAt.Format("02/01/2006 15:04"), patientName, ownerName, a.Type)
+			}
+		}
+	}
+
+	return b.String(), total
+}
+
+// digestNames resolves patient and owner names, falling back to a placeholder
+// so a missing record never drops an appointment from the digest.
+func (s *Scheduler) digestNames(ctx context.Context, a *appointments.Appointment) (string, string) {
+	patientName, ownerName := "Paciente desconocido", "Propietario desconocido"
+	if p, err := s.patientRepo.FindByID(ctx, a.TenantID, a.PatientID.Hex()); err == nil {
+		patientName = p.Name
+	}
+	if o, err := s.ownerRepo.FindByID(ctx, a.OwnerID.Hex()); err == nil {
+		ownerName = o.Name
+	}
+	return patientName, ownerName
+}
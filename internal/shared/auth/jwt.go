@@ -32,54 +32,88 @@ type Claims struct {
 	Email     string    `json:"email"`
 	UserType  UserType  `json:"user_type"`
 	TokenType TokenType `json:"token_type"`
+	// Remember marks a "remember me" session; refreshing keeps the long lifetime
+	Remember bool `json:"remember,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// SessionPolicy holds the token lifetimes for one kind of user.
+type SessionPolicy struct {
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+	RememberTTL time.Duration // refresh lifetime of "remember me" sessions
+}
+
+func (p SessionPolicy) refreshTTL(remember bool) time.Duration {
+	if remember && p.RememberTTL > 0 {
+		return p.RememberTTL
+	}
+	return p.RefreshTTL
+}
+
 type JWTService struct {
-	secret            []byte
-	expiration        time.Duration
-	refreshExpiration time.Duration
+	secret   []byte
+	policies map[UserType]SessionPolicy
 }
 
 func NewJWTService(cfg *config.Config) *JWTService {
 	return &JWTService{
-		secret:            []byte(cfg.JWTSecret),
-		expiration:        cfg.JWTExpiration,
-		refreshExpiration: cfg.JWTRefreshExpiration,
+		secret: []byte(cfg.JWTSecret),
+		policies: map[UserType]SessionPolicy{
+			UserTypeStaff: {
+				AccessTTL:   cfg.JWTExpiration,
+				RefreshTTL:  cfg.JWTRefreshExpiration,
+				RememberTTL: cfg.JWTRememberExpiration,
+			},
+			UserTypeOwner: {
+				AccessTTL:   cfg.MobileJWTExpiration,
+				RefreshTTL:  cfg.MobileJWTRefreshExpiration,
+				RememberTTL: cfg.MobileJWTRememberExpiration,
+			},
+		},
 	}
 }
 
 type TokenPair struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int64  `json:"expires_in"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
 }
 
-func (s *JWTService) GenerateTokenPair(userID, email string, userType UserType) (*TokenPair, error) {
-	accessToken, err := s.generateToken(userID, email, userType, AccessToken, s.expiration)
+// GenerateTokenPair issues an access and a refresh token with the lifetimes of
+// the user type. With remember set, the refresh token gets the longer
+// "remember me" lifetime, which survives refresh rotation.
+func (s *JWTService) GenerateTokenPair(userID, email string, userType UserType, remember bool) (*TokenPair, error) {
+	policy := s.policies[userType]
+	refreshTTL := policy.refreshTTL(remember)
+
+	accessToken, err := s.generateToken(userID, email, userType, AccessToken, false, policy.AccessTTL)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.generateToken(userID, email, userType, RefreshToken, s.refreshExpiration)
+	refreshToken, err := s.generateToken(userID, email, userType, RefreshToken, remember, refreshTTL)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.expiration.Seconds()),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(policy.AccessTTL.Seconds()),
+		RefreshExpiresIn: int64(refreshTTL.Seconds()),
 	}, nil
 }
 
-func (s *JWTService) generateToken(userID, email string, userType UserType, tokenType TokenType, expiration time.Duration) (string, error) {
+func (s *JWTService) generateToken(userID, email string, userType UserType, tokenType TokenType, remember bool, expiration time.Duration) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		UserID:    userID,
 		Email:     email,
 		UserType:  userType,
 		TokenType: tokenType,
+		Remember:  remember,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -124,5 +158,5 @@ func (s *JWTService) RefreshAccessToken(refreshToken string) (*TokenPair, error)
 		return nil, err
 	}
 
-	return s.GenerateTokenPair(claims.UserID, claims.Email, claims.UserType)
+	return s.GenerateTokenPair(claims.UserID, claims.Email, claims.UserType, claims.Remember)
 }
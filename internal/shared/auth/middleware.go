@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/eren_dev/go_server/internal/config"
+	"github.com/eren_dev/go_server/internal/platform/logger"
 )
 
 type contextKey string
@@ -59,6 +60,7 @@ func JWTMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Set(string(UserIDKey), claims.UserID)
 		c.Set(string(EmailKey), claims.Email)
 		c.Set(string(UserTypeKey), string(claims.UserType))
+		c.Request = c.Request.WithContext(logger.WithPrincipalID(c.Request.Context(), claims.UserID))
 
 		c.Next()
 	}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BaseRepository implements the reads and writes shared by tenant-owned,
+// soft-deleted collections of T. Every operation is scoped to a tenant;
+// reads, updates and deletes only see live documents and Restore only sees
+// deleted ones. NotFound and, when set, Duplicate are returned in place of
+// the driver's errors so modules keep their own typed errors.
+type BaseRepository[T any] struct {
+	Collection *mongo.Collection
+	NotFound   error
+	Duplicate  error
+}
+
+// NewBaseRepository creates a base repository over collection
+func NewBaseRepository[T any](collection *mongo.Collection, notFound error) *BaseRepository[T] {
+	return &BaseRepository[T]{Collection: collection, NotFound: notFound}
+}
+
+// WithDuplicate sets the error returned when an update breaks a unique index
+func (r *BaseRepository[T]) WithDuplicate(err error) *BaseRepository[T] {
+	r.Duplicate = err
+	return r
+}
+
+// Live restricts filter to the tenant's documents that are not deleted. The
+// filter is modified in place and may be nil.
+func (r *BaseRepository[T]) Live(tenantID primitive.ObjectID, filter bson.M) bson.M {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter["tenant_id"] = tenantID
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// FindByID returns the tenant's live document with the given ID
+func (r *BaseRepository[T]) FindByID(ctx context.Context, id, tenantID primitive.ObjectID) (*T, error) {
+	return r.FindOne(ctx, tenantID, bson.M{"_id": id})
+}
+
+// FindOne returns the first of the tenant's live documents matching filter
+func (r *BaseRepository[T]) FindOne(ctx context.Context, tenantID primitive.ObjectID, filter bson.M, opts ...*options.FindOneOptions) (*T, error) {
+	var doc T
+	err := r.Collection.FindOne(ctx, r.Live(tenantID, filter), opts...).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, r.NotFound
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// List returns the tenant's live documents matching filter
+func (r *BaseRepository[T]) List(ctx context.Context, tenantID primitive.ObjectID, filter bson.M, opts ...*options.FindOptions) ([]T, error) {
+	cursor, err := r.Collection.Find(ctx, r.Live(tenantID, filter), opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []T
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Page returns one page of the tenant's live documents matching filter along
+// with the total number of matches; opts carries the skip, limit and sort
+func (r *BaseRepository[T]) Page(ctx context.Context, tenantID primitive.ObjectID, filter bson.M, opts *options.FindOptions) ([]T, int64, error) {
+	filter = r.Live(tenantID, filter)
+	total, err := r.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	docs, err := r.List(ctx, tenantID, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return docs, total, nil
+}
+
+// Update sets fields on the tenant's live document and stamps updated_at
+func (r *BaseRepository[T]) Update(ctx context.Context, id, tenantID primitive.ObjectID, updates bson.M) error {
+	updates["updated_at"] = time.Now()
+	return r.updateOne(ctx, r.Live(tenantID, bson.M{"_id": id}), bson.M{"$set": updates})
+}
+
+// SoftDelete marks the tenant's live document as deleted
+func (r *BaseRepository[T]) SoftDelete(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	now := time.Now()
+	return r.updateOne(ctx, r.Live(tenantID, bson.M{"_id": id}), bson.M{
+		"$set": bson.M{"deleted_at": now, "updated_at": now},
+	})
+}
+
+// Restore brings back a soft-deleted document of the tenant
+func (r *BaseRepository[T]) Restore(ctx context.Context, id, tenantID primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"tenant_id":  tenantID,
+		"deleted_at": bson.M{"$ne": nil},
+	}
+	return r.updateOne(ctx, filter, bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+}
+
+func (r *BaseRepository[T]) updateOne(ctx context.Context, filter, update bson.M) error {
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if r.Duplicate != nil && mongo.IsDuplicateKeyError(err) {
+			return r.Duplicate
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.NotFound
+	}
+	return nil
+}
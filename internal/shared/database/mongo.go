@@ -15,6 +15,7 @@ type MongoDB struct {
 	client   *mongo.Client
 	database *mongo.Database
 	timeout  time.Duration
+	slow     *slowQueryMonitor
 }
 
 func NewMongoDB(cfg *config.Config) (*MongoDB, error) {
@@ -22,6 +23,15 @@ func NewMongoDB(cfg *config.Config) (*MongoDB, error) {
 	defer cancel()
 
 	clientOpts := options.Client().ApplyURI(cfg.MongoURI)
+
+	// Slow query logging sees every repository operation through the
+	// client's command events; without a threshold no monitor is installed
+	var slow *slowQueryMonitor
+	if cfg.MongoSlowQueryThreshold > 0 {
+		slow = newSlowQueryMonitor(cfg.MongoSlowQueryThreshold)
+		clientOpts.SetMonitor(slow.commandMonitor())
+	}
+
 	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
 		return nil, err
@@ -35,9 +45,19 @@ func NewMongoDB(cfg *config.Config) (*MongoDB, error) {
 		client:   client,
 		database: client.Database(cfg.MongoDatabase),
 		timeout:  cfg.MongoTimeout,
+		slow:     slow,
 	}, nil
 }
 
+// WithSlowQueryMetrics counts slow queries per collection in metrics. It is a
+// no-op when slow query logging is disabled.
+func (m *MongoDB) WithSlowQueryMetrics(metrics SlowQueryMetrics) *MongoDB {
+	if m.slow != nil {
+		m.slow.setMetrics(metrics)
+	}
+	return m
+}
+
 func (m *MongoDB) DB() *mongo.Database {
 	return m.database
 }
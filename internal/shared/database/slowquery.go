@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// SlowQueryMetrics counts slow queries per collection. metrics.Metrics implements it.
+type SlowQueryMetrics interface {
+	IncSlowQuery(collection string)
+}
+
+// filterFields is where each command carries the filter worth logging
+var filterFields = map[string][]string{
+	"find":          {"filter"},
+	"count":         {"query"},
+	"distinct":      {"query"},
+	"findAndModify": {"query"},
+	"aggregate":     {"pipeline"},
+	"delete":        {"deletes", "0", "q"},
+	"update":        {"updates", "0", "q"},
+}
+
+type startedQuery struct {
+	collection string
+	filter     bson.RawValue
+}
+
+// slowQueryMonitor watches every command the client sends and logs the ones
+// slower than threshold with their collection and the shape of their filter.
+// It is only installed when a threshold is configured, so disabled
+// instrumentation costs nothing.
+type slowQueryMonitor struct {
+	threshold time.Duration
+	started   sync.Map // request id -> startedQuery
+	metrics   atomic.Value
+}
+
+func newSlowQueryMonitor(threshold time.Duration) *slowQueryMonitor {
+	return &slowQueryMonitor{threshold: threshold}
+}
+
+func (m *slowQueryMonitor) commandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started:   m.onStarted,
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) { m.onFinished(e.CommandFinishedEvent, false) },
+		Failed:    func(ctx context.Context, e *event.CommandFailedEvent) { m.onFinished(e.CommandFinishedEvent, true) },
+	}
+}
+
+func (m *slowQueryMonitor) setMetrics(metrics SlowQueryMetrics) {
+	if metrics != nil {
+		m.metrics.Store(metrics)
+	}
+}
+
+func (m *slowQueryMonitor) onStarted(_ context.Context, e *event.CommandStartedEvent) {
+	collection := commandCollection(e.CommandName, e.Command)
+	if collection == "" {
+		return
+	}
+
+	q := startedQuery{collection: collection}
+	if path, ok := filterFields[e.CommandName]; ok {
+		// The command buffer is reused by the driver once the event returns
+		if v, err := e.Command.LookupErr(path...); err == nil {
+			q.filter = bson.RawValue{Type: v.Type, Value: append([]byte(nil), v.Value...)}
+		}
+	}
+	m.started.Store(e.RequestID, q)
+}
+
+func (m *slowQueryMonitor) onFinished(e event.CommandFinishedEvent, failed bool) {
+	v, ok := m.started.LoadAndDelete(e.RequestID)
+	if !ok || e.Duration < m.threshold {
+		return
+	}
+	q := v.(startedQuery)
+
+	slog.Warn("slow mongo query",
+		"collection", q.collection,
+		"operation", e.CommandName,
+		"duration_ms", e.Duration.Milliseconds(),
+		"filter", filterShape(q.filter),
+		"failed", failed,
+	)
+	if metrics, ok := m.metrics.Load().(SlowQueryMetrics); ok {
+		metrics.IncSlowQuery(q.collection)
+	}
+}
+
+// commandCollection returns the collection a command targets, or "" for
+// commands that do not target one (handshakes, pings, sessions)
+func commandCollection(name string, cmd bson.Raw) string {
+	if name == "getMore" {
+		coll, _ := cmd.Lookup("collection").StringValueOK()
+		return coll
+	}
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return ""
+	}
+	coll, _ := elem.Value().StringValueOK()
+	return coll
+}
+
+// filterShape renders a filter with its keys and operators but without its
+// values, so logs show which fields a slow query used and never the data it
+// looked for
+func filterShape(v bson.RawValue) string {
+	if len(v.Value) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	writeShape(&b, v)
+	return b.String()
+}
+
+func writeShape(b *strings.Builder, v bson.RawValue) {
+	switch v.Type {
+	case bsontype.EmbeddedDocument:
+		elems, err := v.Document().Elements()
+		if err != nil {
+			b.WriteString("?")
+			return
+		}
+		b.WriteString("{")
+		for i, elem := range elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(elem.Key())
+			b.WriteString(": ")
+			writeShape(b, elem.Value())
+		}
+		b.WriteString("}")
+	case bsontype.Array:
+		// Arrays of documents are pipelines or $and/$or clauses; any other
+		// array is a list of values
+		values, err := v.Array().Values()
+		if err != nil || len(values) == 0 || values[0].Type != bsontype.EmbeddedDocument {
+			b.WriteString("?")
+			return
+		}
+		b.WriteString("[")
+		for i, value := range values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeShape(b, value)
+		}
+		b.WriteString("]")
+	default:
+		b.WriteString("?")
+	}
+}
@@ -1,13 +1,71 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrInvalidInput = errors.New("invalid_input")
-	ErrBadRequest   = errors.New("bad_request")
-	ErrNotFound     = errors.New("not_found")
-	ErrConflict     = errors.New("conflict")
-	ErrUnauthorized = errors.New("unauthorized")
-	ErrForbidden    = errors.New("forbidden")
-	ErrInternal     = errors.New("internal_error")
+	ErrInvalidInput  = errors.New("invalid_input")
+	ErrBadRequest    = errors.New("bad_request")
+	ErrNotFound      = errors.New("not_found")
+	ErrConflict      = errors.New("conflict")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrUnprocessable = errors.New("unprocessable")
+	ErrInternal      = errors.New("internal_error")
 )
+
+// Error is the structured error handlers return to clients. Kind is one of the
+// sentinels above and decides the HTTP status; Code is a stable,
+// machine-readable identifier clients can switch on.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Field   string
+	Details map[string]interface{}
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the wrapped cause to errors.Is/As.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// New creates a coded error. It is meant for package-level sentinels, e.g.
+//
+//	ErrInsufficientStock = sharedErrors.New(sharedErrors.ErrConflict, "INSUFFICIENT_STOCK", "insufficient stock")
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Validation creates a VALIDATION_ERROR for a single field.
+func Validation(field, message string) *Error {
+	return &Error{
+		Kind:    ErrInvalidInput,
+		Code:    "VALIDATION_ERROR",
+		Message: fmt.Sprintf("validation error: %s - %s", field, message),
+		Field:   field,
+	}
+}
+
+// Business creates an error for a rule the request violates even though it
+// is well formed.
+func Business(code, message string) *Error {
+	return &Error{Kind: ErrUnprocessable, Code: code, Message: message}
+}
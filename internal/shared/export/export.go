@@ -0,0 +1,260 @@
+// Package export streams report rows as CSV or JSON lines. A service
+// describes its rows with typed columns and a source that feeds them in order;
+// Respond picks the format and columns the client asked for and returns an
+// httpx.Stream, so rows reach the client while the source is still reading.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	"github.com/eren_dev/go_server/internal/shared/httpx"
+)
+
+// Format is an export encoding
+type Format string
+
+const (
+	FormatCSV       Format = "csv"
+	FormatJSONLines Format = "jsonl"
+)
+
+// Column is one field of an exported row. Key is both the CSV header and the
+// JSON key. Value returns a string, number, bool, time.Time or nil; times are
+// written as RFC 3339.
+type Column[T any] struct {
+	Key   string
+	Value func(row *T) any
+}
+
+// Source feeds rows to emit in order and stops at the first error emit
+// returns, returning it.
+type Source[T any] func(emit func(row *T) error) error
+
+// Export is a report that can be streamed. Name is the file name without date
+// or extension.
+type Export[T any] struct {
+	Name    string
+	Columns []Column[T]
+	Source  Source[T]
+}
+
+// Respond negotiates the format from ?format= or the Accept header, keeps the
+// columns listed in ?columns= (all of them when absent) and returns the stream
+// for httpx.Adapt to send. Both are checked before any byte is written, so a
+// bad request still gets a proper error response.
+func Respond[T any](c *gin.Context, e *Export[T]) (any, error) {
+	format, err := Negotiate(c.Query("format"), c.GetHeader("Accept"))
+	if err != nil {
+		return nil, err
+	}
+	columns, err := e.Select(c.Query("columns"))
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &httpx.Stream{Filename: e.Name + "-" + time.Now().Format("20060102")}
+	switch format {
+	case FormatJSONLines:
+		stream.ContentType = "application/x-ndjson; charset=utf-8"
+		stream.Filename += ".jsonl"
+		stream.Write = func(w io.Writer) error { return writeJSONLines(w, columns, e.Source) }
+	default:
+		stream.ContentType = "text/csv; charset=utf-8"
+		stream.Filename += ".csv"
+		stream.Write = func(w io.Writer) error { return writeCSV(w, columns, e.Source) }
+	}
+	return stream, nil
+}
+
+// Negotiate picks the export format. An explicit format wins over the Accept
+// header; anything not asking for JSON lines gets CSV.
+func Negotiate(format, accept string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONLines, "ndjson":
+		return FormatJSONLines, nil
+	case "":
+	default:
+		return "", sharedErrors.Validation("format", "must be csv or jsonl")
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return FormatCSV, nil
+		case "application/x-ndjson", "application/jsonl", "application/json":
+			return FormatJSONLines, nil
+		}
+	}
+	return FormatCSV, nil
+}
+
+// Select returns the columns named in a comma separated list, in the order
+// given, or every column when the list is empty
+func (e *Export[T]) Select(list string) ([]Column[T], error) {
+	if strings.TrimSpace(list) == "" {
+		return e.Columns, nil
+	}
+
+	byKey := make(map[string]Column[T], len(e.Columns))
+	for _, col := range e.Columns {
+		byKey[col.Key] = col
+	}
+
+	var selected []Column[T]
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(list, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		col, ok := byKey[key]
+		if !ok {
+			return nil, &sharedErrors.Error{
+				Kind:    sharedErrors.ErrInvalidInput,
+				Code:    "VALIDATION_ERROR",
+				Message: "validation error: columns - unknown column " + key,
+				Field:   "columns",
+				Details: map[string]interface{}{"available": e.keys()},
+			}
+		}
+		seen[key] = true
+		selected = append(selected, col)
+	}
+	return selected, nil
+}
+
+func (e *Export[T]) keys() []string {
+	keys := make([]string, len(e.Columns))
+	for i, col := range e.Columns {
+		keys[i] = col.Key
+	}
+	return keys
+}
+
+func writeCSV[T any](w io.Writer, columns []Column[T], source Source[T]) error {
+	// The BOM makes Excel read the file as UTF-8, so accented names survive
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	out := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Key
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	err := source(func(row *T) error {
+		for i, col := range columns {
+			record[i] = csvValue(col.Value(row))
+		}
+		// csv.Writer buffers a few KB and hands them on as it fills
+		return out.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// csvValue formats a cell. Text starting with a formula character is prefixed
+// with a quote so spreadsheets show it instead of evaluating it.
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+func writeJSONLines[T any](w io.Writer, columns []Column[T], source Source[T]) error {
+	keys := make([][]byte, len(columns))
+	for i, col := range columns {
+		keys[i], _ = json.Marshal(col.Key)
+	}
+
+	// Objects are built by hand so keys keep the column order
+	out := bufio.NewWriter(w)
+	var line []byte
+	err := source(func(row *T) error {
+		line = append(line[:0], '{')
+		for i, col := range columns {
+			if i > 0 {
+				line = append(line, ',')
+			}
+			value, err := json.Marshal(jsonValue(col.Value(row)))
+			if err != nil {
+				return err
+			}
+			line = append(line, keys[i]...)
+			line = append(line, ':')
+			line = append(line, value...)
+		}
+		line = append(line, '}', '\n')
+		_, err := out.Write(line)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return nil
+		}
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	Name  string
+	Count int
+	At    *time.Time
+}
+
+func testExport(rows ...row) *Export[row] {
+	return &Export[row]{
+		Name: "test",
+		Columns: []Column[row]{
+			{Key: "name", Value: func(r *row) any { return r.Name }},
+			{Key: "count", Value: func(r *row) any { return r.Count }},
+			{Key: "at", Value: func(r *row) any { return r.At }},
+		},
+		Source: func(emit func(*row) error) error {
+			for i := range rows {
+				if err := emit(&rows[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		format, accept string
+		want           Format
+	}{
+		{"", "", FormatCSV},
+		{"", "text/csv", FormatCSV},
+		{"", "application/x-ndjson", FormatJSONLines},
+		{"", "text/html, application/json;q=0.9", FormatJSONLines},
+		{"csv", "application/x-ndjson", FormatCSV},
+		{"JSONL", "", FormatJSONLines},
+	}
+	for _, c := range cases {
+		got, err := Negotiate(c.format, c.accept)
+		require.NoError(t, err)
+		assert.Equal(t, c.want, got, "format=%q accept=%q", c.format, c.accept)
+	}
+
+	_, err := Negotiate("xlsx", "")
+	assert.Error(t, err)
+}
+
+func TestSelect_KeepsRequestedOrderAndRejectsUnknown(t *testing.T) {
+	e := testExport()
+
+	all, err := e.Select("")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	cols, err := e.Select("count, name,count")
+	require.NoError(t, err)
+	require.Len(t, cols, 2)
+	assert.Equal(t, "count", cols[0].Key)
+	assert.Equal(t, "name", cols[1].Key)
+
+	_, err = e.Select("name,owner")
+	assert.Error(t, err)
+}
+
+func TestWriteCSV_EscapesCellsAndGuardsFormulas(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	e := testExport(row{Name: `Luna "la gata", Jr.`, Count: 2, At: &at}, row{Name: "=HYPERLINK()", Count: -1})
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, e.Columns, e.Source))
+
+	assert.Equal(t, "\ufeffname,count,at\n"+
+		`"Luna ""la gata"", Jr.",2,2024-03-01T09:30:00Z`+"\n"+
+		"'=HYPERLINK(),-1,\n", buf.String())
+}
+
+func TestWriteJSONLines_KeepsColumnOrder(t *testing.T) {
+	e := testExport(row{Name: "Max", Count: 3})
+	cols, err := e.Select("count,name,at")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJSONLines(&buf, cols, e.Source))
+
+	assert.Equal(t, `{"count":3,"name":"Max","at":null}`+"\n", buf.String())
+}
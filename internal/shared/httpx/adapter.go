@@ -1,7 +1,12 @@
 package httpx
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,10 +39,73 @@ func Adapt(handler AppHandler) gin.HandlerFunc {
 			return
 		}
 
+		if file, ok := data.(*File); ok {
+			if file.Filename != "" {
+				c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", file.Filename))
+			}
+			c.Data(http.StatusOK, file.ContentType, file.Data)
+			return
+		}
+
+		if stream, ok := data.(*Stream); ok {
+			if stream.Filename != "" {
+				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", stream.Filename))
+			}
+			c.Header("Content-Type", stream.ContentType)
+			c.Status(http.StatusOK)
+			if err := stream.Write(c.Writer); err != nil {
+				// The status is already sent; all that is left is to cut the body short
+				logger.Default().Error(ctx, "http_stream_failed", "error", err)
+			}
+			return
+		}
+
+		if tagged, ok := data.(*Tagged); ok {
+			writeTagged(c, tagged)
+			return
+		}
+
 		writeResponse(c, http.StatusOK, true, data)
 	}
 }
 
+// writeTagged answers 304 when the client already holds the current version,
+// otherwise the usual envelope with the ETag and a Cache-Control asking the
+// client to revalidate before reusing it. The envelope's timestamp and path
+// are left out of the tag, so it only changes with the resource.
+func writeTagged(c *gin.Context, tagged *Tagged) {
+	body, err := json.Marshal(tagged.Data)
+	if err != nil {
+		writeResponse(c, http.StatusOK, true, tagged.Data)
+		return
+	}
+	sum := sha256.Sum256(append(body, tagged.UpdatedAt.UTC().Format(time.RFC3339Nano)...))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	writeResponse(c, http.StatusOK, true, tagged.Data)
+}
+
+// etagMatches reports whether an If-None-Match header names etag, comparing
+// weakly as RFC 9110 asks for GET
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 func writeResponse(c *gin.Context, status int, success bool, data any) {
 	requestID, _ := logger.RequestIDFromContext(c.Request.Context())
 
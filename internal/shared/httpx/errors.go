@@ -2,11 +2,13 @@ package httpx
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
+	"github.com/eren_dev/go_server/internal/shared/validation"
 )
 
 // ErrorResponse represents a standard error response
@@ -16,7 +18,10 @@ type ErrorResponse struct {
 	Code      string            `json:"code"`
 	Message   string            `json:"message"`
 	Path      string            `json:"path"`
+	Field     string            `json:"field,omitempty"`
 	Details   map[string]string `json:"details,omitempty"`
+	// Errors lists every invalid field of a VALIDATION_ERROR, in DTO order
+	Errors []validation.FieldError `json:"errors,omitempty"`
 }
 
 // NewErrorResponse creates a new error response with request ID
@@ -30,8 +35,17 @@ func NewErrorResponse(requestID, path, code, message string) ErrorResponse {
 	}
 }
 
-// FromError converts an error to HTTP status code and ErrorResponse
+// FromError converts an error to HTTP status code and ErrorResponse.
+// Coded errors (sharedErrors.Error) and binding validation errors are mapped
+// first; anything else falls back to matching on the message.
 func FromError(err error) (int, ErrorResponse) {
+	if status, resp, ok := fromStructured(err); ok {
+		return status, resp
+	}
+	return fromMessage(err)
+}
+
+func fromMessage(err error) (int, ErrorResponse) {
 	errMsg := err.Error()
 
 	if strings.Contains(errMsg, "validation") || strings.Contains(errMsg, "binding") {
@@ -76,6 +90,13 @@ func FromError(err error) (int, ErrorResponse) {
 		}
 	}
 
+	if strings.Contains(errMsg, "rate limit") {
+		return http.StatusTooManyRequests, ErrorResponse{
+			Code:    ErrCodeRateLimited,
+			Message: errMsg,
+		}
+	}
+
 	if strings.Contains(errMsg, "invalid") {
 		return http.StatusBadRequest, ErrorResponse{
 			Code:    "BAD_REQUEST",
@@ -114,6 +135,12 @@ func FromError(err error) (int, ErrorResponse) {
 			Message: errMsg,
 		}
 
+	case errors.Is(err, sharedErrors.ErrUnprocessable):
+		return http.StatusUnprocessableEntity, ErrorResponse{
+			Code:    ErrCodeUnprocessable,
+			Message: errMsg,
+		}
+
 	case errors.Is(err, sharedErrors.ErrForbidden):
 		return http.StatusForbidden, ErrorResponse{
 			Code:    "FORBIDDEN",
@@ -139,4 +166,79 @@ const (
 	ErrCodeBadRequest      = "BAD_REQUEST"
 	ErrCodeInvalidInput    = "INVALID_INPUT"
 	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrCodeRateLimited     = "RATE_LIMITED"
+	ErrCodeUnprocessable   = "UNPROCESSABLE"
 )
+
+// kindStatus maps sharedErrors kinds to HTTP statuses.
+var kindStatus = map[error]int{
+	sharedErrors.ErrInvalidInput:  http.StatusBadRequest,
+	sharedErrors.ErrBadRequest:    http.StatusBadRequest,
+	sharedErrors.ErrNotFound:      http.StatusNotFound,
+	sharedErrors.ErrConflict:      http.StatusConflict,
+	sharedErrors.ErrUnauthorized:  http.StatusUnauthorized,
+	sharedErrors.ErrForbidden:     http.StatusForbidden,
+	sharedErrors.ErrUnprocessable: http.StatusUnprocessableEntity,
+	sharedErrors.ErrInternal:      http.StatusInternalServerError,
+}
+
+// fromStructured maps the first coded error in err's chain. The code comes
+// from the outermost coded error; the status from the first one with a kind,
+// so a wrapper such as AppointmentError can reuse its cause's status.
+func fromStructured(err error) (int, ErrorResponse, bool) {
+	var ve validation.ValidationError
+	if errors.As(err, &ve) {
+		resp := ErrorResponse{
+			Code:    ErrCodeValidation,
+			Message: err.Error(),
+			Details: make(map[string]string, len(ve.Errors)),
+			Errors:  ve.Errors,
+		}
+		for _, fe := range ve.Errors {
+			resp.Details[fe.Field] = fe.Message
+		}
+		if len(ve.Errors) == 1 {
+			resp.Field = ve.Errors[0].Field
+		}
+		return http.StatusBadRequest, resp, true
+	}
+
+	var coded *sharedErrors.Error
+	if !errors.As(err, &coded) {
+		return 0, ErrorResponse{}, false
+	}
+
+	resp := ErrorResponse{
+		Code:    coded.Code,
+		Message: err.Error(),
+		Field:   coded.Field,
+	}
+	if len(coded.Details) > 0 {
+		resp.Details = make(map[string]string, len(coded.Details))
+		for k, v := range coded.Details {
+			resp.Details[k] = fmt.Sprint(v)
+		}
+	}
+
+	for e := coded; e != nil; {
+		if status, ok := kindStatus[e.Kind]; ok {
+			if status == http.StatusInternalServerError {
+				resp.Message = "internal server error"
+			}
+			return status, resp, true
+		}
+		var inner *sharedErrors.Error
+		if e.Err == nil || !errors.As(e.Err, &inner) {
+			break
+		}
+		e = inner
+	}
+
+	// Coded but kindless: keep the code, let the message rules pick the status.
+	status, fallback := fromMessage(err)
+	if resp.Code == "" {
+		resp.Code = fallback.Code
+	}
+	resp.Message = fallback.Message
+	return status, resp, true
+}
@@ -1,5 +1,35 @@
 package httpx
 
+import (
+	"io"
+	"time"
+)
+
+// File is returned by handlers that answer with a binary body (images,
+// documents) instead of the JSON envelope.
+type File struct {
+	ContentType string
+	Filename    string // when set, sent as an inline Content-Disposition
+	Data        []byte
+}
+
+// Stream is returned by handlers whose body is too large to build in memory,
+// such as exports. Write runs after the headers are sent, so anything that can
+// fail with a proper error response must be checked before returning it.
+type Stream struct {
+	ContentType string
+	Filename    string // when set, sent as an attachment Content-Disposition
+	Write       func(w io.Writer) error
+}
+
+// Tagged is returned by single-resource GET handlers that support conditional
+// requests. The response carries an ETag derived from Data and UpdatedAt, and
+// a request whose If-None-Match still matches gets 304 Not Modified.
+type Tagged struct {
+	Data      any
+	UpdatedAt time.Time
+}
+
 type StandardResponse struct {
 	Success    bool   `json:"success"`
 	Data       any    `json:"data"`
@@ -1,27 +1,58 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/eren_dev/go_server/internal/platform/logger"
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
 )
 
+// accessLogSkipPaths are probed constantly and would drown the access log.
+var accessLogSkipPaths = map[string]struct{}{
+	"/health":  {},
+	"/ready":   {},
+	"/metrics": {},
+}
+
 func SlogLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if _, skip := accessLogSkipPaths[c.Request.URL.Path]; skip {
+			c.Next()
+			return
+		}
+
 		start := time.Now()
 
 		c.Next()
 
 		duration := time.Since(start)
+		status := c.Writer.Status()
 
-		logger.Default().Info(c.Request.Context(), "http_request",
+		attrs := []any{
 			"method", c.Request.Method,
 			"path", c.Request.URL.Path,
-			"status", c.Writer.Status(),
+			"route", c.FullPath(),
+			"status", status,
 			"latency_ms", duration.Milliseconds(),
 			"client_ip", c.ClientIP(),
-		)
+		}
+		// request_id, tenant_id and principal_id come from the request context,
+		// which the JWT and tenant middlewares enrich further down the chain.
+		if principalType := sharedAuth.GetUserType(c); principalType != "" {
+			attrs = append(attrs, "principal_type", principalType)
+		}
+
+		ctx := c.Request.Context()
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Default().Error(ctx, "http_request", attrs...)
+		case status >= http.StatusBadRequest:
+			logger.Default().Warn(ctx, "http_request", attrs...)
+		default:
+			logger.Default().Info(ctx, "http_request", attrs...)
+		}
 	}
 }
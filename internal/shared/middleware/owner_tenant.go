@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
+)
+
+// OwnerTenantChecker resuelve si un owner está asociado a un tenant.
+type OwnerTenantChecker interface {
+	BelongsToTenant(ctx context.Context, ownerID string, tenantID primitive.ObjectID) (bool, error)
+}
+
+// OwnerTenantMiddleware verifica que el owner autenticado esté asociado al
+// tenant enviado en X-Tenant-ID. Un owner puede pertenecer a varias clínicas,
+// pero nunca debe poder leer ni escribir datos de una clínica ajena.
+// Debe usarse después de TenantMiddleware y OwnerGuardMiddleware.
+func OwnerTenantMiddleware(checker OwnerTenantChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := checker.BelongsToTenant(c.Request.Context(), sharedAuth.GetUserID(c), GetTenantID(c))
+		if err != nil || !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "owner is not associated with this tenant",
+				"status":  http.StatusForbidden,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"regexp"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
@@ -9,10 +11,14 @@ import (
 
 const headerRequestID = "X-Request-ID"
 
+// validRequestID limits propagated IDs to a safe charset and length so a
+// client cannot inject arbitrary content into logs or response headers.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rid := c.GetHeader(headerRequestID)
-		if rid == "" {
+		if !validRequestID.MatchString(rid) {
 			rid = uuid.NewString()
 		}
 
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/eren_dev/go_server/internal/modules/roles"
+	"github.com/eren_dev/go_server/internal/modules/users"
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
+)
+
+// StaffGuardMiddleware verifica que el JWT pertenece a un usuario del staff.
+// Es la contraparte de OwnerGuardMiddleware para las rutas mobile del staff.
+// Debe usarse después de JWTMiddleware.
+func StaffGuardMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userType := sharedAuth.GetUserType(c)
+		if userType != string(sharedAuth.UserTypeStaff) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "access restricted to staff",
+				"status":  http.StatusForbidden,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// StaffRoleConfig agrupa los repositorios de StaffRoleMiddleware
+type StaffRoleConfig struct {
+	UserRepo users.UserRepository
+	RoleRepo roles.RoleRepository
+}
+
+// StaffRoleMiddleware exige que el usuario tenga, en el tenant de la request,
+// un rol con alguno de los nombres dados (p. ej. "veterinarian"). Se usa donde
+// RBAC no basta porque el endpoint solo tiene sentido para ese rol.
+// Debe usarse después de JWTMiddleware y TenantMiddleware.
+func StaffRoleMiddleware(cfg StaffRoleConfig, roleNames ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deny := func() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "access denied",
+				"status":  http.StatusForbidden,
+			})
+		}
+
+		user, err := cfg.UserRepo.FindByID(c.Request.Context(), sharedAuth.GetUserID(c))
+		if err != nil || len(user.RoleIds) == 0 {
+			deny()
+			return
+		}
+		userRoles, err := cfg.RoleRepo.FindByIDs(c.Request.Context(), user.RoleIds)
+		if err != nil {
+			deny()
+			return
+		}
+
+		tenantID := GetTenantID(c)
+		for _, role := range userRoles {
+			if role.TenantId != tenantID {
+				continue
+			}
+			for _, name := range roleNames {
+				if role.Name == name {
+					c.Next()
+					return
+				}
+			}
+		}
+		deny()
+	}
+}
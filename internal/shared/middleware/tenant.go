@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/platform/logger"
 )
 
 const (
@@ -36,6 +38,7 @@ func TenantMiddleware() gin.HandlerFunc {
 		}
 
 		c.Set(tenantIDKey, oid)
+		c.Request = c.Request.WithContext(logger.WithTenantID(c.Request.Context(), oid.Hex()))
 		c.Next()
 	}
 }
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eren_dev/go_server/internal/modules/users"
+	"github.com/eren_dev/go_server/internal/platform/cache"
+	"github.com/eren_dev/go_server/internal/platform/logger"
+	sharedAuth "github.com/eren_dev/go_server/internal/shared/auth"
+)
+
+const defaultTenantAccessTTL = 30 * time.Second
+
+// TenantState is what TenantAccessMiddleware needs to know about a tenant.
+type TenantState struct {
+	Found     bool // false if it does not exist or was deleted
+	Active    bool // active or in its trial period
+	Suspended bool
+	OwnerID   string
+}
+
+// TenantStateReader resolves the state of a tenant. A missing tenant is
+// reported with Found=false, not with an error.
+type TenantStateReader interface {
+	TenantState(ctx context.Context, tenantID primitive.ObjectID) (TenantState, error)
+}
+
+// StaffLookup resolves a staff user by ID.
+type StaffLookup interface {
+	FindByID(ctx context.Context, id string) (*users.User, error)
+}
+
+// TenantAccessConfig groups the dependencies of TenantAccessMiddleware
+type TenantAccessConfig struct {
+	Tenants TenantStateReader
+	// Users checks that the staff user belongs to the tenant. Mobile routes
+	// leave it nil: OwnerTenantMiddleware checks the owner's association.
+	Users StaffLookup
+	// TTL of the cached lookups (30s if zero)
+	TTL time.Duration
+}
+
+type tenantAccessEntry struct {
+	status  int
+	message string
+	ownerID string // owner of the tenant, only on tenant entries
+}
+
+// TenantAccessMiddleware checks that the X-Tenant-ID tenant exists, is active
+// (or in trial) and, when Users is set, that the authenticated user belongs
+// to it. Without it a valid token with someone else's tenant would be
+// accepted. It must run after JWTMiddleware and TenantMiddleware.
+//
+// Lookups are cached for TTL so the database is not hit on every request.
+// Expired entries are swept as the cache grows, so it does not keep one entry
+// per user and tenant ever seen.
+func TenantAccessMiddleware(cfg TenantAccessConfig) gin.HandlerFunc {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultTenantAccessTTL
+	}
+	entries := cache.NewMemory[tenantAccessEntry](ttl)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tenantID := GetTenantID(c)
+
+		access := checkTenantStatus(ctx, cfg.Tenants, entries, tenantID)
+		status, message := access.status, access.message
+		if status == http.StatusOK && cfg.Users != nil {
+			status, message = checkStaffMembership(ctx, cfg.Users, entries, sharedAuth.GetUserID(c), tenantID, access.ownerID)
+		}
+		if status != http.StatusOK {
+			c.AbortWithStatusJSON(status, gin.H{
+				"success": false,
+				"error":   message,
+				"status":  status,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// checkTenantStatus rejects missing, deleted, inactive or suspended tenants.
+func checkTenantStatus(ctx context.Context, tenants TenantStateReader, entries *cache.Memory[tenantAccessEntry], tenantID primitive.ObjectID) tenantAccessEntry {
+	key := "tenant:" + tenantID.Hex()
+	if e, ok := entries.Get(key); ok {
+		return e
+	}
+
+	state, err := tenants.TenantState(ctx, tenantID)
+	if err != nil {
+		// Transient errors are not cached
+		logger.Default().Error(ctx, "tenant_access_lookup_failed", "tenant_id", tenantID.Hex(), "error", err)
+		return tenantAccessEntry{status: http.StatusServiceUnavailable, message: "tenant could not be verified"}
+	}
+
+	entry := tenantAccessEntry{status: http.StatusOK, ownerID: state.OwnerID}
+	switch {
+	case !state.Found:
+		entry = tenantAccessEntry{status: http.StatusNotFound, message: "tenant not found"}
+	case state.Suspended:
+		entry = tenantAccessEntry{status: http.StatusForbidden, message: "tenant is suspended"}
+	case !state.Active:
+		entry = tenantAccessEntry{status: http.StatusForbidden, message: "tenant is inactive"}
+	}
+	entries.Set(key, entry)
+	return entry
+}
+
+// checkStaffMembership accepts the user if they belong to the tenant, own it
+// or are a super admin.
+func checkStaffMembership(ctx context.Context, staff StaffLookup, entries *cache.Memory[tenantAccessEntry], userID string, tenantID primitive.ObjectID, ownerID string) (int, string) {
+	if userID != "" && userID == ownerID {
+		return http.StatusOK, ""
+	}
+
+	key := "member:" + userID + ":" + tenantID.Hex()
+	if e, ok := entries.Get(key); ok {
+		return e.status, e.message
+	}
+
+	denied := tenantAccessEntry{status: http.StatusForbidden, message: "user is not associated with this tenant"}
+	user, err := staff.FindByID(ctx, userID)
+	if err != nil {
+		return denied.status, denied.message
+	}
+
+	entry := denied
+	if user.IsSuperAdmin {
+		entry = tenantAccessEntry{status: http.StatusOK}
+	}
+	for _, id := range user.TenantIds {
+		if id == tenantID {
+			entry = tenantAccessEntry{status: http.StatusOK}
+			break
+		}
+	}
+	entries.Set(key, entry)
+	return entry.status, entry.message
+}
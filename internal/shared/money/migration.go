@@ -0,0 +1,10 @@
+package money
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// MinorUnitsExpr is the aggregation expression for FromMajor, for migrations
+// converting stored decimal amounts. $round rounds halves to even, so amounts
+// (never negative) are rounded with floor of x+0.5 to match the Go side.
+func MinorUnitsExpr(field string, factor int64) bson.M {
+	return bson.M{"$toLong": bson.M{"$floor": bson.M{"$add": bson.A{bson.M{"$multiply": bson.A{field, factor}}, 0.5}}}}
+}
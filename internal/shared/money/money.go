@@ -0,0 +1,113 @@
+// Package money handles amounts stored as integer minor units (cents for
+// COP or USD, whole units for JPY). Keeping amounts as integers makes sums
+// and quantity products exact; conversion to decimals only happens at the
+// API edge.
+package money
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for clinics that have not configured a currency
+const DefaultCurrency = "COP"
+
+// ISO 4217 currencies whose minor unit is not the cent
+var (
+	zeroDecimal  = map[string]bool{"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true, "KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "UYI": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true}
+	threeDecimal = map[string]bool{"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true, "OMR": true, "TND": true}
+)
+
+// Normalize upper-cases a currency code, falling back to DefaultCurrency
+func Normalize(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// Decimals returns the number of minor-unit digits of currency
+func Decimals(currency string) int {
+	currency = Normalize(currency)
+	switch {
+	case zeroDecimal[currency]:
+		return 0
+	case threeDecimal[currency]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Factor returns how many minor units make one unit of currency
+func Factor(currency string) int64 {
+	f := int64(1)
+	for range Decimals(currency) {
+		f *= 10
+	}
+	return f
+}
+
+// FromMajor converts a decimal amount such as 12.5 into minor units,
+// rounding half away from zero
+func FromMajor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * float64(Factor(currency))))
+}
+
+// ToMajor converts minor units back into a decimal amount
+func ToMajor(minor int64, currency string) float64 {
+	return float64(minor) / float64(Factor(currency))
+}
+
+// Format renders minor units with the currency code, comma thousands and a
+// dot before the decimals, e.g. "COP 12,500.50"
+func Format(minor int64, currency string) string {
+	currency = Normalize(currency)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	f := Factor(currency)
+	whole := strconv.FormatInt(minor/f, 10)
+
+	var b strings.Builder
+	b.WriteString(currency)
+	b.WriteString(" ")
+	b.WriteString(sign)
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if d := Decimals(currency); d > 0 {
+		frac := strconv.FormatInt(minor%f, 10)
+		b.WriteByte('.')
+		b.WriteString(strings.Repeat("0", d-len(frac)))
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// Amount is how money appears in responses and reports. Value is for
+// display and charts; clients doing arithmetic should use Minor.
+type Amount struct {
+	Value     float64 `json:"value" example:"12500.5"`
+	Minor     int64   `json:"minor" example:"1250050"`
+	Currency  string  `json:"currency" example:"COP"`
+	Formatted string  `json:"formatted" example:"COP 12,500.50"`
+}
+
+// New builds the response form of an amount in minor units
+func New(minor int64, currency string) Amount {
+	currency = Normalize(currency)
+	return Amount{
+		Value:     ToMajor(minor, currency),
+		Minor:     minor,
+		Currency:  currency,
+		Formatted: Format(minor, currency),
+	}
+}
@@ -0,0 +1,31 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMajor_RoundsToMinorUnits(t *testing.T) {
+	assert.Equal(t, int64(1250050), FromMajor(12500.5, "COP"))
+	// 0.1+0.2 is 0.30000000000000004 as a float
+	assert.Equal(t, int64(30), FromMajor(0.1+0.2, "USD"))
+	assert.Equal(t, int64(1999), FromMajor(19.99, "usd"))
+	assert.Equal(t, int64(500), FromMajor(500, "JPY"))
+	assert.Equal(t, int64(1235), FromMajor(1.2345, "KWD"))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "COP 12,500.50", Format(1250050, "COP"))
+	assert.Equal(t, "USD 0.05", Format(5, "USD"))
+	assert.Equal(t, "USD -1,000.00", Format(-100000, "USD"))
+	assert.Equal(t, "JPY 1,234,567", Format(1234567, "JPY"))
+	assert.Equal(t, "COP 999.00", Format(99900, ""))
+}
+
+func TestNew(t *testing.T) {
+	a := New(1999, "usd")
+	assert.Equal(t, 19.99, a.Value)
+	assert.Equal(t, "USD", a.Currency)
+	assert.Equal(t, "USD 19.99", a.Formatted)
+}
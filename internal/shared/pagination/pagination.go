@@ -1,14 +1,31 @@
 package pagination
 
 import (
+	"fmt"
+	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	sharedErrors "github.com/eren_dev/go_server/internal/shared/errors"
 )
 
 type Params struct {
 	Skip  int64
 	Limit int64
+	// Sort campos pedidos en ?sort=, en orden de prioridad
+	Sort []SortField
+	// Capped indica que el limit pedido superaba el máximo y se recortó
+	Capped bool
+}
+
+// SortField es un campo de ordenamiento; Desc cuando viene con prefijo "-"
+type SortField struct {
+	Field string
+	Desc  bool
 }
 
 // PaginationInfo información de paginación
@@ -22,31 +39,109 @@ type PaginationInfo struct {
 	Total int64 `json:"total" example:"100"`
 	// Total de páginas
 	TotalPages int64 `json:"total_pages" example:"10"`
+	// Presente cuando el limit pedido superaba el máximo; limit trae el aplicado
+	Capped bool `json:"capped,omitempty" example:"false"`
 }
 
+// Valores por defecto de limit cuando no se llama a Configure
 const (
 	DefaultLimit = 10
 	MaxLimit     = 100
 )
 
+var (
+	defaultLimit int64 = DefaultLimit
+	maxLimit     int64 = MaxLimit
+)
+
+// Configure fija el limit por defecto y el máximo que acepta FromContext. Se
+// llama una vez al arrancar, antes de atender peticiones; valores no
+// positivos dejan el que había, y el defecto nunca supera al máximo.
+func Configure(def, max int64) {
+	if max > 0 {
+		maxLimit = max
+	}
+	if def > 0 {
+		defaultLimit = def
+	}
+	if defaultLimit > maxLimit {
+		defaultLimit = maxLimit
+	}
+}
+
+// FromContext lee skip, limit y sort del query. Un limit mayor al máximo se
+// recorta (y se registra) en vez de rechazarse, para no romper clientes.
 func FromContext(c *gin.Context) Params {
 	skip := parseQueryInt64(c, "skip", 0)
-	limit := parseQueryInt64(c, "limit", DefaultLimit)
+	limit := parseQueryInt64(c, "limit", defaultLimit)
 
 	if skip < 0 {
 		skip = 0
 	}
 	if limit <= 0 {
-		limit = DefaultLimit
+		limit = defaultLimit
 	}
-	if limit > MaxLimit {
-		limit = MaxLimit
+
+	capped := false
+	if limit > maxLimit {
+		slog.Warn("pagination limit capped",
+			"path", c.FullPath(),
+			"requested", limit,
+			"max", maxLimit,
+		)
+		limit = maxLimit
+		capped = true
 	}
 
 	return Params{
-		Skip:  skip,
-		Limit: limit,
+		Skip:   skip,
+		Limit:  limit,
+		Sort:   parseSort(c.Query("sort")),
+		Capped: capped,
+	}
+}
+
+// SortOrder arma el orden de Mongo a partir de ?sort=, o devuelve def si no
+// se pidió ninguno. Solo acepta campos de allowed, para no ordenar por campos
+// sin índice.
+func (p Params) SortOrder(allowed []string, def bson.D) (bson.D, error) {
+	if len(p.Sort) == 0 {
+		return def, nil
+	}
+
+	sort := make(bson.D, 0, len(p.Sort))
+	seen := make(map[string]bool, len(p.Sort))
+	for _, f := range p.Sort {
+		if !slices.Contains(allowed, f.Field) {
+			return nil, sharedErrors.Validation("sort", fmt.Sprintf("cannot sort by %q, allowed fields: %s", f.Field, strings.Join(allowed, ", ")))
+		}
+		if seen[f.Field] {
+			return nil, sharedErrors.Validation("sort", fmt.Sprintf("field %q is repeated", f.Field))
+		}
+		seen[f.Field] = true
+
+		dir := 1
+		if f.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: f.Field, Value: dir})
+	}
+	return sort, nil
+}
+
+// parseSort interpreta "campo,-otro" como campo ascendente y otro descendente
+func parseSort(raw string) []SortField {
+	var fields []SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		part = strings.TrimPrefix(part, "-")
+		if part == "" {
+			continue
+		}
+		fields = append(fields, SortField{Field: part, Desc: desc})
 	}
+	return fields
 }
 
 func NewPaginationInfo(params Params, total int64) PaginationInfo {
@@ -60,6 +155,7 @@ func NewPaginationInfo(params Params, total int64) PaginationInfo {
 		Limit:      params.Limit,
 		Total:      total,
 		TotalPages: totalPages,
+		Capped:     params.Capped,
 	}
 }
 
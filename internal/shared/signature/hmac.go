@@ -0,0 +1,140 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrMissingSignature is returned when there is no signature to check
+	ErrMissingSignature = errors.New("missing webhook signature")
+	// ErrInvalidSignature is returned when the signature does not match the payload
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnsupportedAlgorithm is returned for an unknown hash algorithm
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+)
+
+// Algorithm is the hash an HMAC signature is computed with
+type Algorithm string
+
+const (
+	SHA256 Algorithm = "sha256"
+	SHA512 Algorithm = "sha512"
+	// SHA1 is only for providers that still sign with it
+	SHA1 Algorithm = "sha1"
+)
+
+// Encoding is how the signature bytes are written in the header
+type Encoding string
+
+const (
+	Hex    Encoding = "hex"
+	Base64 Encoding = "base64"
+)
+
+// HMAC describes how a sender signs request bodies. The zero values of
+// Algorithm and Encoding mean SHA256 and hex, the most common scheme.
+type HMAC struct {
+	Secret    []byte
+	Algorithm Algorithm
+	Encoding  Encoding
+	// Header carries the signature, e.g. "X-Signature"; only VerifyRequest uses it
+	Header string
+	// Prefix is stripped from the signature before decoding, e.g. "sha256="
+	Prefix string
+}
+
+// NewHMAC creates an HMAC-SHA256, hex encoded scheme for secret
+func NewHMAC(secret string) HMAC {
+	return HMAC{Secret: []byte(secret), Algorithm: SHA256, Encoding: Hex}
+}
+
+// Sign returns the signature of payload, encoded and prefixed as configured
+func (h HMAC) Sign(payload []byte) (string, error) {
+	sum, err := h.sum(payload)
+	if err != nil {
+		return "", err
+	}
+	return h.Prefix + h.encode(sum), nil
+}
+
+// Verify checks that signature is the HMAC of payload. The comparison is
+// constant-time, and errors never include the expected value.
+func (h HMAC) Verify(payload []byte, signature string) error {
+	signature = strings.TrimSpace(signature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	expected, err := h.sum(payload)
+	if err != nil {
+		return err
+	}
+	got, err := h.decode(strings.TrimPrefix(signature, h.Prefix))
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+
+	if !hmac.Equal(got, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyRequest verifies the signature found in the configured header
+func (h HMAC) VerifyRequest(header http.Header, payload []byte) error {
+	return h.Verify(payload, header.Get(h.Header))
+}
+
+// Equal compares two signatures or checksums in constant time, for schemes
+// that are not plain HMACs of the body (e.g. a checksum over selected fields)
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func (h HMAC) sum(payload []byte) ([]byte, error) {
+	newHash, err := h.hash()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(newHash, h.Secret)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
+
+func (h HMAC) hash() (func() hash.Hash, error) {
+	switch h.Algorithm {
+	case "", SHA256:
+		return sha256.New, nil
+	case SHA512:
+		return sha512.New, nil
+	case SHA1:
+		return sha1.New, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, h.Algorithm)
+	}
+}
+
+func (h HMAC) encode(sum []byte) string {
+	if h.Encoding == Base64 {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+func (h HMAC) decode(signature string) ([]byte, error) {
+	if h.Encoding == Base64 {
+		return base64.StdEncoding.DecodeString(signature)
+	}
+	return hex.DecodeString(signature)
+}
@@ -0,0 +1,86 @@
+package signature
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var payload = []byte(`{"event":"transaction.updated","data":{"id":"tx_1"}}`)
+
+func TestVerify_ValidSignature(t *testing.T) {
+	h := NewHMAC("s3cret")
+	sig, err := h.Sign(payload)
+	require.NoError(t, err)
+
+	assert.NoError(t, h.Verify(payload, sig))
+	// Hex digests are accepted in either case
+	assert.NoError(t, h.Verify(payload, strings.ToUpper(sig)))
+}
+
+func TestVerify_KnownVector(t *testing.T) {
+	// RFC 4231 test case 2
+	h := NewHMAC("Jefe")
+	assert.NoError(t, h.Verify([]byte("what do ya want for nothing?"), "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"))
+}
+
+func TestVerify_InvalidSignature(t *testing.T) {
+	h := NewHMAC("s3cret")
+
+	other, err := NewHMAC("another").Sign(payload)
+	require.NoError(t, err)
+	assert.ErrorIs(t, h.Verify(payload, other), ErrInvalidSignature)
+
+	assert.ErrorIs(t, h.Verify(payload, "not-hex"), ErrInvalidSignature)
+	assert.ErrorIs(t, h.Verify(payload, ""), ErrMissingSignature)
+}
+
+func TestVerify_TamperedPayload(t *testing.T) {
+	h := NewHMAC("s3cret")
+	sig, err := h.Sign(payload)
+	require.NoError(t, err)
+
+	tampered := []byte(strings.Replace(string(payload), "tx_1", "tx_2", 1))
+	err = h.Verify(tampered, sig)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	// The expected signature must not leak through the error
+	assert.NotContains(t, err.Error(), sig)
+}
+
+func TestVerify_AlgorithmEncodingAndPrefix(t *testing.T) {
+	h := HMAC{Secret: []byte("s3cret"), Algorithm: SHA512, Encoding: Base64, Prefix: "sha512="}
+	sig, err := h.Sign(payload)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sig, "sha512="))
+	assert.NoError(t, h.Verify(payload, sig))
+
+	// Same secret but a different algorithm does not verify
+	sha256Sig, err := HMAC{Secret: []byte("s3cret"), Encoding: Base64, Prefix: "sha512="}.Sign(payload)
+	require.NoError(t, err)
+	assert.ErrorIs(t, h.Verify(payload, sha256Sig), ErrInvalidSignature)
+
+	_, err = HMAC{Secret: []byte("s3cret"), Algorithm: "md5"}.Sign(payload)
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestVerifyRequest(t *testing.T) {
+	h := NewHMAC("s3cret")
+	h.Header = "X-Signature"
+	sig, err := h.Sign(payload)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	assert.ErrorIs(t, h.VerifyRequest(header, payload), ErrMissingSignature)
+
+	header.Set("X-Signature", sig)
+	assert.NoError(t, h.VerifyRequest(header, payload))
+}
+
+func TestEqual(t *testing.T) {
+	assert.True(t, Equal("abc123", "abc123"))
+	assert.False(t, Equal("abc123", "abc124"))
+	assert.False(t, Equal("abc123", "abc12"))
+}
@@ -1,8 +1,10 @@
 package validation
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/gin-gonic/gin/binding"
@@ -12,8 +14,10 @@ import (
 // FieldError error de campo
 // @name FieldError
 type FieldError struct {
-	// Nombre del campo con error
-	Field string `json:"field" example:"email"`
+	// Nombre del campo con error, con la ruta JSON para campos anidados
+	Field string `json:"field" example:"recurrence.frequency"`
+	// Regla que falló (tag de validación, o "type" si el valor no se pudo leer)
+	Rule string `json:"rule" example:"required"`
 	// Mensaje de error
 	Message string `json:"message" example:"must be a valid email"`
 }
@@ -21,7 +25,7 @@ type FieldError struct {
 // ValidationError errores de validación
 // @name ValidationError
 type ValidationError struct {
-	// Lista de errores por campo
+	// Lista de errores por campo, en el orden de los campos del DTO
 	Errors []FieldError `json:"errors"`
 }
 
@@ -33,42 +37,123 @@ func (v ValidationError) Error() string {
 	return strings.Join(msgs, ", ")
 }
 
+// Validate convierte los errores de binding en un ValidationError con todos
+// los campos inválidos a la vez. Los errores que no son de validación se
+// devuelven tal cual.
 func Validate(err error) error {
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
 		fields := make([]FieldError, len(ve))
 		for i, fe := range ve {
 			fields[i] = FieldError{
-				Field:   toSnakeCase(fe.Field()),
+				Field:   fieldPath(fe),
+				Rule:    fe.Tag(),
 				Message: msgForTag(fe),
 			}
 		}
 		return ValidationError{Errors: fields}
 	}
+
+	// Un valor con el tipo equivocado corta el decode antes de validar
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) && te.Field != "" {
+		return ValidationError{Errors: []FieldError{{
+			Field:   te.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be of type %s", jsonType(te.Type)),
+		}}}
+	}
 	return err
 }
 
-func msgForTag(fe validator.FieldError) string {
-	field := toSnakeCase(fe.Field())
+// fieldPath devuelve la ruta del campo sin el nombre del struct raíz
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
 
+func msgForTag(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
-		return fmt.Sprintf("%s is required", field)
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "required_if", "required_with", "required_without":
+		return fmt.Sprintf("%s is required in this case", fe.Field())
 	case "email":
 		return "must be a valid email"
+	case "url":
+		return "must be a valid URL"
+	case "e164":
+		return "must be a phone number in E.164 format"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.Join(strings.Fields(fe.Param()), ", "))
 	case "min":
-		return fmt.Sprintf("must be at least %s characters", fe.Param())
+		return boundMsg(fe, "at least")
 	case "max":
-		return fmt.Sprintf("must be at most %s characters", fe.Param())
+		return boundMsg(fe, "at most")
+	case "len":
+		return boundMsg(fe, "exactly")
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
 	case "gte":
 		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fe.Param())
 	case "lte":
 		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "numeric":
+		return "must be numeric"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "hexadecimal", "mongodb":
+		return "must be a valid ID"
+	case "datetime":
+		return fmt.Sprintf("must be a date in %s format", fe.Param())
+	case "eqfield":
+		return fmt.Sprintf("must match %s", fe.Param())
+	case "nefield":
+		return fmt.Sprintf("must differ from %s", fe.Param())
 	default:
 		return fmt.Sprintf("failed on %s validation", fe.Tag())
 	}
 }
 
+// boundMsg redacta min/max/len según el tipo: largo para textos, cantidad de
+// elementos para listas y valor para números
+func boundMsg(fe validator.FieldError, bound string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters", bound, fe.Param())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s items", bound, fe.Param())
+	default:
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	}
+}
+
+func jsonType(t reflect.Type) string {
+	if t == nil {
+		return "unknown"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
 func toSnakeCase(s string) string {
 	var result strings.Builder
 	for i, r := range s {
@@ -80,8 +165,23 @@ func toSnakeCase(s string) string {
 	return strings.ToLower(result.String())
 }
 
+// tagName nombra los campos como los ve el cliente: el tag json, el tag form
+// para query strings, o el nombre del campo en snake_case
+func tagName(fld reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(fld.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return toSnakeCase(fld.Name)
+}
+
 func init() {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		_ = v
+		v.RegisterTagNameFunc(tagName)
 	}
 }
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bookingDTO mirrors the binding rules of a typical appointment DTO
+type bookingDTO struct {
+	PatientID   string    `json:"patient_id" binding:"required"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Duration    int       `json:"duration" binding:"required,min=15,max=480"`
+	Type        string    `json:"type" binding:"required,oneof=consultation surgery"`
+	Reason      string    `json:"reason" binding:"max=10"`
+	Recurrence  struct {
+		Frequency string `json:"frequency" binding:"required"`
+	} `json:"recurrence"`
+}
+
+func bind(t *testing.T, body string) error {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var dto bookingDTO
+	return Validate(c.ShouldBindJSON(&dto))
+}
+
+func TestValidate_ReportsEveryInvalidField(t *testing.T) {
+	err := bind(t, `{"scheduled_at":"2030-01-15T10:00:00Z","duration":5,"type":"grooming","reason":"far too long a reason"}`)
+
+	var ve ValidationError
+	require.True(t, errors.As(err, &ve))
+	assert.Equal(t, []FieldError{
+		{Field: "patient_id", Rule: "required", Message: "patient_id is required"},
+		{Field: "duration", Rule: "min", Message: "must be at least 15"},
+		{Field: "type", Rule: "oneof", Message: "must be one of: consultation, surgery"},
+		{Field: "reason", Rule: "max", Message: "must be at most 10 characters"},
+		{Field: "recurrence.frequency", Rule: "required", Message: "frequency is required"},
+	}, ve.Errors)
+}
+
+func TestValidate_WrongJSONType(t *testing.T) {
+	err := bind(t, `{"patient_id":"abc","duration":"thirty"}`)
+
+	var ve ValidationError
+	require.True(t, errors.As(err, &ve))
+	assert.Equal(t, []FieldError{
+		{Field: "duration", Rule: "type", Message: "must be of type integer"},
+	}, ve.Errors)
+}
+
+func TestValidate_PassesThroughOtherErrors(t *testing.T) {
+	err := bind(t, `{not json`)
+
+	var ve ValidationError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &ve))
+}